@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -14,18 +15,40 @@ import (
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/route"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/banking"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/branch"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/card"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/certificate"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/chat"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/collection"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/fiscal"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loss"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loyalty"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/onboarding"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/payable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/paymentmethod"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pix"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/platform"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pricetable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/promotion"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/salesman"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/supplier"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/terminal"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/usage"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
 	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 	pkgbranch "github.com/hugohenrick/erp-supermercado/pkg/branch"
+	"github.com/hugohenrick/erp-supermercado/pkg/cep"
+	"github.com/hugohenrick/erp-supermercado/pkg/ibge"
 	"github.com/hugohenrick/erp-supermercado/pkg/logger"
 	"github.com/hugohenrick/erp-supermercado/pkg/mcp"
 	"github.com/hugohenrick/erp-supermercado/pkg/mcp/intent/adapter"
+	"github.com/hugohenrick/erp-supermercado/pkg/plan"
 	pkgtenant "github.com/hugohenrick/erp-supermercado/pkg/tenant"
 	"github.com/jackc/pgx/v5/pgxpool"
 	swaggerFiles "github.com/swaggo/files"
@@ -37,19 +60,38 @@ import (
 
 // App representa a aplicação
 type App struct {
-	Router           *gin.Engine
-	DB               *pgxpool.Pool
-	TenantRepo       tenant.Repository
-	BranchRepo       branch.Repository
-	UserRepo         user.Repository
-	CustomerRepo     customer.Repository
-	CertificateRepo  certificate.Repository
-	FiscalConfigRepo fiscal.Repository
-	ChatRepo         chat.Repository
-	TenantValidator  pkgtenant.TenantValidator
-	Logger           logger.Logger
-	MCPClient        *mcp.MCPClient
-	Server           *http.Server
+	Router            *gin.Engine
+	DB                *pgxpool.Pool
+	TenantRepo        tenant.Repository
+	BranchRepo        branch.Repository
+	UserRepo          user.Repository
+	CustomerRepo      customer.Repository
+	CertificateRepo   certificate.Repository
+	FiscalConfigRepo  fiscal.Repository
+	ChatRepo          chat.Repository
+	LossRepo          loss.Repository
+	SupplierRepo      supplier.Repository
+	PayableRepo       payable.Repository
+	ReceivableRepo    receivable.Repository
+	CollectionRepo    collection.Repository
+	PixRepo           pix.Repository
+	BankingRepo       banking.Repository
+	CardRepo          card.Repository
+	LoyaltyRepo       loyalty.Repository
+	PromotionRepo     promotion.Repository
+	PriceTableRepo    pricetable.Repository
+	PaymentMethodRepo paymentmethod.Repository
+	SalesmanRepo      salesman.Repository
+	TerminalRepo      terminal.Repository
+	UsageRepo         usage.Repository
+	OnboardingRepo    onboarding.Repository
+	PlatformRepo      platform.Repository
+	RoleRepo          rbac.Repository
+	TenantValidator   pkgtenant.TenantValidator
+	CEPProvider       cep.Provider
+	Logger            logger.Logger
+	MCPClient         *mcp.MCPClient
+	Server            *http.Server
 }
 
 // NewApp cria uma nova instância da aplicação
@@ -72,12 +114,46 @@ func NewApp() *App {
 	certificateRepo := repository.NewCertificateRepository(pool)
 	fiscalConfigRepo := repository.NewFiscalRepository(pool)
 	chatRepo := repository.NewChatRepository(pool)
+	lossRepo := repository.NewLossRepository(pool)
+	supplierRepo := repository.NewSupplierRepository(pool)
+	payableRepo := repository.NewPayableRepository(pool)
+	receivableRepo := repository.NewReceivableRepository(pool)
+	collectionRepo := repository.NewCollectionRepository(pool)
+	pixRepo := repository.NewPixRepository(pool)
+	bankingRepo := repository.NewBankingRepository(pool)
+	cardRepo := repository.NewCardRepository(pool)
+	loyaltyRepo := repository.NewLoyaltyRepository(pool)
+	promotionRepo := repository.NewPromotionRepository(pool)
+	priceTableRepo := repository.NewPriceTableRepository(pool)
+	paymentMethodRepo := repository.NewPaymentMethodRepository(pool)
+	salesmanRepo := repository.NewSalesmanRepository(pool)
+	terminalRepo := repository.NewTerminalRepository(pool)
+	usageRepo := repository.NewUsageRepository(pool)
+	onboardingRepo := repository.NewOnboardingRepository(pool)
+	platformRepo := repository.NewPlatformRepository(pool)
+	roleRepo := repository.NewRoleRepository(pool)
+	// Inicializar consulta de CEP; CEP_PROVIDER=fixture usa endereços locais em vez do ViaCEP
+	var cepProvider cep.Provider = cep.NewViaCEPProvider(os.Getenv("CEP_API_URL"), nil)
+	if os.Getenv("CEP_PROVIDER") == "fixture" {
+		cepProvider = cep.NewFixtureProvider(cep.FixtureAddresses...)
+	}
+
+	// A tabela de municípios embarcada pode ser substituída, por exemplo por uma DTB mais recente
+	if file := os.Getenv("IBGE_MUNICIPALITIES_FILE"); file != "" {
+		if err := ibge.LoadFile(file); err != nil {
+			log.Fatalf("Erro ao carregar tabela de municípios do IBGE: %v", err)
+		}
+	}
+	if !ibge.Complete() {
+		log.Printf("Aviso: a tabela de municípios do IBGE em uso é parcial; gere a tabela completa com make ibge-municipios")
+	}
+
 	// Initialize controllers
 	// Inicializar validador de tenant
 	tenantValidator := repository.NewTenantValidator(tenantRepo)
 	// Initialize router
 	// Inicializar MCP client
-	mcpClient, err := mcp.NewMCPClient(logger, chatRepo)
+	mcpClient, err := mcp.NewMCPClient(logger, chatRepo, usageRepo)
 	if err != nil {
 		log.Fatalf("Erro ao inicializar MCP client: %v", err)
 	}
@@ -112,20 +188,55 @@ func NewApp() *App {
 		Handler: router,
 	}
 	return &App{
-		Router:           router,
-		DB:               pool,
-		TenantRepo:       tenantRepo,
-		BranchRepo:       branchRepo,
-		UserRepo:         userRepo,
-		CustomerRepo:     customerRepo,
-		CertificateRepo:  certificateRepo,
-		FiscalConfigRepo: fiscalConfigRepo,
-		ChatRepo:         chatRepo,
-		TenantValidator:  tenantValidator,
-		Logger:           logger,
-		MCPClient:        mcpClient,
-		Server:           server,
+		Router:            router,
+		DB:                pool,
+		TenantRepo:        tenantRepo,
+		BranchRepo:        branchRepo,
+		UserRepo:          userRepo,
+		CustomerRepo:      customerRepo,
+		CertificateRepo:   certificateRepo,
+		FiscalConfigRepo:  fiscalConfigRepo,
+		ChatRepo:          chatRepo,
+		LossRepo:          lossRepo,
+		SupplierRepo:      supplierRepo,
+		PayableRepo:       payableRepo,
+		ReceivableRepo:    receivableRepo,
+		CollectionRepo:    collectionRepo,
+		PixRepo:           pixRepo,
+		BankingRepo:       bankingRepo,
+		CardRepo:          cardRepo,
+		LoyaltyRepo:       loyaltyRepo,
+		PromotionRepo:     promotionRepo,
+		PriceTableRepo:    priceTableRepo,
+		PaymentMethodRepo: paymentMethodRepo,
+		SalesmanRepo:      salesmanRepo,
+		TerminalRepo:      terminalRepo,
+		UsageRepo:         usageRepo,
+		OnboardingRepo:    onboardingRepo,
+		PlatformRepo:      platformRepo,
+		RoleRepo:          roleRepo,
+		TenantValidator:   tenantValidator,
+		CEPProvider:       cepProvider,
+		Logger:            logger,
+		MCPClient:         mcpClient,
+		Server:            server,
+	}
+}
+
+// tenantGracePeriod lê de TENANT_PURGE_GRACE_DAYS o prazo entre a exclusão de um tenant e o
+// expurgo do schema. Sem valor válido, usa tenant.DefaultGracePeriod
+func tenantGracePeriod() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TENANT_PURGE_GRACE_DAYS"))
+	if err != nil || days < 1 {
+		return tenant.DefaultGracePeriod
 	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// pixSimulationEnabled indica se o pagamento simulado do PSP de testes fica disponível: exige
+// PIX_SIMULATION_ENABLED=true e nunca vale com APP_ENV=production
+func pixSimulationEnabled() bool {
+	return os.Getenv("PIX_SIMULATION_ENABLED") == "true" && os.Getenv("APP_ENV") != "production"
 }
 
 // SetupRoutes configura as rotas da API
@@ -142,28 +253,85 @@ func (a *App) SetupRoutes() {
 	// Middleware para capturar o branch_id do cabeçalho
 	apiV1.Use(pkgbranch.BranchMiddleware())
 
+	// Middleware do plano: carrega a assinatura do tenant e bloqueia escritas com o plano expirado
+	apiV1.Use(plan.Middleware(repository.NewSubscriptionProvider(a.TenantRepo)))
+
+	// Perfis de acesso: disponibiliza as permissões atribuídas para auth.RequirePermission
+	apiV1.Use(auth.PermissionMiddleware(a.RoleRepo))
+
+	// Filiais do usuário: o cabeçalho branch-id é validado na autenticação contra os vínculos
+	apiV1.Use(auth.BranchAccessMiddleware(a.UserRepo))
+
 	// Criar instâncias dos controladores
-	tenantController := controller.NewTenantController(a.TenantRepo, a.DB)
-	branchController := controller.NewBranchController(a.BranchRepo)
+	tenantController := controller.NewTenantController(a.TenantRepo, a.DB, tenantGracePeriod())
+	branchController := controller.NewBranchController(a.BranchRepo, a.CEPProvider)
 	authController := controller.NewAuthController(a.UserRepo)
 	userController := controller.NewUserController(a.UserRepo)
-	customerController := controller.NewCustomerController(a.CustomerRepo, a.Logger)
+	customerController := controller.NewCustomerController(a.CustomerRepo, a.CEPProvider, a.Logger)
 	certificateController := controller.NewCertificateController(a.CertificateRepo, a.Logger)
 	fiscalController := controller.NewFiscalController(a.FiscalConfigRepo, a.Logger)
+	lossController := controller.NewLossController(a.LossRepo, a.Logger)
+	supplierController := controller.NewSupplierController(a.SupplierRepo, a.CEPProvider, a.Logger)
+	payableController := controller.NewPayableController(a.PayableRepo, a.SupplierRepo, a.Logger)
+	receivableController := controller.NewReceivableController(a.ReceivableRepo, a.CustomerRepo, a.Logger)
+	collectionController := controller.NewCollectionController(a.CollectionRepo, a.ReceivableRepo, a.CustomerRepo, a.Logger)
+	pixController := controller.NewPixController(a.PixRepo, a.ReceivableRepo, a.CustomerRepo, a.TenantValidator, pixSimulationEnabled(), a.Logger)
+	bankingController := controller.NewBankingController(a.BankingRepo, a.ReceivableRepo, a.PayableRepo, a.Logger)
+	cardController := controller.NewCardController(a.CardRepo, a.Logger)
+	loyaltyController := controller.NewLoyaltyController(a.LoyaltyRepo, a.CustomerRepo, a.Logger)
+	promotionController := controller.NewPromotionController(a.PromotionRepo, a.CustomerRepo, a.PriceTableRepo, a.Logger)
+	priceTableController := controller.NewPriceTableController(a.PriceTableRepo, a.CustomerRepo, a.Logger)
+	paymentMethodController := controller.NewPaymentMethodController(a.PaymentMethodRepo, a.Logger)
+	salesmanController := controller.NewSalesmanController(a.SalesmanRepo, a.CustomerRepo, a.Logger)
+	addressController := controller.NewAddressController(a.CEPProvider, a.Logger)
+	terminalController := controller.NewTerminalController(a.TerminalRepo, a.Logger)
+	usageController := controller.NewUsageController(a.UsageRepo, a.Logger)
+	onboardingController := controller.NewOnboardingController(a.OnboardingRepo, a.CEPProvider, a.Logger)
+	platformController := controller.NewPlatformController(a.PlatformRepo, a.Logger)
+	roleController := controller.NewRoleController(a.RoleRepo, a.Logger)
+
+	// Rotas dos operadores da plataforma: cadastro e gestão dos tenants, planos e consumo
+	platformRoutes := apiV1.Group("", auth.PlatformAuthMiddleware(a.PlatformRepo))
+
+	// Grupos das rotas que dependem de um módulo do plano
+	fiscalRoutes := apiV1.Group("", plan.RequireModule(tenant.ModuleFiscal))
+	financeRoutes := apiV1.Group("", plan.RequireModule(tenant.ModuleFinance))
+	marketingRoutes := apiV1.Group("", plan.RequireModule(tenant.ModuleMarketing))
+	salesmenRoutes := apiV1.Group("", plan.RequireModule(tenant.ModuleSalesmen))
+	assistantRoutes := apiV1.Group("", plan.RequireModule(tenant.ModuleAssistant))
 
 	// Configurar rotas para cada módulo
-	route.SetupTenantRoutes(apiV1, tenantController)
+	route.SetupPlatformRoutes(apiV1, platformRoutes, platformController)
+	route.SetupTenantRoutes(platformRoutes, tenantController)
+	route.SetupUsageRoutes(platformRoutes, usageController)
+	route.SetupOnboardingRoutes(platformRoutes, onboardingController)
 	route.SetupBranchRoutes(apiV1, branchController)
 	route.SetupAuthRoutes(apiV1, authController)
 	route.SetupUserRoutes(apiV1, userController)
+	route.SetupRoleRoutes(apiV1, roleController)
 	route.RegisterCustomerRoutes(apiV1, customerController)
 	route.SetupSetupRoutes(apiV1, userController)
-	route.SetupCertificateRoutes(apiV1, certificateController)
-	route.SetupFiscalRoutes(apiV1, fiscalController)
+	route.SetupCertificateRoutes(fiscalRoutes, certificateController)
+	route.SetupFiscalRoutes(fiscalRoutes, fiscalController)
+	route.SetupLossRoutes(apiV1, lossController)
+	route.SetupSupplierRoutes(apiV1, supplierController)
+	route.SetupPayableRoutes(financeRoutes, payableController)
+	route.SetupReceivableRoutes(financeRoutes, receivableController)
+	route.SetupCollectionRoutes(financeRoutes, collectionController)
+	route.SetupPixRoutes(financeRoutes, pixController)
+	route.SetupBankingRoutes(financeRoutes, bankingController)
+	route.SetupCardRoutes(financeRoutes, cardController)
+	route.SetupLoyaltyRoutes(marketingRoutes, loyaltyController)
+	route.SetupPromotionRoutes(marketingRoutes, promotionController)
+	route.SetupPriceTableRoutes(marketingRoutes, priceTableController)
+	route.SetupPaymentMethodRoutes(apiV1, paymentMethodController)
+	route.SetupSalesmanRoutes(salesmenRoutes, salesmanController)
+	route.SetupAddressRoutes(apiV1, addressController)
+	route.SetupTerminalRoutes(apiV1, terminalController)
 
 	// Create a customer repository adapter for the MCP
 	customerRepoAdapter := adapter.NewCustomerRepositoryAdapter(a.CustomerRepo, a.Logger)
-	route.ConfigureMCPRoutes(apiV1, a.MCPClient, customerRepoAdapter, a.Logger)
+	route.ConfigureMCPRoutes(assistantRoutes, a.MCPClient, customerRepoAdapter, a.Logger)
 }
 
 // Start inicia o servidor HTTP
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const usage = `Uso: go run ./cmd/migration [comando] [N] [opções]
+
+Comandos:
+  (nenhum)   executa apenas as migrações internas do schema public
+  status     mostra a versão e as migrações pendentes de cada schema
+  up [N]     aplica as N próximas migrações pendentes (todas quando omitido): primeiro no
+             schema public, depois em cada tenant
+  down N     reverte as N últimas migrações (exige -scope=public ou -scope=tenants)
+  rebuild-logins
+             reconstrói o índice global de logins (public.user_logins) a partir
+             dos usuários de todos os tenants
+  purge-tenants [-dry-run] [-tenant=ID] [-archive-dir=DIR]
+             arquiva e expurga os tenants excluídos cujo prazo de carência
+             terminou: exporta o schema para um ZIP em -archive-dir (guardado
+             pelo prazo de retenção fiscal), remove o schema e o cadastro e
+             registra os eventos em public.tenant_audit_log
+  collect-usage
+             mede o consumo do mês corrente de todos os tenants ativos em
+             public.tenant_usage (agendar diariamente)
+  block-overdue
+             bloqueia, em todos os tenants ativos, os clientes com títulos a
+             receber vencidos além da tolerância configurada em cada tenant
+             (PUT /receivables/settings; agendar diariamente)
+  create-operator -name=NOME -email=EMAIL
+             cadastra um operador da plataforma, que administra os tenants,
+             os planos e o consumo; a senha é lida de PLATFORM_OPERATOR_PASSWORD
+  help       mostra esta ajuda
+
+Opções:
+`
+
+// options são as opções dos comandos status, up e down
+type options struct {
+	scope    string // all, public ou tenants
+	tenantID string // Restringe a um tenant
+	dir      string // Diretório das migrações do public; as dos tenants ficam em dir/tenant
+	dryRun   bool
+	parallel int // Tenants migrados ao mesmo tempo
+	steps    int // Zero aplica todas as pendentes
+}
+
+// tenantResult é o resultado do comando em um tenant
+type tenantResult struct {
+	id         string
+	name       string
+	schema     string
+	migrations []database.Migration
+	status     *database.SchemaStatus
+	err        error
+}
+
+// parseOptions interpreta as opções e o número de migrações, que pode vir antes ou depois delas
+func parseOptions(command string, args []string) (*options, error) {
+	opts := &options{}
+	fs := flag.NewFlagSet(command, flag.ContinueOnError)
+	fs.StringVar(&opts.scope, "scope", "all", "schemas afetados: all, public ou tenants")
+	fs.StringVar(&opts.tenantID, "tenant", "", "ID de um único tenant (implica -scope=tenants)")
+	fs.StringVar(&opts.dir, "dir", database.MigrationsDir(), "diretório das migrações")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "apenas lista as migrações que seriam executadas")
+	fs.IntVar(&opts.parallel, "parallel", 4, "quantidade de tenants migrados em paralelo")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		positional = append(positional, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+
+	if len(positional) > 1 || (command == "status" && len(positional) > 0) {
+		return nil, fmt.Errorf("argumentos inesperados: %s", strings.Join(positional, " "))
+	}
+	if len(positional) == 1 {
+		steps, err := strconv.Atoi(positional[0])
+		if err != nil || steps < 1 {
+			return nil, fmt.Errorf("quantidade de migrações inválida: %s", positional[0])
+		}
+		opts.steps = steps
+	}
+
+	if opts.tenantID != "" {
+		opts.scope = "tenants"
+	}
+	switch opts.scope {
+	case "all", "public", "tenants":
+	default:
+		return nil, fmt.Errorf("escopo inválido: %s", opts.scope)
+	}
+	if command == "down" && (opts.steps == 0 || opts.scope == "all") {
+		return nil, errors.New("down exige a quantidade de migrações e -scope=public ou -scope=tenants")
+	}
+	if opts.parallel < 1 {
+		opts.parallel = 1
+	}
+	return opts, nil
+}
+
+// runCommand executa o comando no schema public e nos tenants, conforme o escopo. Retorna erro se
+// algum schema falhar, depois de processar todos os tenants
+func runCommand(ctx context.Context, db *pgxpool.Pool, command string, opts *options) error {
+	if opts.scope != "tenants" {
+		if err := runPublic(ctx, db, command, opts); err != nil {
+			return fmt.Errorf("schema public: %w", err)
+		}
+	}
+	if opts.scope == "public" {
+		return nil
+	}
+
+	migrator, err := database.NewMigrator(db, filepath.Join(opts.dir, "tenant"))
+	if err != nil {
+		return err
+	}
+	results, err := runTenants(ctx, db, migrator, command, opts)
+	if err != nil {
+		return err
+	}
+	return report(command, opts, results)
+}
+
+// runPublic executa o comando no schema public: as migrações internas e depois os arquivos do diretório
+func runPublic(ctx context.Context, db *pgxpool.Pool, command string, opts *options) error {
+	migrator, err := database.NewMigrator(db, opts.dir)
+	if err != nil {
+		return err
+	}
+
+	switch command {
+	case "status":
+		status, err := migrator.Status(ctx, "public")
+		if err != nil {
+			return err
+		}
+		printStatus("public", status)
+		return nil
+	case "up":
+		if !opts.dryRun {
+			if err := runMigrations(db); err != nil {
+				return err
+			}
+		}
+		migrations, err := migrator.Up(ctx, "public", opts.steps, opts.dryRun)
+		printMigrations("public", opts.dryRun, migrations)
+		return err
+	default:
+		migrations, err := migrator.Down(ctx, "public", opts.steps, opts.dryRun)
+		printMigrations("public", opts.dryRun, migrations)
+		return err
+	}
+}
+
+// runTenants executa o comando em cada tenant, com no máximo opts.parallel ao mesmo tempo
+func runTenants(ctx context.Context, db *pgxpool.Pool, migrator *database.Migrator, command string, opts *options) ([]*tenantResult, error) {
+	query := "SELECT id, name, schema FROM public.tenants"
+	args := []any{}
+	if opts.tenantID != "" {
+		query += " WHERE id = $1"
+		args = append(args, opts.tenantID)
+	}
+	rows, err := db.Query(ctx, query+" ORDER BY name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var results []*tenantResult
+	for rows.Next() {
+		r := &tenantResult{}
+		if err := rows.Scan(&r.id, &r.name, &r.schema); err != nil {
+			return nil, fmt.Errorf("erro ao ler tenant: %w", err)
+		}
+		results = append(results, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar tenants: %w", err)
+	}
+	if opts.tenantID != "" && len(results) == 0 {
+		return nil, fmt.Errorf("tenant não encontrado: %s", opts.tenantID)
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, opts.parallel)
+	for _, r := range results {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(r *tenantResult) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			switch command {
+			case "status":
+				r.status, r.err = migrator.Status(ctx, r.schema)
+			case "up":
+				r.migrations, r.err = migrator.Up(ctx, r.schema, opts.steps, opts.dryRun)
+			default:
+				r.migrations, r.err = migrator.Down(ctx, r.schema, opts.steps, opts.dryRun)
+			}
+		}(r)
+	}
+	wg.Wait()
+	return results, nil
+}
+
+// report imprime o resultado de cada tenant e o resumo, listando os que falharam
+func report(command string, opts *options, results []*tenantResult) error {
+	var failed []*tenantResult
+	for _, r := range results {
+		label := fmt.Sprintf("%s (%s)", r.schema, r.name)
+		switch {
+		case r.err != nil:
+			failed = append(failed, r)
+			if len(r.migrations) > 0 {
+				printMigrations(label, opts.dryRun, r.migrations)
+			}
+			log.Printf("%s: FALHA: %v", label, r.err)
+		case command == "status":
+			printStatus(label, r.status)
+		default:
+			printMigrations(label, opts.dryRun, r.migrations)
+		}
+	}
+
+	log.Printf("Tenants: %d, sucesso: %d, falha: %d", len(results), len(results)-len(failed), len(failed))
+	if len(failed) == 0 {
+		return nil
+	}
+	for _, r := range failed {
+		log.Printf("  %s %s (%s): %v", r.id, r.name, r.schema, r.err)
+	}
+	return fmt.Errorf("%d tenant(s) com falha", len(failed))
+}
+
+// rebuildLogins preenche o índice global de logins com os usuários já existentes nos tenants
+func rebuildLogins(ctx context.Context, db *pgxpool.Pool) error {
+	count, err := repository.NewUserRepository(db).RebuildLogins(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Índice de logins reconstruído: %d login(s)", count)
+	return nil
+}
+
+// printStatus imprime a versão e as migrações pendentes do schema
+func printStatus(label string, status *database.SchemaStatus) {
+	log.Printf("%s: versão %d, %d aplicada(s), %d pendente(s)", label, status.Version, len(status.Applied), len(status.Pending))
+	for _, m := range status.Pending {
+		log.Printf("  pendente: %s", m.Name)
+	}
+}
+
+// printMigrations imprime as migrações executadas, ou que seriam executadas no dry-run
+func printMigrations(label string, dryRun bool, migrations []database.Migration) {
+	verb := "executada"
+	if dryRun {
+		verb = "seria executada"
+	}
+	if len(migrations) == 0 {
+		log.Printf("%s: nada a executar", label)
+	}
+	for _, m := range migrations {
+		log.Printf("%s: %s %s", label, m.Name, verb)
+	}
+}
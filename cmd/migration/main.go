@@ -2,8 +2,14 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
@@ -18,18 +24,121 @@ func main() {
 		log.Printf("Aviso: Arquivo .env não encontrado: %v", err)
 	}
 
+	// Sem comando, mantém o comportamento original: apenas as migrações internas do public
+	command, args := "", os.Args[1:]
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		command, args = args[0], args[1:]
+	}
+
+	var opts *options
+	var purgeOpts *purgeOptions
+	var operatorOpts *operatorOptions
+	var overdueOpts *overdueOptions
+	switch command {
+	case "":
+	case "help":
+		parseOptions(command, []string{"-h"})
+		return
+	case "rebuild-logins", "collect-usage":
+	case "purge-tenants":
+		var err error
+		if purgeOpts, err = parsePurgeOptions(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			log.Fatalf("Erro: %v", err)
+		}
+	case "block-overdue":
+		var err error
+		if overdueOpts, err = parseOverdueOptions(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			log.Fatalf("Erro: %v", err)
+		}
+	case "create-operator":
+		var err error
+		if operatorOpts, err = parseOperatorOptions(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			log.Fatalf("Erro: %v", err)
+		}
+	case "status", "up", "down":
+		var err error
+		if opts, err = parseOptions(command, args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return
+			}
+			log.Fatalf("Erro: %v", err)
+		}
+	default:
+		log.Fatalf("Comando desconhecido: %s\n\n%s", command, usage)
+	}
+
 	// Criar conexão com o banco
 	db, err := database.NewPostgresDB()
 	if err != nil {
 		log.Fatalf("Erro ao conectar com o banco de dados: %v", err)
 	}
+	defer db.Close()
 
-	// Executar as migrações
-	if err := runMigrations(db); err != nil {
-		log.Fatalf("Erro ao executar migrações: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	if command == "rebuild-logins" {
+		if err := rebuildLogins(ctx, db); err != nil {
+			db.Close()
+			log.Fatalf("Erro ao reconstruir índice de logins: %v", err)
+		}
+		return
+	}
+
+	if command == "collect-usage" {
+		if err := collectUsage(ctx, db); err != nil {
+			db.Close()
+			log.Fatalf("Erro ao medir consumo dos tenants: %v", err)
+		}
+		return
+	}
+
+	if overdueOpts != nil {
+		if err := blockOverdue(ctx, db, overdueOpts); err != nil {
+			db.Close()
+			log.Fatalf("Erro ao bloquear clientes inadimplentes: %v", err)
+		}
+		return
+	}
+
+	if operatorOpts != nil {
+		if err := createOperator(ctx, db, operatorOpts); err != nil {
+			db.Close()
+			log.Fatalf("Erro ao criar operador da plataforma: %v", err)
+		}
+		return
 	}
 
-	log.Println("Migrações executadas com sucesso!")
+	if purgeOpts != nil {
+		if err := purgeTenants(ctx, db, purgeOpts); err != nil {
+			db.Close()
+			log.Fatalf("Erro ao expurgar tenants: %v", err)
+		}
+		return
+	}
+
+	if opts == nil {
+		// Executar as migrações
+		if err := runMigrations(db); err != nil {
+			log.Fatalf("Erro ao executar migrações: %v", err)
+		}
+		log.Println("Migrações executadas com sucesso!")
+		return
+	}
+
+	if err := runCommand(ctx, db, command, opts); err != nil {
+		db.Close()
+		log.Fatalf("Erro ao executar migrações: %v", err)
+	}
 }
 
 func runMigrations(db *pgxpool.Pool) error {
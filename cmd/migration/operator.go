@@ -0,0 +1,62 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/platform"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// operatorOptions são as opções do comando create-operator
+type operatorOptions struct {
+	name     string
+	email    string
+	password string // Lida de PLATFORM_OPERATOR_PASSWORD, para não ficar no histórico do shell
+}
+
+// parseOperatorOptions interpreta as opções do comando create-operator
+func parseOperatorOptions(args []string) (*operatorOptions, error) {
+	opts := &operatorOptions{}
+	fs := flag.NewFlagSet("create-operator", flag.ContinueOnError)
+	fs.StringVar(&opts.name, "name", "", "nome do operador")
+	fs.StringVar(&opts.email, "email", "", "email do operador")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("argumentos inesperados: %v", fs.Args())
+	}
+	if opts.name == "" || opts.email == "" {
+		return nil, errors.New("create-operator exige -name e -email")
+	}
+	opts.password = os.Getenv("PLATFORM_OPERATOR_PASSWORD")
+	if opts.password == "" {
+		return nil, errors.New("informe a senha do operador em PLATFORM_OPERATOR_PASSWORD")
+	}
+	return opts, nil
+}
+
+// createOperator cadastra um operador da plataforma. É a forma de criar o primeiro operador; os
+// demais podem ser cadastrados por ele na API
+func createOperator(ctx context.Context, db *pgxpool.Pool, opts *operatorOptions) error {
+	o, err := platform.NewOperator(opts.name, opts.email, opts.password)
+	if err != nil {
+		return err
+	}
+	if err := repository.NewPlatformRepository(db).Create(ctx, o); err != nil {
+		return err
+	}
+	log.Printf("Operador da plataforma criado: %s (%s)", o.Email, o.ID)
+	return nil
+}
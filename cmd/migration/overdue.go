@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// overdueOptions são as opções do comando block-overdue. A tolerância de atraso não é uma opção: cada
+// tenant usa a sua, gravada em receivable_settings
+type overdueOptions struct{}
+
+// parseOverdueOptions interpreta as opções do comando block-overdue
+func parseOverdueOptions(args []string) (*overdueOptions, error) {
+	opts := &overdueOptions{}
+	fs := flag.NewFlagSet("block-overdue", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("argumentos inesperados: %v", fs.Args())
+	}
+	return opts, nil
+}
+
+// blockOverdue bloqueia os clientes com títulos vencidos além da tolerância configurada em cada tenant
+// ativo. Executado diariamente, aplica o bloqueio automático mesmo sem novas vendas a prazo
+func blockOverdue(ctx context.Context, db *pgxpool.Pool, opts *overdueOptions) error {
+	blocked, err := repository.NewReceivableRepository(db).BlockOverdueAll(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Clientes inadimplentes bloqueados: %d", blocked)
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// purgeActor identifica o comando na trilha de auditoria
+const purgeActor = "purge-tenants"
+
+// purgeOptions são as opções do comando purge-tenants
+type purgeOptions struct {
+	archiveDir string // Onde ficam os arquivos guardados para a retenção fiscal
+	tenantID   string // Restringe a um tenant
+	dryRun     bool
+}
+
+// parsePurgeOptions interpreta as opções do comando purge-tenants
+func parsePurgeOptions(args []string) (*purgeOptions, error) {
+	opts := &purgeOptions{}
+	fs := flag.NewFlagSet("purge-tenants", flag.ContinueOnError)
+	fs.StringVar(&opts.archiveDir, "archive-dir", archiveDir(), "diretório dos arquivos guardados para a retenção fiscal")
+	fs.StringVar(&opts.tenantID, "tenant", "", "ID de um único tenant")
+	fs.BoolVar(&opts.dryRun, "dry-run", false, "apenas lista os tenants que seriam expurgados")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), usage)
+		fs.PrintDefaults()
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if fs.NArg() > 0 {
+		return nil, fmt.Errorf("argumentos inesperados: %v", fs.Args())
+	}
+	return opts, nil
+}
+
+// archiveDir retorna o diretório de arquivos de TENANT_ARCHIVE_DIR ou o padrão archive/tenants
+func archiveDir() string {
+	if dir := os.Getenv("TENANT_ARCHIVE_DIR"); dir != "" {
+		return dir
+	}
+	return filepath.Join("archive", "tenants")
+}
+
+// purgeTenants arquiva e expurga os tenants excluídos cujo prazo de carência terminou. Cada
+// tenant é exportado para o diretório de arquivos antes de o schema ser removido; se o arquivo
+// não puder ser gravado, o tenant não é expurgado. Retorna erro se algum tenant falhar, depois de
+// processar todos
+func purgeTenants(ctx context.Context, db *pgxpool.Pool, opts *purgeOptions) error {
+	repo := repository.NewTenantRepository(db)
+
+	tenants, err := repo.ListPurgeable(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if opts.tenantID != "" {
+		var selected []*tenant.Tenant
+		for _, t := range tenants {
+			if t.ID == opts.tenantID {
+				selected = append(selected, t)
+			}
+		}
+		if len(selected) == 0 {
+			return fmt.Errorf("tenant não está excluído ou ainda está no prazo de carência: %s", opts.tenantID)
+		}
+		tenants = selected
+	}
+
+	var failed, purged int
+	for _, t := range tenants {
+		label := fmt.Sprintf("%s (%s)", t.Schema, t.Name)
+
+		if opts.dryRun {
+			log.Printf("%s: seria expurgado (excluído em %s)", label, t.DeletedAt.Format(time.RFC3339))
+			continue
+		}
+
+		if err := purgeTenant(ctx, db, repo, t, opts.archiveDir); err != nil {
+			failed++
+			log.Printf("%s: FALHA: %v", label, err)
+			continue
+		}
+		purged++
+		log.Printf("%s: expurgado", label)
+	}
+
+	if opts.dryRun {
+		log.Printf("Tenants a expurgar: %d", len(tenants))
+		return nil
+	}
+	log.Printf("Tenants expurgados: %d, falha: %d", purged, failed)
+	if failed > 0 {
+		return fmt.Errorf("%d tenant(s) com falha", failed)
+	}
+	return nil
+}
+
+// purgeTenant guarda a exportação do tenant e depois remove o schema e o cadastro
+func purgeTenant(ctx context.Context, db *pgxpool.Pool, repo tenant.Repository, t *tenant.Tenant, dir string) error {
+	path, checksum, manifest, err := archiveTenant(ctx, db, t, dir)
+	if err != nil {
+		return err
+	}
+
+	retainUntil := time.Now().Add(tenant.FiscalRetention)
+	archived := tenant.NewAuditEntry(t, tenant.AuditArchived, purgeActor, map[string]any{
+		"path":         path,
+		"sha256":       checksum,
+		"tables":       len(manifest.Tables),
+		"xml_files":    manifest.XMLFiles,
+		"retain_until": retainUntil,
+	})
+	if err := repo.RecordAudit(ctx, archived); err != nil {
+		return err
+	}
+
+	audit := tenant.NewAuditEntry(t, tenant.AuditPurged, purgeActor, map[string]any{"archive": path})
+	return repo.Purge(ctx, t, audit)
+}
+
+// archiveTenant exporta o schema do tenant para um ZIP no diretório de arquivos e retorna o
+// caminho e o SHA-256 do arquivo. O ZIP é gravado com outro nome e renomeado só depois de
+// completo, de forma que um arquivo no diretório está sempre íntegro
+func archiveTenant(ctx context.Context, db *pgxpool.Pool, t *tenant.Tenant, dir string) (string, string, *database.ExportManifest, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", "", nil, fmt.Errorf("erro ao criar diretório de arquivos: %w", err)
+	}
+
+	name := fmt.Sprintf("%s_%s_%s.zip", t.Document, t.Schema, time.Now().Format("20060102150405"))
+	path := filepath.Join(dir, name)
+	tmp := path + ".partial"
+
+	file, err := os.OpenFile(tmp, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o640)
+	if err != nil {
+		return "", "", nil, fmt.Errorf("erro ao criar arquivo: %w", err)
+	}
+	defer os.Remove(tmp)
+
+	hash := sha256.New()
+	manifest, err := database.ExportSchema(ctx, db, t.Schema, io.MultiWriter(file, hash))
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return "", "", nil, fmt.Errorf("erro ao exportar tenant: %w", err)
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return "", "", nil, fmt.Errorf("erro ao gravar arquivo: %w", err)
+	}
+	return path, hex.EncodeToString(hash.Sum(nil)), manifest, nil
+}
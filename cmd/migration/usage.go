@@ -0,0 +1,19 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// collectUsage mede o consumo do mês corrente de todos os tenants ativos. Executado diariamente,
+// registra o maior número de usuários, filiais e terminais do mês mesmo sem consultas ao consumo
+func collectUsage(ctx context.Context, db *pgxpool.Pool) error {
+	if err := repository.NewUsageRepository(db).CollectAll(ctx); err != nil {
+		return err
+	}
+	log.Println("Consumo dos tenants medido com sucesso!")
+	return nil
+}
@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/pkg/cep"
+	"github.com/hugohenrick/erp-supermercado/pkg/ibge"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// errMunicipalityNotFound ocorre quando a cidade/UF ou o código não constam na tabela do IBGE
+var errMunicipalityNotFound = errors.New("município não encontrado na tabela do IBGE")
+
+// AddressController manipula as consultas de CEP e de códigos IBGE usadas nos cadastros
+type AddressController struct {
+	cepProvider cep.Provider
+	logger      logger.Logger
+}
+
+// NewAddressController cria uma nova instância de AddressController
+func NewAddressController(cepProvider cep.Provider, logger logger.Logger) *AddressController {
+	return &AddressController{
+		cepProvider: cepProvider,
+		logger:      logger,
+	}
+}
+
+// LookupZipCode busca o endereço de um CEP
+// @Summary Consultar CEP
+// @Description Retorna logradouro, bairro, cidade, UF e os códigos IBGE do município e da UF a partir do CEP
+// @Tags Endereços
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param zip_code path string true "CEP, com ou sem máscara"
+// @Success 200 {object} cep.Address
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.ErrorResponse
+// @Router /addresses/zip-codes/{zip_code} [get]
+func (c *AddressController) LookupZipCode(ctx *gin.Context) {
+	address, err := c.cepProvider.Lookup(ctx, ctx.Param("zip_code"))
+	if err != nil {
+		c.respondAddressError(ctx, "erro ao consultar CEP", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, address)
+}
+
+// ListStates lista as UFs com o código IBGE
+// @Summary Listar UFs
+// @Description Lista as unidades da federação com o código IBGE (cUF)
+// @Tags Endereços
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} ibge.State
+// @Router /addresses/states [get]
+func (c *AddressController) ListStates(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, ibge.States())
+}
+
+// FindMunicipality busca o código IBGE de um município
+// @Summary Consultar município
+// @Description Busca o município pelo código IBGE ou pela cidade e UF, ignorando acentos e caixa
+// @Tags Endereços
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param code query string false "Código IBGE do município"
+// @Param city query string false "Nome da cidade"
+// @Param state query string false "Sigla da UF"
+// @Success 200 {object} ibge.Municipality
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Router /addresses/municipalities [get]
+func (c *AddressController) FindMunicipality(ctx *gin.Context) {
+	var (
+		municipality ibge.Municipality
+		found        bool
+	)
+	switch code, city, state := ctx.Query("code"), ctx.Query("city"), ctx.Query("state"); {
+	case code != "":
+		municipality, found = ibge.MunicipalityByCode(code)
+	case city != "" && state != "":
+		municipality, found = ibge.FindMunicipality(city, state)
+	default:
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "parâmetros inválidos", "informe code ou city e state"))
+		return
+	}
+	if !found {
+		err := errMunicipalityNotFound
+		if !ibge.Complete() {
+			err = fmt.Errorf("%w: a tabela de municípios em uso é parcial, gere a tabela completa com make ibge-municipios", err)
+		}
+		c.respondAddressError(ctx, "erro ao consultar município", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, municipality)
+}
+
+// respondAddressError converte erros da consulta de endereço em respostas HTTP
+func (c *AddressController) respondAddressError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, cep.ErrZipCodeNotFound), errors.Is(err, errMunicipalityNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, cep.ErrInvalidZipCode):
+		status = http.StatusBadRequest
+	case errors.Is(err, cep.ErrUnavailable):
+		status = http.StatusBadGateway
+		c.logger.Warn(message, "provider", c.cepProvider.Name(), "error", err.Error())
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"time"
@@ -35,6 +36,7 @@ func NewAuthController(userRepository user.Repository) *AuthController {
 // @Success 200 {object} dto.LoginResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.LoginTenantSelectionResponse "Email cadastrado em mais de um tenant: repita o login com tenant_id"
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /auth/login [post]
 func (c *AuthController) Login(ctx *gin.Context) {
@@ -52,8 +54,13 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		// Se o tenant_id foi fornecido, buscar no tenant específico
 		u, err = c.userRepository.FindByEmail(ctx, request.TenantID, request.Email)
 	} else {
-		// Se o tenant_id não foi fornecido, buscar em todos os tenants
-		u, err = c.userRepository.FindByEmailAcrossTenants(ctx, request.Email)
+		// Sem tenant_id, o índice global de logins aponta os tenants do email
+		var matches []user.Login
+		u, matches, err = c.findLoginAcrossTenants(ctx, request.Email, request.Password)
+		if err == nil && len(matches) > 1 {
+			ctx.JSON(http.StatusConflict, dto.ToLoginTenantSelectionResponse(matches))
+			return
+		}
 	}
 
 	if err != nil {
@@ -77,14 +84,16 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		return
 	}
 
-	// Gerar o token JWT
-	jwtService, err := auth.NewJWTService()
+	// Carregar as filiais do usuário, para que o cliente possa oferecer a troca de filial
+	tenantCtx := tenant.SetTenantIDContext(ctx, u.TenantID)
+	u.Branches, err = c.userRepository.ListMemberships(tenantCtx, u.ID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao configurar autenticação", err.Error()))
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar filiais do usuário", err.Error()))
 		return
 	}
 
-	token, err := jwtService.GenerateToken(u)
+	// Gerar o token JWT, com a filial principal como filial ativa
+	token, role, err := c.issueToken(tenantCtx, u, u.BranchID)
 	if err != nil {
 		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao gerar token", err.Error()))
 		return
@@ -96,8 +105,8 @@ func (c *AuthController) Login(ctx *gin.Context) {
 	// Obter duração do token (24h por padrão)
 	expirationTime := time.Now().Add(24 * time.Hour)
 
-	// Atualizar o último login do usuário
-	err = c.userRepository.UpdateLastLogin(ctx, u.ID)
+	// Atualizar o último login do usuário, no tenant em que ele se autenticou
+	err = c.userRepository.UpdateLastLogin(tenantCtx, u.ID)
 	if err != nil {
 		// Apenas logar o erro, não impedir o login
 	}
@@ -108,11 +117,71 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		AccessToken:  token,
 		RefreshToken: refreshToken,
 		ExpiresAt:    expirationTime,
+		BranchID:     u.BranchID,
+		Role:         string(role),
 	}
 
 	ctx.JSON(http.StatusOK, response)
 }
 
+// issueToken gera o token JWT do usuário com a filial ativa informada e o papel que ele exerce
+// nela. Na filial principal vale o papel do cadastro; nas demais, o acesso é conferido no
+// repositório e pode retornar user.ErrNoBranchAccess
+func (c *AuthController) issueToken(ctx context.Context, u *user.User, branchID string) (string, user.Role, error) {
+	role := u.Role
+	if branchID != "" && branchID != u.BranchID {
+		var err error
+		if role, err = c.userRepository.FindBranchRole(ctx, u.ID, branchID); err != nil {
+			return "", "", err
+		}
+	}
+
+	jwtService, err := auth.NewJWTService()
+	if err != nil {
+		return "", "", err
+	}
+
+	token, err := jwtService.GenerateBranchToken(u, branchID, role)
+	if err != nil {
+		return "", "", err
+	}
+	return token, role, nil
+}
+
+// findLoginAcrossTenants procura o email nos tenants apontados pelo índice de logins. Apenas os
+// tenants em que a senha confere são considerados, para que a lista de empresas nunca seja revelada
+// sem credenciais válidas; com mais de um, o usuário precisa escolher o tenant
+func (c *AuthController) findLoginAcrossTenants(ctx context.Context, email, password string) (*user.User, []user.Login, error) {
+	logins, err := c.userRepository.FindLogins(ctx, email)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var (
+		found   *user.User
+		matches []user.Login
+	)
+	for _, login := range logins {
+		candidate, err := c.userRepository.FindByEmail(ctx, login.TenantID, login.Email)
+		if errors.Is(err, repository.ErrUserNotFound) {
+			// Índice desatualizado; corrigido pelo comando de reconstrução
+			continue
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if candidate.CheckPassword(password) {
+			found = candidate
+			matches = append(matches, login)
+		}
+	}
+
+	if found == nil {
+		return nil, nil, repository.ErrUserNotFound
+	}
+	return found, matches, nil
+}
+
 // RefreshToken renova um token JWT
 // @Summary Renova um token JWT
 // @Description Renova um token JWT existente
@@ -172,6 +241,19 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		return
 	}
 
+	// Reemitir o token na filial ativa, conferindo se o usuário ainda tem acesso a ela. Sem
+	// acesso, o token volta para a filial principal
+	branchID := claims.BranchID
+	newToken, role, err := c.issueToken(reqCtx, u, branchID)
+	if errors.Is(err, user.ErrNoBranchAccess) {
+		branchID = u.BranchID
+		newToken, role, err = c.issueToken(reqCtx, u, branchID)
+	}
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao renovar token", err.Error()))
+		return
+	}
+
 	// Obter duração do token (24h por padrão)
 	expirationTime := time.Now().Add(24 * time.Hour)
 
@@ -180,6 +262,8 @@ func (c *AuthController) RefreshToken(ctx *gin.Context) {
 		AccessToken:  newToken,
 		RefreshToken: newToken, // Usar o mesmo token para simplicidade
 		ExpiresAt:    expirationTime,
+		BranchID:     branchID,
+		Role:         string(role),
 	}
 
 	ctx.JSON(http.StatusOK, response)
@@ -239,5 +323,76 @@ func (c *AuthController) Me(ctx *gin.Context) {
 		return
 	}
 
+	u.Branches, err = c.userRepository.ListMemberships(reqCtx, userIDStr)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar filiais do usuário", err.Error()))
+		return
+	}
+
 	ctx.JSON(http.StatusOK, dto.ToUserResponse(u))
 }
+
+// SwitchBranch troca a filial ativa do usuário autenticado
+// @Summary Troca a filial ativa
+// @Description Emite um novo token com a filial informada como filial ativa e o papel que o usuário exerce nela. O usuário precisa ter vínculo com a filial, exceto administradores
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Security Bearer
+// @Param branch body dto.SwitchBranchRequest true "Filial desejada"
+// @Success 200 {object} dto.LoginResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /auth/switch-branch [post]
+func (c *AuthController) SwitchBranch(ctx *gin.Context) {
+	var request dto.SwitchBranchRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Requisição inválida", err.Error()))
+		return
+	}
+
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	reqCtx := tenant.SetTenantIDContext(ctx.Request.Context(), tenantID)
+
+	u, err := c.userRepository.FindByID(reqCtx, userID)
+	if err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Usuário não encontrado", ""))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar usuário", err.Error()))
+		return
+	}
+
+	if !u.IsActive() {
+		ctx.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Usuário inativo", "Sua conta está desativada ou bloqueada"))
+		return
+	}
+
+	token, role, err := c.issueToken(reqCtx, u, request.BranchID)
+	if err != nil {
+		if errors.Is(err, user.ErrNoBranchAccess) {
+			ctx.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Acesso negado à filial", err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao gerar token", err.Error()))
+		return
+	}
+
+	u.Branches, err = c.userRepository.ListMemberships(reqCtx, u.ID)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar filiais do usuário", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.LoginResponse{
+		User:         dto.ToUserResponse(u),
+		AccessToken:  token,
+		RefreshToken: token,
+		ExpiresAt:    time.Now().Add(24 * time.Hour),
+		BranchID:     request.BranchID,
+		Role:         string(role),
+	})
+}
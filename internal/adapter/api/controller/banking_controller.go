@@ -0,0 +1,739 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/banking"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/payable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+	"github.com/hugohenrick/erp-supermercado/pkg/ofx"
+)
+
+// bankPaymentMethod identifica as baixas geradas pela conciliação bancária
+const bankPaymentMethod = "bank"
+
+// defaultCashFlowDays é o horizonte do fluxo de caixa quando o período não é informado
+const defaultCashFlowDays = 30
+
+// BankingController manipula as requisições de contas bancárias, conciliação e fluxo de caixa
+type BankingController struct {
+	bankingRepo    banking.Repository
+	receivableRepo receivable.Repository
+	payableRepo    payable.Repository
+	logger         logger.Logger
+}
+
+// NewBankingController cria uma nova instância de BankingController
+func NewBankingController(bankingRepo banking.Repository, receivableRepo receivable.Repository, payableRepo payable.Repository, logger logger.Logger) *BankingController {
+	return &BankingController{
+		bankingRepo:    bankingRepo,
+		receivableRepo: receivableRepo,
+		payableRepo:    payableRepo,
+		logger:         logger,
+	}
+}
+
+// CreateAccount cria uma conta bancária
+// @Summary Criar conta bancária
+// @Description Cadastra uma conta bancária com o saldo de abertura usado no fluxo de caixa
+// @Tags Contas Bancárias
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param account body dto.BankAccountRequest true "Dados da conta bancária"
+// @Success 201 {object} banking.Account
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-accounts [post]
+func (c *BankingController) CreateAccount(ctx *gin.Context) {
+	var req dto.BankAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if !authorizeBranch(ctx, req.BranchID) {
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	a, err := banking.NewAccount(tenantID, req.Name, req.BankCode, req.Agency, req.AccountNumber,
+		banking.AccountType(req.Type), req.OpeningBalance, req.OpeningDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar conta bancária", err.Error()))
+		return
+	}
+	a.BranchID = req.BranchID
+	if req.Active != nil {
+		a.Active = *req.Active
+	}
+
+	if err := c.bankingRepo.CreateAccount(ctx, a); err != nil {
+		c.respondBankingError(ctx, "erro ao salvar conta bancária", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, a)
+}
+
+// UpdateAccount atualiza uma conta bancária
+// @Summary Atualizar conta bancária
+// @Description Atualiza os dados e o saldo de abertura de uma conta bancária
+// @Tags Contas Bancárias
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da conta bancária"
+// @Param account body dto.BankAccountRequest true "Dados da conta bancária"
+// @Success 200 {object} banking.Account
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-accounts/{id} [put]
+func (c *BankingController) UpdateAccount(ctx *gin.Context) {
+	var req dto.BankAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	a, err := c.bankingRepo.FindAccountByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao buscar conta bancária", err)
+		return
+	}
+	if !authorizeBranch(ctx, a.BranchID) || !authorizeBranch(ctx, req.BranchID) {
+		return
+	}
+
+	a.BranchID = req.BranchID
+	a.Name = strings.TrimSpace(req.Name)
+	a.BankCode = req.BankCode
+	a.Agency = req.Agency
+	a.AccountNumber = req.AccountNumber
+	a.Type = banking.AccountType(req.Type)
+	a.OpeningBalance = math.Round(req.OpeningBalance*100) / 100
+	if !req.OpeningDate.IsZero() {
+		a.OpeningDate = time.Date(req.OpeningDate.Year(), req.OpeningDate.Month(), req.OpeningDate.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if req.Active != nil {
+		a.Active = *req.Active
+	}
+	a.UpdatedAt = time.Now()
+
+	if err := a.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.bankingRepo.UpdateAccount(ctx, a); err != nil {
+		c.respondBankingError(ctx, "erro ao atualizar conta bancária", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, a)
+}
+
+// GetAccount busca uma conta bancária
+// @Summary Obter conta bancária
+// @Description Busca uma conta bancária pelo ID
+// @Tags Contas Bancárias
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da conta bancária"
+// @Success 200 {object} banking.Account
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-accounts/{id} [get]
+func (c *BankingController) GetAccount(ctx *gin.Context) {
+	a, err := c.bankingRepo.FindAccountByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao buscar conta bancária", err)
+		return
+	}
+	if !authorizeBranch(ctx, a.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, a)
+}
+
+// ListAccounts lista as contas bancárias
+// @Summary Listar contas bancárias
+// @Description Lista as contas bancárias do tenant, ativas primeiro
+// @Tags Contas Bancárias
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} banking.Account
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-accounts [get]
+func (c *BankingController) ListAccounts(ctx *gin.Context) {
+	accounts, err := c.bankingRepo.ListAccounts(ctx)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao listar contas bancárias", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accounts)
+}
+
+// ImportStatement importa um extrato OFX
+// @Summary Importar extrato OFX
+// @Description Importa o extrato da conta, descarta lançamentos já importados e concilia automaticamente os que têm um único título correspondente por valor, data e documento
+// @Tags Contas Bancárias
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da conta bancária"
+// @Param file formData file true "Arquivo OFX"
+// @Success 201 {object} dto.BankImportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-accounts/{id}/statements [post]
+func (c *BankingController) ImportStatement(ctx *gin.Context) {
+	a, err := c.bankingRepo.FindAccountByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao buscar conta bancária", err)
+		return
+	}
+	if !authorizeBranch(ctx, a.BranchID) {
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo inválido", err.Error()))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+	defer src.Close()
+
+	buffer := bytes.NewBuffer(nil)
+	if _, err := io.Copy(buffer, src); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+
+	stmt, err := ofx.Parse(buffer.Bytes())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo OFX inválido", err.Error()))
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	imp, transactions, err := banking.NewImport(a, file.Filename, stmt, userID)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao importar extrato", err)
+		return
+	}
+
+	inserted, err := c.bankingRepo.CreateImport(ctx, imp, transactions)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao importar extrato", err)
+		return
+	}
+
+	response := dto.BankImportResponse{Import: imp, Inserted: len(inserted)}
+	for _, t := range inserted {
+		candidates, err := c.bankingRepo.FindCandidates(ctx, t)
+		if err != nil {
+			response.Failed = append(response.Failed, fmt.Sprintf("%s: %s", t.FITID, err.Error()))
+			continue
+		}
+
+		best, ok := banking.AutoMatch(t, candidates)
+		if !ok {
+			continue
+		}
+		if err := c.match(ctx, t, *best, true, userID); err != nil {
+			response.Failed = append(response.Failed, fmt.Sprintf("%s: %s", t.FITID, err.Error()))
+			continue
+		}
+		imp.Matched++
+	}
+
+	if imp.Matched > 0 {
+		if err := c.bankingRepo.UpdateImportMatched(ctx, imp); err != nil {
+			c.logger.Error("erro ao atualizar importação do extrato", "error", err.Error())
+		}
+	}
+
+	response.Matched = imp.Matched
+	response.Pending = response.Inserted - imp.Matched
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListImports lista as importações de extrato de uma conta
+// @Summary Listar importações de extrato
+// @Description Lista os extratos OFX importados na conta, do mais recente para o mais antigo
+// @Tags Contas Bancárias
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da conta bancária"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {array} banking.Import
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-accounts/{id}/statements [get]
+func (c *BankingController) ListImports(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	imports, err := c.bankingRepo.ListImports(ctx, ctx.Param("id"), pagination.PageSize, offset)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao listar importações de extrato", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, imports)
+}
+
+// ListTransactions lista os lançamentos do extrato
+// @Summary Listar lançamentos do extrato
+// @Description Lista os lançamentos por conta, importação, situação e período. Com candidates=true, os pendentes trazem as sugestões de conciliação
+// @Tags Contas Bancárias
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param account_id query string false "Filtrar por conta bancária"
+// @Param import_id query string false "Filtrar por importação"
+// @Param status query string false "Filtrar por situação (pending, matched, ignored)"
+// @Param start_date query string false "Data inicial (YYYY-MM-DD)"
+// @Param end_date query string false "Data final (YYYY-MM-DD)"
+// @Param candidates query bool false "Incluir sugestões de conciliação nos pendentes"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.BankTransactionListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-transactions [get]
+func (c *BankingController) ListTransactions(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", "use o formato YYYY-MM-DD"))
+		return
+	}
+
+	filter := banking.TransactionFilter{
+		AccountID: ctx.Query("account_id"),
+		ImportID:  ctx.Query("import_id"),
+		Status:    banking.TransactionStatus(ctx.Query("status")),
+		From:      startDate,
+		To:        endDate,
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	transactions, err := c.bankingRepo.ListTransactions(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao listar lançamentos do extrato", err)
+		return
+	}
+
+	total, err := c.bankingRepo.CountTransactions(ctx, filter)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao contar lançamentos do extrato", err)
+		return
+	}
+
+	if ctx.Query("candidates") == "true" {
+		for _, t := range transactions {
+			if err := c.loadCandidates(ctx, t); err != nil {
+				c.respondBankingError(ctx, "erro ao buscar sugestões de conciliação", err)
+				return
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToBankTransactionListResponse(transactions, total, pagination.Page, pagination.PageSize))
+}
+
+// GetTransaction busca um lançamento do extrato
+// @Summary Obter lançamento do extrato
+// @Description Busca um lançamento pelo ID; se pendente, traz as sugestões de conciliação ordenadas pela pontuação
+// @Tags Contas Bancárias
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do lançamento"
+// @Success 200 {object} banking.Transaction
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-transactions/{id} [get]
+func (c *BankingController) GetTransaction(ctx *gin.Context) {
+	t, ok := c.findTransaction(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.loadCandidates(ctx, t); err != nil {
+		c.respondBankingError(ctx, "erro ao buscar sugestões de conciliação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// MatchTransaction concilia manualmente um lançamento do extrato
+// @Summary Conciliar lançamento
+// @Description Vincula o lançamento a uma baixa existente (payment_id) ou baixa o título em aberto com a data e o valor do lançamento
+// @Tags Contas Bancárias
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do lançamento"
+// @Param match body dto.BankMatchRequest true "Título ou baixa a conciliar"
+// @Success 200 {object} banking.Transaction
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-transactions/{id}/match [post]
+func (c *BankingController) MatchTransaction(ctx *gin.Context) {
+	var req dto.BankMatchRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	t, ok := c.findTransaction(ctx)
+	if !ok {
+		return
+	}
+
+	candidate := banking.Candidate{Kind: banking.MatchKind(req.Kind), TitleID: req.TitleID, PaymentID: req.PaymentID}
+	if !candidate.IsOpenTitle() {
+		if err := c.checkPayment(ctx, t, candidate); err != nil {
+			c.respondBankingError(ctx, "erro ao conciliar lançamento", err)
+			return
+		}
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	if err := c.match(ctx, t, candidate, false, userID); err != nil {
+		c.respondBankingError(ctx, "erro ao conciliar lançamento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// UnmatchTransaction desfaz a conciliação de um lançamento
+// @Summary Desfazer conciliação
+// @Description Devolve o lançamento conciliado ou ignorado para pendente; a baixa do título é mantida e volta a ser sugerida na conciliação
+// @Tags Contas Bancárias
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do lançamento"
+// @Success 200 {object} banking.Transaction
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-transactions/{id}/unmatch [post]
+func (c *BankingController) UnmatchTransaction(ctx *gin.Context) {
+	t, ok := c.findTransaction(ctx)
+	if !ok {
+		return
+	}
+
+	from := t.Status
+	if err := t.Unmatch(); err != nil {
+		c.respondBankingError(ctx, "erro ao desfazer conciliação", err)
+		return
+	}
+
+	if err := c.bankingRepo.UpdateMatch(ctx, t, from, nil); err != nil {
+		c.respondBankingError(ctx, "erro ao desfazer conciliação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// IgnoreTransaction marca um lançamento como sem título correspondente
+// @Summary Ignorar lançamento
+// @Description Retira da conciliação lançamentos sem título, como tarifas e transferências entre contas
+// @Tags Contas Bancárias
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do lançamento"
+// @Success 200 {object} banking.Transaction
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /bank-transactions/{id}/ignore [post]
+func (c *BankingController) IgnoreTransaction(ctx *gin.Context) {
+	t, ok := c.findTransaction(ctx)
+	if !ok {
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	if err := t.Ignore(userID); err != nil {
+		c.respondBankingError(ctx, "erro ao ignorar lançamento", err)
+		return
+	}
+
+	if err := c.bankingRepo.UpdateMatch(ctx, t, banking.TransactionPending, nil); err != nil {
+		c.respondBankingError(ctx, "erro ao ignorar lançamento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// CashFlow retorna o fluxo de caixa diário
+// @Summary Fluxo de caixa
+// @Description Combina recebimentos e pagamentos realizados com o saldo previsto de contas a receber e a pagar por dia. Títulos vencidos em aberto são projetados para hoje
+// @Tags Fluxo de Caixa
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param start_date query string false "Data inicial (YYYY-MM-DD, padrão: hoje)"
+// @Param end_date query string false "Data final (YYYY-MM-DD, padrão: 30 dias após a inicial)"
+// @Success 200 {object} banking.CashFlow
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cash-flow [get]
+func (c *BankingController) CashFlow(ctx *gin.Context) {
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", "use o formato YYYY-MM-DD"))
+		return
+	}
+
+	now := time.Now()
+	if startDate.IsZero() {
+		startDate = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local)
+	}
+	if endDate.IsZero() {
+		endDate = startDate.AddDate(0, 0, defaultCashFlowDays)
+	}
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := banking.CashFlowFilter{
+		BranchID: branchID,
+		From:     startDate,
+		To:       endDate,
+	}
+
+	data, err := c.bankingRepo.CashFlowData(ctx, filter)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao calcular fluxo de caixa", err)
+		return
+	}
+
+	flow, err := banking.BuildCashFlow(startDate, endDate, now, data)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao calcular fluxo de caixa", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, flow)
+}
+
+// loadCandidates preenche as sugestões de conciliação de um lançamento pendente
+func (c *BankingController) loadCandidates(ctx *gin.Context, t *banking.Transaction) error {
+	if t.Status != banking.TransactionPending {
+		return nil
+	}
+
+	candidates, err := c.bankingRepo.FindCandidates(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	t.Candidates = banking.Rank(t, candidates)
+	return nil
+}
+
+// match concilia o lançamento; títulos em aberto são baixados na mesma transação
+func (c *BankingController) match(ctx *gin.Context, t *banking.Transaction, candidate banking.Candidate, auto bool, userID string) error {
+	if t.Status != banking.TransactionPending {
+		return banking.ErrNotPending
+	}
+	if candidate.Kind != t.Kind() {
+		return banking.ErrDirectionMismatch
+	}
+
+	var settlement *banking.Settlement
+	if candidate.IsOpenTitle() {
+		var err error
+		settlement, candidate.PaymentID, err = c.settle(ctx, t, candidate, userID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := t.Match(candidate, auto, userID); err != nil {
+		return err
+	}
+
+	return c.bankingRepo.UpdateMatch(ctx, t, banking.TransactionPending, settlement)
+}
+
+// findTransaction busca o lançamento do caminho e confere o acesso do usuário à filial da conta
+// bancária. Em caso de erro, responde e retorna false
+func (c *BankingController) findTransaction(ctx *gin.Context) (*banking.Transaction, bool) {
+	t, err := c.bankingRepo.FindTransactionByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao buscar lançamento do extrato", err)
+		return nil, false
+	}
+
+	a, err := c.bankingRepo.FindAccountByID(ctx, t.AccountID)
+	if err != nil {
+		c.respondBankingError(ctx, "erro ao buscar conta bancária", err)
+		return nil, false
+	}
+	return t, authorizeBranch(ctx, a.BranchID)
+}
+
+// settle prepara a baixa do título em aberto com a data e o valor do lançamento.
+// O valor acima do saldo do título é registrado como juros
+func (c *BankingController) settle(ctx *gin.Context, t *banking.Transaction, candidate banking.Candidate, userID string) (*banking.Settlement, string, error) {
+	amount := math.Abs(t.Amount)
+	notes := fmt.Sprintf("Conciliação bancária do lançamento %s", t.FITID)
+
+	switch candidate.Kind {
+	case banking.MatchReceivable:
+		rec, err := c.receivableRepo.FindByID(ctx, candidate.TitleID)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := auth.AuthorizeBranch(ctx, rec.BranchID); err != nil {
+			return nil, "", err
+		}
+		principal, interest := splitBankAmount(amount, rec.Balance())
+		payment, err := rec.Receive(principal, interest, 0, 0, t.PostedAt, bankPaymentMethod, notes, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		return &banking.Settlement{Receivable: rec, ReceivablePayment: payment}, payment.ID, nil
+	case banking.MatchPayable:
+		p, err := c.payableRepo.FindByID(ctx, candidate.TitleID)
+		if err != nil {
+			return nil, "", err
+		}
+		if _, err := auth.AuthorizeBranch(ctx, p.BranchID); err != nil {
+			return nil, "", err
+		}
+		principal, interest := splitBankAmount(amount, p.Balance())
+		payment, err := p.Pay(principal, interest, 0, 0, t.PostedAt, bankPaymentMethod, notes, userID)
+		if err != nil {
+			return nil, "", err
+		}
+		return &banking.Settlement{Payable: p, PayablePayment: payment}, payment.ID, nil
+	}
+
+	return nil, "", banking.ErrInvalidMatchKind
+}
+
+// checkPayment verifica se a baixa informada pertence ao título e tem o valor do lançamento
+func (c *BankingController) checkPayment(ctx *gin.Context, t *banking.Transaction, candidate banking.Candidate) error {
+	amount := math.Round(math.Abs(t.Amount) * 100)
+
+	switch candidate.Kind {
+	case banking.MatchReceivable:
+		rec, err := c.receivableRepo.FindByID(ctx, candidate.TitleID)
+		if err != nil {
+			return err
+		}
+		if _, err := auth.AuthorizeBranch(ctx, rec.BranchID); err != nil {
+			return err
+		}
+		for _, payment := range rec.Payments {
+			if payment.ID == candidate.PaymentID {
+				if math.Round(payment.Total*100) != amount {
+					return banking.ErrAmountMismatch
+				}
+				return nil
+			}
+		}
+	case banking.MatchPayable:
+		p, err := c.payableRepo.FindByID(ctx, candidate.TitleID)
+		if err != nil {
+			return err
+		}
+		if _, err := auth.AuthorizeBranch(ctx, p.BranchID); err != nil {
+			return err
+		}
+		for _, payment := range p.Payments {
+			if payment.ID == candidate.PaymentID {
+				if math.Round(payment.Total*100) != amount {
+					return banking.ErrAmountMismatch
+				}
+				return nil
+			}
+		}
+	default:
+		return banking.ErrInvalidMatchKind
+	}
+
+	return banking.ErrMatchTargetRequired
+}
+
+// splitBankAmount separa o valor do lançamento em principal, limitado ao saldo, e juros
+func splitBankAmount(amount, balance float64) (float64, float64) {
+	if amount <= balance {
+		return amount, 0
+	}
+	return balance, math.Round((amount-balance)*100) / 100
+}
+
+// respondBankingError converte erros do domínio e do repositório em respostas HTTP
+func (c *BankingController) respondBankingError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrBankAccountNotFound), errors.Is(err, repository.ErrBankTransactionNotFound),
+		errors.Is(err, repository.ErrReceivableNotFound), errors.Is(err, repository.ErrPayableNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrBankTransactionConcurrent), errors.Is(err, repository.ErrBankPaymentAlreadyMatched),
+		errors.Is(err, repository.ErrReceivableConcurrentUpdate), errors.Is(err, repository.ErrPayableConcurrentUpdate),
+		errors.Is(err, banking.ErrNotPending), errors.Is(err, banking.ErrNotMatched):
+		status = http.StatusConflict
+	case errors.Is(err, banking.ErrAccountInactive), errors.Is(err, banking.ErrAccountMismatch),
+		errors.Is(err, banking.ErrEmptyStatement), errors.Is(err, banking.ErrDirectionMismatch),
+		errors.Is(err, banking.ErrAmountMismatch), errors.Is(err, banking.ErrInvalidMatchKind),
+		errors.Is(err, banking.ErrMatchTargetRequired), errors.Is(err, receivable.ErrNotOpen),
+		errors.Is(err, receivable.ErrInvalidPayment), errors.Is(err, payable.ErrNotOpen),
+		errors.Is(err, payable.ErrInvalidPayment):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, banking.ErrInvalidPeriod), errors.Is(err, banking.ErrPeriodTooLong):
+		status = http.StatusBadRequest
+	case errors.Is(err, user.ErrNoBranchAccess):
+		status = http.StatusForbidden
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
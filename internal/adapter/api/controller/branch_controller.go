@@ -1,28 +1,31 @@
 package controller
 
 import (
+	"context"
 	"errors"
 	"net/http"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/branch"
+	"github.com/hugohenrick/erp-supermercado/pkg/cep"
 	"github.com/hugohenrick/erp-supermercado/pkg/tenant"
 )
 
 // BranchController gerencia as requisições relacionadas a filiais
 type BranchController struct {
 	branchRepository branch.Repository
+	cepProvider      cep.Provider
 }
 
 // NewBranchController cria uma nova instância de BranchController
-func NewBranchController(branchRepository branch.Repository) *BranchController {
+func NewBranchController(branchRepository branch.Repository, cepProvider cep.Provider) *BranchController {
 	return &BranchController{
 		branchRepository: branchRepository,
+		cepProvider:      cepProvider,
 	}
 }
 
@@ -36,6 +39,7 @@ func NewBranchController(branchRepository branch.Repository) *BranchController {
 // @Param branch body dto.BranchRequest true "Dados da filial"
 // @Success 201 {object} dto.BranchResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 402 {object} dto.ErrorResponse
 // @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /branches [post]
@@ -53,20 +57,16 @@ func (c *BranchController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Gerar um ID para a nova filial
-	id := uuid.New().String()
-
-	// Criar o modelo de domínio a partir do DTO
-	b := &branch.Branch{
-		ID:       id,
-		TenantID: tenantID,
-		Name:     request.Name,
-		Code:     request.Code,
-		Type:     branch.BranchType(request.Type),
-		Document: request.Document,
-		Phone:    request.Phone,
-		Email:    request.Email,
-		Address: branch.Address{
+	c.completeAddress(ctx, &request.Address)
+
+	// Criar o modelo de domínio a partir do DTO; o construtor valida o CNPJ da filial
+	b, err := branch.NewBranch(
+		tenantID,
+		request.Name,
+		request.Code,
+		branch.BranchType(request.Type),
+		request.Document,
+		branch.Address{
 			Street:     request.Address.Street,
 			Number:     request.Address.Number,
 			Complement: request.Address.Complement,
@@ -75,20 +75,29 @@ func (c *BranchController) Create(ctx *gin.Context) {
 			State:      request.Address.State,
 			ZipCode:    request.Address.ZipCode,
 			Country:    request.Address.Country,
+			CityCode:   request.Address.CityCode,
+			StateCode:  request.Address.StateCode,
 		},
-		Status:    branch.StatusActive, // Por padrão, a filial é criada ativa
-		IsMain:    request.IsMain,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+		request.Phone,
+		request.Email,
+		request.IsMain,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados da filial inválidos", err.Error()))
+		return
 	}
 
 	// Persistir a filial
-	err := c.branchRepository.Create(ctx, b)
+	err = c.branchRepository.Create(ctx, b)
 	if err != nil {
 		if errors.Is(err, repository.ErrBranchDuplicateKey) {
 			ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Filial com mesmo código já existe para este tenant", ""))
 			return
 		}
+		if errors.Is(err, repository.ErrPlanLimitExceeded) {
+			ctx.JSON(http.StatusPaymentRequired, dto.NewErrorResponse(http.StatusPaymentRequired, "Limite de filiais do plano atingido", err.Error()))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao criar filial", err.Error()))
 		return
 	}
@@ -261,11 +270,19 @@ func (c *BranchController) Update(ctx *gin.Context) {
 		return
 	}
 
+	document, err := branch.NormalizeDocument(request.Document)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados da filial inválidos", err.Error()))
+		return
+	}
+
+	c.completeAddress(ctx, &request.Address)
+
 	// Atualizar a filial existente com os novos dados
 	existingBranch.Name = request.Name
 	existingBranch.Code = request.Code
 	existingBranch.Type = branch.BranchType(request.Type)
-	existingBranch.Document = request.Document
+	existingBranch.Document = document
 	existingBranch.Phone = request.Phone
 	existingBranch.Email = request.Email
 	existingBranch.Address = branch.Address{
@@ -277,6 +294,8 @@ func (c *BranchController) Update(ctx *gin.Context) {
 		State:      request.Address.State,
 		ZipCode:    request.Address.ZipCode,
 		Country:    request.Address.Country,
+		CityCode:   request.Address.CityCode,
+		StateCode:  request.Address.StateCode,
 	}
 	existingBranch.IsMain = request.IsMain
 	existingBranch.UpdatedAt = time.Now()
@@ -382,3 +401,28 @@ func (c *BranchController) UpdateStatus(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, dto.ToBranchResponse(b))
 }
+
+// completeAddress preenche os códigos IBGE e, pelo CEP, os campos do endereço não informados. Se a
+// consulta falhar, a filial é gravada com o endereço como veio na requisição
+func (c *BranchController) completeAddress(ctx context.Context, address *dto.AddressRequest) {
+	completeAddressRequest(ctx, c.cepProvider, address)
+}
+
+// completeAddressRequest completa o endereço da requisição pelo provedor de CEP, ignorando falhas
+// na consulta
+func completeAddressRequest(ctx context.Context, provider cep.Provider, address *dto.AddressRequest) {
+	a := cep.Address{
+		ZipCode:   address.ZipCode,
+		Street:    address.Street,
+		District:  address.District,
+		City:      address.City,
+		State:     address.State,
+		CityCode:  address.CityCode,
+		StateCode: address.StateCode,
+	}
+	_ = cep.Complete(ctx, provider, &a)
+
+	address.Street, address.District = a.Street, a.District
+	address.City, address.State = a.City, a.State
+	address.CityCode, address.StateCode = a.CityCode, a.StateCode
+}
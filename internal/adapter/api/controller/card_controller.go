@@ -0,0 +1,492 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/card"
+	"github.com/hugohenrick/erp-supermercado/pkg/acquirer"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/domain"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// CardController manipula as requisições de taxas, agenda de recebimentos e conciliação das adquirentes
+type CardController struct {
+	cardRepo card.Repository
+	logger   logger.Logger
+}
+
+// NewCardController cria uma nova instância de CardController
+func NewCardController(cardRepo card.Repository, logger logger.Logger) *CardController {
+	return &CardController{
+		cardRepo: cardRepo,
+		logger:   logger,
+	}
+}
+
+// CreateFee cadastra uma taxa de adquirente
+// @Summary Criar taxa de adquirente
+// @Description Cadastra a taxa e o prazo contratados com a adquirente para uma bandeira, produto e faixa de parcelas
+// @Tags Cartões
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param fee body dto.CardFeeRequest true "Dados da taxa"
+// @Success 201 {object} card.FeeTable
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/fees [post]
+func (c *CardController) CreateFee(ctx *gin.Context) {
+	var req dto.CardFeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	f, err := card.NewFeeTable(tenantID, req.Acquirer, req.Brand, card.Product(req.Product), req.InstallmentsFrom,
+		req.InstallmentsTo, req.FeePercent, req.SettlementDays)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar taxa de adquirente", err.Error()))
+		return
+	}
+	if req.Active != nil {
+		f.Active = *req.Active
+	}
+
+	if err := c.cardRepo.CreateFeeTable(ctx, f); err != nil {
+		c.respondCardError(ctx, "erro ao salvar taxa de adquirente", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, f)
+}
+
+// UpdateFee atualiza uma taxa de adquirente
+// @Summary Atualizar taxa de adquirente
+// @Description Atualiza a taxa e o prazo contratados. Vendas já registradas mantêm a agenda calculada na época
+// @Tags Cartões
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da taxa"
+// @Param fee body dto.CardFeeRequest true "Dados da taxa"
+// @Success 200 {object} card.FeeTable
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/fees/{id} [put]
+func (c *CardController) UpdateFee(ctx *gin.Context) {
+	var req dto.CardFeeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	f, err := c.cardRepo.FindFeeTableByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondCardError(ctx, "erro ao buscar taxa de adquirente", err)
+		return
+	}
+
+	f.Acquirer = card.NormalizeAcquirer(req.Acquirer)
+	f.Brand = acquirer.NormalizeBrand(req.Brand)
+	f.Product = card.Product(req.Product)
+	f.InstallmentsFrom = req.InstallmentsFrom
+	f.InstallmentsTo = req.InstallmentsTo
+	f.FeePercent = req.FeePercent
+	f.SettlementDays = req.SettlementDays
+	if req.Active != nil {
+		f.Active = *req.Active
+	}
+	f.UpdatedAt = time.Now()
+
+	if err := f.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.cardRepo.UpdateFeeTable(ctx, f); err != nil {
+		c.respondCardError(ctx, "erro ao atualizar taxa de adquirente", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, f)
+}
+
+// ListFees lista as taxas de adquirente
+// @Summary Listar taxas de adquirente
+// @Description Lista as taxas cadastradas, opcionalmente de uma adquirente
+// @Tags Cartões
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param acquirer query string false "Filtrar por adquirente"
+// @Success 200 {array} card.FeeTable
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/fees [get]
+func (c *CardController) ListFees(ctx *gin.Context) {
+	fees, err := c.cardRepo.ListFeeTables(ctx, ctx.Query("acquirer"))
+	if err != nil {
+		c.respondCardError(ctx, "erro ao listar taxas de adquirente", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, fees)
+}
+
+// CreateSale registra uma venda no cartão
+// @Summary Registrar venda no cartão
+// @Description Registra o pagamento autorizado no TEF/POS e gera a agenda de recebimentos pela taxa contratada
+// @Tags Cartões
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param sale body dto.CardSaleRequest true "Dados do pagamento em cartão"
+// @Success 201 {object} card.Sale
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/sales [post]
+func (c *CardController) CreateSale(ctx *gin.Context) {
+	var req dto.CardSaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+
+	fees, err := c.cardRepo.ListFeeTables(ctx, req.Acquirer)
+	if err != nil {
+		c.respondCardError(ctx, "erro ao buscar taxas de adquirente", err)
+		return
+	}
+
+	payment := domain.Payment{
+		ID:             req.PaymentID,
+		SaleID:         req.SaleID,
+		PaymentType:    req.PaymentType,
+		Amount:         req.Amount,
+		CardBrand:      req.CardBrand,
+		CardLastDigits: req.CardLastDigits,
+		Installments:   req.Installments,
+	}
+
+	s, err := card.NewSale(tenantID, branchID, req.Acquirer, req.NSU, req.AuthorizationCode,
+		payment, req.SoldAt, fees, userID)
+	if err != nil {
+		c.respondCardError(ctx, "erro ao registrar venda no cartão", err)
+		return
+	}
+
+	if err := c.cardRepo.CreateSale(ctx, s); err != nil {
+		c.respondCardError(ctx, "erro ao salvar venda no cartão", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, s)
+}
+
+// GetSale busca uma venda no cartão
+// @Summary Obter venda no cartão
+// @Description Busca uma venda no cartão com a agenda de recebimentos e a situação de cada parcela
+// @Tags Cartões
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da venda no cartão"
+// @Success 200 {object} card.Sale
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/sales/{id} [get]
+func (c *CardController) GetSale(ctx *gin.Context) {
+	s, err := c.cardRepo.FindSaleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondCardError(ctx, "erro ao buscar venda no cartão", err)
+		return
+	}
+	if !authorizeBranch(ctx, s.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, s)
+}
+
+// ListSales lista as vendas no cartão
+// @Summary Listar vendas no cartão
+// @Description Lista as vendas no cartão por filial, adquirente, situação e período da venda
+// @Tags Cartões
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param acquirer query string false "Filtrar por adquirente"
+// @Param status query string false "Filtrar por situação (pending, settled, divergent)"
+// @Param start_date query string false "Data inicial (YYYY-MM-DD)"
+// @Param end_date query string false "Data final (YYYY-MM-DD)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.CardSaleListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/sales [get]
+func (c *CardController) ListSales(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", "use o formato YYYY-MM-DD"))
+		return
+	}
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := card.SaleFilter{
+		BranchID: branchID,
+		Acquirer: ctx.Query("acquirer"),
+		Status:   card.SaleStatus(ctx.Query("status")),
+		From:     startDate,
+		To:       endDate,
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	sales, err := c.cardRepo.ListSales(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondCardError(ctx, "erro ao listar vendas no cartão", err)
+		return
+	}
+
+	total, err := c.cardRepo.CountSales(ctx, filter)
+	if err != nil {
+		c.respondCardError(ctx, "erro ao contar vendas no cartão", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToCardSaleListResponse(sales, total, pagination.Page, pagination.PageSize))
+}
+
+// ListSettlements lista a agenda de recebimentos
+// @Summary Listar agenda de recebimentos
+// @Description Lista as parcelas previstas por filial, adquirente, situação e data prevista. Com missing=true, traz apenas as parcelas vencidas há mais de dois dias que a adquirente não pagou
+// @Tags Cartões
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param acquirer query string false "Filtrar por adquirente"
+// @Param status query string false "Filtrar por situação (expected, settled, divergent)"
+// @Param start_date query string false "Data prevista inicial (YYYY-MM-DD)"
+// @Param end_date query string false "Data prevista final (YYYY-MM-DD)"
+// @Param missing query bool false "Somente parcelas não pagas pela adquirente"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.CardSettlementListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/settlements [get]
+func (c *CardController) ListSettlements(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", "use o formato YYYY-MM-DD"))
+		return
+	}
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := card.SettlementFilter{
+		BranchID: branchID,
+		Acquirer: ctx.Query("acquirer"),
+		Status:   card.SettlementStatus(ctx.Query("status")),
+		From:     startDate,
+		To:       endDate,
+	}
+
+	if ctx.Query("missing") == "true" {
+		cutoff := card.MissingCutoff(time.Now())
+		filter.Status = card.SettlementExpected
+		if filter.To.IsZero() || filter.To.After(cutoff) {
+			filter.To = cutoff
+		}
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	settlements, err := c.cardRepo.ListSettlements(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondCardError(ctx, "erro ao listar agenda de recebimentos", err)
+		return
+	}
+
+	total, err := c.cardRepo.CountSettlements(ctx, filter)
+	if err != nil {
+		c.respondCardError(ctx, "erro ao contar agenda de recebimentos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToCardSettlementListResponse(settlements, total, pagination.Page, pagination.PageSize))
+}
+
+// ImportSettlementFile importa um arquivo de liquidação da adquirente
+// @Summary Importar arquivo da adquirente
+// @Description Importa o arquivo de pagamentos (CSV ou EDI) e confere cada parcela com a agenda prevista, sinalizando taxas e valores divergentes e transações sem venda no ERP
+// @Tags Cartões
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param acquirer formData string true "Adquirente"
+// @Param format formData string true "Formato do arquivo (csv, edi)"
+// @Param file formData file true "Arquivo de liquidação"
+// @Success 201 {object} dto.CardImportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/settlement-files [post]
+func (c *CardController) ImportSettlementFile(ctx *gin.Context) {
+	format := acquirer.Format(strings.ToLower(ctx.PostForm("format")))
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo inválido", err.Error()))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+	defer src.Close()
+
+	buffer := bytes.NewBuffer(nil)
+	if _, err := io.Copy(buffer, src); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+
+	items, err := acquirer.Parse(format, buffer.Bytes())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo da adquirente inválido", err.Error()))
+		return
+	}
+
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	f, records, err := card.NewImportFile(tenantID, ctx.PostForm("acquirer"), format, file.Filename, items, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao importar arquivo da adquirente", err.Error()))
+		return
+	}
+
+	if err := c.cardRepo.CreateFile(ctx, f); err != nil {
+		c.respondCardError(ctx, "erro ao importar arquivo da adquirente", err)
+		return
+	}
+
+	response := dto.CardImportResponse{File: f}
+	for _, r := range records {
+		if err := c.cardRepo.ReconcileRecord(ctx, r); err != nil {
+			if errors.Is(err, card.ErrRecordProcessed) {
+				f.Duplicates++
+				continue
+			}
+			response.Failed = append(response.Failed, fmt.Sprintf("linha %d: %s", r.Line, err.Error()))
+			continue
+		}
+		f.Count(r)
+	}
+
+	if err := c.cardRepo.UpdateFileTotals(ctx, f); err != nil {
+		c.logger.Error("erro ao atualizar importação do arquivo da adquirente", "error", err.Error())
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// ListSettlementFiles lista os arquivos das adquirentes importados
+// @Summary Listar arquivos das adquirentes
+// @Description Lista as importações de arquivos de liquidação com os totais da conciliação, da mais recente para a mais antiga
+// @Tags Cartões
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {array} card.ImportFile
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/settlement-files [get]
+func (c *CardController) ListSettlementFiles(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	files, err := c.cardRepo.ListFiles(ctx, pagination.PageSize, offset)
+	if err != nil {
+		c.respondCardError(ctx, "erro ao listar arquivos das adquirentes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, files)
+}
+
+// ListFileRecords lista os registros de um arquivo da adquirente
+// @Summary Listar registros do arquivo da adquirente
+// @Description Lista os pagamentos informados no arquivo com o resultado da conciliação
+// @Tags Cartões
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da importação"
+// @Param status query string false "Filtrar por resultado (matched, divergent, unmatched)"
+// @Success 200 {array} card.Record
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /cards/settlement-files/{id}/records [get]
+func (c *CardController) ListFileRecords(ctx *gin.Context) {
+	records, err := c.cardRepo.ListRecords(ctx, ctx.Param("id"), card.RecordStatus(ctx.Query("status")))
+	if err != nil {
+		c.respondCardError(ctx, "erro ao listar registros do arquivo da adquirente", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, records)
+}
+
+// respondCardError converte erros da conciliação de cartões em respostas HTTP
+func (c *CardController) respondCardError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrCardFeeNotFound), errors.Is(err, repository.ErrCardSaleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrCardFeeDuplicated), errors.Is(err, repository.ErrCardSaleDuplicated):
+		status = http.StatusConflict
+	case errors.Is(err, card.ErrFeeNotFound), errors.Is(err, card.ErrNotCardPayment),
+		errors.Is(err, card.ErrDebitInstallments):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, card.ErrEmptyBranchID), errors.Is(err, card.ErrEmptyAcquirer),
+		errors.Is(err, card.ErrEmptyBrand), errors.Is(err, card.ErrEmptyNSU), errors.Is(err, card.ErrInvalidAmount):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
@@ -195,6 +195,10 @@ func (c *CertificateController) Create(ctx *gin.Context) {
 		return
 	}
 
+	if !authorizeBranch(ctx, req.BranchID) {
+		return
+	}
+
 	// Criar o certificado
 	cert, err := certificate.NewCertificate(tenantID, req.BranchID, req.Name, req.ExpirationDate)
 	if err != nil {
@@ -266,6 +270,9 @@ func (c *CertificateController) Upload(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", "todos os campos são obrigatórios"))
 		return
 	}
+	if !authorizeBranch(ctx, branchID) {
+		return
+	}
 
 	// Parsear a data de validade
 	expirationDate, err := time.Parse("2006-01-02", expirationDateStr)
@@ -403,6 +410,9 @@ func (c *CertificateController) Get(ctx *gin.Context) {
 		ctx.JSON(statusCode, dto.NewErrorResponse(statusCode, errorMsg, err.Error()))
 		return
 	}
+	if !authorizeBranch(ctx, cert.BranchID) {
+		return
+	}
 
 	ctx.JSON(http.StatusOK, dto.NewCertificateResponse(cert))
 }
@@ -437,7 +447,10 @@ func (c *CertificateController) List(ctx *gin.Context) {
 	offset := (page - 1) * pageSize
 
 	// Verificar se há filtro por filial
-	branchID := ctx.Query("branch_id")
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
 
 	var certificates []*certificate.Certificate
 	var err error
@@ -540,6 +553,9 @@ func (c *CertificateController) Update(ctx *gin.Context) {
 		ctx.JSON(statusCode, dto.NewErrorResponse(statusCode, errorMsg, err.Error()))
 		return
 	}
+	if !authorizeBranch(ctx, existingCert.BranchID) {
+		return
+	}
 
 	// Atualizar os dados do certificado
 	if req.Name != "" {
@@ -621,6 +637,9 @@ func (c *CertificateController) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "certificado não encontrado", fmt.Sprintf("certificado com ID %s não existe", id)))
 		return
 	}
+	if !c.authorizeCertificate(ctx, id) {
+		return
+	}
 
 	// Excluir o certificado
 	if err := c.certificateRepo.Delete(ctx, id); err != nil {
@@ -673,6 +692,9 @@ func (c *CertificateController) Activate(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "certificado não encontrado", fmt.Sprintf("certificado com ID %s não existe", id)))
 		return
 	}
+	if !c.authorizeCertificate(ctx, id) {
+		return
+	}
 
 	// Ativar o certificado
 	if err := c.certificateRepo.Activate(ctx, id); err != nil {
@@ -721,6 +743,9 @@ func (c *CertificateController) Deactivate(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "certificado não encontrado", fmt.Sprintf("certificado com ID %s não existe", id)))
 		return
 	}
+	if !c.authorizeCertificate(ctx, id) {
+		return
+	}
 
 	// Desativar o certificado
 	if err := c.certificateRepo.Deactivate(ctx, id); err != nil {
@@ -736,12 +761,25 @@ func (c *CertificateController) Deactivate(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, dto.NewSuccessResponse("certificado desativado com sucesso", nil))
 }
 
+// authorizeCertificate confere o acesso do usuário à filial do certificado. Sem acesso, responde
+// 403 e retorna false
+func (c *CertificateController) authorizeCertificate(ctx *gin.Context, id string) bool {
+	cert, err := c.certificateRepo.FindByID(ctx, id)
+	if err != nil {
+		c.logger.Error("erro ao buscar certificado", "error", err.Error())
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao buscar certificado", err.Error()))
+		return false
+	}
+	return authorizeBranch(ctx, cert.BranchID)
+}
+
 // @Summary Listar certificados expirando
 // @Description Lista os certificados que expirarão em X dias
 // @Tags Certificados
 // @Produce json
 // @Param Authorization header string true "Bearer token"
 // @Param days query int false "Número de dias (padrão: 30)"
+// @Param branch_id query string false "Filtrar por filial"
 // @Success 200 {object} dto.CertificateListResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
@@ -754,6 +792,11 @@ func (c *CertificateController) ListExpiring(ctx *gin.Context) {
 		days = 30
 	}
 
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+
 	// Buscar certificados que expirarão em X dias
 	certificates, err := c.certificateRepo.FindExpiring(ctx, days)
 	if err != nil {
@@ -762,6 +805,17 @@ func (c *CertificateController) ListExpiring(ctx *gin.Context) {
 		return
 	}
 
+	// Sem acesso a todas as filiais, apenas os certificados da filial consultada
+	if branchID != "" {
+		filtered := certificates[:0]
+		for _, cert := range certificates {
+			if cert.BranchID == branchID {
+				filtered = append(filtered, cert)
+			}
+		}
+		certificates = filtered
+	}
+
 	// Retornar a lista de certificados
 	ctx.JSON(http.StatusOK, dto.NewCertificateListResponse(certificates, len(certificates), 1, len(certificates)))
 }
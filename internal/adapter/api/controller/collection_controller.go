@@ -0,0 +1,688 @@
+package controller
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/collection"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/boleto"
+	"github.com/hugohenrick/erp-supermercado/pkg/cnab"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// CollectionController manipula as requisições de cobrança bancária (boletos e arquivos CNAB)
+type CollectionController struct {
+	collectionRepo collection.Repository
+	receivableRepo receivable.Repository
+	customerRepo   customer.Repository
+	logger         logger.Logger
+}
+
+// NewCollectionController cria uma nova instância de CollectionController
+func NewCollectionController(collectionRepo collection.Repository, receivableRepo receivable.Repository, customerRepo customer.Repository, logger logger.Logger) *CollectionController {
+	return &CollectionController{
+		collectionRepo: collectionRepo,
+		receivableRepo: receivableRepo,
+		customerRepo:   customerRepo,
+		logger:         logger,
+	}
+}
+
+// CreateAccount cria uma conta de cobrança
+// @Summary Criar conta de cobrança
+// @Description Cadastra um convênio de cobrança registrada com o banco
+// @Tags Cobrança
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param account body dto.CollectionAccountRequest true "Dados da conta de cobrança"
+// @Success 201 {object} collection.Account
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/accounts [post]
+func (c *CollectionController) CreateAccount(ctx *gin.Context) {
+	var req dto.CollectionAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	a, err := collection.NewAccount(tenantID, req.Name, req.BankCode, req.Agency, req.Account, req.Wallet,
+		req.BeneficiaryName, req.BeneficiaryDocument, cnab.Layout(req.Layout))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar conta de cobrança", err.Error()))
+		return
+	}
+	applyAccountRequest(a, &req)
+
+	if err := c.collectionRepo.CreateAccount(ctx, a); err != nil {
+		c.respondCollectionError(ctx, "erro ao salvar conta de cobrança", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, a)
+}
+
+// UpdateAccount atualiza uma conta de cobrança
+// @Summary Atualizar conta de cobrança
+// @Description Atualiza os dados do convênio; as sequências de nosso número e remessa não são alteradas
+// @Tags Cobrança
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da conta de cobrança"
+// @Param account body dto.CollectionAccountRequest true "Dados da conta de cobrança"
+// @Success 200 {object} collection.Account
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/accounts/{id} [put]
+func (c *CollectionController) UpdateAccount(ctx *gin.Context) {
+	var req dto.CollectionAccountRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	a, err := c.collectionRepo.FindAccountByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar conta de cobrança", err)
+		return
+	}
+
+	a.Name = strings.TrimSpace(req.Name)
+	a.Agency = req.Agency
+	a.Account = req.Account
+	a.Wallet = req.Wallet
+	a.BeneficiaryName = strings.TrimSpace(req.BeneficiaryName)
+	a.BeneficiaryDocument = req.BeneficiaryDocument
+	a.Layout = cnab.Layout(req.Layout)
+	applyAccountRequest(a, &req)
+	a.UpdatedAt = time.Now()
+
+	if err := a.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.collectionRepo.UpdateAccount(ctx, a); err != nil {
+		c.respondCollectionError(ctx, "erro ao atualizar conta de cobrança", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, a)
+}
+
+// GetAccount busca uma conta de cobrança
+// @Summary Obter conta de cobrança
+// @Description Busca uma conta de cobrança pelo ID
+// @Tags Cobrança
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da conta de cobrança"
+// @Success 200 {object} collection.Account
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/accounts/{id} [get]
+func (c *CollectionController) GetAccount(ctx *gin.Context) {
+	a, err := c.collectionRepo.FindAccountByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar conta de cobrança", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, a)
+}
+
+// ListAccounts lista as contas de cobrança
+// @Summary Listar contas de cobrança
+// @Description Lista os convênios de cobrança do tenant
+// @Tags Cobrança
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} collection.Account
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/accounts [get]
+func (c *CollectionController) ListAccounts(ctx *gin.Context) {
+	accounts, err := c.collectionRepo.ListAccounts(ctx)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao listar contas de cobrança", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, accounts)
+}
+
+// IssueBoleto emite um boleto para um título a receber
+// @Summary Emitir boleto
+// @Description Gera nosso número, código de barras e linha digitável para o saldo de um título a receber
+// @Tags Cobrança
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param boleto body dto.BoletoRequest true "Conta de cobrança e título"
+// @Success 201 {object} collection.Boleto
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/boletos [post]
+func (c *CollectionController) IssueBoleto(ctx *gin.Context) {
+	var req dto.BoletoRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	r, err := c.receivableRepo.FindByID(ctx, req.ReceivableID)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar título a receber", err)
+		return
+	}
+	if !authorizeBranch(ctx, r.BranchID) {
+		return
+	}
+	if !r.IsOpen() {
+		c.respondCollectionError(ctx, "erro ao emitir boleto", collection.ErrReceivableNotOpen)
+		return
+	}
+
+	documentNumber := r.DocumentNumber
+	if r.Installments > 1 {
+		documentNumber = fmt.Sprintf("%s/%d", documentNumber, r.Installment)
+	}
+
+	b, err := c.collectionRepo.CreateBoleto(ctx, req.AccountID, func(a *collection.Account, ourNumber int64) (*collection.Boleto, error) {
+		return collection.NewBoleto(a, r.ID, r.CustomerID, documentNumber, strconv.FormatInt(ourNumber, 10),
+			r.Balance(), time.Now(), r.DueDate)
+	})
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao emitir boleto", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, b)
+}
+
+// GetBoleto busca um boleto
+// @Summary Obter boleto
+// @Description Busca um boleto pelo ID
+// @Tags Cobrança
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do boleto"
+// @Success 200 {object} collection.Boleto
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/boletos/{id} [get]
+func (c *CollectionController) GetBoleto(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	b, err := c.collectionRepo.FindBoletoByID(ctx, id)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar boleto", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, b)
+}
+
+// ListBoletos lista os boletos
+// @Summary Listar boletos
+// @Description Lista os boletos por conta, título, cliente e situação
+// @Tags Cobrança
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param account_id query string false "Filtrar por conta de cobrança"
+// @Param receivable_id query string false "Filtrar por título a receber"
+// @Param customer_id query string false "Filtrar por cliente"
+// @Param status query string false "Filtrar por situação (issued, remitted, registered, rejected, paid, cancelled)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.BoletoListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/boletos [get]
+func (c *CollectionController) ListBoletos(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	filter := collection.BoletoFilter{
+		AccountID:    ctx.Query("account_id"),
+		ReceivableID: ctx.Query("receivable_id"),
+		CustomerID:   ctx.Query("customer_id"),
+		Status:       collection.BoletoStatus(ctx.Query("status")),
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	boletos, err := c.collectionRepo.ListBoletos(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao listar boletos", err)
+		return
+	}
+
+	total, err := c.collectionRepo.CountBoletos(ctx, filter)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao contar boletos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToBoletoListResponse(boletos, total, pagination.Page, pagination.PageSize))
+}
+
+// BoletoPDF gera o PDF de um boleto
+// @Summary Imprimir boleto
+// @Description Gera o PDF do boleto com recibo do pagador e ficha de compensação
+// @Tags Cobrança
+// @Produce application/pdf
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do boleto"
+// @Success 200 {file} file
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/boletos/{id}/pdf [get]
+func (c *CollectionController) BoletoPDF(ctx *gin.Context) {
+	b, err := c.collectionRepo.FindBoletoByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar boleto", err)
+		return
+	}
+
+	a, err := c.collectionRepo.FindAccountByID(ctx, b.AccountID)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar conta de cobrança", err)
+		return
+	}
+
+	payer, err := c.payer(ctx, b.CustomerID)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar pagador", err)
+		return
+	}
+
+	content, err := boleto.RenderPDF(b.Document(a, payer), b.Result())
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao gerar PDF do boleto", err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", fmt.Sprintf("inline; filename=boleto-%s.pdf", b.OurNumber))
+	ctx.Data(http.StatusOK, "application/pdf", content)
+}
+
+// CreateRemittance gera o arquivo de remessa com os boletos pendentes
+// @Summary Gerar remessa
+// @Description Gera o arquivo CNAB 240/400 com todos os boletos emitidos e ainda não enviados da conta
+// @Tags Cobrança
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param remittance body dto.RemittanceRequest true "Conta de cobrança"
+// @Success 201 {object} collection.Remittance
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/remittances [post]
+func (c *CollectionController) CreateRemittance(ctx *gin.Context) {
+	var req dto.RemittanceRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	remittance, err := c.collectionRepo.CreateRemittance(ctx, req.AccountID, func(a *collection.Account, sequence int, boletos []*collection.Boleto) (*collection.Remittance, error) {
+		bank, err := boleto.BankByCode(a.BankCode)
+		if err != nil {
+			return nil, err
+		}
+
+		file := &cnab.Remittance{
+			Layout:      a.Layout,
+			BankCode:    a.BankCode,
+			BankName:    bank.Name(),
+			Sequence:    sequence,
+			GeneratedAt: time.Now(),
+			Company:     a.Company(),
+			Titles:      make([]cnab.Title, 0, len(boletos)),
+		}
+
+		remittance := &collection.Remittance{
+			ID:        uuid.New().String(),
+			AccountID: a.ID,
+			Layout:    a.Layout,
+			Sequence:  sequence,
+			FileName:  fmt.Sprintf("REM_%s_%06d.txt", a.BankCode, sequence),
+			BoletoIDs: make([]string, 0, len(boletos)),
+			CreatedBy: userID,
+			CreatedAt: file.GeneratedAt,
+		}
+
+		for _, b := range boletos {
+			payer, err := c.payer(ctx, b.CustomerID)
+			if err != nil {
+				return nil, fmt.Errorf("pagador do boleto %s: %w", b.OurNumber, err)
+			}
+
+			file.Titles = append(file.Titles, cnab.Title{
+				OurNumber:      b.OurNumber,
+				OurNumberDigit: b.OurNumberDigit,
+				DocumentNumber: b.DocumentNumber,
+				CompanyUse:     b.OurNumber,
+				Amount:         b.Amount,
+				InterestPerDay: a.InterestPerDay(b.Amount),
+				FinePercent:    a.FinePercent,
+				IssueDate:      b.IssueDate,
+				DueDate:        b.DueDate,
+				Payer: cnab.Payer{
+					Name:     payer.Name,
+					Document: payer.Document,
+					Address:  payer.Address,
+					District: payer.District,
+					City:     payer.City,
+					State:    payer.State,
+					ZipCode:  payer.ZipCode,
+				},
+			})
+			remittance.BoletoIDs = append(remittance.BoletoIDs, b.ID)
+			remittance.Amount += b.Amount
+		}
+
+		content, err := cnab.WriteRemittance(file)
+		if err != nil {
+			return nil, err
+		}
+
+		remittance.Content = content
+		remittance.Titles = len(boletos)
+		remittance.Amount = math.Round(remittance.Amount*100) / 100
+		return remittance, nil
+	})
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao gerar remessa", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, remittance)
+}
+
+// ListRemittances lista os arquivos de remessa de uma conta
+// @Summary Listar remessas
+// @Description Lista os arquivos de remessa gerados para a conta de cobrança
+// @Tags Cobrança
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param account_id query string true "ID da conta de cobrança"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {array} collection.Remittance
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/remittances [get]
+func (c *CollectionController) ListRemittances(ctx *gin.Context) {
+	accountID := ctx.Query("account_id")
+	if _, err := uuid.Parse(accountID); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "conta inválida", "informe o account_id"))
+		return
+	}
+
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	remittances, err := c.collectionRepo.ListRemittances(ctx, accountID, pagination.PageSize, offset)
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao listar remessas", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, remittances)
+}
+
+// DownloadRemittance baixa o arquivo de remessa
+// @Summary Baixar remessa
+// @Description Retorna o conteúdo do arquivo de remessa para envio ao banco
+// @Tags Cobrança
+// @Produce text/plain
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da remessa"
+// @Success 200 {file} file
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/remittances/{id}/file [get]
+func (c *CollectionController) DownloadRemittance(ctx *gin.Context) {
+	remittance, err := c.collectionRepo.FindRemittanceByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar remessa", err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", "attachment; filename="+remittance.FileName)
+	ctx.Data(http.StatusOK, "text/plain", remittance.Content)
+}
+
+// ProcessReturn processa o arquivo de retorno do banco
+// @Summary Processar retorno
+// @Description Interpreta o retorno CNAB 240/400, confirma registros, liquida os títulos pagos e informa os registros sem correspondência
+// @Tags Cobrança
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param account_id formData string true "ID da conta de cobrança"
+// @Param file formData file true "Arquivo de retorno"
+// @Success 200 {object} collection.ReturnResult
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /collection/returns [post]
+func (c *CollectionController) ProcessReturn(ctx *gin.Context) {
+	a, err := c.collectionRepo.FindAccountByID(ctx, ctx.PostForm("account_id"))
+	if err != nil {
+		c.respondCollectionError(ctx, "erro ao buscar conta de cobrança", err)
+		return
+	}
+
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo inválido", err.Error()))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+	defer src.Close()
+
+	buffer := bytes.NewBuffer(nil)
+	if _, err := io.Copy(buffer, src); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+
+	ret, err := cnab.ParseReturn(buffer.Bytes())
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo de retorno inválido", err.Error()))
+		return
+	}
+	if ret.BankCode != a.BankCode {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo de retorno inválido",
+			fmt.Sprintf("arquivo do banco %s não pertence à conta do banco %s", ret.BankCode, a.BankCode)))
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	result := collection.NewReturnResult(ret.Layout, len(ret.Entries))
+	for _, entry := range ret.Entries {
+		if entry.CompanyUse == "" {
+			result.Unmatched = append(result.Unmatched, entry)
+			continue
+		}
+
+		b, err := c.collectionRepo.FindBoletoByOurNumber(ctx, a.ID, strings.TrimLeft(entry.CompanyUse, "0"))
+		if err != nil {
+			if errors.Is(err, repository.ErrBoletoNotFound) {
+				result.Unmatched = append(result.Unmatched, entry)
+				continue
+			}
+			result.Failed = append(result.Failed, collection.FailedEntry{Entry: entry, Error: err.Error()})
+			continue
+		}
+
+		switch {
+		case entry.IsSettlement():
+			if err := c.settle(ctx, b, entry, userID); err != nil {
+				result.Failed = append(result.Failed, collection.FailedEntry{Entry: entry, Error: err.Error()})
+				continue
+			}
+			result.Settled = append(result.Settled, collection.SettledEntry{
+				BoletoID:     b.ID,
+				ReceivableID: b.ReceivableID,
+				OurNumber:    b.OurNumber,
+				PaidAmount:   entry.PaidAmount,
+			})
+		case entry.Occurrence == cnab.OccurrenceRegistered, entry.Occurrence == cnab.OccurrenceRejected:
+			b.Status = collection.BoletoRegistered
+			if entry.Occurrence == cnab.OccurrenceRejected {
+				b.Status = collection.BoletoRejected
+			}
+			b.Occurrence = entry.Occurrence
+			b.UpdatedAt = time.Now()
+			if err := c.collectionRepo.UpdateBoletoStatus(ctx, b); err != nil {
+				result.Failed = append(result.Failed, collection.FailedEntry{Entry: entry, Error: err.Error()})
+				continue
+			}
+			if b.Status == collection.BoletoRejected {
+				result.Rejected = append(result.Rejected, b.OurNumber)
+			} else {
+				result.Registered = append(result.Registered, b.OurNumber)
+			}
+		default:
+			result.Ignored = append(result.Ignored, entry)
+		}
+	}
+
+	ctx.JSON(http.StatusOK, result)
+}
+
+// settle baixa o título a receber do boleto liquidado e marca o boleto como pago
+func (c *CollectionController) settle(ctx *gin.Context, b *collection.Boleto, entry cnab.ReturnEntry, userID string) error {
+	if b.IsFinal() {
+		return collection.ErrBoletoAlreadyFinal
+	}
+
+	r, err := c.receivableRepo.FindByID(ctx, b.ReceivableID)
+	if err != nil {
+		return err
+	}
+
+	// O valor pago inclui juros e multa e já vem líquido de descontos
+	principal := math.Round((entry.PaidAmount-entry.Interest+entry.Discount)*100) / 100
+	if principal > r.Balance() {
+		principal = r.Balance()
+	}
+
+	paidAt := entry.CreditedAt
+	if paidAt.IsZero() {
+		paidAt = entry.OccurredAt
+	}
+
+	payment, err := r.Receive(principal, entry.Interest, 0, entry.Discount, paidAt, "boleto",
+		fmt.Sprintf("Liquidação do boleto %s via retorno CNAB", b.PrintedNumber), userID)
+	if err != nil {
+		return err
+	}
+
+	if err := c.receivableRepo.RegisterPayment(ctx, r, payment); err != nil {
+		return err
+	}
+
+	b.Status = collection.BoletoPaid
+	b.Occurrence = entry.Occurrence
+	b.PaidAmount = entry.PaidAmount
+	b.PaidAt = &payment.PaidAt
+	b.UpdatedAt = time.Now()
+	return c.collectionRepo.UpdateBoletoStatus(ctx, b)
+}
+
+// payer monta os dados do pagador a partir do cadastro do cliente
+func (c *CollectionController) payer(ctx *gin.Context, customerID string) (boleto.Party, error) {
+	cust, err := c.customerRepo.FindByID(ctx, customerID)
+	if err != nil {
+		return boleto.Party{}, err
+	}
+
+	party := boleto.Party{Name: cust.Name, Document: cust.Document}
+	addr := cust.GetMainAddress()
+	if addr == nil && len(cust.Addresses) > 0 {
+		addr = &cust.Addresses[0]
+	}
+	if addr != nil {
+		party.Address = strings.TrimSpace(addr.Street + ", " + addr.Number + " " + addr.Complement)
+		party.District = addr.District
+		party.City = addr.City
+		party.State = addr.State
+		party.ZipCode = addr.ZipCode
+	}
+
+	return party, nil
+}
+
+// applyAccountRequest copia os campos opcionais da requisição para a conta
+func applyAccountRequest(a *collection.Account, req *dto.CollectionAccountRequest) {
+	a.AgencyDigit = req.AgencyDigit
+	a.AccountDigit = req.AccountDigit
+	a.Agreement = req.Agreement
+	a.InterestRate = req.InterestRate
+	a.FinePercent = req.FinePercent
+	a.Instructions = req.Instructions
+	if req.Active != nil {
+		a.Active = *req.Active
+	}
+}
+
+// respondCollectionError traduz os erros de cobrança para o status HTTP adequado
+func (c *CollectionController) respondCollectionError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrCollectionAccountNotFound), errors.Is(err, repository.ErrBoletoNotFound),
+		errors.Is(err, repository.ErrRemittanceNotFound), errors.Is(err, repository.ErrReceivableNotFound),
+		errors.Is(err, repository.ErrCustomerNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrBoletoAlreadyIssued), errors.Is(err, collection.ErrReceivableNotOpen),
+		errors.Is(err, collection.ErrBoletoAlreadyFinal):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrNoPendingBoletos), errors.Is(err, collection.ErrAccountInactive),
+		errors.Is(err, boleto.ErrFieldTooLong), errors.Is(err, boleto.ErrInvalidDueDate),
+		errors.Is(err, boleto.ErrInvalidAmount), errors.Is(err, cnab.ErrFieldTooLong),
+		errors.Is(err, cnab.ErrUnsupportedLayout):
+		status = http.StatusUnprocessableEntity
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
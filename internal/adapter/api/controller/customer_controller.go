@@ -2,6 +2,10 @@ package controller
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"net/http"
 	"strconv"
 
@@ -9,21 +13,27 @@ import (
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
 	customerdomain "github.com/hugohenrick/erp-supermercado/internal/domain/customer"
-	pkgbranch "github.com/hugohenrick/erp-supermercado/pkg/branch"
+	"github.com/hugohenrick/erp-supermercado/pkg/cep"
 	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+	"github.com/hugohenrick/erp-supermercado/pkg/spreadsheet"
 	"github.com/hugohenrick/erp-supermercado/pkg/tenant"
 )
 
+// customerExportPageSize é a quantidade de clientes lida do banco por vez na exportação
+const customerExportPageSize = 500
+
 // CustomerController gerencia as requisições relacionadas a clientes
 type CustomerController struct {
 	customerRepo customerdomain.Repository
+	cepProvider  cep.Provider
 	logger       logger.Logger
 }
 
 // NewCustomerController cria uma nova instância de CustomerController
-func NewCustomerController(customerRepo customerdomain.Repository, logger logger.Logger) *CustomerController {
+func NewCustomerController(customerRepo customerdomain.Repository, cepProvider cep.Provider, logger logger.Logger) *CustomerController {
 	return &CustomerController{
 		customerRepo: customerRepo,
+		cepProvider:  cepProvider,
 		logger:       logger,
 	}
 }
@@ -100,10 +110,13 @@ func (c *CustomerController) Create(ctx *gin.Context) {
 			State:           addr.State,
 			ZipCode:         addr.ZipCode,
 			Country:         addr.Country,
+			CityCode:        addr.CityCode,
+			StateCode:       addr.StateCode,
 			AddressType:     addr.AddressType,
 			MainAddress:     false,
 			DeliveryAddress: false,
 		}
+		c.completeAddress(ctx, &address)
 		customer.AddAddress(address)
 	}
 
@@ -196,22 +209,8 @@ func (c *CustomerController) List(ctx *gin.Context) {
 
 	tenantID := tenant.GetTenantID(ctx)
 
-	// Obter o branch_id do cabeçalho
-	branchID := ctx.GetHeader("branch-id")
-
-	// Se tiver um branch_id, defina-o explicitamente em todos os lugares possíveis
-	if branchID != "" {
-		// Armazenar no contexto do Gin para utilização posterior
-		ctx.Set("branch_id", branchID)
-
-		// Também definir no contexto da requisição com a chave do pacote branch
-		ctx.Request = ctx.Request.WithContext(
-			context.WithValue(ctx.Request.Context(), pkgbranch.BranchIDKeyType(), branchID))
-
-		// Definir também como chave string simples
-		ctx.Request = ctx.Request.WithContext(
-			context.WithValue(ctx.Request.Context(), "branch_id", branchID))
-	}
+	// Filial ativa, validada na autenticação, usada pelo repositório para filtrar a listagem
+	branchID := ctx.GetString("branch_id")
 
 	// Debug para verificar se o branch_id está sendo recebido corretamente
 	c.logger.Debug("List Customer - Filtrando clientes",
@@ -316,10 +315,13 @@ func (c *CustomerController) Update(ctx *gin.Context) {
 			State:           addr.State,
 			ZipCode:         addr.ZipCode,
 			Country:         addr.Country,
+			CityCode:        addr.CityCode,
+			StateCode:       addr.StateCode,
 			AddressType:     addr.AddressType,
 			MainAddress:     false,
 			DeliveryAddress: false,
 		}
+		c.completeAddress(ctx, &address)
 		customer.AddAddress(address)
 	}
 
@@ -517,3 +519,208 @@ func (c *CustomerController) FindByName(ctx *gin.Context) {
 
 	ctx.JSON(http.StatusOK, dto.ToCustomerListResponse(customers, total, page, size, totalPages))
 }
+
+// Import importa clientes de uma planilha
+// @Summary Importar clientes
+// @Description Importa clientes de uma planilha CSV ou XLSX. Linhas seguidas com o mesmo documento acrescentam endereços e contatos. Com dry_run, apenas valida e devolve os erros de cada linha; caso contrário, grava todos os clientes válidos em uma única transação
+// @Tags customers
+// @Accept multipart/form-data
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param file formData file true "Planilha de clientes (csv, xlsx)"
+// @Param mapping formData string false "Mapeamento de colunas em JSON, ex.: {\"document\": \"CPF/CNPJ\"}"
+// @Param dry_run formData bool false "Apenas validar, sem gravar"
+// @Success 200 {object} dto.CustomerImportResponse
+// @Success 201 {object} dto.CustomerImportResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /customers/import [post]
+func (c *CustomerController) Import(ctx *gin.Context) {
+	file, err := ctx.FormFile("file")
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo inválido", err.Error()))
+		return
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao ler arquivo", err.Error()))
+		return
+	}
+
+	var mapping map[string]string
+	if value := ctx.PostForm("mapping"); value != "" {
+		if err := json.Unmarshal([]byte(value), &mapping); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "mapeamento de colunas inválido", err.Error()))
+			return
+		}
+	}
+
+	dryRun := false
+	if value := ctx.PostForm("dry_run"); value != "" {
+		if dryRun, err = strconv.ParseBool(value); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dry_run inválido", err.Error()))
+			return
+		}
+	}
+
+	format, err := spreadsheet.DetectFormat(file.Filename, data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo inválido", err.Error()))
+		return
+	}
+	rows, err := spreadsheet.Read(format, data)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "arquivo inválido", err.Error()))
+		return
+	}
+
+	tenantID := ctx.GetString("tenant_id")
+	branchID := ctx.GetString("branch_id")
+
+	records, err := customerdomain.ParseImport(rows, mapping, tenantID, branchID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao importar clientes", err.Error()))
+		return
+	}
+
+	customers := make([]*customerdomain.Customer, 0, len(records))
+	for _, record := range records {
+		if !record.Valid() {
+			continue
+		}
+
+		exists, err := c.customerRepo.ExistsByDocument(ctx, tenantID, record.Customer.Document)
+		if err != nil {
+			c.logger.Error("erro ao verificar documento do cliente", "error", err)
+			ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao importar clientes", err.Error()))
+			return
+		}
+		if exists {
+			record.Reject("document", "já existe um cliente com este documento")
+			continue
+		}
+
+		// Apenas a tabela IBGE embarcada: consultar o CEP de cada linha tornaria a importação lenta
+		for i := range record.Customer.Addresses {
+			completeCustomerAddress(ctx, nil, &record.Customer.Addresses[i])
+		}
+		customers = append(customers, record.Customer)
+	}
+
+	if dryRun || len(customers) == 0 {
+		ctx.JSON(http.StatusOK, dto.ToCustomerImportResponse(records, 0, dryRun))
+		return
+	}
+
+	if err := c.customerRepo.CreateMany(ctx, customers); err != nil {
+		if errors.Is(err, repository.ErrCustomerDuplicateKey) {
+			ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "erro ao importar clientes", err.Error()))
+			return
+		}
+		c.logger.Error("erro ao importar clientes no banco de dados", "error", err)
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao importar clientes", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.ToCustomerImportResponse(records, len(customers), false))
+}
+
+// Export exporta todos os clientes com endereços e contatos
+// @Summary Exportar clientes
+// @Description Gera uma planilha CSV ou XLSX com todos os clientes, uma linha por endereço ou contato, no mesmo layout aceito pela importação
+// @Tags customers
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param Authorization header string true "Bearer token"
+// @Param format query string false "Formato (csv, xlsx)" default(csv)
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /customers/export [get]
+func (c *CustomerController) Export(ctx *gin.Context) {
+	format, err := spreadsheet.ParseFormat(ctx.DefaultQuery("format", string(spreadsheet.FormatCSV)))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "formato inválido", err.Error()))
+		return
+	}
+
+	tenantID := ctx.GetString("tenant_id")
+
+	// A primeira página é lida antes de iniciar a resposta para que falhas ainda virem JSON
+	customers, err := c.customerRepo.List(ctx, tenantID, customerExportPageSize, 0)
+	if err != nil {
+		c.logger.Error("erro ao listar clientes", "error", err)
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao exportar clientes", err.Error()))
+		return
+	}
+
+	ctx.Header("Content-Type", spreadsheet.ContentType(format))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="clientes.%s"`, format))
+	ctx.Status(http.StatusOK)
+
+	writer, err := spreadsheet.NewWriter(format, ctx.Writer)
+	if err == nil {
+		err = writer.Write(customerdomain.TransferColumns)
+	}
+	for offset := 0; err == nil && len(customers) > 0; {
+		for _, customer := range customers {
+			for _, row := range customerdomain.ExportRows(customer) {
+				if err = writer.Write(row); err != nil {
+					break
+				}
+			}
+		}
+		if err != nil || len(customers) < customerExportPageSize {
+			break
+		}
+		offset += customerExportPageSize
+		customers, err = c.customerRepo.List(ctx, tenantID, customerExportPageSize, offset)
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// A resposta já começou a ser enviada; resta registrar a falha
+		c.logger.Error("erro ao exportar clientes", "error", err)
+	}
+}
+
+// completeAddress preenche os códigos IBGE e, pelo CEP, os campos do endereço não informados
+func (c *CustomerController) completeAddress(ctx context.Context, address *customerdomain.Address) {
+	if err := completeCustomerAddress(ctx, c.cepProvider, address); err != nil {
+		c.logger.Warn("não foi possível consultar o CEP do cliente", "zip_code", address.ZipCode, "error", err)
+	}
+}
+
+// completeCustomerAddress completa o endereço pelo provedor informado; sem provedor, apenas os
+// códigos IBGE da tabela embarcada são preenchidos
+func completeCustomerAddress(ctx context.Context, provider cep.Provider, address *customerdomain.Address) error {
+	a := cep.Address{
+		ZipCode:   address.ZipCode,
+		Street:    address.Street,
+		District:  address.District,
+		City:      address.City,
+		State:     address.State,
+		CityCode:  address.CityCode,
+		StateCode: address.StateCode,
+	}
+	err := cep.Complete(ctx, provider, &a)
+
+	address.Street, address.District = a.Street, a.District
+	address.City, address.State = a.City, a.State
+	address.CityCode, address.StateCode = a.CityCode, a.StateCode
+	return err
+}
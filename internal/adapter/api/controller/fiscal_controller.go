@@ -1,6 +1,7 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strconv"
@@ -9,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/fiscal"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 	"github.com/hugohenrick/erp-supermercado/pkg/logger"
 )
 
@@ -47,6 +50,9 @@ func (c *FiscalController) Create(ctx *gin.Context) {
 	}
 
 	tenantID := ctx.GetString("tenant_id")
+	if !authorizeBranch(ctx, req.BranchID) {
+		return
+	}
 
 	// Criar a configuração fiscal
 	config, err := fiscal.NewConfiguration(tenantID, req.BranchID, req.CertificateID)
@@ -152,6 +158,9 @@ func (c *FiscalController) Get(ctx *gin.Context) {
 		ctx.JSON(statusCode, dto.NewErrorResponse(statusCode, errorMsg, err.Error()))
 		return
 	}
+	if !authorizeBranch(ctx, config.BranchID) {
+		return
+	}
 
 	ctx.JSON(http.StatusOK, dto.NewFiscalConfigResponse(config))
 }
@@ -171,7 +180,10 @@ func (c *FiscalController) Get(ctx *gin.Context) {
 // @Router /fiscal/configs [get]
 func (c *FiscalController) List(ctx *gin.Context) {
 	tenantID := ctx.GetString("tenant_id")
-	branchID := ctx.Query("branch_id")
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
 
 	// Parâmetros de paginação
 	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
@@ -244,6 +256,9 @@ func (c *FiscalController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "configuração fiscal não encontrada", err.Error()))
 		return
 	}
+	if !authorizeBranch(ctx, existingConfig.BranchID) {
+		return
+	}
 
 	// Atualizar certificado se fornecido
 	if req.CertificateID != "" && req.CertificateID != existingConfig.CertificateID {
@@ -348,6 +363,15 @@ func (c *FiscalController) Delete(ctx *gin.Context) {
 		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "configuração fiscal não encontrada", fmt.Sprintf("configuração fiscal com ID %s não existe", id)))
 		return
 	}
+	config, err := c.fiscalRepo.FindByID(ctx, id)
+	if err != nil {
+		c.logger.Error("erro ao buscar configuração fiscal", "error", err.Error())
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao buscar configuração fiscal", err.Error()))
+		return
+	}
+	if !authorizeBranch(ctx, config.BranchID) {
+		return
+	}
 
 	// Excluir a configuração fiscal
 	if err := c.fiscalRepo.Delete(ctx, id); err != nil {
@@ -368,6 +392,7 @@ func (c *FiscalController) Delete(ctx *gin.Context) {
 // @Success 200 {object} dto.FiscalConfigResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /fiscal/configs/branch/{branch_id} [get]
@@ -377,6 +402,9 @@ func (c *FiscalController) GetByBranch(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "ID da filial não fornecido"))
 		return
 	}
+	if !authorizeBranch(ctx, branchID) {
+		return
+	}
 
 	// Buscar a configuração fiscal para a filial
 	config, err := c.fiscalRepo.FindByBranch(ctx, branchID)
@@ -403,6 +431,8 @@ func (c *FiscalController) GetByBranch(ctx *gin.Context) {
 // @Success 200 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 402 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /fiscal/configs/branch/{branch_id}/increment-nfe [post]
@@ -412,21 +442,20 @@ func (c *FiscalController) IncrementNFeNumber(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "branch_id é obrigatório", ""))
 		return
 	}
+	if !authorizeBranch(ctx, branchID) {
+		return
+	}
 
 	// Buscar configuração da filial
-	config, err := c.fiscalRepo.FindByBranch(ctx, branchID)
-	if err != nil {
+	if _, err := c.fiscalRepo.FindByBranch(ctx, branchID); err != nil {
 		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "configuração fiscal não encontrada", err.Error()))
 		return
 	}
 
-	// Obter e incrementar número
-	nextNumber := config.GetNextNFeNumber()
-
-	// Atualizar no banco
-	err = c.fiscalRepo.UpdateNFeNextNumber(ctx, config.ID, nextNumber)
+	// Obter e incrementar o número em uma única operação, contando o documento no limite do plano
+	nextNumber, err := c.fiscalRepo.GetAndIncrementNFeNumber(ctx, branchID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao atualizar número de NFe", err.Error()))
+		c.respondNumberError(ctx, "erro ao atualizar número de NFe", err)
 		return
 	}
 
@@ -442,6 +471,8 @@ func (c *FiscalController) IncrementNFeNumber(ctx *gin.Context) {
 // @Success 200 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 402 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /fiscal/configs/branch/{branch_id}/increment-nfce [post]
@@ -451,25 +482,92 @@ func (c *FiscalController) IncrementNFCeNumber(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "branch_id é obrigatório", ""))
 		return
 	}
+	if !authorizeBranch(ctx, branchID) {
+		return
+	}
 
 	// Buscar configuração da filial
+	if _, err := c.fiscalRepo.FindByBranch(ctx, branchID); err != nil {
+		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "configuração fiscal não encontrada", err.Error()))
+		return
+	}
+
+	// Obter e incrementar o número em uma única operação, contando o documento no limite do plano
+	nextNumber, err := c.fiscalRepo.GetAndIncrementNFCeNumber(ctx, branchID)
+	if err != nil {
+		c.respondNumberError(ctx, "erro ao atualizar número de NFCe", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"next_number": nextNumber})
+}
+
+// @Summary Inutilizar numeração
+// @Description Inutiliza uma faixa de números de NF-e ou NFC-e já reservados pela filial e não usados em documentos autorizados. Exige a permissão fiscal.cancel
+// @Tags Configurações Fiscais
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id path string true "ID da filial"
+// @Param void body dto.FiscalVoidRequest true "Faixa a inutilizar"
+// @Success 201 {object} fiscal.VoidedRange
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /fiscal/configs/branch/{branch_id}/void-numbers [post]
+func (c *FiscalController) VoidNumbers(ctx *gin.Context) {
+	branchID := ctx.Param("branch_id")
+	if !authorizeBranch(ctx, branchID) {
+		return
+	}
+
+	var req dto.FiscalVoidRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
 	config, err := c.fiscalRepo.FindByBranch(ctx, branchID)
 	if err != nil {
 		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "configuração fiscal não encontrada", err.Error()))
 		return
 	}
 
-	// Obter e incrementar número
-	nextNumber := config.GetNextNFCeNumber()
-
-	// Atualizar no banco
-	err = c.fiscalRepo.UpdateNFCeNextNumber(ctx, config.ID, nextNumber)
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	voided, err := fiscal.NewVoidedRange(config, fiscal.Model(req.Model), req.StartNumber, req.EndNumber, req.Justification, userID)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao atualizar número de NFCe", err.Error()))
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "faixa inválida", err.Error()))
 		return
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{"next_number": nextNumber})
+	if err := c.fiscalRepo.VoidNumbers(ctx, voided); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, fiscal.ErrNumbersAlreadyVoided):
+			status = http.StatusConflict
+		case errors.Is(err, fiscal.ErrVoidRangeNotReserved):
+			status = http.StatusBadRequest
+		default:
+			c.logger.Error("erro ao inutilizar numeração", "error", err.Error())
+		}
+		ctx.JSON(status, dto.NewErrorResponse(status, "erro ao inutilizar numeração", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, voided)
+}
+
+// respondNumberError responde aos erros da numeração: 402 quando o limite mensal de documentos
+// fiscais do plano foi atingido
+func (c *FiscalController) respondNumberError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, tenant.ErrPlanLimitExceeded) {
+		status = http.StatusPaymentRequired
+	}
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
 }
 
 // @Summary Atualizar modo de contingência
@@ -483,6 +581,7 @@ func (c *FiscalController) IncrementNFCeNumber(ctx *gin.Context) {
 // @Success 200 {object} dto.SuccessResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /fiscal/configs/branch/{branch_id}/contingency [post]
@@ -492,6 +591,9 @@ func (c *FiscalController) UpdateContingency(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "branch_id é obrigatório", ""))
 		return
 	}
+	if !authorizeBranch(ctx, branchID) {
+		return
+	}
 
 	// Buscar configuração da filial
 	config, err := c.fiscalRepo.FindByBranch(ctx, branchID)
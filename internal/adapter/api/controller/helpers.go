@@ -0,0 +1,94 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// errCustomerIdentification ocorre quando o cliente não é informado por ID nem por documento
+var errCustomerIdentification = errors.New("informe customer_id ou document do cliente")
+
+// resolveBranchID retorna a filial da operação: a informada explicitamente, se o usuário tiver
+// acesso a ela, ou a filial ativa validada na autenticação. Nunca usa o cabeçalho branch-id sem
+// validação. Sem acesso, responde 403 e retorna false
+func resolveBranchID(ctx *gin.Context, explicit string) (string, bool) {
+	branchID, err := auth.AuthorizeBranch(ctx, explicit)
+	if err != nil {
+		if errors.Is(err, user.ErrNoBranchAccess) {
+			ctx.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Acesso negado à filial", err.Error()))
+			return "", false
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao verificar acesso à filial", err.Error()))
+		return "", false
+	}
+	return branchID, true
+}
+
+// authorizeBranch confere o acesso do usuário a uma filial informada no caminho, no corpo ou
+// gravada em um registro. Filial vazia significa registro da empresa, sem filial, e é aceita.
+// Sem acesso, responde 403 e retorna false
+func authorizeBranch(ctx *gin.Context, branchID string) bool {
+	if branchID == "" {
+		return true
+	}
+	_, ok := resolveBranchID(ctx, branchID)
+	return ok
+}
+
+// branchFilter lê o filtro branch_id da query. Sem filtro, o administrador do tenant lista todas as
+// filiais e os demais usuários apenas a filial ativa; com filtro, o acesso do usuário à filial é
+// conferido como em resolveBranchID
+func branchFilter(ctx *gin.Context) (string, bool) {
+	value := ctx.Query("branch_id")
+	if value == "" && auth.HasAllBranches(ctx) {
+		return "", true
+	}
+
+	branchID, ok := resolveBranchID(ctx, value)
+	if ok && branchID == "" {
+		ctx.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Acesso negado à filial", "informe a filial no filtro branch_id ou no cabeçalho branch-id"))
+		return "", false
+	}
+	return branchID, ok
+}
+
+// parsePeriod lê os parâmetros start_date e end_date (YYYY-MM-DD) da query
+func parsePeriod(ctx *gin.Context) (time.Time, time.Time, error) {
+	var startDate, endDate time.Time
+	var err error
+
+	if value := ctx.Query("start_date"); value != "" {
+		if startDate, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+			return startDate, endDate, err
+		}
+	}
+	if value := ctx.Query("end_date"); value != "" {
+		if endDate, err = time.ParseInLocation("2006-01-02", value, time.Local); err != nil {
+			return startDate, endDate, err
+		}
+		endDate = endDate.Add(24*time.Hour - time.Nanosecond)
+	}
+
+	return startDate, endDate, nil
+}
+
+// findCustomer busca o cliente pelo ID ou pelo documento (CPF/CNPJ) informado no caixa
+func findCustomer(ctx *gin.Context, customerRepo customer.Repository, customerID, document string) (*customer.Customer, error) {
+	if customerID != "" {
+		return customerRepo.FindByID(ctx, customerID)
+	}
+	if document == "" {
+		return nil, errCustomerIdentification
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	return customerRepo.FindByDocument(ctx, tenantID, strings.TrimSpace(document))
+}
@@ -0,0 +1,509 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loss"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// LossController manipula as requisições relacionadas a perdas e quebras
+type LossController struct {
+	lossRepo loss.Repository
+	logger   logger.Logger
+}
+
+// NewLossController cria uma nova instância de LossController
+func NewLossController(lossRepo loss.Repository, logger logger.Logger) *LossController {
+	return &LossController{
+		lossRepo: lossRepo,
+		logger:   logger,
+	}
+}
+
+// CreateReason cria um novo motivo de perda
+// @Summary Criar motivo de perda
+// @Description Cria um novo código de motivo de perda para o tenant
+// @Tags Perdas
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param reason body dto.LossReasonRequest true "Dados do motivo"
+// @Success 201 {object} loss.Reason
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/reasons [post]
+func (c *LossController) CreateReason(ctx *gin.Context) {
+	var req dto.LossReasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	reason, err := loss.NewReason(ctx.GetString("tenant_id"), req.Code, req.Description, req.Category, req.RequiresPhoto)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar motivo de perda", err.Error()))
+		return
+	}
+
+	if err := c.lossRepo.CreateReason(ctx, reason); err != nil {
+		if errors.Is(err, repository.ErrLossReasonDuplicate) {
+			ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "motivo de perda já cadastrado", err.Error()))
+			return
+		}
+		c.logger.Error("erro ao salvar motivo de perda", "error", err.Error())
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao salvar motivo de perda", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, reason)
+}
+
+// ListReasons lista os motivos de perda
+// @Summary Listar motivos de perda
+// @Description Lista os motivos de perda do tenant
+// @Tags Perdas
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param active query bool false "Somente motivos ativos"
+// @Success 200 {array} loss.Reason
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/reasons [get]
+func (c *LossController) ListReasons(ctx *gin.Context) {
+	onlyActive := ctx.Query("active") == "true"
+
+	reasons, err := c.lossRepo.ListReasons(ctx, onlyActive)
+	if err != nil {
+		c.logger.Error("erro ao listar motivos de perda", "error", err.Error())
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao listar motivos de perda", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reasons)
+}
+
+// UpdateReason atualiza um motivo de perda
+// @Summary Atualizar motivo de perda
+// @Description Atualiza descrição, categoria, exigência de foto e situação do motivo
+// @Tags Perdas
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do motivo"
+// @Param reason body dto.LossReasonRequest true "Dados do motivo"
+// @Success 200 {object} loss.Reason
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/reasons/{id} [put]
+func (c *LossController) UpdateReason(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	var req dto.LossReasonRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	reason, err := c.lossRepo.FindReasonByID(ctx, id)
+	if err != nil {
+		c.respondLossError(ctx, "erro ao buscar motivo de perda", err)
+		return
+	}
+
+	active := reason.Active
+	if req.Active != nil {
+		active = *req.Active
+	}
+
+	if err := reason.Update(req.Description, req.Category, req.RequiresPhoto, active); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao atualizar motivo de perda", err.Error()))
+		return
+	}
+
+	if err := c.lossRepo.UpdateReason(ctx, reason); err != nil {
+		c.respondLossError(ctx, "erro ao atualizar motivo de perda", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reason)
+}
+
+// Create registra uma nova perda
+// @Summary Registrar perda
+// @Description Registra uma perda/quebra de produtos na filial, pendente de aprovação do gerente
+// @Tags Perdas
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch-id header string false "ID da filial (usado quando branch_id não é informado no corpo)"
+// @Param loss body dto.LossRequest true "Dados da perda"
+// @Success 201 {object} dto.LossResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses [post]
+func (c *LossController) Create(ctx *gin.Context) {
+	var req dto.LossRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+
+	reason, err := c.lossRepo.FindReasonByID(ctx, req.ReasonID)
+	if err != nil {
+		c.respondLossError(ctx, "erro ao buscar motivo de perda", err)
+		return
+	}
+	if !reason.Active {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "motivo de perda inativo", ""))
+		return
+	}
+
+	var occurredAt time.Time
+	if req.OccurredAt != nil {
+		occurredAt = *req.OccurredAt
+	}
+
+	l, err := loss.NewLoss(tenantID, branchID, reason.ID, userID, occurredAt, req.Notes)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao registrar perda", err.Error()))
+		return
+	}
+
+	for _, item := range req.Items {
+		if err := l.AddItem(item.ProductID, item.Quantity, item.Notes); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "item inválido", err.Error()))
+			return
+		}
+	}
+
+	for _, photo := range req.Photos {
+		_ = l.AddPhoto(photo)
+	}
+
+	if err := l.Validate(reason); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "perda inválida", err.Error()))
+		return
+	}
+
+	if err := c.lossRepo.Create(ctx, l); err != nil {
+		c.respondLossError(ctx, "erro ao salvar perda", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.ToLossResponse(l))
+}
+
+// Get busca uma perda pelo ID
+// @Summary Obter perda
+// @Description Busca uma perda pelo ID, incluindo os itens
+// @Tags Perdas
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da perda"
+// @Success 200 {object} dto.LossResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/{id} [get]
+func (c *LossController) Get(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	l, err := c.lossRepo.FindByID(ctx, id)
+	if err != nil {
+		c.respondLossError(ctx, "erro ao buscar perda", err)
+		return
+	}
+	if !authorizeBranch(ctx, l.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToLossResponse(l))
+}
+
+// List lista as perdas com filtros e paginação
+// @Summary Listar perdas
+// @Description Lista as perdas filtrando por filial, motivo, situação e período
+// @Tags Perdas
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param reason_id query string false "Filtrar por motivo"
+// @Param status query string false "Filtrar por situação (pending, approved, rejected)"
+// @Param start_date query string false "Data inicial (YYYY-MM-DD)"
+// @Param end_date query string false "Data final (YYYY-MM-DD)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.LossListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses [get]
+func (c *LossController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", err.Error()))
+		return
+	}
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := loss.ListFilter{
+		BranchID:  branchID,
+		ReasonID:  ctx.Query("reason_id"),
+		Status:    loss.Status(ctx.Query("status")),
+		StartDate: startDate,
+		EndDate:   endDate,
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	losses, err := c.lossRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondLossError(ctx, "erro ao listar perdas", err)
+		return
+	}
+
+	total, err := c.lossRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondLossError(ctx, "erro ao contar perdas", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToLossListResponse(losses, total, pagination.Page, pagination.PageSize))
+}
+
+// AddPhotos anexa fotos a uma perda pendente
+// @Summary Anexar fotos à perda
+// @Description Anexa URLs de fotos a uma perda ainda pendente de aprovação
+// @Tags Perdas
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da perda"
+// @Param photos body dto.LossPhotosRequest true "Fotos"
+// @Success 200 {object} dto.LossResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/{id}/photos [post]
+func (c *LossController) AddPhotos(ctx *gin.Context) {
+	var req dto.LossPhotosRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	l, err := c.lossRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondLossError(ctx, "erro ao buscar perda", err)
+		return
+	}
+	if !authorizeBranch(ctx, l.BranchID) {
+		return
+	}
+
+	for _, photo := range req.Photos {
+		if err := l.AddPhoto(photo); err != nil {
+			c.respondLossError(ctx, "erro ao anexar fotos", err)
+			return
+		}
+	}
+
+	if err := c.lossRepo.UpdatePhotos(ctx, l); err != nil {
+		c.respondLossError(ctx, "erro ao anexar fotos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToLossResponse(l))
+}
+
+// Approve aprova uma perda e baixa o estoque
+// @Summary Aprovar perda
+// @Description Aprova a perda, lançando movimentações de estoque do tipo "loss" pelo custo atual dos produtos
+// @Tags Perdas
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da perda"
+// @Success 200 {object} dto.LossResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/{id}/approve [post]
+func (c *LossController) Approve(ctx *gin.Context) {
+	l, err := c.lossRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondLossError(ctx, "erro ao buscar perda", err)
+		return
+	}
+	if !authorizeBranch(ctx, l.BranchID) {
+		return
+	}
+
+	reason, err := c.lossRepo.FindReasonByID(ctx, l.ReasonID)
+	if err != nil {
+		c.respondLossError(ctx, "erro ao buscar motivo de perda", err)
+		return
+	}
+
+	if err := l.Validate(reason); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "perda inválida", err.Error()))
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	if err := l.Approve(userID); err != nil {
+		c.respondLossError(ctx, "erro ao aprovar perda", err)
+		return
+	}
+
+	if err := c.lossRepo.Approve(ctx, l); err != nil {
+		c.respondLossError(ctx, "erro ao aprovar perda", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToLossResponse(l))
+}
+
+// Reject rejeita uma perda
+// @Summary Rejeitar perda
+// @Description Rejeita a perda sem movimentar o estoque
+// @Tags Perdas
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da perda"
+// @Param body body dto.LossRejectRequest true "Motivo da rejeição"
+// @Success 200 {object} dto.LossResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/{id}/reject [post]
+func (c *LossController) Reject(ctx *gin.Context) {
+	var req dto.LossRejectRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	l, err := c.lossRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondLossError(ctx, "erro ao buscar perda", err)
+		return
+	}
+	if !authorizeBranch(ctx, l.BranchID) {
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	if err := l.Reject(userID, req.Reason); err != nil {
+		c.respondLossError(ctx, "erro ao rejeitar perda", err)
+		return
+	}
+
+	if err := c.lossRepo.Reject(ctx, l); err != nil {
+		c.respondLossError(ctx, "erro ao rejeitar perda", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToLossResponse(l))
+}
+
+// Report gera o relatório de perdas
+// @Summary Relatório de perdas
+// @Description Totaliza as perdas aprovadas por motivo, categoria de produto ou mês, com percentual sobre o valor vendido na filial e no período, somado das movimentações de venda do estoque (nulo quando não houver vendas)
+// @Tags Perdas
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param group_by query string false "Agrupamento: reason, category ou period (padrão: reason)"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param start_date query string false "Data inicial (YYYY-MM-DD, padrão: início do mês)"
+// @Param end_date query string false "Data final (YYYY-MM-DD, padrão: hoje)"
+// @Success 200 {object} loss.Report
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /losses/report [get]
+func (c *LossController) Report(ctx *gin.Context) {
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", err.Error()))
+		return
+	}
+
+	now := time.Now()
+	if startDate.IsZero() {
+		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	}
+	if endDate.IsZero() {
+		endDate = time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	}
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	report, err := c.lossRepo.Report(ctx, loss.ReportFilter{
+		BranchID:  branchID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		GroupBy:   loss.ReportGroup(ctx.Query("group_by")),
+	})
+	if err != nil {
+		c.respondLossError(ctx, "erro ao gerar relatório de perdas", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, report)
+}
+
+// respondLossError traduz os erros de perdas para o status HTTP adequado
+func (c *LossController) respondLossError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrLossNotFound), errors.Is(err, repository.ErrLossReasonNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrLossProductNotFound), errors.Is(err, repository.ErrLossInvalidReportType):
+		status = http.StatusBadRequest
+	case errors.Is(err, loss.ErrNotPending), errors.Is(err, repository.ErrLossAlreadyReviewed):
+		status = http.StatusConflict
+	case errors.Is(err, loss.ErrEmptyRejectionNotes):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
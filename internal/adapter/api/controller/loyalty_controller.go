@@ -0,0 +1,675 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loyalty"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// LoyaltyController manipula as requisições do programa de fidelidade
+type LoyaltyController struct {
+	loyaltyRepo  loyalty.Repository
+	customerRepo customer.Repository
+	logger       logger.Logger
+}
+
+// NewLoyaltyController cria uma nova instância de LoyaltyController
+func NewLoyaltyController(loyaltyRepo loyalty.Repository, customerRepo customer.Repository, logger logger.Logger) *LoyaltyController {
+	return &LoyaltyController{
+		loyaltyRepo:  loyaltyRepo,
+		customerRepo: customerRepo,
+		logger:       logger,
+	}
+}
+
+// GetProgram busca a configuração do programa de fidelidade
+// @Summary Obter programa de fidelidade
+// @Description Busca a pontuação padrão, o valor do ponto, a validade e o mínimo para resgate
+// @Tags Fidelidade
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} loyalty.Program
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/program [get]
+func (c *LoyaltyController) GetProgram(ctx *gin.Context) {
+	p, err := c.loyaltyRepo.GetProgram(ctx)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar programa de fidelidade", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, p)
+}
+
+// SaveProgram configura o programa de fidelidade
+// @Summary Configurar programa de fidelidade
+// @Description Cria ou atualiza a configuração do programa de fidelidade do tenant
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param program body dto.LoyaltyProgramRequest true "Configuração do programa"
+// @Success 200 {object} loyalty.Program
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/program [put]
+func (c *LoyaltyController) SaveProgram(ctx *gin.Context) {
+	var req dto.LoyaltyProgramRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	p, err := loyalty.NewProgram(tenantID, req.Name, req.PointsPerReal, req.PointValue, req.ExpirationDays, req.MinRedeemPoints)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+	if req.Active != nil {
+		p.Active = *req.Active
+	}
+
+	if err := c.loyaltyRepo.SaveProgram(ctx, p); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao salvar programa de fidelidade", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, p)
+}
+
+// CreateRule cria uma regra de pontuação por categoria
+// @Summary Criar regra de pontuação
+// @Description Define os pontos por real de uma categoria, substituindo a pontuação padrão do programa
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param rule body dto.LoyaltyRuleRequest true "Dados da regra"
+// @Success 201 {object} loyalty.Rule
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/rules [post]
+func (c *LoyaltyController) CreateRule(ctx *gin.Context) {
+	var req dto.LoyaltyRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	rule, err := loyalty.NewRule(tenantID, req.CategoryID, req.PointsPerReal)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+
+	if err := c.loyaltyRepo.CreateRule(ctx, rule); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao salvar regra de pontuação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rule)
+}
+
+// UpdateRule atualiza uma regra de pontuação
+// @Summary Atualizar regra de pontuação
+// @Description Atualiza os pontos por real de uma categoria
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da regra"
+// @Param rule body dto.LoyaltyRuleRequest true "Dados da regra"
+// @Success 200 {object} loyalty.Rule
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/rules/{id} [put]
+func (c *LoyaltyController) UpdateRule(ctx *gin.Context) {
+	var req dto.LoyaltyRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	rule, err := c.loyaltyRepo.FindRuleByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar regra de pontuação", err)
+		return
+	}
+
+	rule.CategoryID = req.CategoryID
+	rule.PointsPerReal = req.PointsPerReal
+	if req.Active != nil {
+		rule.Active = *req.Active
+	}
+	rule.UpdatedAt = time.Now()
+
+	if err := rule.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.loyaltyRepo.UpdateRule(ctx, rule); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao atualizar regra de pontuação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rule)
+}
+
+// ListRules lista as regras de pontuação
+// @Summary Listar regras de pontuação
+// @Description Lista as regras de pontuação por categoria
+// @Tags Fidelidade
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} loyalty.Rule
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/rules [get]
+func (c *LoyaltyController) ListRules(ctx *gin.Context) {
+	rules, err := c.loyaltyRepo.ListRules(ctx)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao listar regras de pontuação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+// CreateCampaign cria uma campanha de pontuação
+// @Summary Criar campanha de pontuação
+// @Description Multiplica a pontuação de todas as categorias, ou de uma categoria, durante o período da campanha
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param campaign body dto.LoyaltyCampaignRequest true "Dados da campanha"
+// @Success 201 {object} loyalty.Campaign
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/campaigns [post]
+func (c *LoyaltyController) CreateCampaign(ctx *gin.Context) {
+	var req dto.LoyaltyCampaignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	campaign, err := loyalty.NewCampaign(tenantID, req.Name, req.CategoryID, req.Multiplier, req.StartsAt, req.EndsAt)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+	if req.Active != nil {
+		campaign.Active = *req.Active
+	}
+
+	if err := c.loyaltyRepo.CreateCampaign(ctx, campaign); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao salvar campanha de pontuação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, campaign)
+}
+
+// UpdateCampaign atualiza uma campanha de pontuação
+// @Summary Atualizar campanha de pontuação
+// @Description Atualiza o multiplicador, a categoria e o período da campanha
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da campanha"
+// @Param campaign body dto.LoyaltyCampaignRequest true "Dados da campanha"
+// @Success 200 {object} loyalty.Campaign
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/campaigns/{id} [put]
+func (c *LoyaltyController) UpdateCampaign(ctx *gin.Context) {
+	var req dto.LoyaltyCampaignRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	campaign, err := c.loyaltyRepo.FindCampaignByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar campanha de pontuação", err)
+		return
+	}
+
+	campaign.Name = strings.TrimSpace(req.Name)
+	campaign.CategoryID = req.CategoryID
+	campaign.Multiplier = req.Multiplier
+	campaign.StartsAt = req.StartsAt
+	campaign.EndsAt = req.EndsAt
+	if req.Active != nil {
+		campaign.Active = *req.Active
+	}
+	campaign.UpdatedAt = time.Now()
+
+	if err := campaign.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.loyaltyRepo.UpdateCampaign(ctx, campaign); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao atualizar campanha de pontuação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, campaign)
+}
+
+// ListCampaigns lista as campanhas de pontuação
+// @Summary Listar campanhas de pontuação
+// @Description Lista as campanhas de pontuação. Com current=true, somente as ativas e vigentes agora
+// @Tags Fidelidade
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param current query bool false "Somente campanhas vigentes"
+// @Success 200 {array} loyalty.Campaign
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/campaigns [get]
+func (c *LoyaltyController) ListCampaigns(ctx *gin.Context) {
+	var at *time.Time
+	if ctx.Query("current") == "true" {
+		now := time.Now()
+		at = &now
+	}
+
+	campaigns, err := c.loyaltyRepo.ListCampaigns(ctx, at)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao listar campanhas de pontuação", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, campaigns)
+}
+
+// CreateReward cria um prêmio do catálogo de resgate
+// @Summary Criar prêmio
+// @Description Cadastra um produto que pode ser trocado por pontos no PDV
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param reward body dto.LoyaltyRewardRequest true "Dados do prêmio"
+// @Success 201 {object} loyalty.Reward
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/rewards [post]
+func (c *LoyaltyController) CreateReward(ctx *gin.Context) {
+	var req dto.LoyaltyRewardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	reward, err := loyalty.NewReward(tenantID, req.ProductID, req.Name, req.Points)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+	if req.Active != nil {
+		reward.Active = *req.Active
+	}
+
+	if err := c.loyaltyRepo.CreateReward(ctx, reward); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao salvar prêmio", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, reward)
+}
+
+// UpdateReward atualiza um prêmio
+// @Summary Atualizar prêmio
+// @Description Atualiza o produto, o nome e os pontos necessários para o resgate
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do prêmio"
+// @Param reward body dto.LoyaltyRewardRequest true "Dados do prêmio"
+// @Success 200 {object} loyalty.Reward
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/rewards/{id} [put]
+func (c *LoyaltyController) UpdateReward(ctx *gin.Context) {
+	var req dto.LoyaltyRewardRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	reward, err := c.loyaltyRepo.FindRewardByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar prêmio", err)
+		return
+	}
+
+	reward.ProductID = req.ProductID
+	reward.Name = strings.TrimSpace(req.Name)
+	reward.Points = req.Points
+	if req.Active != nil {
+		reward.Active = *req.Active
+	}
+	reward.UpdatedAt = time.Now()
+
+	if err := reward.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.loyaltyRepo.UpdateReward(ctx, reward); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao atualizar prêmio", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reward)
+}
+
+// ListRewards lista os prêmios
+// @Summary Listar prêmios
+// @Description Lista o catálogo de resgate. Com active=true, somente os prêmios disponíveis no PDV
+// @Tags Fidelidade
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param active query bool false "Somente prêmios ativos"
+// @Success 200 {array} loyalty.Reward
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/rewards [get]
+func (c *LoyaltyController) ListRewards(ctx *gin.Context) {
+	rewards, err := c.loyaltyRepo.ListRewards(ctx, ctx.Query("active") == "true")
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao listar prêmios", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rewards)
+}
+
+// GetBalance consulta o saldo de pontos do cliente
+// @Summary Consultar saldo de pontos
+// @Description Consulta no caixa o saldo de pontos válidos pelo CPF/CNPJ ou ID do cliente, com o valor disponível para pagamento e os pontos a vencer
+// @Tags Fidelidade
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param document query string false "CPF/CNPJ do cliente"
+// @Param customer_id query string false "ID do cliente"
+// @Success 200 {object} loyalty.Balance
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/balance [get]
+func (c *LoyaltyController) GetBalance(ctx *gin.Context) {
+	p, err := c.loyaltyRepo.GetProgram(ctx)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar programa de fidelidade", err)
+		return
+	}
+
+	cust, err := findCustomer(ctx, c.customerRepo, ctx.Query("customer_id"), ctx.Query("document"))
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar cliente", err)
+		return
+	}
+
+	b, err := c.balance(ctx, p, cust)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao consultar saldo de pontos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, b)
+}
+
+// Earn pontua uma venda
+// @Summary Pontuar venda
+// @Description Credita os pontos da venda finalizada no PDV conforme as regras por categoria e campanhas vigentes. Cada venda pontua uma única vez
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param earn body dto.LoyaltyEarnRequest true "Venda e itens"
+// @Success 201 {object} dto.LoyaltyEntryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/earn [post]
+func (c *LoyaltyController) Earn(ctx *gin.Context) {
+	var req dto.LoyaltyEarnRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	p, err := c.loyaltyRepo.GetProgram(ctx)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar programa de fidelidade", err)
+		return
+	}
+
+	cust, err := findCustomer(ctx, c.customerRepo, req.CustomerID, req.Document)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar cliente", err)
+		return
+	}
+
+	rules, err := c.loyaltyRepo.ListRules(ctx)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar regras de pontuação", err)
+		return
+	}
+
+	now := time.Now()
+	campaigns, err := c.loyaltyRepo.ListCampaigns(ctx, &now)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar campanhas de pontuação", err)
+		return
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	entry, err := loyalty.Earn(p, rules, campaigns, cust.ID, branchID, req.SaleID, req.Items, now, userID)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao pontuar venda", err)
+		return
+	}
+
+	if err := c.loyaltyRepo.Earn(ctx, entry); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao pontuar venda", err)
+		return
+	}
+
+	b, err := c.balance(ctx, p, cust)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao consultar saldo de pontos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.LoyaltyEntryResponse{Entry: entry, Balance: b})
+}
+
+// Redeem resgata pontos no PDV
+// @Summary Resgatar pontos
+// @Description Usa pontos como pagamento da venda, retornando o valor em reais a lançar como forma de pagamento, ou troca os pontos por um prêmio. Os pontos que vencem primeiro são usados antes
+// @Tags Fidelidade
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param redeem body dto.LoyaltyRedeemRequest true "Dados do resgate"
+// @Success 201 {object} dto.LoyaltyEntryResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/redeem [post]
+func (c *LoyaltyController) Redeem(ctx *gin.Context) {
+	var req dto.LoyaltyRedeemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	p, err := c.loyaltyRepo.GetProgram(ctx)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar programa de fidelidade", err)
+		return
+	}
+
+	cust, err := findCustomer(ctx, c.customerRepo, req.CustomerID, req.Document)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao buscar cliente", err)
+		return
+	}
+
+	var reward *loyalty.Reward
+	if req.RewardID != "" {
+		if reward, err = c.loyaltyRepo.FindRewardByID(ctx, req.RewardID); err != nil {
+			c.respondLoyaltyError(ctx, "erro ao buscar prêmio", err)
+			return
+		}
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	entry, err := loyalty.Redeem(p, reward, req.Points, cust.ID, branchID, req.SaleID, userID)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao resgatar pontos", err)
+		return
+	}
+
+	if err := c.loyaltyRepo.Redeem(ctx, entry); err != nil {
+		c.respondLoyaltyError(ctx, "erro ao resgatar pontos", err)
+		return
+	}
+
+	b, err := c.balance(ctx, p, cust)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao consultar saldo de pontos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.LoyaltyEntryResponse{Entry: entry, Balance: b})
+}
+
+// ListEntries lista o extrato de pontos do cliente
+// @Summary Extrato de pontos
+// @Description Lista os ganhos, resgates e vencimentos de pontos do cliente, do mais recente para o mais antigo
+// @Tags Fidelidade
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do cliente"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.LoyaltyEntryListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/customers/{id}/entries [get]
+func (c *LoyaltyController) ListEntries(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	customerID := ctx.Param("id")
+	offset := (pagination.Page - 1) * pagination.PageSize
+	entries, err := c.loyaltyRepo.ListEntries(ctx, customerID, pagination.PageSize, offset)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao listar extrato de pontos", err)
+		return
+	}
+
+	total, err := c.loyaltyRepo.CountEntries(ctx, customerID)
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao contar extrato de pontos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToLoyaltyEntryListResponse(entries, total, pagination.Page, pagination.PageSize))
+}
+
+// Expire registra o vencimento dos pontos
+// @Summary Vencer pontos
+// @Description Lança no extrato dos clientes o vencimento dos pontos não usados dentro da validade. Pontos vencidos já não contam no saldo mesmo antes desta rotina
+// @Tags Fidelidade
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} map[string]int
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /loyalty/expire [post]
+func (c *LoyaltyController) Expire(ctx *gin.Context) {
+	expired, err := c.loyaltyRepo.Expire(ctx, time.Now())
+	if err != nil {
+		c.respondLoyaltyError(ctx, "erro ao vencer pontos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, gin.H{"expired": expired})
+}
+
+// balance calcula o saldo do cliente com o valor dos pontos como pagamento
+func (c *LoyaltyController) balance(ctx *gin.Context, p *loyalty.Program, cust *customer.Customer) (*loyalty.Balance, error) {
+	b, err := c.loyaltyRepo.Balance(ctx, cust.ID, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	b.CustomerName = cust.Name
+	b.Value = p.PointsValue(b.Points)
+	return b, nil
+}
+
+// respondLoyaltyError converte erros do programa de fidelidade em respostas HTTP
+func (c *LoyaltyController) respondLoyaltyError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrLoyaltyProgramNotFound), errors.Is(err, repository.ErrLoyaltyRuleNotFound),
+		errors.Is(err, repository.ErrLoyaltyCampaignNotFound), errors.Is(err, repository.ErrLoyaltyRewardNotFound),
+		errors.Is(err, repository.ErrCustomerNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrLoyaltyRuleDuplicated), errors.Is(err, repository.ErrLoyaltySaleAlreadyEarned):
+		status = http.StatusConflict
+	case errors.Is(err, loyalty.ErrProgramInactive), errors.Is(err, loyalty.ErrNoPointsEarned),
+		errors.Is(err, loyalty.ErrBelowMinRedeem), errors.Is(err, loyalty.ErrInsufficientPoints),
+		errors.Is(err, loyalty.ErrRewardInactive):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, errCustomerIdentification), errors.Is(err, loyalty.ErrInvalidItemAmount),
+		errors.Is(err, loyalty.ErrRedeemTargetRequired), errors.Is(err, loyalty.ErrRedeemTargetAmbiguous):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
@@ -9,6 +9,8 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 	"github.com/hugohenrick/erp-supermercado/pkg/domain"
 	"github.com/hugohenrick/erp-supermercado/pkg/logger"
 	"github.com/hugohenrick/erp-supermercado/pkg/mcp"
@@ -31,6 +33,9 @@ func NewMCPController(mcpClient *mcp.MCPClient, customerRepo repository.Customer
 	}
 }
 
+// mcpPermissionDenied é a resposta do assistente a uma ação sem a permissão exigida
+const mcpPermissionDenied = "Você não tem permissão para executar esta ação. Por favor, contate um administrador se precisar de acesso."
+
 type MCPMessageRequest struct {
 	Message string `json:"message" binding:"required"`
 }
@@ -86,6 +91,12 @@ func (c *MCPController) ProcessMessage(ctx *gin.Context) {
 		c.logger.Info("DETECTED CUSTOMER CREATION REQUEST:",
 			"message", message)
 
+		// Mesma permissão do cadastro de clientes pela API
+		if !auth.HasPermission(ctx, rbac.PermCustomerCreate) {
+			ctx.JSON(http.StatusOK, gin.H{"response": mcpPermissionDenied})
+			return
+		}
+
 		// Extract customer data
 		nameRegex := regexp.MustCompile(`(?i)Nome\s*:?\s*([^\r\n]+)`)
 		nameMatch := nameRegex.FindStringSubmatch(message)
@@ -190,6 +201,11 @@ func (c *MCPController) ProcessMessage(ctx *gin.Context) {
 		c.logger.Info("DETECTED CUSTOMER LISTING REQUEST:",
 			"message", message)
 
+		if !auth.HasPermission(ctx, rbac.PermCustomerView) {
+			ctx.JSON(http.StatusOK, gin.H{"response": mcpPermissionDenied})
+			return
+		}
+
 		// Extract search parameters
 		nameSearchRegex := regexp.MustCompile(`(?i)(?:por|com|de|chamado|nome)\s+(?:nome|chamado)?\s*(?::|é|como|igual a)?\s*["']?([^"'\n,]+)["']?`)
 		docSearchRegex := regexp.MustCompile(`(?i)(?:por|com|de)\s+(?:cpf|cnpj|documento)\s*(?::|é|como|igual a)?\s*["']?([^"'\n,]+)["']?`)
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/branch"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/fiscal"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/onboarding"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
+	"github.com/hugohenrick/erp-supermercado/pkg/cep"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// defaultHeadquartersCode é o código da matriz quando a requisição não informa um
+const defaultHeadquartersCode = "MATRIZ"
+
+// OnboardingController manipula o cadastro inicial de tenants
+type OnboardingController struct {
+	onboardingRepo onboarding.Repository
+	cepProvider    cep.Provider
+	logger         logger.Logger
+}
+
+// NewOnboardingController cria uma nova instância de OnboardingController
+func NewOnboardingController(onboardingRepo onboarding.Repository, cepProvider cep.Provider, logger logger.Logger) *OnboardingController {
+	return &OnboardingController{
+		onboardingRepo: onboardingRepo,
+		cepProvider:    cepProvider,
+		logger:         logger,
+	}
+}
+
+// Create faz o cadastro inicial completo de um tenant
+// @Summary Cadastro inicial do tenant
+// @Description Restrito aos operadores da plataforma. Cria em uma única chamada o tenant, o schema, a matriz, o primeiro administrador, a configuração fiscal padrão da matriz (sem certificado, enviado depois em /certificates/upload) e os dados iniciais: categorias de produtos e formas de pagamento. Todos os dados são validados antes de qualquer gravação; se uma etapa falhar, nada fica cadastrado
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param onboarding body dto.OnboardingRequest true "Tenant, matriz e administrador"
+// @Success 201 {object} dto.OnboardingResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /onboarding [post]
+func (c *OnboardingController) Create(ctx *gin.Context) {
+	var request dto.OnboardingRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Requisição inválida", err.Error()))
+		return
+	}
+
+	t, err := tenant.NewTenant(request.Tenant.Name, request.Tenant.Document, request.Tenant.Email, request.Tenant.Phone, request.Tenant.PlanType, request.Tenant.MaxBranches)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados do tenant inválidos", err.Error()))
+		return
+	}
+
+	completeAddressRequest(ctx, c.cepProvider, &request.Branch.Address)
+	code := request.Branch.Code
+	if code == "" {
+		code = defaultHeadquartersCode
+	}
+	b, err := branch.NewBranch(
+		t.ID,
+		request.Branch.Name,
+		code,
+		branch.TypeHeadquarters,
+		request.Branch.Document,
+		branch.Address{
+			Street:     request.Branch.Address.Street,
+			Number:     request.Branch.Address.Number,
+			Complement: request.Branch.Address.Complement,
+			District:   request.Branch.Address.District,
+			City:       request.Branch.Address.City,
+			State:      request.Branch.Address.State,
+			ZipCode:    request.Branch.Address.ZipCode,
+			Country:    request.Branch.Address.Country,
+			CityCode:   request.Branch.Address.CityCode,
+			StateCode:  request.Branch.Address.StateCode,
+		},
+		request.Branch.Phone,
+		request.Branch.Email,
+		true,
+	)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados da matriz inválidos", err.Error()))
+		return
+	}
+
+	now := time.Now()
+	admin := &user.User{
+		ID:        uuid.New().String(),
+		TenantID:  t.ID,
+		Name:      request.Admin.Name,
+		Email:     request.Admin.Email,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := admin.SetPassword(request.Admin.Password); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao processar senha", err.Error()))
+		return
+	}
+
+	o, err := onboarding.New(t, b, admin, fiscal.FiscalEnvironment(request.FiscalEnvironment))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Cadastro inicial inválido", err.Error()))
+		return
+	}
+
+	if err := c.onboardingRepo.Create(ctx, o); err != nil {
+		if errors.Is(err, repository.ErrTenantDuplicateDocument) || errors.Is(err, repository.ErrDuplicateKey) {
+			ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Tenant já existe", "Um tenant com este documento já está cadastrado"))
+			return
+		}
+		c.logger.Error("erro no cadastro inicial do tenant", "document", t.Document, "error", err)
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro no cadastro inicial do tenant", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.ToOnboardingResponse(o))
+}
@@ -0,0 +1,405 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/payable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/supplier"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// PayableController manipula as requisições de contas a pagar
+type PayableController struct {
+	payableRepo  payable.Repository
+	supplierRepo supplier.Repository
+	logger       logger.Logger
+}
+
+// NewPayableController cria uma nova instância de PayableController
+func NewPayableController(payableRepo payable.Repository, supplierRepo supplier.Repository, logger logger.Logger) *PayableController {
+	return &PayableController{
+		payableRepo:  payableRepo,
+		supplierRepo: supplierRepo,
+		logger:       logger,
+	}
+}
+
+// Create cria um título a pagar manual
+// @Summary Criar título a pagar
+// @Description Lança manualmente um título a pagar em parcela única
+// @Tags Contas a Pagar
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param payable body dto.PayableRequest true "Dados do título"
+// @Success 201 {object} dto.PayableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payables [post]
+func (c *PayableController) Create(ctx *gin.Context) {
+	var req dto.PayableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if _, err := c.supplierRepo.FindByID(ctx, req.SupplierID); err != nil {
+		c.respondPayableError(ctx, "erro ao buscar fornecedor", err)
+		return
+	}
+
+	issueDate := req.IssueDate
+	if issueDate.IsZero() {
+		issueDate = time.Now()
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	p, err := payable.NewPayable(tenantID, branchID, req.SupplierID,
+		req.DocumentNumber, req.Description, req.Amount, issueDate, req.DueDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar título a pagar", err.Error()))
+		return
+	}
+	p.CreatedBy = userID
+
+	if err := c.payableRepo.Create(ctx, p); err != nil {
+		c.respondPayableError(ctx, "erro ao salvar título a pagar", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.ToPayableResponse(p))
+}
+
+// GenerateFromPurchase gera os títulos de uma compra recebida
+// @Summary Gerar títulos de compra recebida
+// @Description Gera as parcelas a pagar de uma compra recebida conforme o prazo de pagamento do fornecedor
+// @Tags Contas a Pagar
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param purchase body dto.PurchasePayableRequest true "Dados da compra recebida"
+// @Success 201 {array} dto.PayableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payables/purchases [post]
+func (c *PayableController) GenerateFromPurchase(ctx *gin.Context) {
+	var req dto.PurchasePayableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	s, err := c.supplierRepo.FindByID(ctx, req.SupplierID)
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao buscar fornecedor", err)
+		return
+	}
+
+	days := s.InstallmentDays()
+	if req.PaymentTerm != "" {
+		if days, err = supplier.ParsePaymentTerm(req.PaymentTerm); err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "prazo de pagamento inválido", err.Error()))
+			return
+		}
+	}
+
+	receivedAt := req.ReceivedAt
+	if receivedAt.IsZero() {
+		receivedAt = time.Now()
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	payables, err := payable.GenerateInstallments(tenantID, branchID, s.ID,
+		req.PurchaseID, req.DocumentNumber, req.Total, receivedAt, days)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao gerar títulos da compra", err.Error()))
+		return
+	}
+
+	for _, p := range payables {
+		p.CreatedBy = userID
+	}
+
+	if err := c.payableRepo.Create(ctx, payables...); err != nil {
+		c.respondPayableError(ctx, "erro ao salvar títulos da compra", err)
+		return
+	}
+
+	response := make([]*dto.PayableResponse, len(payables))
+	for i, p := range payables {
+		response[i] = dto.ToPayableResponse(p)
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Get busca um título a pagar pelo ID
+// @Summary Obter título a pagar
+// @Description Busca um título a pagar pelo ID, incluindo as baixas
+// @Tags Contas a Pagar
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do título"
+// @Success 200 {object} dto.PayableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payables/{id} [get]
+func (c *PayableController) Get(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	p, err := c.payableRepo.FindByID(ctx, id)
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao buscar título a pagar", err)
+		return
+	}
+	if !authorizeBranch(ctx, p.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPayableResponse(p))
+}
+
+// List lista os títulos a pagar
+// @Summary Listar títulos a pagar
+// @Description Lista os títulos a pagar por fornecedor, vencimento e situação na filial
+// @Tags Contas a Pagar
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param supplier_id query string false "Filtrar por fornecedor"
+// @Param purchase_id query string false "Filtrar por compra"
+// @Param status query string false "Filtrar por situação (open, partial, paid, cancelled)"
+// @Param overdue query bool false "Somente vencidos"
+// @Param start_date query string false "Vencimento inicial (YYYY-MM-DD)"
+// @Param end_date query string false "Vencimento final (YYYY-MM-DD)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.PayableListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payables [get]
+func (c *PayableController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+
+	filter, err := payableFilterFromQuery(ctx, branchID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", err.Error()))
+		return
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	payables, err := c.payableRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao listar títulos a pagar", err)
+		return
+	}
+
+	total, err := c.payableRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao contar títulos a pagar", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPayableListResponse(payables, total, pagination.Page, pagination.PageSize))
+}
+
+// Calendar retorna o calendário de vencimentos
+// @Summary Calendário de vencimentos
+// @Description Totaliza os títulos a pagar em aberto por dia de vencimento
+// @Tags Contas a Pagar
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param supplier_id query string false "Filtrar por fornecedor"
+// @Param start_date query string false "Vencimento inicial (YYYY-MM-DD, padrão: hoje)"
+// @Param end_date query string false "Vencimento final (YYYY-MM-DD, padrão: 30 dias)"
+// @Success 200 {array} payable.CalendarDay
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payables/calendar [get]
+func (c *PayableController) Calendar(ctx *gin.Context) {
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+
+	filter, err := payableFilterFromQuery(ctx, branchID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", err.Error()))
+		return
+	}
+
+	if filter.DueFrom.IsZero() {
+		now := time.Now()
+		filter.DueFrom = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	}
+	if filter.DueTo.IsZero() {
+		filter.DueTo = filter.DueFrom.AddDate(0, 0, 30)
+	}
+
+	days, err := c.payableRepo.Calendar(ctx, filter)
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao montar calendário de vencimentos", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, days)
+}
+
+// Pay registra uma baixa no título
+// @Summary Pagar título
+// @Description Registra um pagamento parcial ou total com juros, multa e desconto
+// @Tags Contas a Pagar
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do título"
+// @Param payment body dto.PayablePaymentRequest true "Dados do pagamento"
+// @Success 200 {object} dto.PayableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payables/{id}/payments [post]
+func (c *PayableController) Pay(ctx *gin.Context) {
+	var req dto.PayablePaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	p, err := c.payableRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao buscar título a pagar", err)
+		return
+	}
+	if !authorizeBranch(ctx, p.BranchID) {
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	payment, err := p.Pay(req.Amount, req.Interest, req.Fine, req.Discount, req.PaidAt, req.Method, req.Notes, userID)
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao registrar pagamento", err)
+		return
+	}
+
+	if err := c.payableRepo.RegisterPayment(ctx, p, payment); err != nil {
+		c.respondPayableError(ctx, "erro ao registrar pagamento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPayableResponse(p))
+}
+
+// Cancel cancela um título
+// @Summary Cancelar título a pagar
+// @Description Cancela um título que ainda não recebeu pagamentos
+// @Tags Contas a Pagar
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do título"
+// @Param body body dto.PayableCancelRequest true "Motivo do cancelamento"
+// @Success 200 {object} dto.PayableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payables/{id}/cancel [post]
+func (c *PayableController) Cancel(ctx *gin.Context) {
+	var req dto.PayableCancelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	p, err := c.payableRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPayableError(ctx, "erro ao buscar título a pagar", err)
+		return
+	}
+	if !authorizeBranch(ctx, p.BranchID) {
+		return
+	}
+
+	if err := p.Cancel(req.Reason); err != nil {
+		c.respondPayableError(ctx, "erro ao cancelar título a pagar", err)
+		return
+	}
+
+	if err := c.payableRepo.Cancel(ctx, p); err != nil {
+		c.respondPayableError(ctx, "erro ao cancelar título a pagar", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPayableResponse(p))
+}
+
+// respondPayableError traduz os erros de contas a pagar para o status HTTP adequado
+func (c *PayableController) respondPayableError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrPayableNotFound), errors.Is(err, repository.ErrSupplierNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrPurchaseAlreadyGenerated), errors.Is(err, repository.ErrPayableConcurrentUpdate),
+		errors.Is(err, payable.ErrNotOpen), errors.Is(err, payable.ErrHasPayments):
+		status = http.StatusConflict
+	case errors.Is(err, payable.ErrInvalidPayment), errors.Is(err, payable.ErrPaymentExceeds),
+		errors.Is(err, payable.ErrDiscountExceeds), errors.Is(err, payable.ErrEmptyCancelReason):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
+
+// payableFilterFromQuery monta o filtro de títulos a partir da query, na filial já validada
+func payableFilterFromQuery(ctx *gin.Context, branchID string) (payable.ListFilter, error) {
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		return payable.ListFilter{}, err
+	}
+
+	return payable.ListFilter{
+		BranchID:   branchID,
+		SupplierID: ctx.Query("supplier_id"),
+		PurchaseID: ctx.Query("purchase_id"),
+		Status:     payable.Status(ctx.Query("status")),
+		DueFrom:    startDate,
+		DueTo:      endDate,
+		Overdue:    ctx.Query("overdue") == "true",
+	}, nil
+}
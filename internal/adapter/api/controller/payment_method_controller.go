@@ -0,0 +1,282 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/paymentmethod"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// PaymentMethodController manipula as requisições de formas de pagamento
+type PaymentMethodController struct {
+	paymentMethodRepo paymentmethod.Repository
+	logger            logger.Logger
+}
+
+// NewPaymentMethodController cria uma nova instância de PaymentMethodController
+func NewPaymentMethodController(paymentMethodRepo paymentmethod.Repository, logger logger.Logger) *PaymentMethodController {
+	return &PaymentMethodController{
+		paymentMethodRepo: paymentMethodRepo,
+		logger:            logger,
+	}
+}
+
+// Create cria uma forma de pagamento
+// @Summary Criar forma de pagamento
+// @Description Cadastra uma forma de pagamento com o código tPag da NFC-e, parcelas permitidas, troco e exigência de TEF
+// @Tags Formas de Pagamento
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param payment_method body dto.PaymentMethodRequest true "Dados da forma de pagamento"
+// @Success 201 {object} paymentmethod.PaymentMethod
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payment-methods [post]
+func (c *PaymentMethodController) Create(ctx *gin.Context) {
+	var req dto.PaymentMethodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	m := newPaymentMethodFromRequest(tenantID, &req)
+
+	if err := m.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.paymentMethodRepo.Create(ctx, m); err != nil {
+		c.respondPaymentMethodError(ctx, "erro ao salvar forma de pagamento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, m)
+}
+
+// Update atualiza uma forma de pagamento
+// @Summary Atualizar forma de pagamento
+// @Description Atualiza uma forma de pagamento; campos omitidos voltam ao padrão do tipo
+// @Tags Formas de Pagamento
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da forma de pagamento"
+// @Param payment_method body dto.PaymentMethodRequest true "Dados da forma de pagamento"
+// @Success 200 {object} paymentmethod.PaymentMethod
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payment-methods/{id} [put]
+func (c *PaymentMethodController) Update(ctx *gin.Context) {
+	var req dto.PaymentMethodRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	current, err := c.paymentMethodRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPaymentMethodError(ctx, "erro ao buscar forma de pagamento", err)
+		return
+	}
+
+	m := newPaymentMethodFromRequest(current.TenantID, &req)
+	m.ID = current.ID
+	m.CreatedAt = current.CreatedAt
+	if req.Active == nil {
+		m.Active = current.Active
+	}
+
+	if err := m.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.paymentMethodRepo.Update(ctx, m); err != nil {
+		c.respondPaymentMethodError(ctx, "erro ao atualizar forma de pagamento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, m)
+}
+
+// Delete exclui uma forma de pagamento
+// @Summary Excluir forma de pagamento
+// @Description Exclui uma forma de pagamento que não esteja vinculada a clientes; caso contrário, desative-a
+// @Tags Formas de Pagamento
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da forma de pagamento"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payment-methods/{id} [delete]
+func (c *PaymentMethodController) Delete(ctx *gin.Context) {
+	if err := c.paymentMethodRepo.Delete(ctx, ctx.Param("id")); err != nil {
+		c.respondPaymentMethodError(ctx, "erro ao excluir forma de pagamento", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Get busca uma forma de pagamento
+// @Summary Obter forma de pagamento
+// @Description Busca uma forma de pagamento pelo ID
+// @Tags Formas de Pagamento
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da forma de pagamento"
+// @Success 200 {object} paymentmethod.PaymentMethod
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payment-methods/{id} [get]
+func (c *PaymentMethodController) Get(ctx *gin.Context) {
+	m, err := c.paymentMethodRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPaymentMethodError(ctx, "erro ao buscar forma de pagamento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, m)
+}
+
+// List lista as formas de pagamento
+// @Summary Listar formas de pagamento
+// @Description Lista as formas de pagamento; o PDV usa active=true para montar o menu de recebimento
+// @Tags Formas de Pagamento
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param kind query string false "Filtrar por tipo"
+// @Param active query bool false "Filtrar por situação"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.PaymentMethodListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payment-methods [get]
+func (c *PaymentMethodController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	filter := paymentmethod.Filter{Kind: paymentmethod.Kind(ctx.Query("kind"))}
+	if value := ctx.Query("active"); value != "" {
+		active := value == "true"
+		filter.Active = &active
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	methods, err := c.paymentMethodRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondPaymentMethodError(ctx, "erro ao listar formas de pagamento", err)
+		return
+	}
+
+	total, err := c.paymentMethodRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondPaymentMethodError(ctx, "erro ao contar formas de pagamento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPaymentMethodListResponse(methods, total, pagination.Page, pagination.PageSize))
+}
+
+// Check confere os pagamentos de uma venda
+// @Summary Conferir pagamentos da venda
+// @Description Chamado pelo PDV no fechamento da venda. Confere parcelas, troco e cliente de cada pagamento e se a soma quita o total, devolvendo os dados do grupo de pagamento da NFC-e (tPag, indPag, vPag e vTroco)
+// @Tags Formas de Pagamento
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.PaymentCheckRequest true "Pagamentos da venda"
+// @Success 200 {object} dto.PaymentCheckResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /payment-methods/check [post]
+func (c *PaymentMethodController) Check(ctx *gin.Context) {
+	var req dto.PaymentCheckRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	payments := make([]*paymentmethod.Payment, 0, len(req.Payments))
+	for _, item := range req.Payments {
+		m, err := c.paymentMethodRepo.FindByID(ctx, item.PaymentMethodID)
+		if err != nil {
+			c.respondPaymentMethodError(ctx, "erro ao buscar forma de pagamento", err)
+			return
+		}
+
+		payment, err := m.Check(item.Amount, item.Tendered, item.Installments, req.CustomerID)
+		if err != nil {
+			c.respondPaymentMethodError(ctx, "pagamento recusado", err)
+			return
+		}
+		payments = append(payments, payment)
+	}
+
+	change, err := paymentmethod.Settle(req.Total, payments)
+	if err != nil {
+		c.respondPaymentMethodError(ctx, "pagamento recusado", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.PaymentCheckResponse{Payments: payments, Change: change})
+}
+
+// newPaymentMethodFromRequest monta a forma de pagamento a partir dos padrões do tipo e dos campos informados
+func newPaymentMethodFromRequest(tenantID string, req *dto.PaymentMethodRequest) *paymentmethod.PaymentMethod {
+	m := paymentmethod.NewPaymentMethod(tenantID, req.Name, paymentmethod.Kind(req.Kind))
+	if req.TPag != "" {
+		m.TPag = req.TPag
+	}
+	if req.MaxInstallments > 0 {
+		m.MaxInstallments = req.MaxInstallments
+	}
+	if req.ChangeAllowed != nil {
+		m.ChangeAllowed = *req.ChangeAllowed
+	}
+	if req.RequiresTEF != nil {
+		m.RequiresTEF = *req.RequiresTEF
+	}
+	if req.Active != nil {
+		m.Active = *req.Active
+	}
+	return m
+}
+
+// respondPaymentMethodError converte erros de formas de pagamento em respostas HTTP
+func (c *PaymentMethodController) respondPaymentMethodError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrPaymentMethodNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrPaymentMethodDuplicated), errors.Is(err, repository.ErrPaymentMethodInUse):
+		status = http.StatusConflict
+	case errors.Is(err, paymentmethod.ErrInactive), errors.Is(err, paymentmethod.ErrInstallmentsExceeded),
+		errors.Is(err, paymentmethod.ErrChangeNotAllowed), errors.Is(err, paymentmethod.ErrInsufficientTendered),
+		errors.Is(err, paymentmethod.ErrCustomerRequired), errors.Is(err, paymentmethod.ErrPaymentsMismatch):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, paymentmethod.ErrInvalidAmount):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
@@ -0,0 +1,683 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pix"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+	pkgpix "github.com/hugohenrick/erp-supermercado/pkg/pix"
+	pkgtenant "github.com/hugohenrick/erp-supermercado/pkg/tenant"
+)
+
+// PixController manipula as requisições de cobranças Pix
+type PixController struct {
+	pixRepo        pix.Repository
+	receivableRepo receivable.Repository
+	customerRepo   customer.Repository
+	tenants        pkgtenant.TenantValidator
+	simulation     bool // Libera o pagamento simulado do PSP de testes (PIX_SIMULATION_ENABLED)
+	logger         logger.Logger
+
+	// Provedores por tenant, recriados quando a configuração muda
+	mu        sync.Mutex
+	providers map[string]cachedPixProvider
+}
+
+// cachedPixProvider guarda o provedor criado para uma versão da configuração
+type cachedPixProvider struct {
+	updatedAt time.Time
+	provider  pkgpix.Provider
+}
+
+// NewPixController cria uma nova instância de PixController. O webhook público valida o tenant da URL
+// com tenants; simulation libera o pagamento simulado, que nunca deve ficar disponível em produção
+func NewPixController(pixRepo pix.Repository, receivableRepo receivable.Repository, customerRepo customer.Repository, tenants pkgtenant.TenantValidator, simulation bool, logger logger.Logger) *PixController {
+	return &PixController{
+		pixRepo:        pixRepo,
+		receivableRepo: receivableRepo,
+		customerRepo:   customerRepo,
+		tenants:        tenants,
+		simulation:     simulation,
+		logger:         logger,
+		providers:      make(map[string]cachedPixProvider),
+	}
+}
+
+// GetSettings retorna a configuração de Pix
+// @Summary Obter configuração de Pix
+// @Description Retorna a chave, o recebedor e o PSP configurados para o tenant
+// @Tags Pix
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} pix.Settings
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/settings [get]
+func (c *PixController) GetSettings(ctx *gin.Context) {
+	settings, err := c.pixRepo.FindSettings(ctx)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao buscar configuração de Pix", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// SaveSettings grava a configuração de Pix
+// @Summary Configurar Pix
+// @Description Define a chave Pix, os dados do recebedor e o PSP usado nas cobranças dinâmicas
+// @Tags Pix
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param settings body dto.PixSettingsRequest true "Configuração de Pix"
+// @Success 200 {object} pix.Settings
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/settings [put]
+func (c *PixController) SaveSettings(ctx *gin.Context) {
+	var req dto.PixSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	settings := &pix.Settings{TenantID: tenantID}
+	if current, err := c.pixRepo.FindSettings(ctx); err == nil {
+		settings = current
+	} else if !errors.Is(err, repository.ErrPixSettingsNotFound) {
+		c.respondPixError(ctx, "erro ao buscar configuração de Pix", err)
+		return
+	}
+
+	settings.Key = req.Key
+	settings.MerchantName = req.MerchantName
+	settings.MerchantCity = req.MerchantCity
+	settings.PostalCode = req.PostalCode
+	settings.Provider = req.Provider
+	settings.BaseURL = req.BaseURL
+	settings.ClientID = req.ClientID
+	settings.ExpiresIn = req.ExpiresIn
+	if req.ClientSecret != "" {
+		settings.ClientSecret = req.ClientSecret
+	}
+	if req.WebhookSecret != "" {
+		settings.WebhookSecret = req.WebhookSecret
+	}
+	if settings.ExpiresIn == 0 {
+		settings.ExpiresIn = 3600
+	}
+	settings.UpdatedAt = time.Now()
+
+	if err := settings.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.pixRepo.SaveSettings(ctx, settings); err != nil {
+		c.respondPixError(ctx, "erro ao salvar configuração de Pix", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// CreateCharge cria uma cobrança Pix
+// @Summary Criar cobrança Pix
+// @Description Gera o BR Code estático ou dinâmico para uma venda ou para o saldo de um título a receber
+// @Tags Pix
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param charge body dto.PixChargeRequest true "Dados da cobrança"
+// @Success 201 {object} pix.Charge
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/charges [post]
+func (c *PixController) CreateCharge(ctx *gin.Context) {
+	var req dto.PixChargeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	settings, err := c.pixRepo.FindSettings(ctx)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao buscar configuração de Pix", err)
+		return
+	}
+
+	amount := req.Amount
+	var payerName, payerDocument, customerID string
+	switch {
+	case req.ReceivableID != "":
+		r, err := c.receivableRepo.FindByID(ctx, req.ReceivableID)
+		if err != nil {
+			c.respondPixError(ctx, "erro ao buscar título a receber", err)
+			return
+		}
+		if !r.IsOpen() {
+			c.respondPixError(ctx, "erro ao criar cobrança Pix", pix.ErrReceivableNotOpen)
+			return
+		}
+		if amount == 0 {
+			amount = r.Balance()
+		}
+		if math.Round(amount*100) > math.Round(r.Balance()*100) {
+			c.respondPixError(ctx, "erro ao criar cobrança Pix", receivable.ErrPaymentExceeds)
+			return
+		}
+		if req.BranchID == "" {
+			req.BranchID = r.BranchID
+		}
+		customerID = r.CustomerID
+		if cust, err := c.customerRepo.FindByID(ctx, r.CustomerID); err == nil {
+			payerName, payerDocument = cust.Name, cust.Document
+		}
+	case req.SaleID == "":
+		c.respondPixError(ctx, "erro ao criar cobrança Pix", pix.ErrMissingOrigin)
+		return
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	charge, err := pix.NewCharge(tenantID, branchID, pix.ChargeKind(req.Kind), amount, req.Description, userID)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar cobrança Pix", err.Error()))
+		return
+	}
+	charge.SaleID = req.SaleID
+	charge.ReceivableID = req.ReceivableID
+	charge.CustomerID = customerID
+
+	if charge.Kind == pix.KindDynamic {
+		provider, err := c.provider(settings)
+		if err != nil {
+			c.respondPixError(ctx, "erro ao criar cobrança Pix", err)
+			return
+		}
+
+		resp, err := provider.CreateCharge(ctx, &pkgpix.ChargeRequest{
+			TxID:          charge.TxID,
+			Key:           settings.Key,
+			Amount:        charge.Amount,
+			ExpiresIn:     settings.ExpiresIn,
+			PayerName:     payerName,
+			PayerDocument: payerDocument,
+			Description:   charge.Description,
+		})
+		if err != nil {
+			c.respondPixError(ctx, "erro ao criar cobrança no PSP", err)
+			return
+		}
+
+		expiresAt := time.Now().Add(time.Duration(resp.ExpiresIn) * time.Second)
+		charge.Provider = provider.Name()
+		charge.Location = resp.Location
+		charge.BRCode = resp.BRCode
+		charge.ExpiresAt = &expiresAt
+	}
+
+	if charge.BRCode == "" {
+		charge.BRCode, err = charge.Payload(settings).Encode()
+		if err != nil {
+			c.respondPixError(ctx, "erro ao gerar BR Code", err)
+			return
+		}
+	}
+
+	if err := c.pixRepo.Create(ctx, charge); err != nil {
+		c.respondPixError(ctx, "erro ao salvar cobrança Pix", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, charge)
+}
+
+// GetCharge busca uma cobrança Pix
+// @Summary Obter cobrança Pix
+// @Description Busca uma cobrança Pix pelo ID; usado pelo PDV para acompanhar o pagamento
+// @Tags Pix
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da cobrança"
+// @Success 200 {object} pix.Charge
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/charges/{id} [get]
+func (c *PixController) GetCharge(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	charge, err := c.pixRepo.FindByID(ctx, id)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao buscar cobrança Pix", err)
+		return
+	}
+	if !authorizeBranch(ctx, charge.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, charge)
+}
+
+// ListCharges lista as cobranças Pix
+// @Summary Listar cobranças Pix
+// @Description Lista as cobranças Pix por filial, venda, título e situação
+// @Tags Pix
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param sale_id query string false "Filtrar por venda"
+// @Param receivable_id query string false "Filtrar por título a receber"
+// @Param status query string false "Filtrar por situação (active, paid, cancelled)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.PixChargeListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/charges [get]
+func (c *PixController) ListCharges(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := pix.ChargeFilter{
+		BranchID:     branchID,
+		SaleID:       ctx.Query("sale_id"),
+		ReceivableID: ctx.Query("receivable_id"),
+		Status:       pix.ChargeStatus(ctx.Query("status")),
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	charges, err := c.pixRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao listar cobranças Pix", err)
+		return
+	}
+
+	total, err := c.pixRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao contar cobranças Pix", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPixChargeListResponse(charges, total, pagination.Page, pagination.PageSize))
+}
+
+// CancelCharge cancela uma cobrança Pix ainda não paga
+// @Summary Cancelar cobrança Pix
+// @Description Cancela a cobrança e, quando dinâmica, remove-a no PSP
+// @Tags Pix
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da cobrança"
+// @Success 200 {object} pix.Charge
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 502 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/charges/{id}/cancel [post]
+func (c *PixController) CancelCharge(ctx *gin.Context) {
+	charge, err := c.pixRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPixError(ctx, "erro ao buscar cobrança Pix", err)
+		return
+	}
+	if !authorizeBranch(ctx, charge.BranchID) {
+		return
+	}
+
+	if err := charge.Cancel(); err != nil {
+		c.respondPixError(ctx, "erro ao cancelar cobrança Pix", err)
+		return
+	}
+
+	if charge.Kind == pix.KindDynamic {
+		settings, err := c.pixRepo.FindSettings(ctx)
+		if err != nil {
+			c.respondPixError(ctx, "erro ao buscar configuração de Pix", err)
+			return
+		}
+		provider, err := c.provider(settings)
+		if err != nil {
+			c.respondPixError(ctx, "erro ao cancelar cobrança Pix", err)
+			return
+		}
+		if err := provider.CancelCharge(ctx, charge.TxID); err != nil && !errors.Is(err, pkgpix.ErrChargeNotFound) {
+			c.respondPixError(ctx, "erro ao cancelar cobrança no PSP", err)
+			return
+		}
+	}
+
+	if err := c.pixRepo.Cancel(ctx, charge); err != nil {
+		c.respondPixError(ctx, "erro ao cancelar cobrança Pix", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, charge)
+}
+
+// ListNotifications lista as notificações recebidas do PSP
+// @Summary Listar notificações Pix
+// @Description Lista os Pix recebidos via webhook; use status=unmatched para os recebimentos sem cobrança correspondente
+// @Tags Pix
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param status query string false "Filtrar por situação (settled, unmatched, rejected)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {array} pix.Notification
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/notifications [get]
+func (c *PixController) ListNotifications(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	notifications, err := c.pixRepo.ListNotifications(ctx, pix.NotificationStatus(ctx.Query("status")), pagination.PageSize, offset)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao listar notificações Pix", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, notifications)
+}
+
+// Webhook recebe as notificações de Pix do PSP
+// @Summary Webhook Pix
+// @Description Recebe as notificações de Pix do PSP e liquida as cobranças correspondentes; reenvios são ignorados
+// @Tags Pix
+// @Accept json
+// @Produce json
+// @Param tenant_id path string true "ID do tenant"
+// @Success 200 {object} dto.PixWebhookResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/webhook/{tenant_id} [post]
+func (c *PixController) Webhook(ctx *gin.Context) {
+	tenantID := ctx.Param("tenant_id")
+	if _, err := uuid.Parse(tenantID); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "tenant inválido", "formato de ID inválido"))
+		return
+	}
+
+	// A rota é pública: o tenant vem da URL cadastrada no PSP e passa pela mesma validação do
+	// TenantMiddleware, recusando tenants inexistentes, inativos ou excluídos
+	valid, err := c.tenants.ValidateTenant(tenantID)
+	if err != nil {
+		c.logger.Error("erro ao validar tenant do webhook Pix", "tenant_id", tenantID, "error", err.Error())
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao validar tenant", err.Error()))
+		return
+	}
+	if !valid {
+		ctx.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Tenant inválido", "O tenant informado não existe ou está inativo"))
+		return
+	}
+
+	ctx.Set("tenant_id", tenantID)
+	ctx.Request = ctx.Request.WithContext(pkgtenant.SetTenantIDContext(ctx.Request.Context(), tenantID))
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Request.Body, 1<<20))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "notificação inválida", err.Error()))
+		return
+	}
+
+	settings, err := c.pixRepo.FindSettings(ctx)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao buscar configuração de Pix", err)
+		return
+	}
+
+	provider, err := c.provider(settings)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao processar notificação Pix", pix.ErrInvalidWebhookProvider)
+		return
+	}
+
+	notifications, err := provider.ParseWebhook(ctx.Request.Header, body)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao processar notificação Pix", err)
+		return
+	}
+
+	summary, err := c.processNotifications(ctx, notifications)
+	if err != nil {
+		// Erro de infraestrutura: o PSP reenvia e as notificações já gravadas são ignoradas
+		c.respondPixError(ctx, "erro ao processar notificação Pix", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summary)
+}
+
+// SimulatePayment paga uma cobrança no PSP de testes
+// @Summary Simular pagamento Pix
+// @Description Disponível apenas com o provedor fake e PIX_SIMULATION_ENABLED fora de produção: gera a notificação do PSP e a processa como o webhook
+// @Tags Pix
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da cobrança"
+// @Param payment body dto.PixSimulatePaymentRequest false "Valor pago"
+// @Success 200 {object} dto.PixWebhookResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /pix/charges/{id}/simulate-payment [post]
+func (c *PixController) SimulatePayment(ctx *gin.Context) {
+	if !c.simulation {
+		c.respondPixError(ctx, "erro ao simular pagamento", pix.ErrSimulationDisabled)
+		return
+	}
+
+	var req dto.PixSimulatePaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	settings, err := c.pixRepo.FindSettings(ctx)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao buscar configuração de Pix", err)
+		return
+	}
+	if settings.Provider != pix.ProviderFake {
+		c.respondPixError(ctx, "erro ao simular pagamento", pix.ErrProviderNotConfigured)
+		return
+	}
+
+	charge, err := c.pixRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPixError(ctx, "erro ao buscar cobrança Pix", err)
+		return
+	}
+	if !authorizeBranch(ctx, charge.BranchID) {
+		return
+	}
+
+	provider, err := c.provider(settings)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao simular pagamento", err)
+		return
+	}
+
+	amount := req.Amount
+	if amount == 0 {
+		amount = charge.Amount
+	}
+	header, body, err := provider.(*pkgpix.FakeProvider).Pay(charge.TxID, amount)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao simular pagamento", err)
+		return
+	}
+
+	notifications, err := provider.ParseWebhook(header, body)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao simular pagamento", err)
+		return
+	}
+
+	summary, err := c.processNotifications(ctx, notifications)
+	if err != nil {
+		c.respondPixError(ctx, "erro ao processar pagamento simulado", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, summary)
+}
+
+// processNotifications liquida as cobranças das notificações recebidas
+func (c *PixController) processNotifications(ctx context.Context, notifications []pkgpix.Notification) (*dto.PixWebhookResponse, error) {
+	summary := &dto.PixWebhookResponse{Received: len(notifications)}
+
+	for _, n := range notifications {
+		n := n
+		charge, err := c.pixRepo.Settle(ctx, n, func(charge *pix.Charge) (*receivable.Receivable, *receivable.Payment, error) {
+			return c.settleCharge(ctx, charge, n)
+		})
+
+		switch {
+		case err == nil:
+			summary.Settled++
+		case errors.Is(err, pix.ErrNotificationProcessed):
+			summary.Duplicate++
+		case errors.Is(err, repository.ErrPixChargeNotFound):
+			summary.Unmatched++
+			c.logger.Info("pix recebido sem cobrança correspondente", "end_to_end_id", n.EndToEndID, "txid", n.TxID)
+		case charge != nil:
+			// Recusa gravada junto com a notificação para tratamento manual
+			summary.Rejected++
+			c.logger.Error("pix recebido não pôde liquidar a cobrança", "end_to_end_id", n.EndToEndID,
+				"charge_id", charge.ID, "error", err.Error())
+		default:
+			return nil, err
+		}
+	}
+
+	return summary, nil
+}
+
+// settleCharge marca a cobrança como paga e, quando vinculada a um título, gera o recebimento correspondente
+func (c *PixController) settleCharge(ctx context.Context, charge *pix.Charge, n pkgpix.Notification) (*receivable.Receivable, *receivable.Payment, error) {
+	if err := charge.Pay(n); err != nil {
+		return nil, nil, err
+	}
+	if charge.ReceivableID == "" {
+		return nil, nil, nil
+	}
+
+	r, err := c.receivableRepo.FindByID(ctx, charge.ReceivableID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !r.IsOpen() {
+		return nil, nil, pix.ErrReceivableNotOpen
+	}
+
+	amount := charge.Amount
+	if amount > r.Balance() {
+		amount = r.Balance()
+	}
+
+	payment, err := r.Receive(amount, 0, 0, 0, n.PaidAt, "pix", fmt.Sprintf("Pix %s", n.EndToEndID), charge.CreatedBy)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return r, payment, nil
+}
+
+// provider retorna o provedor de PSP da configuração, reaproveitando-o enquanto ela não mudar
+func (c *PixController) provider(settings *pix.Settings) (pkgpix.Provider, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if cached, ok := c.providers[settings.TenantID]; ok && cached.updatedAt.Equal(settings.UpdatedAt) {
+		return cached.provider, nil
+	}
+
+	var provider pkgpix.Provider
+	switch settings.Provider {
+	case pix.ProviderFake:
+		provider = pkgpix.NewFakeProvider(settings.WebhookSecret)
+	case pix.ProviderAPI:
+		provider = pkgpix.NewAPIProvider(pkgpix.APIConfig{
+			BaseURL:       settings.BaseURL,
+			ClientID:      settings.ClientID,
+			ClientSecret:  settings.ClientSecret,
+			WebhookSecret: settings.WebhookSecret,
+		})
+	default:
+		return nil, pix.ErrProviderNotConfigured
+	}
+
+	c.providers[settings.TenantID] = cachedPixProvider{updatedAt: settings.UpdatedAt, provider: provider}
+	return provider, nil
+}
+
+// respondPixError traduz os erros de Pix para o status HTTP adequado
+func (c *PixController) respondPixError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrPixChargeNotFound), errors.Is(err, repository.ErrReceivableNotFound),
+		errors.Is(err, pix.ErrSimulationDisabled):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrPixSettingsNotFound):
+		status = http.StatusUnprocessableEntity
+		err = pix.ErrSettingsNotConfigured
+	case errors.Is(err, pix.ErrChargeNotActive), errors.Is(err, repository.ErrPixConcurrentUpdate),
+		errors.Is(err, pix.ErrReceivableNotOpen):
+		status = http.StatusConflict
+	case errors.Is(err, pix.ErrProviderNotConfigured), errors.Is(err, pix.ErrMissingOrigin),
+		errors.Is(err, pix.ErrInvalidWebhookProvider), errors.Is(err, receivable.ErrPaymentExceeds),
+		errors.Is(err, pkgpix.ErrFieldTooLong), errors.Is(err, pkgpix.ErrEmptyKey),
+		errors.Is(err, pkgpix.ErrEmptyMerchant), errors.Is(err, pkgpix.ErrInvalidAmount),
+		errors.Is(err, pkgpix.ErrWebhookNoSecret):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, pkgpix.ErrInvalidSignature):
+		status = http.StatusUnauthorized
+	case errors.Is(err, pkgpix.ErrInvalidWebhookMsg):
+		status = http.StatusBadRequest
+	case errors.Is(err, pkgpix.ErrProviderRequest), errors.Is(err, pkgpix.ErrChargeNotFound):
+		status = http.StatusBadGateway
+		c.logger.Error(message, "error", err.Error())
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
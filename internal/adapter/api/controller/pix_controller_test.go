@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pix"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+	pkgpix "github.com/hugohenrick/erp-supermercado/pkg/pix"
+)
+
+// settingsPixRepository devolve apenas a configuração de Pix; a liquidação e sua idempotência são
+// testadas contra o banco em repository.TestPixRepositorySettleIdempotency
+type settingsPixRepository struct {
+	pix.Repository
+
+	settings *pix.Settings
+}
+
+func (r *settingsPixRepository) FindSettings(ctx context.Context) (*pix.Settings, error) {
+	return r.settings, nil
+}
+
+// stubTenantValidator responde a validação de tenant do webhook
+type stubTenantValidator struct {
+	active map[string]bool
+	err    error
+}
+
+func (v *stubTenantValidator) ValidateTenant(tenantID string) (bool, error) {
+	return v.active[tenantID], v.err
+}
+
+func TestPixControllerWebhookTenant(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	activeID := uuid.New().String()
+	inactiveID := uuid.New().String()
+	provider := pkgpix.NewFakeProvider("segredo")
+	_, body, err := provider.Pay("txidqualquer", 10)
+	if err != nil {
+		t.Fatalf("Pay() erro inesperado: %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		tenantID   string
+		validator  *stubTenantValidator
+		wantStatus int
+	}{
+		{"ID malformado", "abc", &stubTenantValidator{}, http.StatusBadRequest},
+		{"tenant inexistente", uuid.New().String(), &stubTenantValidator{active: map[string]bool{activeID: true}}, http.StatusForbidden},
+		{"tenant inativo ou excluído", inactiveID, &stubTenantValidator{active: map[string]bool{activeID: true, inactiveID: false}}, http.StatusForbidden},
+		{"falha ao validar tenant", activeID, &stubTenantValidator{err: errors.New("banco indisponível")}, http.StatusInternalServerError},
+		{"tenant ativo com assinatura inválida", activeID, &stubTenantValidator{active: map[string]bool{activeID: true}}, http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &settingsPixRepository{settings: &pix.Settings{TenantID: tt.tenantID, Provider: pix.ProviderFake, WebhookSecret: "segredo", UpdatedAt: time.Now()}}
+			c := NewPixController(repo, nil, nil, tt.validator, false, logger.NewLogger())
+
+			router := gin.New()
+			router.POST("/pix/webhook/:tenant_id", c.Webhook)
+
+			req := httptest.NewRequest(http.MethodPost, "/pix/webhook/"+tt.tenantID, bytes.NewReader(body))
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, esperado %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+		})
+	}
+}
+
+func TestPixControllerSimulatePaymentDisabled(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	repo := &settingsPixRepository{settings: &pix.Settings{Provider: pix.ProviderFake, WebhookSecret: "segredo", UpdatedAt: time.Now()}}
+	c := NewPixController(repo, nil, nil, &stubTenantValidator{}, false, logger.NewLogger())
+
+	router := gin.New()
+	router.POST("/pix/charges/:id/simulate-payment", c.SimulatePayment)
+
+	req := httptest.NewRequest(http.MethodPost, "/pix/charges/"+uuid.New().String()+"/simulate-payment", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, esperado %d: %s", w.Code, http.StatusNotFound, w.Body.String())
+	}
+}
@@ -0,0 +1,208 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/platform"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// PlatformController manipula a autenticação e o cadastro dos operadores da plataforma
+type PlatformController struct {
+	operatorRepo platform.Repository
+	logger       logger.Logger
+}
+
+// NewPlatformController cria uma nova instância de PlatformController
+func NewPlatformController(operatorRepo platform.Repository, logger logger.Logger) *PlatformController {
+	return &PlatformController{
+		operatorRepo: operatorRepo,
+		logger:       logger,
+	}
+}
+
+// Login autentica um operador da plataforma
+// @Summary Login de operador da plataforma
+// @Description Autentica um operador e retorna um token com a audiência da plataforma, aceito apenas nas rotas de gestão dos tenants, planos e consumo
+// @Tags platform
+// @Accept json
+// @Produce json
+// @Param login body dto.PlatformLoginRequest true "Credenciais do operador"
+// @Success 200 {object} dto.PlatformLoginResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /platform/auth/login [post]
+func (c *PlatformController) Login(ctx *gin.Context) {
+	var req dto.PlatformLoginRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Requisição inválida", err.Error()))
+		return
+	}
+
+	o, err := c.operatorRepo.FindByEmail(ctx, req.Email)
+	if err != nil && !errors.Is(err, repository.ErrOperatorNotFound) {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar operador", err.Error()))
+		return
+	}
+	if o == nil || !o.CheckPassword(req.Password) {
+		ctx.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Credenciais inválidas", "Email ou senha incorretos"))
+		return
+	}
+	if !o.IsActive() {
+		ctx.JSON(http.StatusUnauthorized, dto.NewErrorResponse(http.StatusUnauthorized, "Operador inativo", "Entre em contato com o administrador da plataforma"))
+		return
+	}
+
+	jwtService, err := auth.NewJWTService()
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao configurar autenticação", err.Error()))
+		return
+	}
+	token, expiresAt, err := jwtService.GeneratePlatformToken(o)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao gerar token", err.Error()))
+		return
+	}
+
+	if err := c.operatorRepo.UpdateLastLogin(ctx, o.ID); err != nil {
+		// O login continua válido; apenas o registro do acesso falhou
+		c.logger.Error("erro ao registrar login do operador", "operator_id", o.ID, "error", err.Error())
+	}
+
+	ctx.JSON(http.StatusOK, dto.PlatformLoginResponse{
+		Operator:    dto.ToOperatorResponse(o),
+		AccessToken: token,
+		ExpiresAt:   expiresAt,
+	})
+}
+
+// Me retorna o operador autenticado
+// @Summary Operador autenticado
+// @Description Retorna os dados do operador da plataforma dono do token
+// @Tags platform
+// @Produce json
+// @Param Authorization header string true "Bearer token do operador"
+// @Success 200 {object} dto.OperatorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /platform/me [get]
+func (c *PlatformController) Me(ctx *gin.Context) {
+	operatorID, _, _ := auth.GetCurrentOperator(ctx)
+	o, err := c.operatorRepo.FindByID(ctx, operatorID)
+	if err != nil {
+		c.respondOperatorError(ctx, "Erro ao buscar operador", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ToOperatorResponse(o))
+}
+
+// CreateOperator cadastra um operador da plataforma
+// @Summary Cadastrar operador da plataforma
+// @Description Cadastra um operador ativo. A senha deve ter no mínimo 12 caracteres
+// @Tags platform
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token do operador"
+// @Param operator body dto.OperatorRequest true "Dados do operador"
+// @Success 201 {object} dto.OperatorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /platform/operators [post]
+func (c *PlatformController) CreateOperator(ctx *gin.Context) {
+	var req dto.OperatorRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados inválidos", err.Error()))
+		return
+	}
+
+	o, err := platform.NewOperator(req.Name, req.Email, req.Password)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados inválidos", err.Error()))
+		return
+	}
+	if err := c.operatorRepo.Create(ctx, o); err != nil {
+		c.respondOperatorError(ctx, "Erro ao criar operador", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.ToOperatorResponse(o))
+}
+
+// ListOperators lista os operadores da plataforma
+// @Summary Listar operadores da plataforma
+// @Tags platform
+// @Produce json
+// @Param Authorization header string true "Bearer token do operador"
+// @Success 200 {array} dto.OperatorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /platform/operators [get]
+func (c *PlatformController) ListOperators(ctx *gin.Context) {
+	operators, err := c.operatorRepo.List(ctx)
+	if err != nil {
+		c.respondOperatorError(ctx, "Erro ao listar operadores", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ToOperatorResponseList(operators))
+}
+
+// UpdateOperatorStatus ativa ou desativa um operador da plataforma
+// @Summary Ativar ou desativar operador
+// @Description Desativar um operador revoga o acesso imediatamente, inclusive dos tokens já emitidos. O operador não pode desativar a si mesmo
+// @Tags platform
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token do operador"
+// @Param id path string true "ID do operador"
+// @Param status body dto.OperatorStatusRequest true "Novo status"
+// @Success 200 {object} dto.OperatorResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 401 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /platform/operators/{id}/status [patch]
+func (c *PlatformController) UpdateOperatorStatus(ctx *gin.Context) {
+	var req dto.OperatorStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados inválidos", err.Error()))
+		return
+	}
+
+	id := ctx.Param("id")
+	status := platform.Status(req.Status)
+	if operatorID, _, _ := auth.GetCurrentOperator(ctx); id == operatorID && status != platform.StatusActive {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Operação não permitida", "O operador não pode desativar a si mesmo"))
+		return
+	}
+
+	if err := c.operatorRepo.UpdateStatus(ctx, id, status); err != nil {
+		c.respondOperatorError(ctx, "Erro ao atualizar status do operador", err)
+		return
+	}
+	o, err := c.operatorRepo.FindByID(ctx, id)
+	if err != nil {
+		c.respondOperatorError(ctx, "Erro ao buscar operador", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, dto.ToOperatorResponse(o))
+}
+
+// respondOperatorError responde aos erros do repositório de operadores
+func (c *PlatformController) respondOperatorError(ctx *gin.Context, message string, err error) {
+	switch {
+	case errors.Is(err, repository.ErrOperatorNotFound):
+		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Operador não encontrado", err.Error()))
+	case errors.Is(err, repository.ErrOperatorDuplicated):
+		ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Operador já cadastrado", err.Error()))
+	default:
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, message, err.Error()))
+	}
+}
@@ -0,0 +1,308 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pricetable"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// PriceTableController manipula as requisições de tabelas de preços
+type PriceTableController struct {
+	priceTableRepo pricetable.Repository
+	customerRepo   customer.Repository
+	logger         logger.Logger
+}
+
+// NewPriceTableController cria uma nova instância de PriceTableController
+func NewPriceTableController(priceTableRepo pricetable.Repository, customerRepo customer.Repository, logger logger.Logger) *PriceTableController {
+	return &PriceTableController{
+		priceTableRepo: priceTableRepo,
+		customerRepo:   customerRepo,
+		logger:         logger,
+	}
+}
+
+// Create cria uma tabela de preços
+// @Summary Criar tabela de preços
+// @Description Cadastra uma tabela com percentual sobre o preço de venda e/ou preços fixos por produto, vigência e filiais
+// @Tags Tabelas de Preços
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param price_table body dto.PriceTableRequest true "Dados da tabela de preços"
+// @Success 201 {object} pricetable.PriceTable
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /price-tables [post]
+func (c *PriceTableController) Create(ctx *gin.Context) {
+	var req dto.PriceTableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	t := pricetable.NewPriceTable(tenantID, req.Name, req.Percentage, req.ValidFrom)
+	applyPriceTableRequest(t, &req)
+
+	if err := t.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.priceTableRepo.Create(ctx, t); err != nil {
+		c.respondPriceTableError(ctx, "erro ao salvar tabela de preços", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, t)
+}
+
+// Update atualiza uma tabela de preços
+// @Summary Atualizar tabela de preços
+// @Description Atualiza a tabela; a lista de preços fixos enviada substitui a anterior
+// @Tags Tabelas de Preços
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da tabela de preços"
+// @Param price_table body dto.PriceTableRequest true "Dados da tabela de preços"
+// @Success 200 {object} pricetable.PriceTable
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /price-tables/{id} [put]
+func (c *PriceTableController) Update(ctx *gin.Context) {
+	var req dto.PriceTableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	t, err := c.priceTableRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPriceTableError(ctx, "erro ao buscar tabela de preços", err)
+		return
+	}
+
+	t.Name = req.Name
+	t.Percentage = req.Percentage
+	t.ValidFrom = req.ValidFrom
+	t.BranchIDs = []string{}
+	t.CustomerTypes = []customer.CustomerType{}
+	t.Items = []pricetable.Item{}
+	t.UpdatedAt = time.Now()
+	applyPriceTableRequest(t, &req)
+
+	if err := t.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.priceTableRepo.Update(ctx, t); err != nil {
+		c.respondPriceTableError(ctx, "erro ao atualizar tabela de preços", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// Delete exclui uma tabela de preços
+// @Summary Excluir tabela de preços
+// @Description Exclui uma tabela de preços que não esteja vinculada a clientes
+// @Tags Tabelas de Preços
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da tabela de preços"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /price-tables/{id} [delete]
+func (c *PriceTableController) Delete(ctx *gin.Context) {
+	if err := c.priceTableRepo.Delete(ctx, ctx.Param("id")); err != nil {
+		c.respondPriceTableError(ctx, "erro ao excluir tabela de preços", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// Get busca uma tabela de preços
+// @Summary Obter tabela de preços
+// @Description Busca uma tabela de preços pelo ID, com os preços fixos
+// @Tags Tabelas de Preços
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da tabela de preços"
+// @Success 200 {object} pricetable.PriceTable
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /price-tables/{id} [get]
+func (c *PriceTableController) Get(ctx *gin.Context) {
+	t, err := c.priceTableRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPriceTableError(ctx, "erro ao buscar tabela de preços", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// List lista as tabelas de preços
+// @Summary Listar tabelas de preços
+// @Description Lista as tabelas de preços por filial e situação
+// @Tags Tabelas de Preços
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param active query bool false "Filtrar por situação"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.PriceTableListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /price-tables [get]
+func (c *PriceTableController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := pricetable.Filter{BranchID: branchID}
+	if value := ctx.Query("active"); value != "" {
+		active := value == "true"
+		filter.Active = &active
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	tables, err := c.priceTableRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondPriceTableError(ctx, "erro ao listar tabelas de preços", err)
+		return
+	}
+
+	total, err := c.priceTableRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondPriceTableError(ctx, "erro ao contar tabelas de preços", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPriceTableListResponse(tables, total, pagination.Page, pagination.PageSize))
+}
+
+// Resolve calcula os preços dos produtos para o cliente
+// @Summary Resolver preços do cliente
+// @Description Retorna o preço de cada produto para o cliente na filial, usando a tabela própria do cliente ou a tabela padrão do seu tipo (atacado, revenda). Sem cliente, ou sem tabela vigente, vale o preço de venda cadastrado
+// @Tags Tabelas de Preços
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.PriceResolveRequest true "Produtos e cliente"
+// @Success 200 {object} dto.PriceResolveResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /price-tables/resolve [post]
+func (c *PriceTableController) Resolve(ctx *gin.Context) {
+	var req dto.PriceResolveRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	response := dto.PriceResolveResponse{}
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+
+	var table *pricetable.PriceTable
+	if req.CustomerID != "" || req.Document != "" {
+		cust, err := findCustomer(ctx, c.customerRepo, req.CustomerID, req.Document)
+		if err != nil {
+			c.respondPriceTableError(ctx, "erro ao buscar cliente", err)
+			return
+		}
+		response.CustomerID = cust.ID
+
+		table, err = c.priceTableRepo.FindForCustomer(ctx, cust, branchID, time.Now())
+		if err != nil {
+			c.respondPriceTableError(ctx, "erro ao buscar tabela de preços do cliente", err)
+			return
+		}
+		if table != nil {
+			response.PriceTableID = table.ID
+			response.PriceTableName = table.Name
+		}
+	}
+
+	productIDs := make([]string, 0, len(req.Items))
+	for _, item := range req.Items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+
+	basePrices, err := c.priceTableRepo.BasePrices(ctx, productIDs)
+	if err != nil {
+		c.respondPriceTableError(ctx, "erro ao buscar preços dos produtos", err)
+		return
+	}
+
+	items, total, err := pricetable.Resolve(table, req.Items, basePrices)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	response.Items = items
+	response.Total = total
+	ctx.JSON(http.StatusOK, response)
+}
+
+// applyPriceTableRequest copia os dados opcionais informados na requisição
+func applyPriceTableRequest(t *pricetable.PriceTable, req *dto.PriceTableRequest) {
+	if req.BranchIDs != nil {
+		t.BranchIDs = req.BranchIDs
+	}
+	if req.CustomerTypes != nil {
+		t.CustomerTypes = req.CustomerTypes
+	}
+	if req.Items != nil {
+		t.Items = req.Items
+	}
+	t.ValidUntil = req.ValidUntil
+	if req.Active != nil {
+		t.Active = *req.Active
+	}
+}
+
+// respondPriceTableError converte erros de tabelas de preços em respostas HTTP
+func (c *PriceTableController) respondPriceTableError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrPriceTableNotFound), errors.Is(err, repository.ErrCustomerNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrPriceTableInUse):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrPriceProductNotFound):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, errCustomerIdentification):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
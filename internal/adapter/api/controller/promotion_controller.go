@@ -0,0 +1,299 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pricetable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/promotion"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// PromotionController manipula as requisições de promoções e o cálculo de preços no PDV
+type PromotionController struct {
+	promotionRepo  promotion.Repository
+	customerRepo   customer.Repository
+	priceTableRepo pricetable.Repository
+	logger         logger.Logger
+}
+
+// NewPromotionController cria uma nova instância de PromotionController
+func NewPromotionController(promotionRepo promotion.Repository, customerRepo customer.Repository, priceTableRepo pricetable.Repository, logger logger.Logger) *PromotionController {
+	return &PromotionController{
+		promotionRepo:  promotionRepo,
+		customerRepo:   customerRepo,
+		priceTableRepo: priceTableRepo,
+		logger:         logger,
+	}
+}
+
+// Create cria uma promoção
+// @Summary Criar promoção
+// @Description Cadastra uma promoção leve N pague M, desconto progressivo, combo ou preço promocional, com vigência e filiais participantes
+// @Tags Promoções
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param promotion body dto.PromotionRequest true "Dados da promoção"
+// @Success 201 {object} promotion.Promotion
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /promotions [post]
+func (c *PromotionController) Create(ctx *gin.Context) {
+	var req dto.PromotionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	p := promotion.NewPromotion(tenantID, req.Name, promotion.Type(req.Type), req.StartsAt, req.EndsAt)
+	applyPromotionRequest(p, &req)
+
+	if err := p.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.promotionRepo.Create(ctx, p); err != nil {
+		c.respondPromotionError(ctx, "erro ao salvar promoção", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, p)
+}
+
+// Update atualiza uma promoção
+// @Summary Atualizar promoção
+// @Description Atualiza a mecânica, a vigência e as filiais de uma promoção
+// @Tags Promoções
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da promoção"
+// @Param promotion body dto.PromotionRequest true "Dados da promoção"
+// @Success 200 {object} promotion.Promotion
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /promotions/{id} [put]
+func (c *PromotionController) Update(ctx *gin.Context) {
+	var req dto.PromotionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	p, err := c.promotionRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPromotionError(ctx, "erro ao buscar promoção", err)
+		return
+	}
+
+	updated := promotion.NewPromotion(p.TenantID, req.Name, promotion.Type(req.Type), req.StartsAt, req.EndsAt)
+	updated.ID = p.ID
+	updated.Active = p.Active
+	updated.CreatedAt = p.CreatedAt
+	applyPromotionRequest(updated, &req)
+
+	if err := updated.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.promotionRepo.Update(ctx, updated); err != nil {
+		c.respondPromotionError(ctx, "erro ao atualizar promoção", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, updated)
+}
+
+// Get busca uma promoção
+// @Summary Obter promoção
+// @Description Busca uma promoção pelo ID
+// @Tags Promoções
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da promoção"
+// @Success 200 {object} promotion.Promotion
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /promotions/{id} [get]
+func (c *PromotionController) Get(ctx *gin.Context) {
+	p, err := c.promotionRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondPromotionError(ctx, "erro ao buscar promoção", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, p)
+}
+
+// List lista as promoções
+// @Summary Listar promoções
+// @Description Lista as promoções por filial participante, tipo e situação
+// @Tags Promoções
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial participante"
+// @Param type query string false "Filtrar por tipo (take_pay, tiered, combo, price)"
+// @Param active query bool false "Filtrar por situação"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.PromotionListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /promotions [get]
+func (c *PromotionController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := promotion.Filter{
+		BranchID: branchID,
+		Type:     promotion.Type(ctx.Query("type")),
+	}
+	if value := ctx.Query("active"); value != "" {
+		active := value == "true"
+		filter.Active = &active
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	promotions, err := c.promotionRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondPromotionError(ctx, "erro ao listar promoções", err)
+		return
+	}
+
+	total, err := c.promotionRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondPromotionError(ctx, "erro ao contar promoções", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToPromotionListResponse(promotions, total, pagination.Page, pagination.PageSize))
+}
+
+// Evaluate calcula as promoções da venda em andamento
+// @Summary Calcular promoções da venda
+// @Description Chamado pelo PDV a cada item registrado e no total. Com cliente identificado, os preços unitários passam antes pela tabela de preços do cliente (própria ou padrão do seu tipo). Cada unidade recebe no máximo uma promoção; quando várias se sobrepõem, aplica-se a de maior desconto, depois a de maior prioridade e por fim a de menor ID, sempre com o mesmo resultado para a mesma venda
+// @Tags Promoções
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param cart body dto.PromotionEvaluateRequest true "Venda em andamento"
+// @Success 200 {object} dto.PromotionEvaluateResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 403 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /promotions/evaluate [post]
+func (c *PromotionController) Evaluate(ctx *gin.Context) {
+	var req dto.PromotionEvaluateRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	// Desconto manual acima do limite depende de permissão do operador
+	if req.ManualDiscount > promotion.ManualDiscountLimit && !auth.HasPermission(ctx, rbac.PermPDVDiscountAbove10) {
+		ctx.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Acesso negado", "Permissão necessária: "+string(rbac.PermPDVDiscountAbove10)))
+		return
+	}
+
+	response := dto.PromotionEvaluateResponse{}
+	items := req.CartItems()
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	now := time.Now()
+
+	if req.CustomerID != "" || req.Document != "" {
+		// Cliente não encontrado não impede a venda, apenas não recebe os preços do clube
+		cust, err := findCustomer(ctx, c.customerRepo, req.CustomerID, req.Document)
+		switch {
+		case err == nil:
+			response.CustomerID = cust.ID
+			response.Member = cust.IsActive()
+		case !errors.Is(err, repository.ErrCustomerNotFound):
+			c.respondPromotionError(ctx, "erro ao buscar cliente", err)
+			return
+		}
+
+		if cust != nil {
+			table, err := c.priceTableRepo.FindForCustomer(ctx, cust, branchID, now)
+			if err != nil {
+				c.respondPromotionError(ctx, "erro ao buscar tabela de preços do cliente", err)
+				return
+			}
+			if table != nil {
+				response.PriceTableID = table.ID
+				for i := range items {
+					items[i].UnitPrice = table.PriceFor(items[i].ProductID, items[i].UnitPrice)
+				}
+			}
+		}
+	}
+
+	promotions, err := c.promotionRepo.ListApplicable(ctx, branchID, now)
+	if err != nil {
+		c.respondPromotionError(ctx, "erro ao buscar promoções vigentes", err)
+		return
+	}
+
+	cart := promotion.Cart{BranchID: branchID, Member: response.Member, ManualDiscount: req.ManualDiscount, Items: items}
+	result, err := promotion.Evaluate(cart, promotions, now)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	response.Result = result
+	ctx.JSON(http.StatusOK, response)
+}
+
+// applyPromotionRequest copia os parâmetros da mecânica informados na requisição
+func applyPromotionRequest(p *promotion.Promotion, req *dto.PromotionRequest) {
+	if req.BranchIDs != nil {
+		p.BranchIDs = req.BranchIDs
+	}
+	if req.ProductIDs != nil {
+		p.ProductIDs = req.ProductIDs
+	}
+	p.TakeQuantity = req.TakeQuantity
+	p.PayQuantity = req.PayQuantity
+	p.Tiers = req.Tiers
+	p.ComboItems = req.ComboItems
+	p.ComboPrice = req.ComboPrice
+	p.Price = req.Price
+	p.MembersOnly = req.MembersOnly
+	p.Priority = req.Priority
+	if req.Active != nil {
+		p.Active = *req.Active
+	}
+}
+
+// respondPromotionError converte erros de promoções em respostas HTTP
+func (c *PromotionController) respondPromotionError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrPromotionNotFound):
+		status = http.StatusNotFound
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
@@ -0,0 +1,476 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// ReceivableController manipula as requisições de contas a receber
+type ReceivableController struct {
+	receivableRepo receivable.Repository
+	customerRepo   customer.Repository
+	logger         logger.Logger
+}
+
+// NewReceivableController cria uma nova instância de ReceivableController
+func NewReceivableController(receivableRepo receivable.Repository, customerRepo customer.Repository, logger logger.Logger) *ReceivableController {
+	return &ReceivableController{
+		receivableRepo: receivableRepo,
+		customerRepo:   customerRepo,
+		logger:         logger,
+	}
+}
+
+// Create cria um título a receber manual
+// @Summary Criar título a receber
+// @Description Lança manualmente um título a receber em parcela única
+// @Tags Contas a Receber
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param receivable body dto.ReceivableRequest true "Dados do título"
+// @Success 201 {object} dto.ReceivableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables [post]
+func (c *ReceivableController) Create(ctx *gin.Context) {
+	var req dto.ReceivableRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if _, err := c.customerRepo.FindByID(ctx, req.CustomerID); err != nil {
+		c.respondReceivableError(ctx, "erro ao buscar cliente", err)
+		return
+	}
+
+	issueDate := req.IssueDate
+	if issueDate.IsZero() {
+		issueDate = time.Now()
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	r, err := receivable.NewReceivable(tenantID, branchID, req.CustomerID,
+		req.DocumentNumber, req.Description, req.Amount, issueDate, req.DueDate)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar título a receber", err.Error()))
+		return
+	}
+	r.CreatedBy = userID
+
+	if err := c.receivableRepo.Create(ctx, r); err != nil {
+		c.respondReceivableError(ctx, "erro ao salvar título a receber", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dto.ToReceivableResponse(r))
+}
+
+// CreditSale gera os títulos de uma venda a prazo
+// @Summary Registrar venda a prazo
+// @Description Valida o limite de crédito e a situação do cliente e gera as parcelas conforme o prazo de pagamento cadastrado
+// @Tags Contas a Receber
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param sale body dto.CreditSaleRequest true "Dados da venda a prazo"
+// @Success 201 {array} dto.ReceivableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/sales [post]
+func (c *ReceivableController) CreditSale(ctx *gin.Context) {
+	var req dto.CreditSaleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	cust, err := c.customerRepo.FindByID(ctx, req.CustomerID)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao buscar cliente", err)
+		return
+	}
+
+	installments := req.Installments
+	if installments == 0 {
+		installments = 1
+	}
+
+	soldAt := req.SoldAt
+	if soldAt.IsZero() {
+		soldAt = time.Now()
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	userID, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	receivables, err := receivable.GenerateInstallments(tenantID, branchID, cust.ID,
+		req.SaleID, req.DocumentNumber, req.Total, soldAt, installments, cust.PaymentTerm)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao gerar títulos da venda", err.Error()))
+		return
+	}
+
+	for _, r := range receivables {
+		r.CreatedBy = userID
+	}
+
+	// A situação, o limite e a tolerância são lidos na transação que bloqueia o cliente
+	blockAfterDays := receivable.DefaultBlockAfterDays
+	check := func(status customer.Status, balance *receivable.CustomerBalance, settings *receivable.Settings) error {
+		blockAfterDays = settings.BlockAfterDays
+		return receivable.CheckCredit(status, balance, req.Total, blockAfterDays)
+	}
+
+	if err := c.receivableRepo.CreateForSale(ctx, cust.ID, check, receivables...); err != nil {
+		// Cliente com atraso acima da tolerância é bloqueado para as próximas vendas; os demais
+		// inadimplentes ficam com o comando agendado block-overdue
+		if errors.Is(err, receivable.ErrCustomerHasOverdue) {
+			if _, blockErr := c.receivableRepo.BlockOverdueCustomer(ctx, cust.ID, blockAfterDays); blockErr != nil {
+				c.logger.Error("erro ao bloquear cliente inadimplente", "customer_id", cust.ID, "error", blockErr.Error())
+			}
+		}
+		c.respondReceivableError(ctx, "venda a prazo recusada", err)
+		return
+	}
+
+	response := make([]*dto.ReceivableResponse, len(receivables))
+	for i, r := range receivables {
+		response[i] = dto.ToReceivableResponse(r)
+	}
+
+	ctx.JSON(http.StatusCreated, response)
+}
+
+// Get busca um título a receber pelo ID
+// @Summary Obter título a receber
+// @Description Busca um título a receber pelo ID, incluindo os recebimentos
+// @Tags Contas a Receber
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do título"
+// @Success 200 {object} dto.ReceivableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/{id} [get]
+func (c *ReceivableController) Get(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	r, err := c.receivableRepo.FindByID(ctx, id)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao buscar título a receber", err)
+		return
+	}
+	if !authorizeBranch(ctx, r.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToReceivableResponse(r))
+}
+
+// List lista os títulos a receber
+// @Summary Listar títulos a receber
+// @Description Lista os títulos a receber por cliente, vencimento e situação na filial
+// @Tags Contas a Receber
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param customer_id query string false "Filtrar por cliente"
+// @Param sale_id query string false "Filtrar por venda"
+// @Param status query string false "Filtrar por situação (open, partial, paid, cancelled)"
+// @Param overdue query bool false "Somente vencidos"
+// @Param start_date query string false "Vencimento inicial (YYYY-MM-DD)"
+// @Param end_date query string false "Vencimento final (YYYY-MM-DD)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.ReceivableListResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables [get]
+func (c *ReceivableController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", err.Error()))
+		return
+	}
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := receivable.ListFilter{
+		BranchID:   branchID,
+		CustomerID: ctx.Query("customer_id"),
+		SaleID:     ctx.Query("sale_id"),
+		Status:     receivable.Status(ctx.Query("status")),
+		DueFrom:    startDate,
+		DueTo:      endDate,
+		Overdue:    ctx.Query("overdue") == "true",
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	receivables, err := c.receivableRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao listar títulos a receber", err)
+		return
+	}
+
+	total, err := c.receivableRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao contar títulos a receber", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToReceivableListResponse(receivables, total, pagination.Page, pagination.PageSize))
+}
+
+// CustomerBalance retorna a situação de crédito de um cliente
+// @Summary Saldo do cliente
+// @Description Retorna o limite, o saldo em aberto, o saldo vencido e o crédito disponível do cliente
+// @Tags Contas a Receber
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param customer_id path string true "ID do cliente"
+// @Success 200 {object} receivable.CustomerBalance
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/customers/{customer_id}/balance [get]
+func (c *ReceivableController) CustomerBalance(ctx *gin.Context) {
+	customerID := ctx.Param("customer_id")
+	if _, err := uuid.Parse(customerID); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	balance, err := c.receivableRepo.CustomerBalance(ctx, customerID)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao calcular saldo do cliente", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, balance)
+}
+
+// Receive registra um recebimento no título
+// @Summary Receber título
+// @Description Registra um recebimento parcial ou total com juros, multa e desconto
+// @Tags Contas a Receber
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do título"
+// @Param payment body dto.ReceivablePaymentRequest true "Dados do recebimento"
+// @Success 200 {object} dto.ReceivableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/{id}/payments [post]
+func (c *ReceivableController) Receive(ctx *gin.Context) {
+	var req dto.ReceivablePaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	r, err := c.receivableRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao buscar título a receber", err)
+		return
+	}
+	if !authorizeBranch(ctx, r.BranchID) {
+		return
+	}
+
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	payment, err := r.Receive(req.Amount, req.Interest, req.Fine, req.Discount, req.PaidAt, req.Method, req.Notes, userID)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao registrar recebimento", err)
+		return
+	}
+
+	if err := c.receivableRepo.RegisterPayment(ctx, r, payment); err != nil {
+		c.respondReceivableError(ctx, "erro ao registrar recebimento", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToReceivableResponse(r))
+}
+
+// Cancel cancela um título
+// @Summary Cancelar título a receber
+// @Description Cancela um título que ainda não recebeu pagamentos
+// @Tags Contas a Receber
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do título"
+// @Param body body dto.ReceivableCancelRequest true "Motivo do cancelamento"
+// @Success 200 {object} dto.ReceivableResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/{id}/cancel [post]
+func (c *ReceivableController) Cancel(ctx *gin.Context) {
+	var req dto.ReceivableCancelRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	r, err := c.receivableRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao buscar título a receber", err)
+		return
+	}
+	if !authorizeBranch(ctx, r.BranchID) {
+		return
+	}
+
+	if err := r.Cancel(req.Reason); err != nil {
+		c.respondReceivableError(ctx, "erro ao cancelar título a receber", err)
+		return
+	}
+
+	if err := c.receivableRepo.Cancel(ctx, r); err != nil {
+		c.respondReceivableError(ctx, "erro ao cancelar título a receber", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToReceivableResponse(r))
+}
+
+// BlockOverdue bloqueia os clientes inadimplentes
+// @Summary Bloquear clientes inadimplentes
+// @Description Bloqueia os clientes com títulos vencidos há mais dias que a tolerância configurada para o tenant
+// @Tags Contas a Receber
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.BlockOverdueResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/block-overdue [post]
+func (c *ReceivableController) BlockOverdue(ctx *gin.Context) {
+	settings, err := c.receivableRepo.FindSettings(ctx)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao buscar configuração de contas a receber", err)
+		return
+	}
+
+	blocked, err := c.receivableRepo.BlockOverdueCustomers(ctx, settings.BlockAfterDays)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao bloquear clientes inadimplentes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.BlockOverdueResponse{Days: settings.BlockAfterDays, Blocked: blocked})
+}
+
+// GetSettings retorna a configuração de contas a receber
+// @Summary Obter configuração de contas a receber
+// @Description Retorna a tolerância de atraso usada na venda a prazo e no bloqueio de inadimplentes
+// @Tags Contas a Receber
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} receivable.Settings
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/settings [get]
+func (c *ReceivableController) GetSettings(ctx *gin.Context) {
+	settings, err := c.receivableRepo.FindSettings(ctx)
+	if err != nil {
+		c.respondReceivableError(ctx, "erro ao buscar configuração de contas a receber", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// SaveSettings grava a configuração de contas a receber
+// @Summary Configurar contas a receber
+// @Description Define os dias de atraso tolerados antes de recusar vendas a prazo e bloquear o cliente
+// @Tags Contas a Receber
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param settings body dto.ReceivableSettingsRequest true "Configuração de contas a receber"
+// @Success 200 {object} receivable.Settings
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /receivables/settings [put]
+func (c *ReceivableController) SaveSettings(ctx *gin.Context) {
+	var req dto.ReceivableSettingsRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	settings := &receivable.Settings{TenantID: tenantID, BlockAfterDays: *req.BlockAfterDays, UpdatedAt: time.Now()}
+	if err := settings.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.receivableRepo.SaveSettings(ctx, settings); err != nil {
+		c.respondReceivableError(ctx, "erro ao salvar configuração de contas a receber", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// respondReceivableError traduz os erros de contas a receber para o status HTTP adequado
+func (c *ReceivableController) respondReceivableError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrReceivableNotFound), errors.Is(err, repository.ErrCustomerNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrSaleAlreadyGenerated), errors.Is(err, repository.ErrReceivableConcurrentUpdate),
+		errors.Is(err, receivable.ErrNotOpen), errors.Is(err, receivable.ErrHasPayments):
+		status = http.StatusConflict
+	case errors.Is(err, receivable.ErrCustomerBlocked), errors.Is(err, receivable.ErrCustomerInactive),
+		errors.Is(err, receivable.ErrNoCreditLimit), errors.Is(err, receivable.ErrCreditLimitExceeded),
+		errors.Is(err, receivable.ErrCustomerHasOverdue):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, receivable.ErrInvalidPayment), errors.Is(err, receivable.ErrPaymentExceeds),
+		errors.Is(err, receivable.ErrDiscountExceeds), errors.Is(err, receivable.ErrEmptyCancelReason),
+		errors.Is(err, receivable.ErrInvalidBlockAfterDays):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
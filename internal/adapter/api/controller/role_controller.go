@@ -0,0 +1,280 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// RoleController manipula as requisições de perfis de acesso e permissões
+type RoleController struct {
+	roleRepo rbac.Repository
+	logger   logger.Logger
+}
+
+// NewRoleController cria uma nova instância de RoleController
+func NewRoleController(roleRepo rbac.Repository, logger logger.Logger) *RoleController {
+	return &RoleController{
+		roleRepo: roleRepo,
+		logger:   logger,
+	}
+}
+
+// ListPermissions lista o catálogo de permissões
+// @Summary Listar permissões
+// @Description Lista as permissões que podem compor os perfis de acesso
+// @Tags Perfis de acesso
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} rbac.PermissionInfo
+// @Router /permissions [get]
+func (c *RoleController) ListPermissions(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, rbac.Permissions())
+}
+
+// MyPermissions lista as permissões do usuário autenticado
+// @Summary Minhas permissões
+// @Description Lista as permissões efetivas do usuário na filial ativa: as do perfil padrão somadas às dos perfis atribuídos
+// @Tags Perfis de acesso
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {object} dto.MyPermissionsResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /permissions/me [get]
+func (c *RoleController) MyPermissions(ctx *gin.Context) {
+	permissions, err := auth.Permissions(ctx)
+	if err != nil {
+		c.respondRoleError(ctx, "erro ao buscar permissões", err)
+		return
+	}
+
+	userID, _, _, _, role, branchID := auth.GetCurrentUser(ctx)
+	ctx.JSON(http.StatusOK, dto.MyPermissionsResponse{
+		UserID:      userID,
+		Role:        role,
+		BranchID:    branchID,
+		Permissions: permissions.List(),
+	})
+}
+
+// Create cadastra um perfil de acesso
+// @Summary Cadastrar perfil de acesso
+// @Description Cadastra um perfil composto de permissões do catálogo. Os nomes admin, manager e staff são reservados aos perfis padrão
+// @Tags Perfis de acesso
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param role body dto.RoleRequest true "Dados do perfil"
+// @Success 201 {object} rbac.Role
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /roles [post]
+func (c *RoleController) Create(ctx *gin.Context) {
+	var req dto.RoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	role, err := rbac.NewRole(tenantID, req.Name, req.Description, req.Permissions)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.roleRepo.CreateRole(ctx, role); err != nil {
+		c.respondRoleError(ctx, "erro ao salvar perfil", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, role)
+}
+
+// List lista os perfis de acesso do tenant
+// @Summary Listar perfis de acesso
+// @Tags Perfis de acesso
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Success 200 {array} rbac.Role
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /roles [get]
+func (c *RoleController) List(ctx *gin.Context) {
+	roles, err := c.roleRepo.ListRoles(ctx)
+	if err != nil {
+		c.respondRoleError(ctx, "erro ao listar perfis", err)
+		return
+	}
+	if roles == nil {
+		roles = []*rbac.Role{}
+	}
+
+	ctx.JSON(http.StatusOK, roles)
+}
+
+// Get busca um perfil de acesso
+// @Summary Obter perfil de acesso
+// @Tags Perfis de acesso
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do perfil"
+// @Success 200 {object} rbac.Role
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /roles/{id} [get]
+func (c *RoleController) Get(ctx *gin.Context) {
+	role, err := c.roleRepo.FindRole(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondRoleError(ctx, "erro ao buscar perfil", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, role)
+}
+
+// Update altera um perfil de acesso
+// @Summary Alterar perfil de acesso
+// @Description Altera o nome, a descrição e as permissões do perfil. Vale imediatamente para os usuários que o têm
+// @Tags Perfis de acesso
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do perfil"
+// @Param role body dto.RoleRequest true "Dados do perfil"
+// @Success 200 {object} rbac.Role
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /roles/{id} [put]
+func (c *RoleController) Update(ctx *gin.Context) {
+	var req dto.RoleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	role, err := c.roleRepo.FindRole(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondRoleError(ctx, "erro ao buscar perfil", err)
+		return
+	}
+	if err := role.Update(req.Name, req.Description, req.Permissions); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.roleRepo.UpdateRole(ctx, role); err != nil {
+		c.respondRoleError(ctx, "erro ao atualizar perfil", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, role)
+}
+
+// Delete exclui um perfil de acesso
+// @Summary Excluir perfil de acesso
+// @Description Exclui o perfil e remove as atribuições dele aos usuários
+// @Tags Perfis de acesso
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do perfil"
+// @Success 204
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /roles/{id} [delete]
+func (c *RoleController) Delete(ctx *gin.Context) {
+	if err := c.roleRepo.DeleteRole(ctx, ctx.Param("id")); err != nil {
+		c.respondRoleError(ctx, "erro ao excluir perfil", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// GetUserRoles lista os perfis atribuídos a um usuário
+// @Summary Perfis do usuário
+// @Description Lista os perfis atribuídos ao usuário, por filial
+// @Tags Perfis de acesso
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param user_id path string true "ID do usuário"
+// @Success 200 {object} dto.UserRolesResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /roles/users/{user_id} [get]
+func (c *RoleController) GetUserRoles(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	assignments, err := c.roleRepo.ListAssignments(ctx, userID)
+	if err != nil {
+		c.respondRoleError(ctx, "erro ao buscar perfis do usuário", err)
+		return
+	}
+	if assignments == nil {
+		assignments = []*rbac.Assignment{}
+	}
+
+	ctx.JSON(http.StatusOK, dto.UserRolesResponse{UserID: userID, Assignments: assignments})
+}
+
+// SetUserRoles substitui os perfis atribuídos a um usuário
+// @Summary Atribuir perfis ao usuário
+// @Description Substitui todas as atribuições do usuário. Cada perfil vale na filial informada ou, sem filial, em todas. O perfil padrão do usuário continua valendo
+// @Tags Perfis de acesso
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param user_id path string true "ID do usuário"
+// @Param roles body dto.UserRolesRequest true "Perfis por filial"
+// @Success 200 {object} dto.UserRolesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /roles/users/{user_id} [put]
+func (c *RoleController) SetUserRoles(ctx *gin.Context) {
+	var req dto.UserRolesRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	userID := ctx.Param("user_id")
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	assignments := make([]*rbac.Assignment, 0, len(req.Assignments))
+	for _, a := range req.Assignments {
+		assignment, err := rbac.NewAssignment(tenantID, userID, a.RoleID, a.BranchID)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+			return
+		}
+		assignments = append(assignments, assignment)
+	}
+
+	if err := c.roleRepo.ReplaceAssignments(ctx, userID, assignments); err != nil {
+		c.respondRoleError(ctx, "erro ao atribuir perfis", err)
+		return
+	}
+
+	c.GetUserRoles(ctx)
+}
+
+// respondRoleError converte erros de perfis de acesso em respostas HTTP
+func (c *RoleController) respondRoleError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrRoleNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrRoleDuplicated):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrAssignmentReference), errors.Is(err, repository.ErrAssignmentDuplicate):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
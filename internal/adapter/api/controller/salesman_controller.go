@@ -0,0 +1,438 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/salesman"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// errSalesmanRequired ocorre quando a venda não informa vendedor e o cliente não tem vendedor no cadastro
+var errSalesmanRequired = errors.New("informe salesman_id ou um cliente com vendedor cadastrado")
+
+// SalesmanController manipula as requisições de vendedores e comissões
+type SalesmanController struct {
+	salesmanRepo salesman.Repository
+	customerRepo customer.Repository
+	logger       logger.Logger
+}
+
+// NewSalesmanController cria uma nova instância de SalesmanController
+func NewSalesmanController(salesmanRepo salesman.Repository, customerRepo customer.Repository, logger logger.Logger) *SalesmanController {
+	return &SalesmanController{
+		salesmanRepo: salesmanRepo,
+		customerRepo: customerRepo,
+		logger:       logger,
+	}
+}
+
+// Create cria um vendedor
+// @Summary Criar vendedor
+// @Description Cadastra um vendedor, opcionalmente vinculado a um usuário do sistema
+// @Tags Vendedores
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param salesman body dto.SalesmanRequest true "Dados do vendedor"
+// @Success 201 {object} salesman.Salesman
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen [post]
+func (c *SalesmanController) Create(ctx *gin.Context) {
+	var req dto.SalesmanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	s, err := salesman.NewSalesman(tenantID, req.Name, req.CommissionPercent)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+	applySalesmanRequest(s, &req)
+
+	if err := c.salesmanRepo.Create(ctx, s); err != nil {
+		c.respondSalesmanError(ctx, "erro ao salvar vendedor", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, s)
+}
+
+// Update atualiza um vendedor
+// @Summary Atualizar vendedor
+// @Description Atualiza os dados e o percentual padrão de comissão do vendedor
+// @Tags Vendedores
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do vendedor"
+// @Param salesman body dto.SalesmanRequest true "Dados do vendedor"
+// @Success 200 {object} salesman.Salesman
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/{id} [put]
+func (c *SalesmanController) Update(ctx *gin.Context) {
+	var req dto.SalesmanRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	s, err := c.salesmanRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao buscar vendedor", err)
+		return
+	}
+
+	s.Name = req.Name
+	s.CommissionPercent = req.CommissionPercent
+	s.UpdatedAt = time.Now()
+	applySalesmanRequest(s, &req)
+
+	if err := s.Validate(); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.salesmanRepo.Update(ctx, s); err != nil {
+		c.respondSalesmanError(ctx, "erro ao atualizar vendedor", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, s)
+}
+
+// Get busca um vendedor
+// @Summary Obter vendedor
+// @Description Busca um vendedor pelo ID
+// @Tags Vendedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do vendedor"
+// @Success 200 {object} salesman.Salesman
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/{id} [get]
+func (c *SalesmanController) Get(ctx *gin.Context) {
+	s, err := c.salesmanRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao buscar vendedor", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, s)
+}
+
+// List lista os vendedores
+// @Summary Listar vendedores
+// @Description Lista os vendedores por nome e situação
+// @Tags Vendedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param name query string false "Filtrar por nome"
+// @Param active query bool false "Filtrar por situação"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.SalesmanListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen [get]
+func (c *SalesmanController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	filter := salesman.Filter{Name: ctx.Query("name")}
+	if value := ctx.Query("active"); value != "" {
+		active := value == "true"
+		filter.Active = &active
+	}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	salesmen, err := c.salesmanRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao listar vendedores", err)
+		return
+	}
+
+	total, err := c.salesmanRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao contar vendedores", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToSalesmanListResponse(salesmen, total, pagination.Page, pagination.PageSize))
+}
+
+// CreateRule cria uma regra de comissão
+// @Summary Criar regra de comissão
+// @Description Cadastra o percentual de comissão por categoria de produto e/ou forma de pagamento, para um vendedor ou para todos. A regra mais específica prevalece; sem regra vale o percentual padrão do vendedor
+// @Tags Vendedores
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param rule body dto.CommissionRuleRequest true "Dados da regra"
+// @Success 201 {object} salesman.Rule
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/commission-rules [post]
+func (c *SalesmanController) CreateRule(ctx *gin.Context) {
+	var req dto.CommissionRuleRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	rule, err := salesman.NewRule(tenantID, req.SalesmanID, req.CategoryID, req.PaymentMethodID, req.Percentage)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.salesmanRepo.CreateRule(ctx, rule); err != nil {
+		c.respondSalesmanError(ctx, "erro ao salvar regra de comissão", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, rule)
+}
+
+// DeleteRule exclui uma regra de comissão
+// @Summary Excluir regra de comissão
+// @Description Exclui uma regra de comissão; comissões já lançadas não são alteradas
+// @Tags Vendedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID da regra"
+// @Success 204 "No Content"
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/commission-rules/{id} [delete]
+func (c *SalesmanController) DeleteRule(ctx *gin.Context) {
+	if err := c.salesmanRepo.DeleteRule(ctx, ctx.Param("id")); err != nil {
+		c.respondSalesmanError(ctx, "erro ao excluir regra de comissão", err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListRules lista as regras de comissão
+// @Summary Listar regras de comissão
+// @Description Lista as regras de comissão; com salesman_id, lista as regras gerais e as do vendedor
+// @Tags Vendedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param salesman_id query string false "Filtrar pelas regras que valem para o vendedor"
+// @Success 200 {array} salesman.Rule
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/commission-rules [get]
+func (c *SalesmanController) ListRules(ctx *gin.Context) {
+	rules, err := c.salesmanRepo.ListRules(ctx, ctx.Query("salesman_id"))
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao listar regras de comissão", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, rules)
+}
+
+// Accrue lança a comissão de uma venda finalizada
+// @Summary Lançar comissão da venda
+// @Description Chamado na finalização da venda. Calcula a comissão por item e forma de pagamento conforme as regras e grava uma única comissão por venda
+// @Tags Vendedores
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param sale body dto.CommissionAccrueRequest true "Venda finalizada"
+// @Success 201 {object} salesman.Commission
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 422 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/commissions/accrue [post]
+func (c *SalesmanController) Accrue(ctx *gin.Context) {
+	var req dto.CommissionAccrueRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	salesmanID := req.SalesmanID
+	if salesmanID == "" && req.CustomerID != "" {
+		cust, err := c.customerRepo.FindByID(ctx, req.CustomerID)
+		if err != nil {
+			c.respondSalesmanError(ctx, "erro ao buscar cliente", err)
+			return
+		}
+		salesmanID = cust.SalesmanID
+	}
+	if salesmanID == "" {
+		c.respondSalesmanError(ctx, "vendedor não informado", errSalesmanRequired)
+		return
+	}
+
+	s, err := c.salesmanRepo.FindByID(ctx, salesmanID)
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao buscar vendedor", err)
+		return
+	}
+
+	rules, err := c.salesmanRepo.ListRules(ctx, s.ID)
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao buscar regras de comissão", err)
+		return
+	}
+
+	branchID, ok := resolveBranchID(ctx, req.BranchID)
+	if !ok {
+		return
+	}
+	sale := salesman.Sale{
+		SaleID:     req.SaleID,
+		BranchID:   branchID,
+		CustomerID: req.CustomerID,
+		SaleDate:   req.SaleDate,
+		Items:      req.Items,
+		Payments:   req.Payments,
+	}
+
+	commission, err := salesman.Accrue(s, rules, sale)
+	if err != nil {
+		c.respondSalesmanError(ctx, "comissão não lançada", err)
+		return
+	}
+
+	if err := c.salesmanRepo.Accrue(ctx, commission); err != nil {
+		c.respondSalesmanError(ctx, "erro ao lançar comissão", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, commission)
+}
+
+// Reverse estorna a comissão de uma venda cancelada
+// @Summary Estornar comissão da venda
+// @Description Chamado no cancelamento da venda. O estorno entra no extrato do período do cancelamento
+// @Tags Vendedores
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param request body dto.CommissionReverseRequest true "Venda cancelada"
+// @Success 200 {object} salesman.Commission
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/commissions/reverse [post]
+func (c *SalesmanController) Reverse(ctx *gin.Context) {
+	var req dto.CommissionReverseRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	commission, err := c.salesmanRepo.Reverse(ctx, req.SaleID, req.Reason)
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao estornar comissão", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, commission)
+}
+
+// Statement retorna o extrato de comissões do vendedor
+// @Summary Extrato de comissões
+// @Description Extrato do vendedor no período: comissões das vendas do período e estornos dos cancelamentos do período. Sem datas, usa o mês corrente
+// @Tags Vendedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do vendedor"
+// @Param start_date query string false "Data inicial (YYYY-MM-DD)"
+// @Param end_date query string false "Data final (YYYY-MM-DD)"
+// @Success 200 {object} salesman.Statement
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /salesmen/{id}/statement [get]
+func (c *SalesmanController) Statement(ctx *gin.Context) {
+	startDate, endDate, err := parsePeriod(ctx)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", "use o formato YYYY-MM-DD"))
+		return
+	}
+
+	now := time.Now()
+	if startDate.IsZero() {
+		startDate = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local)
+	}
+	if endDate.IsZero() {
+		endDate = startDate.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	}
+
+	s, err := c.salesmanRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao buscar vendedor", err)
+		return
+	}
+
+	commissions, err := c.salesmanRepo.ListCommissions(ctx, s.ID, startDate, endDate)
+	if err != nil {
+		c.respondSalesmanError(ctx, "erro ao listar comissões", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, salesman.NewStatement(s, startDate, endDate, commissions))
+}
+
+// applySalesmanRequest copia os dados opcionais informados na requisição
+func applySalesmanRequest(s *salesman.Salesman, req *dto.SalesmanRequest) {
+	s.UserID = req.UserID
+	s.Document = req.Document
+	s.Email = req.Email
+	s.Phone = req.Phone
+	if req.Active != nil {
+		s.Active = *req.Active
+	}
+}
+
+// respondSalesmanError converte erros de vendedores e comissões em respostas HTTP
+func (c *SalesmanController) respondSalesmanError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrSalesmanNotFound), errors.Is(err, repository.ErrCommissionRuleNotFound),
+		errors.Is(err, repository.ErrCommissionNotFound), errors.Is(err, repository.ErrCustomerNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrSalesmanUserInUse), errors.Is(err, repository.ErrCommissionAlreadyAccrued),
+		errors.Is(err, repository.ErrCommissionAlreadyReversed):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrSalesmanUserNotFound), errors.Is(err, repository.ErrCommissionRuleReference),
+		errors.Is(err, salesman.ErrInactive), errors.Is(err, salesman.ErrNoCommission):
+		status = http.StatusUnprocessableEntity
+	case errors.Is(err, errSalesmanRequired), errors.Is(err, salesman.ErrInvalidItemAmount),
+		errors.Is(err, salesman.ErrInvalidPaymentAmount), errors.Is(err, salesman.ErrEmptySaleItems):
+		status = http.StatusBadRequest
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
@@ -0,0 +1,252 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/supplier"
+	"github.com/hugohenrick/erp-supermercado/pkg/cep"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// SupplierController manipula as requisições relacionadas a fornecedores
+type SupplierController struct {
+	supplierRepo supplier.Repository
+	cepProvider  cep.Provider
+	logger       logger.Logger
+}
+
+// NewSupplierController cria uma nova instância de SupplierController
+func NewSupplierController(supplierRepo supplier.Repository, cepProvider cep.Provider, logger logger.Logger) *SupplierController {
+	return &SupplierController{
+		supplierRepo: supplierRepo,
+		cepProvider:  cepProvider,
+		logger:       logger,
+	}
+}
+
+// Create cria um novo fornecedor
+// @Summary Criar fornecedor
+// @Description Cadastra um novo fornecedor com seu prazo de pagamento
+// @Tags Fornecedores
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param supplier body dto.SupplierRequest true "Dados do fornecedor"
+// @Success 201 {object} supplier.Supplier
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /suppliers [post]
+func (c *SupplierController) Create(ctx *gin.Context) {
+	var req dto.SupplierRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	s, err := supplier.NewSupplier(ctx.GetString("tenant_id"), req.Name, req.TradeName, req.Document, req.PaymentTerm)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar fornecedor", err.Error()))
+		return
+	}
+
+	c.completeAddress(ctx, &req.Address)
+	if err := s.Update(req.Name, req.TradeName, req.StateDocument, req.Email, req.Phone, req.PaymentTerm, req.Notes, req.Address); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao criar fornecedor", err.Error()))
+		return
+	}
+
+	if err := c.supplierRepo.Create(ctx, s); err != nil {
+		c.respondSupplierError(ctx, "erro ao salvar fornecedor", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, s)
+}
+
+// Get busca um fornecedor pelo ID
+// @Summary Obter fornecedor
+// @Description Busca um fornecedor pelo ID
+// @Tags Fornecedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do fornecedor"
+// @Success 200 {object} supplier.Supplier
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /suppliers/{id} [get]
+func (c *SupplierController) Get(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	s, err := c.supplierRepo.FindByID(ctx, id)
+	if err != nil {
+		c.respondSupplierError(ctx, "erro ao buscar fornecedor", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, s)
+}
+
+// List lista os fornecedores
+// @Summary Listar fornecedores
+// @Description Lista os fornecedores com busca por nome ou documento
+// @Tags Fornecedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param search query string false "Nome, nome fantasia ou documento"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.SupplierListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /suppliers [get]
+func (c *SupplierController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+	search := ctx.Query("search")
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	suppliers, err := c.supplierRepo.List(ctx, search, pagination.PageSize, offset)
+	if err != nil {
+		c.respondSupplierError(ctx, "erro ao listar fornecedores", err)
+		return
+	}
+
+	total, err := c.supplierRepo.Count(ctx, search)
+	if err != nil {
+		c.respondSupplierError(ctx, "erro ao contar fornecedores", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToSupplierListResponse(suppliers, total, pagination.Page, pagination.PageSize))
+}
+
+// Update atualiza um fornecedor
+// @Summary Atualizar fornecedor
+// @Description Atualiza os dados cadastrais de um fornecedor
+// @Tags Fornecedores
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do fornecedor"
+// @Param supplier body dto.SupplierRequest true "Dados do fornecedor"
+// @Success 200 {object} supplier.Supplier
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /suppliers/{id} [put]
+func (c *SupplierController) Update(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	var req dto.SupplierRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	s, err := c.supplierRepo.FindByID(ctx, id)
+	if err != nil {
+		c.respondSupplierError(ctx, "erro ao buscar fornecedor", err)
+		return
+	}
+
+	c.completeAddress(ctx, &req.Address)
+	if err := s.Update(req.Name, req.TradeName, req.StateDocument, req.Email, req.Phone, req.PaymentTerm, req.Notes, req.Address); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "erro ao atualizar fornecedor", err.Error()))
+		return
+	}
+
+	if req.Active != nil {
+		if *req.Active {
+			s.Activate()
+		} else {
+			s.Deactivate()
+		}
+	}
+
+	if err := c.supplierRepo.Update(ctx, s); err != nil {
+		c.respondSupplierError(ctx, "erro ao atualizar fornecedor", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, s)
+}
+
+// Delete remove um fornecedor
+// @Summary Excluir fornecedor
+// @Description Exclui um fornecedor sem títulos vinculados
+// @Tags Fornecedores
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do fornecedor"
+// @Success 200 {object} dto.SuccessResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /suppliers/{id} [delete]
+func (c *SupplierController) Delete(ctx *gin.Context) {
+	id := ctx.Param("id")
+	if _, err := uuid.Parse(id); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID inválido", "formato de ID inválido"))
+		return
+	}
+
+	if err := c.supplierRepo.Delete(ctx, id); err != nil {
+		c.respondSupplierError(ctx, "erro ao excluir fornecedor", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.NewSuccessResponse("fornecedor excluído com sucesso", nil))
+}
+
+// respondSupplierError traduz os erros de fornecedores para o status HTTP adequado
+func (c *SupplierController) respondSupplierError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrSupplierNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrSupplierDuplicateDocument), errors.Is(err, repository.ErrSupplierInUse):
+		status = http.StatusConflict
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
+
+// completeAddress preenche os códigos IBGE e, pelo CEP, os campos do endereço não informados
+func (c *SupplierController) completeAddress(ctx context.Context, address *supplier.Address) {
+	a := cep.Address{
+		ZipCode:   address.ZipCode,
+		Street:    address.Street,
+		District:  address.District,
+		City:      address.City,
+		State:     address.State,
+		CityCode:  address.CityCode,
+		StateCode: address.StateCode,
+	}
+	if err := cep.Complete(ctx, c.cepProvider, &a); err != nil {
+		c.logger.Warn("não foi possível consultar o CEP do fornecedor", "zip_code", address.ZipCode, "error", err)
+	}
+
+	address.Street, address.District = a.Street, a.District
+	address.City, address.State = a.City, a.State
+	address.CityCode, address.StateCode = a.CityCode, a.StateCode
+}
@@ -6,15 +6,15 @@ import (
 	"fmt"
 	"net/http"
 	"os"
-	"path"
 	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
-	"github.com/google/uuid"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
@@ -22,13 +22,19 @@ import (
 type TenantController struct {
 	tenantRepository tenant.Repository
 	db               *pgxpool.Pool
+	gracePeriod      time.Duration // Prazo entre a exclusão e o expurgo do schema
 }
 
-// NewTenantController cria uma nova instância de TenantController
-func NewTenantController(tenantRepository tenant.Repository, db *pgxpool.Pool) *TenantController {
+// NewTenantController cria uma nova instância de TenantController. Um prazo de carência não
+// positivo usa tenant.DefaultGracePeriod
+func NewTenantController(tenantRepository tenant.Repository, db *pgxpool.Pool, gracePeriod time.Duration) *TenantController {
+	if gracePeriod <= 0 {
+		gracePeriod = tenant.DefaultGracePeriod
+	}
 	return &TenantController{
 		tenantRepository: tenantRepository,
 		db:               db,
+		gracePeriod:      gracePeriod,
 	}
 }
 
@@ -51,29 +57,15 @@ func (c *TenantController) Create(ctx *gin.Context) {
 		return
 	}
 
-	// Gerar um ID para o novo tenant
-	id := uuid.New().String()
-
-	// Gerar um nome de schema único baseado no ID
-	schema := "tenant_" + id[:8]
-
-	// Criar o modelo de domínio a partir do DTO
-	t := &tenant.Tenant{
-		ID:          id,
-		Name:        request.Name,
-		Document:    request.Document,
-		Email:       request.Email,
-		Phone:       request.Phone,
-		Status:      tenant.StatusActive, // Por padrão, o tenant é criado ativo
-		Schema:      schema,
-		PlanType:    request.PlanType,
-		MaxBranches: request.MaxBranches,
-		CreatedAt:   time.Now(),
-		UpdatedAt:   time.Now(),
+	// Criar o modelo de domínio a partir do DTO; o construtor gera o ID e o schema e valida o CNPJ
+	t, err := tenant.NewTenant(request.Name, request.Document, request.Email, request.Phone, request.PlanType, request.MaxBranches)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Dados do tenant inválidos", err.Error()))
+		return
 	}
 
 	// Persistir o tenant
-	err := c.tenantRepository.Create(ctx, t)
+	err = c.tenantRepository.Create(ctx, t)
 	if err != nil {
 		if err == repository.ErrTenantDuplicateDocument {
 			ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Tenant já existe", "Um tenant com este documento já está cadastrado"))
@@ -84,10 +76,10 @@ func (c *TenantController) Create(ctx *gin.Context) {
 	}
 
 	// Criar o schema para o tenant no banco de dados
-	err = c.createTenantSchema(ctx, id, schema)
+	err = c.createTenantSchema(ctx, t.ID, t.Schema)
 	if err != nil {
 		// Se falhar ao criar o schema, excluir o tenant para manter a consistência
-		deleteErr := c.tenantRepository.Delete(ctx, id)
+		deleteErr := c.tenantRepository.Delete(ctx, t.ID)
 		if deleteErr != nil {
 			// Logar o erro de exclusão, mas continuar com o erro principal
 			// Em um ambiente de produção, isso deveria ser registrado em um sistema de logs
@@ -109,147 +101,7 @@ func (c *TenantController) Create(ctx *gin.Context) {
 
 // createTenantSchema cria um novo schema no banco de dados para o tenant
 func (c *TenantController) createTenantSchema(ctx context.Context, tenantID, schema string) error {
-	conn, err := c.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("erro ao adquirir conexão do pool: %w", err)
-	}
-	defer conn.Release()
-
-	// Criar schema
-	_, err = conn.Exec(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema))
-	if err != nil {
-		return fmt.Errorf("erro ao criar schema: %w", err)
-	}
-
-	// Configurar permissões
-	_, err = conn.Exec(ctx, fmt.Sprintf("GRANT ALL ON SCHEMA %s TO CURRENT_USER", schema))
-	if err != nil {
-		return fmt.Errorf("erro ao configurar permissões do schema: %w", err)
-	}
-
-	// Aplicar migrações no schema do tenant
-	err = c.applyTenantMigrations(ctx, schema)
-	if err != nil {
-		return fmt.Errorf("erro ao aplicar migrações no schema do tenant: %w", err)
-	}
-
-	return nil
-}
-
-// applyTenantMigrations executa as migrações necessárias no schema do tenant
-func (c *TenantController) applyTenantMigrations(ctx context.Context, schema string) error {
-	// Lista de migrações a serem aplicadas, na ordem correta
-	migrations := []struct {
-		description string
-		filename    string
-	}{
-		{
-			description: "Criação da tabela de filiais (branches)",
-			filename:    "/home/hugohenrick/Estudos/Cursor/super/erp-supermercado/migrations/tenant/000001_create_branches_table.up.sql",
-		},
-		{
-			description: "Criação da tabela de usuários (users)",
-			filename:    "/home/hugohenrick/Estudos/Cursor/super/erp-supermercado/migrations/tenant/000002_create_users_table.up.sql",
-		},
-		{
-			description: "Criação da tabela de categorias de produtos",
-			filename:    "/home/hugohenrick/Estudos/Cursor/super/erp-supermercado/migrations/tenant/000003_create_product_categories_table.up.sql",
-		},
-		{
-			description: "Criação da tabela de produtos",
-			filename:    "/home/hugohenrick/Estudos/Cursor/super/erp-supermercado/migrations/tenant/000004_create_products_table.up.sql",
-		},
-		{
-			description: "Criação das tabelas de estoque",
-			filename:    "/home/hugohenrick/Estudos/Cursor/super/erp-supermercado/migrations/tenant/000005_create_inventory_tables.up.sql",
-		},
-		{
-			description: "Criação da tabela de clientes",
-			filename:    "/home/hugohenrick/Estudos/Cursor/super/erp-supermercado/migrations/tenant/000006_create_customers_table.up.sql",
-		},
-	}
-
-	conn, err := c.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("erro ao adquirir conexão do pool: %w", err)
-	}
-	defer conn.Release()
-
-	// Tabela para rastrear migrações aplicadas neste schema
-	_, err = conn.Exec(ctx, fmt.Sprintf(`
-		CREATE TABLE IF NOT EXISTS %s.schema_migrations (
-			version VARCHAR(255) PRIMARY KEY,
-			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
-		)`, schema))
-	if err != nil {
-		return fmt.Errorf("erro ao criar tabela de controle de migrações: %w", err)
-	}
-
-	// Para cada migração, verificar se já foi aplicada e aplicar se necessário
-	for _, migration := range migrations {
-		// Verificar se a migração já foi aplicada
-		var exists bool
-		err = conn.QueryRow(ctx, fmt.Sprintf(`
-			SELECT EXISTS(
-				SELECT 1 FROM %s.schema_migrations WHERE version = $1
-			)`, schema), path.Base(migration.filename)).Scan(&exists)
-
-		if err != nil {
-			return fmt.Errorf("erro ao verificar migração: %w", err)
-		}
-
-		// Se a migração já foi aplicada, pular
-		if exists {
-			continue
-		}
-
-		// Ler o conteúdo do arquivo de migração
-		sqlContent, err := os.ReadFile(migration.filename)
-		if err != nil {
-			return fmt.Errorf("erro ao ler arquivo de migração %s: %w", migration.filename, err)
-		}
-
-		// Substituir referências ao schema padrão "public" pelo schema do tenant
-		sqlString := string(sqlContent)
-
-		// Iniciar uma transação para aplicar a migração
-		tx, err := conn.Begin(ctx)
-		if err != nil {
-			return fmt.Errorf("erro ao iniciar transação: %w", err)
-		}
-
-		// Aplicar a migração, configurando o schema de busca
-		_, err = tx.Exec(ctx, fmt.Sprintf("SET search_path TO %s", schema))
-		if err != nil {
-			tx.Rollback(ctx)
-			return fmt.Errorf("erro ao configurar search_path: %w", err)
-		}
-
-		// Executar o script SQL da migração
-		_, err = tx.Exec(ctx, sqlString)
-		if err != nil {
-			tx.Rollback(ctx)
-			return fmt.Errorf("erro ao aplicar migração %s: %w", migration.filename, err)
-		}
-
-		// Registrar a migração como aplicada
-		_, err = tx.Exec(ctx, fmt.Sprintf(`
-			INSERT INTO %s.schema_migrations (version) VALUES ($1)
-		`, schema), path.Base(migration.filename))
-
-		if err != nil {
-			tx.Rollback(ctx)
-			return fmt.Errorf("erro ao registrar migração: %w", err)
-		}
-
-		// Commit da transação
-		err = tx.Commit(ctx)
-		if err != nil {
-			return fmt.Errorf("erro ao fazer commit da transação: %w", err)
-		}
-	}
-
-	return nil
+	return database.CreateTenantSchema(ctx, c.db, schema)
 }
 
 // GetByID busca um tenant pelo ID
@@ -364,6 +216,7 @@ func (c *TenantController) List(ctx *gin.Context) {
 // @Success 200 {object} dto.TenantResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /tenants/{id} [put]
 func (c *TenantController) Update(ctx *gin.Context) {
@@ -383,6 +236,10 @@ func (c *TenantController) Update(ctx *gin.Context) {
 		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar tenant", err.Error()))
 		return
 	}
+	if existingTenant.IsDeleted() {
+		ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Tenant excluído", "Restaure o tenant antes de alterá-lo"))
+		return
+	}
 
 	// Fazer o bind dos dados da requisição
 	var request dto.TenantRequest
@@ -391,6 +248,16 @@ func (c *TenantController) Update(ctx *gin.Context) {
 		return
 	}
 
+	// O plano precisa estar no catálogo; sem limite de filiais informado, vale o do plano
+	plan, err := tenant.FindPlan(request.PlanType)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Plano inválido", err.Error()))
+		return
+	}
+	if request.MaxBranches <= 0 {
+		request.MaxBranches = plan.MaxBranches
+	}
+
 	// Atualizar o tenant existente com os novos dados
 	existingTenant.Name = request.Name
 	existingTenant.Email = request.Email
@@ -411,42 +278,246 @@ func (c *TenantController) Update(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, dto.ToTenantResponse(existingTenant))
 }
 
-// Delete remove um tenant
-// @Summary Remove um tenant
-// @Description Remove um tenant do sistema
+// ListPlans lista o catálogo de planos
+// @Summary Lista os planos
+// @Description Lista os planos disponíveis com os limites de filiais, usuários, terminais de PDV e documentos fiscais por mês (zero é ilimitado) e os módulos incluídos
+// @Tags tenants
+// @Produce json
+// @Success 200 {array} tenant.Plan
+// @Router /tenants/plans [get]
+func (c *TenantController) ListPlans(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, tenant.Plans())
+}
+
+// GetSubscription busca a assinatura de um tenant
+// @Summary Busca a assinatura de um tenant
+// @Description Retorna o plano do tenant, a data de expiração e a situação: active, grace (expirado dentro da carência) ou read_only
+// @Tags tenants
+// @Produce json
+// @Param id path string true "ID do tenant"
+// @Success 200 {object} tenant.Subscription
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/{id}/subscription [get]
+func (c *TenantController) GetSubscription(ctx *gin.Context) {
+	t, ok := c.findTenant(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t.Subscription(time.Now()))
+}
+
+// ChangePlan troca o plano de um tenant
+// @Summary Troca o plano de um tenant
+// @Description Altera o plano, o limite de filiais e a vigência do tenant. Renovar a vigência tira o tenant do modo somente leitura
+// @Tags tenants
+// @Accept json
+// @Produce json
+// @Param id path string true "ID do tenant"
+// @Param plan body dto.PlanChangeRequest true "Novo plano"
+// @Success 200 {object} dto.TenantResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/{id}/plan [put]
+func (c *TenantController) ChangePlan(ctx *gin.Context) {
+	var request dto.PlanChangeRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Requisição inválida", err.Error()))
+		return
+	}
+
+	t, ok := c.findTenant(ctx)
+	if !ok {
+		return
+	}
+	if t.IsDeleted() {
+		ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Tenant excluído", "Restaure o tenant antes de alterar o plano"))
+		return
+	}
+
+	if err := t.ChangePlan(request.PlanType, request.MaxBranches, request.ExpiresAt); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Plano inválido", err.Error()))
+		return
+	}
+
+	if err := c.tenantRepository.Update(ctx, t); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao alterar plano", err.Error()))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToTenantResponse(t))
+}
+
+// Delete exclui um tenant
+// @Summary Exclui um tenant
+// @Description Exclui logicamente o tenant, que deixa de ter acesso ao sistema. Os dados continuam no schema até o fim do prazo de carência, quando são arquivados e expurgados pelo comando purge-tenants
 // @Tags tenants
 // @Produce json
 // @Param id path string true "ID do tenant"
-// @Success 200 {object} dto.SuccessResponse
+// @Success 200 {object} dto.TenantResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /tenants/{id} [delete]
 func (c *TenantController) Delete(ctx *gin.Context) {
+	t, ok := c.findTenant(ctx)
+	if !ok {
+		return
+	}
+
+	if err := t.SoftDelete(c.gracePeriod); err != nil {
+		ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Tenant já excluído", err.Error()))
+		return
+	}
+
+	audit := tenant.NewAuditEntry(t, tenant.AuditDeleted, auditActor(ctx), map[string]any{"purge_after": t.PurgeAfter})
+	if err := c.tenantRepository.SoftDelete(ctx, t, audit); err != nil {
+		c.lifecycleError(ctx, err, "Erro ao excluir tenant")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToTenantResponse(t))
+}
+
+// Restore desfaz a exclusão de um tenant
+// @Summary Restaura um tenant excluído
+// @Description Desfaz a exclusão do tenant dentro do prazo de carência. O tenant volta inativo e precisa ser reativado
+// @Tags tenants
+// @Produce json
+// @Param id path string true "ID do tenant"
+// @Success 200 {object} dto.TenantResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/{id}/restore [post]
+func (c *TenantController) Restore(ctx *gin.Context) {
+	t, ok := c.findTenant(ctx)
+	if !ok {
+		return
+	}
+
+	if err := t.Restore(); err != nil {
+		ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Tenant não está excluído", err.Error()))
+		return
+	}
+
+	audit := tenant.NewAuditEntry(t, tenant.AuditRestored, auditActor(ctx), nil)
+	if err := c.tenantRepository.Restore(ctx, t, audit); err != nil {
+		c.lifecycleError(ctx, err, "Erro ao restaurar tenant")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToTenantResponse(t))
+}
+
+// Export exporta os dados de um tenant
+// @Summary Exporta os dados de um tenant
+// @Description Gera um ZIP com todas as tabelas do schema do tenant em JSON e CSV, os XMLs fiscais e um manifest.json
+// @Tags tenants
+// @Produce application/zip
+// @Param id path string true "ID do tenant"
+// @Success 200 {file} file
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/{id}/export [get]
+func (c *TenantController) Export(ctx *gin.Context) {
+	t, ok := c.findTenant(ctx)
+	if !ok {
+		return
+	}
+
+	// O ZIP é montado em um arquivo temporário: se a exportação falhar no meio, ainda é possível
+	// responder com erro em vez de um arquivo truncado
+	file, err := os.CreateTemp("", t.Schema+"-*.zip")
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao exportar tenant", err.Error()))
+		return
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	manifest, err := database.ExportSchema(ctx, c.db, t.Schema, file)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao exportar tenant", err.Error()))
+		return
+	}
+
+	audit := tenant.NewAuditEntry(t, tenant.AuditExported, auditActor(ctx), map[string]any{
+		"tables":    len(manifest.Tables),
+		"xml_files": manifest.XMLFiles,
+	})
+	if err := c.tenantRepository.RecordAudit(ctx, audit); err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao registrar auditoria", err.Error()))
+		return
+	}
+
+	filename := fmt.Sprintf("%s_%s.zip", t.Schema, manifest.ExportedAt.Format("20060102150405"))
+	ctx.FileAttachment(file.Name(), filename)
+}
+
+// Audit lista a trilha de auditoria de um tenant
+// @Summary Lista a auditoria de um tenant
+// @Description Lista os eventos de exclusão, restauração, exportação e expurgo do tenant. A trilha continua disponível depois do expurgo
+// @Tags tenants
+// @Produce json
+// @Param id path string true "ID do tenant"
+// @Success 200 {array} tenant.AuditEntry
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/{id}/audit [get]
+func (c *TenantController) Audit(ctx *gin.Context) {
+	entries, err := c.tenantRepository.ListAudit(ctx, ctx.Param("id"))
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao listar auditoria", err.Error()))
+		return
+	}
+	if entries == nil {
+		entries = []*tenant.AuditEntry{}
+	}
+
+	ctx.JSON(http.StatusOK, entries)
+}
+
+// findTenant busca o tenant do parâmetro id, respondendo 400, 404 ou 500 quando não é possível
+func (c *TenantController) findTenant(ctx *gin.Context) (*tenant.Tenant, bool) {
 	id := ctx.Param("id")
 	if id == "" {
 		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID não fornecido", ""))
-		return
+		return nil, false
 	}
 
-	// Verificar se o tenant existe
-	_, err := c.tenantRepository.FindByID(ctx, id)
+	t, err := c.tenantRepository.FindByID(ctx, id)
 	if err != nil {
 		if errors.Is(err, repository.ErrTenantNotFound) {
 			ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Tenant não encontrado", ""))
-			return
+			return nil, false
 		}
 		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar tenant", err.Error()))
-		return
+		return nil, false
 	}
+	return t, true
+}
 
-	// Remover o tenant
-	err = c.tenantRepository.Delete(ctx, id)
-	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao remover tenant", err.Error()))
-		return
+// lifecycleError responde aos erros de exclusão e restauração do tenant
+func (c *TenantController) lifecycleError(ctx *gin.Context, err error, message string) {
+	switch {
+	case errors.Is(err, repository.ErrTenantNotFound):
+		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Tenant não encontrado", ""))
+	case errors.Is(err, tenant.ErrTenantDeleted), errors.Is(err, tenant.ErrTenantNotDeleted):
+		ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, message, err.Error()))
+	default:
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, message, err.Error()))
 	}
+}
 
-	ctx.JSON(http.StatusOK, dto.NewSuccessResponse("Tenant removido com sucesso", nil))
+// auditActor identifica o operador da plataforma autenticado na trilha de auditoria
+func auditActor(ctx *gin.Context) string {
+	if _, email, _ := auth.GetCurrentOperator(ctx); email != "" {
+		return email
+	}
+	return "api"
 }
 
 // UpdateStatus atualiza o status de um tenant
@@ -459,6 +530,7 @@ func (c *TenantController) Delete(ctx *gin.Context) {
 // @Success 200 {object} dto.TenantResponse
 // @Failure 400 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /tenants/{id}/status/{status} [patch]
 func (c *TenantController) UpdateStatus(ctx *gin.Context) {
@@ -481,8 +553,23 @@ func (c *TenantController) UpdateStatus(ctx *gin.Context) {
 		return
 	}
 
+	// Tenants excluídos só mudam de status pela restauração
+	existing, err := c.tenantRepository.FindByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repository.ErrTenantNotFound) {
+			ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Tenant não encontrado", ""))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar tenant", err.Error()))
+		return
+	}
+	if existing.IsDeleted() {
+		ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Tenant excluído", "Restaure o tenant antes de alterar o status"))
+		return
+	}
+
 	// Atualizar status
-	err := c.tenantRepository.UpdateStatus(ctx, id, status)
+	err = c.tenantRepository.UpdateStatus(ctx, id, status)
 	if err != nil {
 		if errors.Is(err, repository.ErrTenantNotFound) {
 			ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Tenant não encontrado", ""))
@@ -0,0 +1,189 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/terminal"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+)
+
+// TerminalController manipula as requisições de terminais de PDV
+type TerminalController struct {
+	terminalRepo terminal.Repository
+	logger       logger.Logger
+}
+
+// NewTerminalController cria uma nova instância de TerminalController
+func NewTerminalController(terminalRepo terminal.Repository, logger logger.Logger) *TerminalController {
+	return &TerminalController{
+		terminalRepo: terminalRepo,
+		logger:       logger,
+	}
+}
+
+// Create cadastra um terminal de PDV
+// @Summary Cadastrar terminal de PDV
+// @Description Cadastra um terminal (caixa) ativo na filial. A quantidade de terminais ativos é limitada pelo plano do tenant
+// @Tags Terminais de PDV
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param terminal body dto.TerminalRequest true "Dados do terminal"
+// @Success 201 {object} terminal.Terminal
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 402 {object} dto.ErrorResponse
+// @Failure 409 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /terminals [post]
+func (c *TerminalController) Create(ctx *gin.Context) {
+	var req dto.TerminalRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if !authorizeBranch(ctx, req.BranchID) {
+		return
+	}
+
+	_, tenantID, _, _, _, _ := auth.GetCurrentUser(ctx)
+	t, err := terminal.NewTerminal(tenantID, req.BranchID, req.Code, req.Name)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", err.Error()))
+		return
+	}
+
+	if err := c.terminalRepo.Create(ctx, t); err != nil {
+		c.respondTerminalError(ctx, "erro ao salvar terminal", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, t)
+}
+
+// Get busca um terminal de PDV
+// @Summary Obter terminal de PDV
+// @Description Busca um terminal pelo ID
+// @Tags Terminais de PDV
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do terminal"
+// @Success 200 {object} terminal.Terminal
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /terminals/{id} [get]
+func (c *TerminalController) Get(ctx *gin.Context) {
+	t, err := c.terminalRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondTerminalError(ctx, "erro ao buscar terminal", err)
+		return
+	}
+	if !authorizeBranch(ctx, t.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// List lista os terminais de PDV
+// @Summary Listar terminais de PDV
+// @Description Lista os terminais, opcionalmente de uma filial ou situação
+// @Tags Terminais de PDV
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param branch_id query string false "Filtrar por filial"
+// @Param status query string false "Filtrar por situação (active/inactive)"
+// @Param page query int false "Número da página (padrão: 1)"
+// @Param page_size query int false "Tamanho da página (padrão: 10)"
+// @Success 200 {object} dto.TerminalListResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /terminals [get]
+func (c *TerminalController) List(ctx *gin.Context) {
+	page, _ := strconv.Atoi(ctx.DefaultQuery("page", "1"))
+	pageSize, _ := strconv.Atoi(ctx.DefaultQuery("page_size", "10"))
+	pagination := dto.GetPagination(page, pageSize)
+
+	branchID, ok := branchFilter(ctx)
+	if !ok {
+		return
+	}
+	filter := terminal.Filter{BranchID: branchID, Status: terminal.Status(ctx.Query("status"))}
+
+	offset := (pagination.Page - 1) * pagination.PageSize
+	terminals, err := c.terminalRepo.List(ctx, filter, pagination.PageSize, offset)
+	if err != nil {
+		c.respondTerminalError(ctx, "erro ao listar terminais", err)
+		return
+	}
+
+	total, err := c.terminalRepo.Count(ctx, filter)
+	if err != nil {
+		c.respondTerminalError(ctx, "erro ao contar terminais", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dto.ToTerminalListResponse(terminals, total, pagination.Page, pagination.PageSize))
+}
+
+// UpdateStatus ativa ou desativa um terminal de PDV
+// @Summary Alterar situação do terminal
+// @Description Ativa ou desativa um terminal; a ativação respeita o limite de terminais do plano
+// @Tags Terminais de PDV
+// @Produce json
+// @Param Authorization header string true "Bearer token"
+// @Param id path string true "ID do terminal"
+// @Param status path string true "Nova situação (active/inactive)"
+// @Success 200 {object} terminal.Terminal
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 402 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /terminals/{id}/status/{status} [patch]
+func (c *TerminalController) UpdateStatus(ctx *gin.Context) {
+	status := terminal.Status(ctx.Param("status"))
+	if status != terminal.StatusActive && status != terminal.StatusInactive {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "dados inválidos", terminal.ErrInvalidStatus.Error()))
+		return
+	}
+
+	if err := c.terminalRepo.UpdateStatus(ctx, ctx.Param("id"), status); err != nil {
+		c.respondTerminalError(ctx, "erro ao atualizar terminal", err)
+		return
+	}
+
+	t, err := c.terminalRepo.FindByID(ctx, ctx.Param("id"))
+	if err != nil {
+		c.respondTerminalError(ctx, "erro ao buscar terminal", err)
+		return
+	}
+	if !authorizeBranch(ctx, t.BranchID) {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, t)
+}
+
+// respondTerminalError converte erros de terminais em respostas HTTP
+func (c *TerminalController) respondTerminalError(ctx *gin.Context, message string, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, repository.ErrTerminalNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, repository.ErrTerminalDuplicated):
+		status = http.StatusConflict
+	case errors.Is(err, repository.ErrTerminalBranch):
+		status = http.StatusBadRequest
+	case errors.Is(err, repository.ErrPlanLimitExceeded):
+		status = http.StatusPaymentRequired
+	default:
+		c.logger.Error(message, "error", err.Error())
+	}
+
+	ctx.JSON(status, dto.NewErrorResponse(status, message, err.Error()))
+}
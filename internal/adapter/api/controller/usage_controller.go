@@ -0,0 +1,176 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/usage"
+	"github.com/hugohenrick/erp-supermercado/pkg/logger"
+	"github.com/hugohenrick/erp-supermercado/pkg/spreadsheet"
+)
+
+// UsageController manipula as requisições da medição de consumo dos tenants
+type UsageController struct {
+	usageRepo usage.Repository
+	logger    logger.Logger
+}
+
+// NewUsageController cria uma nova instância de UsageController
+func NewUsageController(usageRepo usage.Repository, logger logger.Logger) *UsageController {
+	return &UsageController{
+		usageRepo: usageRepo,
+		logger:    logger,
+	}
+}
+
+// Get busca o consumo de um tenant no mês
+// @Summary Consumo de um tenant
+// @Description Retorna o consumo do tenant no mês: documentos fiscais, usuários ativos, filiais, terminais de PDV, tokens do assistente e armazenamento. O mês corrente é medido na consulta
+// @Tags tenants
+// @Produce json
+// @Param id path string true "ID do tenant"
+// @Param period query string false "Mês no formato AAAA-MM (padrão: mês corrente)"
+// @Success 200 {object} usage.Usage
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/{id}/usage [get]
+func (c *UsageController) Get(ctx *gin.Context) {
+	period, ok := c.period(ctx)
+	if !ok {
+		return
+	}
+
+	tenantID := ctx.Param("id")
+	var u *usage.Usage
+	var err error
+	if usage.IsCurrent(period) {
+		u, err = c.usageRepo.Collect(ctx, tenantID)
+	} else {
+		u, err = c.usageRepo.Find(ctx, tenantID, period)
+	}
+	if err != nil {
+		c.respondUsageError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, u)
+}
+
+// List lista o consumo de todos os tenants no mês
+// @Summary Consumo dos tenants
+// @Description Lista o consumo de todos os tenants no mês. O mês corrente é medido na consulta
+// @Tags tenants
+// @Produce json
+// @Param period query string false "Mês no formato AAAA-MM (padrão: mês corrente)"
+// @Success 200 {array} usage.Usage
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/usage [get]
+func (c *UsageController) List(ctx *gin.Context) {
+	_, usages, ok := c.list(ctx)
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, usages)
+}
+
+// Export exporta o consumo de todos os tenants no mês
+// @Summary Exportar consumo dos tenants
+// @Description Gera a planilha CSV ou XLSX do consumo de todos os tenants no mês, uma linha por tenant, para a cobrança
+// @Tags tenants
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param period query string false "Mês no formato AAAA-MM (padrão: mês corrente)"
+// @Param format query string false "Formato (csv, xlsx)" default(csv)
+// @Success 200 {file} file
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /tenants/usage/export [get]
+func (c *UsageController) Export(ctx *gin.Context) {
+	format, err := spreadsheet.ParseFormat(ctx.DefaultQuery("format", string(spreadsheet.FormatCSV)))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "formato inválido", err.Error()))
+		return
+	}
+
+	period, usages, ok := c.list(ctx)
+	if !ok {
+		return
+	}
+
+	filename := fmt.Sprintf("consumo_%s.%s", period.Format(usage.PeriodLayout), format)
+	ctx.Header("Content-Type", spreadsheet.ContentType(format))
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	ctx.Status(http.StatusOK)
+
+	writer, err := spreadsheet.NewWriter(format, ctx.Writer)
+	if err == nil {
+		err = writer.Write(usage.Columns)
+	}
+	for _, u := range usages {
+		if err != nil {
+			break
+		}
+		err = writer.Write(u.Row())
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// A resposta já começou a ser enviada; resta registrar a falha
+		c.logger.Error("erro ao exportar consumo dos tenants", "error", err)
+	}
+}
+
+// list busca o consumo de todos os tenants no período da requisição. No mês corrente, os tenants
+// são medidos antes; um tenant com falha na medição fica com o último valor gravado
+func (c *UsageController) list(ctx *gin.Context) (time.Time, []*usage.Usage, bool) {
+	period, ok := c.period(ctx)
+	if !ok {
+		return time.Time{}, nil, false
+	}
+
+	if usage.IsCurrent(period) {
+		if err := c.usageRepo.CollectAll(ctx); err != nil {
+			c.logger.Error("erro ao medir consumo dos tenants", "error", err)
+		}
+	}
+
+	usages, err := c.usageRepo.List(ctx, period)
+	if err != nil {
+		c.respondUsageError(ctx, err)
+		return time.Time{}, nil, false
+	}
+	if usages == nil {
+		usages = []*usage.Usage{}
+	}
+	return period, usages, true
+}
+
+// period lê o período da query string; responde 400 se for inválido
+func (c *UsageController) period(ctx *gin.Context) (time.Time, bool) {
+	period, err := usage.ParsePeriod(ctx.Query("period"))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "período inválido", err.Error()))
+		return time.Time{}, false
+	}
+	return period, true
+}
+
+// respondUsageError converte erros da medição de consumo em respostas HTTP
+func (c *UsageController) respondUsageError(ctx *gin.Context, err error) {
+	switch {
+	case errors.Is(err, repository.ErrUsageNotFound), errors.Is(err, repository.ErrTenantNotFound):
+		ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "consumo não encontrado", err.Error()))
+	default:
+		c.logger.Error("erro ao consultar consumo", "error", err)
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "erro ao consultar consumo", err.Error()))
+	}
+}
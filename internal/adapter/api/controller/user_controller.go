@@ -10,6 +10,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/repository"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
 	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 	"github.com/hugohenrick/erp-supermercado/pkg/tenant"
@@ -37,6 +38,7 @@ func NewUserController(userRepository user.Repository) *UserController {
 // @Param user body dto.UserRequest true "Dados do usuário"
 // @Success 201 {object} dto.UserResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 402 {object} dto.ErrorResponse
 // @Failure 409 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /users [post]
@@ -90,6 +92,10 @@ func (c *UserController) Create(ctx *gin.Context) {
 			ctx.JSON(http.StatusConflict, dto.NewErrorResponse(http.StatusConflict, "Usuário com mesmo email já existe", ""))
 			return
 		}
+		if errors.Is(err, repository.ErrPlanLimitExceeded) {
+			ctx.JSON(http.StatusPaymentRequired, dto.NewErrorResponse(http.StatusPaymentRequired, "Limite de usuários do plano atingido", err.Error()))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao criar usuário", err.Error()))
 		return
 	}
@@ -194,6 +200,7 @@ func (c *UserController) List(ctx *gin.Context) {
 // @Param page query int false "Página"
 // @Param page_size query int false "Itens por página"
 // @Success 200 {object} dto.UserListResponse
+// @Failure 403 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /users/branch/{branch_id} [get]
 func (c *UserController) ListByBranch(ctx *gin.Context) {
@@ -202,6 +209,9 @@ func (c *UserController) ListByBranch(ctx *gin.Context) {
 		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "ID da filial não fornecido", ""))
 		return
 	}
+	if !authorizeBranch(ctx, branchID) {
+		return
+	}
 
 	pageStr := ctx.DefaultQuery("page", "1")
 	pageSizeStr := ctx.DefaultQuery("page_size", "10")
@@ -334,9 +344,9 @@ func (c *UserController) ChangePassword(ctx *gin.Context) {
 		return
 	}
 
-	// Verificar se o usuário atual é o mesmo que está sendo alterado ou é um administrador
-	userID, _, _, _, userRole, _ := auth.GetCurrentUser(ctx)
-	if userID != id && userRole != string(user.RoleAdmin) {
+	// Verificar se o usuário atual é o mesmo que está sendo alterado ou gerencia usuários
+	userID, _, _, _, _, _ := auth.GetCurrentUser(ctx)
+	if userID != id && !auth.HasPermission(ctx, rbac.PermUserManage) {
 		ctx.JSON(http.StatusForbidden, dto.NewErrorResponse(http.StatusForbidden, "Permissão negada", "Você só pode alterar sua própria senha"))
 		return
 	}
@@ -426,6 +436,7 @@ func (c *UserController) Delete(ctx *gin.Context) {
 // @Param status path string true "Novo status (active/inactive/blocked)"
 // @Success 200 {object} dto.UserResponse
 // @Failure 400 {object} dto.ErrorResponse
+// @Failure 402 {object} dto.ErrorResponse
 // @Failure 404 {object} dto.ErrorResponse
 // @Failure 500 {object} dto.ErrorResponse
 // @Router /users/{id}/status/{status} [patch]
@@ -465,6 +476,10 @@ func (c *UserController) UpdateStatus(ctx *gin.Context) {
 			ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Usuário não encontrado", ""))
 			return
 		}
+		if errors.Is(err, repository.ErrPlanLimitExceeded) {
+			ctx.JSON(http.StatusPaymentRequired, dto.NewErrorResponse(http.StatusPaymentRequired, "Limite de usuários do plano atingido", err.Error()))
+			return
+		}
 		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao atualizar status", err.Error()))
 		return
 	}
@@ -479,6 +494,98 @@ func (c *UserController) UpdateStatus(ctx *gin.Context) {
 	ctx.JSON(http.StatusOK, dto.ToUserResponse(u))
 }
 
+// GetBranches lista as filiais em que o usuário trabalha
+// @Summary Lista as filiais do usuário
+// @Description Lista os vínculos do usuário com filiais e o papel que ele exerce em cada uma, incluindo a filial principal
+// @Tags users
+// @Produce json
+// @Param tenant-id header string true "ID do tenant"
+// @Param id path string true "ID do usuário"
+// @Success 200 {object} dto.UserBranchesResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/branches [get]
+func (c *UserController) GetBranches(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	if _, err := c.userRepository.FindByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Usuário não encontrado", ""))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar usuário", err.Error()))
+		return
+	}
+
+	memberships, err := c.userRepository.ListMemberships(ctx, id)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar filiais do usuário", err.Error()))
+		return
+	}
+	if memberships == nil {
+		memberships = []user.Membership{}
+	}
+
+	ctx.JSON(http.StatusOK, dto.UserBranchesResponse{UserID: id, Branches: memberships})
+}
+
+// SetBranches substitui as filiais em que o usuário trabalha
+// @Summary Define as filiais do usuário
+// @Description Substitui os vínculos do usuário com filiais. A filial principal continua vinculada, com o papel do cadastro do usuário
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param tenant-id header string true "ID do tenant"
+// @Param id path string true "ID do usuário"
+// @Param branches body dto.UserBranchesRequest true "Filiais e papéis"
+// @Success 200 {object} dto.UserBranchesResponse
+// @Failure 400 {object} dto.ErrorResponse
+// @Failure 404 {object} dto.ErrorResponse
+// @Failure 500 {object} dto.ErrorResponse
+// @Router /users/{id}/branches [put]
+func (c *UserController) SetBranches(ctx *gin.Context) {
+	var request dto.UserBranchesRequest
+	if err := ctx.ShouldBindJSON(&request); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Requisição inválida", err.Error()))
+		return
+	}
+
+	id := ctx.Param("id")
+	memberships := make([]user.Membership, 0, len(request.Branches))
+	for _, b := range request.Branches {
+		m, err := user.NewMembership(id, b.BranchID, user.Role(b.Role))
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Requisição inválida", err.Error()))
+			return
+		}
+		memberships = append(memberships, *m)
+	}
+	if err := user.ValidateMemberships(memberships); err != nil {
+		ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Requisição inválida", err.Error()))
+		return
+	}
+
+	if _, err := c.userRepository.FindByID(ctx, id); err != nil {
+		if errors.Is(err, repository.ErrUserNotFound) {
+			ctx.JSON(http.StatusNotFound, dto.NewErrorResponse(http.StatusNotFound, "Usuário não encontrado", ""))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao buscar usuário", err.Error()))
+		return
+	}
+
+	if err := c.userRepository.ReplaceMemberships(ctx, id, memberships); err != nil {
+		if errors.Is(err, repository.ErrMembershipBranch) {
+			ctx.JSON(http.StatusBadRequest, dto.NewErrorResponse(http.StatusBadRequest, "Filial inválida", err.Error()))
+			return
+		}
+		ctx.JSON(http.StatusInternalServerError, dto.NewErrorResponse(http.StatusInternalServerError, "Erro ao atualizar filiais do usuário", err.Error()))
+		return
+	}
+
+	c.GetBranches(ctx)
+}
+
 // CreateAdminUser cria o primeiro usuário administrador para um tenant
 // @Summary Cria o primeiro usuário administrador
 // @Description Cria o primeiro usuário administrador para um tenant (não requer autenticação)
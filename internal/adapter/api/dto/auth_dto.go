@@ -2,6 +2,8 @@ package dto
 
 import (
 	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
 )
 
 // LoginRequest representa os dados para login
@@ -17,6 +19,35 @@ type LoginResponse struct {
 	AccessToken  string       `json:"access_token"`
 	RefreshToken string       `json:"refresh_token"`
 	ExpiresAt    time.Time    `json:"expires_at"`
+
+	// Filial ativa do token e o papel do usuário nela
+	BranchID string `json:"branch_id,omitempty"`
+	Role     string `json:"role,omitempty"`
+}
+
+// LoginTenantOption é um tenant que o usuário pode escolher no login
+type LoginTenantOption struct {
+	TenantID   string `json:"tenant_id"`
+	TenantName string `json:"tenant_name"`
+}
+
+// LoginTenantSelectionResponse é retornada quando o email e a senha valem em mais de um tenant:
+// o login deve ser repetido informando o tenant_id escolhido
+type LoginTenantSelectionResponse struct {
+	Message string              `json:"message"`
+	Tenants []LoginTenantOption `json:"tenants"`
+}
+
+// ToLoginTenantSelectionResponse monta a lista de tenants para escolha
+func ToLoginTenantSelectionResponse(logins []user.Login) LoginTenantSelectionResponse {
+	tenants := make([]LoginTenantOption, len(logins))
+	for i, l := range logins {
+		tenants[i] = LoginTenantOption{TenantID: l.TenantID, TenantName: l.TenantName}
+	}
+	return LoginTenantSelectionResponse{
+		Message: "Usuário cadastrado em mais de uma empresa, informe o tenant_id",
+		Tenants: tenants,
+	}
 }
 
 // RefreshTokenRequest representa os dados para renovação de token
@@ -30,3 +61,8 @@ type RefreshTokenResponse struct {
 	RefreshToken string    `json:"refresh_token"`
 	ExpiresAt    time.Time `json:"expires_at"`
 }
+
+// SwitchBranchRequest representa a troca da filial ativa do usuário autenticado
+type SwitchBranchRequest struct {
+	BranchID string `json:"branch_id" binding:"required"`
+}
@@ -0,0 +1,57 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/banking"
+)
+
+// BankAccountRequest representa os dados de uma conta bancária
+type BankAccountRequest struct {
+	BranchID       string    `json:"branch_id,omitempty"` // Vazio para conta da empresa
+	Name           string    `json:"name" binding:"required,max=100"`
+	BankCode       string    `json:"bank_code,omitempty" binding:"omitempty,len=3"`
+	Agency         string    `json:"agency,omitempty" binding:"max=10"`
+	AccountNumber  string    `json:"account_number,omitempty" binding:"max=20"`
+	Type           string    `json:"type" binding:"required,oneof=checking savings investment"`
+	OpeningBalance float64   `json:"opening_balance"`
+	OpeningDate    time.Time `json:"opening_date"`
+	Active         *bool     `json:"active,omitempty"`
+}
+
+// BankMatchRequest representa a conciliação manual de um lançamento do extrato.
+// Sem payment_id, o título em aberto é baixado com a data e o valor do lançamento
+type BankMatchRequest struct {
+	Kind      string `json:"kind" binding:"required,oneof=receivable payable"`
+	TitleID   string `json:"title_id" binding:"required"`
+	PaymentID string `json:"payment_id,omitempty"`
+}
+
+// BankImportResponse resume a importação de um extrato OFX
+type BankImportResponse struct {
+	Import   *banking.Import `json:"import"`
+	Inserted int             `json:"inserted"`
+	Matched  int             `json:"matched"`
+	Pending  int             `json:"pending"`
+	Failed   []string        `json:"failed,omitempty"` // Lançamentos cuja conciliação automática falhou
+}
+
+// BankTransactionListResponse representa a resposta paginada de lançamentos do extrato
+type BankTransactionListResponse struct {
+	Items      []*banking.Transaction `json:"items"`
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	Size       int                    `json:"size"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+// ToBankTransactionListResponse converte uma lista de lançamentos do extrato para DTO paginado
+func ToBankTransactionListResponse(transactions []*banking.Transaction, total, page, size int) *BankTransactionListResponse {
+	return &BankTransactionListResponse{
+		Items:      transactions,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
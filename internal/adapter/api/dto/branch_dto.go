@@ -16,6 +16,8 @@ type AddressRequest struct {
 	State      string `json:"state"`
 	ZipCode    string `json:"zip_code"`
 	Country    string `json:"country"`
+	CityCode   string `json:"city_code"`  // Código IBGE do município; preenchido pela cidade/UF ou pelo CEP quando vazio
+	StateCode  string `json:"state_code"` // Código IBGE da UF
 }
 
 // BranchRequest representa a estrutura de dados para criação/atualização de filial
@@ -40,6 +42,8 @@ type AddressResponse struct {
 	State      string `json:"state"`
 	ZipCode    string `json:"zip_code"`
 	Country    string `json:"country"`
+	CityCode   string `json:"city_code"`  // Código IBGE do município; preenchido pela cidade/UF ou pelo CEP quando vazio
+	StateCode  string `json:"state_code"` // Código IBGE da UF
 }
 
 // BranchResponse representa a estrutura de resposta para filial
@@ -88,6 +92,8 @@ func ToBranchResponse(b *branch.Branch) BranchResponse {
 			State:      b.Address.State,
 			ZipCode:    b.Address.ZipCode,
 			Country:    b.Address.Country,
+			CityCode:   b.Address.CityCode,
+			StateCode:  b.Address.StateCode,
 		},
 		Status:    string(b.Status),
 		IsMain:    b.IsMain,
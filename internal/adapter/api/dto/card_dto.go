@@ -0,0 +1,82 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/card"
+)
+
+// CardFeeRequest representa a taxa contratada com a adquirente
+type CardFeeRequest struct {
+	Acquirer         string  `json:"acquirer" binding:"required,max=30"`
+	Brand            string  `json:"brand" binding:"required,max=30"`
+	Product          string  `json:"product" binding:"required,oneof=credit debit"`
+	InstallmentsFrom int     `json:"installments_from" binding:"required,min=1"`
+	InstallmentsTo   int     `json:"installments_to" binding:"required,min=1"`
+	FeePercent       float64 `json:"fee_percent"`
+	SettlementDays   int     `json:"settlement_days"`
+	Active           *bool   `json:"active,omitempty"`
+}
+
+// CardSaleRequest representa um pagamento em cartão autorizado no TEF/POS.
+// Os campos do pagamento seguem domain.Payment
+type CardSaleRequest struct {
+	BranchID          string    `json:"branch_id,omitempty"`
+	SaleID            string    `json:"sale_id,omitempty"`
+	PaymentID         string    `json:"payment_id,omitempty"`
+	PaymentType       string    `json:"payment_type" binding:"required"` // Cartão crédito ou cartão débito
+	Amount            float64   `json:"amount" binding:"required,gt=0"`
+	CardBrand         string    `json:"card_brand" binding:"required"`
+	CardLastDigits    string    `json:"card_last_digits,omitempty" binding:"omitempty,len=4,numeric"`
+	Installments      int       `json:"installments,omitempty" binding:"omitempty,min=1,max=24"`
+	Acquirer          string    `json:"acquirer" binding:"required,max=30"`
+	NSU               string    `json:"nsu" binding:"required,max=20"`
+	AuthorizationCode string    `json:"authorization_code,omitempty" binding:"max=20"`
+	SoldAt            time.Time `json:"sold_at"`
+}
+
+// CardImportResponse resume a conciliação de um arquivo de liquidação da adquirente
+type CardImportResponse struct {
+	File   *card.ImportFile `json:"file"`
+	Failed []string         `json:"failed,omitempty"` // Registros cuja conciliação falhou
+}
+
+// CardSaleListResponse representa a resposta paginada de vendas no cartão
+type CardSaleListResponse struct {
+	Items      []*card.Sale `json:"items"`
+	Total      int          `json:"total"`
+	Page       int          `json:"page"`
+	Size       int          `json:"size"`
+	TotalPages int          `json:"total_pages"`
+}
+
+// ToCardSaleListResponse converte uma lista de vendas no cartão para DTO paginado
+func ToCardSaleListResponse(sales []*card.Sale, total, page, size int) *CardSaleListResponse {
+	return &CardSaleListResponse{
+		Items:      sales,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
+
+// CardSettlementListResponse representa a resposta paginada da agenda de recebimentos
+type CardSettlementListResponse struct {
+	Items      []*card.Settlement `json:"items"`
+	Total      int                `json:"total"`
+	Page       int                `json:"page"`
+	Size       int                `json:"size"`
+	TotalPages int                `json:"total_pages"`
+}
+
+// ToCardSettlementListResponse converte a agenda de recebimentos para DTO paginado
+func ToCardSettlementListResponse(settlements []*card.Settlement, total, page, size int) *CardSettlementListResponse {
+	return &CardSettlementListResponse{
+		Items:      settlements,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
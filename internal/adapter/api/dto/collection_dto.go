@@ -0,0 +1,55 @@
+package dto
+
+import (
+	"github.com/hugohenrick/erp-supermercado/internal/domain/collection"
+)
+
+// CollectionAccountRequest representa os dados de uma conta de cobrança
+type CollectionAccountRequest struct {
+	Name                string  `json:"name" binding:"required"`
+	BankCode            string  `json:"bank_code" binding:"required,len=3"`
+	Agency              string  `json:"agency" binding:"required"`
+	AgencyDigit         string  `json:"agency_digit,omitempty"`
+	Account             string  `json:"account" binding:"required"`
+	AccountDigit        string  `json:"account_digit,omitempty"`
+	Wallet              string  `json:"wallet" binding:"required"`
+	Agreement           string  `json:"agreement,omitempty"`
+	BeneficiaryName     string  `json:"beneficiary_name" binding:"required"`
+	BeneficiaryDocument string  `json:"beneficiary_document" binding:"required"`
+	Layout              string  `json:"layout" binding:"required,oneof=240 400"`
+	InterestRate        float64 `json:"interest_rate" binding:"gte=0"`
+	FinePercent         float64 `json:"fine_percent" binding:"gte=0"`
+	Instructions        string  `json:"instructions,omitempty"`
+	Active              *bool   `json:"active,omitempty"`
+}
+
+// BoletoRequest representa a emissão de um boleto para um título a receber
+type BoletoRequest struct {
+	AccountID    string `json:"account_id" binding:"required"`
+	ReceivableID string `json:"receivable_id" binding:"required"`
+}
+
+// RemittanceRequest representa a geração de um arquivo de remessa
+type RemittanceRequest struct {
+	AccountID string `json:"account_id" binding:"required"`
+}
+
+// BoletoListResponse representa a resposta paginada de boletos
+type BoletoListResponse struct {
+	Items      []*collection.Boleto `json:"items"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Size       int                  `json:"size"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// ToBoletoListResponse converte uma lista de boletos para DTO paginado
+func ToBoletoListResponse(boletos []*collection.Boleto, total, page, size int) *BoletoListResponse {
+	return &BoletoListResponse{
+		Items:      boletos,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -16,6 +16,8 @@ type CustomerAddressRequest struct {
 	State       string `json:"state" binding:"required"`
 	ZipCode     string `json:"zip_code" binding:"required"`
 	Country     string `json:"country" binding:"required"`
+	CityCode    string `json:"city_code"`  // Código IBGE do município; preenchido pela cidade/UF ou pelo CEP quando vazio
+	StateCode   string `json:"state_code"` // Código IBGE da UF
 	AddressType string `json:"address_type" binding:"required"`
 }
 
@@ -65,6 +67,8 @@ type CustomerAddressResponse struct {
 	State       string `json:"state"`
 	ZipCode     string `json:"zip_code"`
 	Country     string `json:"country"`
+	CityCode    string `json:"city_code"`
+	StateCode   string `json:"state_code"`
 	AddressType string `json:"address_type"`
 }
 
@@ -133,6 +137,8 @@ func ToCustomerResponse(c *customer.Customer) *CustomerResponse {
 			State:       addr.State,
 			ZipCode:     addr.ZipCode,
 			Country:     addr.Country,
+			CityCode:    addr.CityCode,
+			StateCode:   addr.StateCode,
 			AddressType: addr.AddressType,
 		}
 	}
@@ -197,3 +203,32 @@ func ToCustomerListResponse(customers []*customer.Customer, total, page, size, t
 		TotalPages: totalPages,
 	}
 }
+
+// CustomerImportResponse resume a importação de uma planilha de clientes
+type CustomerImportResponse struct {
+	DryRun    bool                   `json:"dry_run"`   // Apenas validou, sem gravar
+	Customers int                    `json:"customers"` // Clientes encontrados na planilha
+	Valid     int                    `json:"valid"`
+	Invalid   int                    `json:"invalid"`
+	Imported  int                    `json:"imported"`
+	Errors    []customer.ImportError `json:"errors"`
+}
+
+// ToCustomerImportResponse monta o relatório da importação
+func ToCustomerImportResponse(records []*customer.ImportRecord, imported int, dryRun bool) *CustomerImportResponse {
+	response := &CustomerImportResponse{
+		DryRun:    dryRun,
+		Customers: len(records),
+		Imported:  imported,
+		Errors:    make([]customer.ImportError, 0),
+	}
+	for _, r := range records {
+		if r.Valid() {
+			response.Valid++
+			continue
+		}
+		response.Invalid++
+		response.Errors = append(response.Errors, r.Errors...)
+	}
+	return response
+}
@@ -42,6 +42,14 @@ type FiscalConfigRequest struct {
 	PrinterPaperSize string           `json:"printer_paper_size,omitempty"`
 }
 
+// FiscalVoidRequest representa a inutilização de uma faixa de numeração da filial
+type FiscalVoidRequest struct {
+	Model         string `json:"model" binding:"required,oneof=nfe nfce"`
+	StartNumber   int    `json:"start_number" binding:"required,min=1"`
+	EndNumber     int    `json:"end_number" binding:"required,min=1"`
+	Justification string `json:"justification" binding:"required"`
+}
+
 // FiscalConfigResponse representa a resposta com dados de uma configuração fiscal
 type FiscalConfigResponse struct {
 	ID              string `json:"id"`
@@ -0,0 +1,108 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loss"
+)
+
+// LossReasonRequest representa os dados para criar/atualizar um motivo de perda
+type LossReasonRequest struct {
+	Code          string              `json:"code"`
+	Description   string              `json:"description" binding:"required"`
+	Category      loss.ReasonCategory `json:"category" binding:"required"`
+	RequiresPhoto bool                `json:"requires_photo"`
+	Active        *bool               `json:"active,omitempty"`
+}
+
+// LossItemRequest representa um produto perdido na requisição
+type LossItemRequest struct {
+	ProductID string  `json:"product_id" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"required,gt=0"`
+	Notes     string  `json:"notes,omitempty"`
+}
+
+// LossRequest representa os dados para registrar uma perda
+type LossRequest struct {
+	BranchID   string            `json:"branch_id"`
+	ReasonID   string            `json:"reason_id" binding:"required"`
+	OccurredAt *time.Time        `json:"occurred_at,omitempty"`
+	Notes      string            `json:"notes,omitempty"`
+	Photos     []string          `json:"photos,omitempty"`
+	Items      []LossItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+// LossPhotosRequest representa as fotos anexadas a uma perda pendente
+type LossPhotosRequest struct {
+	Photos []string `json:"photos" binding:"required,min=1"`
+}
+
+// LossRejectRequest representa os dados para rejeitar uma perda
+type LossRejectRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// LossResponse representa a resposta com os dados de uma perda
+type LossResponse struct {
+	ID              string      `json:"id"`
+	BranchID        string      `json:"branch_id"`
+	ReasonID        string      `json:"reason_id"`
+	Status          loss.Status `json:"status"`
+	Notes           string      `json:"notes,omitempty"`
+	Photos          []string    `json:"photos"`
+	Items           []loss.Item `json:"items"`
+	TotalCost       float64     `json:"total_cost"`
+	OccurredAt      time.Time   `json:"occurred_at"`
+	CreatedBy       string      `json:"created_by,omitempty"`
+	ReviewedBy      string      `json:"reviewed_by,omitempty"`
+	ReviewedAt      *time.Time  `json:"reviewed_at,omitempty"`
+	RejectionReason string      `json:"rejection_reason,omitempty"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+}
+
+// LossListResponse representa a resposta paginada de perdas
+type LossListResponse struct {
+	Items      []LossResponse `json:"items"`
+	Total      int            `json:"total"`
+	Page       int            `json:"page"`
+	Size       int            `json:"size"`
+	TotalPages int            `json:"total_pages"`
+}
+
+// ToLossResponse converte uma perda do domínio para DTO
+func ToLossResponse(l *loss.Loss) *LossResponse {
+	return &LossResponse{
+		ID:              l.ID,
+		BranchID:        l.BranchID,
+		ReasonID:        l.ReasonID,
+		Status:          l.Status,
+		Notes:           l.Notes,
+		Photos:          l.Photos,
+		Items:           l.Items,
+		TotalCost:       l.TotalCost,
+		OccurredAt:      l.OccurredAt,
+		CreatedBy:       l.CreatedBy,
+		ReviewedBy:      l.ReviewedBy,
+		ReviewedAt:      l.ReviewedAt,
+		RejectionReason: l.RejectionReason,
+		CreatedAt:       l.CreatedAt,
+		UpdatedAt:       l.UpdatedAt,
+	}
+}
+
+// ToLossListResponse converte uma lista de perdas para DTO paginado
+func ToLossListResponse(losses []*loss.Loss, total, page, size int) *LossListResponse {
+	items := make([]LossResponse, len(losses))
+	for i, l := range losses {
+		items[i] = *ToLossResponse(l)
+	}
+
+	return &LossListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -0,0 +1,88 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loyalty"
+)
+
+// LoyaltyProgramRequest representa a configuração do programa de fidelidade
+type LoyaltyProgramRequest struct {
+	Name            string  `json:"name" binding:"required,max=100"`
+	PointsPerReal   float64 `json:"points_per_real" binding:"min=0"`
+	PointValue      float64 `json:"point_value" binding:"required,gt=0"`
+	ExpirationDays  int     `json:"expiration_days" binding:"required,min=1"`
+	MinRedeemPoints int     `json:"min_redeem_points" binding:"min=0"`
+	Active          *bool   `json:"active,omitempty"`
+}
+
+// LoyaltyRuleRequest representa a pontuação de uma categoria de produtos
+type LoyaltyRuleRequest struct {
+	CategoryID    string  `json:"category_id" binding:"required"`
+	PointsPerReal float64 `json:"points_per_real" binding:"min=0"`
+	Active        *bool   `json:"active,omitempty"`
+}
+
+// LoyaltyCampaignRequest representa uma campanha que multiplica a pontuação
+type LoyaltyCampaignRequest struct {
+	Name       string    `json:"name" binding:"required,max=100"`
+	CategoryID string    `json:"category_id,omitempty"` // Vazio para todas as categorias
+	Multiplier float64   `json:"multiplier" binding:"required,gt=0"`
+	StartsAt   time.Time `json:"starts_at" binding:"required"`
+	EndsAt     time.Time `json:"ends_at" binding:"required"`
+	Active     *bool     `json:"active,omitempty"`
+}
+
+// LoyaltyRewardRequest representa um produto do catálogo de resgate
+type LoyaltyRewardRequest struct {
+	ProductID string `json:"product_id" binding:"required"`
+	Name      string `json:"name" binding:"required,max=100"`
+	Points    int    `json:"points" binding:"required,min=1"`
+	Active    *bool  `json:"active,omitempty"`
+}
+
+// LoyaltyEarnRequest representa a pontuação de uma venda finalizada no PDV.
+// O cliente é identificado pelo ID ou pelo CPF/CNPJ informado no caixa
+type LoyaltyEarnRequest struct {
+	CustomerID string         `json:"customer_id,omitempty"`
+	Document   string         `json:"document,omitempty"`
+	BranchID   string         `json:"branch_id,omitempty"`
+	SaleID     string         `json:"sale_id" binding:"required"`
+	Items      []loyalty.Item `json:"items" binding:"required,min=1"`
+}
+
+// LoyaltyRedeemRequest representa o resgate de pontos no PDV, como pagamento ou troca por prêmio
+type LoyaltyRedeemRequest struct {
+	CustomerID string `json:"customer_id,omitempty"`
+	Document   string `json:"document,omitempty"`
+	BranchID   string `json:"branch_id,omitempty"`
+	SaleID     string `json:"sale_id,omitempty"`
+	Points     int    `json:"points,omitempty" binding:"min=0"` // Pontos usados como pagamento
+	RewardID   string `json:"reward_id,omitempty"`              // Prêmio trocado pelos pontos
+}
+
+// LoyaltyEntryResponse retorna o lançamento gravado e o saldo atualizado do cliente
+type LoyaltyEntryResponse struct {
+	Entry   *loyalty.Entry   `json:"entry"`
+	Balance *loyalty.Balance `json:"balance"`
+}
+
+// LoyaltyEntryListResponse representa a resposta paginada do extrato de pontos
+type LoyaltyEntryListResponse struct {
+	Items      []*loyalty.Entry `json:"items"`
+	Total      int              `json:"total"`
+	Page       int              `json:"page"`
+	Size       int              `json:"size"`
+	TotalPages int              `json:"total_pages"`
+}
+
+// ToLoyaltyEntryListResponse converte o extrato de pontos para DTO paginado
+func ToLoyaltyEntryListResponse(entries []*loyalty.Entry, total, page, size int) *LoyaltyEntryListResponse {
+	return &LoyaltyEntryListResponse{
+		Items:      entries,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
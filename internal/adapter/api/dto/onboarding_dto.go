@@ -0,0 +1,52 @@
+package dto
+
+import (
+	"github.com/hugohenrick/erp-supermercado/internal/domain/onboarding"
+)
+
+// OnboardingRequest representa o cadastro inicial completo de um tenant
+type OnboardingRequest struct {
+	Tenant            TenantRequest           `json:"tenant" binding:"required"`
+	Branch            OnboardingBranchRequest `json:"branch" binding:"required"`
+	Admin             OnboardingAdminRequest  `json:"admin" binding:"required"`
+	FiscalEnvironment string                  `json:"fiscal_environment" binding:"omitempty,oneof=production homologation"` // Padrão: homologation
+}
+
+// OnboardingBranchRequest representa a matriz do tenant no cadastro inicial
+type OnboardingBranchRequest struct {
+	Name     string         `json:"name" binding:"required"`
+	Code     string         `json:"code"` // Padrão: MATRIZ
+	Document string         `json:"document"`
+	Phone    string         `json:"phone"`
+	Email    string         `json:"email"`
+	Address  AddressRequest `json:"address"`
+}
+
+// OnboardingAdminRequest representa o primeiro administrador do tenant
+type OnboardingAdminRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required,min=6"`
+}
+
+// OnboardingResponse representa o resultado do cadastro inicial
+type OnboardingResponse struct {
+	Tenant         TenantResponse `json:"tenant"`
+	Branch         BranchResponse `json:"branch"`
+	Admin          UserResponse   `json:"admin"`
+	FiscalConfigID string         `json:"fiscal_config_id"`
+	Categories     int            `json:"categories"`      // Categorias de produtos criadas
+	PaymentMethods int            `json:"payment_methods"` // Formas de pagamento criadas
+}
+
+// ToOnboardingResponse converte o cadastro inicial gravado para DTO de resposta
+func ToOnboardingResponse(o *onboarding.Onboarding) OnboardingResponse {
+	return OnboardingResponse{
+		Tenant:         ToTenantResponse(o.Tenant),
+		Branch:         ToBranchResponse(o.Branch),
+		Admin:          ToUserResponse(o.Admin),
+		FiscalConfigID: o.FiscalConfig.ID,
+		Categories:     len(o.Categories),
+		PaymentMethods: len(o.PaymentMethods),
+	}
+}
@@ -0,0 +1,86 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/payable"
+)
+
+// PayableRequest representa os dados de um título a pagar manual
+type PayableRequest struct {
+	BranchID       string    `json:"branch_id"`
+	SupplierID     string    `json:"supplier_id" binding:"required"`
+	DocumentNumber string    `json:"document_number,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	Amount         float64   `json:"amount" binding:"required,gt=0"`
+	IssueDate      time.Time `json:"issue_date"`
+	DueDate        time.Time `json:"due_date" binding:"required"`
+}
+
+// PurchasePayableRequest representa uma compra recebida que deve gerar títulos a pagar
+type PurchasePayableRequest struct {
+	BranchID       string    `json:"branch_id"`
+	PurchaseID     string    `json:"purchase_id" binding:"required"`
+	SupplierID     string    `json:"supplier_id" binding:"required"`
+	DocumentNumber string    `json:"document_number,omitempty"` // Número da NF de entrada
+	Total          float64   `json:"total" binding:"required,gt=0"`
+	ReceivedAt     time.Time `json:"received_at"`
+	PaymentTerm    string    `json:"payment_term,omitempty"` // Substitui o prazo cadastrado no fornecedor
+}
+
+// PayablePaymentRequest representa uma baixa parcial ou total de um título
+type PayablePaymentRequest struct {
+	Amount   float64   `json:"amount" binding:"required,gt=0"` // Principal abatido
+	Interest float64   `json:"interest"`
+	Fine     float64   `json:"fine"`
+	Discount float64   `json:"discount"`
+	PaidAt   time.Time `json:"paid_at"`
+	Method   string    `json:"method,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+}
+
+// PayableCancelRequest representa os dados para cancelar um título
+type PayableCancelRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// PayableResponse representa um título a pagar com seu saldo
+type PayableResponse struct {
+	*payable.Payable
+	Balance float64 `json:"balance"`
+	Overdue bool    `json:"overdue"`
+}
+
+// PayableListResponse representa a resposta paginada de títulos a pagar
+type PayableListResponse struct {
+	Items      []PayableResponse `json:"items"`
+	Total      int               `json:"total"`
+	Page       int               `json:"page"`
+	Size       int               `json:"size"`
+	TotalPages int               `json:"total_pages"`
+}
+
+// ToPayableResponse converte um título do domínio para DTO
+func ToPayableResponse(p *payable.Payable) *PayableResponse {
+	return &PayableResponse{
+		Payable: p,
+		Balance: p.Balance(),
+		Overdue: p.IsOverdue(time.Now()),
+	}
+}
+
+// ToPayableListResponse converte uma lista de títulos para DTO paginado
+func ToPayableListResponse(payables []*payable.Payable, total, page, size int) *PayableListResponse {
+	items := make([]PayableResponse, len(payables))
+	for i, p := range payables {
+		items[i] = *ToPayableResponse(p)
+	}
+
+	return &PayableListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -0,0 +1,56 @@
+package dto
+
+import "github.com/hugohenrick/erp-supermercado/internal/domain/paymentmethod"
+
+// PaymentMethodRequest representa os dados de uma forma de pagamento. Campos omitidos assumem
+// os padrões do tipo: tPag correspondente, troco apenas em dinheiro e TEF para cartões
+type PaymentMethodRequest struct {
+	Name            string `json:"name" binding:"required,max=60"`
+	Kind            string `json:"kind" binding:"required,oneof=cash debit credit pix voucher on_account meal_voucher"`
+	TPag            string `json:"tpag,omitempty" binding:"omitempty,len=2"`
+	MaxInstallments int    `json:"max_installments,omitempty"`
+	ChangeAllowed   *bool  `json:"change_allowed,omitempty"`
+	RequiresTEF     *bool  `json:"requires_tef,omitempty"`
+	Active          *bool  `json:"active,omitempty"`
+}
+
+// PaymentCheckItem representa um pagamento informado no PDV
+type PaymentCheckItem struct {
+	PaymentMethodID string  `json:"payment_method_id" binding:"required"`
+	Amount          float64 `json:"amount" binding:"required,gt=0"`
+	Tendered        float64 `json:"tendered,omitempty"` // Valor entregue, quando há troco
+	Installments    int     `json:"installments,omitempty"`
+}
+
+// PaymentCheckRequest representa os pagamentos de uma venda a conferir antes da emissão da NFC-e
+type PaymentCheckRequest struct {
+	Total      float64            `json:"total" binding:"required,gt=0"`
+	CustomerID string             `json:"customer_id,omitempty"`
+	Payments   []PaymentCheckItem `json:"payments" binding:"required,min=1,dive"`
+}
+
+// PaymentCheckResponse retorna os pagamentos conferidos no formato do grupo de pagamento da NFC-e
+type PaymentCheckResponse struct {
+	Payments []*paymentmethod.Payment `json:"payments"`
+	Change   float64                  `json:"change"` // vTroco
+}
+
+// PaymentMethodListResponse representa a resposta paginada de formas de pagamento
+type PaymentMethodListResponse struct {
+	Items      []*paymentmethod.PaymentMethod `json:"items"`
+	Total      int                            `json:"total"`
+	Page       int                            `json:"page"`
+	Size       int                            `json:"size"`
+	TotalPages int                            `json:"total_pages"`
+}
+
+// ToPaymentMethodListResponse converte uma lista de formas de pagamento para DTO paginado
+func ToPaymentMethodListResponse(methods []*paymentmethod.PaymentMethod, total, page, size int) *PaymentMethodListResponse {
+	return &PaymentMethodListResponse{
+		Items:      methods,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -0,0 +1,63 @@
+package dto
+
+import (
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pix"
+)
+
+// PixSettingsRequest representa a configuração de recebimento via Pix
+type PixSettingsRequest struct {
+	Key           string `json:"key" binding:"required,max=77"`
+	MerchantName  string `json:"merchant_name" binding:"required,max=25"`
+	MerchantCity  string `json:"merchant_city" binding:"required,max=15"`
+	PostalCode    string `json:"postal_code,omitempty"`
+	Provider      string `json:"provider" binding:"omitempty,oneof=fake api"`
+	BaseURL       string `json:"base_url,omitempty"`
+	ClientID      string `json:"client_id,omitempty"`
+	ClientSecret  string `json:"client_secret,omitempty"`  // Vazio mantém o segredo já gravado
+	WebhookSecret string `json:"webhook_secret,omitempty"` // Vazio mantém o segredo já gravado
+	ExpiresIn     int    `json:"expires_in" binding:"gte=0"`
+}
+
+// PixChargeRequest representa a criação de uma cobrança Pix
+type PixChargeRequest struct {
+	BranchID     string  `json:"branch_id,omitempty"`
+	Kind         string  `json:"kind" binding:"required,oneof=static dynamic"`
+	Amount       float64 `json:"amount" binding:"gte=0"` // Opcional para títulos: padrão é o saldo em aberto
+	SaleID       string  `json:"sale_id,omitempty"`
+	ReceivableID string  `json:"receivable_id,omitempty"`
+	Description  string  `json:"description,omitempty" binding:"max=140"`
+}
+
+// PixSimulatePaymentRequest representa o pagamento simulado de uma cobrança no PSP de testes
+type PixSimulatePaymentRequest struct {
+	Amount float64 `json:"amount" binding:"gte=0"` // Zero paga o valor da cobrança
+}
+
+// PixWebhookResponse resume o processamento de uma notificação do PSP
+type PixWebhookResponse struct {
+	Received  int `json:"received"`
+	Settled   int `json:"settled"`
+	Duplicate int `json:"duplicate"`
+	Unmatched int `json:"unmatched"`
+	Rejected  int `json:"rejected"`
+}
+
+// PixChargeListResponse representa a resposta paginada de cobranças Pix
+type PixChargeListResponse struct {
+	Items      []*pix.Charge `json:"items"`
+	Total      int           `json:"total"`
+	Page       int           `json:"page"`
+	Size       int           `json:"size"`
+	TotalPages int           `json:"total_pages"`
+}
+
+// ToPixChargeListResponse converte uma lista de cobranças Pix para DTO paginado
+func ToPixChargeListResponse(charges []*pix.Charge, total, page, size int) *PixChargeListResponse {
+	return &PixChargeListResponse{
+		Items:      charges,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -0,0 +1,65 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/platform"
+)
+
+// PlatformLoginRequest representa os dados para login de um operador da plataforma
+type PlatformLoginRequest struct {
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// PlatformLoginResponse representa a resposta de login de um operador
+type PlatformLoginResponse struct {
+	Operator    OperatorResponse `json:"operator"`
+	AccessToken string           `json:"access_token"`
+	ExpiresAt   time.Time        `json:"expires_at"`
+}
+
+// OperatorRequest representa os dados para cadastro de um operador da plataforma
+type OperatorRequest struct {
+	Name     string `json:"name" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Password string `json:"password" binding:"required"`
+}
+
+// OperatorStatusRequest representa a ativação ou desativação de um operador
+type OperatorStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active inactive"`
+}
+
+// OperatorResponse representa um operador da plataforma nas respostas
+type OperatorResponse struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Status      string     `json:"status"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// ToOperatorResponse converte um operador em OperatorResponse
+func ToOperatorResponse(o *platform.Operator) OperatorResponse {
+	return OperatorResponse{
+		ID:          o.ID,
+		Name:        o.Name,
+		Email:       o.Email,
+		Status:      string(o.Status),
+		LastLoginAt: o.LastLoginAt,
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+	}
+}
+
+// ToOperatorResponseList converte uma lista de operadores
+func ToOperatorResponseList(operators []*platform.Operator) []OperatorResponse {
+	responses := make([]OperatorResponse, len(operators))
+	for i, o := range operators {
+		responses[i] = ToOperatorResponse(o)
+	}
+	return responses
+}
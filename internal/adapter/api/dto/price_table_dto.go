@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pricetable"
+)
+
+// PriceTableRequest representa os dados de uma tabela de preços. Produtos em items têm preço fixo;
+// os demais recebem o percentual sobre o preço de venda
+type PriceTableRequest struct {
+	Name          string                  `json:"name" binding:"required,max=100"`
+	Percentage    float64                 `json:"percentage"`
+	BranchIDs     []string                `json:"branch_ids,omitempty"`     // Vazio para todas as filiais
+	CustomerTypes []customer.CustomerType `json:"customer_types,omitempty"` // Tabela padrão para esses tipos de cliente
+	ValidFrom     time.Time               `json:"valid_from" binding:"required"`
+	ValidUntil    *time.Time              `json:"valid_until,omitempty"`
+	Active        *bool                   `json:"active,omitempty"`
+	Items         []pricetable.Item       `json:"items,omitempty"`
+}
+
+// PriceResolveRequest representa os produtos a precificar para um cliente na filial
+type PriceResolveRequest struct {
+	BranchID   string             `json:"branch_id,omitempty"`
+	CustomerID string             `json:"customer_id,omitempty"`
+	Document   string             `json:"document,omitempty"`
+	Items      []pricetable.Quote `json:"items" binding:"required,min=1"`
+}
+
+// PriceResolveResponse retorna os preços resolvidos e a tabela aplicada
+type PriceResolveResponse struct {
+	CustomerID     string                  `json:"customer_id,omitempty"`
+	PriceTableID   string                  `json:"price_table_id,omitempty"`
+	PriceTableName string                  `json:"price_table_name,omitempty"`
+	Items          []pricetable.QuotedItem `json:"items"`
+	Total          float64                 `json:"total"`
+}
+
+// PriceTableListResponse representa a resposta paginada de tabelas de preços
+type PriceTableListResponse struct {
+	Items      []*pricetable.PriceTable `json:"items"`
+	Total      int                      `json:"total"`
+	Page       int                      `json:"page"`
+	Size       int                      `json:"size"`
+	TotalPages int                      `json:"total_pages"`
+}
+
+// ToPriceTableListResponse converte uma lista de tabelas de preços para DTO paginado
+func ToPriceTableListResponse(tables []*pricetable.PriceTable, total, page, size int) *PriceTableListResponse {
+	return &PriceTableListResponse{
+		Items:      tables,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -0,0 +1,84 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/promotion"
+)
+
+// PromotionRequest representa os dados de uma promoção. Os campos usados dependem do tipo:
+// take_pay (product_ids, take_quantity, pay_quantity), tiered (product_ids, tiers),
+// combo (combo_items, combo_price) e price (product_ids, price)
+type PromotionRequest struct {
+	Name         string                `json:"name" binding:"required,max=100"`
+	Type         string                `json:"type" binding:"required,oneof=take_pay tiered combo price"`
+	BranchIDs    []string              `json:"branch_ids,omitempty"` // Vazio para todas as filiais
+	ProductIDs   []string              `json:"product_ids,omitempty"`
+	TakeQuantity int                   `json:"take_quantity,omitempty"`
+	PayQuantity  int                   `json:"pay_quantity,omitempty"`
+	Tiers        []promotion.Tier      `json:"tiers,omitempty"`
+	ComboItems   []promotion.ComboItem `json:"combo_items,omitempty"`
+	ComboPrice   float64               `json:"combo_price,omitempty"`
+	Price        float64               `json:"price,omitempty"`
+	MembersOnly  bool                  `json:"members_only"`
+	Priority     int                   `json:"priority"`
+	StartsAt     time.Time             `json:"starts_at" binding:"required"`
+	EndsAt       time.Time             `json:"ends_at" binding:"required"`
+	Active       *bool                 `json:"active,omitempty"`
+}
+
+// PromotionEvaluateRequest representa a venda em andamento no PDV, com os preços de venda base dos itens.
+// O cliente identificado por ID ou CPF/CNPJ recebe os preços da sua tabela e os exclusivos do clube.
+// O desconto manual acima de 10% exige a permissão pdv.discount.above_10
+type PromotionEvaluateRequest struct {
+	BranchID       string                     `json:"branch_id,omitempty"`
+	CustomerID     string                     `json:"customer_id,omitempty"`
+	Document       string                     `json:"document,omitempty"`
+	ManualDiscount float64                    `json:"manual_discount,omitempty"` // Desconto do operador sobre o total (%)
+	Items          []PromotionCartItemRequest `json:"items" binding:"required,min=1,max=1000,dive"`
+}
+
+// PromotionCartItemRequest representa um item registrado no PDV, com a quantidade limitada a
+// promotion.MaxItemQuantity
+type PromotionCartItemRequest struct {
+	ProductID string  `json:"product_id" binding:"required"`
+	Quantity  float64 `json:"quantity" binding:"gt=0,lte=99999"`
+	UnitPrice float64 `json:"unit_price" binding:"gte=0"`
+}
+
+// CartItems converte os itens da requisição para o carrinho do motor de promoções
+func (r *PromotionEvaluateRequest) CartItems() []promotion.CartItem {
+	items := make([]promotion.CartItem, 0, len(r.Items))
+	for _, item := range r.Items {
+		items = append(items, promotion.CartItem{ProductID: item.ProductID, Quantity: item.Quantity, UnitPrice: item.UnitPrice})
+	}
+	return items
+}
+
+// PromotionEvaluateResponse retorna os itens com desconto e as promoções aplicadas
+type PromotionEvaluateResponse struct {
+	*promotion.Result
+	CustomerID   string `json:"customer_id,omitempty"`
+	Member       bool   `json:"member"`                   // Cliente identificado e ativo, com direito aos preços do clube
+	PriceTableID string `json:"price_table_id,omitempty"` // Tabela de preços do cliente aplicada antes das promoções
+}
+
+// PromotionListResponse representa a resposta paginada de promoções
+type PromotionListResponse struct {
+	Items      []*promotion.Promotion `json:"items"`
+	Total      int                    `json:"total"`
+	Page       int                    `json:"page"`
+	Size       int                    `json:"size"`
+	TotalPages int                    `json:"total_pages"`
+}
+
+// ToPromotionListResponse converte uma lista de promoções para DTO paginado
+func ToPromotionListResponse(promotions []*promotion.Promotion, total, page, size int) *PromotionListResponse {
+	return &PromotionListResponse{
+		Items:      promotions,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
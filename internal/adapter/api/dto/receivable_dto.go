@@ -0,0 +1,97 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+)
+
+// ReceivableRequest representa os dados de um título a receber manual
+type ReceivableRequest struct {
+	BranchID       string    `json:"branch_id"`
+	CustomerID     string    `json:"customer_id" binding:"required"`
+	DocumentNumber string    `json:"document_number,omitempty"`
+	Description    string    `json:"description,omitempty"`
+	Amount         float64   `json:"amount" binding:"required,gt=0"`
+	IssueDate      time.Time `json:"issue_date"`
+	DueDate        time.Time `json:"due_date" binding:"required"`
+}
+
+// CreditSaleRequest representa uma venda a prazo que deve gerar títulos a receber
+type CreditSaleRequest struct {
+	BranchID       string    `json:"branch_id"`
+	SaleID         string    `json:"sale_id" binding:"required"`
+	CustomerID     string    `json:"customer_id" binding:"required"`
+	DocumentNumber string    `json:"document_number,omitempty"` // Número do cupom ou NF de saída
+	Total          float64   `json:"total" binding:"required,gt=0"`
+	Installments   int       `json:"installments"` // Padrão: 1
+	SoldAt         time.Time `json:"sold_at"`
+}
+
+// ReceivablePaymentRequest representa um recebimento parcial ou total de um título
+type ReceivablePaymentRequest struct {
+	Amount   float64   `json:"amount" binding:"required,gt=0"` // Principal abatido
+	Interest float64   `json:"interest"`
+	Fine     float64   `json:"fine"`
+	Discount float64   `json:"discount"`
+	PaidAt   time.Time `json:"paid_at"`
+	Method   string    `json:"method,omitempty"`
+	Notes    string    `json:"notes,omitempty"`
+}
+
+// ReceivableCancelRequest representa os dados para cancelar um título
+type ReceivableCancelRequest struct {
+	Reason string `json:"reason" binding:"required"`
+}
+
+// ReceivableSettingsRequest representa a configuração de contas a receber do tenant
+type ReceivableSettingsRequest struct {
+	BlockAfterDays *int `json:"block_after_days" binding:"required,gte=0"`
+}
+
+// BlockOverdueResponse representa o resultado do bloqueio de clientes inadimplentes
+type BlockOverdueResponse struct {
+	Days    int      `json:"days"`
+	Blocked []string `json:"blocked"`
+}
+
+// ReceivableResponse representa um título a receber com seu saldo
+type ReceivableResponse struct {
+	*receivable.Receivable
+	Balance float64 `json:"balance"`
+	Overdue bool    `json:"overdue"`
+}
+
+// ReceivableListResponse representa a resposta paginada de títulos a receber
+type ReceivableListResponse struct {
+	Items      []ReceivableResponse `json:"items"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Size       int                  `json:"size"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// ToReceivableResponse converte um título do domínio para DTO
+func ToReceivableResponse(r *receivable.Receivable) *ReceivableResponse {
+	return &ReceivableResponse{
+		Receivable: r,
+		Balance:    r.Balance(),
+		Overdue:    r.IsOverdue(time.Now()),
+	}
+}
+
+// ToReceivableListResponse converte uma lista de títulos para DTO paginado
+func ToReceivableListResponse(receivables []*receivable.Receivable, total, page, size int) *ReceivableListResponse {
+	items := make([]ReceivableResponse, len(receivables))
+	for i, r := range receivables {
+		items[i] = *ToReceivableResponse(r)
+	}
+
+	return &ReceivableListResponse{
+		Items:      items,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -0,0 +1,35 @@
+package dto
+
+import "github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+
+// RoleRequest representa o cadastro ou a alteração de um perfil de acesso
+type RoleRequest struct {
+	Name        string            `json:"name" binding:"required"`
+	Description string            `json:"description"`
+	Permissions []rbac.Permission `json:"permissions" binding:"required,min=1"`
+}
+
+// AssignmentRequest atribui um perfil ao usuário em uma filial. Sem filial, vale em todas
+type AssignmentRequest struct {
+	RoleID   string `json:"role_id" binding:"required"`
+	BranchID string `json:"branch_id"`
+}
+
+// UserRolesRequest substitui os perfis atribuídos a um usuário
+type UserRolesRequest struct {
+	Assignments []AssignmentRequest `json:"assignments" binding:"dive"`
+}
+
+// UserRolesResponse lista os perfis atribuídos a um usuário
+type UserRolesResponse struct {
+	UserID      string             `json:"user_id"`
+	Assignments []*rbac.Assignment `json:"assignments"`
+}
+
+// MyPermissionsResponse lista as permissões efetivas do usuário autenticado na filial ativa
+type MyPermissionsResponse struct {
+	UserID      string            `json:"user_id"`
+	Role        string            `json:"role"`
+	BranchID    string            `json:"branch_id,omitempty"`
+	Permissions []rbac.Permission `json:"permissions"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/salesman"
+)
+
+// SalesmanRequest representa os dados de um vendedor
+type SalesmanRequest struct {
+	UserID            string  `json:"user_id,omitempty"` // Usuário do sistema vinculado ao vendedor
+	Name              string  `json:"name" binding:"required,max=255"`
+	Document          string  `json:"document,omitempty"`
+	Email             string  `json:"email,omitempty" binding:"omitempty,email"`
+	Phone             string  `json:"phone,omitempty"`
+	CommissionPercent float64 `json:"commission_percent" binding:"min=0,max=100"`
+	Active            *bool   `json:"active,omitempty"`
+}
+
+// CommissionRuleRequest representa uma regra de comissão por categoria e/ou forma de pagamento
+type CommissionRuleRequest struct {
+	SalesmanID      string  `json:"salesman_id,omitempty"` // Vazio para todos os vendedores
+	CategoryID      string  `json:"category_id,omitempty"`
+	PaymentMethodID string  `json:"payment_method_id,omitempty"`
+	Percentage      float64 `json:"percentage" binding:"min=0,max=100"`
+}
+
+// CommissionAccrueRequest representa a venda finalizada que gera comissão. Sem salesman_id,
+// a comissão vai para o vendedor do cadastro do cliente
+type CommissionAccrueRequest struct {
+	SalesmanID string                 `json:"salesman_id,omitempty"`
+	CustomerID string                 `json:"customer_id,omitempty"`
+	BranchID   string                 `json:"branch_id,omitempty"`
+	SaleID     string                 `json:"sale_id" binding:"required,uuid"`
+	SaleDate   time.Time              `json:"sale_date,omitempty"`
+	Items      []salesman.SaleItem    `json:"items" binding:"required,min=1"`
+	Payments   []salesman.SalePayment `json:"payments,omitempty"`
+}
+
+// CommissionReverseRequest representa o cancelamento da venda
+type CommissionReverseRequest struct {
+	SaleID string `json:"sale_id" binding:"required,uuid"`
+	Reason string `json:"reason,omitempty" binding:"max=255"`
+}
+
+// SalesmanListResponse representa a resposta paginada de vendedores
+type SalesmanListResponse struct {
+	Items      []*salesman.Salesman `json:"items"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Size       int                  `json:"size"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// ToSalesmanListResponse converte uma lista de vendedores para DTO paginado
+func ToSalesmanListResponse(salesmen []*salesman.Salesman, total, page, size int) *SalesmanListResponse {
+	return &SalesmanListResponse{
+		Items:      salesmen,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"github.com/hugohenrick/erp-supermercado/internal/domain/supplier"
+)
+
+// SupplierRequest representa os dados para criar/atualizar um fornecedor
+type SupplierRequest struct {
+	Name          string           `json:"name" binding:"required"`
+	TradeName     string           `json:"trade_name,omitempty"`
+	Document      string           `json:"document" binding:"required"`
+	StateDocument string           `json:"state_document,omitempty"`
+	Email         string           `json:"email,omitempty"`
+	Phone         string           `json:"phone,omitempty"`
+	PaymentTerm   string           `json:"payment_term,omitempty"` // Ex.: 30/60/90
+	Address       supplier.Address `json:"address"`
+	Notes         string           `json:"notes,omitempty"`
+	Active        *bool            `json:"active,omitempty"`
+}
+
+// SupplierListResponse representa a resposta paginada de fornecedores
+type SupplierListResponse struct {
+	Items      []*supplier.Supplier `json:"items"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Size       int                  `json:"size"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// ToSupplierListResponse converte uma lista de fornecedores para DTO paginado
+func ToSupplierListResponse(suppliers []*supplier.Supplier, total, page, size int) *SupplierListResponse {
+	return &SupplierListResponse{
+		Items:      suppliers,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -13,21 +13,31 @@ type TenantRequest struct {
 	Email       string `json:"email"`
 	Phone       string `json:"phone"`
 	PlanType    string `json:"plan_type" binding:"required"`
-	MaxBranches int    `json:"max_branches" binding:"min=1"`
+	MaxBranches int    `json:"max_branches" binding:"min=0"` // Zero usa o limite do plano
+}
+
+// PlanChangeRequest representa a troca de plano de um tenant
+type PlanChangeRequest struct {
+	PlanType    string     `json:"plan_type" binding:"required"`
+	MaxBranches int        `json:"max_branches" binding:"min=0"` // Zero usa o limite do plano
+	ExpiresAt   *time.Time `json:"expires_at"`                   // Fim da vigência; nulo não expira
 }
 
 // TenantResponse representa a estrutura de dados de resposta para tenant
 type TenantResponse struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Document    string    `json:"document"`
-	Email       string    `json:"email"`
-	Phone       string    `json:"phone"`
-	Status      string    `json:"status"`
-	PlanType    string    `json:"plan_type"`
-	MaxBranches int       `json:"max_branches"`
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Document      string     `json:"document"`
+	Email         string     `json:"email"`
+	Phone         string     `json:"phone"`
+	Status        string     `json:"status"`
+	PlanType      string     `json:"plan_type"`
+	MaxBranches   int        `json:"max_branches"`
+	PlanExpiresAt *time.Time `json:"plan_expires_at,omitempty"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	PurgeAfter    *time.Time `json:"purge_after,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // TenantListResponse representa a resposta de listagem de tenants
@@ -42,16 +52,19 @@ type TenantListResponse struct {
 // ToResponse converte um modelo de domínio em uma resposta DTO
 func ToTenantResponse(t *tenant.Tenant) TenantResponse {
 	return TenantResponse{
-		ID:          t.ID,
-		Name:        t.Name,
-		Document:    t.Document,
-		Email:       t.Email,
-		Phone:       t.Phone,
-		Status:      string(t.Status),
-		PlanType:    t.PlanType,
-		MaxBranches: t.MaxBranches,
-		CreatedAt:   t.CreatedAt,
-		UpdatedAt:   t.UpdatedAt,
+		ID:            t.ID,
+		Name:          t.Name,
+		Document:      t.Document,
+		Email:         t.Email,
+		Phone:         t.Phone,
+		Status:        string(t.Status),
+		PlanType:      t.PlanType,
+		MaxBranches:   t.MaxBranches,
+		PlanExpiresAt: t.PlanExpiresAt,
+		DeletedAt:     t.DeletedAt,
+		PurgeAfter:    t.PurgeAfter,
+		CreatedAt:     t.CreatedAt,
+		UpdatedAt:     t.UpdatedAt,
 	}
 }
 
@@ -0,0 +1,30 @@
+package dto
+
+import "github.com/hugohenrick/erp-supermercado/internal/domain/terminal"
+
+// TerminalRequest representa o cadastro de um terminal de PDV
+type TerminalRequest struct {
+	BranchID string `json:"branch_id" binding:"required"`
+	Code     string `json:"code" binding:"required"`
+	Name     string `json:"name" binding:"required"`
+}
+
+// TerminalListResponse representa a resposta paginada de terminais de PDV
+type TerminalListResponse struct {
+	Items      []*terminal.Terminal `json:"items"`
+	Total      int                  `json:"total"`
+	Page       int                  `json:"page"`
+	Size       int                  `json:"size"`
+	TotalPages int                  `json:"total_pages"`
+}
+
+// ToTerminalListResponse converte uma lista de terminais para DTO paginado
+func ToTerminalListResponse(terminals []*terminal.Terminal, total, page, size int) *TerminalListResponse {
+	return &TerminalListResponse{
+		Items:      terminals,
+		Total:      total,
+		Page:       page,
+		Size:       size,
+		TotalPages: calculateTotalPages(total, size),
+	}
+}
@@ -27,6 +27,8 @@ type UserResponse struct {
 	LastLoginAt time.Time `json:"last_login_at,omitempty"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	Branches []user.Membership `json:"branches,omitempty"`
 }
 
 // UserListResponse representa a resposta com a lista de usuários paginada
@@ -44,6 +46,23 @@ type ChangePasswordRequest struct {
 	NewPassword     string `json:"new_password" binding:"required,min=6"`
 }
 
+// MembershipRequest vincula o usuário a uma filial com um papel
+type MembershipRequest struct {
+	BranchID string `json:"branch_id" binding:"required"`
+	Role     string `json:"role" binding:"required,oneof=admin manager staff"`
+}
+
+// UserBranchesRequest substitui as filiais em que o usuário trabalha
+type UserBranchesRequest struct {
+	Branches []MembershipRequest `json:"branches" binding:"dive"`
+}
+
+// UserBranchesResponse lista as filiais em que o usuário trabalha
+type UserBranchesResponse struct {
+	UserID   string            `json:"user_id"`
+	Branches []user.Membership `json:"branches"`
+}
+
 // ToUserResponse converte um usuário do domínio para DTO de resposta
 func ToUserResponse(u *user.User) UserResponse {
 	return UserResponse{
@@ -57,6 +76,7 @@ func ToUserResponse(u *user.User) UserResponse {
 		LastLoginAt: u.LastLoginAt,
 		CreatedAt:   u.CreatedAt,
 		UpdatedAt:   u.UpdatedAt,
+		Branches:    u.Branches,
 	}
 }
 
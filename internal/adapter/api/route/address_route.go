@@ -0,0 +1,18 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupAddressRoutes configura as rotas de consulta de CEP e códigos IBGE
+func SetupAddressRoutes(router *gin.RouterGroup, addressController *controller.AddressController) {
+	addressRouter := router.Group("/addresses")
+	addressRouter.Use(auth.JWTAuthMiddleware())
+	{
+		addressRouter.GET("/zip-codes/:zip_code", addressController.LookupZipCode)
+		addressRouter.GET("/states", addressController.ListStates)
+		addressRouter.GET("/municipalities", addressController.FindMunicipality)
+	}
+}
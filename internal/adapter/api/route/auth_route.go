@@ -18,5 +18,8 @@ func SetupAuthRoutes(router *gin.RouterGroup, authController *controller.AuthCon
 		
 		// Rota para obter informações do usuário logado (requer autenticação)
 		authRouter.GET("/me", auth.JWTAuthMiddleware(), authController.Me)
+		
+		// Rota para trocar a filial ativa do token (requer autenticação)
+		authRouter.POST("/switch-branch", auth.JWTAuthMiddleware(), authController.SwitchBranch)
 	}
 } 
\ No newline at end of file
@@ -0,0 +1,42 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupBankingRoutes configura as rotas de contas bancárias, conciliação e fluxo de caixa
+func SetupBankingRoutes(router *gin.RouterGroup, bankingController *controller.BankingController) {
+	accountRouter := router.Group("/bank-accounts")
+	accountRouter.Use(auth.JWTAuthMiddleware())
+	{
+		accountRouter.GET("", bankingController.ListAccounts)
+		accountRouter.GET("/:id", bankingController.GetAccount)
+		accountRouter.GET("/:id/statements", bankingController.ListImports)
+
+		// Cadastro de contas e importação de extratos dependem de permissão
+		accountRouter.POST("", auth.RequirePermission(rbac.PermBankingAccountManage), bankingController.CreateAccount)
+		accountRouter.PUT("/:id", auth.RequirePermission(rbac.PermBankingAccountManage), bankingController.UpdateAccount)
+		accountRouter.POST("/:id/statements", auth.RequirePermission(rbac.PermBankingReconcile), bankingController.ImportStatement)
+	}
+
+	transactionRouter := router.Group("/bank-transactions")
+	transactionRouter.Use(auth.JWTAuthMiddleware())
+	{
+		transactionRouter.GET("", bankingController.ListTransactions)
+		transactionRouter.GET("/:id", bankingController.GetTransaction)
+
+		// A conciliação baixa títulos, por isso depende de permissão
+		transactionRouter.POST("/:id/match", auth.RequirePermission(rbac.PermBankingReconcile), bankingController.MatchTransaction)
+		transactionRouter.POST("/:id/unmatch", auth.RequirePermission(rbac.PermBankingReconcile), bankingController.UnmatchTransaction)
+		transactionRouter.POST("/:id/ignore", auth.RequirePermission(rbac.PermBankingReconcile), bankingController.IgnoreTransaction)
+	}
+
+	cashFlowRouter := router.Group("/cash-flow")
+	cashFlowRouter.Use(auth.JWTAuthMiddleware())
+	{
+		cashFlowRouter.GET("", bankingController.CashFlow)
+	}
+}
@@ -3,6 +3,7 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
 	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 )
 
@@ -12,15 +13,17 @@ func SetupBranchRoutes(router *gin.RouterGroup, branchController *controller.Bra
 	branchRouter := router.Group("/branches")
 	branchRouter.Use(auth.JWTAuthMiddleware())
 	{
+		manage := auth.RequirePermission(rbac.PermBranchManage)
+
 		// Operações CRUD básicas
-		branchRouter.POST("", branchController.Create)
+		branchRouter.POST("", manage, branchController.Create)
 		branchRouter.GET("", branchController.List)
 		branchRouter.GET("/:id", branchController.GetByID)
 		branchRouter.GET("/main", branchController.GetMainBranch)
-		branchRouter.PUT("/:id", branchController.Update)
-		branchRouter.DELETE("/:id", branchController.Delete)
+		branchRouter.PUT("/:id", manage, branchController.Update)
+		branchRouter.DELETE("/:id", manage, branchController.Delete)
 		
 		// Operações adicionais
-		branchRouter.PATCH("/:id/status/:status", branchController.UpdateStatus)
+		branchRouter.PATCH("/:id/status/:status", manage, branchController.UpdateStatus)
 	}
 } 
\ No newline at end of file
@@ -0,0 +1,30 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupCardRoutes configura as rotas de taxas, agenda de recebimentos e conciliação das adquirentes
+func SetupCardRoutes(router *gin.RouterGroup, cardController *controller.CardController) {
+	cardRouter := router.Group("/cards")
+	cardRouter.Use(auth.JWTAuthMiddleware())
+	{
+		cardRouter.GET("/fees", cardController.ListFees)
+		cardRouter.GET("/sales", cardController.ListSales)
+		cardRouter.GET("/sales/:id", cardController.GetSale)
+		cardRouter.GET("/settlements", cardController.ListSettlements)
+		cardRouter.GET("/settlement-files", cardController.ListSettlementFiles)
+		cardRouter.GET("/settlement-files/:id/records", cardController.ListFileRecords)
+
+		// Registro das vendas autorizadas pelo TEF/POS
+		cardRouter.POST("/sales", cardController.CreateSale)
+
+		// Taxas contratadas e arquivos das adquirentes dependem de permissão
+		cardRouter.POST("/fees", auth.RequirePermission(rbac.PermCardManage), cardController.CreateFee)
+		cardRouter.PUT("/fees/:id", auth.RequirePermission(rbac.PermCardManage), cardController.UpdateFee)
+		cardRouter.POST("/settlement-files", auth.RequirePermission(rbac.PermCardManage), cardController.ImportSettlementFile)
+	}
+}
@@ -3,6 +3,7 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
 	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 )
 
@@ -12,20 +13,22 @@ func SetupCertificateRoutes(router *gin.RouterGroup, certificateController *cont
 	certificateRouter := router.Group("/certificates")
 	certificateRouter.Use(auth.JWTAuthMiddleware())
 	{
+		manage := auth.RequirePermission(rbac.PermCertificateManage)
+
 		// Operações CRUD básicas
 		certificateRouter.GET("", certificateController.List)
 		certificateRouter.GET("/:id", certificateController.Get)
-		certificateRouter.POST("", certificateController.Create)
-		certificateRouter.POST("/upload", certificateController.Upload)
-		certificateRouter.PUT("/:id", certificateController.Update)
-		certificateRouter.DELETE("/:id", certificateController.Delete)
+		certificateRouter.POST("", manage, certificateController.Create)
+		certificateRouter.POST("/upload", manage, certificateController.Upload)
+		certificateRouter.PUT("/:id", manage, certificateController.Update)
+		certificateRouter.DELETE("/:id", manage, certificateController.Delete)
 
 		// Operações adicionais
-		certificateRouter.POST("/:id/activate", certificateController.Activate)
-		certificateRouter.POST("/:id/deactivate", certificateController.Deactivate)
+		certificateRouter.POST("/:id/activate", manage, certificateController.Activate)
+		certificateRouter.POST("/:id/deactivate", manage, certificateController.Deactivate)
 		certificateRouter.GET("/expiring", certificateController.ListExpiring)
 
 		// Nova rota para extrair informações do certificado
-		certificateRouter.POST("/extract-info", certificateController.ExtractInfo)
+		certificateRouter.POST("/extract-info", manage, certificateController.ExtractInfo)
 	}
 }
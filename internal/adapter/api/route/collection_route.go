@@ -0,0 +1,33 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupCollectionRoutes configura as rotas para o módulo de cobrança bancária
+func SetupCollectionRoutes(router *gin.RouterGroup, collectionController *controller.CollectionController) {
+	collectionRouter := router.Group("/collection")
+	collectionRouter.Use(auth.JWTAuthMiddleware())
+	{
+		// Contas de cobrança (convênios)
+		collectionRouter.GET("/accounts", collectionController.ListAccounts)
+		collectionRouter.GET("/accounts/:id", collectionController.GetAccount)
+		collectionRouter.POST("/accounts", auth.RequirePermission(rbac.PermCollectionManage), collectionController.CreateAccount)
+		collectionRouter.PUT("/accounts/:id", auth.RequirePermission(rbac.PermCollectionManage), collectionController.UpdateAccount)
+
+		// Boletos
+		collectionRouter.GET("/boletos", collectionController.ListBoletos)
+		collectionRouter.GET("/boletos/:id", collectionController.GetBoleto)
+		collectionRouter.GET("/boletos/:id/pdf", collectionController.BoletoPDF)
+		collectionRouter.POST("/boletos", collectionController.IssueBoleto)
+
+		// Arquivos de remessa e retorno dependem de permissão
+		collectionRouter.GET("/remittances", auth.RequirePermission(rbac.PermCollectionManage), collectionController.ListRemittances)
+		collectionRouter.GET("/remittances/:id/file", auth.RequirePermission(rbac.PermCollectionManage), collectionController.DownloadRemittance)
+		collectionRouter.POST("/remittances", auth.RequirePermission(rbac.PermCollectionManage), collectionController.CreateRemittance)
+		collectionRouter.POST("/returns", auth.RequirePermission(rbac.PermCollectionManage), collectionController.ProcessReturn)
+	}
+}
@@ -3,21 +3,30 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
-	"github.com/hugohenrick/erp-supermercado/pkg/middleware"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 )
 
-// RegisterCustomerRoutes registra as rotas do módulo de clientes
+// RegisterCustomerRoutes registra as rotas do módulo de clientes. Cada operação depende da
+// permissão correspondente, as mesmas exigidas pelas intenções do assistente
 func RegisterCustomerRoutes(r *gin.RouterGroup, customerController *controller.CustomerController) {
 	customers := r.Group("/customers")
-	customers.Use(middleware.AuthMiddleware())
+	customers.Use(auth.JWTAuthMiddleware())
 	{
-		customers.POST("", customerController.Create)
-		customers.GET("", customerController.List)
-		customers.GET("/:id", customerController.Get)
-		customers.PUT("/:id", customerController.Update)
-		customers.DELETE("/:id", customerController.Delete)
-		customers.PATCH("/:id/status", customerController.UpdateStatus)
-		customers.GET("/document/:document", customerController.FindByDocument)
-		customers.GET("/search", customerController.FindByName)
+		view := auth.RequirePermission(rbac.PermCustomerView)
+		create := auth.RequirePermission(rbac.PermCustomerCreate)
+		update := auth.RequirePermission(rbac.PermCustomerUpdate)
+		remove := auth.RequirePermission(rbac.PermCustomerDelete)
+
+		customers.POST("", create, customerController.Create)
+		customers.GET("", view, customerController.List)
+		customers.POST("/import", create, customerController.Import)
+		customers.GET("/export", view, customerController.Export)
+		customers.GET("/:id", view, customerController.Get)
+		customers.PUT("/:id", update, customerController.Update)
+		customers.DELETE("/:id", remove, customerController.Delete)
+		customers.PATCH("/:id/status", update, customerController.UpdateStatus)
+		customers.GET("/document/:document", view, customerController.FindByDocument)
+		customers.GET("/search", view, customerController.FindByName)
 	}
 }
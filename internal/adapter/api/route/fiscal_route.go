@@ -3,6 +3,7 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
 	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 )
 
@@ -12,17 +13,22 @@ func SetupFiscalRoutes(router *gin.RouterGroup, fiscalController *controller.Fis
 	fiscalRouter := router.Group("/fiscal/configs")
 	fiscalRouter.Use(auth.JWTAuthMiddleware())
 	{
+		manage := auth.RequirePermission(rbac.PermFiscalManage)
+
 		// Operações CRUD básicas
 		fiscalRouter.GET("", fiscalController.List)
 		fiscalRouter.GET("/:id", fiscalController.Get)
-		fiscalRouter.POST("", fiscalController.Create)
-		fiscalRouter.PUT("/:id", fiscalController.Update)
-		fiscalRouter.DELETE("/:id", fiscalController.Delete)
+		fiscalRouter.POST("", manage, fiscalController.Create)
+		fiscalRouter.PUT("/:id", manage, fiscalController.Update)
+		fiscalRouter.DELETE("/:id", manage, fiscalController.Delete)
 
 		// Operações por filial
 		fiscalRouter.GET("/branch/:branch_id", fiscalController.GetByBranch)
-		fiscalRouter.POST("/branch/:branch_id/increment-nfe", fiscalController.IncrementNFeNumber)
-		fiscalRouter.POST("/branch/:branch_id/increment-nfce", fiscalController.IncrementNFCeNumber)
-		fiscalRouter.POST("/branch/:branch_id/contingency", fiscalController.UpdateContingency)
+		fiscalRouter.POST("/branch/:branch_id/increment-nfe", manage, fiscalController.IncrementNFeNumber)
+		fiscalRouter.POST("/branch/:branch_id/increment-nfce", manage, fiscalController.IncrementNFCeNumber)
+		fiscalRouter.POST("/branch/:branch_id/contingency", manage, fiscalController.UpdateContingency)
+
+		// Inutilização de numeração depende da permissão de cancelamento fiscal
+		fiscalRouter.POST("/branch/:branch_id/void-numbers", auth.RequirePermission(rbac.PermFiscalCancel), fiscalController.VoidNumbers)
 	}
 }
@@ -0,0 +1,33 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupLossRoutes configura as rotas para o módulo de perdas e quebras
+func SetupLossRoutes(router *gin.RouterGroup, lossController *controller.LossController) {
+	lossRouter := router.Group("/losses")
+	lossRouter.Use(auth.JWTAuthMiddleware())
+	{
+		// Motivos de perda
+		lossRouter.GET("/reasons", lossController.ListReasons)
+		lossRouter.POST("/reasons", auth.RequirePermission(rbac.PermLossReasonManage), lossController.CreateReason)
+		lossRouter.PUT("/reasons/:id", auth.RequirePermission(rbac.PermLossReasonManage), lossController.UpdateReason)
+
+		// Relatório
+		lossRouter.GET("/report", lossController.Report)
+
+		// Registro de perdas
+		lossRouter.GET("", lossController.List)
+		lossRouter.POST("", lossController.Create)
+		lossRouter.GET("/:id", lossController.Get)
+		lossRouter.POST("/:id/photos", lossController.AddPhotos)
+
+		// Aprovação depende de permissão
+		lossRouter.POST("/:id/approve", auth.RequirePermission(rbac.PermLossApprove), lossController.Approve)
+		lossRouter.POST("/:id/reject", auth.RequirePermission(rbac.PermLossApprove), lossController.Reject)
+	}
+}
@@ -0,0 +1,36 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupLoyaltyRoutes configura as rotas do programa de fidelidade
+func SetupLoyaltyRoutes(router *gin.RouterGroup, loyaltyController *controller.LoyaltyController) {
+	loyaltyRouter := router.Group("/loyalty")
+	loyaltyRouter.Use(auth.JWTAuthMiddleware())
+	{
+		loyaltyRouter.GET("/program", loyaltyController.GetProgram)
+		loyaltyRouter.GET("/rules", loyaltyController.ListRules)
+		loyaltyRouter.GET("/campaigns", loyaltyController.ListCampaigns)
+		loyaltyRouter.GET("/rewards", loyaltyController.ListRewards)
+		loyaltyRouter.GET("/customers/:id/entries", loyaltyController.ListEntries)
+
+		// Operações do caixa
+		loyaltyRouter.GET("/balance", loyaltyController.GetBalance)
+		loyaltyRouter.POST("/earn", loyaltyController.Earn)
+		loyaltyRouter.POST("/redeem", loyaltyController.Redeem)
+
+		// Configuração do programa depende de permissão
+		loyaltyRouter.PUT("/program", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.SaveProgram)
+		loyaltyRouter.POST("/rules", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.CreateRule)
+		loyaltyRouter.PUT("/rules/:id", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.UpdateRule)
+		loyaltyRouter.POST("/campaigns", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.CreateCampaign)
+		loyaltyRouter.PUT("/campaigns/:id", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.UpdateCampaign)
+		loyaltyRouter.POST("/rewards", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.CreateReward)
+		loyaltyRouter.PUT("/rewards/:id", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.UpdateReward)
+		loyaltyRouter.POST("/expire", auth.RequirePermission(rbac.PermLoyaltyManage), loyaltyController.Expire)
+	}
+}
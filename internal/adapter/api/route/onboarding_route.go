@@ -0,0 +1,13 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+)
+
+// SetupOnboardingRoutes configura a rota do cadastro inicial de tenants. O router já deve exigir o
+// token de operador da plataforma (auth.PlatformAuthMiddleware)
+func SetupOnboardingRoutes(router *gin.RouterGroup, onboardingController *controller.OnboardingController) {
+	// Não requer o cabeçalho tenant-id: o tenant é criado pela própria chamada
+	router.POST("/onboarding", onboardingController.Create)
+}
@@ -0,0 +1,27 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupPayableRoutes configura as rotas para o módulo de contas a pagar
+func SetupPayableRoutes(router *gin.RouterGroup, payableController *controller.PayableController) {
+	payableRouter := router.Group("/payables")
+	payableRouter.Use(auth.JWTAuthMiddleware())
+	{
+		payableRouter.GET("", payableController.List)
+		payableRouter.GET("/calendar", payableController.Calendar)
+		payableRouter.GET("/:id", payableController.Get)
+		payableRouter.POST("", payableController.Create)
+
+		// Geração de parcelas a partir de compras recebidas
+		payableRouter.POST("/purchases", payableController.GenerateFromPurchase)
+
+		// Baixas e cancelamento dependem de permissão
+		payableRouter.POST("/:id/payments", auth.RequirePermission(rbac.PermPayablePay), payableController.Pay)
+		payableRouter.POST("/:id/cancel", auth.RequirePermission(rbac.PermPayableCancel), payableController.Cancel)
+	}
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupPaymentMethodRoutes configura as rotas de formas de pagamento
+func SetupPaymentMethodRoutes(router *gin.RouterGroup, paymentMethodController *controller.PaymentMethodController) {
+	paymentMethodRouter := router.Group("/payment-methods")
+	paymentMethodRouter.Use(auth.JWTAuthMiddleware())
+	{
+		paymentMethodRouter.GET("", paymentMethodController.List)
+		paymentMethodRouter.GET("/:id", paymentMethodController.Get)
+
+		// Conferência dos pagamentos no fechamento da venda
+		paymentMethodRouter.POST("/check", paymentMethodController.Check)
+
+		// Cadastro depende de permissão
+		paymentMethodRouter.POST("", auth.RequirePermission(rbac.PermPaymentMethodManage), paymentMethodController.Create)
+		paymentMethodRouter.PUT("/:id", auth.RequirePermission(rbac.PermPaymentMethodManage), paymentMethodController.Update)
+		paymentMethodRouter.DELETE("/:id", auth.RequirePermission(rbac.PermPaymentMethodManage), paymentMethodController.Delete)
+	}
+}
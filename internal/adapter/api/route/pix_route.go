@@ -0,0 +1,31 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupPixRoutes configura as rotas para o módulo de cobranças Pix
+func SetupPixRoutes(router *gin.RouterGroup, pixController *controller.PixController) {
+	// Webhook público chamado pelo PSP, autenticado pelo segredo da configuração de Pix (sem segredo,
+	// as notificações são rejeitadas); a especificação da API Pix acrescenta /pix à URL cadastrada
+	router.POST("/pix/webhook/:tenant_id", pixController.Webhook)
+	router.POST("/pix/webhook/:tenant_id/pix", pixController.Webhook)
+
+	pixRouter := router.Group("/pix")
+	pixRouter.Use(auth.JWTAuthMiddleware())
+	{
+		pixRouter.GET("/charges", pixController.ListCharges)
+		pixRouter.GET("/charges/:id", pixController.GetCharge)
+		pixRouter.POST("/charges", pixController.CreateCharge)
+		pixRouter.POST("/charges/:id/cancel", pixController.CancelCharge)
+
+		// Configuração, conciliação e simulação dependem de permissão
+		pixRouter.GET("/settings", auth.RequirePermission(rbac.PermPixManage), pixController.GetSettings)
+		pixRouter.PUT("/settings", auth.RequirePermission(rbac.PermPixManage), pixController.SaveSettings)
+		pixRouter.GET("/notifications", auth.RequirePermission(rbac.PermPixManage), pixController.ListNotifications)
+		pixRouter.POST("/charges/:id/simulate-payment", auth.RequirePermission(rbac.PermPixManage), pixController.SimulatePayment)
+	}
+}
@@ -0,0 +1,21 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+)
+
+// SetupPlatformRoutes configura as rotas dos operadores da plataforma. O router das rotas
+// protegidas já deve exigir o token de operador (auth.PlatformAuthMiddleware)
+func SetupPlatformRoutes(router, protected *gin.RouterGroup, platformController *controller.PlatformController) {
+	// Login do operador (não requer autenticação)
+	router.POST("/platform/auth/login", platformController.Login)
+
+	platformRouter := protected.Group("/platform")
+	{
+		platformRouter.GET("/me", platformController.Me)
+		platformRouter.GET("/operators", platformController.ListOperators)
+		platformRouter.POST("/operators", platformController.CreateOperator)
+		platformRouter.PATCH("/operators/:id/status", platformController.UpdateOperatorStatus)
+	}
+}
@@ -0,0 +1,26 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupPriceTableRoutes configura as rotas de tabelas de preços
+func SetupPriceTableRoutes(router *gin.RouterGroup, priceTableController *controller.PriceTableController) {
+	priceTableRouter := router.Group("/price-tables")
+	priceTableRouter.Use(auth.JWTAuthMiddleware())
+	{
+		priceTableRouter.GET("", priceTableController.List)
+		priceTableRouter.GET("/:id", priceTableController.Get)
+
+		// Preços do cliente usados na venda
+		priceTableRouter.POST("/resolve", priceTableController.Resolve)
+
+		// Cadastro de tabelas depende de permissão
+		priceTableRouter.POST("", auth.RequirePermission(rbac.PermPriceTableManage), priceTableController.Create)
+		priceTableRouter.PUT("/:id", auth.RequirePermission(rbac.PermPriceTableManage), priceTableController.Update)
+		priceTableRouter.DELETE("/:id", auth.RequirePermission(rbac.PermPriceTableManage), priceTableController.Delete)
+	}
+}
@@ -0,0 +1,25 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupPromotionRoutes configura as rotas de promoções
+func SetupPromotionRoutes(router *gin.RouterGroup, promotionController *controller.PromotionController) {
+	promotionRouter := router.Group("/promotions")
+	promotionRouter.Use(auth.JWTAuthMiddleware())
+	{
+		promotionRouter.GET("", promotionController.List)
+		promotionRouter.GET("/:id", promotionController.Get)
+
+		// Cálculo dos preços no PDV
+		promotionRouter.POST("/evaluate", promotionController.Evaluate)
+
+		// Cadastro de promoções depende de permissão
+		promotionRouter.POST("", auth.RequirePermission(rbac.PermPromotionManage), promotionController.Create)
+		promotionRouter.PUT("/:id", auth.RequirePermission(rbac.PermPromotionManage), promotionController.Update)
+	}
+}
@@ -0,0 +1,32 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupReceivableRoutes configura as rotas para o módulo de contas a receber
+func SetupReceivableRoutes(router *gin.RouterGroup, receivableController *controller.ReceivableController) {
+	receivableRouter := router.Group("/receivables")
+	receivableRouter.Use(auth.JWTAuthMiddleware())
+	{
+		receivableRouter.GET("", receivableController.List)
+		receivableRouter.GET("/customers/:customer_id/balance", receivableController.CustomerBalance)
+		receivableRouter.GET("/:id", receivableController.Get)
+		receivableRouter.POST("", receivableController.Create)
+
+		// Vendas a prazo (fiado/crediário) com validação de limite de crédito
+		receivableRouter.POST("/sales", receivableController.CreditSale)
+		receivableRouter.POST("/:id/payments", receivableController.Receive)
+
+		// Cancelamento e bloqueio de inadimplentes dependem de permissão
+		receivableRouter.POST("/:id/cancel", auth.RequirePermission(rbac.PermReceivableCancel), receivableController.Cancel)
+		receivableRouter.POST("/block-overdue", auth.RequirePermission(rbac.PermReceivableBlockOverdue), receivableController.BlockOverdue)
+
+		// Tolerância de atraso lida pela venda a prazo e pelo comando block-overdue
+		receivableRouter.GET("/settings", receivableController.GetSettings)
+		receivableRouter.PUT("/settings", auth.RequirePermission(rbac.PermReceivableBlockOverdue), receivableController.SaveSettings)
+	}
+}
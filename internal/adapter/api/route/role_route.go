@@ -0,0 +1,33 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupRoleRoutes configura as rotas de perfis de acesso e permissões
+func SetupRoleRoutes(router *gin.RouterGroup, roleController *controller.RoleController) {
+	permissionRouter := router.Group("/permissions")
+	permissionRouter.Use(auth.JWTAuthMiddleware())
+	{
+		permissionRouter.GET("", roleController.ListPermissions)
+		permissionRouter.GET("/me", roleController.MyPermissions)
+	}
+
+	// Perfis e atribuições exigem a gestão de perfis
+	roleRouter := router.Group("/roles")
+	roleRouter.Use(auth.JWTAuthMiddleware())
+	roleRouter.Use(auth.RequirePermission(rbac.PermRoleManage))
+	{
+		roleRouter.GET("", roleController.List)
+		roleRouter.POST("", roleController.Create)
+		roleRouter.GET("/:id", roleController.Get)
+		roleRouter.PUT("/:id", roleController.Update)
+		roleRouter.DELETE("/:id", roleController.Delete)
+
+		roleRouter.GET("/users/:user_id", roleController.GetUserRoles)
+		roleRouter.PUT("/users/:user_id", roleController.SetUserRoles)
+	}
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupSalesmanRoutes configura as rotas de vendedores e comissões
+func SetupSalesmanRoutes(router *gin.RouterGroup, salesmanController *controller.SalesmanController) {
+	salesmanRouter := router.Group("/salesmen")
+	salesmanRouter.Use(auth.JWTAuthMiddleware())
+	{
+		salesmanRouter.GET("", salesmanController.List)
+		salesmanRouter.GET("/commission-rules", salesmanController.ListRules)
+		salesmanRouter.GET("/:id", salesmanController.Get)
+		salesmanRouter.GET("/:id/statement", salesmanController.Statement)
+
+		// Lançamento e estorno chamados pela finalização e pelo cancelamento da venda
+		salesmanRouter.POST("/commissions/accrue", salesmanController.Accrue)
+		salesmanRouter.POST("/commissions/reverse", salesmanController.Reverse)
+
+		// Cadastro de vendedores e regras depende de permissão
+		salesmanRouter.POST("", auth.RequirePermission(rbac.PermSalesmanManage), salesmanController.Create)
+		salesmanRouter.PUT("/:id", auth.RequirePermission(rbac.PermSalesmanManage), salesmanController.Update)
+		salesmanRouter.POST("/commission-rules", auth.RequirePermission(rbac.PermSalesmanManage), salesmanController.CreateRule)
+		salesmanRouter.DELETE("/commission-rules/:id", auth.RequirePermission(rbac.PermSalesmanManage), salesmanController.DeleteRule)
+	}
+}
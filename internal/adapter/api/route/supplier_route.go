@@ -0,0 +1,21 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupSupplierRoutes configura as rotas para o módulo de fornecedores
+func SetupSupplierRoutes(router *gin.RouterGroup, supplierController *controller.SupplierController) {
+	supplierRouter := router.Group("/suppliers")
+	supplierRouter.Use(auth.JWTAuthMiddleware())
+	{
+		supplierRouter.GET("", supplierController.List)
+		supplierRouter.GET("/:id", supplierController.Get)
+		supplierRouter.POST("", supplierController.Create)
+		supplierRouter.PUT("/:id", supplierController.Update)
+		supplierRouter.DELETE("/:id", auth.RequirePermission(rbac.PermSupplierDelete), supplierController.Delete)
+	}
+}
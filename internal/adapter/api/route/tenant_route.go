@@ -12,6 +12,7 @@ func SetupTenantRoutes(router *gin.RouterGroup, tenantController *controller.Ten
 		// Operações CRUD básicas
 		tenantRouter.POST("", tenantController.Create)
 		tenantRouter.GET("", tenantController.List)
+		tenantRouter.GET("/plans", tenantController.ListPlans)
 		tenantRouter.GET("/:id", tenantController.GetByID)
 		tenantRouter.GET("/document/:document", tenantController.GetByDocument)
 		tenantRouter.PUT("/:id", tenantController.Update)
@@ -19,6 +20,15 @@ func SetupTenantRoutes(router *gin.RouterGroup, tenantController *controller.Ten
 		
 		// Operações adicionais
 		tenantRouter.PATCH("/:id/status/:status", tenantController.UpdateStatus)
+
+		// Plano e vigência
+		tenantRouter.GET("/:id/subscription", tenantController.GetSubscription)
+		tenantRouter.PUT("/:id/plan", tenantController.ChangePlan)
+
+		// Desligamento: restauração dentro do prazo de carência, exportação e auditoria
+		tenantRouter.POST("/:id/restore", tenantController.Restore)
+		tenantRouter.GET("/:id/export", tenantController.Export)
+		tenantRouter.GET("/:id/audit", tenantController.Audit)
 	}
 }
  
\ No newline at end of file
@@ -0,0 +1,22 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
+)
+
+// SetupTerminalRoutes configura as rotas de terminais de PDV
+func SetupTerminalRoutes(router *gin.RouterGroup, terminalController *controller.TerminalController) {
+	terminalRouter := router.Group("/terminals")
+	terminalRouter.Use(auth.JWTAuthMiddleware())
+	{
+		terminalRouter.GET("", terminalController.List)
+		terminalRouter.GET("/:id", terminalController.Get)
+
+		// Cadastro depende de permissão
+		terminalRouter.POST("", auth.RequirePermission(rbac.PermTerminalManage), terminalController.Create)
+		terminalRouter.PATCH("/:id/status/:status", auth.RequirePermission(rbac.PermTerminalManage), terminalController.UpdateStatus)
+	}
+}
@@ -0,0 +1,16 @@
+package route
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+)
+
+// SetupUsageRoutes configura as rotas da medição de consumo dos tenants, usadas na cobrança
+func SetupUsageRoutes(router *gin.RouterGroup, usageController *controller.UsageController) {
+	usageRouter := router.Group("/tenants")
+	{
+		usageRouter.GET("/usage", usageController.List)
+		usageRouter.GET("/usage/export", usageController.Export)
+		usageRouter.GET("/:id/usage", usageController.Get)
+	}
+}
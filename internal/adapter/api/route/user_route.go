@@ -3,31 +3,36 @@ package route
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/controller"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
 	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 )
 
 // SetupUserRoutes configura as rotas para o módulo de usuários
 func SetupUserRoutes(router *gin.RouterGroup, userController *controller.UserController) {
 	userRouter := router.Group("/users")
+	userRouter.Use(auth.JWTAuthMiddleware())
 	{
-		// Rotas que requerem autenticação e autorização de administrador
-		userRouter.Use(auth.JWTAuthMiddleware())
-		userRouter.Use(auth.RoleAuthMiddleware("admin"))
-		{
-			// Operações CRUD básicas
-			userRouter.POST("", userController.Create)
-			userRouter.GET("", userController.List)
-			userRouter.GET("/:id", userController.GetByID)
-			userRouter.PUT("/:id", userController.Update)
-			userRouter.DELETE("/:id", userController.Delete)
+		// Consultas dependem da permissão de consulta de usuários, as mesmas das intenções do
+		// assistente; as alterações, da permissão de gestão
+		view := auth.RequirePermission(rbac.PermUserView)
+		manage := auth.RequirePermission(rbac.PermUserManage)
 
-			// Rotas especiais para filtragem e gestão
-			userRouter.GET("/branch/:branch_id", userController.ListByBranch)
-			userRouter.PATCH("/:id/status/:status", userController.UpdateStatus)
-		}
+		// Operações CRUD básicas
+		userRouter.POST("", manage, userController.Create)
+		userRouter.GET("", view, userController.List)
+		userRouter.GET("/:id", view, userController.GetByID)
+		userRouter.PUT("/:id", manage, userController.Update)
+		userRouter.DELETE("/:id", manage, userController.Delete)
 
-		// Rota para alteração de senha (pode ser usada pelo próprio usuário ou por um admin)
-		userRouter.Use(auth.JWTAuthMiddleware())
+		// Rotas especiais para filtragem e gestão
+		userRouter.GET("/branch/:branch_id", view, userController.ListByBranch)
+		userRouter.PATCH("/:id/status/:status", manage, userController.UpdateStatus)
+
+		// Filiais em que o usuário trabalha e o papel em cada uma
+		userRouter.GET("/:id/branches", view, userController.GetBranches)
+		userRouter.PUT("/:id/branches", manage, userController.SetBranches)
+
+		// Rota para alteração de senha (pode ser usada pelo próprio usuário ou por quem gerencia usuários)
 		userRouter.PATCH("/:id/password", userController.ChangePassword)
 	}
 }
@@ -0,0 +1,755 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/banking"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de contas bancárias
+var (
+	ErrBankAccountNotFound       = errors.New("conta bancária não encontrada")
+	ErrBankTransactionNotFound   = errors.New("lançamento do extrato não encontrado")
+	ErrBankTransactionConcurrent = errors.New("lançamento foi alterado por outra operação, tente novamente")
+	ErrBankPaymentAlreadyMatched = errors.New("baixa já conciliada com outro lançamento do extrato")
+)
+
+// BankingRepository implementa a interface banking.Repository
+type BankingRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewBankingRepository cria uma nova instância de BankingRepository
+func NewBankingRepository(db *pgxpool.Pool) banking.Repository {
+	return &BankingRepository{
+		db: db,
+	}
+}
+
+const bankAccountColumns = `id, tenant_id, branch_id, name, COALESCE(bank_code, ''), COALESCE(agency, ''),
+	COALESCE(account_number, ''), type, opening_balance, opening_date, active, created_at, updated_at`
+
+const bankTransactionColumns = `id, tenant_id, account_id, import_id, fitid, COALESCE(type, ''), posted_at, amount,
+	COALESCE(document, ''), COALESCE(memo, ''), status, COALESCE(match_kind, ''), title_id, payment_id,
+	matched_auto, matched_by, matched_at, created_at, updated_at`
+
+// bankingTitleTables identifica as tabelas de títulos e baixas de cada tipo de conciliação
+type bankingTitleTables struct {
+	titles   string // Tabela de títulos
+	payments string // Tabela de baixas
+	titleFK  string // Coluna da baixa que aponta para o título
+	settled  string // Coluna do principal já baixado no título
+	parties  string // Tabela de clientes ou fornecedores
+	partyFK  string // Coluna do título que aponta para o cliente ou fornecedor
+}
+
+var (
+	receivableTables = bankingTitleTables{"receivables", "receivable_payments", "receivable_id", "received_amount", "customers", "customer_id"}
+	payableTables    = bankingTitleTables{"payables", "payable_payments", "payable_id", "paid_amount", "suppliers", "supplier_id"}
+)
+
+// CreateAccount implementa banking.Repository.CreateAccount
+func (r *BankingRepository) CreateAccount(ctx context.Context, a *banking.Account) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	a.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.bank_accounts (
+			id, tenant_id, branch_id, name, bank_code, agency, account_number, type, opening_balance,
+			opening_date, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, a.ID, a.TenantID, nullIfEmpty(a.BranchID), a.Name, nullIfEmpty(a.BankCode),
+		nullIfEmpty(a.Agency), nullIfEmpty(a.AccountNumber), string(a.Type), a.OpeningBalance, a.OpeningDate,
+		a.Active, a.CreatedAt, a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao criar conta bancária: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAccount implementa banking.Repository.UpdateAccount
+func (r *BankingRepository) UpdateAccount(ctx context.Context, a *banking.Account) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.bank_accounts
+		SET branch_id = $1, name = $2, bank_code = $3, agency = $4, account_number = $5, type = $6,
+			opening_balance = $7, opening_date = $8, active = $9, updated_at = $10
+		WHERE id = $11 AND tenant_id = $12
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, nullIfEmpty(a.BranchID), a.Name, nullIfEmpty(a.BankCode),
+		nullIfEmpty(a.Agency), nullIfEmpty(a.AccountNumber), string(a.Type), a.OpeningBalance, a.OpeningDate,
+		a.Active, a.UpdatedAt, a.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar conta bancária: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrBankAccountNotFound
+	}
+
+	return nil
+}
+
+// FindAccountByID implementa banking.Repository.FindAccountByID
+func (r *BankingRepository) FindAccountByID(ctx context.Context, id string) (*banking.Account, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.bank_accounts WHERE id = $1 AND tenant_id = $2", bankAccountColumns, schema)
+
+	a, err := scanBankAccount(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar conta bancária: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListAccounts implementa banking.Repository.ListAccounts
+func (r *BankingRepository) ListAccounts(ctx context.Context) ([]*banking.Account, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.bank_accounts
+		WHERE tenant_id = $1
+		ORDER BY active DESC, name
+	`, bankAccountColumns, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar contas bancárias: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := make([]*banking.Account, 0)
+	for rows.Next() {
+		a, err := scanBankAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler conta bancária: %w", err)
+		}
+		accounts = append(accounts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar contas bancárias: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// CreateImport implementa banking.Repository.CreateImport
+func (r *BankingRepository) CreateImport(ctx context.Context, imp *banking.Import, transactions []*banking.Transaction) ([]*banking.Transaction, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+	imp.TenantID = tenantID
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.bank_statement_imports (
+			id, tenant_id, account_id, file_name, start_date, end_date, balance, transactions, duplicates,
+			matched, imported_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, schema), imp.ID, imp.TenantID, imp.AccountID, nullIfEmpty(imp.FileName), nullIfZeroTime(imp.StartDate),
+		nullIfZeroTime(imp.EndDate), imp.Balance, imp.Transactions, imp.Duplicates, imp.Matched,
+		nullIfEmpty(imp.ImportedBy), imp.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao registrar importação do extrato: %w", err)
+	}
+
+	// O FITID único por conta descarta os lançamentos de extratos com períodos sobrepostos
+	insert := fmt.Sprintf(`
+		INSERT INTO %s.bank_transactions (
+			id, tenant_id, account_id, import_id, fitid, type, posted_at, amount, document, memo, status,
+			matched_auto, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, false, $12, $13)
+		ON CONFLICT (account_id, fitid) DO NOTHING
+	`, schema)
+
+	inserted := make([]*banking.Transaction, 0, len(transactions))
+	for _, t := range transactions {
+		t.TenantID = tenantID
+		result, err := tx.Exec(ctx, insert, t.ID, t.TenantID, t.AccountID, t.ImportID, t.FITID, nullIfEmpty(t.Type),
+			t.PostedAt, t.Amount, nullIfEmpty(t.Document), nullIfEmpty(t.Memo), string(t.Status), t.CreatedAt,
+			t.UpdatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao gravar lançamento do extrato: %w", err)
+		}
+		if result.RowsAffected() > 0 {
+			inserted = append(inserted, t)
+		}
+	}
+
+	imp.Duplicates = len(transactions) - len(inserted)
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.bank_statement_imports SET duplicates = $1 WHERE id = $2
+	`, schema), imp.Duplicates, imp.ID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao atualizar importação do extrato: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return inserted, nil
+}
+
+// UpdateImportMatched implementa banking.Repository.UpdateImportMatched
+func (r *BankingRepository) UpdateImportMatched(ctx context.Context, imp *banking.Import) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.bank_statement_imports SET matched = $1 WHERE id = $2 AND tenant_id = $3
+	`, schema)
+
+	if _, err := conn.Exec(ctx, query, imp.Matched, imp.ID, tenantID); err != nil {
+		return fmt.Errorf("falha ao atualizar importação do extrato: %w", err)
+	}
+
+	return nil
+}
+
+// ListImports implementa banking.Repository.ListImports
+func (r *BankingRepository) ListImports(ctx context.Context, accountID string, limit, offset int) ([]*banking.Import, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, account_id, COALESCE(file_name, ''), start_date, end_date, balance, transactions,
+			duplicates, matched, imported_by, created_at
+		FROM %s.bank_statement_imports
+		WHERE tenant_id = $1 AND account_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID, accountID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar importações de extrato: %w", err)
+	}
+	defer rows.Close()
+
+	imports := make([]*banking.Import, 0)
+	for rows.Next() {
+		var imp banking.Import
+		var startDate, endDate pgtype.Date
+		var importedBy pgtype.Text
+		if err := rows.Scan(&imp.ID, &imp.TenantID, &imp.AccountID, &imp.FileName, &startDate, &endDate,
+			&imp.Balance, &imp.Transactions, &imp.Duplicates, &imp.Matched, &importedBy, &imp.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler importação de extrato: %w", err)
+		}
+		imp.StartDate = startDate.Time
+		imp.EndDate = endDate.Time
+		imp.ImportedBy = importedBy.String
+		imports = append(imports, &imp)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar importações de extrato: %w", err)
+	}
+
+	return imports, nil
+}
+
+// FindTransactionByID implementa banking.Repository.FindTransactionByID
+func (r *BankingRepository) FindTransactionByID(ctx context.Context, id string) (*banking.Transaction, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.bank_transactions WHERE id = $1 AND tenant_id = $2", bankTransactionColumns, schema)
+
+	t, err := scanBankTransaction(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBankTransactionNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar lançamento do extrato: %w", err)
+	}
+
+	return t, nil
+}
+
+// ListTransactions implementa banking.Repository.ListTransactions
+func (r *BankingRepository) ListTransactions(ctx context.Context, filter banking.TransactionFilter, limit, offset int) ([]*banking.Transaction, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildBankTransactionFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.bank_transactions
+		WHERE %s
+		ORDER BY posted_at, created_at
+		LIMIT $%d OFFSET $%d
+	`, bankTransactionColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar lançamentos do extrato: %w", err)
+	}
+	defer rows.Close()
+
+	transactions := make([]*banking.Transaction, 0)
+	for rows.Next() {
+		t, err := scanBankTransaction(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler lançamento do extrato: %w", err)
+		}
+		transactions = append(transactions, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar lançamentos do extrato: %w", err)
+	}
+
+	return transactions, nil
+}
+
+// CountTransactions implementa banking.Repository.CountTransactions
+func (r *BankingRepository) CountTransactions(ctx context.Context, filter banking.TransactionFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildBankTransactionFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.bank_transactions WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar lançamentos do extrato: %w", err)
+	}
+
+	return count, nil
+}
+
+// FindCandidates implementa banking.Repository.FindCandidates
+func (r *BankingRepository) FindCandidates(ctx context.Context, t *banking.Transaction) ([]banking.Candidate, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Contas de uma filial só conciliam títulos da própria filial
+	var branchID pgtype.Text
+	err = conn.QueryRow(ctx, fmt.Sprintf("SELECT branch_id FROM %s.bank_accounts WHERE id = $1 AND tenant_id = $2", schema),
+		t.AccountID, tenantID).Scan(&branchID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBankAccountNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar conta bancária: %w", err)
+	}
+
+	tables := receivableTables
+	if t.Kind() == banking.MatchPayable {
+		tables = payableTables
+	}
+
+	// Baixas ainda não conciliadas com o mesmo valor e títulos em aberto com o mesmo saldo
+	query := fmt.Sprintf(`
+		SELECT p.id::text, t.id, COALESCE(t.document_number, ''), COALESCE(NULLIF(t.description, ''), c.name),
+			p.paid_at, p.total
+		FROM %[1]s.%[3]s p
+		JOIN %[1]s.%[2]s t ON t.id = p.%[4]s
+		JOIN %[1]s.%[6]s c ON c.id = t.%[7]s
+		WHERE t.tenant_id = $1 AND p.total = ROUND($2::numeric, 2) AND p.paid_at BETWEEN $3 AND $4
+			AND ($7::uuid IS NULL OR t.branch_id = $7)
+			AND NOT EXISTS (SELECT 1 FROM %[1]s.bank_transactions b WHERE b.payment_id = p.id)
+		UNION ALL
+		SELECT '', t.id, COALESCE(t.document_number, ''), COALESCE(NULLIF(t.description, ''), c.name),
+			t.due_date, t.amount - t.%[5]s
+		FROM %[1]s.%[2]s t
+		JOIN %[1]s.%[6]s c ON c.id = t.%[7]s
+		WHERE t.tenant_id = $1 AND t.status IN ('open', 'partial') AND t.amount - t.%[5]s = ROUND($2::numeric, 2)
+			AND t.due_date BETWEEN $5 AND $6
+			AND ($7::uuid IS NULL OR t.branch_id = $7)
+		ORDER BY 5
+		LIMIT 50
+	`, schema, tables.titles, tables.payments, tables.titleFK, tables.settled, tables.parties, tables.partyFK)
+
+	amount := t.Amount
+	if amount < 0 {
+		amount = -amount
+	}
+	paymentTolerance := time.Duration(banking.PaymentDateTolerance) * 24 * time.Hour
+	dueTolerance := time.Duration(banking.DueDateTolerance) * 24 * time.Hour
+
+	rows, err := conn.Query(ctx, query, tenantID, amount, t.PostedAt.Add(-paymentTolerance),
+		t.PostedAt.Add(paymentTolerance), t.PostedAt.Add(-dueTolerance), t.PostedAt.Add(dueTolerance),
+		nullIfEmpty(branchID.String))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar títulos para conciliação: %w", err)
+	}
+	defer rows.Close()
+
+	candidates := make([]banking.Candidate, 0)
+	for rows.Next() {
+		c := banking.Candidate{Kind: t.Kind()}
+		if err := rows.Scan(&c.PaymentID, &c.TitleID, &c.DocumentNumber, &c.Description, &c.Date, &c.Amount); err != nil {
+			return nil, fmt.Errorf("falha ao ler título para conciliação: %w", err)
+		}
+		candidates = append(candidates, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar títulos para conciliação: %w", err)
+	}
+
+	return candidates, nil
+}
+
+// UpdateMatch implementa banking.Repository.UpdateMatch
+func (r *BankingRepository) UpdateMatch(ctx context.Context, t *banking.Transaction, from banking.TransactionStatus, settlement *banking.Settlement) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if settlement != nil {
+		if settlement.Receivable != nil && settlement.ReceivablePayment != nil {
+			if err := applyReceivablePayment(ctx, tx, schema, tenantID, settlement.Receivable, settlement.ReceivablePayment); err != nil {
+				return err
+			}
+		}
+		if settlement.Payable != nil && settlement.PayablePayment != nil {
+			if err := applyPayablePayment(ctx, tx, schema, tenantID, settlement.Payable, settlement.PayablePayment); err != nil {
+				return err
+			}
+		}
+	}
+
+	// A situação anterior impede que duas conciliações simultâneas usem o mesmo lançamento
+	result, err := tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.bank_transactions
+		SET status = $1, match_kind = $2, title_id = $3, payment_id = $4, matched_auto = $5, matched_by = $6,
+			matched_at = $7, updated_at = $8
+		WHERE id = $9 AND tenant_id = $10 AND status = $11
+	`, schema), string(t.Status), nullIfEmpty(string(t.MatchKind)), nullIfEmpty(t.TitleID),
+		nullIfEmpty(t.PaymentID), t.MatchedAuto, nullIfEmpty(t.MatchedBy), t.MatchedAt, t.UpdatedAt, t.ID,
+		tenantID, string(from))
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrBankPaymentAlreadyMatched
+		}
+		return fmt.Errorf("falha ao atualizar conciliação do lançamento: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrBankTransactionConcurrent
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// CashFlowData implementa banking.Repository.CashFlowData
+func (r *BankingRepository) CashFlowData(ctx context.Context, filter banking.CashFlowFilter) (*banking.CashFlowData, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	branchID := nullIfEmpty(filter.BranchID)
+
+	// O saldo inicial das contas já contempla a movimentação anterior à data de abertura
+	var openingBalance float64
+	var openingDate pgtype.Date
+	err = conn.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COALESCE(SUM(opening_balance), 0), MIN(opening_date)
+		FROM %s.bank_accounts
+		WHERE tenant_id = $1 AND active AND ($2::uuid IS NULL OR branch_id = $2)
+	`, schema), tenantID, branchID).Scan(&openingBalance, &openingDate)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao calcular saldo inicial das contas: %w", err)
+	}
+
+	var since interface{}
+	if openingDate.Valid {
+		since = openingDate.Time
+	}
+
+	data := &banking.CashFlowData{OpeningBalance: openingBalance}
+	for _, tables := range []bankingTitleTables{receivableTables, payableTables} {
+		var before float64
+		err = conn.QueryRow(ctx, fmt.Sprintf(`
+			SELECT COALESCE(SUM(p.total), 0)
+			FROM %[1]s.%[3]s p
+			JOIN %[1]s.%[2]s t ON t.id = p.%[4]s
+			WHERE t.tenant_id = $1 AND ($2::uuid IS NULL OR t.branch_id = $2)
+				AND p.paid_at < $3 AND ($4::date IS NULL OR p.paid_at >= $4)
+		`, schema, tables.titles, tables.payments, tables.titleFK), tenantID, branchID, filter.From, since).Scan(&before)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao calcular saldo anterior ao período: %w", err)
+		}
+
+		realized, err := cashFlowEntries(ctx, conn, fmt.Sprintf(`
+			SELECT p.paid_at, SUM(p.total)
+			FROM %[1]s.%[3]s p
+			JOIN %[1]s.%[2]s t ON t.id = p.%[4]s
+			WHERE t.tenant_id = $1 AND ($2::uuid IS NULL OR t.branch_id = $2) AND p.paid_at BETWEEN $3 AND $4
+			GROUP BY p.paid_at
+		`, schema, tables.titles, tables.payments, tables.titleFK), tenantID, branchID, filter.From, filter.To)
+		if err != nil {
+			return nil, err
+		}
+
+		// Títulos vencidos entram no previsto; o domínio os projeta para hoje
+		expected, err := cashFlowEntries(ctx, conn, fmt.Sprintf(`
+			SELECT due_date, SUM(amount - %[3]s)
+			FROM %[1]s.%[2]s
+			WHERE tenant_id = $1 AND ($2::uuid IS NULL OR branch_id = $2) AND status IN ('open', 'partial')
+				AND due_date <= $3
+			GROUP BY due_date
+		`, schema, tables.titles, tables.settled), tenantID, branchID, filter.To)
+		if err != nil {
+			return nil, err
+		}
+
+		if tables == receivableTables {
+			data.OpeningBalance += before
+			data.Received = realized
+			data.ExpectedReceipts = expected
+		} else {
+			data.OpeningBalance -= before
+			data.Paid = realized
+			data.ExpectedPayments = expected
+		}
+	}
+
+	return data, nil
+}
+
+// cashFlowEntries lê os totais diários (data, valor) de uma consulta do fluxo de caixa
+func cashFlowEntries(ctx context.Context, conn *pgxpool.Conn, query string, args ...interface{}) ([]banking.CashFlowEntry, error) {
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao consultar fluxo de caixa: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]banking.CashFlowEntry, 0)
+	for rows.Next() {
+		var e banking.CashFlowEntry
+		if err := rows.Scan(&e.Date, &e.Amount); err != nil {
+			return nil, fmt.Errorf("falha ao ler fluxo de caixa: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar fluxo de caixa: %w", err)
+	}
+
+	return entries, nil
+}
+
+// buildBankTransactionFilter monta a cláusula WHERE para as consultas de lançamentos do extrato
+func buildBankTransactionFilter(tenantID string, filter banking.TransactionFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.AccountID != "" {
+		args = append(args, filter.AccountID)
+		conditions = append(conditions, fmt.Sprintf("account_id = $%d", len(args)))
+	}
+	if filter.ImportID != "" {
+		args = append(args, filter.ImportID)
+		conditions = append(conditions, fmt.Sprintf("import_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("posted_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("posted_at <= $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// nullIfZeroTime converte datas não informadas em NULL
+func nullIfZeroTime(value time.Time) interface{} {
+	if value.IsZero() {
+		return nil
+	}
+	return value
+}
+
+// scanBankAccount lê uma conta bancária de uma linha de resultado
+func scanBankAccount(row pgx.Row) (*banking.Account, error) {
+	var a banking.Account
+	var accountType string
+	var branchID pgtype.Text
+
+	err := row.Scan(&a.ID, &a.TenantID, &branchID, &a.Name, &a.BankCode, &a.Agency, &a.AccountNumber,
+		&accountType, &a.OpeningBalance, &a.OpeningDate, &a.Active, &a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	a.BranchID = branchID.String
+	a.Type = banking.AccountType(accountType)
+	return &a, nil
+}
+
+// scanBankTransaction lê um lançamento do extrato de uma linha de resultado
+func scanBankTransaction(row pgx.Row) (*banking.Transaction, error) {
+	var t banking.Transaction
+	var status, matchKind string
+	var titleID, paymentID, matchedBy pgtype.Text
+	var matchedAt pgtype.Timestamp
+
+	err := row.Scan(&t.ID, &t.TenantID, &t.AccountID, &t.ImportID, &t.FITID, &t.Type, &t.PostedAt, &t.Amount,
+		&t.Document, &t.Memo, &status, &matchKind, &titleID, &paymentID, &t.MatchedAuto, &matchedBy, &matchedAt,
+		&t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Status = banking.TransactionStatus(status)
+	t.MatchKind = banking.MatchKind(matchKind)
+	t.TitleID = titleID.String
+	t.PaymentID = paymentID.String
+	t.MatchedBy = matchedBy.String
+	if matchedAt.Valid {
+		t.MatchedAt = &matchedAt.Time
+	}
+	return &t, nil
+}
@@ -0,0 +1,876 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/card"
+	"github.com/hugohenrick/erp-supermercado/pkg/acquirer"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de conciliação de cartões
+var (
+	ErrCardFeeNotFound    = errors.New("taxa de adquirente não encontrada")
+	ErrCardFeeDuplicated  = errors.New("já existe taxa para a adquirente, bandeira, produto e parcela inicial")
+	ErrCardSaleNotFound   = errors.New("venda no cartão não encontrada")
+	ErrCardSaleDuplicated = errors.New("venda no cartão já registrada para este NSU ou pagamento")
+)
+
+// CardRepository implementa a interface card.Repository
+type CardRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCardRepository cria uma nova instância de CardRepository
+func NewCardRepository(db *pgxpool.Pool) card.Repository {
+	return &CardRepository{
+		db: db,
+	}
+}
+
+const cardFeeColumns = `id, tenant_id, acquirer, brand, product, installments_from, installments_to, fee_percent,
+	settlement_days, active, created_at, updated_at`
+
+const cardSaleColumns = `id, tenant_id, branch_id, sale_id, payment_id, acquirer, brand, product, installments, amount,
+	fee_percent, fee_amount, net_amount, nsu, COALESCE(authorization_code, ''), COALESCE(card_last_digits, ''),
+	sold_at, status, created_by, created_at, updated_at`
+
+const cardSettlementColumns = `s.id, s.tenant_id, s.card_sale_id, s.installment, s.expected_date, s.gross_amount,
+	s.fee_amount, s.net_amount, s.status, s.paid_date, s.paid_gross, s.paid_fee, s.paid_net, s.difference,
+	s.record_id, COALESCE(s.message, ''), s.updated_at, c.acquirer, c.brand, c.nsu, c.branch_id`
+
+const cardRecordColumns = `id, tenant_id, file_id, acquirer, line, sale_date, payment_date, nsu,
+	COALESCE(authorization_code, ''), COALESCE(brand, ''), installment, installments, gross_amount, fee_amount,
+	net_amount, settlement_id, status, COALESCE(message, ''), created_at`
+
+// CreateFeeTable implementa card.Repository.CreateFeeTable
+func (r *CardRepository) CreateFeeTable(ctx context.Context, f *card.FeeTable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	f.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.card_fee_tables (
+			id, tenant_id, acquirer, brand, product, installments_from, installments_to, fee_percent,
+			settlement_days, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, f.ID, f.TenantID, f.Acquirer, f.Brand, string(f.Product), f.InstallmentsFrom,
+		f.InstallmentsTo, f.FeePercent, f.SettlementDays, f.Active, f.CreatedAt, f.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrCardFeeDuplicated
+		}
+		return fmt.Errorf("falha ao criar taxa de adquirente: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateFeeTable implementa card.Repository.UpdateFeeTable
+func (r *CardRepository) UpdateFeeTable(ctx context.Context, f *card.FeeTable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.card_fee_tables
+		SET acquirer = $1, brand = $2, product = $3, installments_from = $4, installments_to = $5,
+			fee_percent = $6, settlement_days = $7, active = $8, updated_at = $9
+		WHERE id = $10 AND tenant_id = $11
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, f.Acquirer, f.Brand, string(f.Product), f.InstallmentsFrom,
+		f.InstallmentsTo, f.FeePercent, f.SettlementDays, f.Active, f.UpdatedAt, f.ID, tenantID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrCardFeeDuplicated
+		}
+		return fmt.Errorf("falha ao atualizar taxa de adquirente: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCardFeeNotFound
+	}
+
+	return nil
+}
+
+// FindFeeTableByID implementa card.Repository.FindFeeTableByID
+func (r *CardRepository) FindFeeTableByID(ctx context.Context, id string) (*card.FeeTable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.card_fee_tables WHERE id = $1 AND tenant_id = $2", cardFeeColumns, schema)
+
+	f, err := scanCardFee(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCardFeeNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar taxa de adquirente: %w", err)
+	}
+
+	return f, nil
+}
+
+// ListFeeTables implementa card.Repository.ListFeeTables
+func (r *CardRepository) ListFeeTables(ctx context.Context, acquirerName string) ([]*card.FeeTable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+	if acquirerName != "" {
+		args = append(args, card.NormalizeAcquirer(acquirerName))
+		conditions = append(conditions, fmt.Sprintf("acquirer = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.card_fee_tables
+		WHERE %s
+		ORDER BY acquirer, brand, product, installments_from
+	`, cardFeeColumns, schema, strings.Join(conditions, " AND "))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar taxas de adquirente: %w", err)
+	}
+	defer rows.Close()
+
+	fees := make([]*card.FeeTable, 0)
+	for rows.Next() {
+		f, err := scanCardFee(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler taxa de adquirente: %w", err)
+		}
+		fees = append(fees, f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar taxas de adquirente: %w", err)
+	}
+
+	return fees, nil
+}
+
+// CreateSale implementa card.Repository.CreateSale
+func (r *CardRepository) CreateSale(ctx context.Context, s *card.Sale) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	s.TenantID = tenantID
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.card_sales (
+			id, tenant_id, branch_id, sale_id, payment_id, acquirer, brand, product, installments, amount,
+			fee_percent, fee_amount, net_amount, nsu, authorization_code, card_last_digits, sold_at, status,
+			created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`, schema), s.ID, s.TenantID, s.BranchID, nullIfEmpty(s.SaleID), nullIfEmpty(s.PaymentID), s.Acquirer, s.Brand,
+		string(s.Product), s.Installments, s.Amount, s.FeePercent, s.FeeAmount, s.NetAmount, s.NSU,
+		nullIfEmpty(s.AuthorizationCode), nullIfEmpty(s.CardLastDigits), s.SoldAt, string(s.Status),
+		nullIfEmpty(s.CreatedBy), s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrCardSaleDuplicated
+		}
+		return fmt.Errorf("falha ao registrar venda no cartão: %w", err)
+	}
+
+	insert := fmt.Sprintf(`
+		INSERT INTO %s.card_settlements (
+			id, tenant_id, card_sale_id, installment, expected_date, gross_amount, fee_amount, net_amount,
+			status, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, schema)
+
+	for i := range s.Settlements {
+		st := &s.Settlements[i]
+		st.TenantID = tenantID
+		_, err = tx.Exec(ctx, insert, st.ID, st.TenantID, s.ID, st.Installment, st.ExpectedDate, st.Gross, st.Fee,
+			st.Net, string(st.Status), st.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("falha ao gravar parcela prevista: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// FindSaleByID implementa card.Repository.FindSaleByID
+func (r *CardRepository) FindSaleByID(ctx context.Context, id string) (*card.Sale, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.card_sales WHERE id = $1 AND tenant_id = $2", cardSaleColumns, schema)
+
+	s, err := scanCardSale(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCardSaleNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar venda no cartão: %w", err)
+	}
+
+	settlementsQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.card_settlements s
+		JOIN %s.card_sales c ON c.id = s.card_sale_id
+		WHERE s.card_sale_id = $1
+		ORDER BY s.installment
+	`, cardSettlementColumns, schema, schema)
+
+	rows, err := conn.Query(ctx, settlementsQuery, s.ID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar parcelas da venda no cartão: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		st, err := scanCardSettlement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler parcela da venda no cartão: %w", err)
+		}
+		s.Settlements = append(s.Settlements, *st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar parcelas da venda no cartão: %w", err)
+	}
+
+	return s, nil
+}
+
+// ListSales implementa card.Repository.ListSales
+func (r *CardRepository) ListSales(ctx context.Context, filter card.SaleFilter, limit, offset int) ([]*card.Sale, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildCardSaleFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.card_sales
+		WHERE %s
+		ORDER BY sold_at DESC
+		LIMIT $%d OFFSET $%d
+	`, cardSaleColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar vendas no cartão: %w", err)
+	}
+	defer rows.Close()
+
+	sales := make([]*card.Sale, 0)
+	for rows.Next() {
+		s, err := scanCardSale(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler venda no cartão: %w", err)
+		}
+		sales = append(sales, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar vendas no cartão: %w", err)
+	}
+
+	return sales, nil
+}
+
+// CountSales implementa card.Repository.CountSales
+func (r *CardRepository) CountSales(ctx context.Context, filter card.SaleFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildCardSaleFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.card_sales WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar vendas no cartão: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListSettlements implementa card.Repository.ListSettlements
+func (r *CardRepository) ListSettlements(ctx context.Context, filter card.SettlementFilter, limit, offset int) ([]*card.Settlement, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildCardSettlementFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.card_settlements s
+		JOIN %s.card_sales c ON c.id = s.card_sale_id
+		WHERE %s
+		ORDER BY s.expected_date, c.nsu, s.installment
+		LIMIT $%d OFFSET $%d
+	`, cardSettlementColumns, schema, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar agenda de recebimentos: %w", err)
+	}
+	defer rows.Close()
+
+	settlements := make([]*card.Settlement, 0)
+	for rows.Next() {
+		st, err := scanCardSettlement(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler parcela da agenda: %w", err)
+		}
+		settlements = append(settlements, st)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar agenda de recebimentos: %w", err)
+	}
+
+	return settlements, nil
+}
+
+// CountSettlements implementa card.Repository.CountSettlements
+func (r *CardRepository) CountSettlements(ctx context.Context, filter card.SettlementFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildCardSettlementFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM %s.card_settlements s
+		JOIN %s.card_sales c ON c.id = s.card_sale_id
+		WHERE %s
+	`, schema, schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar agenda de recebimentos: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateFile implementa card.Repository.CreateFile
+func (r *CardRepository) CreateFile(ctx context.Context, f *card.ImportFile) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	f.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.card_settlement_files (
+			id, tenant_id, acquirer, format, file_name, records, matched, divergent, unmatched, duplicates,
+			imported_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, f.ID, f.TenantID, f.Acquirer, string(f.Format), nullIfEmpty(f.FileName),
+		f.Records, f.Matched, f.Divergent, f.Unmatched, f.Duplicates, nullIfEmpty(f.ImportedBy), f.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao registrar arquivo da adquirente: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateFileTotals implementa card.Repository.UpdateFileTotals
+func (r *CardRepository) UpdateFileTotals(ctx context.Context, f *card.ImportFile) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.card_settlement_files
+		SET matched = $1, divergent = $2, unmatched = $3, duplicates = $4
+		WHERE id = $5 AND tenant_id = $6
+	`, schema)
+
+	if _, err := conn.Exec(ctx, query, f.Matched, f.Divergent, f.Unmatched, f.Duplicates, f.ID, tenantID); err != nil {
+		return fmt.Errorf("falha ao atualizar arquivo da adquirente: %w", err)
+	}
+
+	return nil
+}
+
+// ListFiles implementa card.Repository.ListFiles
+func (r *CardRepository) ListFiles(ctx context.Context, limit, offset int) ([]*card.ImportFile, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, acquirer, format, COALESCE(file_name, ''), records, matched, divergent, unmatched,
+			duplicates, imported_by, created_at
+		FROM %s.card_settlement_files
+		WHERE tenant_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar arquivos das adquirentes: %w", err)
+	}
+	defer rows.Close()
+
+	files := make([]*card.ImportFile, 0)
+	for rows.Next() {
+		var f card.ImportFile
+		var format string
+		var importedBy pgtype.Text
+		if err := rows.Scan(&f.ID, &f.TenantID, &f.Acquirer, &format, &f.FileName, &f.Records, &f.Matched,
+			&f.Divergent, &f.Unmatched, &f.Duplicates, &importedBy, &f.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler arquivo da adquirente: %w", err)
+		}
+		f.Format = acquirer.Format(format)
+		f.ImportedBy = importedBy.String
+		files = append(files, &f)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar arquivos das adquirentes: %w", err)
+	}
+
+	return files, nil
+}
+
+// ReconcileRecord implementa card.Repository.ReconcileRecord
+func (r *CardRepository) ReconcileRecord(ctx context.Context, rec *card.Record) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	rec.TenantID = tenantID
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Registros sem venda correspondente são reprocessados quando reenviados em outro arquivo
+	err = tx.QueryRow(ctx, fmt.Sprintf(`
+		INSERT INTO %[1]s.card_settlement_records (
+			id, tenant_id, file_id, acquirer, line, sale_date, payment_date, nsu, authorization_code, brand,
+			installment, installments, gross_amount, fee_amount, net_amount, status, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (tenant_id, acquirer, nsu, installment) DO UPDATE SET
+			file_id = EXCLUDED.file_id, line = EXCLUDED.line, sale_date = EXCLUDED.sale_date,
+			payment_date = EXCLUDED.payment_date, authorization_code = EXCLUDED.authorization_code,
+			brand = EXCLUDED.brand, installments = EXCLUDED.installments, gross_amount = EXCLUDED.gross_amount,
+			fee_amount = EXCLUDED.fee_amount, net_amount = EXCLUDED.net_amount, created_at = EXCLUDED.created_at
+		WHERE %[1]s.card_settlement_records.status = 'unmatched'
+		RETURNING id
+	`, schema), rec.ID, rec.TenantID, rec.FileID, rec.Acquirer, rec.Line, nullIfZeroTime(rec.SaleDate),
+		nullIfZeroTime(rec.PaymentDate), rec.NSU, nullIfEmpty(rec.AuthorizationCode), nullIfEmpty(rec.Brand),
+		rec.Installment, rec.Installments, rec.Gross, rec.Fee, rec.Net, string(card.RecordUnmatched),
+		rec.CreatedAt).Scan(&rec.ID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return card.ErrRecordProcessed
+		}
+		return fmt.Errorf("falha ao registrar pagamento da adquirente: %w", err)
+	}
+
+	st, err := findCardSettlement(ctx, tx, schema, tenantID, rec)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case st == nil:
+		rec.Status = card.RecordUnmatched
+		rec.Message = "nenhuma venda registrada com este NSU e parcela"
+	default:
+		if err := st.Reconcile(rec); err != nil {
+			if !errors.Is(err, card.ErrSettlementAlreadyFinal) {
+				return err
+			}
+			rec.Status = card.RecordDivergent
+			rec.Message = err.Error()
+			break
+		}
+
+		result, err := tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE %s.card_settlements
+			SET status = $1, paid_date = $2, paid_gross = $3, paid_fee = $4, paid_net = $5, difference = $6,
+				record_id = $7, message = $8, updated_at = $9
+			WHERE id = $10 AND status = 'expected'
+		`, schema), string(st.Status), st.PaidDate, st.PaidGross, st.PaidFee, st.PaidNet, st.Difference,
+			st.RecordID, nullIfEmpty(st.Message), st.UpdatedAt, st.ID)
+		if err != nil {
+			return fmt.Errorf("falha ao conciliar parcela prevista: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return card.ErrSettlementAlreadyFinal
+		}
+
+		// A venda fica divergente com qualquer parcela divergente e liquidada quando todas foram pagas
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE %[1]s.card_sales c SET
+				status = CASE
+					WHEN EXISTS (SELECT 1 FROM %[1]s.card_settlements s WHERE s.card_sale_id = c.id AND s.status = 'divergent') THEN 'divergent'
+					WHEN NOT EXISTS (SELECT 1 FROM %[1]s.card_settlements s WHERE s.card_sale_id = c.id AND s.status = 'expected') THEN 'settled'
+					ELSE 'pending'
+				END,
+				updated_at = $1
+			WHERE c.id = $2
+		`, schema), st.UpdatedAt, st.CardSaleID)
+		if err != nil {
+			return fmt.Errorf("falha ao atualizar situação da venda no cartão: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.card_settlement_records SET settlement_id = $1, status = $2, message = $3 WHERE id = $4
+	`, schema), nullIfEmpty(rec.SettlementID), string(rec.Status), nullIfEmpty(rec.Message), rec.ID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar pagamento da adquirente: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// ListRecords implementa card.Repository.ListRecords
+func (r *CardRepository) ListRecords(ctx context.Context, fileID string, status card.RecordStatus) ([]*card.Record, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"tenant_id = $1", "file_id = $2"}
+	args := []interface{}{tenantID, fileID}
+	if status != "" {
+		args = append(args, string(status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.card_settlement_records
+		WHERE %s
+		ORDER BY line
+	`, cardRecordColumns, schema, strings.Join(conditions, " AND "))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar pagamentos da adquirente: %w", err)
+	}
+	defer rows.Close()
+
+	records := make([]*card.Record, 0)
+	for rows.Next() {
+		var rec card.Record
+		var recordStatus string
+		var saleDate, paymentDate pgtype.Date
+		var settlementID pgtype.Text
+		if err := rows.Scan(&rec.ID, &rec.TenantID, &rec.FileID, &rec.Acquirer, &rec.Line, &saleDate, &paymentDate,
+			&rec.NSU, &rec.AuthorizationCode, &rec.Brand, &rec.Installment, &rec.Installments, &rec.Gross, &rec.Fee,
+			&rec.Net, &settlementID, &recordStatus, &rec.Message, &rec.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler pagamento da adquirente: %w", err)
+		}
+		rec.SaleDate = saleDate.Time
+		rec.PaymentDate = paymentDate.Time
+		rec.SettlementID = settlementID.String
+		rec.Status = card.RecordStatus(recordStatus)
+		records = append(records, &rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar pagamentos da adquirente: %w", err)
+	}
+
+	return records, nil
+}
+
+// findCardSettlement bloqueia a parcela prevista do registro, buscando pelo NSU e, se não houver,
+// pela autorização e data da venda
+func findCardSettlement(ctx context.Context, tx pgx.Tx, schema, tenantID string, rec *card.Record) (*card.Settlement, error) {
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.card_settlements s
+		JOIN %s.card_sales c ON c.id = s.card_sale_id
+		WHERE c.tenant_id = $1 AND c.acquirer = $2 AND s.installment = $3 AND %%s
+		ORDER BY c.sold_at DESC
+		LIMIT 1
+		FOR UPDATE OF s
+	`, cardSettlementColumns, schema, schema)
+
+	st, err := scanCardSettlement(tx.QueryRow(ctx, fmt.Sprintf(query, "c.nsu = $4"), tenantID, rec.Acquirer,
+		rec.Installment, rec.NSU))
+	if err == nil {
+		return st, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("falha ao buscar parcela prevista: %w", err)
+	}
+	if rec.AuthorizationCode == "" || rec.SaleDate.IsZero() {
+		return nil, nil
+	}
+
+	st, err = scanCardSettlement(tx.QueryRow(ctx, fmt.Sprintf(query, "c.authorization_code = $4 AND c.sold_at::date = $5"),
+		tenantID, rec.Acquirer, rec.Installment, rec.AuthorizationCode, rec.SaleDate))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("falha ao buscar parcela prevista: %w", err)
+	}
+	return st, nil
+}
+
+// buildCardSaleFilter monta a cláusula WHERE para as consultas de vendas no cartão
+func buildCardSaleFilter(tenantID string, filter card.SaleFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("branch_id = $%d", len(args)))
+	}
+	if filter.Acquirer != "" {
+		args = append(args, card.NormalizeAcquirer(filter.Acquirer))
+		conditions = append(conditions, fmt.Sprintf("acquirer = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("sold_at >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("sold_at <= $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// buildCardSettlementFilter monta a cláusula WHERE para as consultas da agenda de recebimentos
+func buildCardSettlementFilter(tenantID string, filter card.SettlementFilter) (string, []interface{}) {
+	conditions := []string{"c.tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("c.branch_id = $%d", len(args)))
+	}
+	if filter.Acquirer != "" {
+		args = append(args, card.NormalizeAcquirer(filter.Acquirer))
+		conditions = append(conditions, fmt.Sprintf("c.acquirer = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("s.status = $%d", len(args)))
+	}
+	if !filter.From.IsZero() {
+		args = append(args, filter.From)
+		conditions = append(conditions, fmt.Sprintf("s.expected_date >= $%d", len(args)))
+	}
+	if !filter.To.IsZero() {
+		args = append(args, filter.To)
+		conditions = append(conditions, fmt.Sprintf("s.expected_date <= $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanCardFee lê uma taxa de adquirente de uma linha de resultado
+func scanCardFee(row pgx.Row) (*card.FeeTable, error) {
+	var f card.FeeTable
+	var product string
+
+	err := row.Scan(&f.ID, &f.TenantID, &f.Acquirer, &f.Brand, &product, &f.InstallmentsFrom, &f.InstallmentsTo,
+		&f.FeePercent, &f.SettlementDays, &f.Active, &f.CreatedAt, &f.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	f.Product = card.Product(product)
+	return &f, nil
+}
+
+// scanCardSale lê uma venda no cartão de uma linha de resultado
+func scanCardSale(row pgx.Row) (*card.Sale, error) {
+	var s card.Sale
+	var product, status string
+	var saleID, paymentID, createdBy pgtype.Text
+
+	err := row.Scan(&s.ID, &s.TenantID, &s.BranchID, &saleID, &paymentID, &s.Acquirer, &s.Brand, &product,
+		&s.Installments, &s.Amount, &s.FeePercent, &s.FeeAmount, &s.NetAmount, &s.NSU, &s.AuthorizationCode,
+		&s.CardLastDigits, &s.SoldAt, &status, &createdBy, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.SaleID = saleID.String
+	s.PaymentID = paymentID.String
+	s.CreatedBy = createdBy.String
+	s.Product = card.Product(product)
+	s.Status = card.SaleStatus(status)
+	s.Settlements = []card.Settlement{}
+	return &s, nil
+}
+
+// scanCardSettlement lê uma parcela prevista de uma linha de resultado
+func scanCardSettlement(row pgx.Row) (*card.Settlement, error) {
+	var st card.Settlement
+	var status string
+	var paidDate pgtype.Date
+	var recordID pgtype.Text
+
+	err := row.Scan(&st.ID, &st.TenantID, &st.CardSaleID, &st.Installment, &st.ExpectedDate, &st.Gross, &st.Fee,
+		&st.Net, &status, &paidDate, &st.PaidGross, &st.PaidFee, &st.PaidNet, &st.Difference, &recordID,
+		&st.Message, &st.UpdatedAt, &st.Acquirer, &st.Brand, &st.NSU, &st.BranchID)
+	if err != nil {
+		return nil, err
+	}
+
+	st.Status = card.SettlementStatus(status)
+	st.RecordID = recordID.String
+	if paidDate.Valid {
+		st.PaidDate = &paidDate.Time
+	}
+	return &st, nil
+}
@@ -0,0 +1,642 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/collection"
+	"github.com/hugohenrick/erp-supermercado/pkg/cnab"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de cobrança
+var (
+	ErrCollectionAccountNotFound = errors.New("conta de cobrança não encontrada")
+	ErrBoletoNotFound            = errors.New("boleto não encontrado")
+	ErrBoletoAlreadyIssued       = errors.New("título já possui boleto ativo")
+	ErrRemittanceNotFound        = errors.New("arquivo de remessa não encontrado")
+	ErrNoPendingBoletos          = errors.New("não há boletos pendentes de remessa para a conta")
+)
+
+// CollectionRepository implementa a interface collection.Repository
+type CollectionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewCollectionRepository cria uma nova instância de CollectionRepository
+func NewCollectionRepository(db *pgxpool.Pool) collection.Repository {
+	return &CollectionRepository{
+		db: db,
+	}
+}
+
+const collectionAccountColumns = `id, tenant_id, name, bank_code, agency, COALESCE(agency_digit, ''), account,
+	COALESCE(account_digit, ''), wallet, COALESCE(agreement, ''), beneficiary_name, beneficiary_document, layout,
+	interest_rate, fine_percent, COALESCE(instructions, ''), next_our_number, next_remittance, active,
+	created_at, updated_at`
+
+const boletoColumns = `id, tenant_id, account_id, receivable_id, customer_id, our_number,
+	COALESCE(our_number_digit, ''), printed_number, COALESCE(document_number, ''), barcode, digitable_line,
+	amount, issue_date, due_date, status, remittance_id, COALESCE(occurrence, ''), paid_amount, paid_at,
+	created_at, updated_at`
+
+// CreateAccount implementa collection.Repository.CreateAccount
+func (r *CollectionRepository) CreateAccount(ctx context.Context, a *collection.Account) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	a.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.collection_accounts (
+			id, tenant_id, name, bank_code, agency, agency_digit, account, account_digit, wallet, agreement,
+			beneficiary_name, beneficiary_document, layout, interest_rate, fine_percent, instructions,
+			next_our_number, next_remittance, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query,
+		a.ID, a.TenantID, a.Name, a.BankCode, a.Agency, nullIfEmpty(a.AgencyDigit), a.Account,
+		nullIfEmpty(a.AccountDigit), a.Wallet, nullIfEmpty(a.Agreement), a.BeneficiaryName,
+		a.BeneficiaryDocument, string(a.Layout), a.InterestRate, a.FinePercent, nullIfEmpty(a.Instructions),
+		a.NextOurNumber, a.NextRemittance, a.Active, a.CreatedAt, a.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao criar conta de cobrança: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateAccount implementa collection.Repository.UpdateAccount
+func (r *CollectionRepository) UpdateAccount(ctx context.Context, a *collection.Account) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	// As sequências de nosso número e remessa só são alteradas pelas operações de emissão
+	query := fmt.Sprintf(`
+		UPDATE %s.collection_accounts SET
+			name = $1, agency = $2, agency_digit = $3, account = $4, account_digit = $5, wallet = $6,
+			agreement = $7, beneficiary_name = $8, beneficiary_document = $9, layout = $10,
+			interest_rate = $11, fine_percent = $12, instructions = $13, active = $14, updated_at = $15
+		WHERE id = $16 AND tenant_id = $17
+	`, schema)
+
+	result, err := conn.Exec(ctx, query,
+		a.Name, a.Agency, nullIfEmpty(a.AgencyDigit), a.Account, nullIfEmpty(a.AccountDigit), a.Wallet,
+		nullIfEmpty(a.Agreement), a.BeneficiaryName, a.BeneficiaryDocument, string(a.Layout),
+		a.InterestRate, a.FinePercent, nullIfEmpty(a.Instructions), a.Active, a.UpdatedAt, a.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar conta de cobrança: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCollectionAccountNotFound
+	}
+
+	return nil
+}
+
+// FindAccountByID implementa collection.Repository.FindAccountByID
+func (r *CollectionRepository) FindAccountByID(ctx context.Context, id string) (*collection.Account, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.collection_accounts WHERE id = $1 AND tenant_id = $2",
+		collectionAccountColumns, schema)
+
+	a, err := scanCollectionAccount(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCollectionAccountNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar conta de cobrança: %w", err)
+	}
+
+	return a, nil
+}
+
+// ListAccounts implementa collection.Repository.ListAccounts
+func (r *CollectionRepository) ListAccounts(ctx context.Context) ([]*collection.Account, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.collection_accounts WHERE tenant_id = $1 ORDER BY name",
+		collectionAccountColumns, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar contas de cobrança: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := make([]*collection.Account, 0)
+	for rows.Next() {
+		a, err := scanCollectionAccount(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler conta de cobrança: %w", err)
+		}
+		accounts = append(accounts, a)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar contas de cobrança: %w", err)
+	}
+
+	return accounts, nil
+}
+
+// CreateBoleto implementa collection.Repository.CreateBoleto
+func (r *CollectionRepository) CreateBoleto(ctx context.Context, accountID string, build func(a *collection.Account, ourNumber int64) (*collection.Boleto, error)) (*collection.Boleto, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Bloquear a conta garante que o nosso número não seja reutilizado
+	a, err := lockCollectionAccount(ctx, tx, schema, tenantID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	b, err := build(a, a.NextOurNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.boletos (
+			id, tenant_id, account_id, receivable_id, customer_id, our_number, our_number_digit, printed_number,
+			document_number, barcode, digitable_line, amount, issue_date, due_date, status, paid_amount,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18)
+	`, schema), b.ID, tenantID, a.ID, b.ReceivableID, b.CustomerID, b.OurNumber, nullIfEmpty(b.OurNumberDigit),
+		b.PrintedNumber, nullIfEmpty(b.DocumentNumber), b.Barcode, b.DigitableLine, b.Amount, b.IssueDate,
+		b.DueDate, string(b.Status), b.PaidAmount, b.CreatedAt, b.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrBoletoAlreadyIssued
+		}
+		return nil, fmt.Errorf("falha ao gravar boleto: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.collection_accounts SET next_our_number = next_our_number + 1, updated_at = $1 WHERE id = $2
+	`, schema), time.Now(), a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao atualizar sequência do nosso número: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	b.TenantID = tenantID
+	return b, nil
+}
+
+// FindBoletoByID implementa collection.Repository.FindBoletoByID
+func (r *CollectionRepository) FindBoletoByID(ctx context.Context, id string) (*collection.Boleto, error) {
+	return r.findBoleto(ctx, "id = $1", id)
+}
+
+// FindBoletoByOurNumber implementa collection.Repository.FindBoletoByOurNumber
+func (r *CollectionRepository) FindBoletoByOurNumber(ctx context.Context, accountID, ourNumber string) (*collection.Boleto, error) {
+	return r.findBoleto(ctx, "account_id = $1 AND our_number = $3", accountID, ourNumber)
+}
+
+// findBoleto busca um boleto pela condição informada; $2 é sempre o tenant ID
+func (r *CollectionRepository) findBoleto(ctx context.Context, condition string, first string, extra ...interface{}) (*collection.Boleto, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.boletos WHERE %s AND tenant_id = $2", boletoColumns, schema, condition)
+	args := append([]interface{}{first, tenantID}, extra...)
+
+	b, err := scanBoleto(conn.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrBoletoNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar boleto: %w", err)
+	}
+
+	return b, nil
+}
+
+// ListBoletos implementa collection.Repository.ListBoletos
+func (r *CollectionRepository) ListBoletos(ctx context.Context, filter collection.BoletoFilter, limit, offset int) ([]*collection.Boleto, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildBoletoFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.boletos
+		WHERE %s
+		ORDER BY due_date, our_number
+		LIMIT $%d OFFSET $%d
+	`, boletoColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar boletos: %w", err)
+	}
+	defer rows.Close()
+
+	boletos := make([]*collection.Boleto, 0)
+	for rows.Next() {
+		b, err := scanBoleto(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler boleto: %w", err)
+		}
+		boletos = append(boletos, b)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar boletos: %w", err)
+	}
+
+	return boletos, nil
+}
+
+// CountBoletos implementa collection.Repository.CountBoletos
+func (r *CollectionRepository) CountBoletos(ctx context.Context, filter collection.BoletoFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildBoletoFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.boletos WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar boletos: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdateBoletoStatus implementa collection.Repository.UpdateBoletoStatus
+func (r *CollectionRepository) UpdateBoletoStatus(ctx context.Context, b *collection.Boleto) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	// Boletos liquidados ou baixados não voltam a ser alterados por retornos reprocessados
+	query := fmt.Sprintf(`
+		UPDATE %s.boletos SET status = $1, occurrence = $2, paid_amount = $3, paid_at = $4, updated_at = $5
+		WHERE id = $6 AND tenant_id = $7 AND status NOT IN ('paid', 'cancelled')
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, string(b.Status), nullIfEmpty(b.Occurrence), b.PaidAmount, b.PaidAt,
+		b.UpdatedAt, b.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar boleto: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return collection.ErrBoletoAlreadyFinal
+	}
+
+	return nil
+}
+
+// CreateRemittance implementa collection.Repository.CreateRemittance
+func (r *CollectionRepository) CreateRemittance(ctx context.Context, accountID string, build func(a *collection.Account, sequence int, boletos []*collection.Boleto) (*collection.Remittance, error)) (*collection.Remittance, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	a, err := lockCollectionAccount(ctx, tx, schema, tenantID, accountID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT %s FROM %s.boletos
+		WHERE account_id = $1 AND tenant_id = $2 AND status = 'issued'
+		ORDER BY our_number
+		FOR UPDATE
+	`, boletoColumns, schema), a.ID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar boletos pendentes: %w", err)
+	}
+
+	boletos := make([]*collection.Boleto, 0)
+	for rows.Next() {
+		b, err := scanBoleto(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("falha ao ler boleto: %w", err)
+		}
+		boletos = append(boletos, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar boletos pendentes: %w", err)
+	}
+
+	if len(boletos) == 0 {
+		return nil, ErrNoPendingBoletos
+	}
+
+	remittance, err := build(a, a.NextRemittance, boletos)
+	if err != nil {
+		return nil, err
+	}
+	remittance.TenantID = tenantID
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.collection_remittances (
+			id, tenant_id, account_id, layout, sequence, file_name, content, titles, amount, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, schema), remittance.ID, tenantID, a.ID, string(remittance.Layout), remittance.Sequence, remittance.FileName,
+		remittance.Content, remittance.Titles, remittance.Amount, nullIfEmpty(remittance.CreatedBy),
+		remittance.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao gravar arquivo de remessa: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.boletos SET status = 'remitted', remittance_id = $1, updated_at = $2
+		WHERE id = ANY($3) AND tenant_id = $4
+	`, schema), remittance.ID, remittance.CreatedAt, remittance.BoletoIDs, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao atualizar boletos da remessa: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.collection_accounts SET next_remittance = next_remittance + 1, updated_at = $1 WHERE id = $2
+	`, schema), remittance.CreatedAt, a.ID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao atualizar sequência de remessa: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return remittance, nil
+}
+
+// FindRemittanceByID implementa collection.Repository.FindRemittanceByID
+func (r *CollectionRepository) FindRemittanceByID(ctx context.Context, id string) (*collection.Remittance, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, account_id, layout, sequence, file_name, content, titles, amount, created_by, created_at,
+			ARRAY(SELECT b.id::text FROM %s.boletos b WHERE b.remittance_id = rm.id ORDER BY b.our_number)
+		FROM %s.collection_remittances rm
+		WHERE id = $1 AND tenant_id = $2
+	`, schema, schema)
+
+	var remittance collection.Remittance
+	var layout string
+	var createdBy pgtype.Text
+	err = conn.QueryRow(ctx, query, id, tenantID).Scan(&remittance.ID, &remittance.TenantID, &remittance.AccountID,
+		&layout, &remittance.Sequence, &remittance.FileName, &remittance.Content, &remittance.Titles,
+		&remittance.Amount, &createdBy, &remittance.CreatedAt, &remittance.BoletoIDs)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrRemittanceNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar arquivo de remessa: %w", err)
+	}
+
+	remittance.Layout = cnab.Layout(layout)
+	remittance.CreatedBy = createdBy.String
+	return &remittance, nil
+}
+
+// ListRemittances implementa collection.Repository.ListRemittances
+func (r *CollectionRepository) ListRemittances(ctx context.Context, accountID string, limit, offset int) ([]*collection.Remittance, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, account_id, layout, sequence, file_name, titles, amount, created_by, created_at
+		FROM %s.collection_remittances
+		WHERE account_id = $1 AND tenant_id = $2
+		ORDER BY sequence DESC
+		LIMIT $3 OFFSET $4
+	`, schema)
+
+	rows, err := conn.Query(ctx, query, accountID, tenantID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar arquivos de remessa: %w", err)
+	}
+	defer rows.Close()
+
+	remittances := make([]*collection.Remittance, 0)
+	for rows.Next() {
+		var remittance collection.Remittance
+		var layout string
+		var createdBy pgtype.Text
+		if err := rows.Scan(&remittance.ID, &remittance.TenantID, &remittance.AccountID, &layout,
+			&remittance.Sequence, &remittance.FileName, &remittance.Titles, &remittance.Amount, &createdBy,
+			&remittance.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler arquivo de remessa: %w", err)
+		}
+		remittance.Layout = cnab.Layout(layout)
+		remittance.CreatedBy = createdBy.String
+		remittances = append(remittances, &remittance)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar arquivos de remessa: %w", err)
+	}
+
+	return remittances, nil
+}
+
+// lockCollectionAccount lê e bloqueia a conta de cobrança dentro da transação
+func lockCollectionAccount(ctx context.Context, tx pgx.Tx, schema, tenantID, accountID string) (*collection.Account, error) {
+	query := fmt.Sprintf("SELECT %s FROM %s.collection_accounts WHERE id = $1 AND tenant_id = $2 FOR UPDATE",
+		collectionAccountColumns, schema)
+
+	a, err := scanCollectionAccount(tx.QueryRow(ctx, query, accountID, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCollectionAccountNotFound
+		}
+		return nil, fmt.Errorf("falha ao bloquear conta de cobrança: %w", err)
+	}
+
+	return a, nil
+}
+
+// buildBoletoFilter monta a cláusula WHERE da listagem de boletos
+func buildBoletoFilter(tenantID string, filter collection.BoletoFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.AccountID != "" {
+		args = append(args, filter.AccountID)
+		conditions = append(conditions, fmt.Sprintf("account_id = $%d", len(args)))
+	}
+	if filter.ReceivableID != "" {
+		args = append(args, filter.ReceivableID)
+		conditions = append(conditions, fmt.Sprintf("receivable_id = $%d", len(args)))
+	}
+	if filter.CustomerID != "" {
+		args = append(args, filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanCollectionAccount lê uma conta de cobrança de uma linha de resultado
+func scanCollectionAccount(row pgx.Row) (*collection.Account, error) {
+	var a collection.Account
+	var layout string
+
+	err := row.Scan(&a.ID, &a.TenantID, &a.Name, &a.BankCode, &a.Agency, &a.AgencyDigit, &a.Account,
+		&a.AccountDigit, &a.Wallet, &a.Agreement, &a.BeneficiaryName, &a.BeneficiaryDocument, &layout,
+		&a.InterestRate, &a.FinePercent, &a.Instructions, &a.NextOurNumber, &a.NextRemittance, &a.Active,
+		&a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Layout = cnab.Layout(layout)
+	return &a, nil
+}
+
+// scanBoleto lê um boleto de uma linha de resultado
+func scanBoleto(row pgx.Row) (*collection.Boleto, error) {
+	var b collection.Boleto
+	var status string
+	var remittanceID pgtype.Text
+	var paidAt pgtype.Date
+
+	err := row.Scan(&b.ID, &b.TenantID, &b.AccountID, &b.ReceivableID, &b.CustomerID, &b.OurNumber,
+		&b.OurNumberDigit, &b.PrintedNumber, &b.DocumentNumber, &b.Barcode, &b.DigitableLine, &b.Amount,
+		&b.IssueDate, &b.DueDate, &status, &remittanceID, &b.Occurrence, &b.PaidAmount, &paidAt,
+		&b.CreatedAt, &b.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	b.Status = collection.BoletoStatus(status)
+	b.RemittanceID = remittanceID.String
+	if paidAt.Valid {
+		b.PaidAt = &paidAt.Time
+	}
+	return &b, nil
+}
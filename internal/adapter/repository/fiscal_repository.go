@@ -2,16 +2,26 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
 	"github.com/hugohenrick/erp-supermercado/internal/domain/fiscal"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
 	"github.com/hugohenrick/erp-supermercado/pkg/branch"
-	"github.com/hugohenrick/erp-supermercado/pkg/tenant"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
+// fiscalColumns são as colunas lidas por scanFiscalConfiguration, na mesma ordem
+const fiscalColumns = `id, tenant_id, branch_id, COALESCE(certificate_id::text, ''),
+			nfe_series, nfe_next_number, nfe_environment, nfe_csc_id, nfe_csc_token,
+			nfce_series, nfce_next_number, nfce_environment, nfce_csc_id, nfce_csc_token,
+			fiscal_csc, fiscal_csc_id, contingency_enabled,
+			smtp_host, smtp_port, smtp_username, smtp_password,
+			print_danfe_mode, printer_name, printer_paper_size,
+			created_at, updated_at`
+
 // FiscalRepository implementa a interface fiscal.Repository
 type FiscalRepository struct {
 	db *pgxpool.Pool
@@ -26,65 +36,41 @@ func NewFiscalRepository(db *pgxpool.Pool) fiscal.Repository {
 
 // Create implementa o método Create da interface fiscal.Repository
 func (r *FiscalRepository) Create(ctx context.Context, config *fiscal.Configuration) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return fmt.Errorf("tenant ID não encontrado no contexto")
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Verificar se a filial existe
-	var exists bool
-	err = conn.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.branches WHERE id = $1)", schema), config.BranchID).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("falha ao verificar se a filial existe: %w", err)
-	}
-	if !exists {
-		return fmt.Errorf("filial com ID %s não encontrada", config.BranchID)
-	}
-
-	// Verificar se já existe configuração para esta filial
-	err = conn.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.fiscal_configurations WHERE branch_id = $1)", schema), config.BranchID).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("falha ao verificar configurações existentes: %w", err)
-	}
-	if exists {
-		return fmt.Errorf("já existe uma configuração fiscal para a filial %s", config.BranchID)
-	}
-
-	// Verificar se o certificado existe, se fornecido
-	if config.CertificateID != "" {
-		err = conn.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.branch_certificates WHERE id = $1)", schema), config.CertificateID).Scan(&exists)
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		// Verificar se a filial existe
+		var exists bool
+		err := tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", scope.Table("branches")), config.BranchID).Scan(&exists)
 		if err != nil {
-			return fmt.Errorf("falha ao verificar se o certificado existe: %w", err)
+			return fmt.Errorf("falha ao verificar se a filial existe: %w", err)
 		}
 		if !exists {
-			return fmt.Errorf("certificado com ID %s não encontrado", config.CertificateID)
+			return fmt.Errorf("filial com ID %s não encontrada", config.BranchID)
 		}
-	}
 
-	// Inserir a nova configuração fiscal
+		// Verificar se já existe configuração para esta filial
+		err = tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE branch_id = $1)", scope.Table("fiscal_configurations")), config.BranchID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("falha ao verificar configurações existentes: %w", err)
+		}
+		if exists {
+			return fmt.Errorf("já existe uma configuração fiscal para a filial %s", config.BranchID)
+		}
+
+		// Verificar se o certificado existe, se fornecido
+		if err := checkCertificateExists(ctx, tx, scope, config.CertificateID); err != nil {
+			return err
+		}
+
+		return insertFiscalConfig(ctx, tx, scope, config)
+	})
+}
+
+// insertFiscalConfig grava a configuração fiscal na transação do tenant. Sem certificado, a
+// configuração fica sem certificate_id até o upload
+func insertFiscalConfig(ctx context.Context, tx pgx.Tx, scope database.TenantScope, config *fiscal.Configuration) error {
 	query := fmt.Sprintf(`
-		INSERT INTO %s.fiscal_configurations (
-			id, tenant_id, branch_id, certificate_id, 
+		INSERT INTO %s (
+			id, tenant_id, branch_id, certificate_id,
 			nfe_series, nfe_next_number, nfe_environment, nfe_csc_id, nfe_csc_token,
 			nfce_series, nfce_next_number, nfce_environment, nfce_csc_id, nfce_csc_token,
 			fiscal_csc, fiscal_csc_id, contingency_enabled,
@@ -95,178 +81,86 @@ func (r *FiscalRepository) Create(ctx context.Context, config *fiscal.Configurat
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14,
 			$15, $16, $17, $18, $19, $20, $21, $22, $23, $24, $25, $26
 		)
-	`, schema)
+	`, scope.Table("fiscal_configurations"))
 
-	_, err = conn.Exec(ctx, query,
-		config.ID, config.TenantID, config.BranchID, config.CertificateID,
+	_, err := tx.Exec(ctx, query,
+		config.ID, config.TenantID, config.BranchID, nullIfEmpty(config.CertificateID),
 		config.NFeSeries, config.NFeNextNumber, config.NFeEnvironment, config.NFeCSCID, config.NFeCSCToken,
 		config.NFCeSeries, config.NFCeNextNumber, config.NFCeEnvironment, config.NFCeCSCID, config.NFCeCSCToken,
 		config.FiscalCSC, config.FiscalCSCID, config.ContingencyEnabled,
 		config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword,
 		config.PrintDANFEMode, config.PrinterName, config.PrinterPaperSize,
 		config.CreatedAt, config.UpdatedAt)
-
 	if err != nil {
 		return fmt.Errorf("falha ao inserir configuração fiscal: %w", err)
 	}
-
 	return nil
 }
 
 // FindByID implementa o método FindByID da interface fiscal.Repository
 func (r *FiscalRepository) FindByID(ctx context.Context, id string) (*fiscal.Configuration, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return nil, fmt.Errorf("tenant ID não encontrado no contexto")
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return nil, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Buscar a configuração
-	query := fmt.Sprintf(`
-		SELECT 
-			id, tenant_id, branch_id, certificate_id, 
-			nfe_series, nfe_next_number, nfe_environment, nfe_csc_id, nfe_csc_token,
-			nfce_series, nfce_next_number, nfce_environment, nfce_csc_id, nfce_csc_token,
-			fiscal_csc, fiscal_csc_id, contingency_enabled,
-			smtp_host, smtp_port, smtp_username, smtp_password,
-			print_danfe_mode, printer_name, printer_paper_size,
-			created_at, updated_at
-		FROM %s.fiscal_configurations
-		WHERE id = $1 AND tenant_id = $2
-	`, schema)
-
-	var config fiscal.Configuration
-	err = conn.QueryRow(ctx, query, id, tenantID).Scan(
-		&config.ID, &config.TenantID, &config.BranchID, &config.CertificateID,
-		&config.NFeSeries, &config.NFeNextNumber, &config.NFeEnvironment, &config.NFeCSCID, &config.NFeCSCToken,
-		&config.NFCeSeries, &config.NFCeNextNumber, &config.NFCeEnvironment, &config.NFCeCSCID, &config.NFCeCSCToken,
-		&config.FiscalCSC, &config.FiscalCSCID, &config.ContingencyEnabled,
-		&config.SMTPHost, &config.SMTPPort, &config.SMTPUsername, &config.SMTPPassword,
-		&config.PrintDANFEMode, &config.PrinterName, &config.PrinterPaperSize,
-		&config.CreatedAt, &config.UpdatedAt)
-
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("configuração fiscal com ID %s não encontrada", id)
+	var config *fiscal.Configuration
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE id = $1 AND tenant_id = $2
+		`, fiscalColumns, scope.Table("fiscal_configurations"))
+
+		var err error
+		config, err = scanFiscalConfiguration(tx.QueryRow(ctx, query, id, scope.TenantID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("configuração fiscal com ID %s não encontrada", id)
+			}
+			return fmt.Errorf("falha ao buscar configuração fiscal: %w", err)
 		}
-		return nil, fmt.Errorf("falha ao buscar configuração fiscal: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // FindByBranch implementa o método FindByBranch da interface fiscal.Repository
 func (r *FiscalRepository) FindByBranch(ctx context.Context, branchID string) (*fiscal.Configuration, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return nil, fmt.Errorf("tenant ID não encontrado no contexto")
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return nil, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
 	// Obter branch_id do contexto se não fornecido
 	if branchID == "" {
 		branchID = branch.GetBranchID(ctx)
 	}
 
-	// Buscar a configuração
-	query := fmt.Sprintf(`
-		SELECT 
-			id, tenant_id, branch_id, certificate_id, 
-			nfe_series, nfe_next_number, nfe_environment, nfe_csc_id, nfe_csc_token,
-			nfce_series, nfce_next_number, nfce_environment, nfce_csc_id, nfce_csc_token,
-			fiscal_csc, fiscal_csc_id, contingency_enabled,
-			smtp_host, smtp_port, smtp_username, smtp_password,
-			print_danfe_mode, printer_name, printer_paper_size,
-			created_at, updated_at
-		FROM %s.fiscal_configurations
-		WHERE branch_id = $1 AND tenant_id = $2
-	`, schema)
+	var config *fiscal.Configuration
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE branch_id = $1 AND tenant_id = $2
+		`, fiscalColumns, scope.Table("fiscal_configurations"))
 
-	var config fiscal.Configuration
-	err = conn.QueryRow(ctx, query, branchID, tenantID).Scan(
-		&config.ID, &config.TenantID, &config.BranchID, &config.CertificateID,
-		&config.NFeSeries, &config.NFeNextNumber, &config.NFeEnvironment, &config.NFeCSCID, &config.NFeCSCToken,
-		&config.NFCeSeries, &config.NFCeNextNumber, &config.NFCeEnvironment, &config.NFCeCSCID, &config.NFCeCSCToken,
-		&config.FiscalCSC, &config.FiscalCSCID, &config.ContingencyEnabled,
-		&config.SMTPHost, &config.SMTPPort, &config.SMTPUsername, &config.SMTPPassword,
-		&config.PrintDANFEMode, &config.PrinterName, &config.PrinterPaperSize,
-		&config.CreatedAt, &config.UpdatedAt)
-
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, fmt.Errorf("configuração fiscal não encontrada para a filial %s", branchID)
+		var err error
+		config, err = scanFiscalConfiguration(tx.QueryRow(ctx, query, branchID, scope.TenantID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("configuração fiscal não encontrada para a filial %s", branchID)
+			}
+			return fmt.Errorf("falha ao buscar configuração fiscal: %w", err)
 		}
-		return nil, fmt.Errorf("falha ao buscar configuração fiscal: %w", err)
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return &config, nil
+	return config, nil
 }
 
 // List implementa o método List da interface fiscal.Repository
 func (r *FiscalRepository) List(ctx context.Context, tenantID string, limit, offset int) ([]*fiscal.Configuration, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
 	// Obter tenant_id do contexto se não fornecido
 	if tenantID == "" {
-		tenantID = tenant.GetTenantIDFromContext(ctx)
-		if tenantID == "" {
-			return nil, fmt.Errorf("tenant ID não encontrado no contexto")
-		}
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return nil, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao obter schema do tenant: %w", err)
+		tenantID = database.TenantIDFromContext(ctx)
 	}
 
 	// Validar parâmetros de paginação
@@ -277,48 +171,37 @@ func (r *FiscalRepository) List(ctx context.Context, tenantID string, limit, off
 		offset = 0
 	}
 
-	// Buscar as configurações fiscais com paginação
-	query := fmt.Sprintf(`
-		SELECT 
-			id, tenant_id, branch_id, certificate_id, 
-			nfe_series, nfe_next_number, nfe_environment, nfe_csc_id, nfe_csc_token,
-			nfce_series, nfce_next_number, nfce_environment, nfce_csc_id, nfce_csc_token,
-			fiscal_csc, fiscal_csc_id, contingency_enabled,
-			smtp_host, smtp_port, smtp_username, smtp_password,
-			print_danfe_mode, printer_name, printer_paper_size,
-			created_at, updated_at
-		FROM %s.fiscal_configurations
-		WHERE tenant_id = $1
-		ORDER BY branch_id
-		LIMIT $2 OFFSET $3
-	`, schema)
-
-	rows, err := conn.Query(ctx, query, tenantID, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao buscar configurações fiscais: %w", err)
-	}
-	defer rows.Close()
-
-	// Processar os resultados
 	configs := []*fiscal.Configuration{}
-	for rows.Next() {
-		var config fiscal.Configuration
-		err = rows.Scan(
-			&config.ID, &config.TenantID, &config.BranchID, &config.CertificateID,
-			&config.NFeSeries, &config.NFeNextNumber, &config.NFeEnvironment, &config.NFeCSCID, &config.NFeCSCToken,
-			&config.NFCeSeries, &config.NFCeNextNumber, &config.NFCeEnvironment, &config.NFCeCSCID, &config.NFCeCSCToken,
-			&config.FiscalCSC, &config.FiscalCSCID, &config.ContingencyEnabled,
-			&config.SMTPHost, &config.SMTPPort, &config.SMTPUsername, &config.SMTPPassword,
-			&config.PrintDANFEMode, &config.PrinterName, &config.PrinterPaperSize,
-			&config.CreatedAt, &config.UpdatedAt)
+	err := database.TenantTxFor(ctx, r.db, tenantID, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT %s
+			FROM %s
+			WHERE tenant_id = $1
+			ORDER BY branch_id
+			LIMIT $2 OFFSET $3
+		`, fiscalColumns, scope.Table("fiscal_configurations"))
+
+		rows, err := tx.Query(ctx, query, tenantID, limit, offset)
 		if err != nil {
-			return nil, fmt.Errorf("falha ao ler configuração fiscal: %w", err)
+			return fmt.Errorf("falha ao buscar configurações fiscais: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			config, err := scanFiscalConfiguration(rows)
+			if err != nil {
+				return fmt.Errorf("falha ao ler configuração fiscal: %w", err)
+			}
+			configs = append(configs, config)
 		}
-		configs = append(configs, &config)
-	}
 
-	if err = rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar configurações fiscais: %w", err)
+		if err = rows.Err(); err != nil {
+			return fmt.Errorf("erro ao iterar configurações fiscais: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return configs, nil
@@ -326,409 +209,268 @@ func (r *FiscalRepository) List(ctx context.Context, tenantID string, limit, off
 
 // Update implementa o método Update da interface fiscal.Repository
 func (r *FiscalRepository) Update(ctx context.Context, config *fiscal.Configuration) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return fmt.Errorf("tenant ID não encontrado no contexto")
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Verificar se a configuração existe
-	var exists bool
-	err = conn.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.fiscal_configurations WHERE id = $1 AND tenant_id = $2)", schema), config.ID, tenantID).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("falha ao verificar se a configuração existe: %w", err)
-	}
-	if !exists {
-		return fmt.Errorf("configuração fiscal com ID %s não encontrada", config.ID)
-	}
-
-	// Verificar se o certificado existe, se fornecido
-	if config.CertificateID != "" {
-		err = conn.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.branch_certificates WHERE id = $1)", schema), config.CertificateID).Scan(&exists)
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		// Verificar se a configuração existe
+		var exists bool
+		err := tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1 AND tenant_id = $2)", scope.Table("fiscal_configurations")), config.ID, scope.TenantID).Scan(&exists)
 		if err != nil {
-			return fmt.Errorf("falha ao verificar se o certificado existe: %w", err)
+			return fmt.Errorf("falha ao verificar se a configuração existe: %w", err)
 		}
 		if !exists {
-			return fmt.Errorf("certificado com ID %s não encontrado", config.CertificateID)
+			return fmt.Errorf("configuração fiscal com ID %s não encontrada", config.ID)
 		}
-	}
 
-	// Atualizar a configuração
-	query := fmt.Sprintf(`
-		UPDATE %s.fiscal_configurations SET
-			certificate_id = $1, 
-			nfe_series = $2, nfe_next_number = $3, nfe_environment = $4, nfe_csc_id = $5, nfe_csc_token = $6,
-			nfce_series = $7, nfce_next_number = $8, nfce_environment = $9, nfce_csc_id = $10, nfce_csc_token = $11,
-			fiscal_csc = $12, fiscal_csc_id = $13, contingency_enabled = $14,
-			smtp_host = $15, smtp_port = $16, smtp_username = $17, smtp_password = $18,
-			print_danfe_mode = $19, printer_name = $20, printer_paper_size = $21,
-			updated_at = $22
-		WHERE id = $23 AND tenant_id = $24
-	`, schema)
-
-	_, err = conn.Exec(ctx, query,
-		config.CertificateID,
-		config.NFeSeries, config.NFeNextNumber, config.NFeEnvironment, config.NFeCSCID, config.NFeCSCToken,
-		config.NFCeSeries, config.NFCeNextNumber, config.NFCeEnvironment, config.NFCeCSCID, config.NFCeCSCToken,
-		config.FiscalCSC, config.FiscalCSCID, config.ContingencyEnabled,
-		config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword,
-		config.PrintDANFEMode, config.PrinterName, config.PrinterPaperSize,
-		time.Now(),
-		config.ID, tenantID)
+		// Verificar se o certificado existe, se fornecido
+		if err := checkCertificateExists(ctx, tx, scope, config.CertificateID); err != nil {
+			return err
+		}
 
-	if err != nil {
-		return fmt.Errorf("falha ao atualizar configuração fiscal: %w", err)
-	}
+		// Atualizar a configuração
+		query := fmt.Sprintf(`
+			UPDATE %s SET
+				certificate_id = $1,
+				nfe_series = $2, nfe_next_number = $3, nfe_environment = $4, nfe_csc_id = $5, nfe_csc_token = $6,
+				nfce_series = $7, nfce_next_number = $8, nfce_environment = $9, nfce_csc_id = $10, nfce_csc_token = $11,
+				fiscal_csc = $12, fiscal_csc_id = $13, contingency_enabled = $14,
+				smtp_host = $15, smtp_port = $16, smtp_username = $17, smtp_password = $18,
+				print_danfe_mode = $19, printer_name = $20, printer_paper_size = $21,
+				updated_at = $22
+			WHERE id = $23 AND tenant_id = $24
+		`, scope.Table("fiscal_configurations"))
+
+		_, err = tx.Exec(ctx, query,
+			nullIfEmpty(config.CertificateID),
+			config.NFeSeries, config.NFeNextNumber, config.NFeEnvironment, config.NFeCSCID, config.NFeCSCToken,
+			config.NFCeSeries, config.NFCeNextNumber, config.NFCeEnvironment, config.NFCeCSCID, config.NFCeCSCToken,
+			config.FiscalCSC, config.FiscalCSCID, config.ContingencyEnabled,
+			config.SMTPHost, config.SMTPPort, config.SMTPUsername, config.SMTPPassword,
+			config.PrintDANFEMode, config.PrinterName, config.PrinterPaperSize,
+			time.Now(),
+			config.ID, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao atualizar configuração fiscal: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // Delete implementa o método Delete da interface fiscal.Repository
 func (r *FiscalRepository) Delete(ctx context.Context, id string) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return fmt.Errorf("tenant ID não encontrado no contexto")
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Excluir a configuração fiscal
-	_, err = conn.Exec(ctx, fmt.Sprintf("DELETE FROM %s.fiscal_configurations WHERE id = $1 AND tenant_id = $2", schema), id, tenantID)
-	if err != nil {
-		return fmt.Errorf("falha ao excluir configuração fiscal: %w", err)
-	}
-
-	return nil
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		_, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND tenant_id = $2", scope.Table("fiscal_configurations")), id, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao excluir configuração fiscal: %w", err)
+		}
+		return nil
+	})
 }
 
 // UpdateNFeNextNumber implementa o método UpdateNFeNextNumber da interface fiscal.Repository
 func (r *FiscalRepository) UpdateNFeNextNumber(ctx context.Context, id string, nextNumber int) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return fmt.Errorf("tenant ID não encontrado no contexto")
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Atualizar o próximo número de NFe
-	query := fmt.Sprintf(`
-		UPDATE %s.fiscal_configurations SET
-			nfe_next_number = $1,
-			updated_at = $2
-		WHERE id = $3 AND tenant_id = $4
-	`, schema)
-
-	_, err = conn.Exec(ctx, query, nextNumber, time.Now(), id, tenantID)
-	if err != nil {
-		return fmt.Errorf("falha ao atualizar próximo número de NFe: %w", err)
-	}
-
-	return nil
+	return r.updateNextNumber(ctx, "nfe_next_number", id, nextNumber, "falha ao atualizar próximo número de NFe")
 }
 
 // UpdateNFCeNextNumber implementa o método UpdateNFCeNextNumber da interface fiscal.Repository
 func (r *FiscalRepository) UpdateNFCeNextNumber(ctx context.Context, id string, nextNumber int) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return fmt.Errorf("tenant ID não encontrado no contexto")
-	}
-
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Atualizar o próximo número de NFCe
-	query := fmt.Sprintf(`
-		UPDATE %s.fiscal_configurations SET
-			nfce_next_number = $1,
-			updated_at = $2
-		WHERE id = $3 AND tenant_id = $4
-	`, schema)
-
-	_, err = conn.Exec(ctx, query, nextNumber, time.Now(), id, tenantID)
-	if err != nil {
-		return fmt.Errorf("falha ao atualizar próximo número de NFCe: %w", err)
-	}
-
-	return nil
+	return r.updateNextNumber(ctx, "nfce_next_number", id, nextNumber, "falha ao atualizar próximo número de NFCe")
 }
 
 // GetAndIncrementNFeNumber implementa o método GetAndIncrementNFeNumber da interface fiscal.Repository
 func (r *FiscalRepository) GetAndIncrementNFeNumber(ctx context.Context, branchID string) (int, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return 0, fmt.Errorf("tenant ID não encontrado no contexto")
-	}
+	return r.incrementNumber(ctx, "nfe", branchID, "falha ao obter e incrementar número de NFe")
+}
 
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return 0, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
+// GetAndIncrementNFCeNumber implementa o método GetAndIncrementNFCeNumber da interface fiscal.Repository
+func (r *FiscalRepository) GetAndIncrementNFCeNumber(ctx context.Context, branchID string) (int, error) {
+	return r.incrementNumber(ctx, "nfce", branchID, "falha ao obter e incrementar número de NFCe")
+}
 
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
+// Exists implementa o método Exists da interface fiscal.Repository
+func (r *FiscalRepository) Exists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		err := tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1 AND tenant_id = $2)", scope.Table("fiscal_configurations")), id, scope.TenantID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("falha ao verificar se a configuração existe: %w", err)
+		}
+		return nil
+	})
+	return exists, err
+}
 
+// ExistsByBranch implementa o método ExistsByBranch da interface fiscal.Repository
+func (r *FiscalRepository) ExistsByBranch(ctx context.Context, branchID string) (bool, error) {
 	// Obter branch_id do contexto se não fornecido
 	if branchID == "" {
 		branchID = branch.GetBranchID(ctx)
 	}
 
-	// Iniciar uma transação
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao iniciar transação: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Obter e incrementar o número de NFe em uma única operação
-	var currentNumber int
-	query := fmt.Sprintf(`
-		UPDATE %s.fiscal_configurations SET
-			nfe_next_number = nfe_next_number + 1,
-			updated_at = $1
-		WHERE branch_id = $2 AND tenant_id = $3
-		RETURNING nfe_next_number - 1
-	`, schema)
-
-	err = tx.QueryRow(ctx, query, time.Now(), branchID, tenantID).Scan(&currentNumber)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return 0, fmt.Errorf("configuração fiscal não encontrada para a filial %s", branchID)
+	var exists bool
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		err := tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE branch_id = $1 AND tenant_id = $2)", scope.Table("fiscal_configurations")), branchID, scope.TenantID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("falha ao verificar se existe configuração para a filial: %w", err)
 		}
-		return 0, fmt.Errorf("falha ao obter e incrementar número de NFe: %w", err)
-	}
-
-	// Confirmar a transação
-	if err = tx.Commit(ctx); err != nil {
-		return 0, fmt.Errorf("falha ao confirmar transação: %w", err)
-	}
-
-	return currentNumber, nil
+		return nil
+	})
+	return exists, err
 }
 
-// GetAndIncrementNFCeNumber implementa o método GetAndIncrementNFCeNumber da interface fiscal.Repository
-func (r *FiscalRepository) GetAndIncrementNFCeNumber(ctx context.Context, branchID string) (int, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
+// VoidNumbers implementa o método VoidNumbers da interface fiscal.Repository. A configuração da
+// filial fica bloqueada durante a gravação, para que a faixa seja conferida contra a numeração e as
+// inutilizações já registradas sem concorrência
+func (r *FiscalRepository) VoidNumbers(ctx context.Context, v *fiscal.VoidedRange) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		var next int
+		err := tx.QueryRow(ctx, fmt.Sprintf(`
+			SELECT %s_next_number FROM %s
+			WHERE branch_id = $1 AND tenant_id = $2
+			FOR UPDATE
+		`, string(v.Model), scope.Table("fiscal_configurations")), v.BranchID, scope.TenantID).Scan(&next)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return fmt.Errorf("configuração fiscal não encontrada para a filial %s", v.BranchID)
+		}
+		if err != nil {
+			return fmt.Errorf("falha ao bloquear configuração fiscal: %w", err)
+		}
+		if v.EndNumber >= next {
+			return fiscal.ErrVoidRangeNotReserved
+		}
 
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return 0, fmt.Errorf("tenant ID não encontrado no contexto")
-	}
+		var overlaps bool
+		err = tx.QueryRow(ctx, fmt.Sprintf(`
+			SELECT EXISTS(
+				SELECT 1 FROM %s
+				WHERE branch_id = $1 AND model = $2 AND series = $3
+					AND start_number <= $5 AND end_number >= $4
+			)
+		`, scope.Table("fiscal_voided_numbers")), v.BranchID, string(v.Model), v.Series, v.StartNumber, v.EndNumber).Scan(&overlaps)
+		if err != nil {
+			return fmt.Errorf("falha ao verificar numeração inutilizada: %w", err)
+		}
+		if overlaps {
+			return fiscal.ErrNumbersAlreadyVoided
+		}
 
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return 0, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO %s (
+				id, tenant_id, branch_id, model, series, start_number, end_number,
+				justification, created_by, created_at
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		`, scope.Table("fiscal_voided_numbers")), v.ID, scope.TenantID, v.BranchID, string(v.Model), v.Series,
+			v.StartNumber, v.EndNumber, v.Justification, nullIfEmpty(v.CreatedBy), v.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("falha ao registrar numeração inutilizada: %w", err)
+		}
+		return nil
+	})
+}
 
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
+// updateNextNumber define o próximo número da série (nfe_next_number ou nfce_next_number)
+func (r *FiscalRepository) updateNextNumber(ctx context.Context, column, id string, nextNumber int, message string) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			UPDATE %s SET
+				%s = $1,
+				updated_at = $2
+			WHERE id = $3 AND tenant_id = $4
+		`, scope.Table("fiscal_configurations"), column)
+
+		if _, err := tx.Exec(ctx, query, nextNumber, time.Now(), id, scope.TenantID); err != nil {
+			return fmt.Errorf("%s: %w", message, err)
+		}
+		return nil
+	})
+}
 
+// incrementNumber obtém e incrementa o próximo número da série do modelo (nfe ou nfce) em uma
+// única operação. Cada número obtido é um documento emitido, contado no limite mensal do plano
+func (r *FiscalRepository) incrementNumber(ctx context.Context, model, branchID, message string) (int, error) {
 	// Obter branch_id do contexto se não fornecido
 	if branchID == "" {
 		branchID = branch.GetBranchID(ctx)
 	}
+	column := model + "_next_number"
 
-	// Iniciar uma transação
-	tx, err := conn.Begin(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao iniciar transação: %w", err)
-	}
-	defer tx.Rollback(ctx)
-
-	// Obter e incrementar o número de NFCe em uma única operação
 	var currentNumber int
-	query := fmt.Sprintf(`
-		UPDATE %s.fiscal_configurations SET
-			nfce_next_number = nfce_next_number + 1,
-			updated_at = $1
-		WHERE branch_id = $2 AND tenant_id = $3
-		RETURNING nfce_next_number - 1
-	`, schema)
-
-	err = tx.QueryRow(ctx, query, time.Now(), branchID, tenantID).Scan(&currentNumber)
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return 0, fmt.Errorf("configuração fiscal não encontrada para a filial %s", branchID)
-		}
-		return 0, fmt.Errorf("falha ao obter e incrementar número de NFCe: %w", err)
-	}
-
-	// Confirmar a transação
-	if err = tx.Commit(ctx); err != nil {
-		return 0, fmt.Errorf("falha ao confirmar transação: %w", err)
-	}
-
-	return currentNumber, nil
-}
-
-// Exists implementa o método Exists da interface fiscal.Repository
-func (r *FiscalRepository) Exists(ctx context.Context, id string) (bool, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return false, fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		now := time.Now()
+		period := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
 
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return false, fmt.Errorf("tenant ID não encontrado no contexto")
-	}
+		plan, err := lockTenantPlan(ctx, tx, scope.TenantID)
+		if err != nil {
+			return err
+		}
+		err = checkPlanLimit(ctx, tx, plan.MaxFiscalDocuments, "documentos fiscais por mês",
+			fmt.Sprintf("SELECT COALESCE(SUM(quantity), 0) FROM %s WHERE period = $1", scope.Table("fiscal_document_counters")), period)
+		if err != nil {
+			return err
+		}
 
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return false, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
+		query := fmt.Sprintf(`
+			UPDATE %s SET
+				%s = %s + 1,
+				updated_at = $1
+			WHERE branch_id = $2 AND tenant_id = $3
+			RETURNING %s - 1
+		`, scope.Table("fiscal_configurations"), column, column, column)
 
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		return false, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
+		err = tx.QueryRow(ctx, query, now, branchID, scope.TenantID).Scan(&currentNumber)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return fmt.Errorf("configuração fiscal não encontrada para a filial %s", branchID)
+			}
+			return fmt.Errorf("%s: %w", message, err)
+		}
 
-	// Verificar se a configuração existe
-	var exists bool
-	err = conn.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.fiscal_configurations WHERE id = $1 AND tenant_id = $2)", schema), id, tenantID).Scan(&exists)
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO %s (period, branch_id, model, quantity, updated_at)
+			VALUES ($1, $2, $3, 1, $4)
+			ON CONFLICT (period, branch_id, model) DO UPDATE SET quantity = fiscal_document_counters.quantity + 1, updated_at = EXCLUDED.updated_at
+		`, scope.Table("fiscal_document_counters")), period, branchID, model, now)
+		if err != nil {
+			return fmt.Errorf("falha ao contar documento fiscal: %w", err)
+		}
+		return nil
+	})
 	if err != nil {
-		return false, fmt.Errorf("falha ao verificar se a configuração existe: %w", err)
+		return 0, err
 	}
 
-	return exists, nil
+	return currentNumber, nil
 }
 
-// ExistsByBranch implementa o método ExistsByBranch da interface fiscal.Repository
-func (r *FiscalRepository) ExistsByBranch(ctx context.Context, branchID string) (bool, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return false, fmt.Errorf("falha ao adquirir conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant_id do contexto
-	tenantID := tenant.GetTenantIDFromContext(ctx)
-	if tenantID == "" {
-		return false, fmt.Errorf("tenant ID não encontrado no contexto")
+// checkCertificateExists verifica se o certificado informado existe no schema do tenant
+func checkCertificateExists(ctx context.Context, tx pgx.Tx, scope database.TenantScope, certificateID string) error {
+	if certificateID == "" {
+		return nil
 	}
 
-	// Configurar search_path para public para acessar a tabela de tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return false, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM tenants WHERE id = $1", tenantID).Scan(&schema)
+	var exists bool
+	err := tx.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1)", scope.Table("branch_certificates")), certificateID).Scan(&exists)
 	if err != nil {
-		return false, fmt.Errorf("falha ao obter schema do tenant: %w", err)
+		return fmt.Errorf("falha ao verificar se o certificado existe: %w", err)
 	}
-
-	// Obter branch_id do contexto se não fornecido
-	if branchID == "" {
-		branchID = branch.GetBranchID(ctx)
+	if !exists {
+		return fmt.Errorf("certificado com ID %s não encontrado", certificateID)
 	}
+	return nil
+}
 
-	// Verificar se existe configuração para a filial
-	var exists bool
-	err = conn.QueryRow(ctx, fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.fiscal_configurations WHERE branch_id = $1 AND tenant_id = $2)", schema), branchID, tenantID).Scan(&exists)
+// scanFiscalConfiguration lê uma configuração fiscal selecionada com fiscalColumns
+func scanFiscalConfiguration(row pgx.Row) (*fiscal.Configuration, error) {
+	var config fiscal.Configuration
+	err := row.Scan(
+		&config.ID, &config.TenantID, &config.BranchID, &config.CertificateID,
+		&config.NFeSeries, &config.NFeNextNumber, &config.NFeEnvironment, &config.NFeCSCID, &config.NFeCSCToken,
+		&config.NFCeSeries, &config.NFCeNextNumber, &config.NFCeEnvironment, &config.NFCeCSCID, &config.NFCeCSCToken,
+		&config.FiscalCSC, &config.FiscalCSCID, &config.ContingencyEnabled,
+		&config.SMTPHost, &config.SMTPPort, &config.SMTPUsername, &config.SMTPPassword,
+		&config.PrintDANFEMode, &config.PrinterName, &config.PrinterPaperSize,
+		&config.CreatedAt, &config.UpdatedAt)
 	if err != nil {
-		return false, fmt.Errorf("falha ao verificar se existe configuração para a filial: %w", err)
+		return nil, err
 	}
-
-	return exists, nil
+	return &config, nil
 }
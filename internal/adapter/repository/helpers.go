@@ -0,0 +1,45 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrTenantNotInContext ocorre quando o tenant ID não está presente no contexto
+var ErrTenantNotInContext = database.ErrTenantNotInContext
+
+// rowQuerier abstrai conexões e transações que executam consultas de uma linha
+type rowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row
+}
+
+// execer abstrai conexões e transações que executam comandos
+type execer interface {
+	Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error)
+}
+
+// tenantIDFromContext obtém o tenant ID tanto do contexto do Gin quanto do context.Context padrão
+func tenantIDFromContext(ctx context.Context) string {
+	return database.TenantIDFromContext(ctx)
+}
+
+// resolveTenantSchema retorna o tenant ID do contexto e o schema correspondente
+func resolveTenantSchema(ctx context.Context, q rowQuerier) (string, string, error) {
+	tenantID := tenantIDFromContext(ctx)
+	schema, err := database.ResolveTenantSchema(ctx, q, tenantID)
+	if err != nil {
+		return "", "", err
+	}
+	return tenantID, schema, nil
+}
+
+// nullIfEmpty converte strings vazias em NULL para colunas opcionais
+func nullIfEmpty(value string) interface{} {
+	if value == "" {
+		return nil
+	}
+	return value
+}
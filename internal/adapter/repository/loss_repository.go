@@ -0,0 +1,735 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loss"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de perdas
+var (
+	ErrLossNotFound          = errors.New("registro de perda não encontrado")
+	ErrLossReasonNotFound    = errors.New("motivo de perda não encontrado")
+	ErrLossReasonDuplicate   = errors.New("já existe um motivo de perda com este código")
+	ErrLossProductNotFound   = errors.New("produto informado na perda não encontrado")
+	ErrLossAlreadyReviewed   = errors.New("registro de perda já foi analisado")
+	ErrLossInvalidReportType = errors.New("agrupamento de relatório inválido")
+)
+
+// LossRepository implementa a interface loss.Repository
+type LossRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLossRepository cria uma nova instância de LossRepository
+func NewLossRepository(db *pgxpool.Pool) loss.Repository {
+	return &LossRepository{
+		db: db,
+	}
+}
+
+// CreateReason implementa loss.Repository.CreateReason
+func (r *LossRepository) CreateReason(ctx context.Context, reason *loss.Reason) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	reason.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.loss_reasons (
+			id, tenant_id, code, description, category, requires_photo, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query,
+		reason.ID, reason.TenantID, reason.Code, reason.Description, string(reason.Category),
+		reason.RequiresPhoto, reason.Active, reason.CreatedAt, reason.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrLossReasonDuplicate
+		}
+		return fmt.Errorf("falha ao inserir motivo de perda: %w", err)
+	}
+
+	return nil
+}
+
+// FindReasonByID implementa loss.Repository.FindReasonByID
+func (r *LossRepository) FindReasonByID(ctx context.Context, id string) (*loss.Reason, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, code, description, category, requires_photo, active, created_at, updated_at
+		FROM %s.loss_reasons
+		WHERE id = $1 AND tenant_id = $2
+	`, schema)
+
+	reason, err := scanLossReason(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLossReasonNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar motivo de perda: %w", err)
+	}
+
+	return reason, nil
+}
+
+// ListReasons implementa loss.Repository.ListReasons
+func (r *LossRepository) ListReasons(ctx context.Context, onlyActive bool) ([]*loss.Reason, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, code, description, category, requires_photo, active, created_at, updated_at
+		FROM %s.loss_reasons
+		WHERE tenant_id = $1 AND ($2 = false OR active = true)
+		ORDER BY code
+	`, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID, onlyActive)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar motivos de perda: %w", err)
+	}
+	defer rows.Close()
+
+	reasons := make([]*loss.Reason, 0)
+	for rows.Next() {
+		reason, err := scanLossReason(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler motivo de perda: %w", err)
+		}
+		reasons = append(reasons, reason)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar motivos de perda: %w", err)
+	}
+
+	return reasons, nil
+}
+
+// UpdateReason implementa loss.Repository.UpdateReason
+func (r *LossRepository) UpdateReason(ctx context.Context, reason *loss.Reason) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.loss_reasons
+		SET description = $1, category = $2, requires_photo = $3, active = $4, updated_at = $5
+		WHERE id = $6 AND tenant_id = $7
+	`, schema)
+
+	result, err := conn.Exec(ctx, query,
+		reason.Description, string(reason.Category), reason.RequiresPhoto, reason.Active,
+		reason.UpdatedAt, reason.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar motivo de perda: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrLossReasonNotFound
+	}
+
+	return nil
+}
+
+// Create implementa loss.Repository.Create
+func (r *LossRepository) Create(ctx context.Context, l *loss.Loss) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	l.TenantID = tenantID
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Estimar o custo dos itens pelo custo atual dos produtos
+	for _, item := range l.Items {
+		cost, err := productCost(ctx, tx, schema, item.ProductID)
+		if err != nil {
+			return err
+		}
+		l.ApplyCost(item.ProductID, cost)
+	}
+
+	photos, err := json.Marshal(l.Photos)
+	if err != nil {
+		return fmt.Errorf("erro ao converter fotos para JSON: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.losses (
+			id, tenant_id, branch_id, reason_id, status, notes, photos, total_cost,
+			occurred_at, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, schema)
+
+	_, err = tx.Exec(ctx, query,
+		l.ID, l.TenantID, l.BranchID, l.ReasonID, string(l.Status), l.Notes, photos, l.TotalCost,
+		l.OccurredAt, nullIfEmpty(l.CreatedBy), l.CreatedAt, l.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao inserir registro de perda: %w", err)
+	}
+
+	itemQuery := fmt.Sprintf(`
+		INSERT INTO %s.loss_items (id, loss_id, product_id, quantity, unit_cost, total_cost, notes)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, schema)
+
+	for _, item := range l.Items {
+		_, err = tx.Exec(ctx, itemQuery,
+			item.ID, l.ID, item.ProductID, item.Quantity, item.UnitCost, item.TotalCost, item.Notes)
+		if err != nil {
+			return fmt.Errorf("falha ao inserir item da perda: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID implementa loss.Repository.FindByID
+func (r *LossRepository) FindByID(ctx context.Context, id string) (*loss.Loss, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.losses
+		WHERE id = $1 AND tenant_id = $2
+	`, lossColumns, schema)
+
+	l, err := scanLoss(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLossNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar registro de perda: %w", err)
+	}
+
+	itemsQuery := fmt.Sprintf(`
+		SELECT id, loss_id, product_id, quantity, unit_cost, total_cost, COALESCE(notes, '')
+		FROM %s.loss_items
+		WHERE loss_id = $1
+	`, schema)
+
+	rows, err := conn.Query(ctx, itemsQuery, l.ID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar itens da perda: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item loss.Item
+		if err := rows.Scan(&item.ID, &item.LossID, &item.ProductID, &item.Quantity,
+			&item.UnitCost, &item.TotalCost, &item.Notes); err != nil {
+			return nil, fmt.Errorf("falha ao ler item da perda: %w", err)
+		}
+		l.Items = append(l.Items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar itens da perda: %w", err)
+	}
+
+	return l, nil
+}
+
+// List implementa loss.Repository.List
+func (r *LossRepository) List(ctx context.Context, filter loss.ListFilter, limit, offset int) ([]*loss.Loss, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildLossFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM %s.losses
+		WHERE %s
+		ORDER BY occurred_at DESC
+		LIMIT $%d OFFSET $%d
+	`, lossColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar perdas: %w", err)
+	}
+	defer rows.Close()
+
+	losses := make([]*loss.Loss, 0)
+	for rows.Next() {
+		l, err := scanLoss(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler registro de perda: %w", err)
+		}
+		losses = append(losses, l)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar perdas: %w", err)
+	}
+
+	return losses, nil
+}
+
+// Count implementa loss.Repository.Count
+func (r *LossRepository) Count(ctx context.Context, filter loss.ListFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildLossFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.losses WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar perdas: %w", err)
+	}
+
+	return count, nil
+}
+
+// UpdatePhotos implementa loss.Repository.UpdatePhotos
+func (r *LossRepository) UpdatePhotos(ctx context.Context, l *loss.Loss) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	photos, err := json.Marshal(l.Photos)
+	if err != nil {
+		return fmt.Errorf("erro ao converter fotos para JSON: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.losses SET photos = $1, updated_at = $2
+		WHERE id = $3 AND tenant_id = $4 AND status = $5
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, photos, l.UpdatedAt, l.ID, tenantID, string(loss.StatusPending))
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar fotos da perda: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrLossAlreadyReviewed
+	}
+
+	return nil
+}
+
+// Approve implementa loss.Repository.Approve
+func (r *LossRepository) Approve(ctx context.Context, l *loss.Loss) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Marcar como aprovada primeiro para impedir aprovações concorrentes
+	result, err := tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.losses SET status = $1, reviewed_by = $2, reviewed_at = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6 AND status = $7
+	`, schema), string(l.Status), nullIfEmpty(l.ReviewedBy), l.ReviewedAt, l.UpdatedAt,
+		l.ID, tenantID, string(loss.StatusPending))
+	if err != nil {
+		return fmt.Errorf("falha ao aprovar perda: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrLossAlreadyReviewed
+	}
+
+	now := time.Now()
+	for _, item := range l.Items {
+		// Valorizar pelo custo do produto no momento do lançamento
+		cost, err := productCost(ctx, tx, schema, item.ProductID)
+		if err != nil {
+			return err
+		}
+		l.ApplyCost(item.ProductID, cost)
+	}
+
+	for _, item := range l.Items {
+		previous, err := lockInventory(ctx, tx, schema, tenantID, l.BranchID, item.ProductID, now)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE %s.inventory SET quantity = quantity - $1, updated_at = $2
+			WHERE tenant_id = $3 AND branch_id = $4 AND product_id = $5
+		`, schema), item.Quantity, now, tenantID, l.BranchID, item.ProductID)
+		if err != nil {
+			return fmt.Errorf("falha ao baixar estoque: %w", err)
+		}
+
+		// A quantidade da movimentação é negativa por se tratar de uma saída
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+			INSERT INTO %s.inventory_movements (
+				id, tenant_id, branch_id, product_id, type, quantity, previous_quantity,
+				reference_id, reference_type, notes, created_by, created_at, unit_cost, total_cost
+			) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+		`, schema),
+			uuid.New().String(), tenantID, l.BranchID, item.ProductID, loss.MovementType,
+			-item.Quantity, previous, l.ID, "loss", item.Notes, nullIfEmpty(l.ReviewedBy), now,
+			item.UnitCost, item.TotalCost)
+		if err != nil {
+			return fmt.Errorf("falha ao registrar movimentação de estoque: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, fmt.Sprintf(`
+			UPDATE %s.loss_items SET unit_cost = $1, total_cost = $2 WHERE id = $3
+		`, schema), item.UnitCost, item.TotalCost, item.ID)
+		if err != nil {
+			return fmt.Errorf("falha ao atualizar custo do item: %w", err)
+		}
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf("UPDATE %s.losses SET total_cost = $1 WHERE id = $2", schema), l.TotalCost, l.ID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar custo total da perda: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// Reject implementa loss.Repository.Reject
+func (r *LossRepository) Reject(ctx context.Context, l *loss.Loss) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.losses
+		SET status = $1, reviewed_by = $2, reviewed_at = $3, rejection_reason = $4, updated_at = $5
+		WHERE id = $6 AND tenant_id = $7 AND status = $8
+	`, schema)
+
+	result, err := conn.Exec(ctx, query,
+		string(l.Status), nullIfEmpty(l.ReviewedBy), l.ReviewedAt, l.RejectionReason, l.UpdatedAt,
+		l.ID, tenantID, string(loss.StatusPending))
+	if err != nil {
+		return fmt.Errorf("falha ao rejeitar perda: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrLossAlreadyReviewed
+	}
+
+	return nil
+}
+
+// Report implementa loss.Repository.Report
+func (r *LossRepository) Report(ctx context.Context, filter loss.ReportFilter) (*loss.Report, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	var keyExpr, labelExpr string
+	switch filter.GroupBy {
+	case loss.GroupByReason, "":
+		filter.GroupBy = loss.GroupByReason
+		keyExpr = "r.id::text"
+		labelExpr = "r.code || ' - ' || r.description"
+	case loss.GroupByCategory:
+		keyExpr = "COALESCE(c.id::text, '')"
+		labelExpr = "COALESCE(c.name, 'Sem categoria')"
+	case loss.GroupByPeriod:
+		keyExpr = "to_char(date_trunc('month', l.occurred_at), 'YYYY-MM')"
+		labelExpr = keyExpr
+	default:
+		return nil, ErrLossInvalidReportType
+	}
+
+	args := []interface{}{tenantID, string(loss.StatusApproved), filter.StartDate, filter.EndDate}
+	branchFilter := ""
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		branchFilter = "AND l.branch_id = $5"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s AS key, %s AS label, SUM(i.quantity), SUM(i.total_cost)
+		FROM %s.losses l
+		JOIN %s.loss_items i ON i.loss_id = l.id
+		JOIN %s.loss_reasons r ON r.id = l.reason_id
+		JOIN %s.products p ON p.id = i.product_id
+		LEFT JOIN %s.product_categories c ON c.id = p.category_id
+		WHERE l.tenant_id = $1 AND l.status = $2 AND l.occurred_at BETWEEN $3 AND $4 %s
+		GROUP BY 1, 2
+		ORDER BY 4 DESC
+	`, keyExpr, labelExpr, schema, schema, schema, schema, schema, branchFilter)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao gerar relatório de perdas: %w", err)
+	}
+	defer rows.Close()
+
+	report := &loss.Report{
+		GroupBy:   filter.GroupBy,
+		StartDate: filter.StartDate,
+		EndDate:   filter.EndDate,
+		Lines:     make([]loss.ReportLine, 0),
+	}
+
+	for rows.Next() {
+		var line loss.ReportLine
+		if err := rows.Scan(&line.Key, &line.Label, &line.Quantity, &line.TotalCost); err != nil {
+			return nil, fmt.Errorf("falha ao ler linha do relatório: %w", err)
+		}
+		report.Lines = append(report.Lines, line)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar relatório de perdas: %w", err)
+	}
+
+	// O percentual sobre vendas usa o valor vendido gravado nas movimentações de venda da mesma
+	// filial e período, nunca um total informado pelo cliente
+	salesArgs := []interface{}{tenantID, loss.SaleMovementType, filter.StartDate, filter.EndDate}
+	salesBranchFilter := ""
+	if filter.BranchID != "" {
+		salesArgs = append(salesArgs, filter.BranchID)
+		salesBranchFilter = "AND branch_id = $5"
+	}
+	err = conn.QueryRow(ctx, fmt.Sprintf(`
+		SELECT COALESCE(SUM(total_value), 0)
+		FROM %s.inventory_movements
+		WHERE tenant_id = $1 AND type = $2 AND created_at BETWEEN $3 AND $4 %s
+	`, schema, salesBranchFilter), salesArgs...).Scan(&report.SalesTotal)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao totalizar vendas do período: %w", err)
+	}
+
+	report.Calculate()
+	return report, nil
+}
+
+// lossColumns lista as colunas lidas da tabela de perdas
+const lossColumns = `id, tenant_id, branch_id, reason_id, status, COALESCE(notes, ''), photos, total_cost,
+	occurred_at, created_by, reviewed_by, reviewed_at, COALESCE(rejection_reason, ''), created_at, updated_at`
+
+// buildLossFilter monta a cláusula WHERE da listagem de perdas
+func buildLossFilter(tenantID string, filter loss.ListFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("branch_id = $%d", len(args)))
+	}
+	if filter.ReasonID != "" {
+		args = append(args, filter.ReasonID)
+		conditions = append(conditions, fmt.Sprintf("reason_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if !filter.StartDate.IsZero() {
+		args = append(args, filter.StartDate)
+		conditions = append(conditions, fmt.Sprintf("occurred_at >= $%d", len(args)))
+	}
+	if !filter.EndDate.IsZero() {
+		args = append(args, filter.EndDate)
+		conditions = append(conditions, fmt.Sprintf("occurred_at <= $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanLossReason lê um motivo de perda de uma linha de resultado
+func scanLossReason(row pgx.Row) (*loss.Reason, error) {
+	var reason loss.Reason
+	var category string
+	err := row.Scan(&reason.ID, &reason.TenantID, &reason.Code, &reason.Description, &category,
+		&reason.RequiresPhoto, &reason.Active, &reason.CreatedAt, &reason.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	reason.Category = loss.ReasonCategory(category)
+	return &reason, nil
+}
+
+// scanLoss lê um registro de perda de uma linha de resultado
+func scanLoss(row pgx.Row) (*loss.Loss, error) {
+	var l loss.Loss
+	var status string
+	var photos []byte
+	var createdBy, reviewedBy pgtype.Text
+	var reviewedAt pgtype.Timestamp
+
+	err := row.Scan(&l.ID, &l.TenantID, &l.BranchID, &l.ReasonID, &status, &l.Notes, &photos, &l.TotalCost,
+		&l.OccurredAt, &createdBy, &reviewedBy, &reviewedAt, &l.RejectionReason, &l.CreatedAt, &l.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	l.Status = loss.Status(status)
+	l.CreatedBy = createdBy.String
+	l.ReviewedBy = reviewedBy.String
+	if reviewedAt.Valid {
+		l.ReviewedAt = &reviewedAt.Time
+	}
+	l.Items = []loss.Item{}
+	if err := json.Unmarshal(photos, &l.Photos); err != nil {
+		return nil, fmt.Errorf("erro ao converter fotos: %w", err)
+	}
+
+	return &l, nil
+}
+
+// productCost obtém o custo atual de um produto
+func productCost(ctx context.Context, q rowQuerier, schema, productID string) (float64, error) {
+	var cost float64
+	err := q.QueryRow(ctx, fmt.Sprintf("SELECT cost_price FROM %s.products WHERE id = $1", schema), productID).Scan(&cost)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrLossProductNotFound
+		}
+		return 0, fmt.Errorf("falha ao obter custo do produto: %w", err)
+	}
+	return cost, nil
+}
+
+// lockInventory bloqueia o saldo do produto na filial, criando-o zerado se não existir,
+// e retorna a quantidade anterior à movimentação
+func lockInventory(ctx context.Context, tx pgx.Tx, schema, tenantID, branchID, productID string, now time.Time) (float64, error) {
+	_, err := tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.inventory (id, tenant_id, branch_id, product_id, quantity, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, 0, $5, $5)
+		ON CONFLICT (tenant_id, branch_id, product_id) DO NOTHING
+	`, schema), uuid.New().String(), tenantID, branchID, productID, now)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao preparar saldo de estoque: %w", err)
+	}
+
+	var quantity float64
+	err = tx.QueryRow(ctx, fmt.Sprintf(`
+		SELECT quantity FROM %s.inventory
+		WHERE tenant_id = $1 AND branch_id = $2 AND product_id = $3
+		FOR UPDATE
+	`, schema), tenantID, branchID, productID).Scan(&quantity)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao bloquear saldo de estoque: %w", err)
+	}
+
+	return quantity, nil
+}
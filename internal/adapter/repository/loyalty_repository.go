@@ -0,0 +1,867 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/loyalty"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório do programa de fidelidade
+var (
+	ErrLoyaltyProgramNotFound   = errors.New("programa de fidelidade não configurado")
+	ErrLoyaltyRuleNotFound      = errors.New("regra de pontuação não encontrada")
+	ErrLoyaltyRuleDuplicated    = errors.New("já existe regra de pontuação para a categoria")
+	ErrLoyaltyCampaignNotFound  = errors.New("campanha de pontuação não encontrada")
+	ErrLoyaltyRewardNotFound    = errors.New("prêmio não encontrado")
+	ErrLoyaltySaleAlreadyEarned = errors.New("venda já pontuada no programa de fidelidade")
+)
+
+// LoyaltyRepository implementa a interface loyalty.Repository
+type LoyaltyRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewLoyaltyRepository cria uma nova instância de LoyaltyRepository
+func NewLoyaltyRepository(db *pgxpool.Pool) loyalty.Repository {
+	return &LoyaltyRepository{
+		db: db,
+	}
+}
+
+const loyaltyEntryColumns = `id, tenant_id, customer_id, branch_id, type, points, remaining, amount, sale_id, reward_id,
+	expires_at, COALESCE(description, ''), created_by, created_at`
+
+// GetProgram implementa loyalty.Repository.GetProgram
+func (r *LoyaltyRepository) GetProgram(ctx context.Context) (*loyalty.Program, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, name, points_per_real, point_value, expiration_days, min_redeem_points, active,
+			created_at, updated_at
+		FROM %s.loyalty_programs
+		WHERE tenant_id = $1
+	`, schema)
+
+	var p loyalty.Program
+	err = conn.QueryRow(ctx, query, tenantID).Scan(&p.ID, &p.TenantID, &p.Name, &p.PointsPerReal, &p.PointValue,
+		&p.ExpirationDays, &p.MinRedeemPoints, &p.Active, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLoyaltyProgramNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar programa de fidelidade: %w", err)
+	}
+
+	return &p, nil
+}
+
+// SaveProgram implementa loyalty.Repository.SaveProgram
+func (r *LoyaltyRepository) SaveProgram(ctx context.Context, p *loyalty.Program) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	p.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.loyalty_programs (
+			id, tenant_id, name, points_per_real, point_value, expiration_days, min_redeem_points, active,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			name = EXCLUDED.name, points_per_real = EXCLUDED.points_per_real, point_value = EXCLUDED.point_value,
+			expiration_days = EXCLUDED.expiration_days, min_redeem_points = EXCLUDED.min_redeem_points,
+			active = EXCLUDED.active, updated_at = EXCLUDED.updated_at
+		RETURNING id, created_at
+	`, schema)
+
+	err = conn.QueryRow(ctx, query, p.ID, p.TenantID, p.Name, p.PointsPerReal, p.PointValue, p.ExpirationDays,
+		p.MinRedeemPoints, p.Active, p.CreatedAt, p.UpdatedAt).Scan(&p.ID, &p.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao salvar programa de fidelidade: %w", err)
+	}
+
+	return nil
+}
+
+// CreateRule implementa loyalty.Repository.CreateRule
+func (r *LoyaltyRepository) CreateRule(ctx context.Context, rule *loyalty.Rule) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	rule.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.loyalty_rules (id, tenant_id, category_id, points_per_real, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, rule.ID, rule.TenantID, rule.CategoryID, rule.PointsPerReal, rule.Active,
+		rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrLoyaltyRuleDuplicated
+		}
+		return fmt.Errorf("falha ao criar regra de pontuação: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateRule implementa loyalty.Repository.UpdateRule
+func (r *LoyaltyRepository) UpdateRule(ctx context.Context, rule *loyalty.Rule) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.loyalty_rules
+		SET category_id = $1, points_per_real = $2, active = $3, updated_at = $4
+		WHERE id = $5 AND tenant_id = $6
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, rule.CategoryID, rule.PointsPerReal, rule.Active, rule.UpdatedAt,
+		rule.ID, tenantID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrLoyaltyRuleDuplicated
+		}
+		return fmt.Errorf("falha ao atualizar regra de pontuação: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrLoyaltyRuleNotFound
+	}
+
+	return nil
+}
+
+// FindRuleByID implementa loyalty.Repository.FindRuleByID
+func (r *LoyaltyRepository) FindRuleByID(ctx context.Context, id string) (*loyalty.Rule, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, category_id, points_per_real, active, created_at, updated_at
+		FROM %s.loyalty_rules
+		WHERE id = $1 AND tenant_id = $2
+	`, schema)
+
+	var rule loyalty.Rule
+	err = conn.QueryRow(ctx, query, id, tenantID).Scan(&rule.ID, &rule.TenantID, &rule.CategoryID,
+		&rule.PointsPerReal, &rule.Active, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLoyaltyRuleNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar regra de pontuação: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// ListRules implementa loyalty.Repository.ListRules
+func (r *LoyaltyRepository) ListRules(ctx context.Context) ([]*loyalty.Rule, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, category_id, points_per_real, active, created_at, updated_at
+		FROM %s.loyalty_rules
+		WHERE tenant_id = $1
+		ORDER BY created_at
+	`, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar regras de pontuação: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*loyalty.Rule, 0)
+	for rows.Next() {
+		var rule loyalty.Rule
+		if err := rows.Scan(&rule.ID, &rule.TenantID, &rule.CategoryID, &rule.PointsPerReal, &rule.Active,
+			&rule.CreatedAt, &rule.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler regra de pontuação: %w", err)
+		}
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar regras de pontuação: %w", err)
+	}
+
+	return rules, nil
+}
+
+// CreateCampaign implementa loyalty.Repository.CreateCampaign
+func (r *LoyaltyRepository) CreateCampaign(ctx context.Context, c *loyalty.Campaign) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	c.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.loyalty_campaigns (
+			id, tenant_id, name, category_id, multiplier, starts_at, ends_at, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, c.ID, c.TenantID, c.Name, nullIfEmpty(c.CategoryID), c.Multiplier, c.StartsAt,
+		c.EndsAt, c.Active, c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao criar campanha de pontuação: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateCampaign implementa loyalty.Repository.UpdateCampaign
+func (r *LoyaltyRepository) UpdateCampaign(ctx context.Context, c *loyalty.Campaign) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.loyalty_campaigns
+		SET name = $1, category_id = $2, multiplier = $3, starts_at = $4, ends_at = $5, active = $6, updated_at = $7
+		WHERE id = $8 AND tenant_id = $9
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, c.Name, nullIfEmpty(c.CategoryID), c.Multiplier, c.StartsAt, c.EndsAt,
+		c.Active, c.UpdatedAt, c.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar campanha de pontuação: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrLoyaltyCampaignNotFound
+	}
+
+	return nil
+}
+
+// FindCampaignByID implementa loyalty.Repository.FindCampaignByID
+func (r *LoyaltyRepository) FindCampaignByID(ctx context.Context, id string) (*loyalty.Campaign, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, name, category_id, multiplier, starts_at, ends_at, active, created_at, updated_at
+		FROM %s.loyalty_campaigns
+		WHERE id = $1 AND tenant_id = $2
+	`, schema)
+
+	c, err := scanLoyaltyCampaign(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLoyaltyCampaignNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar campanha de pontuação: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListCampaigns implementa loyalty.Repository.ListCampaigns
+func (r *LoyaltyRepository) ListCampaigns(ctx context.Context, at *time.Time) ([]*loyalty.Campaign, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where := "tenant_id = $1"
+	args := []interface{}{tenantID}
+	if at != nil {
+		args = append(args, *at)
+		where += " AND active = true AND starts_at <= $2 AND ends_at > $2"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, name, category_id, multiplier, starts_at, ends_at, active, created_at, updated_at
+		FROM %s.loyalty_campaigns
+		WHERE %s
+		ORDER BY starts_at DESC
+	`, schema, where)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar campanhas de pontuação: %w", err)
+	}
+	defer rows.Close()
+
+	campaigns := make([]*loyalty.Campaign, 0)
+	for rows.Next() {
+		c, err := scanLoyaltyCampaign(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler campanha de pontuação: %w", err)
+		}
+		campaigns = append(campaigns, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar campanhas de pontuação: %w", err)
+	}
+
+	return campaigns, nil
+}
+
+// CreateReward implementa loyalty.Repository.CreateReward
+func (r *LoyaltyRepository) CreateReward(ctx context.Context, reward *loyalty.Reward) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	reward.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.loyalty_rewards (id, tenant_id, product_id, name, points, active, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, reward.ID, reward.TenantID, reward.ProductID, reward.Name, reward.Points,
+		reward.Active, reward.CreatedAt, reward.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao criar prêmio: %w", err)
+	}
+
+	return nil
+}
+
+// UpdateReward implementa loyalty.Repository.UpdateReward
+func (r *LoyaltyRepository) UpdateReward(ctx context.Context, reward *loyalty.Reward) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.loyalty_rewards
+		SET product_id = $1, name = $2, points = $3, active = $4, updated_at = $5
+		WHERE id = $6 AND tenant_id = $7
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, reward.ProductID, reward.Name, reward.Points, reward.Active,
+		reward.UpdatedAt, reward.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar prêmio: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrLoyaltyRewardNotFound
+	}
+
+	return nil
+}
+
+// FindRewardByID implementa loyalty.Repository.FindRewardByID
+func (r *LoyaltyRepository) FindRewardByID(ctx context.Context, id string) (*loyalty.Reward, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, product_id, name, points, active, created_at, updated_at
+		FROM %s.loyalty_rewards
+		WHERE id = $1 AND tenant_id = $2
+	`, schema)
+
+	var reward loyalty.Reward
+	err = conn.QueryRow(ctx, query, id, tenantID).Scan(&reward.ID, &reward.TenantID, &reward.ProductID,
+		&reward.Name, &reward.Points, &reward.Active, &reward.CreatedAt, &reward.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrLoyaltyRewardNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar prêmio: %w", err)
+	}
+
+	return &reward, nil
+}
+
+// ListRewards implementa loyalty.Repository.ListRewards
+func (r *LoyaltyRepository) ListRewards(ctx context.Context, onlyActive bool) ([]*loyalty.Reward, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where := "tenant_id = $1"
+	if onlyActive {
+		where += " AND active = true"
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, product_id, name, points, active, created_at, updated_at
+		FROM %s.loyalty_rewards
+		WHERE %s
+		ORDER BY points, name
+	`, schema, where)
+
+	rows, err := conn.Query(ctx, query, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar prêmios: %w", err)
+	}
+	defer rows.Close()
+
+	rewards := make([]*loyalty.Reward, 0)
+	for rows.Next() {
+		var reward loyalty.Reward
+		if err := rows.Scan(&reward.ID, &reward.TenantID, &reward.ProductID, &reward.Name, &reward.Points,
+			&reward.Active, &reward.CreatedAt, &reward.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler prêmio: %w", err)
+		}
+		rewards = append(rewards, &reward)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar prêmios: %w", err)
+	}
+
+	return rewards, nil
+}
+
+// Earn implementa loyalty.Repository.Earn
+func (r *LoyaltyRepository) Earn(ctx context.Context, e *loyalty.Entry) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	e.TenantID = tenantID
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertLoyaltyEntry(ctx, tx, schema, e); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrLoyaltySaleAlreadyEarned
+		}
+		return fmt.Errorf("falha ao registrar pontos: %w", err)
+	}
+
+	result, err := tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.customers SET last_purchase_at = $1 WHERE id = $2 AND tenant_id = $3
+	`, schema), e.CreatedAt, e.CustomerID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar última compra do cliente: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrCustomerNotFound
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// Redeem implementa loyalty.Repository.Redeem
+func (r *LoyaltyRepository) Redeem(ctx context.Context, e *loyalty.Entry) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	e.TenantID = tenantID
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Bloqueia os ganhos disponíveis para que resgates simultâneos não usem os mesmos pontos
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT %s FROM %s.loyalty_entries
+		WHERE tenant_id = $1 AND customer_id = $2 AND type = 'earn' AND remaining > 0 AND expires_at > $3
+		ORDER BY expires_at, created_at
+		FOR UPDATE
+	`, loyaltyEntryColumns, schema), tenantID, e.CustomerID, e.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao buscar saldo de pontos: %w", err)
+	}
+
+	earnings := make([]*loyalty.Entry, 0)
+	for rows.Next() {
+		earning, err := scanLoyaltyEntry(rows)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("falha ao ler saldo de pontos: %w", err)
+		}
+		earnings = append(earnings, earning)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("erro ao iterar saldo de pontos: %w", err)
+	}
+
+	changed, err := loyalty.Consume(earnings, -e.Points)
+	if err != nil {
+		return err
+	}
+
+	update := fmt.Sprintf("UPDATE %s.loyalty_entries SET remaining = $1 WHERE id = $2", schema)
+	for _, earning := range changed {
+		if _, err := tx.Exec(ctx, update, earning.Remaining, earning.ID); err != nil {
+			return fmt.Errorf("falha ao baixar pontos: %w", err)
+		}
+	}
+
+	if err := insertLoyaltyEntry(ctx, tx, schema, e); err != nil {
+		return fmt.Errorf("falha ao registrar resgate de pontos: %w", err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// Balance implementa loyalty.Repository.Balance
+func (r *LoyaltyRepository) Balance(ctx context.Context, customerID string, at time.Time) (*loyalty.Balance, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COALESCE(SUM(remaining), 0),
+			COALESCE(SUM(remaining) FILTER (WHERE expires_at <= $4), 0),
+			MIN(expires_at)
+		FROM %s.loyalty_entries
+		WHERE tenant_id = $1 AND customer_id = $2 AND type = 'earn' AND remaining > 0 AND expires_at > $3
+	`, schema)
+
+	b := &loyalty.Balance{CustomerID: customerID}
+	var nextExpiration pgtype.Timestamp
+	window := at.AddDate(0, 0, loyalty.ExpiringWindowDays)
+	err = conn.QueryRow(ctx, query, tenantID, customerID, at, window).Scan(&b.Points, &b.ExpiringPoints, &nextExpiration)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao calcular saldo de pontos: %w", err)
+	}
+	if nextExpiration.Valid {
+		b.NextExpiration = &nextExpiration.Time
+	}
+
+	return b, nil
+}
+
+// ListEntries implementa loyalty.Repository.ListEntries
+func (r *LoyaltyRepository) ListEntries(ctx context.Context, customerID string, limit, offset int) ([]*loyalty.Entry, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.loyalty_entries
+		WHERE tenant_id = $1 AND customer_id = $2
+		ORDER BY created_at DESC
+		LIMIT $3 OFFSET $4
+	`, loyaltyEntryColumns, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID, customerID, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar extrato de pontos: %w", err)
+	}
+	defer rows.Close()
+
+	entries := make([]*loyalty.Entry, 0)
+	for rows.Next() {
+		e, err := scanLoyaltyEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler extrato de pontos: %w", err)
+		}
+		entries = append(entries, e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar extrato de pontos: %w", err)
+	}
+
+	return entries, nil
+}
+
+// CountEntries implementa loyalty.Repository.CountEntries
+func (r *LoyaltyRepository) CountEntries(ctx context.Context, customerID string) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.loyalty_entries WHERE tenant_id = $1 AND customer_id = $2", schema)
+	if err := conn.QueryRow(ctx, query, tenantID, customerID).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar extrato de pontos: %w", err)
+	}
+
+	return count, nil
+}
+
+// Expire implementa loyalty.Repository.Expire
+func (r *LoyaltyRepository) Expire(ctx context.Context, at time.Time) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Ganhos bloqueados por um resgate em andamento ficam para a próxima execução
+	rows, err := tx.Query(ctx, fmt.Sprintf(`
+		SELECT %s FROM %s.loyalty_entries
+		WHERE tenant_id = $1 AND type = 'earn' AND remaining > 0 AND expires_at <= $2
+		FOR UPDATE SKIP LOCKED
+	`, loyaltyEntryColumns, schema), tenantID, at)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao buscar pontos vencidos: %w", err)
+	}
+
+	earnings := make([]*loyalty.Entry, 0)
+	for rows.Next() {
+		earning, err := scanLoyaltyEntry(rows)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("falha ao ler pontos vencidos: %w", err)
+		}
+		earnings = append(earnings, earning)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("erro ao iterar pontos vencidos: %w", err)
+	}
+
+	update := fmt.Sprintf("UPDATE %s.loyalty_entries SET remaining = 0 WHERE id = $1", schema)
+	for _, earning := range earnings {
+		expired := &loyalty.Entry{
+			ID:          uuid.New().String(),
+			TenantID:    tenantID,
+			CustomerID:  earning.CustomerID,
+			BranchID:    earning.BranchID,
+			Type:        loyalty.EntryExpire,
+			Points:      -earning.Remaining,
+			SaleID:      earning.SaleID,
+			ExpiresAt:   earning.ExpiresAt,
+			Description: "vencimento dos pontos",
+			CreatedAt:   at,
+		}
+		if err := insertLoyaltyEntry(ctx, tx, schema, expired); err != nil {
+			return 0, fmt.Errorf("falha ao registrar vencimento de pontos: %w", err)
+		}
+		if _, err := tx.Exec(ctx, update, earning.ID); err != nil {
+			return 0, fmt.Errorf("falha ao baixar pontos vencidos: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return len(earnings), nil
+}
+
+// insertLoyaltyEntry grava um lançamento no extrato de pontos
+func insertLoyaltyEntry(ctx context.Context, tx pgx.Tx, schema string, e *loyalty.Entry) error {
+	_, err := tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.loyalty_entries (
+			id, tenant_id, customer_id, branch_id, type, points, remaining, amount, sale_id, reward_id, expires_at,
+			description, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, schema), e.ID, e.TenantID, e.CustomerID, nullIfEmpty(e.BranchID), string(e.Type), e.Points, e.Remaining,
+		e.Amount, nullIfEmpty(e.SaleID), nullIfEmpty(e.RewardID), e.ExpiresAt, nullIfEmpty(e.Description),
+		nullIfEmpty(e.CreatedBy), e.CreatedAt)
+	return err
+}
+
+// scanLoyaltyCampaign lê uma campanha de pontuação de uma linha de resultado
+func scanLoyaltyCampaign(row pgx.Row) (*loyalty.Campaign, error) {
+	var c loyalty.Campaign
+	var categoryID pgtype.Text
+
+	err := row.Scan(&c.ID, &c.TenantID, &c.Name, &categoryID, &c.Multiplier, &c.StartsAt, &c.EndsAt, &c.Active,
+		&c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.CategoryID = categoryID.String
+	return &c, nil
+}
+
+// scanLoyaltyEntry lê um lançamento do extrato de pontos de uma linha de resultado
+func scanLoyaltyEntry(row pgx.Row) (*loyalty.Entry, error) {
+	var e loyalty.Entry
+	var entryType string
+	var branchID, saleID, rewardID, createdBy pgtype.Text
+	var expiresAt pgtype.Timestamp
+
+	err := row.Scan(&e.ID, &e.TenantID, &e.CustomerID, &branchID, &entryType, &e.Points, &e.Remaining, &e.Amount,
+		&saleID, &rewardID, &expiresAt, &e.Description, &createdBy, &e.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	e.Type = loyalty.EntryType(entryType)
+	e.BranchID = branchID.String
+	e.SaleID = saleID.String
+	e.RewardID = rewardID.String
+	e.CreatedBy = createdBy.String
+	if expiresAt.Valid {
+		e.ExpiresAt = &expiresAt.Time
+	}
+	return &e, nil
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/onboarding"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// OnboardingRepository implementa a interface onboarding.Repository
+type OnboardingRepository struct {
+	db      *pgxpool.Pool
+	tenants tenant.Repository
+}
+
+// NewOnboardingRepository cria uma nova instância de OnboardingRepository
+func NewOnboardingRepository(db *pgxpool.Pool) onboarding.Repository {
+	return &OnboardingRepository{
+		db:      db,
+		tenants: NewTenantRepository(db),
+	}
+}
+
+// Create implementa onboarding.Repository.Create. O cadastro do tenant e as migrações do schema
+// são confirmados em etapas separadas; a matriz, o administrador, a configuração fiscal e os dados
+// iniciais são gravados em uma única transação no schema já migrado. Em caso de falha, o schema e
+// o cadastro do tenant são removidos
+func (r *OnboardingRepository) Create(ctx context.Context, o *onboarding.Onboarding) error {
+	if err := r.tenants.Create(ctx, o.Tenant); err != nil {
+		return err
+	}
+
+	if err := r.provision(ctx, o); err != nil {
+		// A limpeza não depende da requisição, que pode ter sido cancelada
+		if cleanupErr := r.discard(context.WithoutCancel(ctx), o.Tenant); cleanupErr != nil {
+			return errors.Join(err, fmt.Errorf("falha ao desfazer cadastro do tenant %s: %w", o.Tenant.ID, cleanupErr))
+		}
+		return err
+	}
+	return nil
+}
+
+// provision cria o schema do tenant e grava os dados do cadastro inicial
+func (r *OnboardingRepository) provision(ctx context.Context, o *onboarding.Onboarding) error {
+	if err := database.CreateTenantSchema(ctx, r.db, o.Tenant.Schema); err != nil {
+		return err
+	}
+
+	return database.TenantTxFor(ctx, r.db, o.Tenant.ID, func(tx pgx.Tx, scope database.TenantScope) error {
+		if err := insertBranch(ctx, tx, scope, o.Branch); err != nil {
+			return err
+		}
+		if err := insertUser(ctx, tx, scope, o.Admin); err != nil {
+			return err
+		}
+		if err := insertFiscalConfig(ctx, tx, scope, o.FiscalConfig); err != nil {
+			return err
+		}
+
+		categoryQuery := fmt.Sprintf(`
+			INSERT INTO %s (id, tenant_id, name, code, active, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, true, $5, $5)
+		`, scope.Table("product_categories"))
+		for _, c := range o.Categories {
+			if _, err := tx.Exec(ctx, categoryQuery, c.ID, c.TenantID, c.Name, c.Code, c.CreatedAt); err != nil {
+				return fmt.Errorf("falha ao criar categoria %s: %w", c.Name, err)
+			}
+		}
+
+		methodQuery := fmt.Sprintf(`
+			INSERT INTO %s (%s)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		`, scope.Table("payment_methods"), paymentMethodColumns)
+		for _, m := range o.PaymentMethods {
+			_, err := tx.Exec(ctx, methodQuery, m.ID, m.TenantID, m.Name, string(m.Kind), m.TPag, m.MaxInstallments,
+				m.ChangeAllowed, m.RequiresTEF, m.Active, m.CreatedAt, m.UpdatedAt)
+			if err != nil {
+				return fmt.Errorf("falha ao criar forma de pagamento %s: %w", m.Name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// discard remove o schema e o cadastro de um tenant cujo cadastro inicial falhou. Os logins em
+// public.user_logins são removidos em cascata
+func (r *OnboardingRepository) discard(ctx context.Context, t *tenant.Tenant) error {
+	defer database.InvalidateTenantSchema(t.ID)
+
+	if err := database.DropTenantSchema(ctx, r.db, t.Schema); err != nil {
+		return err
+	}
+	if _, err := r.db.Exec(ctx, "DELETE FROM tenants WHERE id = $1", t.ID); err != nil {
+		return fmt.Errorf("erro ao excluir tenant: %w", err)
+	}
+	return nil
+}
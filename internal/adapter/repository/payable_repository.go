@@ -0,0 +1,407 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/payable"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de contas a pagar
+var (
+	ErrPayableNotFound          = errors.New("título a pagar não encontrado")
+	ErrPayableConcurrentUpdate  = errors.New("título foi alterado por outra operação, tente novamente")
+	ErrPurchaseAlreadyGenerated = errors.New("os títulos desta compra já foram gerados")
+)
+
+// PayableRepository implementa a interface payable.Repository
+type PayableRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPayableRepository cria uma nova instância de PayableRepository
+func NewPayableRepository(db *pgxpool.Pool) payable.Repository {
+	return &PayableRepository{
+		db: db,
+	}
+}
+
+// payableColumns lista as colunas lidas da tabela de títulos a pagar
+const payableColumns = `id, tenant_id, branch_id, supplier_id, purchase_id, COALESCE(document_number, ''),
+	COALESCE(description, ''), installment, installments, issue_date, due_date, amount, paid_amount,
+	interest, fine, discount, status, COALESCE(cancel_reason, ''), created_by, created_at, updated_at`
+
+// Create implementa payable.Repository.Create
+func (r *PayableRepository) Create(ctx context.Context, payables ...*payable.Payable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.payables (
+			id, tenant_id, branch_id, supplier_id, purchase_id, document_number, description,
+			installment, installments, issue_date, due_date, amount, paid_amount, interest, fine,
+			discount, status, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+	`, schema)
+
+	for _, p := range payables {
+		p.TenantID = tenantID
+		_, err = tx.Exec(ctx, query,
+			p.ID, p.TenantID, p.BranchID, p.SupplierID, nullIfEmpty(p.PurchaseID), p.DocumentNumber,
+			p.Description, p.Installment, p.Installments, p.IssueDate, p.DueDate, p.Amount, p.PaidAmount,
+			p.Interest, p.Fine, p.Discount, string(p.Status), nullIfEmpty(p.CreatedBy), p.CreatedAt, p.UpdatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return ErrPurchaseAlreadyGenerated
+			}
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return ErrSupplierNotFound
+			}
+			return fmt.Errorf("falha ao inserir título a pagar: %w", err)
+		}
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID implementa payable.Repository.FindByID
+func (r *PayableRepository) FindByID(ctx context.Context, id string) (*payable.Payable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.payables WHERE id = $1 AND tenant_id = $2", payableColumns, schema)
+
+	p, err := scanPayable(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPayableNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar título a pagar: %w", err)
+	}
+
+	paymentsQuery := fmt.Sprintf(`
+		SELECT id, payable_id, paid_at, amount, interest, fine, discount, total,
+			COALESCE(method, ''), COALESCE(notes, ''), created_by, created_at
+		FROM %s.payable_payments
+		WHERE payable_id = $1
+		ORDER BY paid_at, created_at
+	`, schema)
+
+	rows, err := conn.Query(ctx, paymentsQuery, p.ID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar baixas do título: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payment payable.Payment
+		var createdBy pgtype.Text
+		if err := rows.Scan(&payment.ID, &payment.PayableID, &payment.PaidAt, &payment.Amount,
+			&payment.Interest, &payment.Fine, &payment.Discount, &payment.Total, &payment.Method,
+			&payment.Notes, &createdBy, &payment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler baixa do título: %w", err)
+		}
+		payment.CreatedBy = createdBy.String
+		p.Payments = append(p.Payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar baixas do título: %w", err)
+	}
+
+	return p, nil
+}
+
+// List implementa payable.Repository.List
+func (r *PayableRepository) List(ctx context.Context, filter payable.ListFilter, limit, offset int) ([]*payable.Payable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildPayableFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.payables
+		WHERE %s
+		ORDER BY due_date, installment
+		LIMIT $%d OFFSET $%d
+	`, payableColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar títulos a pagar: %w", err)
+	}
+	defer rows.Close()
+
+	payables := make([]*payable.Payable, 0)
+	for rows.Next() {
+		p, err := scanPayable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler título a pagar: %w", err)
+		}
+		payables = append(payables, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar títulos a pagar: %w", err)
+	}
+
+	return payables, nil
+}
+
+// Count implementa payable.Repository.Count
+func (r *PayableRepository) Count(ctx context.Context, filter payable.ListFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildPayableFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.payables WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar títulos a pagar: %w", err)
+	}
+
+	return count, nil
+}
+
+// RegisterPayment implementa payable.Repository.RegisterPayment
+func (r *PayableRepository) RegisterPayment(ctx context.Context, p *payable.Payable, payment *payable.Payment) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applyPayablePayment(ctx, tx, schema, tenantID, p, payment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// Cancel implementa payable.Repository.Cancel
+func (r *PayableRepository) Cancel(ctx context.Context, p *payable.Payable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.payables SET status = $1, cancel_reason = $2, updated_at = $3
+		WHERE id = $4 AND tenant_id = $5 AND status = 'open' AND paid_amount = 0
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, string(p.Status), p.CancelReason, p.UpdatedAt, p.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao cancelar título a pagar: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrPayableConcurrentUpdate
+	}
+
+	return nil
+}
+
+// Calendar implementa payable.Repository.Calendar
+func (r *PayableRepository) Calendar(ctx context.Context, filter payable.ListFilter) ([]payable.CalendarDay, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildPayableFilter(tenantID, filter)
+
+	query := fmt.Sprintf(`
+		SELECT due_date, COUNT(*), SUM(amount), SUM(amount - paid_amount)
+		FROM %s.payables
+		WHERE %s AND status IN ('open', 'partial')
+		GROUP BY due_date
+		ORDER BY due_date
+	`, schema, where)
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao montar calendário de vencimentos: %w", err)
+	}
+	defer rows.Close()
+
+	days := make([]payable.CalendarDay, 0)
+	for rows.Next() {
+		var day payable.CalendarDay
+		if err := rows.Scan(&day.Date, &day.Count, &day.Amount, &day.Balance); err != nil {
+			return nil, fmt.Errorf("falha ao ler dia do calendário: %w", err)
+		}
+		days = append(days, day)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar calendário de vencimentos: %w", err)
+	}
+
+	return days, nil
+}
+
+// applyPayablePayment atualiza o saldo do título e grava o pagamento dentro da transação informada
+func applyPayablePayment(ctx context.Context, tx pgx.Tx, schema, tenantID string, p *payable.Payable, payment *payable.Payment) error {
+	// O saldo anterior garante que duas baixas simultâneas não se sobreponham
+	previousPaid := p.PaidAmount - payment.Amount
+	result, err := tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.payables
+		SET paid_amount = $1, interest = $2, fine = $3, discount = $4, status = $5, updated_at = $6
+		WHERE id = $7 AND tenant_id = $8 AND status IN ('open', 'partial') AND paid_amount = ROUND($9::numeric, 2)
+	`, schema), p.PaidAmount, p.Interest, p.Fine, p.Discount, string(p.Status), p.UpdatedAt,
+		p.ID, tenantID, previousPaid)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar título a pagar: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPayableConcurrentUpdate
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.payable_payments (
+			id, payable_id, paid_at, amount, interest, fine, discount, total, method, notes, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, schema), payment.ID, p.ID, payment.PaidAt, payment.Amount, payment.Interest, payment.Fine,
+		payment.Discount, payment.Total, payment.Method, payment.Notes, nullIfEmpty(payment.CreatedBy),
+		payment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao registrar pagamento: %w", err)
+	}
+
+	return nil
+}
+
+// buildPayableFilter monta a cláusula WHERE da listagem de títulos a pagar
+func buildPayableFilter(tenantID string, filter payable.ListFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("branch_id = $%d", len(args)))
+	}
+	if filter.SupplierID != "" {
+		args = append(args, filter.SupplierID)
+		conditions = append(conditions, fmt.Sprintf("supplier_id = $%d", len(args)))
+	}
+	if filter.PurchaseID != "" {
+		args = append(args, filter.PurchaseID)
+		conditions = append(conditions, fmt.Sprintf("purchase_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if !filter.DueFrom.IsZero() {
+		args = append(args, filter.DueFrom)
+		conditions = append(conditions, fmt.Sprintf("due_date >= $%d", len(args)))
+	}
+	if !filter.DueTo.IsZero() {
+		args = append(args, filter.DueTo)
+		conditions = append(conditions, fmt.Sprintf("due_date <= $%d", len(args)))
+	}
+	if filter.Overdue {
+		conditions = append(conditions, "status IN ('open', 'partial') AND due_date < CURRENT_DATE")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanPayable lê um título a pagar de uma linha de resultado
+func scanPayable(row pgx.Row) (*payable.Payable, error) {
+	var p payable.Payable
+	var status string
+	var purchaseID, createdBy pgtype.Text
+
+	err := row.Scan(&p.ID, &p.TenantID, &p.BranchID, &p.SupplierID, &purchaseID, &p.DocumentNumber,
+		&p.Description, &p.Installment, &p.Installments, &p.IssueDate, &p.DueDate, &p.Amount, &p.PaidAmount,
+		&p.Interest, &p.Fine, &p.Discount, &status, &p.CancelReason, &createdBy, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Status = payable.Status(status)
+	p.PurchaseID = purchaseID.String
+	p.CreatedBy = createdBy.String
+	p.Payments = []payable.Payment{}
+	return &p, nil
+}
@@ -0,0 +1,263 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/paymentmethod"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrPaymentMethodNotFound   = errors.New("forma de pagamento não encontrada")
+	ErrPaymentMethodDuplicated = errors.New("já existe forma de pagamento com esse nome")
+	ErrPaymentMethodInUse      = errors.New("forma de pagamento vinculada a clientes")
+)
+
+// PaymentMethodRepository implementa a interface paymentmethod.Repository
+type PaymentMethodRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPaymentMethodRepository cria uma nova instância de PaymentMethodRepository
+func NewPaymentMethodRepository(db *pgxpool.Pool) paymentmethod.Repository {
+	return &PaymentMethodRepository{
+		db: db,
+	}
+}
+
+const paymentMethodColumns = `id, tenant_id, name, kind, tpag, max_installments, change_allowed, requires_tef, active,
+	created_at, updated_at`
+
+// Create implementa paymentmethod.Repository.Create
+func (r *PaymentMethodRepository) Create(ctx context.Context, m *paymentmethod.PaymentMethod) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	m.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.payment_methods (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, schema, paymentMethodColumns)
+
+	_, err = conn.Exec(ctx, query, m.ID, m.TenantID, m.Name, string(m.Kind), m.TPag, m.MaxInstallments,
+		m.ChangeAllowed, m.RequiresTEF, m.Active, m.CreatedAt, m.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrPaymentMethodDuplicated
+		}
+		return fmt.Errorf("falha ao criar forma de pagamento: %w", err)
+	}
+
+	return nil
+}
+
+// Update implementa paymentmethod.Repository.Update
+func (r *PaymentMethodRepository) Update(ctx context.Context, m *paymentmethod.PaymentMethod) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.payment_methods
+		SET name = $1, kind = $2, tpag = $3, max_installments = $4, change_allowed = $5, requires_tef = $6,
+			active = $7, updated_at = $8
+		WHERE id = $9 AND tenant_id = $10
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, m.Name, string(m.Kind), m.TPag, m.MaxInstallments, m.ChangeAllowed,
+		m.RequiresTEF, m.Active, m.UpdatedAt, m.ID, tenantID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrPaymentMethodDuplicated
+		}
+		return fmt.Errorf("falha ao atualizar forma de pagamento: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrPaymentMethodNotFound
+	}
+
+	return nil
+}
+
+// Delete implementa paymentmethod.Repository.Delete
+func (r *PaymentMethodRepository) Delete(ctx context.Context, id string) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	var inUse bool
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.customers WHERE payment_method_id = $1 AND tenant_id = $2)", schema)
+	if err := conn.QueryRow(ctx, query, id, tenantID).Scan(&inUse); err != nil {
+		return fmt.Errorf("falha ao verificar clientes da forma de pagamento: %w", err)
+	}
+	if inUse {
+		return ErrPaymentMethodInUse
+	}
+
+	result, err := conn.Exec(ctx, fmt.Sprintf("DELETE FROM %s.payment_methods WHERE id = $1 AND tenant_id = $2", schema), id, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao excluir forma de pagamento: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPaymentMethodNotFound
+	}
+
+	return nil
+}
+
+// FindByID implementa paymentmethod.Repository.FindByID
+func (r *PaymentMethodRepository) FindByID(ctx context.Context, id string) (*paymentmethod.PaymentMethod, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.payment_methods WHERE id = $1 AND tenant_id = $2", paymentMethodColumns, schema)
+
+	m, err := scanPaymentMethod(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPaymentMethodNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar forma de pagamento: %w", err)
+	}
+
+	return m, nil
+}
+
+// List implementa paymentmethod.Repository.List
+func (r *PaymentMethodRepository) List(ctx context.Context, filter paymentmethod.Filter, limit, offset int) ([]*paymentmethod.PaymentMethod, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildPaymentMethodFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.payment_methods
+		WHERE %s
+		ORDER BY name
+		LIMIT $%d OFFSET $%d
+	`, paymentMethodColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar formas de pagamento: %w", err)
+	}
+	defer rows.Close()
+
+	methods := make([]*paymentmethod.PaymentMethod, 0)
+	for rows.Next() {
+		m, err := scanPaymentMethod(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler forma de pagamento: %w", err)
+		}
+		methods = append(methods, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar formas de pagamento: %w", err)
+	}
+
+	return methods, nil
+}
+
+// Count implementa paymentmethod.Repository.Count
+func (r *PaymentMethodRepository) Count(ctx context.Context, filter paymentmethod.Filter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildPaymentMethodFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.payment_methods WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar formas de pagamento: %w", err)
+	}
+
+	return count, nil
+}
+
+// buildPaymentMethodFilter monta a cláusula WHERE para as consultas de formas de pagamento
+func buildPaymentMethodFilter(tenantID string, filter paymentmethod.Filter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.Kind != "" {
+		args = append(args, string(filter.Kind))
+		conditions = append(conditions, fmt.Sprintf("kind = $%d", len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanPaymentMethod lê uma forma de pagamento de uma linha de resultado
+func scanPaymentMethod(row pgx.Row) (*paymentmethod.PaymentMethod, error) {
+	var m paymentmethod.PaymentMethod
+	var kind string
+
+	err := row.Scan(&m.ID, &m.TenantID, &m.Name, &kind, &m.TPag, &m.MaxInstallments, &m.ChangeAllowed,
+		&m.RequiresTEF, &m.Active, &m.CreatedAt, &m.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	m.Kind = paymentmethod.Kind(kind)
+
+	return &m, nil
+}
@@ -0,0 +1,487 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pix"
+	pkgpix "github.com/hugohenrick/erp-supermercado/pkg/pix"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de Pix
+var (
+	ErrPixChargeNotFound    = errors.New("cobrança Pix não encontrada")
+	ErrPixSettingsNotFound  = errors.New("configuração de Pix não encontrada")
+	ErrPixConcurrentUpdate  = errors.New("cobrança Pix foi alterada por outra operação")
+	ErrPixNotificationEmpty = errors.New("notificação Pix sem endToEndId")
+)
+
+// PixRepository implementa a interface pix.Repository
+type PixRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPixRepository cria uma nova instância de PixRepository
+func NewPixRepository(db *pgxpool.Pool) pix.Repository {
+	return &PixRepository{
+		db: db,
+	}
+}
+
+const pixChargeColumns = `id, tenant_id, branch_id, kind, txid, sale_id, receivable_id, customer_id, amount,
+	COALESCE(description, ''), br_code, COALESCE(location, ''), COALESCE(provider, ''), status,
+	COALESCE(end_to_end_id, ''), paid_amount, paid_at, expires_at, created_by, created_at, updated_at`
+
+// FindSettings implementa pix.Repository.FindSettings
+func (r *PixRepository) FindSettings(ctx context.Context) (*pix.Settings, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT tenant_id, pix_key, merchant_name, merchant_city, COALESCE(postal_code, ''), COALESCE(provider, ''),
+			COALESCE(base_url, ''), COALESCE(client_id, ''), COALESCE(client_secret, ''),
+			COALESCE(webhook_secret, ''), expires_in, updated_at
+		FROM %s.pix_settings
+		WHERE tenant_id = $1
+	`, schema)
+
+	var s pix.Settings
+	err = conn.QueryRow(ctx, query, tenantID).Scan(&s.TenantID, &s.Key, &s.MerchantName, &s.MerchantCity,
+		&s.PostalCode, &s.Provider, &s.BaseURL, &s.ClientID, &s.ClientSecret, &s.WebhookSecret, &s.ExpiresIn,
+		&s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPixSettingsNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar configuração de Pix: %w", err)
+	}
+
+	return &s, nil
+}
+
+// SaveSettings implementa pix.Repository.SaveSettings
+func (r *PixRepository) SaveSettings(ctx context.Context, s *pix.Settings) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	s.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.pix_settings (
+			tenant_id, pix_key, merchant_name, merchant_city, postal_code, provider, base_url, client_id,
+			client_secret, webhook_secret, expires_in, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			pix_key = EXCLUDED.pix_key, merchant_name = EXCLUDED.merchant_name,
+			merchant_city = EXCLUDED.merchant_city, postal_code = EXCLUDED.postal_code,
+			provider = EXCLUDED.provider, base_url = EXCLUDED.base_url, client_id = EXCLUDED.client_id,
+			client_secret = EXCLUDED.client_secret, webhook_secret = EXCLUDED.webhook_secret,
+			expires_in = EXCLUDED.expires_in, updated_at = EXCLUDED.updated_at
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, s.TenantID, s.Key, s.MerchantName, s.MerchantCity, nullIfEmpty(s.PostalCode),
+		nullIfEmpty(s.Provider), nullIfEmpty(s.BaseURL), nullIfEmpty(s.ClientID), nullIfEmpty(s.ClientSecret),
+		nullIfEmpty(s.WebhookSecret), s.ExpiresIn, s.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao salvar configuração de Pix: %w", err)
+	}
+
+	return nil
+}
+
+// Create implementa pix.Repository.Create
+func (r *PixRepository) Create(ctx context.Context, c *pix.Charge) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	c.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.pix_charges (
+			id, tenant_id, branch_id, kind, txid, sale_id, receivable_id, customer_id, amount, description,
+			br_code, location, provider, status, paid_amount, expires_at, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, c.ID, c.TenantID, c.BranchID, string(c.Kind), c.TxID, nullIfEmpty(c.SaleID),
+		nullIfEmpty(c.ReceivableID), nullIfEmpty(c.CustomerID), c.Amount, nullIfEmpty(c.Description), c.BRCode,
+		nullIfEmpty(c.Location), nullIfEmpty(c.Provider), string(c.Status), c.PaidAmount, c.ExpiresAt,
+		nullIfEmpty(c.CreatedBy), c.CreatedAt, c.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao criar cobrança Pix: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID implementa pix.Repository.FindByID
+func (r *PixRepository) FindByID(ctx context.Context, id string) (*pix.Charge, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.pix_charges WHERE id = $1 AND tenant_id = $2", pixChargeColumns, schema)
+
+	c, err := scanPixCharge(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPixChargeNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar cobrança Pix: %w", err)
+	}
+
+	return c, nil
+}
+
+// List implementa pix.Repository.List
+func (r *PixRepository) List(ctx context.Context, filter pix.ChargeFilter, limit, offset int) ([]*pix.Charge, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildPixChargeFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.pix_charges
+		WHERE %s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, pixChargeColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar cobranças Pix: %w", err)
+	}
+	defer rows.Close()
+
+	charges := make([]*pix.Charge, 0)
+	for rows.Next() {
+		c, err := scanPixCharge(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler cobrança Pix: %w", err)
+		}
+		charges = append(charges, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar cobranças Pix: %w", err)
+	}
+
+	return charges, nil
+}
+
+// Count implementa pix.Repository.Count
+func (r *PixRepository) Count(ctx context.Context, filter pix.ChargeFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildPixChargeFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.pix_charges WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar cobranças Pix: %w", err)
+	}
+
+	return count, nil
+}
+
+// Cancel implementa pix.Repository.Cancel
+func (r *PixRepository) Cancel(ctx context.Context, c *pix.Charge) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	// Uma notificação de pagamento processada ao mesmo tempo tem prioridade sobre o cancelamento
+	query := fmt.Sprintf(`
+		UPDATE %s.pix_charges SET status = $1, updated_at = $2
+		WHERE id = $3 AND tenant_id = $4 AND status = 'active'
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, string(c.Status), c.UpdatedAt, c.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao cancelar cobrança Pix: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrPixConcurrentUpdate
+	}
+
+	return nil
+}
+
+// Settle implementa pix.Repository.Settle
+func (r *PixRepository) Settle(ctx context.Context, n pkgpix.Notification, settle pix.SettleFunc) (*pix.Charge, error) {
+	if n.EndToEndID == "" {
+		return nil, ErrPixNotificationEmpty
+	}
+
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// A chave primária no endToEndId descarta os reenvios do PSP antes de qualquer efeito
+	result, err := tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.pix_notifications (
+			end_to_end_id, tenant_id, txid, amount, paid_at, payer_info, status, received_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (end_to_end_id) DO NOTHING
+	`, schema), n.EndToEndID, tenantID, nullIfEmpty(n.TxID), n.Amount, n.PaidAt, nullIfEmpty(n.PayerInfo),
+		string(pix.NotificationUnmatched), time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("falha ao registrar notificação Pix: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, pix.ErrNotificationProcessed
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.pix_charges WHERE txid = $1 AND tenant_id = $2 FOR UPDATE
+	`, pixChargeColumns, schema)
+
+	c, err := scanPixCharge(tx.QueryRow(ctx, query, n.TxID, tenantID))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return nil, fmt.Errorf("falha ao buscar cobrança Pix: %w", err)
+		}
+		// Pix sem cobrança correspondente fica registrado para conciliação manual
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("erro ao fazer commit da transação: %w", err)
+		}
+		return nil, ErrPixChargeNotFound
+	}
+
+	rec, payment, settleErr := settle(c)
+	if settleErr != nil {
+		// O valor foi recebido, então a recusa fica registrada em vez de pedir o reenvio ao PSP
+		if err := updatePixNotification(ctx, tx, schema, n.EndToEndID, c.ID, pix.NotificationRejected, settleErr.Error()); err != nil {
+			return nil, err
+		}
+		if err := tx.Commit(ctx); err != nil {
+			return nil, fmt.Errorf("erro ao fazer commit da transação: %w", err)
+		}
+		return c, settleErr
+	}
+
+	if rec != nil && payment != nil {
+		if err := applyReceivablePayment(ctx, tx, schema, tenantID, rec, payment); err != nil {
+			return nil, err
+		}
+	}
+
+	result, err = tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.pix_charges
+		SET status = $1, end_to_end_id = $2, paid_amount = $3, paid_at = $4, updated_at = $5
+		WHERE id = $6 AND tenant_id = $7 AND status = 'active'
+	`, schema), string(c.Status), c.EndToEndID, c.PaidAmount, c.PaidAt, c.UpdatedAt, c.ID, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao liquidar cobrança Pix: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return nil, ErrPixConcurrentUpdate
+	}
+
+	if err := updatePixNotification(ctx, tx, schema, n.EndToEndID, c.ID, pix.NotificationSettled, ""); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return c, nil
+}
+
+// ListNotifications implementa pix.Repository.ListNotifications
+func (r *PixRepository) ListNotifications(ctx context.Context, status pix.NotificationStatus, limit, offset int) ([]*pix.Notification, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+	if status != "" {
+		args = append(args, string(status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT end_to_end_id, COALESCE(txid, ''), charge_id, amount, paid_at, COALESCE(payer_info, ''),
+			status, COALESCE(error, ''), received_at
+		FROM %s.pix_notifications
+		WHERE %s
+		ORDER BY received_at DESC
+		LIMIT $%d OFFSET $%d
+	`, schema, strings.Join(conditions, " AND "), len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar notificações Pix: %w", err)
+	}
+	defer rows.Close()
+
+	notifications := make([]*pix.Notification, 0)
+	for rows.Next() {
+		var n pix.Notification
+		var chargeID pgtype.Text
+		var status string
+		if err := rows.Scan(&n.EndToEndID, &n.TxID, &chargeID, &n.Amount, &n.PaidAt, &n.PayerInfo, &status,
+			&n.Error, &n.ReceivedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler notificação Pix: %w", err)
+		}
+		n.ChargeID = chargeID.String
+		n.Status = pix.NotificationStatus(status)
+		notifications = append(notifications, &n)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar notificações Pix: %w", err)
+	}
+
+	return notifications, nil
+}
+
+// updatePixNotification grava o resultado do processamento de uma notificação
+func updatePixNotification(ctx context.Context, tx pgx.Tx, schema, endToEndID, chargeID string, status pix.NotificationStatus, message string) error {
+	_, err := tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.pix_notifications SET charge_id = $1, status = $2, error = $3
+		WHERE end_to_end_id = $4
+	`, schema), chargeID, string(status), nullIfEmpty(message), endToEndID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar notificação Pix: %w", err)
+	}
+	return nil
+}
+
+// buildPixChargeFilter monta a cláusula WHERE para as consultas de cobranças Pix
+func buildPixChargeFilter(tenantID string, filter pix.ChargeFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("branch_id = $%d", len(args)))
+	}
+	if filter.SaleID != "" {
+		args = append(args, filter.SaleID)
+		conditions = append(conditions, fmt.Sprintf("sale_id = $%d", len(args)))
+	}
+	if filter.ReceivableID != "" {
+		args = append(args, filter.ReceivableID)
+		conditions = append(conditions, fmt.Sprintf("receivable_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanPixCharge lê uma cobrança Pix de uma linha de resultado
+func scanPixCharge(row pgx.Row) (*pix.Charge, error) {
+	var c pix.Charge
+	var kind, status string
+	var saleID, receivableID, customerID, createdBy pgtype.Text
+	var paidAt, expiresAt pgtype.Timestamp
+
+	err := row.Scan(&c.ID, &c.TenantID, &c.BranchID, &kind, &c.TxID, &saleID, &receivableID, &customerID,
+		&c.Amount, &c.Description, &c.BRCode, &c.Location, &c.Provider, &status, &c.EndToEndID, &c.PaidAmount,
+		&paidAt, &expiresAt, &createdBy, &c.CreatedAt, &c.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.Kind = pix.ChargeKind(kind)
+	c.Status = pix.ChargeStatus(status)
+	c.SaleID = saleID.String
+	c.ReceivableID = receivableID.String
+	c.CustomerID = customerID.String
+	c.CreatedBy = createdBy.String
+	if paidAt.Valid {
+		c.PaidAt = &paidAt.Time
+	}
+	if expiresAt.Valid {
+		c.ExpiresAt = &expiresAt.Time
+	}
+	return &c, nil
+}
@@ -0,0 +1,224 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pix"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	pkgpix "github.com/hugohenrick/erp-supermercado/pkg/pix"
+	pkgtenant "github.com/hugohenrick/erp-supermercado/pkg/tenant"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// pixTestTables cria as tabelas de cobranças e notificações Pix como na migração 000013, sem as chaves
+// estrangeiras para filiais, títulos, clientes e usuários
+const pixTestTables = `
+	CREATE TABLE %[1]s.pix_charges (
+		id UUID PRIMARY KEY,
+		tenant_id UUID NOT NULL,
+		branch_id UUID NOT NULL,
+		kind VARCHAR(10) NOT NULL,
+		txid VARCHAR(35) NOT NULL UNIQUE,
+		sale_id UUID,
+		receivable_id UUID,
+		customer_id UUID,
+		amount DECIMAL(15,2) NOT NULL,
+		description VARCHAR(140),
+		br_code TEXT NOT NULL,
+		location VARCHAR(255),
+		provider VARCHAR(20),
+		status VARCHAR(20) NOT NULL DEFAULT 'active',
+		end_to_end_id VARCHAR(32),
+		paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+		paid_at TIMESTAMP,
+		expires_at TIMESTAMP,
+		created_by UUID,
+		created_at TIMESTAMP NOT NULL,
+		updated_at TIMESTAMP NOT NULL
+	);
+	CREATE TABLE %[1]s.pix_notifications (
+		end_to_end_id VARCHAR(32) PRIMARY KEY,
+		tenant_id UUID NOT NULL,
+		txid VARCHAR(35),
+		charge_id UUID REFERENCES %[1]s.pix_charges(id),
+		amount DECIMAL(15,2) NOT NULL,
+		paid_at TIMESTAMP NOT NULL,
+		payer_info TEXT,
+		status VARCHAR(20) NOT NULL,
+		error TEXT,
+		received_at TIMESTAMP NOT NULL
+	);
+`
+
+// setupPixRepository cria um tenant com schema próprio no banco de TEST_DATABASE_URL, que deve ter as
+// migrações públicas aplicadas, e retorna o repositório, o schema e o contexto do tenant. Sem a
+// variável o teste é ignorado
+func setupPixRepository(t *testing.T) (*PixRepository, string, context.Context) {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL não configurado; teste de integração com o PostgreSQL ignorado")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, dsn)
+	if err != nil {
+		t.Fatalf("falha ao conectar ao banco de testes: %v", err)
+	}
+
+	tenantID := uuid.New().String()
+	schema := "test_pix_" + strings.ReplaceAll(tenantID, "-", "")[:16]
+	t.Cleanup(func() {
+		pool.Exec(ctx, "DELETE FROM public.tenants WHERE id = $1", tenantID)
+		pool.Exec(ctx, "DROP SCHEMA IF EXISTS "+schema+" CASCADE")
+		pool.Close()
+	})
+
+	if _, err := pool.Exec(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("falha ao criar schema de testes: %v", err)
+	}
+	if _, err := pool.Exec(ctx, fmt.Sprintf(pixTestTables, schema)); err != nil {
+		t.Fatalf("falha ao criar tabelas de Pix: %v", err)
+	}
+	now := time.Now()
+	_, err = pool.Exec(ctx, `
+		INSERT INTO public.tenants (id, name, document, status, schema, plan_type, max_branches, created_at, updated_at)
+		VALUES ($1, $2, $3, 'active', $4, 'basic', 1, $5, $5)
+	`, tenantID, "Tenant de testes Pix", fmt.Sprintf("%014d", rand.Int63n(1e14)), schema, now)
+	if err != nil {
+		t.Fatalf("falha ao cadastrar tenant de testes: %v", err)
+	}
+
+	return &PixRepository{db: pool}, schema, pkgtenant.SetTenantIDContext(ctx, tenantID)
+}
+
+// createTestCharge grava uma cobrança dinâmica ativa
+func createTestCharge(t *testing.T, ctx context.Context, repo *PixRepository, amount float64) *pix.Charge {
+	t.Helper()
+
+	charge, err := pix.NewCharge(pkgtenant.GetTenantIDFromContext(ctx), uuid.New().String(), pix.KindDynamic, amount, "Venda", "")
+	if err != nil {
+		t.Fatalf("NewCharge() erro inesperado: %v", err)
+	}
+	charge.BRCode = "00020101021226"
+	if err := repo.Create(ctx, charge); err != nil {
+		t.Fatalf("Create() erro inesperado: %v", err)
+	}
+	return charge
+}
+
+// notificationStatus lê a situação gravada da notificação
+func notificationStatus(t *testing.T, ctx context.Context, repo *PixRepository, schema, endToEndID string) pix.NotificationStatus {
+	t.Helper()
+
+	var status string
+	err := repo.db.QueryRow(ctx, fmt.Sprintf("SELECT status FROM %s.pix_notifications WHERE end_to_end_id = $1", schema), endToEndID).Scan(&status)
+	if err != nil {
+		t.Fatalf("falha ao ler notificação %s: %v", endToEndID, err)
+	}
+	return pix.NotificationStatus(status)
+}
+
+func TestPixRepositorySettleIdempotency(t *testing.T) {
+	repo, schema, ctx := setupPixRepository(t)
+
+	charge := createTestCharge(t, ctx, repo, 30)
+	paid := pkgpix.Notification{EndToEndID: "E0000000020250310120000000000001", TxID: charge.TxID, Amount: 30, PaidAt: time.Now()}
+	unknown := pkgpix.Notification{EndToEndID: "E0000000020250310120000000000002", TxID: "txidsemcobranca", Amount: 5, PaidAt: time.Now()}
+
+	settled := 0
+	settle := func(c *pix.Charge) (*receivable.Receivable, *receivable.Payment, error) {
+		settled++
+		return nil, nil, c.Pay(paid)
+	}
+
+	tests := []struct {
+		name         string
+		notification pkgpix.Notification
+		wantErr      error
+		wantStatus   pix.NotificationStatus
+	}{
+		{"primeira entrega liquida", paid, nil, pix.NotificationSettled},
+		{"reenvio descartado pelo endToEndId", paid, pix.ErrNotificationProcessed, pix.NotificationSettled},
+		{"terceira entrega continua descartada", paid, pix.ErrNotificationProcessed, pix.NotificationSettled},
+		{"txid sem cobrança fica para conciliação", unknown, ErrPixChargeNotFound, pix.NotificationUnmatched},
+		{"reenvio sem cobrança também é descartado", unknown, pix.ErrNotificationProcessed, pix.NotificationUnmatched},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := repo.Settle(ctx, tt.notification, settle)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Settle() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if got := notificationStatus(t, ctx, repo, schema, tt.notification.EndToEndID); got != tt.wantStatus {
+				t.Errorf("notificação = %s, esperado %s", got, tt.wantStatus)
+			}
+		})
+	}
+
+	if settled != 1 {
+		t.Errorf("liquidação executada %d vezes, esperado 1", settled)
+	}
+	stored, err := repo.FindByID(ctx, charge.ID)
+	if err != nil {
+		t.Fatalf("FindByID() erro inesperado: %v", err)
+	}
+	if stored.Status != pix.ChargePaid || stored.PaidAmount != 30 || stored.EndToEndID != paid.EndToEndID {
+		t.Errorf("cobrança = %s %.2f %s, esperado paga com 30.00 por %s", stored.Status, stored.PaidAmount, stored.EndToEndID, paid.EndToEndID)
+	}
+}
+
+func TestPixRepositorySettleConcurrentDeliveries(t *testing.T) {
+	repo, _, ctx := setupPixRepository(t)
+
+	charge := createTestCharge(t, ctx, repo, 12.5)
+	n := pkgpix.Notification{EndToEndID: "E0000000020250310120000000000003", TxID: charge.TxID, Amount: 12.5, PaidAt: time.Now()}
+
+	// Entregas simultâneas do mesmo Pix: o INSERT das demais espera a primeira transação e cai no ON CONFLICT
+	const deliveries = 8
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		settled  int
+		outcomes = make(map[string]int)
+	)
+	for i := 0; i < deliveries; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := repo.Settle(ctx, n, func(c *pix.Charge) (*receivable.Receivable, *receivable.Payment, error) {
+				mu.Lock()
+				settled++
+				mu.Unlock()
+				return nil, nil, c.Pay(n)
+			})
+
+			mu.Lock()
+			defer mu.Unlock()
+			switch {
+			case err == nil:
+				outcomes["liquidada"]++
+			case errors.Is(err, pix.ErrNotificationProcessed):
+				outcomes["descartada"]++
+			default:
+				outcomes[err.Error()]++
+			}
+		}()
+	}
+	wg.Wait()
+
+	if settled != 1 || outcomes["liquidada"] != 1 || outcomes["descartada"] != deliveries-1 {
+		t.Errorf("entregas = %v com %d liquidações, esperado 1 liquidada e %d descartadas", outcomes, settled, deliveries-1)
+	}
+}
@@ -0,0 +1,44 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/jackc/pgx/v5"
+)
+
+// ErrPlanLimitExceeded ocorre quando o cadastro ultrapassaria um limite do plano do tenant
+var ErrPlanLimitExceeded = tenant.ErrPlanLimitExceeded
+
+// lockTenantPlan retorna o plano do tenant e bloqueia o seu cadastro até o fim da transação, de
+// forma que cadastros simultâneos do mesmo tenant não ultrapassem os limites do plano
+func lockTenantPlan(ctx context.Context, tx pgx.Tx, tenantID string) (tenant.Plan, error) {
+	var planType string
+	err := tx.QueryRow(ctx, "SELECT plan_type FROM public.tenants WHERE id = $1 FOR NO KEY UPDATE", tenantID).Scan(&planType)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return tenant.Plan{}, ErrTenantNotFound
+		}
+		return tenant.Plan{}, fmt.Errorf("erro ao obter plano do tenant: %w", err)
+	}
+	return tenant.PlanFor(planType), nil
+}
+
+// checkPlanLimit conta os registros com a query e retorna um tenant.LimitError se o limite já foi
+// atingido. Limite zero é ilimitado
+func checkPlanLimit(ctx context.Context, tx pgx.Tx, limit int, resource, query string, args ...any) error {
+	if limit <= 0 {
+		return nil
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return fmt.Errorf("erro ao contar %s: %w", resource, err)
+	}
+	if count >= limit {
+		return tenant.NewLimitError(resource, limit)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/platform"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrOperatorNotFound   = errors.New("operador não encontrado")
+	ErrOperatorDuplicated = errors.New("já existe operador com esse email")
+)
+
+// operatorColumns são as colunas lidas por scanOperator, na mesma ordem
+const operatorColumns = "id, name, email, password, status, last_login_at, created_at, updated_at"
+
+// PlatformRepository implementa a interface platform.Repository
+type PlatformRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPlatformRepository cria uma nova instância de PlatformRepository
+func NewPlatformRepository(db *pgxpool.Pool) platform.Repository {
+	return &PlatformRepository{
+		db: db,
+	}
+}
+
+// Create implementa platform.Repository.Create
+func (r *PlatformRepository) Create(ctx context.Context, o *platform.Operator) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO platform_operators (id, name, email, password, status, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		o.ID, o.Name, o.Email, o.Password, string(o.Status), o.CreatedAt, o.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrOperatorDuplicated
+		}
+		return fmt.Errorf("falha ao criar operador: %w", err)
+	}
+	return nil
+}
+
+// FindByID implementa platform.Repository.FindByID
+func (r *PlatformRepository) FindByID(ctx context.Context, id string) (*platform.Operator, error) {
+	query := fmt.Sprintf("SELECT %s FROM platform_operators WHERE id = $1", operatorColumns)
+	return r.findOne(ctx, query, id)
+}
+
+// FindByEmail implementa platform.Repository.FindByEmail
+func (r *PlatformRepository) FindByEmail(ctx context.Context, email string) (*platform.Operator, error) {
+	query := fmt.Sprintf("SELECT %s FROM platform_operators WHERE lower(email) = lower($1)", operatorColumns)
+	return r.findOne(ctx, query, email)
+}
+
+// List implementa platform.Repository.List
+func (r *PlatformRepository) List(ctx context.Context) ([]*platform.Operator, error) {
+	query := fmt.Sprintf("SELECT %s FROM platform_operators ORDER BY name", operatorColumns)
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar operadores: %w", err)
+	}
+	defer rows.Close()
+
+	var operators []*platform.Operator
+	for rows.Next() {
+		o, err := scanOperator(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler operador: %w", err)
+		}
+		operators = append(operators, o)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao listar operadores: %w", err)
+	}
+	return operators, nil
+}
+
+// UpdateStatus implementa platform.Repository.UpdateStatus
+func (r *PlatformRepository) UpdateStatus(ctx context.Context, id string, status platform.Status) error {
+	result, err := r.db.Exec(ctx, "UPDATE platform_operators SET status = $1, updated_at = $2 WHERE id = $3",
+		string(status), time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar status do operador: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrOperatorNotFound
+	}
+	return nil
+}
+
+// UpdateLastLogin implementa platform.Repository.UpdateLastLogin
+func (r *PlatformRepository) UpdateLastLogin(ctx context.Context, id string) error {
+	result, err := r.db.Exec(ctx, "UPDATE platform_operators SET last_login_at = $1 WHERE id = $2", time.Now(), id)
+	if err != nil {
+		return fmt.Errorf("falha ao registrar login do operador: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrOperatorNotFound
+	}
+	return nil
+}
+
+// findOne busca um único operador
+func (r *PlatformRepository) findOne(ctx context.Context, query string, args ...any) (*platform.Operator, error) {
+	o, err := scanOperator(r.db.QueryRow(ctx, query, args...))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrOperatorNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar operador: %w", err)
+	}
+	return o, nil
+}
+
+// scanOperator lê um operador selecionado com operatorColumns
+func scanOperator(row pgx.Row) (*platform.Operator, error) {
+	var o platform.Operator
+	var status string
+	if err := row.Scan(&o.ID, &o.Name, &o.Email, &o.Password, &status, &o.LastLoginAt, &o.CreatedAt, &o.UpdatedAt); err != nil {
+		return nil, err
+	}
+	o.Status = platform.Status(status)
+	return &o, nil
+}
@@ -0,0 +1,455 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/pricetable"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrPriceTableNotFound   = errors.New("tabela de preços não encontrada")
+	ErrPriceTableInUse      = errors.New("tabela de preços vinculada a clientes")
+	ErrPriceProductNotFound = errors.New("produto não encontrado ou inativo")
+)
+
+// PriceTableRepository implementa a interface pricetable.Repository
+type PriceTableRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPriceTableRepository cria uma nova instância de PriceTableRepository
+func NewPriceTableRepository(db *pgxpool.Pool) pricetable.Repository {
+	return &PriceTableRepository{
+		db: db,
+	}
+}
+
+const priceTableColumns = `id, tenant_id, name, percentage, branch_ids, customer_types, valid_from, valid_until,
+	active, created_at, updated_at`
+
+// Create implementa pricetable.Repository.Create
+func (r *PriceTableRepository) Create(ctx context.Context, t *pricetable.PriceTable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	t.TenantID = tenantID
+
+	branchIDs, customerTypes, err := marshalPriceTable(t)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.price_tables (
+			id, tenant_id, name, percentage, branch_ids, customer_types, valid_from, valid_until, active,
+			created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, schema)
+
+	_, err = tx.Exec(ctx, query, t.ID, t.TenantID, t.Name, t.Percentage, branchIDs, customerTypes, t.ValidFrom,
+		t.ValidUntil, t.Active, t.CreatedAt, t.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao criar tabela de preços: %w", err)
+	}
+
+	if err := insertPriceTableItems(ctx, tx, schema, t); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// Update implementa pricetable.Repository.Update
+func (r *PriceTableRepository) Update(ctx context.Context, t *pricetable.PriceTable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	branchIDs, customerTypes, err := marshalPriceTable(t)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	query := fmt.Sprintf(`
+		UPDATE %s.price_tables
+		SET name = $1, percentage = $2, branch_ids = $3, customer_types = $4, valid_from = $5, valid_until = $6,
+			active = $7, updated_at = $8
+		WHERE id = $9 AND tenant_id = $10
+	`, schema)
+
+	result, err := tx.Exec(ctx, query, t.Name, t.Percentage, branchIDs, customerTypes, t.ValidFrom, t.ValidUntil,
+		t.Active, t.UpdatedAt, t.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar tabela de preços: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPriceTableNotFound
+	}
+
+	// Os preços fixos são sempre enviados completos e substituem os anteriores
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s.price_table_items WHERE price_table_id = $1", schema), t.ID); err != nil {
+		return fmt.Errorf("falha ao remover preços da tabela: %w", err)
+	}
+
+	if err := insertPriceTableItems(ctx, tx, schema, t); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+
+	return nil
+}
+
+// Delete implementa pricetable.Repository.Delete
+func (r *PriceTableRepository) Delete(ctx context.Context, id string) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	var inUse bool
+	query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.customers WHERE price_table_id = $1 AND tenant_id = $2)", schema)
+	if err := conn.QueryRow(ctx, query, id, tenantID).Scan(&inUse); err != nil {
+		return fmt.Errorf("falha ao verificar clientes da tabela de preços: %w", err)
+	}
+	if inUse {
+		return ErrPriceTableInUse
+	}
+
+	result, err := conn.Exec(ctx, fmt.Sprintf("DELETE FROM %s.price_tables WHERE id = $1 AND tenant_id = $2", schema), id, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao excluir tabela de preços: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrPriceTableNotFound
+	}
+
+	return nil
+}
+
+// FindByID implementa pricetable.Repository.FindByID
+func (r *PriceTableRepository) FindByID(ctx context.Context, id string) (*pricetable.PriceTable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.price_tables WHERE id = $1 AND tenant_id = $2", priceTableColumns, schema)
+
+	t, err := scanPriceTable(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPriceTableNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar tabela de preços: %w", err)
+	}
+
+	if t.Items, err = loadPriceTableItems(ctx, conn, schema, t.ID); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// List implementa pricetable.Repository.List
+func (r *PriceTableRepository) List(ctx context.Context, filter pricetable.Filter, limit, offset int) ([]*pricetable.PriceTable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildPriceTableFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.price_tables
+		WHERE %s
+		ORDER BY name
+		LIMIT $%d OFFSET $%d
+	`, priceTableColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar tabelas de preços: %w", err)
+	}
+	defer rows.Close()
+
+	tables := make([]*pricetable.PriceTable, 0)
+	for rows.Next() {
+		t, err := scanPriceTable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler tabela de preços: %w", err)
+		}
+		tables = append(tables, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar tabelas de preços: %w", err)
+	}
+
+	return tables, nil
+}
+
+// Count implementa pricetable.Repository.Count
+func (r *PriceTableRepository) Count(ctx context.Context, filter pricetable.Filter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildPriceTableFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.price_tables WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar tabelas de preços: %w", err)
+	}
+
+	return count, nil
+}
+
+// FindForCustomer implementa pricetable.Repository.FindForCustomer
+func (r *PriceTableRepository) FindForCustomer(ctx context.Context, c *customer.Customer, branchID string, at time.Time) (*pricetable.PriceTable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	// A tabela própria do cliente prevalece sobre a tabela padrão do tipo de cliente
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.price_tables
+		WHERE tenant_id = $1 AND active = true AND valid_from <= $2 AND (valid_until IS NULL OR valid_until > $2)
+			AND (branch_ids = '[]'::jsonb OR branch_ids ? $3)
+			AND (id::text = $4 OR customer_types ? $5)
+		ORDER BY (id::text = $4) DESC, created_at, id
+		LIMIT 1
+	`, priceTableColumns, schema)
+
+	t, err := scanPriceTable(conn.QueryRow(ctx, query, tenantID, at, branchID, c.PriceTableID, string(c.CustomerType)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("falha ao buscar tabela de preços do cliente: %w", err)
+	}
+
+	if t.Items, err = loadPriceTableItems(ctx, conn, schema, t.ID); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// BasePrices implementa pricetable.Repository.BasePrices
+func (r *PriceTableRepository) BasePrices(ctx context.Context, productIDs []string) (map[string]float64, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, sell_price FROM %s.products
+		WHERE id::text = ANY($1) AND tenant_id = $2 AND active = true
+	`, schema)
+
+	rows, err := conn.Query(ctx, query, productIDs, tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar preços dos produtos: %w", err)
+	}
+	defer rows.Close()
+
+	prices := make(map[string]float64, len(productIDs))
+	for rows.Next() {
+		var id string
+		var price float64
+		if err := rows.Scan(&id, &price); err != nil {
+			return nil, fmt.Errorf("falha ao ler preço do produto: %w", err)
+		}
+		prices[id] = price
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar preços dos produtos: %w", err)
+	}
+
+	for _, id := range productIDs {
+		if _, ok := prices[id]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrPriceProductNotFound, id)
+		}
+	}
+
+	return prices, nil
+}
+
+// insertPriceTableItems grava os preços fixos da tabela
+func insertPriceTableItems(ctx context.Context, tx pgx.Tx, schema string, t *pricetable.PriceTable) error {
+	query := fmt.Sprintf("INSERT INTO %s.price_table_items (price_table_id, product_id, price) VALUES ($1, $2, $3)", schema)
+	for _, item := range t.Items {
+		if _, err := tx.Exec(ctx, query, t.ID, item.ProductID, item.Price); err != nil {
+			return fmt.Errorf("falha ao gravar preço do produto %s: %w", item.ProductID, err)
+		}
+	}
+	return nil
+}
+
+// loadPriceTableItems carrega os preços fixos da tabela
+func loadPriceTableItems(ctx context.Context, conn *pgxpool.Conn, schema, priceTableID string) ([]pricetable.Item, error) {
+	query := fmt.Sprintf("SELECT product_id, price FROM %s.price_table_items WHERE price_table_id = $1 ORDER BY product_id", schema)
+	rows, err := conn.Query(ctx, query, priceTableID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar preços da tabela: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]pricetable.Item, 0)
+	for rows.Next() {
+		var item pricetable.Item
+		if err := rows.Scan(&item.ProductID, &item.Price); err != nil {
+			return nil, fmt.Errorf("falha ao ler preço da tabela: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar preços da tabela: %w", err)
+	}
+
+	return items, nil
+}
+
+// buildPriceTableFilter monta a cláusula WHERE para as consultas de tabelas de preços
+func buildPriceTableFilter(tenantID string, filter pricetable.Filter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("(branch_ids = '[]'::jsonb OR branch_ids ? $%d)", len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// marshalPriceTable serializa as listas da tabela gravadas em colunas JSONB
+func marshalPriceTable(t *pricetable.PriceTable) ([]byte, []byte, error) {
+	branchIDs, err := json.Marshal(nonNilStrings(t.BranchIDs))
+	if err != nil {
+		return nil, nil, fmt.Errorf("falha ao serializar filiais da tabela de preços: %w", err)
+	}
+	customerTypes := t.CustomerTypes
+	if customerTypes == nil {
+		customerTypes = []customer.CustomerType{}
+	}
+	typesJSON, err := json.Marshal(customerTypes)
+	if err != nil {
+		return nil, nil, fmt.Errorf("falha ao serializar tipos de cliente da tabela de preços: %w", err)
+	}
+	return branchIDs, typesJSON, nil
+}
+
+// scanPriceTable lê uma tabela de preços de uma linha de resultado
+func scanPriceTable(row pgx.Row) (*pricetable.PriceTable, error) {
+	var t pricetable.PriceTable
+	var branchIDs, customerTypes []byte
+	var validUntil pgtype.Timestamp
+
+	err := row.Scan(&t.ID, &t.TenantID, &t.Name, &t.Percentage, &branchIDs, &customerTypes, &t.ValidFrom,
+		&validUntil, &t.Active, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	if validUntil.Valid {
+		t.ValidUntil = &validUntil.Time
+	}
+	if err := json.Unmarshal(branchIDs, &t.BranchIDs); err != nil {
+		return nil, fmt.Errorf("falha ao ler filiais da tabela de preços: %w", err)
+	}
+	if err := json.Unmarshal(customerTypes, &t.CustomerTypes); err != nil {
+		return nil, fmt.Errorf("falha ao ler tipos de cliente da tabela de preços: %w", err)
+	}
+	t.Items = []pricetable.Item{}
+
+	return &t, nil
+}
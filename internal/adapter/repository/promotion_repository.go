@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/promotion"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ErrPromotionNotFound ocorre quando a promoção não existe no tenant
+var ErrPromotionNotFound = errors.New("promoção não encontrada")
+
+// PromotionRepository implementa a interface promotion.Repository
+type PromotionRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewPromotionRepository cria uma nova instância de PromotionRepository
+func NewPromotionRepository(db *pgxpool.Pool) promotion.Repository {
+	return &PromotionRepository{
+		db: db,
+	}
+}
+
+const promotionColumns = `id, tenant_id, name, type, branch_ids, product_ids, take_quantity, pay_quantity, tiers,
+	combo_items, combo_price, price, members_only, priority, starts_at, ends_at, active, created_at, updated_at`
+
+// Create implementa promotion.Repository.Create
+func (r *PromotionRepository) Create(ctx context.Context, p *promotion.Promotion) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	p.TenantID = tenantID
+
+	branchIDs, productIDs, tiers, comboItems, err := marshalPromotion(p)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.promotions (
+			id, tenant_id, name, type, branch_ids, product_ids, take_quantity, pay_quantity, tiers, combo_items,
+			combo_price, price, members_only, priority, starts_at, ends_at, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, p.ID, p.TenantID, p.Name, string(p.Type), branchIDs, productIDs, p.TakeQuantity,
+		p.PayQuantity, tiers, comboItems, p.ComboPrice, p.Price, p.MembersOnly, p.Priority, p.StartsAt, p.EndsAt,
+		p.Active, p.CreatedAt, p.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao criar promoção: %w", err)
+	}
+
+	return nil
+}
+
+// Update implementa promotion.Repository.Update
+func (r *PromotionRepository) Update(ctx context.Context, p *promotion.Promotion) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	branchIDs, productIDs, tiers, comboItems, err := marshalPromotion(p)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.promotions
+		SET name = $1, type = $2, branch_ids = $3, product_ids = $4, take_quantity = $5, pay_quantity = $6,
+			tiers = $7, combo_items = $8, combo_price = $9, price = $10, members_only = $11, priority = $12,
+			starts_at = $13, ends_at = $14, active = $15, updated_at = $16
+		WHERE id = $17 AND tenant_id = $18
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, p.Name, string(p.Type), branchIDs, productIDs, p.TakeQuantity,
+		p.PayQuantity, tiers, comboItems, p.ComboPrice, p.Price, p.MembersOnly, p.Priority, p.StartsAt, p.EndsAt,
+		p.Active, p.UpdatedAt, p.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar promoção: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrPromotionNotFound
+	}
+
+	return nil
+}
+
+// FindByID implementa promotion.Repository.FindByID
+func (r *PromotionRepository) FindByID(ctx context.Context, id string) (*promotion.Promotion, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.promotions WHERE id = $1 AND tenant_id = $2", promotionColumns, schema)
+
+	p, err := scanPromotion(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrPromotionNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar promoção: %w", err)
+	}
+
+	return p, nil
+}
+
+// List implementa promotion.Repository.List
+func (r *PromotionRepository) List(ctx context.Context, filter promotion.Filter, limit, offset int) ([]*promotion.Promotion, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildPromotionFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.promotions
+		WHERE %s
+		ORDER BY starts_at DESC, name
+		LIMIT $%d OFFSET $%d
+	`, promotionColumns, schema, where, len(args)-1, len(args))
+
+	return r.queryPromotions(ctx, conn, query, args...)
+}
+
+// Count implementa promotion.Repository.Count
+func (r *PromotionRepository) Count(ctx context.Context, filter promotion.Filter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildPromotionFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.promotions WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar promoções: %w", err)
+	}
+
+	return count, nil
+}
+
+// ListApplicable implementa promotion.Repository.ListApplicable
+func (r *PromotionRepository) ListApplicable(ctx context.Context, branchID string, at time.Time) ([]*promotion.Promotion, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.promotions
+		WHERE tenant_id = $1 AND active = true AND starts_at <= $2 AND ends_at > $2
+			AND (branch_ids = '[]'::jsonb OR branch_ids ? $3)
+		ORDER BY priority DESC, id
+	`, promotionColumns, schema)
+
+	return r.queryPromotions(ctx, conn, query, tenantID, at, branchID)
+}
+
+// queryPromotions executa a consulta e lê as promoções retornadas
+func (r *PromotionRepository) queryPromotions(ctx context.Context, conn *pgxpool.Conn, query string, args ...interface{}) ([]*promotion.Promotion, error) {
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar promoções: %w", err)
+	}
+	defer rows.Close()
+
+	promotions := make([]*promotion.Promotion, 0)
+	for rows.Next() {
+		p, err := scanPromotion(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler promoção: %w", err)
+		}
+		promotions = append(promotions, p)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar promoções: %w", err)
+	}
+
+	return promotions, nil
+}
+
+// buildPromotionFilter monta a cláusula WHERE para as consultas de promoções
+func buildPromotionFilter(tenantID string, filter promotion.Filter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("(branch_ids = '[]'::jsonb OR branch_ids ? $%d)", len(args)))
+	}
+	if filter.Type != "" {
+		args = append(args, string(filter.Type))
+		conditions = append(conditions, fmt.Sprintf("type = $%d", len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// marshalPromotion serializa as listas da promoção gravadas em colunas JSONB
+func marshalPromotion(p *promotion.Promotion) ([]byte, []byte, []byte, []byte, error) {
+	branchIDs, err := json.Marshal(nonNilStrings(p.BranchIDs))
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("falha ao serializar filiais da promoção: %w", err)
+	}
+	productIDs, err := json.Marshal(nonNilStrings(p.ProductIDs))
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("falha ao serializar produtos da promoção: %w", err)
+	}
+	tiers := p.Tiers
+	if tiers == nil {
+		tiers = []promotion.Tier{}
+	}
+	tiersJSON, err := json.Marshal(tiers)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("falha ao serializar faixas da promoção: %w", err)
+	}
+	comboItems := p.ComboItems
+	if comboItems == nil {
+		comboItems = []promotion.ComboItem{}
+	}
+	comboJSON, err := json.Marshal(comboItems)
+	if err != nil {
+		return nil, nil, nil, nil, fmt.Errorf("falha ao serializar combo da promoção: %w", err)
+	}
+	return branchIDs, productIDs, tiersJSON, comboJSON, nil
+}
+
+// nonNilStrings garante que listas vazias sejam gravadas como [] e não como null
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
+
+// scanPromotion lê uma promoção de uma linha de resultado
+func scanPromotion(row pgx.Row) (*promotion.Promotion, error) {
+	var p promotion.Promotion
+	var promotionType string
+	var branchIDs, productIDs, tiers, comboItems []byte
+
+	err := row.Scan(&p.ID, &p.TenantID, &p.Name, &promotionType, &branchIDs, &productIDs, &p.TakeQuantity,
+		&p.PayQuantity, &tiers, &comboItems, &p.ComboPrice, &p.Price, &p.MembersOnly, &p.Priority, &p.StartsAt,
+		&p.EndsAt, &p.Active, &p.CreatedAt, &p.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	p.Type = promotion.Type(promotionType)
+	if err := json.Unmarshal(branchIDs, &p.BranchIDs); err != nil {
+		return nil, fmt.Errorf("falha ao ler filiais da promoção: %w", err)
+	}
+	if err := json.Unmarshal(productIDs, &p.ProductIDs); err != nil {
+		return nil, fmt.Errorf("falha ao ler produtos da promoção: %w", err)
+	}
+	if err := json.Unmarshal(tiers, &p.Tiers); err != nil {
+		return nil, fmt.Errorf("falha ao ler faixas da promoção: %w", err)
+	}
+	if err := json.Unmarshal(comboItems, &p.ComboItems); err != nil {
+		return nil, fmt.Errorf("falha ao ler combo da promoção: %w", err)
+	}
+
+	return &p, nil
+}
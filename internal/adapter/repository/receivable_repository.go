@@ -0,0 +1,638 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de contas a receber
+var (
+	ErrReceivableNotFound         = errors.New("título a receber não encontrado")
+	ErrReceivableConcurrentUpdate = errors.New("título foi alterado por outra operação, tente novamente")
+	ErrSaleAlreadyGenerated       = errors.New("os títulos desta venda já foram gerados")
+)
+
+// ReceivableRepository implementa a interface receivable.Repository
+type ReceivableRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewReceivableRepository cria uma nova instância de ReceivableRepository
+func NewReceivableRepository(db *pgxpool.Pool) receivable.Repository {
+	return &ReceivableRepository{
+		db: db,
+	}
+}
+
+// receivableColumns lista as colunas lidas da tabela de títulos a receber
+const receivableColumns = `id, tenant_id, branch_id, customer_id, sale_id, COALESCE(document_number, ''),
+	COALESCE(description, ''), installment, installments, issue_date, due_date, amount, received_amount,
+	interest, fine, discount, status, COALESCE(cancel_reason, ''), created_by, created_at, updated_at`
+
+// Create implementa receivable.Repository.Create
+func (r *ReceivableRepository) Create(ctx context.Context, receivables ...*receivable.Receivable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := insertReceivables(ctx, tx, schema, tenantID, receivables); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// CreateForSale implementa receivable.Repository.CreateForSale
+func (r *ReceivableRepository) CreateForSale(ctx context.Context, customerID string, check receivable.CreditCheck, receivables ...*receivable.Receivable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Bloquear o cliente para que vendas simultâneas não ultrapassem o limite; a situação é relida aqui
+	// para que um bloqueio gravado depois da consulta do cliente não seja ignorado
+	var status string
+	err = tx.QueryRow(ctx, fmt.Sprintf("SELECT status::text FROM %s.customers WHERE id = $1 FOR UPDATE", schema), customerID).Scan(&status)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrCustomerNotFound
+		}
+		return fmt.Errorf("falha ao bloquear cliente: %w", err)
+	}
+
+	settings, err := receivableSettings(ctx, tx, schema, tenantID)
+	if err != nil {
+		return err
+	}
+
+	balance, err := customerBalance(ctx, tx, schema, tenantID, customerID)
+	if err != nil {
+		return err
+	}
+
+	if err := check(customer.Status(status), balance, settings); err != nil {
+		return err
+	}
+
+	if err := insertReceivables(ctx, tx, schema, tenantID, receivables); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID implementa receivable.Repository.FindByID
+func (r *ReceivableRepository) FindByID(ctx context.Context, id string) (*receivable.Receivable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.receivables WHERE id = $1 AND tenant_id = $2", receivableColumns, schema)
+
+	rec, err := scanReceivable(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrReceivableNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar título a receber: %w", err)
+	}
+
+	paymentsQuery := fmt.Sprintf(`
+		SELECT id, receivable_id, paid_at, amount, interest, fine, discount, total,
+			COALESCE(method, ''), COALESCE(notes, ''), created_by, created_at
+		FROM %s.receivable_payments
+		WHERE receivable_id = $1
+		ORDER BY paid_at, created_at
+	`, schema)
+
+	rows, err := conn.Query(ctx, paymentsQuery, rec.ID)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao buscar recebimentos do título: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var payment receivable.Payment
+		var createdBy pgtype.Text
+		if err := rows.Scan(&payment.ID, &payment.ReceivableID, &payment.PaidAt, &payment.Amount,
+			&payment.Interest, &payment.Fine, &payment.Discount, &payment.Total, &payment.Method,
+			&payment.Notes, &createdBy, &payment.CreatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler recebimento do título: %w", err)
+		}
+		payment.CreatedBy = createdBy.String
+		rec.Payments = append(rec.Payments, payment)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar recebimentos do título: %w", err)
+	}
+
+	return rec, nil
+}
+
+// List implementa receivable.Repository.List
+func (r *ReceivableRepository) List(ctx context.Context, filter receivable.ListFilter, limit, offset int) ([]*receivable.Receivable, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildReceivableFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.receivables
+		WHERE %s
+		ORDER BY due_date, installment
+		LIMIT $%d OFFSET $%d
+	`, receivableColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar títulos a receber: %w", err)
+	}
+	defer rows.Close()
+
+	receivables := make([]*receivable.Receivable, 0)
+	for rows.Next() {
+		rec, err := scanReceivable(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler título a receber: %w", err)
+		}
+		receivables = append(receivables, rec)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar títulos a receber: %w", err)
+	}
+
+	return receivables, nil
+}
+
+// Count implementa receivable.Repository.Count
+func (r *ReceivableRepository) Count(ctx context.Context, filter receivable.ListFilter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildReceivableFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.receivables WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar títulos a receber: %w", err)
+	}
+
+	return count, nil
+}
+
+// RegisterPayment implementa receivable.Repository.RegisterPayment
+func (r *ReceivableRepository) RegisterPayment(ctx context.Context, rec *receivable.Receivable, payment *receivable.Payment) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if err := applyReceivablePayment(ctx, tx, schema, tenantID, rec, payment); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao fazer commit da transação: %w", err)
+	}
+
+	return nil
+}
+
+// Cancel implementa receivable.Repository.Cancel
+func (r *ReceivableRepository) Cancel(ctx context.Context, rec *receivable.Receivable) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.receivables SET status = $1, cancel_reason = $2, updated_at = $3
+		WHERE id = $4 AND tenant_id = $5 AND status = 'open' AND received_amount = 0
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, string(rec.Status), rec.CancelReason, rec.UpdatedAt, rec.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao cancelar título a receber: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrReceivableConcurrentUpdate
+	}
+
+	return nil
+}
+
+// CustomerBalance implementa receivable.Repository.CustomerBalance
+func (r *ReceivableRepository) CustomerBalance(ctx context.Context, customerID string) (*receivable.CustomerBalance, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return customerBalance(ctx, conn, schema, tenantID, customerID)
+}
+
+// BlockOverdueCustomers implementa receivable.Repository.BlockOverdueCustomers
+func (r *ReceivableRepository) BlockOverdueCustomers(ctx context.Context, days int) ([]string, error) {
+	tenantID, schema, err := resolveTenantSchema(ctx, r.db)
+	if err != nil {
+		return nil, err
+	}
+	return blockOverdueCustomers(ctx, r.db, schema, tenantID, days, "")
+}
+
+// BlockOverdueCustomer implementa receivable.Repository.BlockOverdueCustomer
+func (r *ReceivableRepository) BlockOverdueCustomer(ctx context.Context, customerID string, days int) (bool, error) {
+	tenantID, schema, err := resolveTenantSchema(ctx, r.db)
+	if err != nil {
+		return false, err
+	}
+	blocked, err := blockOverdueCustomers(ctx, r.db, schema, tenantID, days, customerID)
+	if err != nil {
+		return false, err
+	}
+	return len(blocked) > 0, nil
+}
+
+// BlockOverdueAll implementa receivable.Repository.BlockOverdueAll
+func (r *ReceivableRepository) BlockOverdueAll(ctx context.Context) (int, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id FROM public.tenants
+		WHERE deleted_at IS NULL AND LOWER(status) = 'active'
+		ORDER BY id`)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar tenants: %w", err)
+	}
+	tenantIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return 0, fmt.Errorf("erro ao listar tenants: %w", err)
+	}
+
+	var (
+		total int
+		errs  []error
+	)
+	for _, tenantID := range tenantIDs {
+		schema, err := database.ResolveTenantSchema(ctx, r.db, tenantID)
+		if err == nil {
+			var settings *receivable.Settings
+			if settings, err = receivableSettings(ctx, r.db, schema, tenantID); err == nil {
+				var blocked []string
+				blocked, err = blockOverdueCustomers(ctx, r.db, schema, tenantID, settings.BlockAfterDays, "")
+				total += len(blocked)
+			}
+		}
+		if err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenantID, err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// FindSettings implementa receivable.Repository.FindSettings
+func (r *ReceivableRepository) FindSettings(ctx context.Context) (*receivable.Settings, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	return receivableSettings(ctx, conn, schema, tenantID)
+}
+
+// SaveSettings implementa receivable.Repository.SaveSettings
+func (r *ReceivableRepository) SaveSettings(ctx context.Context, s *receivable.Settings) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	s.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.receivable_settings (tenant_id, block_after_days, updated_at)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (tenant_id) DO UPDATE SET
+			block_after_days = EXCLUDED.block_after_days, updated_at = EXCLUDED.updated_at
+	`, schema)
+
+	if _, err := conn.Exec(ctx, query, s.TenantID, s.BlockAfterDays, s.UpdatedAt); err != nil {
+		return fmt.Errorf("falha ao salvar configuração de contas a receber: %w", err)
+	}
+
+	return nil
+}
+
+// receivableSettings busca a configuração de contas a receber do tenant usando a conexão ou transação
+// informada. Sem configuração gravada, retorna a padrão
+func receivableSettings(ctx context.Context, q rowQuerier, schema, tenantID string) (*receivable.Settings, error) {
+	query := fmt.Sprintf(`
+		SELECT tenant_id, block_after_days, updated_at
+		FROM %s.receivable_settings
+		WHERE tenant_id = $1
+	`, schema)
+
+	var s receivable.Settings
+	err := q.QueryRow(ctx, query, tenantID).Scan(&s.TenantID, &s.BlockAfterDays, &s.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return receivable.DefaultSettings(tenantID), nil
+		}
+		return nil, fmt.Errorf("falha ao buscar configuração de contas a receber: %w", err)
+	}
+
+	return &s, nil
+}
+
+// blockOverdueCustomers bloqueia os clientes do tenant com títulos vencidos há mais de days dias,
+// opcionalmente restrito a um cliente, e retorna os IDs dos clientes bloqueados
+func blockOverdueCustomers(ctx context.Context, db *pgxpool.Pool, schema, tenantID string, days int, customerID string) ([]string, error) {
+	// O status do cliente é um enum em maiúsculas no schema do tenant
+	query := fmt.Sprintf(`
+		UPDATE %s.customers c SET status = 'BLOCKED', updated_at = $1
+		WHERE c.tenant_id = $2 AND c.status <> 'BLOCKED'
+		AND ($4::uuid IS NULL OR c.id = $4::uuid)
+		AND EXISTS (
+			SELECT 1 FROM %s.receivables r
+			WHERE r.customer_id = c.id AND r.status IN ('open', 'partial')
+			AND r.due_date < CURRENT_DATE - $3::int
+		)
+		RETURNING c.id
+	`, schema, schema)
+
+	rows, err := db.Query(ctx, query, time.Now(), tenantID, days, nullIfEmpty(customerID))
+	if err != nil {
+		return nil, fmt.Errorf("falha ao bloquear clientes inadimplentes: %w", err)
+	}
+	defer rows.Close()
+
+	blocked := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("falha ao ler cliente bloqueado: %w", err)
+		}
+		blocked = append(blocked, id)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar clientes bloqueados: %w", err)
+	}
+
+	return blocked, nil
+}
+
+// customerBalance calcula o saldo em aberto de um cliente usando a conexão ou transação informada
+func customerBalance(ctx context.Context, q rowQuerier, schema, tenantID, customerID string) (*receivable.CustomerBalance, error) {
+	balance := &receivable.CustomerBalance{CustomerID: customerID}
+
+	query := fmt.Sprintf(`
+		SELECT
+			c.credit_limit,
+			COALESCE(SUM(r.amount - r.received_amount), 0),
+			COALESCE(SUM(r.amount - r.received_amount) FILTER (WHERE r.due_date < CURRENT_DATE), 0),
+			COUNT(r.id),
+			COALESCE(MAX(CURRENT_DATE - r.due_date) FILTER (WHERE r.due_date < CURRENT_DATE), 0)
+		FROM %s.customers c
+		LEFT JOIN %s.receivables r
+			ON r.customer_id = c.id AND r.tenant_id = $2 AND r.status IN ('open', 'partial')
+		WHERE c.id = $1
+		GROUP BY c.credit_limit
+	`, schema, schema)
+
+	err := q.QueryRow(ctx, query, customerID, tenantID).Scan(&balance.CreditLimit, &balance.OpenBalance,
+		&balance.OverdueBalance, &balance.OpenTitles, &balance.MaxDaysOverdue)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrCustomerNotFound
+		}
+		return nil, fmt.Errorf("falha ao calcular saldo do cliente: %w", err)
+	}
+
+	balance.Calculate()
+	return balance, nil
+}
+
+// applyReceivablePayment atualiza o saldo do título e grava o recebimento dentro da transação informada
+func applyReceivablePayment(ctx context.Context, tx pgx.Tx, schema, tenantID string, rec *receivable.Receivable, payment *receivable.Payment) error {
+	// O saldo anterior garante que dois recebimentos simultâneos não se sobreponham
+	previousReceived := rec.ReceivedAmount - payment.Amount
+	result, err := tx.Exec(ctx, fmt.Sprintf(`
+		UPDATE %s.receivables
+		SET received_amount = $1, interest = $2, fine = $3, discount = $4, status = $5, updated_at = $6
+		WHERE id = $7 AND tenant_id = $8 AND status IN ('open', 'partial') AND received_amount = ROUND($9::numeric, 2)
+	`, schema), rec.ReceivedAmount, rec.Interest, rec.Fine, rec.Discount, string(rec.Status), rec.UpdatedAt,
+		rec.ID, tenantID, previousReceived)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar título a receber: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return ErrReceivableConcurrentUpdate
+	}
+
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO %s.receivable_payments (
+			id, receivable_id, paid_at, amount, interest, fine, discount, total, method, notes, created_by, created_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)
+	`, schema), payment.ID, rec.ID, payment.PaidAt, payment.Amount, payment.Interest, payment.Fine,
+		payment.Discount, payment.Total, payment.Method, payment.Notes, nullIfEmpty(payment.CreatedBy),
+		payment.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("falha ao registrar recebimento: %w", err)
+	}
+
+	return nil
+}
+
+// insertReceivables grava os títulos dentro da transação informada
+func insertReceivables(ctx context.Context, tx pgx.Tx, schema, tenantID string, receivables []*receivable.Receivable) error {
+	query := fmt.Sprintf(`
+		INSERT INTO %s.receivables (
+			id, tenant_id, branch_id, customer_id, sale_id, document_number, description,
+			installment, installments, issue_date, due_date, amount, received_amount, interest, fine,
+			discount, status, created_by, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20)
+	`, schema)
+
+	for _, rec := range receivables {
+		rec.TenantID = tenantID
+		_, err := tx.Exec(ctx, query,
+			rec.ID, rec.TenantID, rec.BranchID, rec.CustomerID, nullIfEmpty(rec.SaleID), rec.DocumentNumber,
+			rec.Description, rec.Installment, rec.Installments, rec.IssueDate, rec.DueDate, rec.Amount,
+			rec.ReceivedAmount, rec.Interest, rec.Fine, rec.Discount, string(rec.Status),
+			nullIfEmpty(rec.CreatedBy), rec.CreatedAt, rec.UpdatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+				return ErrSaleAlreadyGenerated
+			}
+			if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+				return ErrCustomerNotFound
+			}
+			return fmt.Errorf("falha ao inserir título a receber: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// buildReceivableFilter monta a cláusula WHERE da listagem de títulos a receber
+func buildReceivableFilter(tenantID string, filter receivable.ListFilter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("branch_id = $%d", len(args)))
+	}
+	if filter.CustomerID != "" {
+		args = append(args, filter.CustomerID)
+		conditions = append(conditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+	if filter.SaleID != "" {
+		args = append(args, filter.SaleID)
+		conditions = append(conditions, fmt.Sprintf("sale_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if !filter.DueFrom.IsZero() {
+		args = append(args, filter.DueFrom)
+		conditions = append(conditions, fmt.Sprintf("due_date >= $%d", len(args)))
+	}
+	if !filter.DueTo.IsZero() {
+		args = append(args, filter.DueTo)
+		conditions = append(conditions, fmt.Sprintf("due_date <= $%d", len(args)))
+	}
+	if filter.Overdue {
+		conditions = append(conditions, "status IN ('open', 'partial') AND due_date < CURRENT_DATE")
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanReceivable lê um título a receber de uma linha de resultado
+func scanReceivable(row pgx.Row) (*receivable.Receivable, error) {
+	var rec receivable.Receivable
+	var status string
+	var saleID, createdBy pgtype.Text
+
+	err := row.Scan(&rec.ID, &rec.TenantID, &rec.BranchID, &rec.CustomerID, &saleID, &rec.DocumentNumber,
+		&rec.Description, &rec.Installment, &rec.Installments, &rec.IssueDate, &rec.DueDate, &rec.Amount,
+		&rec.ReceivedAmount, &rec.Interest, &rec.Fine, &rec.Discount, &status, &rec.CancelReason, &createdBy,
+		&rec.CreatedAt, &rec.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	rec.Status = receivable.Status(status)
+	rec.SaleID = saleID.String
+	rec.CreatedBy = createdBy.String
+	rec.Payments = []receivable.Payment{}
+	return &rec, nil
+}
@@ -0,0 +1,247 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrRoleNotFound        = errors.New("perfil não encontrado")
+	ErrRoleDuplicated      = errors.New("já existe perfil com esse nome")
+	ErrAssignmentReference = errors.New("usuário, perfil ou filial da atribuição não encontrado")
+	ErrAssignmentDuplicate = errors.New("perfil atribuído mais de uma vez na mesma filial")
+)
+
+// roleColumns são as colunas lidas por scanRole, na mesma ordem
+const roleColumns = "id, tenant_id, name, COALESCE(description, ''), permissions, created_at, updated_at"
+
+// RoleRepository implementa a interface rbac.Repository
+type RoleRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewRoleRepository cria uma nova instância de RoleRepository
+func NewRoleRepository(db *pgxpool.Pool) rbac.Repository {
+	return &RoleRepository{
+		db: db,
+	}
+}
+
+// CreateRole implementa rbac.Repository.CreateRole
+func (r *RoleRepository) CreateRole(ctx context.Context, role *rbac.Role) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		role.TenantID = scope.TenantID
+
+		query := fmt.Sprintf(`INSERT INTO %s (id, tenant_id, name, description, permissions, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`, scope.Table("roles"))
+		_, err := tx.Exec(ctx, query, role.ID, role.TenantID, role.Name, nullIfEmpty(role.Description),
+			permissionStrings(role.Permissions), role.CreatedAt, role.UpdatedAt)
+		if err != nil {
+			return roleError(err, "falha ao criar perfil")
+		}
+		return nil
+	})
+}
+
+// FindRole implementa rbac.Repository.FindRole
+func (r *RoleRepository) FindRole(ctx context.Context, id string) (*rbac.Role, error) {
+	var role *rbac.Role
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND tenant_id = $2", roleColumns, scope.Table("roles"))
+
+		var err error
+		role, err = scanRole(tx.QueryRow(ctx, query, id, scope.TenantID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrRoleNotFound
+			}
+			return fmt.Errorf("falha ao buscar perfil: %w", err)
+		}
+		return nil
+	})
+	return role, err
+}
+
+// ListRoles implementa rbac.Repository.ListRoles
+func (r *RoleRepository) ListRoles(ctx context.Context) ([]*rbac.Role, error) {
+	var roles []*rbac.Role
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE tenant_id = $1 ORDER BY name", roleColumns, scope.Table("roles"))
+		rows, err := tx.Query(ctx, query, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao listar perfis: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			role, err := scanRole(rows)
+			if err != nil {
+				return fmt.Errorf("falha ao ler perfil: %w", err)
+			}
+			roles = append(roles, role)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("falha ao listar perfis: %w", err)
+		}
+		return nil
+	})
+	return roles, err
+}
+
+// UpdateRole implementa rbac.Repository.UpdateRole
+func (r *RoleRepository) UpdateRole(ctx context.Context, role *rbac.Role) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`UPDATE %s SET name = $1, description = $2, permissions = $3, updated_at = $4
+			WHERE id = $5 AND tenant_id = $6`, scope.Table("roles"))
+		result, err := tx.Exec(ctx, query, role.Name, nullIfEmpty(role.Description), permissionStrings(role.Permissions),
+			role.UpdatedAt, role.ID, scope.TenantID)
+		if err != nil {
+			return roleError(err, "falha ao atualizar perfil")
+		}
+		if result.RowsAffected() == 0 {
+			return ErrRoleNotFound
+		}
+		return nil
+	})
+}
+
+// DeleteRole implementa rbac.Repository.DeleteRole. As atribuições são removidas pela chave
+// estrangeira
+func (r *RoleRepository) DeleteRole(ctx context.Context, id string) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		result, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND tenant_id = $2", scope.Table("roles")), id, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao excluir perfil: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrRoleNotFound
+		}
+		return nil
+	})
+}
+
+// ListAssignments implementa rbac.Repository.ListAssignments
+func (r *RoleRepository) ListAssignments(ctx context.Context, userID string) ([]*rbac.Assignment, error) {
+	var assignments []*rbac.Assignment
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT a.id, a.tenant_id, a.user_id, a.role_id, r.name, COALESCE(a.branch_id::text, ''), a.created_at
+			FROM %s a
+			JOIN %s r ON r.id = a.role_id
+			WHERE a.user_id = $1 AND a.tenant_id = $2
+			ORDER BY a.branch_id NULLS FIRST, r.name`, scope.Table("user_role_assignments"), scope.Table("roles"))
+		rows, err := tx.Query(ctx, query, userID, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao listar perfis do usuário: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var a rbac.Assignment
+			if err := rows.Scan(&a.ID, &a.TenantID, &a.UserID, &a.RoleID, &a.RoleName, &a.BranchID, &a.CreatedAt); err != nil {
+				return fmt.Errorf("falha ao ler perfil do usuário: %w", err)
+			}
+			assignments = append(assignments, &a)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("falha ao listar perfis do usuário: %w", err)
+		}
+		return nil
+	})
+	return assignments, err
+}
+
+// ReplaceAssignments implementa rbac.Repository.ReplaceAssignments
+func (r *RoleRepository) ReplaceAssignments(ctx context.Context, userID string, assignments []*rbac.Assignment) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		table := scope.Table("user_role_assignments")
+		if _, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE user_id = $1 AND tenant_id = $2", table), userID, scope.TenantID); err != nil {
+			return fmt.Errorf("falha ao remover perfis do usuário: %w", err)
+		}
+
+		query := fmt.Sprintf(`INSERT INTO %s (id, tenant_id, user_id, role_id, branch_id, created_at)
+			VALUES ($1, $2, $3, $4, $5, $6)`, table)
+		for _, a := range assignments {
+			a.TenantID = scope.TenantID
+			_, err := tx.Exec(ctx, query, a.ID, a.TenantID, userID, a.RoleID, nullIfEmpty(a.BranchID), a.CreatedAt)
+			if err != nil {
+				var pgErr *pgconn.PgError
+				if errors.As(err, &pgErr) {
+					switch pgErr.Code {
+					case "23503", "22P02":
+						return ErrAssignmentReference
+					case "23505":
+						return ErrAssignmentDuplicate
+					}
+				}
+				return fmt.Errorf("falha ao atribuir perfil ao usuário: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// AssignedPermissions implementa rbac.Repository.AssignedPermissions
+func (r *RoleRepository) AssignedPermissions(ctx context.Context, userID, branchID string) ([]rbac.Permission, error) {
+	var permissions []rbac.Permission
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT DISTINCT unnest(r.permissions)
+			FROM %s a
+			JOIN %s r ON r.id = a.role_id
+			WHERE a.user_id = $1 AND a.tenant_id = $2 AND (a.branch_id IS NULL OR a.branch_id::text = $3)`,
+			scope.Table("user_role_assignments"), scope.Table("roles"))
+		rows, err := tx.Query(ctx, query, userID, scope.TenantID, branchID)
+		if err != nil {
+			return fmt.Errorf("falha ao buscar permissões do usuário: %w", err)
+		}
+		values, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return fmt.Errorf("falha ao buscar permissões do usuário: %w", err)
+		}
+		for _, v := range values {
+			permissions = append(permissions, rbac.Permission(v))
+		}
+		return nil
+	})
+	return permissions, err
+}
+
+// roleError converte as violações de restrição ao gravar um perfil
+func roleError(err error, message string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+		return ErrRoleDuplicated
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// permissionStrings converte as permissões para o array de texto do banco
+func permissionStrings(permissions []rbac.Permission) []string {
+	values := make([]string, len(permissions))
+	for i, p := range permissions {
+		values[i] = string(p)
+	}
+	return values
+}
+
+// scanRole lê um perfil selecionado com roleColumns
+func scanRole(row pgx.Row) (*rbac.Role, error) {
+	var role rbac.Role
+	var permissions []string
+	if err := row.Scan(&role.ID, &role.TenantID, &role.Name, &role.Description, &permissions, &role.CreatedAt, &role.UpdatedAt); err != nil {
+		return nil, err
+	}
+	role.Permissions = make([]rbac.Permission, len(permissions))
+	for i, p := range permissions {
+		role.Permissions[i] = rbac.Permission(p)
+	}
+	return &role, nil
+}
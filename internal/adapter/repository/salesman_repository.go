@@ -0,0 +1,516 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/salesman"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrSalesmanNotFound          = errors.New("vendedor não encontrado")
+	ErrSalesmanUserNotFound      = errors.New("usuário vinculado ao vendedor não encontrado")
+	ErrSalesmanUserInUse         = errors.New("usuário já vinculado a outro vendedor")
+	ErrCommissionRuleNotFound    = errors.New("regra de comissão não encontrada")
+	ErrCommissionRuleReference   = errors.New("categoria ou forma de pagamento da regra não encontrada")
+	ErrCommissionNotFound        = errors.New("comissão da venda não encontrada")
+	ErrCommissionAlreadyAccrued  = errors.New("comissão da venda já lançada")
+	ErrCommissionAlreadyReversed = errors.New("comissão da venda já estornada")
+)
+
+// pgForeignKeyViolation é o código do PostgreSQL para referência inexistente
+const pgForeignKeyViolation = "23503"
+
+// SalesmanRepository implementa a interface salesman.Repository
+type SalesmanRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSalesmanRepository cria uma nova instância de SalesmanRepository
+func NewSalesmanRepository(db *pgxpool.Pool) salesman.Repository {
+	return &SalesmanRepository{
+		db: db,
+	}
+}
+
+const salesmanColumns = `id, tenant_id, user_id, name, document, email, phone, commission_percent, active,
+	created_at, updated_at`
+
+const commissionColumns = `id, tenant_id, salesman_id, sale_id, branch_id, customer_id, sale_date, base_amount,
+	amount, status, lines, reversed_at, reverse_reason, created_at`
+
+// Create implementa salesman.Repository.Create
+func (r *SalesmanRepository) Create(ctx context.Context, s *salesman.Salesman) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	s.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.salesmen (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+	`, schema, salesmanColumns)
+
+	_, err = conn.Exec(ctx, query, s.ID, s.TenantID, nullIfEmpty(s.UserID), s.Name, nullIfEmpty(s.Document),
+		nullIfEmpty(s.Email), nullIfEmpty(s.Phone), s.CommissionPercent, s.Active, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		return salesmanWriteError("falha ao criar vendedor", err)
+	}
+
+	return nil
+}
+
+// Update implementa salesman.Repository.Update
+func (r *SalesmanRepository) Update(ctx context.Context, s *salesman.Salesman) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.salesmen
+		SET user_id = $1, name = $2, document = $3, email = $4, phone = $5, commission_percent = $6,
+			active = $7, updated_at = $8
+		WHERE id = $9 AND tenant_id = $10
+	`, schema)
+
+	result, err := conn.Exec(ctx, query, nullIfEmpty(s.UserID), s.Name, nullIfEmpty(s.Document),
+		nullIfEmpty(s.Email), nullIfEmpty(s.Phone), s.CommissionPercent, s.Active, s.UpdatedAt, s.ID, tenantID)
+	if err != nil {
+		return salesmanWriteError("falha ao atualizar vendedor", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSalesmanNotFound
+	}
+
+	return nil
+}
+
+// FindByID implementa salesman.Repository.FindByID
+func (r *SalesmanRepository) FindByID(ctx context.Context, id string) (*salesman.Salesman, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.salesmen WHERE id = $1 AND tenant_id = $2", salesmanColumns, schema)
+
+	s, err := scanSalesman(conn.QueryRow(ctx, query, id, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSalesmanNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar vendedor: %w", err)
+	}
+
+	return s, nil
+}
+
+// List implementa salesman.Repository.List
+func (r *SalesmanRepository) List(ctx context.Context, filter salesman.Filter, limit, offset int) ([]*salesman.Salesman, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	where, args := buildSalesmanFilter(tenantID, filter)
+	args = append(args, limit, offset)
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.salesmen
+		WHERE %s
+		ORDER BY name
+		LIMIT $%d OFFSET $%d
+	`, salesmanColumns, schema, where, len(args)-1, len(args))
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar vendedores: %w", err)
+	}
+	defer rows.Close()
+
+	salesmen := make([]*salesman.Salesman, 0)
+	for rows.Next() {
+		s, err := scanSalesman(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler vendedor: %w", err)
+		}
+		salesmen = append(salesmen, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar vendedores: %w", err)
+	}
+
+	return salesmen, nil
+}
+
+// Count implementa salesman.Repository.Count
+func (r *SalesmanRepository) Count(ctx context.Context, filter salesman.Filter) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	where, args := buildSalesmanFilter(tenantID, filter)
+
+	var count int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.salesmen WHERE %s", schema, where)
+	if err := conn.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar vendedores: %w", err)
+	}
+
+	return count, nil
+}
+
+// CreateRule implementa salesman.Repository.CreateRule
+func (r *SalesmanRepository) CreateRule(ctx context.Context, rule *salesman.Rule) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	rule.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.commission_rules (
+			id, tenant_id, salesman_id, category_id, payment_method_id, percentage, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query, rule.ID, rule.TenantID, nullIfEmpty(rule.SalesmanID), nullIfEmpty(rule.CategoryID),
+		nullIfEmpty(rule.PaymentMethodID), rule.Percentage, rule.CreatedAt, rule.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == pgForeignKeyViolation {
+			if pgErr.ConstraintName == "commission_rules_salesman_id_fkey" {
+				return ErrSalesmanNotFound
+			}
+			return ErrCommissionRuleReference
+		}
+		return fmt.Errorf("falha ao criar regra de comissão: %w", err)
+	}
+
+	return nil
+}
+
+// DeleteRule implementa salesman.Repository.DeleteRule
+func (r *SalesmanRepository) DeleteRule(ctx context.Context, id string) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("DELETE FROM %s.commission_rules WHERE id = $1 AND tenant_id = $2", schema)
+	result, err := conn.Exec(ctx, query, id, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao excluir regra de comissão: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrCommissionRuleNotFound
+	}
+
+	return nil
+}
+
+// ListRules implementa salesman.Repository.ListRules
+func (r *SalesmanRepository) ListRules(ctx context.Context, salesmanID string) ([]*salesman.Rule, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, tenant_id, salesman_id, category_id, payment_method_id, percentage, created_at, updated_at
+		FROM %s.commission_rules
+		WHERE tenant_id = $1
+	`, schema)
+	args := []interface{}{tenantID}
+	if salesmanID != "" {
+		query += " AND (salesman_id IS NULL OR salesman_id = $2)"
+		args = append(args, salesmanID)
+	}
+	query += " ORDER BY created_at, id"
+
+	rows, err := conn.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar regras de comissão: %w", err)
+	}
+	defer rows.Close()
+
+	rules := make([]*salesman.Rule, 0)
+	for rows.Next() {
+		var rule salesman.Rule
+		var ruleSalesmanID, categoryID, paymentMethodID pgtype.Text
+		if err := rows.Scan(&rule.ID, &rule.TenantID, &ruleSalesmanID, &categoryID, &paymentMethodID,
+			&rule.Percentage, &rule.CreatedAt, &rule.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("falha ao ler regra de comissão: %w", err)
+		}
+		rule.SalesmanID = ruleSalesmanID.String
+		rule.CategoryID = categoryID.String
+		rule.PaymentMethodID = paymentMethodID.String
+		rules = append(rules, &rule)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar regras de comissão: %w", err)
+	}
+
+	return rules, nil
+}
+
+// Accrue implementa salesman.Repository.Accrue
+func (r *SalesmanRepository) Accrue(ctx context.Context, c *salesman.Commission) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	c.TenantID = tenantID
+
+	lines, err := json.Marshal(c.Lines)
+	if err != nil {
+		return fmt.Errorf("falha ao serializar itens da comissão: %w", err)
+	}
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.commissions (%s)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14)
+	`, schema, commissionColumns)
+
+	_, err = conn.Exec(ctx, query, c.ID, c.TenantID, c.SalesmanID, c.SaleID, nullIfEmpty(c.BranchID),
+		nullIfEmpty(c.CustomerID), c.SaleDate, c.BaseAmount, c.Amount, string(c.Status), lines, c.ReversedAt,
+		nullIfEmpty(c.ReverseReason), c.CreatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrCommissionAlreadyAccrued
+		}
+		return fmt.Errorf("falha ao lançar comissão: %w", err)
+	}
+
+	return nil
+}
+
+// Reverse implementa salesman.Repository.Reverse
+func (r *SalesmanRepository) Reverse(ctx context.Context, saleID, reason string) (*salesman.Commission, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.commissions
+		SET status = $1, reversed_at = $2, reverse_reason = $3
+		WHERE sale_id = $4 AND tenant_id = $5 AND status = $6
+		RETURNING %s
+	`, schema, commissionColumns)
+
+	c, err := scanCommission(conn.QueryRow(ctx, query, string(salesman.StatusReversed), time.Now(),
+		nullIfEmpty(reason), saleID, tenantID, string(salesman.StatusAccrued)))
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("falha ao estornar comissão: %w", err)
+	}
+
+	var exists bool
+	existsQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.commissions WHERE sale_id = $1 AND tenant_id = $2)", schema)
+	if err := conn.QueryRow(ctx, existsQuery, saleID, tenantID).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("falha ao buscar comissão da venda: %w", err)
+	}
+	if exists {
+		return nil, ErrCommissionAlreadyReversed
+	}
+	return nil, ErrCommissionNotFound
+}
+
+// ListCommissions implementa salesman.Repository.ListCommissions
+func (r *SalesmanRepository) ListCommissions(ctx context.Context, salesmanID string, startDate, endDate time.Time) ([]*salesman.Commission, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.commissions
+		WHERE tenant_id = $1 AND salesman_id = $2
+			AND (sale_date BETWEEN $3 AND $4 OR reversed_at BETWEEN $3 AND $4)
+		ORDER BY sale_date, id
+	`, commissionColumns, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID, salesmanID, startDate, endDate)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar comissões: %w", err)
+	}
+	defer rows.Close()
+
+	commissions := make([]*salesman.Commission, 0)
+	for rows.Next() {
+		c, err := scanCommission(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler comissão: %w", err)
+		}
+		commissions = append(commissions, c)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar comissões: %w", err)
+	}
+
+	return commissions, nil
+}
+
+// salesmanWriteError converte violações de integridade na gravação do vendedor
+func salesmanWriteError(message string, err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		switch pgErr.Code {
+		case "23505":
+			return ErrSalesmanUserInUse
+		case pgForeignKeyViolation:
+			return ErrSalesmanUserNotFound
+		}
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// buildSalesmanFilter monta a cláusula WHERE para as consultas de vendedores
+func buildSalesmanFilter(tenantID string, filter salesman.Filter) (string, []interface{}) {
+	conditions := []string{"tenant_id = $1"}
+	args := []interface{}{tenantID}
+
+	if filter.Name != "" {
+		args = append(args, "%"+filter.Name+"%")
+		conditions = append(conditions, fmt.Sprintf("name ILIKE $%d", len(args)))
+	}
+	if filter.Active != nil {
+		args = append(args, *filter.Active)
+		conditions = append(conditions, fmt.Sprintf("active = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanSalesman lê um vendedor de uma linha de resultado
+func scanSalesman(row pgx.Row) (*salesman.Salesman, error) {
+	var s salesman.Salesman
+	var userID, document, email, phone pgtype.Text
+
+	err := row.Scan(&s.ID, &s.TenantID, &userID, &s.Name, &document, &email, &phone, &s.CommissionPercent,
+		&s.Active, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	s.UserID = userID.String
+	s.Document = document.String
+	s.Email = email.String
+	s.Phone = phone.String
+
+	return &s, nil
+}
+
+// scanCommission lê uma comissão de uma linha de resultado
+func scanCommission(row pgx.Row) (*salesman.Commission, error) {
+	var c salesman.Commission
+	var branchID, customerID, reverseReason pgtype.Text
+	var reversedAt pgtype.Timestamp
+	var status string
+	var lines []byte
+
+	err := row.Scan(&c.ID, &c.TenantID, &c.SalesmanID, &c.SaleID, &branchID, &customerID, &c.SaleDate,
+		&c.BaseAmount, &c.Amount, &status, &lines, &reversedAt, &reverseReason, &c.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	c.BranchID = branchID.String
+	c.CustomerID = customerID.String
+	c.ReverseReason = reverseReason.String
+	c.Status = salesman.Status(status)
+	if reversedAt.Valid {
+		c.ReversedAt = &reversedAt.Time
+	}
+	if err := json.Unmarshal(lines, &c.Lines); err != nil {
+		return nil, fmt.Errorf("falha ao ler itens da comissão: %w", err)
+	}
+
+	return &c, nil
+}
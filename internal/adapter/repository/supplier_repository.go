@@ -0,0 +1,261 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/supplier"
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// Erros específicos do repositório de fornecedores
+var (
+	ErrSupplierNotFound          = errors.New("fornecedor não encontrado")
+	ErrSupplierDuplicateDocument = errors.New("já existe um fornecedor com este documento")
+	ErrSupplierInUse             = errors.New("fornecedor possui títulos vinculados e não pode ser excluído")
+)
+
+// SupplierRepository implementa a interface supplier.Repository
+type SupplierRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewSupplierRepository cria uma nova instância de SupplierRepository
+func NewSupplierRepository(db *pgxpool.Pool) supplier.Repository {
+	return &SupplierRepository{
+		db: db,
+	}
+}
+
+// supplierColumns lista as colunas lidas da tabela de fornecedores
+const supplierColumns = `id, tenant_id, name, COALESCE(trade_name, ''), document, COALESCE(state_document, ''),
+	COALESCE(email, ''), COALESCE(phone, ''), payment_term, COALESCE(street, ''), COALESCE(number, ''),
+	COALESCE(complement, ''), COALESCE(district, ''), COALESCE(city, ''), COALESCE(state, ''),
+	COALESCE(zip_code, ''), COALESCE(city_code, ''), COALESCE(state_code, ''), COALESCE(notes, ''),
+	active, created_at, updated_at`
+
+// Create implementa supplier.Repository.Create
+func (r *SupplierRepository) Create(ctx context.Context, s *supplier.Supplier) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+	s.TenantID = tenantID
+
+	query := fmt.Sprintf(`
+		INSERT INTO %s.suppliers (
+			id, tenant_id, name, trade_name, document, state_document, email, phone, payment_term,
+			street, number, complement, district, city, state, zip_code, city_code, state_code,
+			notes, active, created_at, updated_at
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22)
+	`, schema)
+
+	_, err = conn.Exec(ctx, query,
+		s.ID, s.TenantID, s.Name, s.TradeName, s.Document, s.StateDocument, s.Email, s.Phone, s.PaymentTerm,
+		s.Address.Street, s.Address.Number, s.Address.Complement, s.Address.District, s.Address.City,
+		s.Address.State, s.Address.ZipCode, s.Address.CityCode, s.Address.StateCode,
+		s.Notes, s.Active, s.CreatedAt, s.UpdatedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrSupplierDuplicateDocument
+		}
+		return fmt.Errorf("falha ao inserir fornecedor: %w", err)
+	}
+
+	return nil
+}
+
+// FindByID implementa supplier.Repository.FindByID
+func (r *SupplierRepository) FindByID(ctx context.Context, id string) (*supplier.Supplier, error) {
+	return r.findOne(ctx, "id = $1", id)
+}
+
+// FindByDocument implementa supplier.Repository.FindByDocument
+func (r *SupplierRepository) FindByDocument(ctx context.Context, document string) (*supplier.Supplier, error) {
+	return r.findOne(ctx, "document = $1", pkgdocument.Normalize(document))
+}
+
+// findOne busca um único fornecedor pela condição informada
+func (r *SupplierRepository) findOne(ctx context.Context, condition string, value string) (*supplier.Supplier, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf("SELECT %s FROM %s.suppliers WHERE %s AND tenant_id = $2", supplierColumns, schema, condition)
+
+	s, err := scanSupplier(conn.QueryRow(ctx, query, value, tenantID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrSupplierNotFound
+		}
+		return nil, fmt.Errorf("falha ao buscar fornecedor: %w", err)
+	}
+
+	return s, nil
+}
+
+// List implementa supplier.Repository.List
+func (r *SupplierRepository) List(ctx context.Context, search string, limit, offset int) ([]*supplier.Supplier, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return nil, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT %s FROM %s.suppliers
+		WHERE tenant_id = $1 AND ($2 = '' OR name ILIKE '%%' || $2 || '%%' OR trade_name ILIKE '%%' || $2 || '%%' OR document = $2)
+		ORDER BY name
+		LIMIT $3 OFFSET $4
+	`, supplierColumns, schema)
+
+	rows, err := conn.Query(ctx, query, tenantID, search, limit, offset)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar fornecedores: %w", err)
+	}
+	defer rows.Close()
+
+	suppliers := make([]*supplier.Supplier, 0)
+	for rows.Next() {
+		s, err := scanSupplier(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler fornecedor: %w", err)
+		}
+		suppliers = append(suppliers, s)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao iterar fornecedores: %w", err)
+	}
+
+	return suppliers, nil
+}
+
+// Count implementa supplier.Repository.Count
+func (r *SupplierRepository) Count(ctx context.Context, search string) (int, error) {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return 0, err
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*) FROM %s.suppliers
+		WHERE tenant_id = $1 AND ($2 = '' OR name ILIKE '%%' || $2 || '%%' OR trade_name ILIKE '%%' || $2 || '%%' OR document = $2)
+	`, schema)
+
+	var count int
+	if err := conn.QueryRow(ctx, query, tenantID, search).Scan(&count); err != nil {
+		return 0, fmt.Errorf("falha ao contar fornecedores: %w", err)
+	}
+
+	return count, nil
+}
+
+// Update implementa supplier.Repository.Update
+func (r *SupplierRepository) Update(ctx context.Context, s *supplier.Supplier) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf(`
+		UPDATE %s.suppliers SET
+			name = $1, trade_name = $2, state_document = $3, email = $4, phone = $5, payment_term = $6,
+			street = $7, number = $8, complement = $9, district = $10, city = $11, state = $12,
+			zip_code = $13, city_code = $14, state_code = $15, notes = $16, active = $17, updated_at = $18
+		WHERE id = $19 AND tenant_id = $20
+	`, schema)
+
+	result, err := conn.Exec(ctx, query,
+		s.Name, s.TradeName, s.StateDocument, s.Email, s.Phone, s.PaymentTerm,
+		s.Address.Street, s.Address.Number, s.Address.Complement, s.Address.District, s.Address.City,
+		s.Address.State, s.Address.ZipCode, s.Address.CityCode, s.Address.StateCode,
+		s.Notes, s.Active, s.UpdatedAt, s.ID, tenantID)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar fornecedor: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSupplierNotFound
+	}
+
+	return nil
+}
+
+// Delete implementa supplier.Repository.Delete
+func (r *SupplierRepository) Delete(ctx context.Context, id string) error {
+	conn, err := r.db.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao obter conexão: %w", err)
+	}
+	defer conn.Release()
+
+	tenantID, schema, err := resolveTenantSchema(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	result, err := conn.Exec(ctx, fmt.Sprintf("DELETE FROM %s.suppliers WHERE id = $1 AND tenant_id = $2", schema), id, tenantID)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return ErrSupplierInUse
+		}
+		return fmt.Errorf("falha ao excluir fornecedor: %w", err)
+	}
+
+	if result.RowsAffected() == 0 {
+		return ErrSupplierNotFound
+	}
+
+	return nil
+}
+
+// scanSupplier lê um fornecedor de uma linha de resultado
+func scanSupplier(row pgx.Row) (*supplier.Supplier, error) {
+	var s supplier.Supplier
+	err := row.Scan(&s.ID, &s.TenantID, &s.Name, &s.TradeName, &s.Document, &s.StateDocument,
+		&s.Email, &s.Phone, &s.PaymentTerm, &s.Address.Street, &s.Address.Number,
+		&s.Address.Complement, &s.Address.District, &s.Address.City, &s.Address.State,
+		&s.Address.ZipCode, &s.Address.CityCode, &s.Address.StateCode, &s.Notes,
+		&s.Active, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
@@ -2,24 +2,30 @@ package repository
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 // Erros específicos do repositório
 var (
-	ErrTenantNotFound          = errors.New("tenant não encontrado")
+	ErrTenantNotFound          = database.ErrTenantNotFound
 	ErrTenantDuplicateDocument = errors.New("tenant com mesmo documento já existe")
 	ErrTenantDatabaseError     = errors.New("erro de banco de dados")
 	// ErrDuplicateKey já definido em outro lugar do pacote
 )
 
+// tenantColumns são as colunas lidas por scanTenant, na mesma ordem
+const tenantColumns = "id, name, document, email, phone, status, schema, plan_type, max_branches, plan_expires_at, deleted_at, purge_after, created_at, updated_at"
+
 // TenantRepository implementa a interface tenant.Repository
 type TenantRepository struct {
 	db *pgxpool.Pool
@@ -47,9 +53,9 @@ func (r *TenantRepository) Create(ctx context.Context, t *tenant.Tenant) error {
 
 	// Inserir o tenant no banco de dados
 	_, err = r.db.Exec(ctx,
-		`INSERT INTO tenants (id, name, document, email, phone, status, schema, plan_type, max_branches, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
-		t.ID, t.Name, t.Document, t.Email, t.Phone, string(t.Status), t.Schema, t.PlanType, t.MaxBranches, t.CreatedAt, t.UpdatedAt)
+		`INSERT INTO tenants (id, name, document, email, phone, status, schema, plan_type, max_branches, plan_expires_at, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`,
+		t.ID, t.Name, t.Document, t.Email, t.Phone, string(t.Status), t.Schema, t.PlanType, t.MaxBranches, t.PlanExpiresAt, t.CreatedAt, t.UpdatedAt)
 
 	if err != nil {
 		// Verificar se é um erro de chave duplicada
@@ -64,15 +70,11 @@ func (r *TenantRepository) Create(ctx context.Context, t *tenant.Tenant) error {
 
 // FindByID implementa tenant.Repository.FindByID
 func (r *TenantRepository) FindByID(ctx context.Context, id string) (*tenant.Tenant, error) {
-	var t tenant.Tenant
-	var status string
-
-	err := r.db.QueryRow(ctx, `
-		SELECT id, name, document, email, phone, status, schema, plan_type, max_branches, created_at, updated_at
+	t, err := scanTenant(r.db.QueryRow(ctx, `
+		SELECT `+tenantColumns+`
 		FROM tenants
 		WHERE id = $1`,
-		id).Scan(
-		&t.ID, &t.Name, &t.Document, &t.Email, &t.Phone, &status, &t.Schema, &t.PlanType, &t.MaxBranches, &t.CreatedAt, &t.UpdatedAt)
+		id))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -81,21 +83,16 @@ func (r *TenantRepository) FindByID(ctx context.Context, id string) (*tenant.Ten
 		return nil, fmt.Errorf("erro ao buscar tenant por ID: %w", err)
 	}
 
-	t.Status = tenant.Status(status)
-	return &t, nil
+	return t, nil
 }
 
 // FindByDocument implementa tenant.Repository.FindByDocument
 func (r *TenantRepository) FindByDocument(ctx context.Context, document string) (*tenant.Tenant, error) {
-	var t tenant.Tenant
-	var status string
-
-	err := r.db.QueryRow(ctx, `
-		SELECT id, name, document, email, phone, status, schema, plan_type, max_branches, created_at, updated_at
+	t, err := scanTenant(r.db.QueryRow(ctx, `
+		SELECT `+tenantColumns+`
 		FROM tenants
 		WHERE document = $1`,
-		document).Scan(
-		&t.ID, &t.Name, &t.Document, &t.Email, &t.Phone, &status, &t.Schema, &t.PlanType, &t.MaxBranches, &t.CreatedAt, &t.UpdatedAt)
+		pkgdocument.Normalize(document)))
 
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
@@ -104,14 +101,13 @@ func (r *TenantRepository) FindByDocument(ctx context.Context, document string)
 		return nil, fmt.Errorf("erro ao buscar tenant por documento: %w", err)
 	}
 
-	t.Status = tenant.Status(status)
-	return &t, nil
+	return t, nil
 }
 
 // List implementa tenant.Repository.List
 func (r *TenantRepository) List(ctx context.Context, limit, offset int) ([]*tenant.Tenant, error) {
 	rows, err := r.db.Query(ctx, `
-		SELECT id, name, document, email, phone, status, schema, plan_type, max_branches, created_at, updated_at
+		SELECT `+tenantColumns+`
 		FROM tenants
 		ORDER BY name
 		LIMIT $1 OFFSET $2`,
@@ -122,26 +118,7 @@ func (r *TenantRepository) List(ctx context.Context, limit, offset int) ([]*tena
 	}
 	defer rows.Close()
 
-	var tenants []*tenant.Tenant
-
-	for rows.Next() {
-		var t tenant.Tenant
-		var status string
-
-		err := rows.Scan(&t.ID, &t.Name, &t.Document, &t.Email, &t.Phone, &status, &t.Schema, &t.PlanType, &t.MaxBranches, &t.CreatedAt, &t.UpdatedAt)
-		if err != nil {
-			return nil, fmt.Errorf("erro ao ler tenant: %w", err)
-		}
-
-		t.Status = tenant.Status(status)
-		tenants = append(tenants, &t)
-	}
-
-	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao percorrer resultados: %w", err)
-	}
-
-	return tenants, nil
+	return scanTenants(rows)
 }
 
 // Update implementa tenant.Repository.Update
@@ -158,9 +135,9 @@ func (r *TenantRepository) Update(ctx context.Context, t *tenant.Tenant) error {
 	// Atualizar o tenant
 	_, err = r.db.Exec(ctx, `
 		UPDATE tenants
-		SET name = $1, email = $2, phone = $3, plan_type = $4, max_branches = $5, updated_at = $6
-		WHERE id = $7`,
-		t.Name, t.Email, t.Phone, t.PlanType, t.MaxBranches, time.Now(), t.ID)
+		SET name = $1, email = $2, phone = $3, plan_type = $4, max_branches = $5, plan_expires_at = $6, updated_at = $7
+		WHERE id = $8`,
+		t.Name, t.Email, t.Phone, t.PlanType, t.MaxBranches, t.PlanExpiresAt, time.Now(), t.ID)
 
 	if err != nil {
 		return fmt.Errorf("erro ao atualizar tenant: %w", err)
@@ -185,6 +162,7 @@ func (r *TenantRepository) Delete(ctx context.Context, id string) error {
 	if err != nil {
 		return fmt.Errorf("erro ao excluir tenant: %w", err)
 	}
+	database.InvalidateTenantSchema(id)
 
 	return nil
 }
@@ -229,7 +207,7 @@ func (r *TenantRepository) Count(ctx context.Context) (int, error) {
 func (r *TenantRepository) FindByNameLike(ctx context.Context, name string, limit, offset int) ([]*tenant.Tenant, error) {
 	// Utilizar ILIKE para busca case-insensitive
 	rows, err := r.db.Query(ctx, `
-		SELECT id, name, document, email, phone, status, schema, plan_type, max_branches, created_at, updated_at
+		SELECT `+tenantColumns+`
 		FROM tenants
 		WHERE name ILIKE $1
 		ORDER BY name
@@ -241,19 +219,56 @@ func (r *TenantRepository) FindByNameLike(ctx context.Context, name string, limi
 	}
 	defer rows.Close()
 
+	return scanTenants(rows)
+}
+
+// Exists implementa tenant.Repository.Exists
+func (r *TenantRepository) Exists(ctx context.Context, id string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tenants WHERE id = $1)", id).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar existência de tenant: %w", err)
+	}
+
+	return exists, nil
+}
+
+// ExistsByDocument implementa tenant.Repository.ExistsByDocument
+func (r *TenantRepository) ExistsByDocument(ctx context.Context, document string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tenants WHERE document = $1)", document).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar existência de tenant por documento: %w", err)
+	}
+
+	return exists, nil
+}
+
+// scanTenant lê um tenant selecionado com tenantColumns
+func scanTenant(row pgx.Row) (*tenant.Tenant, error) {
+	var t tenant.Tenant
+	var status string
+
+	err := row.Scan(&t.ID, &t.Name, &t.Document, &t.Email, &t.Phone, &status, &t.Schema, &t.PlanType, &t.MaxBranches,
+		&t.PlanExpiresAt, &t.DeletedAt, &t.PurgeAfter, &t.CreatedAt, &t.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	t.Status = tenant.Status(status)
+	return &t, nil
+}
+
+// scanTenants lê todas as linhas de uma consulta selecionada com tenantColumns
+func scanTenants(rows pgx.Rows) ([]*tenant.Tenant, error) {
 	var tenants []*tenant.Tenant
 
 	for rows.Next() {
-		var t tenant.Tenant
-		var status string
-
-		err := rows.Scan(&t.ID, &t.Name, &t.Document, &t.Email, &t.Phone, &status, &t.Schema, &t.PlanType, &t.MaxBranches, &t.CreatedAt, &t.UpdatedAt)
+		t, err := scanTenant(rows)
 		if err != nil {
 			return nil, fmt.Errorf("erro ao ler tenant: %w", err)
 		}
-
-		t.Status = tenant.Status(status)
-		tenants = append(tenants, &t)
+		tenants = append(tenants, t)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -263,24 +278,176 @@ func (r *TenantRepository) FindByNameLike(ctx context.Context, name string, limi
 	return tenants, nil
 }
 
-// Exists implementa tenant.Repository.Exists
-func (r *TenantRepository) Exists(ctx context.Context, id string) (bool, error) {
-	var exists bool
-	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tenants WHERE id = $1)", id).Scan(&exists)
+// SoftDelete implementa tenant.Repository.SoftDelete
+func (r *TenantRepository) SoftDelete(ctx context.Context, t *tenant.Tenant, audit *tenant.AuditEntry) error {
+	return r.updateLifecycle(ctx, t, audit, "deleted_at IS NULL", tenant.ErrTenantDeleted)
+}
+
+// Restore implementa tenant.Repository.Restore
+func (r *TenantRepository) Restore(ctx context.Context, t *tenant.Tenant, audit *tenant.AuditEntry) error {
+	return r.updateLifecycle(ctx, t, audit, "deleted_at IS NOT NULL", tenant.ErrTenantNotDeleted)
+}
+
+// updateLifecycle grava status, deleted_at e purge_after do tenant junto com o evento de
+// auditoria. A condição evita que duas requisições simultâneas excluam ou restaurem o mesmo tenant
+func (r *TenantRepository) updateLifecycle(ctx context.Context, t *tenant.Tenant, audit *tenant.AuditEntry, condition string, conflict error) error {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return false, fmt.Errorf("erro ao verificar existência de tenant: %w", err)
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
 	}
+	defer tx.Rollback(ctx)
 
-	return exists, nil
+	result, err := tx.Exec(ctx, `
+		UPDATE tenants
+		SET status = $1, deleted_at = $2, purge_after = $3, updated_at = $4
+		WHERE id = $5 AND `+condition,
+		string(t.Status), t.DeletedAt, t.PurgeAfter, t.UpdatedAt, t.ID)
+	if err != nil {
+		return fmt.Errorf("erro ao atualizar tenant: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		exists, err := r.Exists(ctx, t.ID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrTenantNotFound
+		}
+		return conflict
+	}
+
+	if err := insertAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	return nil
 }
 
-// ExistsByDocument implementa tenant.Repository.ExistsByDocument
-func (r *TenantRepository) ExistsByDocument(ctx context.Context, document string) (bool, error) {
-	var exists bool
-	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM tenants WHERE document = $1)", document).Scan(&exists)
+// ListPurgeable implementa tenant.Repository.ListPurgeable
+func (r *TenantRepository) ListPurgeable(ctx context.Context, now time.Time) ([]*tenant.Tenant, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+tenantColumns+`
+		FROM tenants
+		WHERE deleted_at IS NOT NULL AND purge_after <= $1
+		ORDER BY purge_after`,
+		now)
 	if err != nil {
-		return false, fmt.Errorf("erro ao verificar existência de tenant por documento: %w", err)
+		return nil, fmt.Errorf("erro ao listar tenants a expurgar: %w", err)
 	}
+	defer rows.Close()
 
-	return exists, nil
+	return scanTenants(rows)
+}
+
+// Purge implementa tenant.Repository.Purge. O schema e o cadastro são removidos na mesma
+// transação: se o DROP SCHEMA falhar, o tenant continua excluído e o expurgo pode ser repetido
+func (r *TenantRepository) Purge(ctx context.Context, t *tenant.Tenant, audit *tenant.AuditEntry) error {
+	if !t.CanPurge(time.Now()) {
+		return tenant.ErrGracePeriod
+	}
+	if err := database.ValidateSchema(t.Schema); err != nil {
+		return err
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// Confirma, com o registro bloqueado, que o tenant não foi restaurado desde a listagem
+	var purgeable bool
+	err = tx.QueryRow(ctx, `
+		SELECT deleted_at IS NOT NULL AND purge_after <= $2
+		FROM tenants
+		WHERE id = $1
+		FOR UPDATE`,
+		t.ID, time.Now()).Scan(&purgeable)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrTenantNotFound
+		}
+		return fmt.Errorf("erro ao buscar tenant: %w", err)
+	}
+	if !purgeable {
+		return tenant.ErrGracePeriod
+	}
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", t.Schema)); err != nil {
+		return fmt.Errorf("erro ao remover schema do tenant: %w", err)
+	}
+	// Os logins em public.user_logins são removidos em cascata
+	if _, err := tx.Exec(ctx, "DELETE FROM tenants WHERE id = $1", t.ID); err != nil {
+		return fmt.Errorf("erro ao excluir tenant: %w", err)
+	}
+	if err := insertAudit(ctx, tx, audit); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("erro ao confirmar transação: %w", err)
+	}
+	database.InvalidateTenantSchema(t.ID)
+	return nil
+}
+
+// RecordAudit implementa tenant.Repository.RecordAudit
+func (r *TenantRepository) RecordAudit(ctx context.Context, audit *tenant.AuditEntry) error {
+	return insertAudit(ctx, r.db, audit)
+}
+
+// insertAudit grava o evento de auditoria no pool ou na transação informada
+func insertAudit(ctx context.Context, db execer, audit *tenant.AuditEntry) error {
+	details, err := json.Marshal(audit.Details)
+	if err != nil {
+		return fmt.Errorf("erro ao serializar detalhes da auditoria: %w", err)
+	}
+
+	_, err = db.Exec(ctx, `
+		INSERT INTO tenant_audit_log (id, tenant_id, tenant_name, tenant_document, schema, action, actor, details, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)`,
+		audit.ID, audit.TenantID, audit.TenantName, audit.TenantDocument, audit.Schema, string(audit.Action), audit.Actor, details, audit.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("erro ao gravar auditoria do tenant: %w", err)
+	}
+	return nil
+}
+
+// ListAudit implementa tenant.Repository.ListAudit
+func (r *TenantRepository) ListAudit(ctx context.Context, tenantID string) ([]*tenant.AuditEntry, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT id, tenant_id, tenant_name, tenant_document, schema, action, actor, details, created_at
+		FROM tenant_audit_log
+		WHERE tenant_id = $1
+		ORDER BY created_at, id`,
+		tenantID)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar auditoria do tenant: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*tenant.AuditEntry
+	for rows.Next() {
+		var e tenant.AuditEntry
+		var action string
+		var details []byte
+		err := rows.Scan(&e.ID, &e.TenantID, &e.TenantName, &e.TenantDocument, &e.Schema, &action, &e.Actor, &details, &e.CreatedAt)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler auditoria do tenant: %w", err)
+		}
+		if err := json.Unmarshal(details, &e.Details); err != nil {
+			return nil, fmt.Errorf("erro ao ler detalhes da auditoria: %w", err)
+		}
+		e.Action = tenant.AuditAction(action)
+		entries = append(entries, &e)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao percorrer resultados: %w", err)
+	}
+
+	return entries, nil
 }
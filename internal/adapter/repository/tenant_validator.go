@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/pkg/plan"
 	pkgtenant "github.com/hugohenrick/erp-supermercado/pkg/tenant"
 )
 
@@ -22,6 +24,13 @@ func NewTenantValidator(repository tenant.Repository) pkgtenant.TenantValidator
 	}
 }
 
+// NewSubscriptionProvider cria o provedor das assinaturas usadas por plan.Middleware
+func NewSubscriptionProvider(repository tenant.Repository) plan.SubscriptionProvider {
+	return &TenantValidator{
+		repository: repository,
+	}
+}
+
 // ValidateTenant verifica se um tenant existe e está ativo
 func (v *TenantValidator) ValidateTenant(tenantID string) (bool, error) {
 	// Verifica se o tenant existe
@@ -60,3 +69,12 @@ func (v *TenantValidator) Validate(c *gin.Context, tenantID string) error {
 
 	return nil
 }
+
+// Subscription implementa plan.SubscriptionProvider: o plano do tenant e a situação da assinatura
+func (v *TenantValidator) Subscription(ctx context.Context, tenantID string) (*tenant.Subscription, error) {
+	t, err := v.repository.FindByID(ctx, tenantID)
+	if err != nil {
+		return nil, err
+	}
+	return t.Subscription(time.Now()), nil
+}
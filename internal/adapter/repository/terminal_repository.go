@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/terminal"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrTerminalNotFound   = errors.New("terminal não encontrado")
+	ErrTerminalDuplicated = errors.New("já existe terminal com esse código na filial")
+	ErrTerminalBranch     = errors.New("filial do terminal não encontrada")
+)
+
+// terminalColumns são as colunas lidas por scanTerminal, na mesma ordem
+const terminalColumns = "id, tenant_id, branch_id, code, name, status, created_at, updated_at"
+
+// TerminalRepository implementa a interface terminal.Repository
+type TerminalRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewTerminalRepository cria uma nova instância de TerminalRepository
+func NewTerminalRepository(db *pgxpool.Pool) terminal.Repository {
+	return &TerminalRepository{
+		db: db,
+	}
+}
+
+// Create implementa terminal.Repository.Create
+func (r *TerminalRepository) Create(ctx context.Context, t *terminal.Terminal) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		t.TenantID = scope.TenantID
+
+		if t.IsActive() {
+			if err := checkTerminalLimit(ctx, tx, scope, ""); err != nil {
+				return err
+			}
+		}
+
+		query := fmt.Sprintf("INSERT INTO %s (%s) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)", scope.Table("pdv_terminals"), terminalColumns)
+		_, err := tx.Exec(ctx, query, t.ID, t.TenantID, t.BranchID, t.Code, t.Name, string(t.Status), t.CreatedAt, t.UpdatedAt)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) {
+				switch pgErr.Code {
+				case "23505":
+					return ErrTerminalDuplicated
+				case "23503":
+					return ErrTerminalBranch
+				}
+			}
+			return fmt.Errorf("falha ao criar terminal: %w", err)
+		}
+		return nil
+	})
+}
+
+// FindByID implementa terminal.Repository.FindByID
+func (r *TerminalRepository) FindByID(ctx context.Context, id string) (*terminal.Terminal, error) {
+	var t *terminal.Terminal
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND tenant_id = $2", terminalColumns, scope.Table("pdv_terminals"))
+
+		var err error
+		t, err = scanTerminal(tx.QueryRow(ctx, query, id, scope.TenantID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrTerminalNotFound
+			}
+			return fmt.Errorf("falha ao buscar terminal: %w", err)
+		}
+		return nil
+	})
+	return t, err
+}
+
+// List implementa terminal.Repository.List
+func (r *TerminalRepository) List(ctx context.Context, filter terminal.Filter, limit, offset int) ([]*terminal.Terminal, error) {
+	var terminals []*terminal.Terminal
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		where, args := terminalFilter(scope, filter)
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE %s ORDER BY branch_id, code LIMIT $%d OFFSET $%d",
+			terminalColumns, scope.Table("pdv_terminals"), where, len(args)+1, len(args)+2)
+
+		rows, err := tx.Query(ctx, query, append(args, limit, offset)...)
+		if err != nil {
+			return fmt.Errorf("falha ao listar terminais: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			t, err := scanTerminal(rows)
+			if err != nil {
+				return fmt.Errorf("falha ao ler terminal: %w", err)
+			}
+			terminals = append(terminals, t)
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("falha ao listar terminais: %w", err)
+		}
+		return nil
+	})
+	return terminals, err
+}
+
+// Count implementa terminal.Repository.Count
+func (r *TerminalRepository) Count(ctx context.Context, filter terminal.Filter) (int, error) {
+	var count int
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		where, args := terminalFilter(scope, filter)
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", scope.Table("pdv_terminals"), where)
+		if err := tx.QueryRow(ctx, query, args...).Scan(&count); err != nil {
+			return fmt.Errorf("falha ao contar terminais: %w", err)
+		}
+		return nil
+	})
+	return count, err
+}
+
+// UpdateStatus implementa terminal.Repository.UpdateStatus
+func (r *TerminalRepository) UpdateStatus(ctx context.Context, id string, status terminal.Status) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		if status == terminal.StatusActive {
+			if err := checkTerminalLimit(ctx, tx, scope, id); err != nil {
+				return err
+			}
+		}
+
+		query := fmt.Sprintf("UPDATE %s SET status = $1, updated_at = $2 WHERE id = $3 AND tenant_id = $4", scope.Table("pdv_terminals"))
+		result, err := tx.Exec(ctx, query, string(status), time.Now(), id, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao atualizar status do terminal: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrTerminalNotFound
+		}
+		return nil
+	})
+}
+
+// checkTerminalLimit verifica o limite de terminais ativos do plano, desconsiderando o terminal
+// informado, que está sendo reativado
+func checkTerminalLimit(ctx context.Context, tx pgx.Tx, scope database.TenantScope, exceptID string) error {
+	plan, err := lockTenantPlan(ctx, tx, scope.TenantID)
+	if err != nil {
+		return err
+	}
+
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE tenant_id = $1 AND status = $2 AND id::text <> $3", scope.Table("pdv_terminals"))
+	return checkPlanLimit(ctx, tx, plan.MaxTerminals, "terminais de PDV ativos", query, scope.TenantID, string(terminal.StatusActive), exceptID)
+}
+
+// terminalFilter monta a condição WHERE dos filtros de terminais
+func terminalFilter(scope database.TenantScope, filter terminal.Filter) (string, []any) {
+	conditions := []string{"tenant_id = $1"}
+	args := []any{scope.TenantID}
+
+	if filter.BranchID != "" {
+		args = append(args, filter.BranchID)
+		conditions = append(conditions, fmt.Sprintf("branch_id = $%d", len(args)))
+	}
+	if filter.Status != "" {
+		args = append(args, string(filter.Status))
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
+
+// scanTerminal lê um terminal selecionado com terminalColumns
+func scanTerminal(row pgx.Row) (*terminal.Terminal, error) {
+	var t terminal.Terminal
+	var status string
+	if err := row.Scan(&t.ID, &t.TenantID, &t.BranchID, &t.Code, &t.Name, &status, &t.CreatedAt, &t.UpdatedAt); err != nil {
+		return nil, err
+	}
+	t.Status = terminal.Status(status)
+	return &t, nil
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/usage"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrUsageNotFound = errors.New("nenhuma medição de consumo no período")
+)
+
+// usageColumns são as colunas lidas por scanUsage, na mesma ordem. Exigem o JOIN de tenant_usage
+// (u) com tenants (t); o LEFT JOIN mantém o consumo de tenants já expurgados
+const usageColumns = `u.tenant_id, COALESCE(t.name, ''), COALESCE(t.document, ''), COALESCE(t.plan_type, ''), u.period,
+	u.fiscal_documents, u.active_users, u.branches, u.pdv_terminals,
+	u.assistant_input_tokens, u.assistant_output_tokens, u.storage_bytes, u.updated_at`
+
+// UsageRepository implementa a interface usage.Repository
+type UsageRepository struct {
+	db *pgxpool.Pool
+}
+
+// NewUsageRepository cria uma nova instância de UsageRepository
+func NewUsageRepository(db *pgxpool.Pool) usage.Repository {
+	return &UsageRepository{
+		db: db,
+	}
+}
+
+// RecordTokens implementa usage.Repository.RecordTokens
+func (r *UsageRepository) RecordTokens(ctx context.Context, tenantID string, inputTokens, outputTokens int) error {
+	_, err := r.db.Exec(ctx, `
+		INSERT INTO tenant_usage (tenant_id, period, assistant_input_tokens, assistant_output_tokens, updated_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (tenant_id, period) DO UPDATE SET
+			assistant_input_tokens = tenant_usage.assistant_input_tokens + EXCLUDED.assistant_input_tokens,
+			assistant_output_tokens = tenant_usage.assistant_output_tokens + EXCLUDED.assistant_output_tokens,
+			updated_at = EXCLUDED.updated_at`,
+		tenantID, usage.PeriodOf(time.Now()), inputTokens, outputTokens, time.Now())
+	if err != nil {
+		return fmt.Errorf("erro ao registrar tokens do assistente: %w", err)
+	}
+	return nil
+}
+
+// Collect implementa usage.Repository.Collect. Os documentos fiscais vêm dos contadores do mês,
+// que já são acumulados; as demais medidas só substituem o valor gravado se forem maiores
+func (r *UsageRepository) Collect(ctx context.Context, tenantID string) (*usage.Usage, error) {
+	period := usage.PeriodOf(time.Now())
+
+	err := database.TenantTxFor(ctx, r.db, tenantID, func(tx pgx.Tx, scope database.TenantScope) error {
+		u := usage.Usage{TenantID: tenantID, Period: period}
+
+		query := fmt.Sprintf(`
+			SELECT
+				(SELECT COALESCE(SUM(quantity), 0) FROM %s WHERE period = $1),
+				(SELECT COUNT(*) FROM %s WHERE tenant_id = $2 AND status = 'active'),
+				(SELECT COUNT(*) FROM %s WHERE tenant_id = $2 AND status = 'active'),
+				(SELECT COUNT(*) FROM %s WHERE tenant_id = $2 AND status = 'active')`,
+			scope.Table("fiscal_document_counters"), scope.Table("users"), scope.Table("branches"), scope.Table("pdv_terminals"))
+		if err := tx.QueryRow(ctx, query, period, tenantID).Scan(&u.FiscalDocuments, &u.ActiveUsers, &u.Branches, &u.Terminals); err != nil {
+			return fmt.Errorf("erro ao medir consumo do tenant: %w", err)
+		}
+
+		// Tabelas, índices e TOAST de todas as tabelas do schema
+		err := tx.QueryRow(ctx, `
+			SELECT COALESCE(SUM(pg_total_relation_size(c.oid)), 0)::BIGINT
+			FROM pg_class c
+			JOIN pg_namespace n ON n.oid = c.relnamespace
+			WHERE n.nspname = $1 AND c.relkind IN ('r', 'm')`,
+			scope.Schema).Scan(&u.StorageBytes)
+		if err != nil {
+			return fmt.Errorf("erro ao medir armazenamento do tenant: %w", err)
+		}
+
+		_, err = tx.Exec(ctx, `
+			INSERT INTO public.tenant_usage
+				(tenant_id, period, fiscal_documents, active_users, branches, pdv_terminals, storage_bytes, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+			ON CONFLICT (tenant_id, period) DO UPDATE SET
+				fiscal_documents = EXCLUDED.fiscal_documents,
+				active_users = GREATEST(tenant_usage.active_users, EXCLUDED.active_users),
+				branches = GREATEST(tenant_usage.branches, EXCLUDED.branches),
+				pdv_terminals = GREATEST(tenant_usage.pdv_terminals, EXCLUDED.pdv_terminals),
+				storage_bytes = GREATEST(tenant_usage.storage_bytes, EXCLUDED.storage_bytes),
+				updated_at = EXCLUDED.updated_at`,
+			tenantID, period, u.FiscalDocuments, u.ActiveUsers, u.Branches, u.Terminals, u.StorageBytes, time.Now())
+		if err != nil {
+			return fmt.Errorf("erro ao gravar consumo do tenant: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.Find(ctx, tenantID, period)
+}
+
+// CollectAll implementa usage.Repository.CollectAll
+func (r *UsageRepository) CollectAll(ctx context.Context) error {
+	rows, err := r.db.Query(ctx, `
+		SELECT id FROM tenants
+		WHERE deleted_at IS NULL AND LOWER(status) = 'active'
+		ORDER BY id`)
+	if err != nil {
+		return fmt.Errorf("erro ao listar tenants: %w", err)
+	}
+	tenantIDs, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return fmt.Errorf("erro ao listar tenants: %w", err)
+	}
+
+	var errs []error
+	for _, tenantID := range tenantIDs {
+		if _, err := r.Collect(ctx, tenantID); err != nil {
+			errs = append(errs, fmt.Errorf("tenant %s: %w", tenantID, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Find implementa usage.Repository.Find
+func (r *UsageRepository) Find(ctx context.Context, tenantID string, period time.Time) (*usage.Usage, error) {
+	u, err := scanUsage(r.db.QueryRow(ctx, `
+		SELECT `+usageColumns+`
+		FROM tenant_usage u
+		LEFT JOIN tenants t ON t.id = u.tenant_id
+		WHERE u.tenant_id = $1 AND u.period = $2`,
+		tenantID, usage.PeriodOf(period)))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrUsageNotFound
+		}
+		return nil, fmt.Errorf("erro ao buscar consumo do tenant: %w", err)
+	}
+	return u, nil
+}
+
+// List implementa usage.Repository.List
+func (r *UsageRepository) List(ctx context.Context, period time.Time) ([]*usage.Usage, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT `+usageColumns+`
+		FROM tenant_usage u
+		LEFT JOIN tenants t ON t.id = u.tenant_id
+		WHERE u.period = $1
+		ORDER BY t.name, u.tenant_id`,
+		usage.PeriodOf(period))
+	if err != nil {
+		return nil, fmt.Errorf("erro ao listar consumo dos tenants: %w", err)
+	}
+	defer rows.Close()
+
+	var usages []*usage.Usage
+	for rows.Next() {
+		u, err := scanUsage(rows)
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler consumo do tenant: %w", err)
+		}
+		usages = append(usages, u)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("erro ao listar consumo dos tenants: %w", err)
+	}
+	return usages, nil
+}
+
+// scanUsage lê um consumo selecionado com usageColumns
+func scanUsage(row pgx.Row) (*usage.Usage, error) {
+	var u usage.Usage
+	err := row.Scan(&u.TenantID, &u.TenantName, &u.TenantDocument, &u.PlanType, &u.Period,
+		&u.FiscalDocuments, &u.ActiveUsers, &u.Branches, &u.Terminals,
+		&u.AssistantInputTokens, &u.AssistantOutputTokens, &u.StorageBytes, &u.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &u, nil
+}
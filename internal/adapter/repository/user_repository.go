@@ -7,7 +7,7 @@ import (
 	"time"
 
 	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
-	pkgtenant "github.com/hugohenrick/erp-supermercado/pkg/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/infrastructure/database"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/jackc/pgx/v5/pgtype"
@@ -19,6 +19,7 @@ var (
 	ErrUserNotFound       = errors.New("usuário não encontrado")
 	ErrUserDuplicateEmail = errors.New("usuário com mesmo email já existe para este tenant")
 	ErrUserDatabaseError  = errors.New("erro de banco de dados")
+	ErrMembershipBranch   = errors.New("filial do vínculo não encontrada")
 )
 
 // UserRepository implementa a interface user.Repository usando PostgreSQL
@@ -33,65 +34,46 @@ func NewUserRepository(db *pgxpool.Pool) user.Repository {
 	}
 }
 
+// userColumns são as colunas lidas por scanUser, na mesma ordem
+const userColumns = `id, tenant_id, branch_id, name, email, password, role, status, last_login_at, created_at, updated_at`
+
 // Create implementa user.Repository.Create
 func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Primeiro, definir o search_path para public para garantir que acessamos os tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant a partir do tenant_id
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM public.tenants WHERE id = $1", u.TenantID).Scan(&schema)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return errors.New("tenant não encontrado")
+	return database.TenantTxFor(ctx, r.db, u.TenantID, func(tx pgx.Tx, scope database.TenantScope) error {
+		// Verificar se já existe um usuário com o mesmo email no mesmo tenant
+		var exists bool
+		checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE tenant_id = $1 AND lower(email) = lower($2))", scope.Table("users"))
+		if err := tx.QueryRow(ctx, checkQuery, u.TenantID, u.Email).Scan(&exists); err != nil {
+			return fmt.Errorf("falha ao verificar existência do usuário: %w", err)
+		}
+		if exists {
+			return ErrUserDuplicateEmail
 		}
-		return fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Debug para verificar o schema recuperado
-	fmt.Printf("DEBUG Create - Tenant ID: %s, Schema: %s\n", u.TenantID, schema)
-
-	// Verificar se já existe um usuário com o mesmo email no mesmo tenant
-	// Note que esta verificação precisa ocorrer no schema específico do tenant
-	var exists bool
-	checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s.users WHERE tenant_id = $1 AND email = $2)", schema)
-	err = conn.QueryRow(ctx, checkQuery, u.TenantID, u.Email).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("falha ao verificar existência do usuário: %w", err)
-	}
 
-	if exists {
-		return ErrUserDuplicateEmail
-	}
+		if u.Status == user.StatusActive {
+			if err := checkUserLimit(ctx, tx, scope, ""); err != nil {
+				return err
+			}
+		}
 
-	// Preparar branch_id - se for string vazia, enviar NULL para o banco
-	var branchID interface{} = nil
-	if u.BranchID != "" {
-		branchID = u.BranchID
-	}
+		return insertUser(ctx, tx, scope, u)
+	})
+}
 
-	// Inserir o usuário no schema específico do tenant
+// insertUser grava o usuário na transação do tenant e o registra no índice global de logins
+func insertUser(ctx context.Context, tx pgx.Tx, scope database.TenantScope, u *user.User) error {
 	query := fmt.Sprintf(`
-		INSERT INTO %s.users (
+		INSERT INTO %s (
 			id, tenant_id, branch_id, name, email, password, role, status, last_login_at, created_at, updated_at
 		) VALUES (
 			$1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
 		)
-	`, schema)
+	`, scope.Table("users"))
 
-	_, err = conn.Exec(ctx, query,
+	_, err := tx.Exec(ctx, query,
 		u.ID,
 		u.TenantID,
-		branchID,
+		nullIfEmpty(u.BranchID),
 		u.Name,
 		u.Email,
 		u.Password,
@@ -101,7 +83,6 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 		u.CreatedAt,
 		u.UpdatedAt,
 	)
-
 	if err != nil {
 		var pgErr *pgconn.PgError
 		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Unique violation
@@ -110,674 +91,544 @@ func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
 		return fmt.Errorf("falha ao inserir usuário: %w", err)
 	}
 
-	return nil
+	if err := upsertHomeMembership(ctx, tx, scope, u); err != nil {
+		return err
+	}
+	return upsertLogin(ctx, tx, u)
 }
 
 // FindByID implementa user.Repository.FindByID
 func (r *UserRepository) FindByID(ctx context.Context, id string) (*user.User, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return nil, errors.New("tenant ID não encontrado no contexto")
-	}
-
-	query := `
-		SELECT 
-			id, tenant_id, branch_id, name, email, password, role, status, last_login_at, created_at, updated_at
-		FROM 
-			users
-		WHERE 
-			id = $1 AND tenant_id = $2
-	`
-
-	u := &user.User{}
-	var role, status string
-	var lastLoginTime pgtype.Timestamp
-
-	err = conn.QueryRow(ctx, query, id, tenantID).Scan(
-		&u.ID,
-		&u.TenantID,
-		&u.BranchID,
-		&u.Name,
-		&u.Email,
-		&u.Password,
-		&role,
-		&status,
-		&lastLoginTime,
-		&u.CreatedAt,
-		&u.UpdatedAt,
-	)
-
+	var u *user.User
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		var err error
+		query := fmt.Sprintf("SELECT %s FROM %s WHERE id = $1 AND tenant_id = $2", userColumns, scope.Table("users"))
+		u, err = scanUser(tx.QueryRow(ctx, query, id, scope.TenantID))
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
 		}
 		return nil, fmt.Errorf("falha ao buscar usuário: %w", err)
 	}
-
-	u.Role = user.Role(role)
-	u.Status = user.Status(status)
-	if lastLoginTime.Valid {
-		u.LastLoginAt = lastLoginTime.Time
-	}
-
 	return u, nil
 }
 
-// FindByEmail implementa user.Repository.FindByEmail
+// FindByEmail implementa user.Repository.FindByEmail. O email é comparado sem diferenciar
+// maiúsculas, como no índice de logins, e usuários de tenants excluídos ou inativos não são
+// encontrados
 func (r *UserRepository) FindByEmail(ctx context.Context, tenantID, email string) (*user.User, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter o schema do tenant a partir do tenant_id
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM public.tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("tenant não encontrado")
-		}
-		return nil, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Construir a query usando o schema específico do tenant
-	query := fmt.Sprintf(`
-		SELECT 
-			id, tenant_id, branch_id, name, email, password, role, status, last_login_at, created_at, updated_at
-		FROM 
-			%s.users
-		WHERE 
-			tenant_id = $1 AND email = $2
-	`, schema)
-
-	u := &user.User{}
-	var role, status string
-	var lastLoginTime pgtype.Timestamp
-
-	err = conn.QueryRow(ctx, query, tenantID, email).Scan(
-		&u.ID,
-		&u.TenantID,
-		&u.BranchID,
-		&u.Name,
-		&u.Email,
-		&u.Password,
-		&role,
-		&status,
-		&lastLoginTime,
-		&u.CreatedAt,
-		&u.UpdatedAt,
-	)
-
+	var u *user.User
+	err := database.TenantTxFor(ctx, r.db, tenantID, func(tx pgx.Tx, scope database.TenantScope) error {
+		var err error
+		// Cadastros antigos podem repetir o email com outra caixa; a grafia exata tem prioridade
+		query := fmt.Sprintf(`
+			SELECT %s FROM %s
+			WHERE tenant_id = $1 AND lower(email) = lower($2)
+			AND EXISTS (
+				SELECT 1 FROM public.tenants t
+				WHERE t.id = $1 AND t.deleted_at IS NULL AND t.status = 'active'
+			)
+			ORDER BY email = $2 DESC
+			LIMIT 1
+		`, userColumns, scope.Table("users"))
+		u, err = scanUser(tx.QueryRow(ctx, query, tenantID, email))
+		return err
+	})
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, ErrUserNotFound
 		}
+		if errors.Is(err, ErrTenantNotFound) {
+			return nil, err
+		}
 		return nil, fmt.Errorf("falha ao buscar usuário por email: %w", err)
 	}
-
-	u.Role = user.Role(role)
-	u.Status = user.Status(status)
-	if lastLoginTime.Valid {
-		u.LastLoginAt = lastLoginTime.Time
-	}
-
 	return u, nil
 }
 
-// FindByEmailAcrossTenants implementa user.Repository.FindByEmailAcrossTenants
-func (r *UserRepository) FindByEmailAcrossTenants(ctx context.Context, email string) (*user.User, error) {
-	conn, err := r.db.Acquire(ctx)
+// FindLogins implementa user.Repository.FindLogins. Tenants excluídos ou inativos não são
+// oferecidos no login, mesmo que o índice ainda aponte para eles
+func (r *UserRepository) FindLogins(ctx context.Context, email string) ([]user.Login, error) {
+	rows, err := r.db.Query(ctx, `
+		SELECT l.email, l.tenant_id, t.name, l.user_id
+		FROM public.user_logins l
+		JOIN public.tenants t ON t.id = l.tenant_id
+		WHERE lower(l.email) = lower($1)
+		AND t.deleted_at IS NULL AND t.status = 'active'
+		ORDER BY t.name
+	`, email)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+		return nil, fmt.Errorf("falha ao buscar logins: %w", err)
 	}
-	defer conn.Release()
+	defer rows.Close()
 
-	// Primeiro, definir o search_path para public para garantir que acessamos os tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return nil, fmt.Errorf("falha ao configurar search_path: %w", err)
+	logins := make([]user.Login, 0)
+	for rows.Next() {
+		var l user.Login
+		if err := rows.Scan(&l.Email, &l.TenantID, &l.TenantName, &l.UserID); err != nil {
+			return nil, fmt.Errorf("falha ao ler login: %w", err)
+		}
+		logins = append(logins, l)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao buscar logins: %w", err)
 	}
 
-	// Obter todos os schemas de tenants
-	rows, err := conn.Query(ctx, "SELECT id, schema FROM public.tenants")
+	return logins, nil
+}
+
+// RebuildLogins implementa user.Repository.RebuildLogins. Cada tenant é reconstruído em uma
+// transação própria; schemas ainda sem a tabela de usuários são ignorados
+func (r *UserRepository) RebuildLogins(ctx context.Context) (int, error) {
+	rows, err := r.db.Query(ctx, "SELECT id, schema FROM public.tenants WHERE schema <> '' ORDER BY name")
 	if err != nil {
-		return nil, fmt.Errorf("falha ao obter schemas dos tenants: %w", err)
+		return 0, fmt.Errorf("falha ao obter schemas dos tenants: %w", err)
 	}
-	defer rows.Close()
-
-	// Lista para armazenar os schemas e IDs dos tenants
 	type tenantInfo struct {
 		ID     string
 		Schema string
 	}
 	var tenants []tenantInfo
-
-	// Processar os resultados
 	for rows.Next() {
 		var t tenantInfo
 		if err := rows.Scan(&t.ID, &t.Schema); err != nil {
-			return nil, fmt.Errorf("falha ao ler dados do tenant: %w", err)
+			rows.Close()
+			return 0, fmt.Errorf("falha ao ler dados do tenant: %w", err)
 		}
 		tenants = append(tenants, t)
-
-		// Debug: printar o ID e schema do tenant
-		fmt.Printf("DEBUG - Tenant ID: %s, Schema: %s\n", t.ID, t.Schema)
 	}
-
+	rows.Close()
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("erro ao iterar resultados dos tenants: %w", err)
+		return 0, fmt.Errorf("erro ao iterar resultados dos tenants: %w", err)
 	}
 
-	// Verificar se temos tenants para procurar
-	if len(tenants) == 0 {
-		return nil, ErrUserNotFound
-	}
-
-	// Procurar o usuário em cada schema de tenant
-	for _, tenant := range tenants {
-		// Garantir que o schema é válido
-		if tenant.Schema == "" {
-			fmt.Printf("DEBUG - Ignorando tenant %s com schema vazio\n", tenant.ID)
-			continue
+	total := 0
+	for _, t := range tenants {
+		var hasUsers bool
+		if err := r.db.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", t.Schema+".users").Scan(&hasUsers); err != nil {
+			return total, fmt.Errorf("falha ao verificar usuários do schema %s: %w", t.Schema, err)
 		}
-
-		// Query para buscar o usuário no schema específico
-		query := fmt.Sprintf(`
-			SELECT 
-				id, tenant_id, branch_id, name, email, password, role, status, last_login_at, created_at, updated_at
-			FROM 
-				%s.users
-			WHERE 
-				email = $1
-			LIMIT 1
-		`, tenant.Schema)
-
-		// Debug: printar a query completa
-		fmt.Printf("DEBUG - Executing query: %s with email: %s\n", query, email)
-
-		u := &user.User{}
-		var role, status string
-		var lastLoginTime pgtype.Timestamp
-		var branchID pgtype.Text // Usar pgtype.Text para lidar com NULL
-
-		err = conn.QueryRow(ctx, query, email).Scan(
-			&u.ID,
-			&u.TenantID,
-			&branchID,
-			&u.Name,
-			&u.Email,
-			&u.Password,
-			&role,
-			&status,
-			&lastLoginTime,
-			&u.CreatedAt,
-			&u.UpdatedAt,
-		)
-
-		if err != nil {
-			if errors.Is(err, pgx.ErrNoRows) {
-				// Se não encontrou neste tenant, continuar procurando no próximo
-				fmt.Printf("DEBUG - Usuário não encontrado no tenant %s\n", tenant.ID)
-				continue
-			}
-			// Se foi outro erro, logar para debug mas continuar tentando outros tenants
-			fmt.Printf("DEBUG - Erro ao buscar usuário em %s: %v\n", tenant.Schema, err)
+		if !hasUsers {
 			continue
 		}
 
-		// Encontrou o usuário, converter os campos
-		if branchID.Valid {
-			u.BranchID = branchID.String
-		} else {
-			u.BranchID = "" // String vazia se for NULL
-		}
-
-		u.Role = user.Role(role)
-		u.Status = user.Status(status)
-		if lastLoginTime.Valid {
-			u.LastLoginAt = lastLoginTime.Time
+		count, err := r.rebuildTenantLogins(ctx, t.ID, t.Schema)
+		if err != nil {
+			return total, fmt.Errorf("falha ao indexar logins do schema %s: %w", t.Schema, err)
 		}
-
-		fmt.Printf("DEBUG - Usuário encontrado no tenant %s\n", tenant.ID)
-
-		// Retornar o usuário encontrado
-		return u, nil
+		total += count
 	}
 
-	// Se chegou aqui, o usuário não foi encontrado em nenhum tenant
-	return nil, ErrUserNotFound
+	return total, nil
 }
 
-// FindByBranch implementa user.Repository.FindByBranch
-func (r *UserRepository) FindByBranch(ctx context.Context, branchID string, limit, offset int) ([]*user.User, error) {
-	conn, err := r.db.Acquire(ctx)
+// rebuildTenantLogins substitui os logins do tenant pelos usuários do seu schema
+func (r *UserRepository) rebuildTenantLogins(ctx context.Context, tenantID, schema string) (int, error) {
+	tx, err := r.db.Begin(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
+		return 0, err
 	}
-	defer conn.Release()
+	defer tx.Rollback(ctx)
 
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return nil, errors.New("tenant ID não encontrado no contexto")
+	if _, err := tx.Exec(ctx, "DELETE FROM public.user_logins WHERE tenant_id = $1", tenantID); err != nil {
+		return 0, err
 	}
 
-	query := `
-		SELECT 
-			id, tenant_id, branch_id, name, email, password, role, status, last_login_at, created_at, updated_at
-		FROM 
-			users
-		WHERE 
-			tenant_id = $1 AND branch_id = $2
-		ORDER BY 
-			name ASC
-		LIMIT $3 OFFSET $4
-	`
-
-	rows, err := conn.Query(ctx, query, tenantID, branchID, limit, offset)
+	result, err := tx.Exec(ctx, fmt.Sprintf(`
+		INSERT INTO public.user_logins (tenant_id, user_id, email, created_at, updated_at)
+		SELECT $1, id, email, NOW(), NOW() FROM %s.users WHERE tenant_id = $1
+	`, schema), tenantID)
 	if err != nil {
-		return nil, fmt.Errorf("falha ao listar usuários por filial: %w", err)
+		return 0, err
 	}
-	defer rows.Close()
 
-	return r.scanUserRows(rows)
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return int(result.RowsAffected()), nil
+}
+
+// FindByBranch implementa user.Repository.FindByBranch
+func (r *UserRepository) FindByBranch(ctx context.Context, branchID string, limit, offset int) ([]*user.User, error) {
+	var users []*user.User
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT %s FROM %s
+			WHERE tenant_id = $1
+				AND (branch_id = $2 OR id IN (SELECT user_id FROM %s WHERE branch_id = $2))
+			ORDER BY name ASC
+			LIMIT $3 OFFSET $4
+		`, userColumns, scope.Table("users"), scope.Table("user_branches"))
+
+		rows, err := tx.Query(ctx, query, scope.TenantID, branchID, limit, offset)
+		if err != nil {
+			return fmt.Errorf("falha ao listar usuários por filial: %w", err)
+		}
+		defer rows.Close()
+
+		users, err = r.scanUserRows(rows)
+		return err
+	})
+	return users, err
 }
 
 // List implementa user.Repository.List
 func (r *UserRepository) List(ctx context.Context, tenantID string, limit, offset int) ([]*user.User, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Primeiro, definir o search_path para public para garantir que acessamos os tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return nil, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
+	var users []*user.User
+	err := database.TenantTxFor(ctx, r.db, tenantID, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT %s FROM %s
+			WHERE tenant_id = $1
+			ORDER BY name ASC
+			LIMIT $2 OFFSET $3
+		`, userColumns, scope.Table("users"))
 
-	// Obter o schema do tenant a partir do tenant_id
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM public.tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, errors.New("tenant não encontrado")
+		rows, err := tx.Query(ctx, query, tenantID, limit, offset)
+		if err != nil {
+			return fmt.Errorf("falha ao listar usuários: %w", err)
 		}
-		return nil, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Debug para verificar o schema recuperado
-	fmt.Printf("DEBUG List - Tenant ID: %s, Schema: %s\n", tenantID, schema)
+		defer rows.Close()
 
-	// Construir a query usando o schema específico do tenant
-	query := fmt.Sprintf(`
-		SELECT 
-			id, tenant_id, branch_id, name, email, password, role, status, last_login_at, created_at, updated_at
-		FROM 
-			%s.users
-		WHERE 
-			tenant_id = $1
-		ORDER BY 
-			name ASC
-		LIMIT $2 OFFSET $3
-	`, schema)
-
-	rows, err := conn.Query(ctx, query, tenantID, limit, offset)
-	if err != nil {
-		return nil, fmt.Errorf("falha ao listar usuários: %w", err)
-	}
-	defer rows.Close()
-
-	return r.scanUserRows(rows)
+		users, err = r.scanUserRows(rows)
+		return err
+	})
+	return users, err
 }
 
 // Update implementa user.Repository.Update
 func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return errors.New("tenant ID não encontrado no contexto")
-	}
-
-	// Verificar se o usuário pertence ao tenant correto
-	if u.TenantID != tenantID {
-		return errors.New("usuário não pertence ao tenant atual")
-	}
-
-	// Verificar se já existe um usuário com o mesmo email no mesmo tenant (exceto este)
-	var exists bool
-	err = conn.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE tenant_id = $1 AND email = $2 AND id != $3)", u.TenantID, u.Email, u.ID).Scan(&exists)
-	if err != nil {
-		return fmt.Errorf("falha ao verificar existência do usuário: %w", err)
-	}
-
-	if exists {
-		return ErrUserDuplicateEmail
-	}
-
-	query := `
-		UPDATE users
-		SET 
-			branch_id = $1,
-			name = $2,
-			email = $3,
-			role = $4,
-			status = $5,
-			updated_at = $6
-		WHERE 
-			id = $7 AND tenant_id = $8
-	`
-
-	result, err := conn.Exec(ctx, query,
-		u.BranchID,
-		u.Name,
-		u.Email,
-		string(u.Role),
-		string(u.Status),
-		time.Now(),
-		u.ID,
-		u.TenantID,
-	)
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		// Verificar se o usuário pertence ao tenant correto
+		if u.TenantID != scope.TenantID {
+			return errors.New("usuário não pertence ao tenant atual")
+		}
 
-	if err != nil {
-		var pgErr *pgconn.PgError
-		if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Unique violation
+		// Verificar se já existe um usuário com o mesmo email no mesmo tenant (exceto este)
+		var exists bool
+		checkQuery := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE tenant_id = $1 AND lower(email) = lower($2) AND id != $3)", scope.Table("users"))
+		if err := tx.QueryRow(ctx, checkQuery, u.TenantID, u.Email, u.ID).Scan(&exists); err != nil {
+			return fmt.Errorf("falha ao verificar existência do usuário: %w", err)
+		}
+		if exists {
 			return ErrUserDuplicateEmail
 		}
-		return fmt.Errorf("falha ao atualizar usuário: %w", err)
-	}
 
-	if result.RowsAffected() == 0 {
-		return ErrUserNotFound
-	}
+		// O vínculo da filial principal anterior sai junto com a troca de filial
+		unlinkQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE user_id = $1 AND branch_id = (SELECT branch_id FROM %s WHERE id = $1 AND tenant_id = $2)
+		`, scope.Table("user_branches"), scope.Table("users"))
+		if _, err := tx.Exec(ctx, unlinkQuery, u.ID, u.TenantID); err != nil {
+			return fmt.Errorf("falha ao atualizar vínculos do usuário: %w", err)
+		}
 
-	return nil
+		query := fmt.Sprintf(`
+			UPDATE %s
+			SET
+				branch_id = $1,
+				name = $2,
+				email = $3,
+				role = $4,
+				status = $5,
+				updated_at = $6
+			WHERE
+				id = $7 AND tenant_id = $8
+		`, scope.Table("users"))
+
+		result, err := tx.Exec(ctx, query,
+			nullIfEmpty(u.BranchID),
+			u.Name,
+			u.Email,
+			string(u.Role),
+			string(u.Status),
+			time.Now(),
+			u.ID,
+			u.TenantID,
+		)
+		if err != nil {
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "23505" { // Unique violation
+				return ErrUserDuplicateEmail
+			}
+			return fmt.Errorf("falha ao atualizar usuário: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+
+		if err := upsertHomeMembership(ctx, tx, scope, u); err != nil {
+			return err
+		}
+		return upsertLogin(ctx, tx, u)
+	})
 }
 
 // Delete implementa user.Repository.Delete
 func (r *UserRepository) Delete(ctx context.Context, id string) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return errors.New("tenant ID não encontrado no contexto")
-	}
-
-	result, err := conn.Exec(ctx, "DELETE FROM users WHERE id = $1 AND tenant_id = $2", id, tenantID)
-	if err != nil {
-		return fmt.Errorf("falha ao excluir usuário: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return ErrUserNotFound
-	}
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		result, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE id = $1 AND tenant_id = $2", scope.Table("users")), id, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao excluir usuário: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
 
-	return nil
+		_, err = tx.Exec(ctx, "DELETE FROM public.user_logins WHERE tenant_id = $1 AND user_id = $2", scope.TenantID, id)
+		if err != nil {
+			return fmt.Errorf("falha ao remover login do usuário: %w", err)
+		}
+		return nil
+	})
 }
 
-// UpdateStatus implementa user.Repository.UpdateStatus
+// UpdateStatus implementa user.Repository.UpdateStatus. Reativar um usuário respeita o limite
+// de usuários ativos do plano
 func (r *UserRepository) UpdateStatus(ctx context.Context, id string, status user.Status) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao obter conexão: %w", err)
+	if status != user.StatusActive {
+		return r.updateUser(ctx, id, "status = $1", string(status), "falha ao atualizar status do usuário")
 	}
-	defer conn.Release()
 
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return errors.New("tenant ID não encontrado no contexto")
-	}
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		if err := checkUserLimit(ctx, tx, scope, id); err != nil {
+			return err
+		}
 
-	query := `
-		UPDATE users
-		SET 
-			status = $1,
-			updated_at = $2
-		WHERE 
-			id = $3 AND tenant_id = $4
-	`
+		query := fmt.Sprintf("UPDATE %s SET status = $1, updated_at = $2 WHERE id = $3 AND tenant_id = $4", scope.Table("users"))
+		result, err := tx.Exec(ctx, query, string(status), time.Now(), id, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao atualizar status do usuário: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
 
-	result, err := conn.Exec(ctx, query, string(status), time.Now(), id, tenantID)
+// checkUserLimit verifica o limite de usuários ativos do plano, desconsiderando o usuário
+// informado, que está sendo reativado
+func checkUserLimit(ctx context.Context, tx pgx.Tx, scope database.TenantScope, exceptID string) error {
+	plan, err := lockTenantPlan(ctx, tx, scope.TenantID)
 	if err != nil {
-		return fmt.Errorf("falha ao atualizar status do usuário: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return ErrUserNotFound
+		return err
 	}
 
-	return nil
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE tenant_id = $1 AND status = $2 AND id::text <> $3", scope.Table("users"))
+	return checkPlanLimit(ctx, tx, plan.MaxUsers, "usuários ativos", query, scope.TenantID, string(user.StatusActive), exceptID)
 }
 
 // UpdatePassword implementa user.Repository.UpdatePassword
 func (r *UserRepository) UpdatePassword(ctx context.Context, id, hashedPassword string) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return errors.New("tenant ID não encontrado no contexto")
-	}
-
-	query := `
-		UPDATE users
-		SET 
-			password = $1,
-			updated_at = $2
-		WHERE 
-			id = $3 AND tenant_id = $4
-	`
-
-	result, err := conn.Exec(ctx, query, hashedPassword, time.Now(), id, tenantID)
-	if err != nil {
-		return fmt.Errorf("falha ao atualizar senha do usuário: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return ErrUserNotFound
-	}
-
-	return nil
+	return r.updateUser(ctx, id, "password = $1", hashedPassword, "falha ao atualizar senha do usuário")
 }
 
 // UpdateLastLogin implementa user.Repository.UpdateLastLogin
 func (r *UserRepository) UpdateLastLogin(ctx context.Context, id string) error {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant ID do contexto, se disponível
-	tenantID := pkgtenant.GetTenantID(ctx)
-
-	var query string
-	var args []interface{}
-
-	if tenantID != "" {
-		// Se tiver tenant_id no contexto, usar na query
-		query = `
-			UPDATE users
-			SET 
-				last_login_at = $1
-			WHERE 
-				id = $2 AND tenant_id = $3
-		`
-		args = []interface{}{time.Now(), id, tenantID}
-	} else {
-		// Se não tiver tenant_id no contexto, buscar apenas pelo ID
-		query = `
-			UPDATE users
-			SET 
-				last_login_at = $1
-			WHERE 
-				id = $2
-		`
-		args = []interface{}{time.Now(), id}
-	}
-
-	result, err := conn.Exec(ctx, query, args...)
-	if err != nil {
-		return fmt.Errorf("falha ao atualizar último login do usuário: %w", err)
-	}
-
-	if result.RowsAffected() == 0 {
-		return ErrUserNotFound
-	}
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf("UPDATE %s SET last_login_at = $1 WHERE id = $2 AND tenant_id = $3", scope.Table("users"))
+		result, err := tx.Exec(ctx, query, time.Now(), id, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao atualizar último login do usuário: %w", err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
+}
 
-	return nil
+// updateUser altera uma coluna do usuário do tenant do contexto, atualizando também updated_at
+func (r *UserRepository) updateUser(ctx context.Context, id, set string, value any, message string) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf("UPDATE %s SET %s, updated_at = $2 WHERE id = $3 AND tenant_id = $4", scope.Table("users"), set)
+		result, err := tx.Exec(ctx, query, value, time.Now(), id, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", message, err)
+		}
+		if result.RowsAffected() == 0 {
+			return ErrUserNotFound
+		}
+		return nil
+	})
 }
 
 // CountByTenant implementa user.Repository.CountByTenant
 func (r *UserRepository) CountByTenant(ctx context.Context, tenantID string) (int, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Primeiro, definir o search_path para public para garantir que acessamos os tenants
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return 0, fmt.Errorf("falha ao configurar search_path: %w", err)
-	}
-
-	// Obter o schema do tenant a partir do tenant_id
-	var schema string
-	err = conn.QueryRow(ctx, "SELECT schema FROM public.tenants WHERE id = $1", tenantID).Scan(&schema)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return 0, errors.New("tenant não encontrado")
-		}
-		return 0, fmt.Errorf("falha ao obter schema do tenant: %w", err)
-	}
-
-	// Debug para verificar o schema recuperado
-	fmt.Printf("DEBUG CountByTenant - Tenant ID: %s, Schema: %s\n", tenantID, schema)
-
-	// Contar usuários no schema específico do tenant
 	var count int
-	query := fmt.Sprintf("SELECT COUNT(*) FROM %s.users WHERE tenant_id = $1", schema)
-	err = conn.QueryRow(ctx, query, tenantID).Scan(&count)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao contar usuários: %w", err)
-	}
-
-	return count, nil
+	err := database.TenantTxFor(ctx, r.db, tenantID, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE tenant_id = $1", scope.Table("users"))
+		if err := tx.QueryRow(ctx, query, tenantID).Scan(&count); err != nil {
+			return fmt.Errorf("falha ao contar usuários: %w", err)
+		}
+		return nil
+	})
+	return count, err
 }
 
 // CountByBranch implementa user.Repository.CountByBranch
 func (r *UserRepository) CountByBranch(ctx context.Context, branchID string) (int, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
-
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return 0, errors.New("tenant ID não encontrado no contexto")
-	}
-
 	var count int
-	err = conn.QueryRow(ctx, "SELECT COUNT(*) FROM users WHERE tenant_id = $1 AND branch_id = $2", tenantID, branchID).Scan(&count)
-	if err != nil {
-		return 0, fmt.Errorf("falha ao contar usuários da filial: %w", err)
-	}
-
-	return count, nil
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT COUNT(*) FROM %s
+			WHERE tenant_id = $1
+				AND (branch_id = $2 OR id IN (SELECT user_id FROM %s WHERE branch_id = $2))
+		`, scope.Table("users"), scope.Table("user_branches"))
+		if err := tx.QueryRow(ctx, query, scope.TenantID, branchID).Scan(&count); err != nil {
+			return fmt.Errorf("falha ao contar usuários da filial: %w", err)
+		}
+		return nil
+	})
+	return count, err
 }
 
 // Exists implementa user.Repository.Exists
 func (r *UserRepository) Exists(ctx context.Context, id string) (bool, error) {
-	conn, err := r.db.Acquire(ctx)
-	if err != nil {
-		return false, fmt.Errorf("falha ao obter conexão: %w", err)
-	}
-	defer conn.Release()
+	var exists bool
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf("SELECT EXISTS(SELECT 1 FROM %s WHERE id = $1 AND tenant_id = $2)", scope.Table("users"))
+		if err := tx.QueryRow(ctx, query, id, scope.TenantID).Scan(&exists); err != nil {
+			return fmt.Errorf("falha ao verificar existência do usuário: %w", err)
+		}
+		return nil
+	})
+	return exists, err
+}
 
-	// Obter tenant ID do contexto
-	tenantID := pkgtenant.GetTenantID(ctx)
-	if tenantID == "" {
-		return false, errors.New("tenant ID não encontrado no contexto")
+// upsertHomeMembership grava o vínculo da filial principal do usuário, com o papel do cadastro
+func upsertHomeMembership(ctx context.Context, tx pgx.Tx, scope database.TenantScope, u *user.User) error {
+	if u.BranchID == "" {
+		return nil
 	}
 
-	var exists bool
-	err = conn.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = $1 AND tenant_id = $2)", id, tenantID).Scan(&exists)
-	if err != nil {
-		return false, fmt.Errorf("falha ao verificar existência do usuário: %w", err)
+	query := fmt.Sprintf(`
+		INSERT INTO %s (user_id, branch_id, tenant_id, role, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, branch_id) DO UPDATE SET role = EXCLUDED.role
+	`, scope.Table("user_branches"))
+	if _, err := tx.Exec(ctx, query, u.ID, u.BranchID, u.TenantID, string(u.Role), time.Now()); err != nil {
+		return membershipError(err, "falha ao vincular usuário à filial principal")
 	}
+	return nil
+}
 
-	return exists, nil
+// ListMemberships implementa user.Repository.ListMemberships
+func (r *UserRepository) ListMemberships(ctx context.Context, userID string) ([]user.Membership, error) {
+	var memberships []user.Membership
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT ub.user_id, ub.branch_id, b.name, ub.role, ub.created_at
+			FROM %s ub
+			JOIN %s b ON b.id = ub.branch_id
+			WHERE ub.user_id = $1 AND ub.tenant_id = $2
+			ORDER BY b.name ASC
+		`, scope.Table("user_branches"), scope.Table("branches"))
+
+		rows, err := tx.Query(ctx, query, userID, scope.TenantID)
+		if err != nil {
+			return fmt.Errorf("falha ao listar filiais do usuário: %w", err)
+		}
+		defer rows.Close()
+
+		for rows.Next() {
+			var m user.Membership
+			var role string
+			if err := rows.Scan(&m.UserID, &m.BranchID, &m.BranchName, &role, &m.CreatedAt); err != nil {
+				return fmt.Errorf("falha ao ler filial do usuário: %w", err)
+			}
+			m.Role = user.Role(role)
+			memberships = append(memberships, m)
+		}
+		return rows.Err()
+	})
+	return memberships, err
 }
 
-// scanUserRows é um método auxiliar para processar resultados de consultas que retornam múltiplos usuários
-func (r *UserRepository) scanUserRows(rows pgx.Rows) ([]*user.User, error) {
-	var users []*user.User
+// ReplaceMemberships implementa user.Repository.ReplaceMemberships
+func (r *UserRepository) ReplaceMemberships(ctx context.Context, userID string, memberships []user.Membership) error {
+	return database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		table := scope.Table("user_branches")
+		deleteQuery := fmt.Sprintf(`
+			DELETE FROM %s
+			WHERE user_id = $1 AND tenant_id = $2
+				AND branch_id IS DISTINCT FROM (SELECT branch_id FROM %s WHERE id = $1)
+		`, table, scope.Table("users"))
+		if _, err := tx.Exec(ctx, deleteQuery, userID, scope.TenantID); err != nil {
+			return fmt.Errorf("falha ao remover filiais do usuário: %w", err)
+		}
 
-	for rows.Next() {
-		u := &user.User{}
-		var role, status string
-		var lastLoginTime pgtype.Timestamp
-		var branchID pgtype.Text // Usar pgtype.Text para lidar com NULL
-
-		err := rows.Scan(
-			&u.ID,
-			&u.TenantID,
-			&branchID,
-			&u.Name,
-			&u.Email,
-			&u.Password,
-			&role,
-			&status,
-			&lastLoginTime,
-			&u.CreatedAt,
-			&u.UpdatedAt,
-		)
+		// A filial principal já tem vínculo, com o papel do cadastro, e não é alterada aqui
+		query := fmt.Sprintf(`
+			INSERT INTO %s (user_id, branch_id, tenant_id, role, created_at)
+			VALUES ($1, $2, $3, $4, $5)
+			ON CONFLICT (user_id, branch_id) DO NOTHING
+		`, table)
+		for _, m := range memberships {
+			if _, err := tx.Exec(ctx, query, userID, m.BranchID, scope.TenantID, string(m.Role), m.CreatedAt); err != nil {
+				return membershipError(err, "falha ao vincular usuário à filial")
+			}
+		}
+		return nil
+	})
+}
 
+// FindBranchRole implementa user.Repository.FindBranchRole
+func (r *UserRepository) FindBranchRole(ctx context.Context, userID, branchID string) (user.Role, error) {
+	var role user.Role
+	err := database.TenantTx(ctx, r.db, func(tx pgx.Tx, scope database.TenantScope) error {
+		query := fmt.Sprintf(`
+			SELECT u.role, COALESCE(u.branch_id::text, ''), ub.role
+			FROM %s u
+			JOIN %s b ON b.id::text = $2
+			LEFT JOIN %s ub ON ub.user_id = u.id AND ub.branch_id = b.id
+			WHERE u.id = $1 AND u.tenant_id = $3
+		`, scope.Table("users"), scope.Table("branches"), scope.Table("user_branches"))
+
+		var baseRole, homeBranchID string
+		var membershipRole *string
+		err := tx.QueryRow(ctx, query, userID, branchID, scope.TenantID).Scan(&baseRole, &homeBranchID, &membershipRole)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return user.ErrNoBranchAccess
+		}
 		if err != nil {
-			return nil, fmt.Errorf("falha ao ler usuário: %w", err)
+			return fmt.Errorf("falha ao verificar acesso à filial: %w", err)
 		}
 
-		// Atribuir valores nulos aos campos da estrutura apenas se forem válidos
-		if branchID.Valid {
-			u.BranchID = branchID.String
-		} else {
-			u.BranchID = "" // String vazia se for NULL
+		u := &user.User{Role: user.Role(baseRole), BranchID: homeBranchID}
+		if membershipRole != nil {
+			u.Branches = []user.Membership{{UserID: userID, BranchID: branchID, Role: user.Role(*membershipRole)}}
 		}
 
-		u.Role = user.Role(role)
-		u.Status = user.Status(status)
-		if lastLoginTime.Valid {
-			u.LastLoginAt = lastLoginTime.Time
+		var ok bool
+		if role, ok = u.RoleInBranch(branchID); !ok {
+			return user.ErrNoBranchAccess
 		}
+		return nil
+	})
+	return role, err
+}
 
+// membershipError converte as violações de restrição ao gravar um vínculo com filial
+func membershipError(err error, message string) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && (pgErr.Code == "23503" || pgErr.Code == "22P02") {
+		return ErrMembershipBranch
+	}
+	return fmt.Errorf("%s: %w", message, err)
+}
+
+// scanUserRows é um método auxiliar para processar resultados de consultas que retornam múltiplos usuários
+func (r *UserRepository) scanUserRows(rows pgx.Rows) ([]*user.User, error) {
+	var users []*user.User
+
+	for rows.Next() {
+		u, err := scanUser(rows)
+		if err != nil {
+			return nil, fmt.Errorf("falha ao ler usuário: %w", err)
+		}
 		users = append(users, u)
 	}
 
@@ -788,26 +639,61 @@ func (r *UserRepository) scanUserRows(rows pgx.Rows) ([]*user.User, error) {
 	return users, nil
 }
 
-// TenantExists implementa user.Repository.TenantExists
-func (r *UserRepository) TenantExists(ctx context.Context, tenantID string) (bool, error) {
-	conn, err := r.db.Acquire(ctx)
+// scanUser lê um usuário selecionado com userColumns
+func scanUser(row pgx.Row) (*user.User, error) {
+	u := &user.User{}
+	var role, status string
+	var lastLoginTime pgtype.Timestamp
+	var branchID pgtype.Text // Usar pgtype.Text para lidar com NULL
+
+	err := row.Scan(
+		&u.ID,
+		&u.TenantID,
+		&branchID,
+		&u.Name,
+		&u.Email,
+		&u.Password,
+		&role,
+		&status,
+		&lastLoginTime,
+		&u.CreatedAt,
+		&u.UpdatedAt,
+	)
 	if err != nil {
-		return false, fmt.Errorf("falha ao obter conexão: %w", err)
+		return nil, err
 	}
-	defer conn.Release()
 
-	// Primeiro, configurar o search_path para garantir que estamos consultando o schema public
-	_, err = conn.Exec(ctx, "SET search_path TO public")
-	if err != nil {
-		return false, fmt.Errorf("falha ao configurar search_path: %w", err)
+	u.BranchID = branchID.String
+	u.Role = user.Role(role)
+	u.Status = user.Status(status)
+	if lastLoginTime.Valid {
+		u.LastLoginAt = lastLoginTime.Time
 	}
 
+	return u, nil
+}
+
+// TenantExists implementa user.Repository.TenantExists
+func (r *UserRepository) TenantExists(ctx context.Context, tenantID string) (bool, error) {
 	// Verificar se o tenant existe especificando explicitamente o schema public
 	var exists bool
-	err = conn.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM public.tenants WHERE id = $1)", tenantID).Scan(&exists)
+	err := r.db.QueryRow(ctx, "SELECT EXISTS(SELECT 1 FROM public.tenants WHERE id = $1)", tenantID).Scan(&exists)
 	if err != nil {
 		return false, fmt.Errorf("falha ao verificar existência do tenant: %w", err)
 	}
 
 	return exists, nil
 }
+
+// upsertLogin grava o email do usuário no índice global de logins
+func upsertLogin(ctx context.Context, tx pgx.Tx, u *user.User) error {
+	_, err := tx.Exec(ctx, `
+		INSERT INTO public.user_logins (tenant_id, user_id, email, created_at, updated_at)
+		VALUES ($1, $2, $3, NOW(), NOW())
+		ON CONFLICT (tenant_id, user_id) DO UPDATE SET email = EXCLUDED.email, updated_at = NOW()
+	`, u.TenantID, u.ID, u.Email)
+	if err != nil {
+		return fmt.Errorf("falha ao atualizar índice de logins: %w", err)
+	}
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/api/models"
 	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+	"github.com/hugohenrick/erp-supermercado/pkg/auth"
 	"github.com/hugohenrick/erp-supermercado/pkg/logger"
 	"github.com/hugohenrick/erp-supermercado/pkg/mcp"
 	"github.com/hugohenrick/erp-supermercado/pkg/mcp/intent"
@@ -464,6 +465,16 @@ func (h *MCPHandler) ProcessMessage(c *gin.Context) {
 		return
 	}
 
+	// Permissões do usuário na filial ativa, exigidas pelas intenções
+	permissions, err := auth.Permissions(c)
+	if err != nil {
+		h.logger.Error("Erro ao buscar permissões do usuário", "error", err)
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error": fmt.Sprintf("Erro ao verificar permissões: %v", err),
+		})
+		return
+	}
+
 	// Processar a mensagem com o contexto do usuário
 	response, err := h.mcp.ProcessWithContext(
 		context.Background(),
@@ -471,6 +482,7 @@ func (h *MCPHandler) ProcessMessage(c *gin.Context) {
 		userID,
 		tenantID,
 		userRole,
+		permissions,
 		locale,
 	)
 
@@ -0,0 +1,555 @@
+package banking
+
+import (
+	"errors"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/pkg/ofx"
+)
+
+var (
+	ErrEmptyTenantID       = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName           = errors.New("nome da conta bancária é obrigatório")
+	ErrInvalidAccountType  = errors.New("tipo de conta inválido, use checking, savings ou investment")
+	ErrAccountInactive     = errors.New("conta bancária inativa")
+	ErrAccountMismatch     = errors.New("extrato pertence a outra conta bancária")
+	ErrEmptyStatement      = errors.New("extrato sem lançamentos")
+	ErrNotPending          = errors.New("lançamento já conciliado ou ignorado")
+	ErrNotMatched          = errors.New("lançamento não está conciliado nem ignorado")
+	ErrInvalidMatchKind    = errors.New("tipo de título inválido, use receivable ou payable")
+	ErrDirectionMismatch   = errors.New("créditos só podem ser conciliados com contas a receber e débitos com contas a pagar")
+	ErrAmountMismatch      = errors.New("valor do título não confere com o lançamento")
+	ErrInvalidPeriod       = errors.New("período inválido")
+	ErrPeriodTooLong       = errors.New("período do fluxo de caixa limitado a 366 dias")
+	ErrMatchTargetRequired = errors.New("informe o título ou o pagamento a conciliar")
+)
+
+// AccountType define o tipo de conta bancária
+type AccountType string
+
+const (
+	AccountChecking   AccountType = "checking"   // Conta corrente
+	AccountSavings    AccountType = "savings"    // Poupança
+	AccountInvestment AccountType = "investment" // Aplicação
+)
+
+// TransactionStatus define a situação de conciliação de um lançamento do extrato
+type TransactionStatus string
+
+const (
+	TransactionPending TransactionStatus = "pending" // Aguardando conciliação
+	TransactionMatched TransactionStatus = "matched" // Conciliado com um título
+	TransactionIgnored TransactionStatus = "ignored" // Sem título correspondente (tarifas, transferências...)
+)
+
+// MatchKind define o tipo de título conciliado
+type MatchKind string
+
+const (
+	MatchReceivable MatchKind = "receivable"
+	MatchPayable    MatchKind = "payable"
+)
+
+// Tolerâncias usadas na conciliação automática
+const (
+	PaymentDateTolerance = 3 // Dias entre a baixa registrada e o lançamento
+	DueDateTolerance     = 5 // Dias entre o vencimento do título em aberto e o lançamento
+)
+
+// Account representa uma conta bancária do tenant
+type Account struct {
+	ID             string      `json:"id"`
+	TenantID       string      `json:"tenant_id"`
+	BranchID       string      `json:"branch_id"` // Filial dona da conta; vazio para conta da empresa
+	Name           string      `json:"name"`
+	BankCode       string      `json:"bank_code"`
+	Agency         string      `json:"agency"`
+	AccountNumber  string      `json:"account_number"`
+	Type           AccountType `json:"type"`
+	OpeningBalance float64     `json:"opening_balance"`
+	OpeningDate    time.Time   `json:"opening_date"`
+	Active         bool        `json:"active"`
+	CreatedAt      time.Time   `json:"created_at"`
+	UpdatedAt      time.Time   `json:"updated_at"`
+}
+
+// NewAccount cria uma nova conta bancária
+func NewAccount(tenantID, name, bankCode, agency, accountNumber string, accountType AccountType, openingBalance float64, openingDate time.Time) (*Account, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+
+	if openingDate.IsZero() {
+		openingDate = time.Now()
+	}
+
+	now := time.Now()
+	a := &Account{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		Name:           strings.TrimSpace(name),
+		BankCode:       bankCode,
+		Agency:         agency,
+		AccountNumber:  accountNumber,
+		Type:           accountType,
+		OpeningBalance: roundMoney(openingBalance),
+		OpeningDate:    truncateDate(openingDate),
+		Active:         true,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Validate verifica os dados obrigatórios da conta
+func (a *Account) Validate() error {
+	if a.Name == "" {
+		return ErrEmptyName
+	}
+	switch a.Type {
+	case AccountChecking, AccountSavings, AccountInvestment:
+	default:
+		return ErrInvalidAccountType
+	}
+	return nil
+}
+
+// Matches verifica se o extrato OFX pertence a esta conta, comparando apenas os dígitos do número da conta
+func (a *Account) Matches(stmt *ofx.Statement) bool {
+	if a.AccountNumber == "" || stmt.AccountID == "" {
+		return true
+	}
+	account := strings.TrimLeft(onlyDigits(a.AccountNumber), "0")
+	statement := strings.TrimLeft(onlyDigits(stmt.AccountID), "0")
+	return account == "" || statement == "" || strings.HasPrefix(statement, account) || strings.HasPrefix(account, statement)
+}
+
+// Import representa a importação de um extrato OFX
+type Import struct {
+	ID           string    `json:"id"`
+	TenantID     string    `json:"tenant_id"`
+	AccountID    string    `json:"account_id"`
+	FileName     string    `json:"file_name"`
+	StartDate    time.Time `json:"start_date"`
+	EndDate      time.Time `json:"end_date"`
+	Balance      float64   `json:"balance"` // Saldo final informado pelo banco
+	Transactions int       `json:"transactions"`
+	Duplicates   int       `json:"duplicates"` // Lançamentos já importados anteriormente
+	Matched      int       `json:"matched"`    // Conciliados automaticamente
+	ImportedBy   string    `json:"imported_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Transaction representa um lançamento do extrato bancário
+type Transaction struct {
+	ID          string            `json:"id"`
+	TenantID    string            `json:"tenant_id"`
+	AccountID   string            `json:"account_id"`
+	ImportID    string            `json:"import_id"`
+	FITID       string            `json:"fitid"`
+	Type        string            `json:"type"`
+	PostedAt    time.Time         `json:"posted_at"`
+	Amount      float64           `json:"amount"` // Positivo para créditos e negativo para débitos
+	Document    string            `json:"document"`
+	Memo        string            `json:"memo"`
+	Status      TransactionStatus `json:"status"`
+	MatchKind   MatchKind         `json:"match_kind,omitempty"`
+	TitleID     string            `json:"title_id,omitempty"`   // Título a receber ou a pagar
+	PaymentID   string            `json:"payment_id,omitempty"` // Baixa do título conciliada
+	MatchedAuto bool              `json:"matched_auto"`
+	MatchedBy   string            `json:"matched_by,omitempty"`
+	MatchedAt   *time.Time        `json:"matched_at"`
+	CreatedAt   time.Time         `json:"created_at"`
+	UpdatedAt   time.Time         `json:"updated_at"`
+	Candidates  []Candidate       `json:"candidates,omitempty"` // Sugestões para a conciliação manual
+}
+
+// NewImport cria a importação e os lançamentos a partir de um extrato OFX
+func NewImport(account *Account, fileName string, stmt *ofx.Statement, userID string) (*Import, []*Transaction, error) {
+	if !account.Active {
+		return nil, nil, ErrAccountInactive
+	}
+	if !account.Matches(stmt) {
+		return nil, nil, ErrAccountMismatch
+	}
+	if len(stmt.Transactions) == 0 {
+		return nil, nil, ErrEmptyStatement
+	}
+
+	now := time.Now()
+	imp := &Import{
+		ID:           uuid.New().String(),
+		TenantID:     account.TenantID,
+		AccountID:    account.ID,
+		FileName:     fileName,
+		StartDate:    truncateDate(stmt.StartDate),
+		EndDate:      truncateDate(stmt.EndDate),
+		Balance:      roundMoney(stmt.Balance),
+		Transactions: len(stmt.Transactions),
+		ImportedBy:   userID,
+		CreatedAt:    now,
+	}
+
+	transactions := make([]*Transaction, 0, len(stmt.Transactions))
+	for _, item := range stmt.Transactions {
+		if imp.StartDate.IsZero() || item.PostedAt.Before(imp.StartDate) {
+			imp.StartDate = truncateDate(item.PostedAt)
+		}
+		if item.PostedAt.After(imp.EndDate) {
+			imp.EndDate = truncateDate(item.PostedAt)
+		}
+
+		document := item.CheckNumber
+		if document == "" {
+			document = item.RefNumber
+		}
+		memo := strings.TrimSpace(strings.Join(nonEmpty(item.Name, item.Memo), " - "))
+
+		transactions = append(transactions, &Transaction{
+			ID:        uuid.New().String(),
+			TenantID:  account.TenantID,
+			AccountID: account.ID,
+			ImportID:  imp.ID,
+			FITID:     item.FITID,
+			Type:      item.Type,
+			PostedAt:  truncateDate(item.PostedAt),
+			Amount:    roundMoney(item.Amount),
+			Document:  document,
+			Memo:      memo,
+			Status:    TransactionPending,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	return imp, transactions, nil
+}
+
+// IsCredit indica se o lançamento é uma entrada na conta
+func (t *Transaction) IsCredit() bool {
+	return t.Amount > 0
+}
+
+// Kind retorna o tipo de título compatível com o sentido do lançamento
+func (t *Transaction) Kind() MatchKind {
+	if t.IsCredit() {
+		return MatchReceivable
+	}
+	return MatchPayable
+}
+
+// Match concilia o lançamento com a baixa de um título
+func (t *Transaction) Match(c Candidate, auto bool, userID string) error {
+	if t.Status != TransactionPending {
+		return ErrNotPending
+	}
+	if c.Kind != t.Kind() {
+		return ErrDirectionMismatch
+	}
+
+	now := time.Now()
+	t.Status = TransactionMatched
+	t.MatchKind = c.Kind
+	t.TitleID = c.TitleID
+	t.PaymentID = c.PaymentID
+	t.MatchedAuto = auto
+	t.MatchedBy = userID
+	t.MatchedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// Unmatch devolve o lançamento conciliado ou ignorado para pendente, mantendo a baixa do título
+func (t *Transaction) Unmatch() error {
+	if t.Status == TransactionPending {
+		return ErrNotMatched
+	}
+
+	t.Status = TransactionPending
+	t.MatchKind = ""
+	t.TitleID = ""
+	t.PaymentID = ""
+	t.MatchedAuto = false
+	t.MatchedBy = ""
+	t.MatchedAt = nil
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// Ignore marca o lançamento como sem título correspondente
+func (t *Transaction) Ignore(userID string) error {
+	if t.Status != TransactionPending {
+		return ErrNotPending
+	}
+
+	now := time.Now()
+	t.Status = TransactionIgnored
+	t.MatchedBy = userID
+	t.MatchedAt = &now
+	t.UpdatedAt = now
+	return nil
+}
+
+// Candidate representa um título ou baixa que pode corresponder a um lançamento
+type Candidate struct {
+	Kind           MatchKind `json:"kind"`
+	TitleID        string    `json:"title_id"`
+	PaymentID      string    `json:"payment_id,omitempty"` // Vazio para títulos em aberto, que são baixados na conciliação
+	DocumentNumber string    `json:"document_number"`
+	Description    string    `json:"description"`
+	Date           time.Time `json:"date"` // Data da baixa ou vencimento do título em aberto
+	Amount         float64   `json:"amount"`
+	Score          int       `json:"score"`
+}
+
+// IsOpenTitle indica se o candidato é um título ainda sem baixa
+func (c *Candidate) IsOpenTitle() bool {
+	return c.PaymentID == ""
+}
+
+// Rank pontua os candidatos de mesmo valor e os ordena do mais provável para o menos provável.
+// O documento do título no histórico vale 2 pontos, a mesma data vale 1 e baixas já registradas valem 1
+func Rank(t *Transaction, candidates []Candidate) []Candidate {
+	ranked := make([]Candidate, 0, len(candidates))
+	text := strings.ToUpper(t.Memo + " " + t.Document)
+	for _, c := range candidates {
+		if c.Kind != t.Kind() || math.Round(c.Amount*100) != math.Round(math.Abs(t.Amount)*100) {
+			continue
+		}
+
+		tolerance := PaymentDateTolerance
+		if c.IsOpenTitle() {
+			tolerance = DueDateTolerance
+		}
+		if days := math.Abs(t.PostedAt.Sub(truncateDate(c.Date)).Hours() / 24); days > float64(tolerance) {
+			continue
+		}
+
+		c.Score = 0
+		if containsDocument(text, c.DocumentNumber) {
+			c.Score += 2
+		}
+		if truncateDate(c.Date).Equal(t.PostedAt) {
+			c.Score++
+		}
+		if !c.IsOpenTitle() {
+			c.Score++
+		}
+		ranked = append(ranked, c)
+	}
+
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Score > ranked[j].Score
+	})
+	return ranked
+}
+
+// AutoMatch escolhe o candidato para conciliação automática.
+// Só concilia sem intervenção quando o melhor candidato é único e, para títulos em aberto, quando o documento confere
+func AutoMatch(t *Transaction, candidates []Candidate) (*Candidate, bool) {
+	ranked := Rank(t, candidates)
+	if len(ranked) == 0 {
+		return nil, false
+	}
+
+	best := ranked[0]
+	if len(ranked) > 1 && ranked[1].Score == best.Score {
+		return nil, false
+	}
+	if best.IsOpenTitle() && best.Score < 2 {
+		return nil, false
+	}
+	if len(ranked) > 1 && best.Score < 2 {
+		return nil, false
+	}
+	return &best, true
+}
+
+var documentSeparators = regexp.MustCompile(`[^0-9A-Z]+`)
+
+// containsDocument procura o número do documento como palavra inteira no histórico do lançamento
+func containsDocument(text, document string) bool {
+	document = strings.TrimLeft(strings.ToUpper(strings.TrimSpace(document)), "0")
+	if len(document) < 3 {
+		return false
+	}
+	for _, word := range documentSeparators.Split(text, -1) {
+		if strings.TrimLeft(word, "0") == document {
+			return true
+		}
+	}
+	return false
+}
+
+// CashFlowDay totaliza as entradas e saídas realizadas e previstas de um dia
+type CashFlowDay struct {
+	Date             time.Time `json:"date"`
+	Received         float64   `json:"received"`          // Recebimentos realizados
+	Paid             float64   `json:"paid"`              // Pagamentos realizados
+	ExpectedReceipts float64   `json:"expected_receipts"` // Saldo de contas a receber com vencimento no dia
+	ExpectedPayments float64   `json:"expected_payments"` // Saldo de contas a pagar com vencimento no dia
+	Net              float64   `json:"net"`               // Entradas menos saídas do dia
+	Balance          float64   `json:"balance"`           // Saldo acumulado ao final do dia
+	OverdueReceipts  float64   `json:"overdue_receipts"`  // Recebimentos vencidos trazidos para hoje
+	OverduePayments  float64   `json:"overdue_payments"`  // Pagamentos vencidos trazidos para hoje
+}
+
+// CashFlow representa o fluxo de caixa diário de um período
+type CashFlow struct {
+	From             time.Time     `json:"from"`
+	To               time.Time     `json:"to"`
+	OpeningBalance   float64       `json:"opening_balance"`
+	Received         float64       `json:"received"`
+	Paid             float64       `json:"paid"`
+	ExpectedReceipts float64       `json:"expected_receipts"`
+	ExpectedPayments float64       `json:"expected_payments"`
+	ClosingBalance   float64       `json:"closing_balance"`
+	Days             []CashFlowDay `json:"days"`
+}
+
+// CashFlowEntry é um total diário lido das baixas ou dos vencimentos em aberto
+type CashFlowEntry struct {
+	Date   time.Time
+	Amount float64
+}
+
+// CashFlowData reúne os totais usados para montar o fluxo de caixa
+type CashFlowData struct {
+	OpeningBalance   float64 // Saldo inicial das contas mais o realizado antes do período
+	Received         []CashFlowEntry
+	Paid             []CashFlowEntry
+	ExpectedReceipts []CashFlowEntry // Por vencimento, somente títulos em aberto
+	ExpectedPayments []CashFlowEntry
+}
+
+// BuildCashFlow monta o fluxo diário: realizado até hoje e previsto a partir de hoje.
+// Títulos em aberto já vencidos são projetados para hoje, quando hoje está no período
+func BuildCashFlow(from, to, today time.Time, data *CashFlowData) (*CashFlow, error) {
+	from, to, today = truncateDate(from), truncateDate(to), truncateDate(today)
+	if from.IsZero() || to.IsZero() || to.Before(from) {
+		return nil, ErrInvalidPeriod
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return nil, ErrPeriodTooLong
+	}
+
+	days := make([]CashFlowDay, 0, int(to.Sub(from).Hours()/24)+1)
+	index := make(map[string]int)
+	for day := from; !day.After(to); day = day.AddDate(0, 0, 1) {
+		index[day.Format("2006-01-02")] = len(days)
+		days = append(days, CashFlowDay{Date: day})
+	}
+
+	// find devolve o dia do período; previsões vencidas são deslocadas para hoje
+	find := func(date time.Time, projected bool) (*CashFlowDay, bool) {
+		date = truncateDate(date)
+		overdue := projected && date.Before(today)
+		if overdue {
+			date = today
+		}
+		i, ok := index[date.Format("2006-01-02")]
+		if !ok {
+			return nil, false
+		}
+		return &days[i], overdue
+	}
+
+	for _, e := range data.Received {
+		if d, _ := find(e.Date, false); d != nil {
+			d.Received += e.Amount
+		}
+	}
+	for _, e := range data.Paid {
+		if d, _ := find(e.Date, false); d != nil {
+			d.Paid += e.Amount
+		}
+	}
+	for _, e := range data.ExpectedReceipts {
+		if d, overdue := find(e.Date, true); d != nil {
+			d.ExpectedReceipts += e.Amount
+			if overdue {
+				d.OverdueReceipts += e.Amount
+			}
+		}
+	}
+	for _, e := range data.ExpectedPayments {
+		if d, overdue := find(e.Date, true); d != nil {
+			d.ExpectedPayments += e.Amount
+			if overdue {
+				d.OverduePayments += e.Amount
+			}
+		}
+	}
+
+	flow := &CashFlow{From: from, To: to, OpeningBalance: roundMoney(data.OpeningBalance), Days: days}
+	balance := flow.OpeningBalance
+	for i := range days {
+		d := &days[i]
+		d.Received = roundMoney(d.Received)
+		d.Paid = roundMoney(d.Paid)
+		d.ExpectedReceipts = roundMoney(d.ExpectedReceipts)
+		d.ExpectedPayments = roundMoney(d.ExpectedPayments)
+		d.OverdueReceipts = roundMoney(d.OverdueReceipts)
+		d.OverduePayments = roundMoney(d.OverduePayments)
+		d.Net = roundMoney(d.Received + d.ExpectedReceipts - d.Paid - d.ExpectedPayments)
+		balance = roundMoney(balance + d.Net)
+		d.Balance = balance
+
+		flow.Received += d.Received
+		flow.Paid += d.Paid
+		flow.ExpectedReceipts += d.ExpectedReceipts
+		flow.ExpectedPayments += d.ExpectedPayments
+	}
+
+	flow.Received = roundMoney(flow.Received)
+	flow.Paid = roundMoney(flow.Paid)
+	flow.ExpectedReceipts = roundMoney(flow.ExpectedReceipts)
+	flow.ExpectedPayments = roundMoney(flow.ExpectedPayments)
+	flow.ClosingBalance = balance
+	return flow, nil
+}
+
+// nonEmpty devolve apenas os textos preenchidos
+func nonEmpty(values ...string) []string {
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+// onlyDigits remove todos os caracteres não numéricos
+func onlyDigits(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// truncateDate remove o horário de uma data
+func truncateDate(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
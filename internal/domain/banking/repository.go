@@ -0,0 +1,77 @@
+package banking
+
+import (
+	"context"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/payable"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+)
+
+// TransactionFilter define os filtros para listagem de lançamentos do extrato
+type TransactionFilter struct {
+	AccountID string
+	ImportID  string
+	Status    TransactionStatus
+	From      time.Time
+	To        time.Time
+}
+
+// CashFlowFilter define o período e a filial do fluxo de caixa
+type CashFlowFilter struct {
+	BranchID string
+	From     time.Time
+	To       time.Time
+}
+
+// Settlement é a baixa de um título em aberto gravada na mesma transação da conciliação
+type Settlement struct {
+	Receivable        *receivable.Receivable
+	ReceivablePayment *receivable.Payment
+	Payable           *payable.Payable
+	PayablePayment    *payable.Payment
+}
+
+// Repository define a interface para operações de repositório de contas bancárias e conciliação
+type Repository interface {
+	// CreateAccount grava uma nova conta bancária
+	CreateAccount(ctx context.Context, account *Account) error
+
+	// UpdateAccount atualiza os dados de uma conta bancária
+	UpdateAccount(ctx context.Context, account *Account) error
+
+	// FindAccountByID busca uma conta bancária pelo ID
+	FindAccountByID(ctx context.Context, id string) (*Account, error)
+
+	// ListAccounts lista as contas bancárias do tenant
+	ListAccounts(ctx context.Context) ([]*Account, error)
+
+	// CreateImport grava a importação e os lançamentos novos, descartando os FITIDs já importados na conta.
+	// Devolve somente os lançamentos efetivamente gravados
+	CreateImport(ctx context.Context, imp *Import, transactions []*Transaction) ([]*Transaction, error)
+
+	// UpdateImportMatched atualiza o total de lançamentos conciliados automaticamente na importação
+	UpdateImportMatched(ctx context.Context, imp *Import) error
+
+	// ListImports lista as importações de extrato de uma conta
+	ListImports(ctx context.Context, accountID string, limit, offset int) ([]*Import, error)
+
+	// FindTransactionByID busca um lançamento do extrato pelo ID
+	FindTransactionByID(ctx context.Context, id string) (*Transaction, error)
+
+	// ListTransactions lista os lançamentos com filtros e paginação, ordenados por data
+	ListTransactions(ctx context.Context, filter TransactionFilter, limit, offset int) ([]*Transaction, error)
+
+	// CountTransactions conta os lançamentos que atendem aos filtros
+	CountTransactions(ctx context.Context, filter TransactionFilter) (int, error)
+
+	// FindCandidates busca baixas ainda não conciliadas e títulos em aberto com o valor e a data próximos do lançamento
+	FindCandidates(ctx context.Context, t *Transaction) ([]Candidate, error)
+
+	// UpdateMatch grava a situação de conciliação do lançamento; from é a situação esperada antes da alteração.
+	// Quando informada, a baixa do título em aberto é gravada na mesma transação
+	UpdateMatch(ctx context.Context, t *Transaction, from TransactionStatus, settlement *Settlement) error
+
+	// CashFlowData totaliza as baixas e os vencimentos em aberto usados no fluxo de caixa
+	CashFlowData(ctx context.Context, filter CashFlowFilter) (*CashFlowData, error)
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
 )
 
 var (
@@ -13,6 +14,7 @@ var (
 	ErrEmptyTenantID   = errors.New("ID do tenant não pode ser vazio")
 	ErrInvalidBranchID = errors.New("ID de filial inválido")
 	ErrBranchNotActive = errors.New("filial não está ativa")
+	ErrInvalidDocument = pkgdocument.ErrInvalid
 )
 
 // Status representa o estado da filial
@@ -61,6 +63,8 @@ type Address struct {
 	State      string `json:"state"`
 	ZipCode    string `json:"zip_code"`
 	Country    string `json:"country"`
+	CityCode   string `json:"city_code"`  // Código IBGE do município
+	StateCode  string `json:"state_code"` // Código IBGE da UF
 }
 
 // NewBranch cria uma nova filial
@@ -80,13 +84,18 @@ func NewBranch(
 		return nil, ErrEmptyName
 	}
 
+	normalized, err := NormalizeDocument(document)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Branch{
 		ID:        uuid.New().String(),
 		TenantID:  tenantID,
 		Name:      name,
 		Code:      code,
 		Type:      branchType,
-		Document:  document,
+		Document:  normalized,
 		Address:   address,
 		Phone:     phone,
 		Email:     email,
@@ -97,6 +106,14 @@ func NewBranch(
 	}, nil
 }
 
+// NormalizeDocument valida o CNPJ da filial, que é opcional, e o devolve sem máscara
+func NormalizeDocument(document string) (string, error) {
+	if document == "" {
+		return "", nil
+	}
+	return pkgdocument.ValidateCNPJ(document)
+}
+
 // IsActive verifica se a filial está ativa
 func (b *Branch) IsActive() bool {
 	return b.Status == StatusActive
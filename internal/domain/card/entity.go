@@ -0,0 +1,497 @@
+package card
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/pkg/acquirer"
+	"github.com/hugohenrick/erp-supermercado/pkg/domain"
+)
+
+var (
+	ErrEmptyTenantID          = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyBranchID          = errors.New("ID da filial não pode ser vazio")
+	ErrEmptyAcquirer          = errors.New("adquirente é obrigatória")
+	ErrEmptyBrand             = errors.New("bandeira é obrigatória")
+	ErrEmptyNSU               = errors.New("NSU da transação é obrigatório")
+	ErrInvalidProduct         = errors.New("produto inválido, use credit ou debit")
+	ErrInvalidInstallments    = errors.New("faixa de parcelas inválida")
+	ErrInvalidFee             = errors.New("taxa deve estar entre 0 e 100%")
+	ErrInvalidSettlementDays  = errors.New("prazo de liquidação não pode ser negativo")
+	ErrInvalidAmount          = errors.New("valor deve ser maior que zero")
+	ErrNotCardPayment         = errors.New("pagamento não é de cartão de crédito ou débito")
+	ErrFeeNotFound            = errors.New("nenhuma taxa cadastrada para a adquirente, bandeira, produto e parcelas")
+	ErrDebitInstallments      = errors.New("venda no débito não pode ser parcelada")
+	ErrSettlementAlreadyFinal = errors.New("parcela já liquidada por outro registro")
+	ErrRecordProcessed        = errors.New("registro já conciliado em importação anterior")
+)
+
+// Product define a modalidade da venda no cartão
+type Product string
+
+const (
+	ProductCredit Product = "credit"
+	ProductDebit  Product = "debit"
+)
+
+// SaleStatus define a situação de conciliação de uma venda no cartão
+type SaleStatus string
+
+const (
+	SalePending   SaleStatus = "pending"   // Parcelas aguardando pagamento da adquirente
+	SaleSettled   SaleStatus = "settled"   // Todas as parcelas pagas conforme a taxa contratada
+	SaleDivergent SaleStatus = "divergent" // Alguma parcela paga com valor ou taxa diferente
+)
+
+// SettlementStatus define a situação de uma parcela prevista
+type SettlementStatus string
+
+const (
+	SettlementExpected  SettlementStatus = "expected"
+	SettlementSettled   SettlementStatus = "settled"
+	SettlementDivergent SettlementStatus = "divergent"
+)
+
+// RecordStatus define o resultado da conciliação de um registro do arquivo da adquirente
+type RecordStatus string
+
+const (
+	RecordMatched   RecordStatus = "matched"   // Conferido com a parcela prevista
+	RecordDivergent RecordStatus = "divergent" // Valor bruto ou taxa diferente do previsto
+	RecordUnmatched RecordStatus = "unmatched" // Sem venda correspondente no ERP
+)
+
+// Tolerâncias da conciliação
+const (
+	AmountTolerance      = 0.01 // Diferença de arredondamento aceita entre previsto e pago
+	MissingToleranceDays = 2    // Dias após a previsão para considerar a parcela não paga (fins de semana e feriados)
+)
+
+// FeeTable representa a taxa contratada com a adquirente para uma bandeira, produto e faixa de parcelas
+type FeeTable struct {
+	ID               string    `json:"id"`
+	TenantID         string    `json:"tenant_id"`
+	Acquirer         string    `json:"acquirer"`
+	Brand            string    `json:"brand"`
+	Product          Product   `json:"product"`
+	InstallmentsFrom int       `json:"installments_from"`
+	InstallmentsTo   int       `json:"installments_to"`
+	FeePercent       float64   `json:"fee_percent"`
+	SettlementDays   int       `json:"settlement_days"` // Prazo de pagamento; no crédito, por parcela
+	Active           bool      `json:"active"`
+	CreatedAt        time.Time `json:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// NewFeeTable cria uma nova taxa de adquirente
+func NewFeeTable(tenantID, acquirerName, brand string, product Product, from, to int, feePercent float64, settlementDays int) (*FeeTable, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+
+	now := time.Now()
+	f := &FeeTable{
+		ID:               uuid.New().String(),
+		TenantID:         tenantID,
+		Acquirer:         NormalizeAcquirer(acquirerName),
+		Brand:            acquirer.NormalizeBrand(brand),
+		Product:          product,
+		InstallmentsFrom: from,
+		InstallmentsTo:   to,
+		FeePercent:       feePercent,
+		SettlementDays:   settlementDays,
+		Active:           true,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// Validate verifica os dados da taxa
+func (f *FeeTable) Validate() error {
+	if f.Acquirer == "" {
+		return ErrEmptyAcquirer
+	}
+	if f.Brand == "" {
+		return ErrEmptyBrand
+	}
+	if f.Product != ProductCredit && f.Product != ProductDebit {
+		return ErrInvalidProduct
+	}
+	if f.InstallmentsFrom < 1 || f.InstallmentsTo < f.InstallmentsFrom || f.InstallmentsTo > 99 {
+		return ErrInvalidInstallments
+	}
+	if f.Product == ProductDebit && f.InstallmentsTo > 1 {
+		return ErrDebitInstallments
+	}
+	if f.FeePercent < 0 || f.FeePercent > 100 {
+		return ErrInvalidFee
+	}
+	if f.SettlementDays < 0 {
+		return ErrInvalidSettlementDays
+	}
+	return nil
+}
+
+// Covers verifica se a taxa se aplica à venda
+func (f *FeeTable) Covers(acquirerName, brand string, product Product, installments int) bool {
+	return f.Active && f.Acquirer == acquirerName && f.Brand == brand && f.Product == product &&
+		installments >= f.InstallmentsFrom && installments <= f.InstallmentsTo
+}
+
+// FindFee escolhe a taxa aplicável à venda entre as taxas cadastradas
+func FindFee(fees []*FeeTable, acquirerName, brand string, product Product, installments int) (*FeeTable, error) {
+	for _, f := range fees {
+		if f.Covers(acquirerName, brand, product, installments) {
+			return f, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s/%s/%s/%dx", ErrFeeNotFound, acquirerName, brand, product, installments)
+}
+
+// ProductFromPaymentType identifica crédito ou débito pelo tipo de pagamento registrado no PDV
+func ProductFromPaymentType(paymentType string) (Product, error) {
+	value := strings.ToLower(paymentType)
+	switch {
+	case strings.Contains(value, "débito"), strings.Contains(value, "debito"), strings.Contains(value, "debit"):
+		return ProductDebit, nil
+	case strings.Contains(value, "crédito"), strings.Contains(value, "credito"), strings.Contains(value, "credit"):
+		return ProductCredit, nil
+	}
+	return "", ErrNotCardPayment
+}
+
+// NormalizeAcquirer padroniza o nome da adquirente
+func NormalizeAcquirer(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// Sale representa uma venda no cartão aguardando os pagamentos da adquirente
+type Sale struct {
+	ID                string       `json:"id"`
+	TenantID          string       `json:"tenant_id"`
+	BranchID          string       `json:"branch_id"`
+	SaleID            string       `json:"sale_id"`
+	PaymentID         string       `json:"payment_id"`
+	Acquirer          string       `json:"acquirer"`
+	Brand             string       `json:"brand"`
+	Product           Product      `json:"product"`
+	Installments      int          `json:"installments"`
+	Amount            float64      `json:"amount"`
+	FeePercent        float64      `json:"fee_percent"`
+	FeeAmount         float64      `json:"fee_amount"`
+	NetAmount         float64      `json:"net_amount"`
+	NSU               string       `json:"nsu"`
+	AuthorizationCode string       `json:"authorization_code"`
+	CardLastDigits    string       `json:"card_last_digits"`
+	SoldAt            time.Time    `json:"sold_at"`
+	Status            SaleStatus   `json:"status"`
+	CreatedBy         string       `json:"created_by"`
+	Settlements       []Settlement `json:"settlements"`
+	CreatedAt         time.Time    `json:"created_at"`
+	UpdatedAt         time.Time    `json:"updated_at"`
+}
+
+// NewSale registra o pagamento em cartão e gera a agenda de recebimentos prevista pela taxa contratada
+func NewSale(tenantID, branchID, acquirerName, nsu, authorizationCode string, payment domain.Payment, soldAt time.Time, fees []*FeeTable, userID string) (*Sale, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	if branchID == "" {
+		return nil, ErrEmptyBranchID
+	}
+	acquirerName = NormalizeAcquirer(acquirerName)
+	if acquirerName == "" {
+		return nil, ErrEmptyAcquirer
+	}
+	nsu = strings.TrimLeft(strings.TrimSpace(nsu), "0")
+	if nsu == "" {
+		return nil, ErrEmptyNSU
+	}
+	if payment.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	brand := acquirer.NormalizeBrand(payment.CardBrand)
+	if brand == "" {
+		return nil, ErrEmptyBrand
+	}
+
+	product, err := ProductFromPaymentType(payment.PaymentType)
+	if err != nil {
+		return nil, err
+	}
+	installments := payment.Installments
+	if installments < 1 {
+		installments = 1
+	}
+	if product == ProductDebit && installments > 1 {
+		return nil, ErrDebitInstallments
+	}
+
+	fee, err := FindFee(fees, acquirerName, brand, product, installments)
+	if err != nil {
+		return nil, err
+	}
+
+	if soldAt.IsZero() {
+		soldAt = time.Now()
+	}
+
+	now := time.Now()
+	s := &Sale{
+		ID:                uuid.New().String(),
+		TenantID:          tenantID,
+		BranchID:          branchID,
+		SaleID:            payment.SaleID,
+		PaymentID:         payment.ID,
+		Acquirer:          acquirerName,
+		Brand:             brand,
+		Product:           product,
+		Installments:      installments,
+		Amount:            roundMoney(payment.Amount),
+		FeePercent:        fee.FeePercent,
+		NSU:               nsu,
+		AuthorizationCode: strings.ToUpper(strings.TrimSpace(authorizationCode)),
+		CardLastDigits:    payment.CardLastDigits,
+		SoldAt:            soldAt,
+		Status:            SalePending,
+		CreatedBy:         userID,
+		CreatedAt:         now,
+		UpdatedAt:         now,
+	}
+	s.Settlements = schedule(s, fee, now)
+
+	for _, st := range s.Settlements {
+		s.FeeAmount += st.Fee
+		s.NetAmount += st.Net
+	}
+	s.FeeAmount = roundMoney(s.FeeAmount)
+	s.NetAmount = roundMoney(s.NetAmount)
+
+	return s, nil
+}
+
+// schedule divide a venda em parcelas; a diferença de arredondamento fica na primeira parcela.
+// No crédito cada parcela é paga após o prazo multiplicado pelo número da parcela (D+30, D+60...)
+func schedule(s *Sale, fee *FeeTable, now time.Time) []Settlement {
+	installment := math.Floor(s.Amount/float64(s.Installments)*100) / 100
+	first := roundMoney(s.Amount - installment*float64(s.Installments-1))
+	soldDate := truncateDate(s.SoldAt)
+
+	settlements := make([]Settlement, 0, s.Installments)
+	for i := 1; i <= s.Installments; i++ {
+		gross := installment
+		if i == 1 {
+			gross = first
+		}
+		days := fee.SettlementDays
+		if s.Product == ProductCredit {
+			days = fee.SettlementDays * i
+		}
+		feeAmount := roundMoney(gross * fee.FeePercent / 100)
+
+		settlements = append(settlements, Settlement{
+			ID:           uuid.New().String(),
+			TenantID:     s.TenantID,
+			CardSaleID:   s.ID,
+			Installment:  i,
+			ExpectedDate: soldDate.AddDate(0, 0, days),
+			Gross:        gross,
+			Fee:          feeAmount,
+			Net:          roundMoney(gross - feeAmount),
+			Status:       SettlementExpected,
+			UpdatedAt:    now,
+		})
+	}
+	return settlements
+}
+
+// Settlement representa uma parcela a receber da adquirente
+type Settlement struct {
+	ID           string           `json:"id"`
+	TenantID     string           `json:"tenant_id"`
+	CardSaleID   string           `json:"card_sale_id"`
+	Installment  int              `json:"installment"`
+	ExpectedDate time.Time        `json:"expected_date"`
+	Gross        float64          `json:"gross"`
+	Fee          float64          `json:"fee"`
+	Net          float64          `json:"net"`
+	Status       SettlementStatus `json:"status"`
+	PaidDate     *time.Time       `json:"paid_date"`
+	PaidGross    float64          `json:"paid_gross"`
+	PaidFee      float64          `json:"paid_fee"`
+	PaidNet      float64          `json:"paid_net"`
+	Difference   float64          `json:"difference"` // Líquido previsto menos o pago; positivo quando a adquirente pagou a menos
+	RecordID     string           `json:"record_id,omitempty"`
+	Message      string           `json:"message,omitempty"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+
+	// Dados da venda, preenchidos nas listagens
+	Acquirer string `json:"acquirer,omitempty"`
+	Brand    string `json:"brand,omitempty"`
+	NSU      string `json:"nsu,omitempty"`
+	BranchID string `json:"branch_id,omitempty"`
+}
+
+// Reconcile confere o pagamento informado pela adquirente com a parcela prevista
+func (s *Settlement) Reconcile(r *Record) error {
+	if s.Status != SettlementExpected {
+		return ErrSettlementAlreadyFinal
+	}
+
+	paidDate := r.PaymentDate
+	if paidDate.IsZero() {
+		paidDate = truncateDate(time.Now())
+	}
+
+	s.PaidDate = &paidDate
+	s.PaidGross = r.Gross
+	s.PaidFee = r.Fee
+	s.PaidNet = r.Net
+	s.Difference = roundMoney(s.Net - r.Net)
+	s.RecordID = r.ID
+	s.UpdatedAt = time.Now()
+
+	switch {
+	case math.Abs(r.Gross-s.Gross) > AmountTolerance:
+		s.Status = SettlementDivergent
+		s.Message = fmt.Sprintf("valor bruto pago %.2f difere do previsto %.2f", r.Gross, s.Gross)
+	case math.Abs(r.Fee-s.Fee) > AmountTolerance:
+		s.Status = SettlementDivergent
+		s.Message = fmt.Sprintf("taxa cobrada %.2f difere da contratada %.2f", r.Fee, s.Fee)
+	default:
+		s.Status = SettlementSettled
+		s.Message = ""
+	}
+
+	r.SettlementID = s.ID
+	r.Message = s.Message
+	r.Status = RecordMatched
+	if s.Status == SettlementDivergent {
+		r.Status = RecordDivergent
+	}
+	return nil
+}
+
+// MissingCutoff retorna a data limite: parcelas previstas antes dela e ainda não pagas são consideradas faltantes
+func MissingCutoff(today time.Time) time.Time {
+	return truncateDate(today).AddDate(0, 0, -MissingToleranceDays)
+}
+
+// ImportFile representa a importação de um arquivo de liquidação da adquirente
+type ImportFile struct {
+	ID         string          `json:"id"`
+	TenantID   string          `json:"tenant_id"`
+	Acquirer   string          `json:"acquirer"`
+	Format     acquirer.Format `json:"format"`
+	FileName   string          `json:"file_name"`
+	Records    int             `json:"records"`
+	Matched    int             `json:"matched"`
+	Divergent  int             `json:"divergent"`
+	Unmatched  int             `json:"unmatched"`
+	Duplicates int             `json:"duplicates"` // Registros já conciliados em importações anteriores
+	ImportedBy string          `json:"imported_by"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
+
+// NewImportFile cria a importação e os registros a conciliar
+func NewImportFile(tenantID, acquirerName string, format acquirer.Format, fileName string, items []acquirer.Record, userID string) (*ImportFile, []*Record, error) {
+	if tenantID == "" {
+		return nil, nil, ErrEmptyTenantID
+	}
+	acquirerName = NormalizeAcquirer(acquirerName)
+	if acquirerName == "" {
+		return nil, nil, ErrEmptyAcquirer
+	}
+
+	now := time.Now()
+	f := &ImportFile{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		Acquirer:   acquirerName,
+		Format:     format,
+		FileName:   fileName,
+		Records:    len(items),
+		ImportedBy: userID,
+		CreatedAt:  now,
+	}
+
+	records := make([]*Record, 0, len(items))
+	for _, item := range items {
+		records = append(records, &Record{
+			ID:                uuid.New().String(),
+			TenantID:          tenantID,
+			FileID:            f.ID,
+			Acquirer:          acquirerName,
+			Line:              item.Line,
+			SaleDate:          item.SaleDate,
+			PaymentDate:       item.PaymentDate,
+			NSU:               item.NSU,
+			AuthorizationCode: item.AuthorizationCode,
+			Brand:             item.Brand,
+			Installment:       item.Installment,
+			Installments:      item.Installments,
+			Gross:             item.Gross,
+			Fee:               item.Fee,
+			Net:               item.Net,
+			Status:            RecordUnmatched,
+			CreatedAt:         now,
+		})
+	}
+
+	return f, records, nil
+}
+
+// Count soma o resultado da conciliação de um registro aos totais da importação
+func (f *ImportFile) Count(r *Record) {
+	switch r.Status {
+	case RecordMatched:
+		f.Matched++
+	case RecordDivergent:
+		f.Divergent++
+	default:
+		f.Unmatched++
+	}
+}
+
+// Record representa um pagamento informado no arquivo da adquirente
+type Record struct {
+	ID                string       `json:"id"`
+	TenantID          string       `json:"tenant_id"`
+	FileID            string       `json:"file_id"`
+	Acquirer          string       `json:"acquirer"`
+	Line              int          `json:"line"`
+	SaleDate          time.Time    `json:"sale_date"`
+	PaymentDate       time.Time    `json:"payment_date"`
+	NSU               string       `json:"nsu"`
+	AuthorizationCode string       `json:"authorization_code"`
+	Brand             string       `json:"brand"`
+	Installment       int          `json:"installment"`
+	Installments      int          `json:"installments"`
+	Gross             float64      `json:"gross"`
+	Fee               float64      `json:"fee"`
+	Net               float64      `json:"net"`
+	SettlementID      string       `json:"settlement_id,omitempty"`
+	Status            RecordStatus `json:"status"`
+	Message           string       `json:"message,omitempty"`
+	CreatedAt         time.Time    `json:"created_at"`
+}
+
+// truncateDate remove o horário de uma data
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,73 @@
+package card
+
+import (
+	"context"
+	"time"
+)
+
+// SaleFilter define os filtros para listagem de vendas no cartão
+type SaleFilter struct {
+	BranchID string
+	Acquirer string
+	Status   SaleStatus
+	From     time.Time
+	To       time.Time
+}
+
+// SettlementFilter define os filtros para a agenda de recebimentos
+type SettlementFilter struct {
+	BranchID string
+	Acquirer string
+	Status   SettlementStatus
+	From     time.Time // Data prevista inicial
+	To       time.Time // Data prevista final
+}
+
+// Repository define a interface para operações de repositório de conciliação de cartões
+type Repository interface {
+	// CreateFeeTable grava uma nova taxa de adquirente
+	CreateFeeTable(ctx context.Context, fee *FeeTable) error
+
+	// UpdateFeeTable atualiza uma taxa de adquirente
+	UpdateFeeTable(ctx context.Context, fee *FeeTable) error
+
+	// FindFeeTableByID busca uma taxa pelo ID
+	FindFeeTableByID(ctx context.Context, id string) (*FeeTable, error)
+
+	// ListFeeTables lista as taxas, opcionalmente de uma adquirente
+	ListFeeTables(ctx context.Context, acquirer string) ([]*FeeTable, error)
+
+	// CreateSale grava a venda no cartão e sua agenda de recebimentos
+	CreateSale(ctx context.Context, sale *Sale) error
+
+	// FindSaleByID busca uma venda no cartão com as parcelas previstas
+	FindSaleByID(ctx context.Context, id string) (*Sale, error)
+
+	// ListSales lista as vendas no cartão com filtros e paginação
+	ListSales(ctx context.Context, filter SaleFilter, limit, offset int) ([]*Sale, error)
+
+	// CountSales conta as vendas no cartão que atendem aos filtros
+	CountSales(ctx context.Context, filter SaleFilter) (int, error)
+
+	// ListSettlements lista a agenda de recebimentos com filtros e paginação, ordenada pela data prevista
+	ListSettlements(ctx context.Context, filter SettlementFilter, limit, offset int) ([]*Settlement, error)
+
+	// CountSettlements conta as parcelas que atendem aos filtros
+	CountSettlements(ctx context.Context, filter SettlementFilter) (int, error)
+
+	// CreateFile grava a importação de um arquivo da adquirente
+	CreateFile(ctx context.Context, file *ImportFile) error
+
+	// UpdateFileTotals grava os totais da conciliação da importação
+	UpdateFileTotals(ctx context.Context, file *ImportFile) error
+
+	// ListFiles lista as importações de arquivos das adquirentes
+	ListFiles(ctx context.Context, limit, offset int) ([]*ImportFile, error)
+
+	// ReconcileRecord grava o registro e o confere com a parcela prevista em uma única transação.
+	// Registros já conciliados em outra importação retornam ErrRecordProcessed sem efeito
+	ReconcileRecord(ctx context.Context, record *Record) error
+
+	// ListRecords lista os registros de uma importação, opcionalmente por situação
+	ListRecords(ctx context.Context, fileID string, status RecordStatus) ([]*Record, error)
+}
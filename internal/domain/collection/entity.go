@@ -0,0 +1,303 @@
+package collection
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/pkg/boleto"
+	"github.com/hugohenrick/erp-supermercado/pkg/cnab"
+)
+
+var (
+	ErrEmptyTenantID      = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName          = errors.New("nome da conta de cobrança é obrigatório")
+	ErrEmptyAgency        = errors.New("agência é obrigatória")
+	ErrEmptyAccount       = errors.New("conta é obrigatória")
+	ErrEmptyWallet        = errors.New("carteira é obrigatória")
+	ErrEmptyBeneficiary   = errors.New("nome e documento do beneficiário são obrigatórios")
+	ErrInvalidLayout      = errors.New("layout CNAB inválido, use 240 ou 400")
+	ErrAccountInactive    = errors.New("conta de cobrança inativa")
+	ErrReceivableNotOpen  = errors.New("título a receber não está em aberto")
+	ErrBoletoAlreadyFinal = errors.New("boleto já liquidado ou cancelado")
+)
+
+// BoletoStatus representa a situação de um boleto junto ao banco
+type BoletoStatus string
+
+const (
+	BoletoIssued     BoletoStatus = "issued"     // Emitido, aguardando remessa
+	BoletoRemitted   BoletoStatus = "remitted"   // Enviado em arquivo de remessa
+	BoletoRegistered BoletoStatus = "registered" // Entrada confirmada pelo banco
+	BoletoRejected   BoletoStatus = "rejected"   // Entrada rejeitada pelo banco
+	BoletoPaid       BoletoStatus = "paid"       // Liquidado
+	BoletoCancelled  BoletoStatus = "cancelled"  // Baixado
+)
+
+// Account representa uma conta de cobrança registrada (convênio com o banco)
+type Account struct {
+	ID                  string      `json:"id"`
+	TenantID            string      `json:"tenant_id"`
+	Name                string      `json:"name"`
+	BankCode            string      `json:"bank_code"`
+	Agency              string      `json:"agency"`
+	AgencyDigit         string      `json:"agency_digit"`
+	Account             string      `json:"account"`
+	AccountDigit        string      `json:"account_digit"`
+	Wallet              string      `json:"wallet"`    // Carteira
+	Agreement           string      `json:"agreement"` // Convênio / código do beneficiário
+	BeneficiaryName     string      `json:"beneficiary_name"`
+	BeneficiaryDocument string      `json:"beneficiary_document"`
+	Layout              cnab.Layout `json:"layout"`
+	InterestRate        float64     `json:"interest_rate"` // Juros de mora ao mês, em percentual
+	FinePercent         float64     `json:"fine_percent"`  // Multa por atraso, em percentual
+	Instructions        string      `json:"instructions"`  // Instruções impressas no boleto, uma por linha
+	NextOurNumber       int64       `json:"next_our_number"`
+	NextRemittance      int         `json:"next_remittance"`
+	Active              bool        `json:"active"`
+	CreatedAt           time.Time   `json:"created_at"`
+	UpdatedAt           time.Time   `json:"updated_at"`
+}
+
+// NewAccount cria uma nova conta de cobrança
+func NewAccount(tenantID, name, bankCode, agency, account, wallet, beneficiaryName, beneficiaryDocument string, layout cnab.Layout) (*Account, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+
+	a := &Account{
+		ID:                  uuid.New().String(),
+		TenantID:            tenantID,
+		Name:                strings.TrimSpace(name),
+		BankCode:            bankCode,
+		Agency:              agency,
+		Account:             account,
+		Wallet:              wallet,
+		Layout:              layout,
+		BeneficiaryName:     strings.TrimSpace(beneficiaryName),
+		BeneficiaryDocument: beneficiaryDocument,
+		NextOurNumber:       1,
+		NextRemittance:      1,
+		Active:              true,
+		CreatedAt:           time.Now(),
+		UpdatedAt:           time.Now(),
+	}
+
+	if err := a.Validate(); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// Validate verifica os dados obrigatórios e se o banco suporta o layout escolhido
+func (a *Account) Validate() error {
+	if a.Name == "" {
+		return ErrEmptyName
+	}
+	if _, err := boleto.BankByCode(a.BankCode); err != nil {
+		return err
+	}
+	if a.Agency == "" {
+		return ErrEmptyAgency
+	}
+	if a.Account == "" {
+		return ErrEmptyAccount
+	}
+	if a.Wallet == "" {
+		return ErrEmptyWallet
+	}
+	if a.BeneficiaryName == "" || a.BeneficiaryDocument == "" {
+		return ErrEmptyBeneficiary
+	}
+	if a.Layout != cnab.Layout240 && a.Layout != cnab.Layout400 {
+		return ErrInvalidLayout
+	}
+	return nil
+}
+
+// Company converte a conta para os dados do beneficiário usados nos arquivos CNAB
+func (a *Account) Company() cnab.Company {
+	return cnab.Company{
+		Name:         a.BeneficiaryName,
+		Document:     a.BeneficiaryDocument,
+		Agency:       a.Agency,
+		AgencyDigit:  a.AgencyDigit,
+		Account:      a.Account,
+		AccountDigit: a.AccountDigit,
+		Wallet:       a.Wallet,
+		Agreement:    a.Agreement,
+	}
+}
+
+// InterestPerDay calcula o valor de juros por dia de atraso a partir da taxa mensal
+func (a *Account) InterestPerDay(amount float64) float64 {
+	return math.Round(amount*a.InterestRate/100/30*100) / 100
+}
+
+// InstructionLines retorna as instruções configuradas, uma por linha
+func (a *Account) InstructionLines() []string {
+	lines := make([]string, 0)
+	for _, line := range strings.Split(a.Instructions, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// Boleto representa um boleto emitido para um título a receber
+type Boleto struct {
+	ID             string       `json:"id"`
+	TenantID       string       `json:"tenant_id"`
+	AccountID      string       `json:"account_id"`
+	ReceivableID   string       `json:"receivable_id"`
+	CustomerID     string       `json:"customer_id"`
+	OurNumber      string       `json:"our_number"` // Sequencial sem DV
+	OurNumberDigit string       `json:"our_number_digit"`
+	PrintedNumber  string       `json:"printed_number"` // Nosso número como impresso
+	DocumentNumber string       `json:"document_number"`
+	Barcode        string       `json:"barcode"`
+	DigitableLine  string       `json:"digitable_line"`
+	Amount         float64      `json:"amount"`
+	IssueDate      time.Time    `json:"issue_date"`
+	DueDate        time.Time    `json:"due_date"`
+	Status         BoletoStatus `json:"status"`
+	RemittanceID   string       `json:"remittance_id"`
+	Occurrence     string       `json:"occurrence"` // Última ocorrência informada pelo banco
+	PaidAmount     float64      `json:"paid_amount"`
+	PaidAt         *time.Time   `json:"paid_at"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+// NewBoleto cria um boleto com nosso número, código de barras e linha digitável
+func NewBoleto(account *Account, receivableID, customerID, documentNumber, ourNumber string, amount float64, issueDate, dueDate time.Time) (*Boleto, error) {
+	if !account.Active {
+		return nil, ErrAccountInactive
+	}
+
+	b := &Boleto{
+		ID:             uuid.New().String(),
+		TenantID:       account.TenantID,
+		AccountID:      account.ID,
+		ReceivableID:   receivableID,
+		CustomerID:     customerID,
+		OurNumber:      ourNumber,
+		DocumentNumber: documentNumber,
+		Amount:         amount,
+		IssueDate:      issueDate,
+		DueDate:        dueDate,
+		Status:         BoletoIssued,
+		CreatedAt:      time.Now(),
+		UpdatedAt:      time.Now(),
+	}
+
+	result, err := boleto.Generate(b.Document(account, boleto.Party{}))
+	if err != nil {
+		return nil, err
+	}
+
+	b.Barcode = result.Barcode
+	b.DigitableLine = result.DigitableLine
+	b.PrintedNumber = result.OurNumber
+	b.OurNumberDigit = result.OurNumberDigit
+	return b, nil
+}
+
+// Document monta os dados do boleto para geração do código de barras e do PDF
+func (b *Boleto) Document(account *Account, payer boleto.Party) *boleto.Boleto {
+	return &boleto.Boleto{
+		BankCode:       account.BankCode,
+		Agency:         account.Agency,
+		AgencyDigit:    account.AgencyDigit,
+		Account:        account.Account,
+		AccountDigit:   account.AccountDigit,
+		Wallet:         account.Wallet,
+		Agreement:      account.Agreement,
+		OurNumber:      b.OurNumber,
+		DocumentNumber: b.DocumentNumber,
+		Amount:         b.Amount,
+		IssueDate:      b.IssueDate,
+		DueDate:        b.DueDate,
+		Beneficiary: boleto.Party{
+			Name:     account.BeneficiaryName,
+			Document: account.BeneficiaryDocument,
+		},
+		Payer:        payer,
+		Instructions: account.InstructionLines(),
+	}
+}
+
+// Result retorna a representação numérica já gravada do boleto
+func (b *Boleto) Result() *boleto.Result {
+	return &boleto.Result{
+		Barcode:        b.Barcode,
+		DigitableLine:  b.DigitableLine,
+		OurNumber:      b.PrintedNumber,
+		OurNumberDigit: b.OurNumberDigit,
+	}
+}
+
+// IsFinal verifica se o boleto não aceita mais alterações de situação
+func (b *Boleto) IsFinal() bool {
+	return b.Status == BoletoPaid || b.Status == BoletoCancelled
+}
+
+// Remittance representa um arquivo de remessa gerado para o banco
+type Remittance struct {
+	ID        string      `json:"id"`
+	TenantID  string      `json:"tenant_id"`
+	AccountID string      `json:"account_id"`
+	Layout    cnab.Layout `json:"layout"`
+	Sequence  int         `json:"sequence"`
+	FileName  string      `json:"file_name"`
+	Content   []byte      `json:"-"`
+	Titles    int         `json:"titles"`
+	Amount    float64     `json:"amount"`
+	BoletoIDs []string    `json:"boleto_ids"`
+	CreatedBy string      `json:"created_by"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ReturnResult resume o processamento de um arquivo de retorno
+type ReturnResult struct {
+	Layout     cnab.Layout        `json:"layout"`
+	Entries    int                `json:"entries"`
+	Settled    []SettledEntry     `json:"settled"`
+	Registered []string           `json:"registered"` // Nossos números com entrada confirmada
+	Rejected   []string           `json:"rejected"`   // Nossos números com entrada rejeitada
+	Unmatched  []cnab.ReturnEntry `json:"unmatched"`  // Registros sem boleto correspondente
+	Failed     []FailedEntry      `json:"failed"`     // Registros encontrados que não puderam ser baixados
+	Ignored    []cnab.ReturnEntry `json:"ignored"`    // Ocorrências sem tratamento automático
+}
+
+// SettledEntry representa um boleto liquidado pelo retorno
+type SettledEntry struct {
+	BoletoID     string  `json:"boleto_id"`
+	ReceivableID string  `json:"receivable_id"`
+	OurNumber    string  `json:"our_number"`
+	PaidAmount   float64 `json:"paid_amount"`
+}
+
+// FailedEntry representa um registro que não pôde ser processado
+type FailedEntry struct {
+	Entry cnab.ReturnEntry `json:"entry"`
+	Error string           `json:"error"`
+}
+
+// NewReturnResult cria um resumo vazio de processamento de retorno
+func NewReturnResult(layout cnab.Layout, entries int) *ReturnResult {
+	return &ReturnResult{
+		Layout:     layout,
+		Entries:    entries,
+		Settled:    []SettledEntry{},
+		Registered: []string{},
+		Rejected:   []string{},
+		Unmatched:  []cnab.ReturnEntry{},
+		Failed:     []FailedEntry{},
+		Ignored:    []cnab.ReturnEntry{},
+	}
+}
@@ -0,0 +1,56 @@
+package collection
+
+import (
+	"context"
+)
+
+// BoletoFilter define os filtros para listagem de boletos
+type BoletoFilter struct {
+	AccountID    string
+	ReceivableID string
+	CustomerID   string
+	Status       BoletoStatus
+}
+
+// Repository define a interface para operações de repositório de cobrança bancária
+type Repository interface {
+	// CreateAccount cria uma nova conta de cobrança
+	CreateAccount(ctx context.Context, a *Account) error
+
+	// UpdateAccount atualiza os dados de uma conta de cobrança
+	UpdateAccount(ctx context.Context, a *Account) error
+
+	// FindAccountByID busca uma conta de cobrança pelo ID
+	FindAccountByID(ctx context.Context, id string) (*Account, error)
+
+	// ListAccounts lista as contas de cobrança do tenant
+	ListAccounts(ctx context.Context) ([]*Account, error)
+
+	// CreateBoleto reserva o próximo nosso número da conta e grava o boleto montado por build
+	CreateBoleto(ctx context.Context, accountID string, build func(a *Account, ourNumber int64) (*Boleto, error)) (*Boleto, error)
+
+	// FindBoletoByID busca um boleto pelo ID
+	FindBoletoByID(ctx context.Context, id string) (*Boleto, error)
+
+	// FindBoletoByOurNumber busca um boleto pelo nosso número dentro da conta
+	FindBoletoByOurNumber(ctx context.Context, accountID, ourNumber string) (*Boleto, error)
+
+	// ListBoletos lista os boletos com filtros e paginação
+	ListBoletos(ctx context.Context, filter BoletoFilter, limit, offset int) ([]*Boleto, error)
+
+	// CountBoletos conta os boletos que atendem aos filtros
+	CountBoletos(ctx context.Context, filter BoletoFilter) (int, error)
+
+	// UpdateBoletoStatus grava a situação, a ocorrência e os dados de pagamento do boleto
+	UpdateBoletoStatus(ctx context.Context, b *Boleto) error
+
+	// CreateRemittance reserva a sequência de remessa da conta, monta o arquivo com os boletos
+	// pendentes por meio de build e marca os boletos como enviados
+	CreateRemittance(ctx context.Context, accountID string, build func(a *Account, sequence int, boletos []*Boleto) (*Remittance, error)) (*Remittance, error)
+
+	// FindRemittanceByID busca um arquivo de remessa pelo ID, incluindo o conteúdo
+	FindRemittanceByID(ctx context.Context, id string) (*Remittance, error)
+
+	// ListRemittances lista os arquivos de remessa de uma conta
+	ListRemittances(ctx context.Context, accountID string, limit, offset int) ([]*Remittance, error)
+}
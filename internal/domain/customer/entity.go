@@ -5,12 +5,13 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
 )
 
 var (
 	ErrEmptyName       = errors.New("nome não pode ser vazio")
 	ErrEmptyDocument   = errors.New("documento não pode ser vazio")
-	ErrInvalidDocument = errors.New("documento inválido")
+	ErrInvalidDocument = pkgdocument.ErrInvalid
 	ErrInvalidEmail    = errors.New("email inválido")
 )
 
@@ -127,7 +128,10 @@ func NewCustomer(
 		return nil, ErrEmptyDocument
 	}
 
-	// Aqui poderia haver validação de CPF/CNPJ
+	normalized, personType, err := NormalizeDocument(document, personType)
+	if err != nil {
+		return nil, err
+	}
 
 	now := time.Now()
 	return &Customer{
@@ -136,13 +140,39 @@ func NewCustomer(
 		BranchID:   branchID,
 		PersonType: personType,
 		Name:       name,
-		Document:   document,
+		Document:   normalized,
 		Status:     StatusActive,
 		CreatedAt:  now,
 		UpdatedAt:  now,
 	}, nil
 }
 
+// NormalizeDocument valida o CPF/CNPJ e confere com o tipo de pessoa: PF exige CPF e PJ exige CNPJ.
+// Sem tipo de pessoa informado, ele é deduzido do documento
+func NormalizeDocument(document string, personType PersonType) (string, PersonType, error) {
+	var (
+		normalized string
+		err        error
+	)
+	switch personType {
+	case PersonTypePF:
+		normalized, err = pkgdocument.ValidateCPF(document)
+	case PersonTypePJ:
+		normalized, err = pkgdocument.ValidateCNPJ(document)
+	default:
+		var docType pkgdocument.Type
+		normalized, docType, err = pkgdocument.Validate(document)
+		personType = PersonTypePF
+		if docType == pkgdocument.TypeCNPJ {
+			personType = PersonTypePJ
+		}
+	}
+	if err != nil {
+		return "", "", err
+	}
+	return normalized, personType, nil
+}
+
 // IsActive verifica se o cliente está ativo
 func (c *Customer) IsActive() bool {
 	return c.Status == StatusActive
@@ -0,0 +1,431 @@
+package customer
+
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strconv"
+	"strings"
+
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
+	"github.com/hugohenrick/erp-supermercado/pkg/spreadsheet"
+)
+
+var (
+	ErrImportMissingColumn = errors.New("coluna obrigatória ausente na planilha")
+	ErrImportUnknownColumn = errors.New("campo desconhecido no mapeamento de colunas")
+	ErrImportEmpty         = errors.New("planilha sem clientes")
+)
+
+// TransferColumns são os campos da planilha de clientes, na ordem da exportação. Cada linha traz
+// um endereço e um contato; clientes com vários endereços ou contatos ocupam linhas seguidas com
+// o mesmo documento
+var TransferColumns = []string{
+	"document", "person_type", "name", "trade_name", "state_document", "city_document",
+	"tax_regime", "customer_type", "status", "credit_limit", "payment_term", "website",
+	"external_code", "reference_code", "suframa", "observations", "fiscal_notes",
+	"address_type", "street", "number", "complement", "district", "city", "state", "zip_code",
+	"country", "city_code", "state_code",
+	"contact_name", "contact_department", "contact_position", "phone", "mobile_phone", "email",
+}
+
+// columnAliases são os nomes de coluna reconhecidos sem mapeamento, já normalizados por spreadsheet.HeaderKey
+var columnAliases = map[string][]string{
+	"document":           {"cpf_cnpj", "cnpj_cpf", "cpfcnpj", "documento", "cpf", "cnpj"},
+	"person_type":        {"tipo_pessoa", "pessoa"},
+	"name":               {"nome", "razao_social", "nome_razao_social"},
+	"trade_name":         {"nome_fantasia", "fantasia"},
+	"state_document":     {"inscricao_estadual", "ie"},
+	"city_document":      {"inscricao_municipal", "im"},
+	"tax_regime":         {"regime_tributario", "regime"},
+	"customer_type":      {"tipo_cliente"},
+	"status":             {"situacao"},
+	"credit_limit":       {"limite_credito", "limite_de_credito", "limite"},
+	"payment_term":       {"prazo_pagamento", "prazo_de_pagamento", "prazo"},
+	"website":            {"site"},
+	"external_code":      {"codigo", "codigo_externo"},
+	"reference_code":     {"codigo_referencia", "referencia"},
+	"observations":       {"observacoes", "observacao", "obs"},
+	"fiscal_notes":       {"observacoes_fiscais", "obs_fiscal"},
+	"address_type":       {"tipo_endereco"},
+	"street":             {"logradouro", "endereco", "rua"},
+	"number":             {"numero", "nro"},
+	"complement":         {"complemento"},
+	"district":           {"bairro"},
+	"city":               {"cidade", "municipio"},
+	"state":              {"uf", "estado"},
+	"zip_code":           {"cep"},
+	"country":            {"pais"},
+	"city_code":          {"codigo_ibge", "codigo_municipio", "cod_municipio", "ibge"},
+	"state_code":         {"codigo_uf", "cod_uf"},
+	"contact_name":       {"contato", "nome_contato"},
+	"contact_department": {"departamento"},
+	"contact_position":   {"cargo"},
+	"phone":              {"telefone", "fone"},
+	"mobile_phone":       {"celular"},
+	"email":              {"e_mail"},
+}
+
+// ImportError descreve um problema encontrado em uma linha da planilha
+type ImportError struct {
+	Line    int    `json:"line"`
+	Column  string `json:"column,omitempty"`
+	Message string `json:"message"`
+}
+
+// ImportRecord é um cliente lido da planilha
+type ImportRecord struct {
+	Line     int           // Primeira linha do cliente na planilha
+	Document string        // Documento como veio na planilha
+	Customer *Customer     // Nulo quando a linha principal não pôde ser interpretada
+	Errors   []ImportError // Problemas de validação; o cliente só é gravado sem erros
+}
+
+// Valid indica se o cliente pode ser gravado
+func (r *ImportRecord) Valid() bool {
+	return r.Customer != nil && len(r.Errors) == 0
+}
+
+// Reject registra um problema encontrado fora da planilha, como documento já cadastrado
+func (r *ImportRecord) Reject(column, message string) {
+	r.Errors = append(r.Errors, ImportError{Line: r.Line, Column: column, Message: message})
+}
+
+// ResolveColumns localiza as colunas de cada campo no cabeçalho. O mapeamento informado
+// (campo → nome da coluna) tem precedência sobre os nomes reconhecidos automaticamente
+func ResolveColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	position := make(map[string]int, len(header))
+	for i, name := range header {
+		if key := spreadsheet.HeaderKey(name); key != "" {
+			if _, ok := position[key]; !ok {
+				position[key] = i
+			}
+		}
+	}
+
+	columns := make(map[string]int)
+	for field, name := range mapping {
+		if _, ok := columnAliases[field]; !ok && !isTransferColumn(field) {
+			return nil, fmt.Errorf("%w: %s", ErrImportUnknownColumn, field)
+		}
+		i, ok := position[spreadsheet.HeaderKey(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s (mapeada para %q)", ErrImportMissingColumn, field, name)
+		}
+		columns[field] = i
+	}
+
+	for _, field := range TransferColumns {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		for _, name := range append([]string{field}, columnAliases[field]...) {
+			if i, ok := position[name]; ok {
+				columns[field] = i
+				break
+			}
+		}
+	}
+
+	for _, field := range []string{"document", "name"} {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrImportMissingColumn, field)
+		}
+	}
+	return columns, nil
+}
+
+// ParseImport interpreta as linhas da planilha; a primeira é o cabeçalho. Linhas seguidas com o
+// mesmo documento acrescentam endereços e contatos ao cliente da linha anterior
+func ParseImport(rows [][]string, mapping map[string]string, tenantID, branchID string) ([]*ImportRecord, error) {
+	if len(rows) < 2 {
+		return nil, ErrImportEmpty
+	}
+	columns, err := ResolveColumns(rows[0], mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]*ImportRecord, 0, len(rows)-1)
+	firstLine := make(map[string]int)
+	var current *ImportRecord
+	currentKey := ""
+
+	for i, fields := range rows[1:] {
+		line := i + 2
+		get := func(field string) string {
+			col, ok := columns[field]
+			if !ok || col >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[col])
+		}
+		if blankRow(fields) {
+			continue
+		}
+
+		key := pkgdocument.Normalize(get("document"))
+		if current != nil && key != "" && key == currentKey {
+			if current.Customer != nil {
+				addRowDetails(current, line, get)
+			}
+			continue
+		}
+
+		record := &ImportRecord{Line: line, Document: get("document")}
+		records = append(records, record)
+		current, currentKey = record, key
+
+		if first, ok := firstLine[key]; ok && key != "" {
+			record.Reject("document", fmt.Sprintf("documento repetido na planilha (linha %d)", first))
+			continue
+		}
+		firstLine[key] = line
+
+		record.Customer = parseRow(record, tenantID, branchID, get)
+		if record.Customer != nil {
+			addRowDetails(record, line, get)
+		}
+	}
+
+	if len(records) == 0 {
+		return nil, ErrImportEmpty
+	}
+	return records, nil
+}
+
+// parseRow monta o cliente a partir da linha principal, registrando cada campo inválido
+func parseRow(record *ImportRecord, tenantID, branchID string, get func(string) string) *Customer {
+	name := get("name")
+	if name == "" {
+		record.Reject("name", ErrEmptyName.Error())
+	}
+
+	personType, ok := parsePersonType(get("person_type"))
+	if !ok {
+		record.Reject("person_type", fmt.Sprintf("tipo de pessoa inválido: %q (use PF ou PJ)", get("person_type")))
+	}
+	if get("document") == "" {
+		record.Reject("document", ErrEmptyDocument.Error())
+	} else if _, _, err := NormalizeDocument(get("document"), personType); err != nil {
+		record.Reject("document", err.Error())
+	}
+
+	taxRegime, ok := parseTaxRegime(get("tax_regime"))
+	if !ok {
+		record.Reject("tax_regime", fmt.Sprintf("regime tributário inválido: %q", get("tax_regime")))
+	}
+	customerType, ok := parseCustomerType(get("customer_type"))
+	if !ok {
+		record.Reject("customer_type", fmt.Sprintf("tipo de cliente inválido: %q", get("customer_type")))
+	}
+	status, ok := parseStatus(get("status"))
+	if !ok {
+		record.Reject("status", fmt.Sprintf("status inválido: %q", get("status")))
+	}
+	creditLimit, err := parseDecimal(get("credit_limit"))
+	if err != nil || creditLimit < 0 {
+		record.Reject("credit_limit", fmt.Sprintf("limite de crédito inválido: %q", get("credit_limit")))
+	}
+	paymentTerm := 0
+	if value := get("payment_term"); value != "" {
+		if paymentTerm, err = strconv.Atoi(value); err != nil || paymentTerm < 0 {
+			record.Reject("payment_term", fmt.Sprintf("prazo de pagamento inválido: %q", value))
+		}
+	}
+	if len(record.Errors) > 0 {
+		return nil
+	}
+
+	c, err := NewCustomer(tenantID, branchID, personType, name, get("document"))
+	if err != nil {
+		record.Reject("document", err.Error())
+		return nil
+	}
+	if err := c.Update(
+		name, get("trade_name"), get("state_document"), get("city_document"),
+		taxRegime, customerType, creditLimit, paymentTerm,
+		get("website"), get("observations"), get("fiscal_notes"), get("external_code"),
+		"", "", "", get("suframa"), get("reference_code"),
+	); err != nil {
+		record.Reject("name", err.Error())
+		return nil
+	}
+	c.Status = status
+	return c
+}
+
+// addRowDetails acrescenta o endereço e o contato da linha, quando preenchidos
+func addRowDetails(record *ImportRecord, line int, get func(string) string) {
+	c := record.Customer
+
+	address := Address{
+		Street:      get("street"),
+		Number:      get("number"),
+		Complement:  get("complement"),
+		District:    get("district"),
+		City:        get("city"),
+		State:       strings.ToUpper(get("state")),
+		ZipCode:     get("zip_code"),
+		Country:     get("country"),
+		CityCode:    get("city_code"),
+		StateCode:   get("state_code"),
+		AddressType: get("address_type"),
+	}
+	if address.Street != "" || address.City != "" || address.ZipCode != "" {
+		if address.Country == "" {
+			address.Country = "Brasil"
+		}
+		address.MainAddress = len(c.Addresses) == 0
+		c.AddAddress(address)
+	}
+
+	contact := Contact{
+		Name:        get("contact_name"),
+		Department:  get("contact_department"),
+		Position:    get("contact_position"),
+		Phone:       get("phone"),
+		MobilePhone: get("mobile_phone"),
+		Email:       get("email"),
+	}
+	if contact.Email != "" {
+		if parsed, err := mail.ParseAddress(contact.Email); err != nil || parsed.Address != contact.Email {
+			record.Errors = append(record.Errors, ImportError{Line: line, Column: "email", Message: fmt.Sprintf("%s: %q", ErrInvalidEmail, contact.Email)})
+			return
+		}
+	}
+	if contact.Name != "" || contact.Phone != "" || contact.MobilePhone != "" || contact.Email != "" {
+		if contact.Name == "" {
+			contact.Name = c.Name
+		}
+		contact.MainContact = len(c.Contacts) == 0
+		c.AddContact(contact)
+	}
+}
+
+// ExportRows converte o cliente em linhas da planilha, uma para cada endereço ou contato
+func ExportRows(c *Customer) [][]string {
+	count := len(c.Addresses)
+	if len(c.Contacts) > count {
+		count = len(c.Contacts)
+	}
+	if count == 0 {
+		count = 1
+	}
+
+	// Valores gravados no formato do banco (ex.: SIMPLE) saem no formato aceito pela importação
+	taxRegime, _ := parseTaxRegime(string(c.TaxRegime))
+	customerType, _ := parseCustomerType(string(c.CustomerType))
+	status, _ := parseStatus(string(c.Status))
+
+	rows := make([][]string, 0, count)
+	for i := 0; i < count; i++ {
+		row := []string{
+			c.Document, string(c.PersonType), c.Name, c.TradeName, c.StateDocument, c.CityDocument,
+			string(taxRegime), string(customerType), string(status),
+			strconv.FormatFloat(c.CreditLimit, 'f', 2, 64), strconv.Itoa(c.PaymentTerm), c.Website,
+			c.ExternalCode, c.ReferenceCode, c.SUFRAMA, c.Observations, c.FiscalNotes,
+		}
+
+		var a Address
+		if i < len(c.Addresses) {
+			a = c.Addresses[i]
+		}
+		row = append(row, a.AddressType, a.Street, a.Number, a.Complement, a.District, a.City, a.State,
+			a.ZipCode, a.Country, a.CityCode, a.StateCode)
+
+		var ct Contact
+		if i < len(c.Contacts) {
+			ct = c.Contacts[i]
+		}
+		row = append(row, ct.Name, ct.Department, ct.Position, ct.Phone, ct.MobilePhone, ct.Email)
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// parsePersonType aceita PF/PJ e as formas por extenso; vazio deixa o tipo ser deduzido do documento
+func parsePersonType(value string) (PersonType, bool) {
+	switch spreadsheet.HeaderKey(value) {
+	case "":
+		return "", true
+	case "pf", "f", "fisica", "pessoa_fisica":
+		return PersonTypePF, true
+	case "pj", "j", "juridica", "pessoa_juridica":
+		return PersonTypePJ, true
+	}
+	return "", false
+}
+
+// parseTaxRegime aceita os valores do domínio, os gravados no banco e os nomes por extenso
+func parseTaxRegime(value string) (TaxRegime, bool) {
+	switch spreadsheet.HeaderKey(value) {
+	case "", "simples", "simple", "simples_nacional":
+		return TaxRegimeSimples, true
+	case "mei":
+		return TaxRegimeMEI, true
+	case "presumido", "presumed", "lucro_presumido":
+		return TaxRegimePresumido, true
+	case "real", "lucro_real":
+		return TaxRegimeReal, true
+	}
+	return "", false
+}
+
+// parseCustomerType aceita os valores do domínio, os gravados no banco e os nomes por extenso
+func parseCustomerType(value string) (CustomerType, bool) {
+	switch spreadsheet.HeaderKey(value) {
+	case "", "final", "customer", "consumidor", "consumidor_final":
+		return TypeFinal, true
+	case "reseller", "supplier", "revendedor", "revenda":
+		return TypeReseller, true
+	case "wholesale", "carrier", "atacadista", "atacado":
+		return TypeWholesale, true
+	}
+	return "", false
+}
+
+// parseStatus aceita os status em inglês ou português; vazio importa o cliente ativo
+func parseStatus(value string) (Status, bool) {
+	switch spreadsheet.HeaderKey(value) {
+	case "", "active", "ativo":
+		return StatusActive, true
+	case "inactive", "inativo":
+		return StatusInactive, true
+	case "blocked", "bloqueado":
+		return StatusBlocked, true
+	}
+	return "", false
+}
+
+// parseDecimal aceita valores como 1.234,56, 1234.56 e R$ 1.234,56
+func parseDecimal(value string) (float64, error) {
+	value = strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(value), "R$"))
+	if value == "" {
+		return 0, nil
+	}
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(strings.ReplaceAll(value, ".", ""), ",", ".")
+	}
+	return strconv.ParseFloat(value, 64)
+}
+
+// isTransferColumn indica se o campo faz parte da planilha de clientes
+func isTransferColumn(field string) bool {
+	for _, column := range TransferColumns {
+		if column == field {
+			return true
+		}
+	}
+	return false
+}
+
+// blankRow indica linhas sem nenhum valor preenchido
+func blankRow(fields []string) bool {
+	for _, f := range fields {
+		if strings.TrimSpace(f) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -9,6 +9,9 @@ type Repository interface {
 	// Create cria um novo cliente
 	Create(ctx context.Context, c *Customer) error
 
+	// CreateMany cria vários clientes em uma única transação; se um falhar, nenhum é gravado
+	CreateMany(ctx context.Context, customers []*Customer) error
+
 	// FindByID busca um cliente pelo ID
 	FindByID(ctx context.Context, id string) (*Customer, error)
 
@@ -41,4 +41,8 @@ type Repository interface {
 
 	// ExistsByBranch verifica se uma configuração existe para a filial
 	ExistsByBranch(ctx context.Context, branchID string) (bool, error)
+
+	// VoidNumbers registra a inutilização de uma faixa de numeração, recusando faixas que
+	// sobreponham números já inutilizados com ErrNumbersAlreadyVoided
+	VoidNumbers(ctx context.Context, v *VoidedRange) error
 }
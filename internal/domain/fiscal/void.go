@@ -0,0 +1,85 @@
+package fiscal
+
+import (
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// Erros da inutilização de numeração
+var (
+	ErrInvalidModel         = errors.New("modelo inválido: use nfe ou nfce")
+	ErrInvalidVoidRange     = errors.New("faixa inválida: número inicial de ao menos 1 e final maior ou igual ao inicial")
+	ErrVoidRangeNotReserved = errors.New("só podem ser inutilizados números já reservados pela numeração da filial")
+	ErrInvalidJustification = errors.New("justificativa deve ter entre 15 e 255 caracteres")
+	ErrNumbersAlreadyVoided = errors.New("a faixa inclui números já inutilizados")
+	ErrEmptySeries          = errors.New("configuração fiscal sem série para o modelo informado")
+)
+
+// Model define o modelo do documento fiscal
+type Model string
+
+const (
+	ModelNFe  Model = "nfe"  // NF-e, modelo 55
+	ModelNFCe Model = "nfce" // NFC-e, modelo 65
+)
+
+// VoidedRange representa uma faixa de numeração inutilizada: números reservados pela filial que não
+// chegaram a ser usados em documentos autorizados e são cancelados junto à SEFAZ
+type VoidedRange struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	BranchID      string    `json:"branch_id"`
+	Model         Model     `json:"model"`
+	Series        string    `json:"series"`
+	StartNumber   int       `json:"start_number"`
+	EndNumber     int       `json:"end_number"`
+	Justification string    `json:"justification"`
+	CreatedBy     string    `json:"created_by"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// NewVoidedRange cria a inutilização de uma faixa da série do modelo na configuração da filial.
+// Apenas números já reservados (abaixo do próximo número da série) podem ser inutilizados
+func NewVoidedRange(config *Configuration, model Model, start, end int, justification, userID string) (*VoidedRange, error) {
+	var series string
+	var next int
+	switch model {
+	case ModelNFe:
+		series, next = config.NFeSeries, config.NFeNextNumber
+	case ModelNFCe:
+		series, next = config.NFCeSeries, config.NFCeNextNumber
+	default:
+		return nil, ErrInvalidModel
+	}
+	if series == "" {
+		return nil, ErrEmptySeries
+	}
+	if start < 1 || end < start {
+		return nil, ErrInvalidVoidRange
+	}
+	if end >= next {
+		return nil, ErrVoidRangeNotReserved
+	}
+
+	justification = strings.TrimSpace(justification)
+	if length := utf8.RuneCountInString(justification); length < 15 || length > 255 {
+		return nil, ErrInvalidJustification
+	}
+
+	return &VoidedRange{
+		ID:            uuid.New().String(),
+		TenantID:      config.TenantID,
+		BranchID:      config.BranchID,
+		Model:         model,
+		Series:        series,
+		StartNumber:   start,
+		EndNumber:     end,
+		Justification: justification,
+		CreatedBy:     userID,
+		CreatedAt:     time.Now(),
+	}, nil
+}
@@ -0,0 +1,279 @@
+package loss
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID       = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyBranchID       = errors.New("ID da filial não pode ser vazio")
+	ErrEmptyReasonID       = errors.New("motivo da perda é obrigatório")
+	ErrEmptyCode           = errors.New("código do motivo não pode ser vazio")
+	ErrEmptyDescription    = errors.New("descrição do motivo não pode ser vazia")
+	ErrInvalidCategory     = errors.New("categoria de motivo inválida")
+	ErrEmptyProductID      = errors.New("produto é obrigatório")
+	ErrInvalidQuantity     = errors.New("quantidade deve ser maior que zero")
+	ErrNoItems             = errors.New("a perda deve ter pelo menos um item")
+	ErrNotPending          = errors.New("a perda já foi analisada")
+	ErrPhotoRequired       = errors.New("o motivo informado exige ao menos uma foto")
+	ErrEmptyRejectionNotes = errors.New("informe o motivo da rejeição")
+)
+
+// ReasonCategory agrupa os motivos de perda
+type ReasonCategory string
+
+const (
+	CategoryDamage              ReasonCategory = "damage"               // Avaria/quebra
+	CategorySpoilage            ReasonCategory = "spoilage"             // Deterioração
+	CategoryExpiry              ReasonCategory = "expiry"               // Vencimento
+	CategoryTheft               ReasonCategory = "theft"                // Furto
+	CategoryInternalConsumption ReasonCategory = "internal_consumption" // Consumo interno
+	CategoryOther               ReasonCategory = "other"                // Outros
+)
+
+// IsValid verifica se a categoria é conhecida
+func (c ReasonCategory) IsValid() bool {
+	switch c {
+	case CategoryDamage, CategorySpoilage, CategoryExpiry, CategoryTheft, CategoryInternalConsumption, CategoryOther:
+		return true
+	}
+	return false
+}
+
+// Status representa o estado de um registro de perda
+type Status string
+
+const (
+	StatusPending  Status = "pending"  // Aguardando aprovação do gerente
+	StatusApproved Status = "approved" // Aprovada e lançada no estoque
+	StatusRejected Status = "rejected" // Rejeitada, sem efeito no estoque
+)
+
+// MovementType é o tipo de movimentação de estoque lançado na aprovação de uma perda
+const MovementType = "loss"
+
+// Reason representa um motivo de perda configurável pelo tenant
+type Reason struct {
+	ID            string         `json:"id"`
+	TenantID      string         `json:"tenant_id"`
+	Code          string         `json:"code"`
+	Description   string         `json:"description"`
+	Category      ReasonCategory `json:"category"`
+	RequiresPhoto bool           `json:"requires_photo"`
+	Active        bool           `json:"active"`
+	CreatedAt     time.Time      `json:"created_at"`
+	UpdatedAt     time.Time      `json:"updated_at"`
+}
+
+// NewReason cria um novo motivo de perda
+func NewReason(tenantID, code, description string, category ReasonCategory, requiresPhoto bool) (*Reason, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	if strings.TrimSpace(code) == "" {
+		return nil, ErrEmptyCode
+	}
+	if strings.TrimSpace(description) == "" {
+		return nil, ErrEmptyDescription
+	}
+	if !category.IsValid() {
+		return nil, ErrInvalidCategory
+	}
+
+	now := time.Now()
+	return &Reason{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		Code:          strings.ToUpper(strings.TrimSpace(code)),
+		Description:   strings.TrimSpace(description),
+		Category:      category,
+		RequiresPhoto: requiresPhoto,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}, nil
+}
+
+// Update atualiza os dados do motivo
+func (r *Reason) Update(description string, category ReasonCategory, requiresPhoto, active bool) error {
+	if strings.TrimSpace(description) == "" {
+		return ErrEmptyDescription
+	}
+	if !category.IsValid() {
+		return ErrInvalidCategory
+	}
+
+	r.Description = strings.TrimSpace(description)
+	r.Category = category
+	r.RequiresPhoto = requiresPhoto
+	r.Active = active
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// Item representa um produto perdido em um registro de perda
+type Item struct {
+	ID        string  `json:"id"`
+	LossID    string  `json:"loss_id"`
+	ProductID string  `json:"product_id"`
+	Quantity  float64 `json:"quantity"`
+	UnitCost  float64 `json:"unit_cost"`  // Custo unitário no momento do lançamento
+	TotalCost float64 `json:"total_cost"` // Quantidade x custo unitário
+	Notes     string  `json:"notes"`
+}
+
+// Loss representa um registro de perda/quebra em uma filial
+type Loss struct {
+	ID              string     `json:"id"`
+	TenantID        string     `json:"tenant_id"`
+	BranchID        string     `json:"branch_id"`
+	ReasonID        string     `json:"reason_id"`
+	Status          Status     `json:"status"`
+	Notes           string     `json:"notes"`
+	Photos          []string   `json:"photos"` // URLs das fotos anexadas
+	Items           []Item     `json:"items"`
+	TotalCost       float64    `json:"total_cost"`
+	OccurredAt      time.Time  `json:"occurred_at"`
+	CreatedBy       string     `json:"created_by"`
+	ReviewedBy      string     `json:"reviewed_by"`
+	ReviewedAt      *time.Time `json:"reviewed_at"`
+	RejectionReason string     `json:"rejection_reason"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NewLoss cria um novo registro de perda pendente de aprovação
+func NewLoss(tenantID, branchID, reasonID, createdBy string, occurredAt time.Time, notes string) (*Loss, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	if branchID == "" {
+		return nil, ErrEmptyBranchID
+	}
+	if reasonID == "" {
+		return nil, ErrEmptyReasonID
+	}
+
+	now := time.Now()
+	if occurredAt.IsZero() {
+		occurredAt = now
+	}
+
+	return &Loss{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		BranchID:   branchID,
+		ReasonID:   reasonID,
+		Status:     StatusPending,
+		Notes:      notes,
+		Photos:     []string{},
+		Items:      []Item{},
+		OccurredAt: occurredAt,
+		CreatedBy:  createdBy,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}, nil
+}
+
+// AddItem adiciona um produto ao registro de perda
+func (l *Loss) AddItem(productID string, quantity float64, notes string) error {
+	if productID == "" {
+		return ErrEmptyProductID
+	}
+	if quantity <= 0 {
+		return ErrInvalidQuantity
+	}
+
+	l.Items = append(l.Items, Item{
+		ID:        uuid.New().String(),
+		LossID:    l.ID,
+		ProductID: productID,
+		Quantity:  quantity,
+		Notes:     notes,
+	})
+	l.UpdatedAt = time.Now()
+	return nil
+}
+
+// AddPhoto anexa uma foto ao registro de perda
+func (l *Loss) AddPhoto(url string) error {
+	if !l.IsPending() {
+		return ErrNotPending
+	}
+	if url = strings.TrimSpace(url); url != "" {
+		l.Photos = append(l.Photos, url)
+		l.UpdatedAt = time.Now()
+	}
+	return nil
+}
+
+// ApplyCost define o custo unitário de um item e recalcula os totais
+func (l *Loss) ApplyCost(productID string, unitCost float64) {
+	total := 0.0
+	for i := range l.Items {
+		if l.Items[i].ProductID == productID {
+			l.Items[i].UnitCost = unitCost
+			l.Items[i].TotalCost = roundMoney(l.Items[i].Quantity * unitCost)
+		}
+		total += l.Items[i].TotalCost
+	}
+	l.TotalCost = roundMoney(total)
+}
+
+// Validate verifica se o registro pode ser gravado com o motivo informado
+func (l *Loss) Validate(reason *Reason) error {
+	if len(l.Items) == 0 {
+		return ErrNoItems
+	}
+	if reason != nil && reason.RequiresPhoto && len(l.Photos) == 0 {
+		return ErrPhotoRequired
+	}
+	return nil
+}
+
+// IsPending verifica se a perda aguarda aprovação
+func (l *Loss) IsPending() bool {
+	return l.Status == StatusPending
+}
+
+// Approve aprova a perda
+func (l *Loss) Approve(userID string) error {
+	if !l.IsPending() {
+		return ErrNotPending
+	}
+
+	now := time.Now()
+	l.Status = StatusApproved
+	l.ReviewedBy = userID
+	l.ReviewedAt = &now
+	l.UpdatedAt = now
+	return nil
+}
+
+// Reject rejeita a perda informando o motivo
+func (l *Loss) Reject(userID, reason string) error {
+	if !l.IsPending() {
+		return ErrNotPending
+	}
+	if strings.TrimSpace(reason) == "" {
+		return ErrEmptyRejectionNotes
+	}
+
+	now := time.Now()
+	l.Status = StatusRejected
+	l.ReviewedBy = userID
+	l.ReviewedAt = &now
+	l.RejectionReason = strings.TrimSpace(reason)
+	l.UpdatedAt = now
+	return nil
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
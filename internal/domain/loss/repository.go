@@ -0,0 +1,126 @@
+package loss
+
+import (
+	"context"
+	"time"
+)
+
+// ListFilter define os filtros para listagem de perdas
+type ListFilter struct {
+	BranchID  string
+	ReasonID  string
+	Status    Status
+	StartDate time.Time
+	EndDate   time.Time
+}
+
+// ReportGroup define o agrupamento do relatório de perdas
+type ReportGroup string
+
+const (
+	GroupByReason   ReportGroup = "reason"   // Por motivo de perda
+	GroupByCategory ReportGroup = "category" // Por categoria de produto
+	GroupByPeriod   ReportGroup = "period"   // Por mês
+)
+
+// SaleMovementType é o tipo das movimentações de estoque lançadas pelas vendas do PDV, cujo valor
+// vendido (total_value) é a base do percentual de perdas sobre vendas
+const SaleMovementType = "sale"
+
+// ReportFilter define os parâmetros do relatório de perdas
+type ReportFilter struct {
+	BranchID  string
+	StartDate time.Time
+	EndDate   time.Time
+	GroupBy   ReportGroup
+}
+
+// ReportLine representa uma linha agrupada do relatório de perdas
+type ReportLine struct {
+	Key             string   `json:"key"`
+	Label           string   `json:"label"`
+	Quantity        float64  `json:"quantity"`
+	TotalCost       float64  `json:"total_cost"`
+	Share           float64  `json:"share"`            // Participação no total de perdas (%)
+	SalesPercentage *float64 `json:"sales_percentage"` // Perda sobre o valor vendido no período (%); nulo sem vendas
+}
+
+// Report representa o relatório de perdas de um período
+type Report struct {
+	GroupBy         ReportGroup  `json:"group_by"`
+	StartDate       time.Time    `json:"start_date"`
+	EndDate         time.Time    `json:"end_date"`
+	Lines           []ReportLine `json:"lines"`
+	TotalCost       float64      `json:"total_cost"`
+	SalesTotal      float64      `json:"sales_total"`      // Valor vendido no período, somado das movimentações de venda
+	SalesPercentage *float64     `json:"sales_percentage"` // Nulo quando não há vendas registradas no período
+}
+
+// Calculate preenche os percentuais das linhas e do total do relatório.
+// Sem vendas no período o percentual sobre vendas fica nulo em vez de zero,
+// para não apresentar uma perda sem vendas como 0%.
+func (r *Report) Calculate() {
+	r.TotalCost = 0
+	for _, line := range r.Lines {
+		r.TotalCost += line.TotalCost
+	}
+	r.TotalCost = roundMoney(r.TotalCost)
+
+	for i := range r.Lines {
+		if r.TotalCost > 0 {
+			r.Lines[i].Share = roundMoney(r.Lines[i].TotalCost / r.TotalCost * 100)
+		}
+		r.Lines[i].SalesPercentage = nil
+	}
+
+	r.SalesPercentage = nil
+	if r.SalesTotal <= 0 {
+		return
+	}
+
+	for i := range r.Lines {
+		percentage := roundMoney(r.Lines[i].TotalCost / r.SalesTotal * 100)
+		r.Lines[i].SalesPercentage = &percentage
+	}
+	percentage := roundMoney(r.TotalCost / r.SalesTotal * 100)
+	r.SalesPercentage = &percentage
+}
+
+// Repository define a interface para operações de repositório de perdas
+type Repository interface {
+	// CreateReason cria um novo motivo de perda
+	CreateReason(ctx context.Context, r *Reason) error
+
+	// FindReasonByID busca um motivo pelo ID
+	FindReasonByID(ctx context.Context, id string) (*Reason, error)
+
+	// ListReasons lista os motivos do tenant
+	ListReasons(ctx context.Context, onlyActive bool) ([]*Reason, error)
+
+	// UpdateReason atualiza um motivo existente
+	UpdateReason(ctx context.Context, r *Reason) error
+
+	// Create cria um novo registro de perda com seus itens
+	Create(ctx context.Context, l *Loss) error
+
+	// FindByID busca um registro de perda pelo ID, incluindo itens
+	FindByID(ctx context.Context, id string) (*Loss, error)
+
+	// List lista os registros de perda com filtros e paginação
+	List(ctx context.Context, filter ListFilter, limit, offset int) ([]*Loss, error)
+
+	// Count conta os registros de perda que atendem aos filtros
+	Count(ctx context.Context, filter ListFilter) (int, error)
+
+	// UpdatePhotos atualiza as fotos anexadas a uma perda pendente
+	UpdatePhotos(ctx context.Context, l *Loss) error
+
+	// Approve grava a aprovação e lança as movimentações de estoque pelo custo atual
+	Approve(ctx context.Context, l *Loss) error
+
+	// Reject grava a rejeição de uma perda
+	Reject(ctx context.Context, l *Loss) error
+
+	// Report gera o relatório de perdas do período
+	Report(ctx context.Context, filter ReportFilter) (*Report, error)
+}
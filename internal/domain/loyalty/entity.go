@@ -0,0 +1,444 @@
+package loyalty
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID         = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyCustomerID       = errors.New("cliente é obrigatório")
+	ErrEmptyName             = errors.New("nome é obrigatório")
+	ErrEmptyCategoryID       = errors.New("categoria é obrigatória")
+	ErrEmptyProductID        = errors.New("produto é obrigatório")
+	ErrInvalidPointsPerReal  = errors.New("pontos por real não podem ser negativos")
+	ErrInvalidPointValue     = errors.New("valor do ponto deve ser maior que zero")
+	ErrInvalidExpiration     = errors.New("validade dos pontos deve ser maior que zero")
+	ErrInvalidMinRedeem      = errors.New("mínimo para resgate não pode ser negativo")
+	ErrInvalidMultiplier     = errors.New("multiplicador deve ser maior que zero")
+	ErrInvalidPeriod         = errors.New("data final da campanha deve ser posterior à inicial")
+	ErrInvalidPoints         = errors.New("quantidade de pontos deve ser maior que zero")
+	ErrInvalidItemAmount     = errors.New("valor do item não pode ser negativo")
+	ErrProgramInactive       = errors.New("programa de fidelidade inativo")
+	ErrNoPointsEarned        = errors.New("compra não gera pontos")
+	ErrBelowMinRedeem        = errors.New("quantidade de pontos abaixo do mínimo para resgate")
+	ErrInsufficientPoints    = errors.New("saldo de pontos insuficiente")
+	ErrRewardInactive        = errors.New("prêmio inativo")
+	ErrRedeemTargetRequired  = errors.New("informe os pontos a usar como pagamento ou o prêmio a resgatar")
+	ErrRedeemTargetAmbiguous = errors.New("informe pontos ou prêmio, não ambos")
+)
+
+// EntryType define o tipo de lançamento no extrato de pontos
+type EntryType string
+
+const (
+	EntryEarn   EntryType = "earn"   // Pontos ganhos em uma compra
+	EntryRedeem EntryType = "redeem" // Pontos usados como pagamento ou trocados por prêmio
+	EntryExpire EntryType = "expire" // Pontos vencidos
+)
+
+// ExpiringWindowDays é o horizonte usado para avisar o cliente sobre pontos a vencer
+const ExpiringWindowDays = 30
+
+// Program representa a configuração do programa de fidelidade do tenant
+type Program struct {
+	ID              string    `json:"id"`
+	TenantID        string    `json:"tenant_id"`
+	Name            string    `json:"name"`
+	PointsPerReal   float64   `json:"points_per_real"`   // Pontos por R$ 1,00 nas categorias sem regra própria
+	PointValue      float64   `json:"point_value"`       // Valor em R$ de cada ponto usado como pagamento
+	ExpirationDays  int       `json:"expiration_days"`   // Validade dos pontos a partir da compra
+	MinRedeemPoints int       `json:"min_redeem_points"` // Mínimo de pontos por resgate
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NewProgram cria a configuração do programa de fidelidade
+func NewProgram(tenantID, name string, pointsPerReal, pointValue float64, expirationDays, minRedeemPoints int) (*Program, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+
+	now := time.Now()
+	p := &Program{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		Name:            strings.TrimSpace(name),
+		PointsPerReal:   pointsPerReal,
+		PointValue:      pointValue,
+		ExpirationDays:  expirationDays,
+		MinRedeemPoints: minRedeemPoints,
+		Active:          true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+
+	if err := p.Validate(); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Validate verifica a configuração do programa
+func (p *Program) Validate() error {
+	if p.Name == "" {
+		return ErrEmptyName
+	}
+	if p.PointsPerReal < 0 {
+		return ErrInvalidPointsPerReal
+	}
+	if p.PointValue <= 0 {
+		return ErrInvalidPointValue
+	}
+	if p.ExpirationDays <= 0 {
+		return ErrInvalidExpiration
+	}
+	if p.MinRedeemPoints < 0 {
+		return ErrInvalidMinRedeem
+	}
+	return nil
+}
+
+// PointsValue converte pontos no valor em R$ aceito como pagamento
+func (p *Program) PointsValue(points int) float64 {
+	return roundMoney(float64(points) * p.PointValue)
+}
+
+// Rule define a pontuação de uma categoria de produtos, substituindo a pontuação padrão do programa
+type Rule struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	CategoryID    string    `json:"category_id"`
+	PointsPerReal float64   `json:"points_per_real"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewRule cria uma regra de pontuação por categoria
+func NewRule(tenantID, categoryID string, pointsPerReal float64) (*Rule, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+
+	now := time.Now()
+	r := &Rule{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		CategoryID:    categoryID,
+		PointsPerReal: pointsPerReal,
+		Active:        true,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Validate verifica os dados da regra
+func (r *Rule) Validate() error {
+	if r.CategoryID == "" {
+		return ErrEmptyCategoryID
+	}
+	if r.PointsPerReal < 0 {
+		return ErrInvalidPointsPerReal
+	}
+	return nil
+}
+
+// Campaign multiplica a pontuação durante um período, em todas as categorias ou em uma específica
+type Campaign struct {
+	ID         string    `json:"id"`
+	TenantID   string    `json:"tenant_id"`
+	Name       string    `json:"name"`
+	CategoryID string    `json:"category_id,omitempty"` // Vazio para todas as categorias
+	Multiplier float64   `json:"multiplier"`
+	StartsAt   time.Time `json:"starts_at"`
+	EndsAt     time.Time `json:"ends_at"`
+	Active     bool      `json:"active"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// NewCampaign cria uma campanha de pontuação
+func NewCampaign(tenantID, name, categoryID string, multiplier float64, startsAt, endsAt time.Time) (*Campaign, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+
+	now := time.Now()
+	c := &Campaign{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		Name:       strings.TrimSpace(name),
+		CategoryID: categoryID,
+		Multiplier: multiplier,
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Validate verifica os dados da campanha
+func (c *Campaign) Validate() error {
+	if c.Name == "" {
+		return ErrEmptyName
+	}
+	if c.Multiplier <= 0 {
+		return ErrInvalidMultiplier
+	}
+	if c.StartsAt.IsZero() || !c.EndsAt.After(c.StartsAt) {
+		return ErrInvalidPeriod
+	}
+	return nil
+}
+
+// Applies verifica se a campanha vale para a categoria no momento informado
+func (c *Campaign) Applies(categoryID string, at time.Time) bool {
+	if !c.Active || at.Before(c.StartsAt) || !at.Before(c.EndsAt) {
+		return false
+	}
+	return c.CategoryID == "" || c.CategoryID == categoryID
+}
+
+// Reward representa um produto que pode ser trocado por pontos
+type Reward struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	ProductID string    `json:"product_id"`
+	Name      string    `json:"name"`
+	Points    int       `json:"points"`
+	Active    bool      `json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewReward cria um prêmio do catálogo de resgate
+func NewReward(tenantID, productID, name string, points int) (*Reward, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+
+	now := time.Now()
+	r := &Reward{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		ProductID: productID,
+		Name:      strings.TrimSpace(name),
+		Points:    points,
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Validate verifica os dados do prêmio
+func (r *Reward) Validate() error {
+	if r.ProductID == "" {
+		return ErrEmptyProductID
+	}
+	if r.Name == "" {
+		return ErrEmptyName
+	}
+	if r.Points <= 0 {
+		return ErrInvalidPoints
+	}
+	return nil
+}
+
+// Item representa um item da venda considerado na pontuação
+type Item struct {
+	ProductID  string  `json:"product_id"`
+	CategoryID string  `json:"category_id"`
+	Amount     float64 `json:"amount"` // Valor pago no item, já com descontos
+}
+
+// Entry representa um lançamento no extrato de pontos do cliente
+type Entry struct {
+	ID          string     `json:"id"`
+	TenantID    string     `json:"tenant_id"`
+	CustomerID  string     `json:"customer_id"`
+	BranchID    string     `json:"branch_id,omitempty"`
+	Type        EntryType  `json:"type"`
+	Points      int        `json:"points"`              // Positivo nos ganhos, negativo nos resgates e vencimentos
+	Remaining   int        `json:"remaining,omitempty"` // Pontos do ganho ainda disponíveis para resgate
+	Amount      float64    `json:"amount"`              // Valor da compra no ganho ou valor do pagamento no resgate
+	SaleID      string     `json:"sale_id,omitempty"`
+	RewardID    string     `json:"reward_id,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"`
+	Description string     `json:"description,omitempty"`
+	CreatedBy   string     `json:"created_by,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// Earn calcula os pontos de uma compra. Cada item pontua pela regra da sua categoria, ou pela pontuação
+// padrão do programa, multiplicada pela maior campanha vigente para a categoria
+func Earn(p *Program, rules []*Rule, campaigns []*Campaign, customerID, branchID, saleID string, items []Item, at time.Time, userID string) (*Entry, error) {
+	if !p.Active {
+		return nil, ErrProgramInactive
+	}
+	if customerID == "" {
+		return nil, ErrEmptyCustomerID
+	}
+
+	var points float64
+	var amount float64
+	for _, item := range items {
+		if item.Amount < 0 {
+			return nil, ErrInvalidItemAmount
+		}
+
+		rate := p.PointsPerReal
+		for _, r := range rules {
+			if r.Active && r.CategoryID == item.CategoryID && item.CategoryID != "" {
+				rate = r.PointsPerReal
+				break
+			}
+		}
+
+		multiplier := 1.0
+		for _, c := range campaigns {
+			if c.Applies(item.CategoryID, at) && c.Multiplier > multiplier {
+				multiplier = c.Multiplier
+			}
+		}
+
+		points += item.Amount * rate * multiplier
+		amount += item.Amount
+	}
+
+	// Frações de ponto são descartadas somente no total, para não penalizar compras com muitos itens
+	total := int(math.Floor(points + 1e-9))
+	if total <= 0 {
+		return nil, ErrNoPointsEarned
+	}
+
+	expiresAt := at.AddDate(0, 0, p.ExpirationDays)
+	return &Entry{
+		ID:         uuid.New().String(),
+		TenantID:   p.TenantID,
+		CustomerID: customerID,
+		BranchID:   branchID,
+		Type:       EntryEarn,
+		Points:     total,
+		Remaining:  total,
+		Amount:     roundMoney(amount),
+		SaleID:     saleID,
+		ExpiresAt:  &expiresAt,
+		CreatedBy:  userID,
+		CreatedAt:  at,
+	}, nil
+}
+
+// Redeem cria o resgate de pontos como pagamento na venda ou pela troca de um prêmio
+func Redeem(p *Program, reward *Reward, points int, customerID, branchID, saleID, userID string) (*Entry, error) {
+	if !p.Active {
+		return nil, ErrProgramInactive
+	}
+	if customerID == "" {
+		return nil, ErrEmptyCustomerID
+	}
+
+	e := &Entry{
+		ID:         uuid.New().String(),
+		TenantID:   p.TenantID,
+		CustomerID: customerID,
+		BranchID:   branchID,
+		Type:       EntryRedeem,
+		SaleID:     saleID,
+		CreatedBy:  userID,
+		CreatedAt:  time.Now(),
+	}
+
+	switch {
+	case reward != nil && points > 0:
+		return nil, ErrRedeemTargetAmbiguous
+	case reward != nil:
+		if !reward.Active {
+			return nil, ErrRewardInactive
+		}
+		points = reward.Points
+		e.RewardID = reward.ID
+		e.Description = reward.Name
+	case points > 0:
+		e.Amount = p.PointsValue(points)
+	default:
+		return nil, ErrRedeemTargetRequired
+	}
+
+	if points < p.MinRedeemPoints {
+		return nil, ErrBelowMinRedeem
+	}
+
+	e.Points = -points
+	return e, nil
+}
+
+// Consume baixa os pontos dos ganhos que vencem primeiro, atualizando o saldo disponível de cada um.
+// Retorna os ganhos alterados
+func Consume(earnings []*Entry, points int) ([]*Entry, error) {
+	var available int
+	for _, e := range earnings {
+		available += e.Remaining
+	}
+	if available < points {
+		return nil, ErrInsufficientPoints
+	}
+
+	changed := make([]*Entry, 0)
+	for _, e := range earnings {
+		if points == 0 {
+			break
+		}
+		used := e.Remaining
+		if used > points {
+			used = points
+		}
+		if used == 0 {
+			continue
+		}
+		e.Remaining -= used
+		points -= used
+		changed = append(changed, e)
+	}
+	return changed, nil
+}
+
+// Balance representa o saldo de pontos consultado no PDV
+type Balance struct {
+	CustomerID     string     `json:"customer_id"`
+	CustomerName   string     `json:"customer_name,omitempty"`
+	Points         int        `json:"points"`
+	Value          float64    `json:"value"`           // Valor em R$ dos pontos como pagamento
+	ExpiringPoints int        `json:"expiring_points"` // Pontos que vencem nos próximos dias
+	NextExpiration *time.Time `json:"next_expiration,omitempty"`
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
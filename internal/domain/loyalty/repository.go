@@ -0,0 +1,70 @@
+package loyalty
+
+import (
+	"context"
+	"time"
+)
+
+// Repository define a interface para operações de repositório do programa de fidelidade
+type Repository interface {
+	// GetProgram busca a configuração do programa do tenant
+	GetProgram(ctx context.Context) (*Program, error)
+
+	// SaveProgram cria ou atualiza a configuração do programa do tenant
+	SaveProgram(ctx context.Context, p *Program) error
+
+	// CreateRule grava uma regra de pontuação por categoria
+	CreateRule(ctx context.Context, r *Rule) error
+
+	// UpdateRule atualiza uma regra de pontuação
+	UpdateRule(ctx context.Context, r *Rule) error
+
+	// FindRuleByID busca uma regra pelo ID
+	FindRuleByID(ctx context.Context, id string) (*Rule, error)
+
+	// ListRules lista as regras de pontuação
+	ListRules(ctx context.Context) ([]*Rule, error)
+
+	// CreateCampaign grava uma campanha de pontuação
+	CreateCampaign(ctx context.Context, c *Campaign) error
+
+	// UpdateCampaign atualiza uma campanha de pontuação
+	UpdateCampaign(ctx context.Context, c *Campaign) error
+
+	// FindCampaignByID busca uma campanha pelo ID
+	FindCampaignByID(ctx context.Context, id string) (*Campaign, error)
+
+	// ListCampaigns lista as campanhas; com at informado, somente as ativas e vigentes no momento
+	ListCampaigns(ctx context.Context, at *time.Time) ([]*Campaign, error)
+
+	// CreateReward grava um prêmio do catálogo de resgate
+	CreateReward(ctx context.Context, r *Reward) error
+
+	// UpdateReward atualiza um prêmio
+	UpdateReward(ctx context.Context, r *Reward) error
+
+	// FindRewardByID busca um prêmio pelo ID
+	FindRewardByID(ctx context.Context, id string) (*Reward, error)
+
+	// ListRewards lista os prêmios, opcionalmente somente os ativos
+	ListRewards(ctx context.Context, onlyActive bool) ([]*Reward, error)
+
+	// Earn grava os pontos ganhos em uma compra e a data da última compra do cliente.
+	// Cada venda pontua uma única vez
+	Earn(ctx context.Context, e *Entry) error
+
+	// Redeem baixa os pontos dos ganhos que vencem primeiro e grava o resgate em uma única transação
+	Redeem(ctx context.Context, e *Entry) error
+
+	// Balance calcula o saldo de pontos válidos do cliente no momento informado
+	Balance(ctx context.Context, customerID string, at time.Time) (*Balance, error)
+
+	// ListEntries lista o extrato de pontos do cliente, do mais recente para o mais antigo
+	ListEntries(ctx context.Context, customerID string, limit, offset int) ([]*Entry, error)
+
+	// CountEntries conta os lançamentos do extrato de pontos do cliente
+	CountEntries(ctx context.Context, customerID string) (int, error)
+
+	// Expire registra o vencimento dos pontos não usados até o momento informado e retorna os lançamentos gerados
+	Expire(ctx context.Context, at time.Time) (int, error)
+}
@@ -0,0 +1,142 @@
+package onboarding
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/branch"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/fiscal"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/paymentmethod"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
+)
+
+var (
+	ErrMissingTenant      = errors.New("dados do tenant são obrigatórios")
+	ErrMissingBranch      = errors.New("dados da matriz são obrigatórios")
+	ErrMissingAdmin       = errors.New("dados do administrador são obrigatórios")
+	ErrBranchTenant       = errors.New("matriz não pertence ao tenant")
+	ErrAdminTenant        = errors.New("administrador não pertence ao tenant")
+	ErrAdminPassword      = errors.New("senha do administrador é obrigatória")
+	ErrInvalidEnvironment = errors.New("ambiente fiscal inválido, use production ou homologation")
+)
+
+// Category é uma categoria de produtos criada no cadastro inicial do tenant
+type Category struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	Name      string    `json:"name"`
+	Code      string    `json:"code"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// defaultCategories são as categorias de produtos de um supermercado criadas para todo tenant
+var defaultCategories = []struct{ code, name string }{
+	{"MERC", "Mercearia"},
+	{"BEB", "Bebidas"},
+	{"HORT", "Hortifrúti"},
+	{"ACOU", "Açougue"},
+	{"FRIO", "Frios e Laticínios"},
+	{"PAD", "Padaria"},
+	{"CONG", "Congelados"},
+	{"LIMP", "Limpeza"},
+	{"HIG", "Higiene e Perfumaria"},
+	{"PET", "Pet Shop"},
+	{"UTIL", "Utilidades Domésticas"},
+}
+
+// defaultPaymentMethods são as formas de pagamento criadas para todo tenant, com os padrões de
+// paymentmethod.NewPaymentMethod
+var defaultPaymentMethods = []struct {
+	name string
+	kind paymentmethod.Kind
+}{
+	{"Dinheiro", paymentmethod.KindCash},
+	{"Cartão de Débito", paymentmethod.KindDebit},
+	{"Cartão de Crédito", paymentmethod.KindCredit},
+	{"Pix", paymentmethod.KindPix},
+	{"Vale Alimentação", paymentmethod.KindMealVoucher},
+}
+
+// Onboarding é o cadastro inicial completo de um tenant: o tenant, a matriz, o primeiro
+// administrador, a configuração fiscal padrão da matriz e os dados iniciais. É gravado de uma
+// vez por Repository.Create
+type Onboarding struct {
+	Tenant         *tenant.Tenant
+	Branch         *branch.Branch
+	Admin          *user.User
+	FiscalConfig   *fiscal.Configuration
+	Categories     []*Category
+	PaymentMethods []*paymentmethod.PaymentMethod
+}
+
+// New monta o cadastro inicial a partir do tenant, da matriz e do administrador já construídos
+// pelos seus domínios. A filial passa a ser a matriz (TypeHeadquarters e IsMain) e o
+// administrador fica vinculado a ela, ativo e com o papel admin. A configuração fiscal é criada
+// no ambiente informado (homologação quando vazio) e sem certificado, que é enviado depois
+func New(t *tenant.Tenant, b *branch.Branch, admin *user.User, environment fiscal.FiscalEnvironment) (*Onboarding, error) {
+	switch {
+	case t == nil:
+		return nil, ErrMissingTenant
+	case b == nil:
+		return nil, ErrMissingBranch
+	case admin == nil:
+		return nil, ErrMissingAdmin
+	case b.TenantID != t.ID:
+		return nil, ErrBranchTenant
+	case admin.TenantID != t.ID:
+		return nil, ErrAdminTenant
+	case admin.Password == "":
+		return nil, ErrAdminPassword
+	}
+
+	if environment == "" {
+		environment = fiscal.Homologation
+	}
+	if environment != fiscal.Homologation && environment != fiscal.Production {
+		return nil, ErrInvalidEnvironment
+	}
+
+	b.Type = branch.TypeHeadquarters
+	b.IsMain = true
+	b.Status = branch.StatusActive
+
+	admin.BranchID = b.ID
+	admin.Role = user.RoleAdmin
+	admin.Status = user.StatusActive
+
+	config, err := fiscal.NewConfiguration(t.ID, b.ID, "")
+	if err != nil {
+		return nil, err
+	}
+	config.NFeEnvironment = environment
+	config.NFCeEnvironment = environment
+
+	o := &Onboarding{
+		Tenant:       t,
+		Branch:       b,
+		Admin:        admin,
+		FiscalConfig: config,
+	}
+
+	now := time.Now()
+	for _, c := range defaultCategories {
+		o.Categories = append(o.Categories, &Category{
+			ID:        uuid.New().String(),
+			TenantID:  t.ID,
+			Name:      c.name,
+			Code:      c.code,
+			CreatedAt: now,
+		})
+	}
+	for _, m := range defaultPaymentMethods {
+		method := paymentmethod.NewPaymentMethod(t.ID, m.name, m.kind)
+		if err := method.Validate(); err != nil {
+			return nil, err
+		}
+		o.PaymentMethods = append(o.PaymentMethods, method)
+	}
+
+	return o, nil
+}
@@ -0,0 +1,10 @@
+package onboarding
+
+import "context"
+
+// Repository define a interface para a gravação do cadastro inicial de tenants
+type Repository interface {
+	// Create grava o cadastro inicial completo. Se qualquer etapa falhar, o que já foi criado
+	// (cadastro do tenant e schema) é removido, e o tenant pode ser cadastrado de novo
+	Create(ctx context.Context, o *Onboarding) error
+}
@@ -0,0 +1,259 @@
+package payable
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID     = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyBranchID     = errors.New("ID da filial não pode ser vazio")
+	ErrEmptySupplierID   = errors.New("fornecedor é obrigatório")
+	ErrInvalidAmount     = errors.New("valor deve ser maior que zero")
+	ErrInvalidDueDate    = errors.New("vencimento não pode ser anterior à emissão")
+	ErrNoInstallments    = errors.New("informe ao menos uma parcela")
+	ErrInvalidPayment    = errors.New("valor do pagamento inválido")
+	ErrPaymentExceeds    = errors.New("valor do pagamento excede o saldo do título")
+	ErrNotOpen           = errors.New("título não está em aberto")
+	ErrHasPayments       = errors.New("título com pagamentos não pode ser cancelado")
+	ErrEmptyCancelReason = errors.New("informe o motivo do cancelamento")
+	ErrDiscountExceeds   = errors.New("desconto não pode ser maior que o valor pago")
+)
+
+// Status representa a situação de um título a pagar
+type Status string
+
+const (
+	StatusOpen      Status = "open"      // Em aberto
+	StatusPartial   Status = "partial"   // Pago parcialmente
+	StatusPaid      Status = "paid"      // Quitado
+	StatusCancelled Status = "cancelled" // Cancelado
+)
+
+// Payment representa uma baixa (pagamento) de um título
+type Payment struct {
+	ID        string    `json:"id"`
+	PayableID string    `json:"payable_id"`
+	PaidAt    time.Time `json:"paid_at"`
+	Amount    float64   `json:"amount"`   // Principal abatido do saldo
+	Interest  float64   `json:"interest"` // Juros
+	Fine      float64   `json:"fine"`     // Multa
+	Discount  float64   `json:"discount"` // Desconto
+	Total     float64   `json:"total"`    // Valor efetivamente pago
+	Method    string    `json:"method"`
+	Notes     string    `json:"notes"`
+	CreatedBy string    `json:"created_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Payable representa um título a pagar
+type Payable struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	BranchID       string    `json:"branch_id"`
+	SupplierID     string    `json:"supplier_id"`
+	PurchaseID     string    `json:"purchase_id"`
+	DocumentNumber string    `json:"document_number"`
+	Description    string    `json:"description"`
+	Installment    int       `json:"installment"`
+	Installments   int       `json:"installments"`
+	IssueDate      time.Time `json:"issue_date"`
+	DueDate        time.Time `json:"due_date"`
+	Amount         float64   `json:"amount"`
+	PaidAmount     float64   `json:"paid_amount"` // Principal já abatido
+	Interest       float64   `json:"interest"`    // Juros acumulados nas baixas
+	Fine           float64   `json:"fine"`        // Multas acumuladas nas baixas
+	Discount       float64   `json:"discount"`    // Descontos acumulados nas baixas
+	Status         Status    `json:"status"`
+	CancelReason   string    `json:"cancel_reason"`
+	CreatedBy      string    `json:"created_by"`
+	Payments       []Payment `json:"payments"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewPayable cria um título a pagar manual, em parcela única
+func NewPayable(tenantID, branchID, supplierID, documentNumber, description string, amount float64, issueDate, dueDate time.Time) (*Payable, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	if branchID == "" {
+		return nil, ErrEmptyBranchID
+	}
+	if supplierID == "" {
+		return nil, ErrEmptySupplierID
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	issueDate = truncateDate(issueDate)
+	dueDate = truncateDate(dueDate)
+	if dueDate.Before(issueDate) {
+		return nil, ErrInvalidDueDate
+	}
+
+	now := time.Now()
+	return &Payable{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		BranchID:       branchID,
+		SupplierID:     supplierID,
+		DocumentNumber: documentNumber,
+		Description:    description,
+		Installment:    1,
+		Installments:   1,
+		IssueDate:      issueDate,
+		DueDate:        dueDate,
+		Amount:         roundMoney(amount),
+		Status:         StatusOpen,
+		Payments:       []Payment{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// GenerateInstallments gera os títulos de uma compra recebida conforme os prazos do fornecedor.
+// A diferença de arredondamento é lançada na primeira parcela
+func GenerateInstallments(tenantID, branchID, supplierID, purchaseID, documentNumber string, total float64, issueDate time.Time, days []int) ([]*Payable, error) {
+	if len(days) == 0 {
+		return nil, ErrNoInstallments
+	}
+	if total <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	count := len(days)
+	value := math.Floor(total/float64(count)*100) / 100
+	remainder := roundMoney(total - value*float64(count))
+
+	payables := make([]*Payable, 0, count)
+	for i, d := range days {
+		amount := value
+		if i == 0 {
+			amount = roundMoney(value + remainder)
+		}
+
+		p, err := NewPayable(tenantID, branchID, supplierID, documentNumber, "", amount, issueDate, issueDate.AddDate(0, 0, d))
+		if err != nil {
+			return nil, err
+		}
+		p.PurchaseID = purchaseID
+		p.Installment = i + 1
+		p.Installments = count
+		p.Description = describeInstallment(documentNumber, i+1, count)
+		payables = append(payables, p)
+	}
+
+	return payables, nil
+}
+
+// Balance retorna o saldo em aberto do título
+func (p *Payable) Balance() float64 {
+	if p.Status == StatusCancelled {
+		return 0
+	}
+	return roundMoney(p.Amount - p.PaidAmount)
+}
+
+// IsOpen verifica se o título ainda aceita pagamentos
+func (p *Payable) IsOpen() bool {
+	return p.Status == StatusOpen || p.Status == StatusPartial
+}
+
+// IsOverdue verifica se o título está vencido na data informada
+func (p *Payable) IsOverdue(reference time.Time) bool {
+	return p.IsOpen() && p.DueDate.Before(truncateDate(reference))
+}
+
+// Pay registra uma baixa parcial ou total do título.
+// amount é o principal abatido; juros e multa acrescem e o desconto reduz o valor pago
+func (p *Payable) Pay(amount, interest, fine, discount float64, paidAt time.Time, method, notes, userID string) (*Payment, error) {
+	if !p.IsOpen() {
+		return nil, ErrNotOpen
+	}
+	if amount <= 0 || interest < 0 || fine < 0 || discount < 0 {
+		return nil, ErrInvalidPayment
+	}
+	if roundMoney(amount) > p.Balance() {
+		return nil, ErrPaymentExceeds
+	}
+	if discount > amount+interest+fine {
+		return nil, ErrDiscountExceeds
+	}
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+
+	now := time.Now()
+	payment := Payment{
+		ID:        uuid.New().String(),
+		PayableID: p.ID,
+		PaidAt:    truncateDate(paidAt),
+		Amount:    roundMoney(amount),
+		Interest:  roundMoney(interest),
+		Fine:      roundMoney(fine),
+		Discount:  roundMoney(discount),
+		Total:     roundMoney(amount + interest + fine - discount),
+		Method:    method,
+		Notes:     notes,
+		CreatedBy: userID,
+		CreatedAt: now,
+	}
+
+	p.PaidAmount = roundMoney(p.PaidAmount + payment.Amount)
+	p.Interest = roundMoney(p.Interest + payment.Interest)
+	p.Fine = roundMoney(p.Fine + payment.Fine)
+	p.Discount = roundMoney(p.Discount + payment.Discount)
+	if p.Balance() <= 0 {
+		p.Status = StatusPaid
+	} else {
+		p.Status = StatusPartial
+	}
+	p.Payments = append(p.Payments, payment)
+	p.UpdatedAt = now
+
+	return &payment, nil
+}
+
+// Cancel cancela um título que ainda não recebeu pagamentos
+func (p *Payable) Cancel(reason string) error {
+	if !p.IsOpen() {
+		return ErrNotOpen
+	}
+	if p.PaidAmount > 0 {
+		return ErrHasPayments
+	}
+	if strings.TrimSpace(reason) == "" {
+		return ErrEmptyCancelReason
+	}
+
+	p.Status = StatusCancelled
+	p.CancelReason = strings.TrimSpace(reason)
+	p.UpdatedAt = time.Now()
+	return nil
+}
+
+// describeInstallment monta a descrição padrão de uma parcela
+func describeInstallment(documentNumber string, installment, installments int) string {
+	description := "Compra"
+	if documentNumber != "" {
+		description += " NF " + documentNumber
+	}
+	return description + " - parcela " + strconv.Itoa(installment) + "/" + strconv.Itoa(installments)
+}
+
+// truncateDate remove o horário de uma data
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
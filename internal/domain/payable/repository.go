@@ -0,0 +1,49 @@
+package payable
+
+import (
+	"context"
+	"time"
+)
+
+// ListFilter define os filtros para listagem de títulos a pagar
+type ListFilter struct {
+	BranchID   string
+	SupplierID string
+	PurchaseID string
+	Status     Status
+	DueFrom    time.Time
+	DueTo      time.Time
+	Overdue    bool // Somente títulos em aberto com vencimento anterior a hoje
+}
+
+// CalendarDay totaliza os títulos em aberto de um dia de vencimento
+type CalendarDay struct {
+	Date    time.Time `json:"date"`
+	Count   int       `json:"count"`
+	Amount  float64   `json:"amount"`  // Valor original dos títulos
+	Balance float64   `json:"balance"` // Saldo em aberto
+}
+
+// Repository define a interface para operações de repositório de contas a pagar
+type Repository interface {
+	// Create grava um ou mais títulos em uma única transação
+	Create(ctx context.Context, payables ...*Payable) error
+
+	// FindByID busca um título pelo ID, incluindo as baixas
+	FindByID(ctx context.Context, id string) (*Payable, error)
+
+	// List lista os títulos com filtros e paginação, ordenados por vencimento
+	List(ctx context.Context, filter ListFilter, limit, offset int) ([]*Payable, error)
+
+	// Count conta os títulos que atendem aos filtros
+	Count(ctx context.Context, filter ListFilter) (int, error)
+
+	// RegisterPayment grava uma baixa e atualiza o saldo do título
+	RegisterPayment(ctx context.Context, p *Payable, payment *Payment) error
+
+	// Cancel grava o cancelamento de um título
+	Cancel(ctx context.Context, p *Payable) error
+
+	// Calendar totaliza os títulos em aberto por dia de vencimento
+	Calendar(ctx context.Context, filter ListFilter) ([]CalendarDay, error)
+}
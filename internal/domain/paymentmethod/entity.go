@@ -0,0 +1,214 @@
+package paymentmethod
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID         = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName             = errors.New("nome da forma de pagamento é obrigatório")
+	ErrInvalidKind           = errors.New("tipo inválido, use cash, debit, credit, pix, voucher, on_account ou meal_voucher")
+	ErrInvalidTPag           = errors.New("código tPag da NFC-e inválido")
+	ErrInvalidInstallments   = errors.New("número máximo de parcelas deve ser ao menos 1")
+	ErrInstallmentsNotCredit = errors.New("parcelamento só é permitido para cartão de crédito e crediário")
+	ErrInactive              = errors.New("forma de pagamento inativa")
+	ErrInvalidAmount         = errors.New("valor do pagamento deve ser maior que zero")
+	ErrInstallmentsExceeded  = errors.New("número de parcelas acima do permitido para a forma de pagamento")
+	ErrChangeNotAllowed      = errors.New("forma de pagamento não permite troco")
+	ErrInsufficientTendered  = errors.New("valor entregue menor que o valor do pagamento")
+	ErrCustomerRequired      = errors.New("venda no crediário exige cliente identificado")
+	ErrPaymentsMismatch      = errors.New("soma dos pagamentos diferente do total da venda")
+)
+
+// Kind define o tipo da forma de pagamento
+type Kind string
+
+const (
+	KindCash        Kind = "cash"         // Dinheiro
+	KindDebit       Kind = "debit"        // Cartão de débito
+	KindCredit      Kind = "credit"       // Cartão de crédito
+	KindPix         Kind = "pix"          // Pix
+	KindVoucher     Kind = "voucher"      // Vale presente
+	KindOnAccount   Kind = "on_account"   // Crediário / crédito loja
+	KindMealVoucher Kind = "meal_voucher" // Vale refeição / alimentação
+)
+
+// Códigos tPag do grupo de pagamento da NFC-e
+var tPagDescriptions = map[string]string{
+	"01": "Dinheiro",
+	"02": "Cheque",
+	"03": "Cartão de Crédito",
+	"04": "Cartão de Débito",
+	"05": "Crédito Loja",
+	"10": "Vale Alimentação",
+	"11": "Vale Refeição",
+	"12": "Vale Presente",
+	"13": "Vale Combustível",
+	"15": "Boleto Bancário",
+	"16": "Depósito Bancário",
+	"17": "Pagamento Instantâneo (PIX)",
+	"18": "Transferência bancária, Carteira Digital",
+	"19": "Programa de fidelidade, Cashback, Crédito Virtual",
+	"20": "Pagamento Instantâneo (PIX) – Estático",
+	"90": "Sem pagamento",
+	"99": "Outros",
+}
+
+// defaultTPag relaciona cada tipo ao seu código tPag padrão
+var defaultTPag = map[Kind]string{
+	KindCash:        "01",
+	KindDebit:       "04",
+	KindCredit:      "03",
+	KindPix:         "17",
+	KindVoucher:     "12",
+	KindOnAccount:   "05",
+	KindMealVoucher: "11",
+}
+
+// PaymentMethod representa uma forma de pagamento configurada pelo tenant
+type PaymentMethod struct {
+	ID              string    `json:"id"`
+	TenantID        string    `json:"tenant_id"`
+	Name            string    `json:"name"`
+	Kind            Kind      `json:"kind"`
+	TPag            string    `json:"tpag"`             // Código do meio de pagamento na NFC-e
+	MaxInstallments int       `json:"max_installments"` // Parcelas permitidas; 1 para pagamento à vista
+	ChangeAllowed   bool      `json:"change_allowed"`   // Aceita valor entregue maior que o devido, com troco
+	RequiresTEF     bool      `json:"requires_tef"`     // Exige transação no TEF/POS integrado
+	Active          bool      `json:"active"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NewPaymentMethod cria uma forma de pagamento com os padrões do tipo: tPag correspondente,
+// troco apenas em dinheiro e TEF para cartões
+func NewPaymentMethod(tenantID, name string, kind Kind) *PaymentMethod {
+	now := time.Now()
+	return &PaymentMethod{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		Name:            name,
+		Kind:            kind,
+		TPag:            defaultTPag[kind],
+		MaxInstallments: 1,
+		ChangeAllowed:   kind == KindCash,
+		RequiresTEF:     kind == KindDebit || kind == KindCredit,
+		Active:          true,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+}
+
+// Validate verifica os dados da forma de pagamento
+func (m *PaymentMethod) Validate() error {
+	if m.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if m.Name == "" {
+		return ErrEmptyName
+	}
+	if _, ok := defaultTPag[m.Kind]; !ok {
+		return ErrInvalidKind
+	}
+	if _, ok := tPagDescriptions[m.TPag]; !ok {
+		return ErrInvalidTPag
+	}
+	if m.MaxInstallments < 1 {
+		return ErrInvalidInstallments
+	}
+	if m.MaxInstallments > 1 && m.Kind != KindCredit && m.Kind != KindOnAccount {
+		return ErrInstallmentsNotCredit
+	}
+	return nil
+}
+
+// TPagDescription retorna a descrição oficial do código tPag
+func (m *PaymentMethod) TPagDescription() string {
+	return tPagDescriptions[m.TPag]
+}
+
+// Payment representa um pagamento conferido no PDV, com os dados do grupo detPag da NFC-e
+type Payment struct {
+	PaymentMethodID string  `json:"payment_method_id"`
+	Name            string  `json:"name"`
+	TPag            string  `json:"tpag"`
+	XPag            string  `json:"xpag"`    // Descrição do meio de pagamento
+	IndPag          int     `json:"ind_pag"` // 0 à vista, 1 a prazo
+	Amount          float64 `json:"amount"`  // vPag
+	Tendered        float64 `json:"tendered"`
+	Change          float64 `json:"change"` // vTroco
+	Installments    int     `json:"installments"`
+	RequiresTEF     bool    `json:"requires_tef"`
+}
+
+// Check confere um pagamento no PDV: situação da forma, parcelas, troco e identificação do cliente.
+// tendered zero equivale ao valor exato
+func (m *PaymentMethod) Check(amount, tendered float64, installments int, customerID string) (*Payment, error) {
+	if !m.Active {
+		return nil, ErrInactive
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if installments < 1 {
+		installments = 1
+	}
+	if installments > m.MaxInstallments {
+		return nil, ErrInstallmentsExceeded
+	}
+	if m.Kind == KindOnAccount && customerID == "" {
+		return nil, ErrCustomerRequired
+	}
+
+	amount = round(amount)
+	if tendered == 0 {
+		tendered = amount
+	}
+	tendered = round(tendered)
+	if tendered < amount {
+		return nil, ErrInsufficientTendered
+	}
+	if tendered > amount && !m.ChangeAllowed {
+		return nil, ErrChangeNotAllowed
+	}
+
+	indPag := 0
+	if installments > 1 || m.Kind == KindOnAccount {
+		indPag = 1
+	}
+
+	return &Payment{
+		PaymentMethodID: m.ID,
+		Name:            m.Name,
+		TPag:            m.TPag,
+		XPag:            m.TPagDescription(),
+		IndPag:          indPag,
+		Amount:          amount,
+		Tendered:        tendered,
+		Change:          round(tendered - amount),
+		Installments:    installments,
+		RequiresTEF:     m.RequiresTEF,
+	}, nil
+}
+
+// Settle confere se os pagamentos quitam exatamente o total da venda e retorna o troco total (vTroco)
+func Settle(total float64, payments []*Payment) (float64, error) {
+	paid, change := 0.0, 0.0
+	for _, p := range payments {
+		paid += p.Amount
+		change += p.Change
+	}
+	if round(paid) != round(total) {
+		return 0, ErrPaymentsMismatch
+	}
+	return round(change), nil
+}
+
+// round arredonda valores monetários para centavos
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,30 @@
+package paymentmethod
+
+import "context"
+
+// Filter define os filtros para listagem de formas de pagamento
+type Filter struct {
+	Kind   Kind
+	Active *bool
+}
+
+// Repository define a interface para operações de repositório de formas de pagamento
+type Repository interface {
+	// Create grava uma nova forma de pagamento
+	Create(ctx context.Context, m *PaymentMethod) error
+
+	// Update atualiza uma forma de pagamento
+	Update(ctx context.Context, m *PaymentMethod) error
+
+	// Delete remove uma forma de pagamento que não esteja vinculada a clientes
+	Delete(ctx context.Context, id string) error
+
+	// FindByID busca uma forma de pagamento pelo ID
+	FindByID(ctx context.Context, id string) (*PaymentMethod, error)
+
+	// List lista as formas de pagamento com filtros e paginação
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*PaymentMethod, error)
+
+	// Count conta as formas de pagamento que atendem aos filtros
+	Count(ctx context.Context, filter Filter) (int, error)
+}
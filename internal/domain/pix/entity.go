@@ -0,0 +1,237 @@
+package pix
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pkgpix "github.com/hugohenrick/erp-supermercado/pkg/pix"
+)
+
+var (
+	ErrEmptyTenantID          = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyBranchID          = errors.New("ID da filial não pode ser vazio")
+	ErrInvalidKind            = errors.New("tipo de cobrança inválido, use static ou dynamic")
+	ErrInvalidProvider        = errors.New("provedor Pix inválido, use fake ou api")
+	ErrProviderNotConfigured  = errors.New("cobranças dinâmicas exigem um PSP configurado")
+	ErrMissingCredentials     = errors.New("URL e credenciais do PSP são obrigatórias")
+	ErrMissingWebhookSecret   = errors.New("segredo do webhook é obrigatório com um PSP configurado")
+	ErrInvalidAmount          = errors.New("valor da cobrança deve ser maior que zero")
+	ErrMissingOrigin          = errors.New("cobrança deve estar vinculada a uma venda ou título a receber")
+	ErrChargeNotActive        = errors.New("cobrança Pix não está ativa")
+	ErrAmountMismatch         = errors.New("valor pago é menor que o valor da cobrança")
+	ErrReceivableNotOpen      = errors.New("título a receber não está em aberto")
+	ErrNotificationProcessed  = errors.New("notificação Pix já processada")
+	ErrSettingsNotConfigured  = errors.New("recebimento via Pix não configurado")
+	ErrInvalidWebhookProvider = errors.New("provedor não recebe notificações por webhook")
+	ErrSimulationDisabled     = errors.New("simulação de pagamento Pix desabilitada neste ambiente")
+)
+
+// ChargeKind define o tipo de BR Code da cobrança
+type ChargeKind string
+
+const (
+	KindStatic  ChargeKind = "static"  // QR Code gerado localmente com a chave Pix
+	KindDynamic ChargeKind = "dynamic" // QR Code com location de uma cobrança criada no PSP
+)
+
+// ChargeStatus define a situação de uma cobrança Pix
+type ChargeStatus string
+
+const (
+	ChargeActive    ChargeStatus = "active"
+	ChargePaid      ChargeStatus = "paid"
+	ChargeCancelled ChargeStatus = "cancelled"
+)
+
+// NotificationStatus define o resultado do processamento de uma notificação do PSP
+type NotificationStatus string
+
+const (
+	NotificationSettled   NotificationStatus = "settled"   // Cobrança liquidada
+	NotificationUnmatched NotificationStatus = "unmatched" // Nenhuma cobrança com o txid informado
+	NotificationRejected  NotificationStatus = "rejected"  // Cobrança encontrada, mas não pôde ser liquidada
+)
+
+// Provedores de PSP suportados
+const (
+	ProviderNone = ""
+	ProviderFake = "fake"
+	ProviderAPI  = "api"
+)
+
+// Settings representa a configuração de recebimento via Pix do tenant
+type Settings struct {
+	TenantID      string    `json:"tenant_id"`
+	Key           string    `json:"key"` // Chave Pix do recebedor
+	MerchantName  string    `json:"merchant_name"`
+	MerchantCity  string    `json:"merchant_city"`
+	PostalCode    string    `json:"postal_code"`
+	Provider      string    `json:"provider"` // fake, api ou vazio para somente QR Code estático
+	BaseURL       string    `json:"base_url"`
+	ClientID      string    `json:"client_id"`
+	ClientSecret  string    `json:"-"`
+	WebhookSecret string    `json:"-"`
+	ExpiresIn     int       `json:"expires_in"` // Validade das cobranças dinâmicas, em segundos
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// Validate verifica os dados obrigatórios da configuração
+func (s *Settings) Validate() error {
+	if s.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if strings.TrimSpace(s.Key) == "" {
+		return pkgpix.ErrEmptyKey
+	}
+	if strings.TrimSpace(s.MerchantName) == "" || strings.TrimSpace(s.MerchantCity) == "" {
+		return pkgpix.ErrEmptyMerchant
+	}
+	switch s.Provider {
+	case ProviderNone:
+		return nil
+	case ProviderFake:
+	case ProviderAPI:
+		if s.BaseURL == "" || s.ClientID == "" || s.ClientSecret == "" {
+			return ErrMissingCredentials
+		}
+	default:
+		return ErrInvalidProvider
+	}
+
+	// O webhook é público: sem segredo, qualquer um poderia liquidar cobranças
+	if s.WebhookSecret == "" {
+		return ErrMissingWebhookSecret
+	}
+	return nil
+}
+
+// Charge representa uma cobrança Pix vinculada a uma venda ou a um título a receber
+type Charge struct {
+	ID           string       `json:"id"`
+	TenantID     string       `json:"tenant_id"`
+	BranchID     string       `json:"branch_id"`
+	Kind         ChargeKind   `json:"kind"`
+	TxID         string       `json:"txid"`
+	SaleID       string       `json:"sale_id"`
+	ReceivableID string       `json:"receivable_id"`
+	CustomerID   string       `json:"customer_id"`
+	Amount       float64      `json:"amount"`
+	Description  string       `json:"description"`
+	BRCode       string       `json:"br_code"`  // Pix Copia e Cola
+	Location     string       `json:"location"` // Location no PSP (somente dinâmica)
+	Provider     string       `json:"provider"`
+	Status       ChargeStatus `json:"status"`
+	EndToEndID   string       `json:"end_to_end_id"`
+	PaidAmount   float64      `json:"paid_amount"`
+	PaidAt       *time.Time   `json:"paid_at"`
+	ExpiresAt    *time.Time   `json:"expires_at"`
+	CreatedBy    string       `json:"created_by"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+}
+
+// NewCharge cria uma cobrança Pix com txid próprio; o BR Code é montado em seguida com Payload
+func NewCharge(tenantID, branchID string, kind ChargeKind, amount float64, description, createdBy string) (*Charge, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	if branchID == "" {
+		return nil, ErrEmptyBranchID
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	// O BR Code estático aceita txid de até 25 caracteres; a cobrança dinâmica exige de 26 a 35
+	var txID string
+	switch kind {
+	case KindStatic:
+		txID = pkgpix.NewTxID(25)
+	case KindDynamic:
+		txID = pkgpix.NewTxID(32)
+	default:
+		return nil, ErrInvalidKind
+	}
+
+	now := time.Now()
+	return &Charge{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		BranchID:    branchID,
+		Kind:        kind,
+		TxID:        txID,
+		Amount:      math.Round(amount*100) / 100,
+		Description: strings.TrimSpace(description),
+		Status:      ChargeActive,
+		CreatedBy:   createdBy,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Payload monta os dados do BR Code da cobrança com a configuração do recebedor
+func (c *Charge) Payload(settings *Settings) *pkgpix.Payload {
+	p := &pkgpix.Payload{
+		MerchantName: settings.MerchantName,
+		MerchantCity: settings.MerchantCity,
+		PostalCode:   settings.PostalCode,
+		Amount:       c.Amount,
+		TxID:         c.TxID,
+	}
+	if c.Kind == KindDynamic {
+		p.URL = c.Location
+	} else {
+		p.Key = settings.Key
+		p.Description = c.Description
+	}
+	return p
+}
+
+// Pay liquida a cobrança com um Pix recebido
+func (c *Charge) Pay(n pkgpix.Notification) error {
+	if c.Status != ChargeActive {
+		return ErrChargeNotActive
+	}
+	if math.Round(n.Amount*100) < math.Round(c.Amount*100) {
+		return ErrAmountMismatch
+	}
+
+	paidAt := n.PaidAt
+	c.Status = ChargePaid
+	c.EndToEndID = n.EndToEndID
+	c.PaidAmount = math.Round(n.Amount*100) / 100
+	c.PaidAt = &paidAt
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// Cancel cancela uma cobrança ainda não paga
+func (c *Charge) Cancel() error {
+	if c.Status != ChargeActive {
+		return ErrChargeNotActive
+	}
+	c.Status = ChargeCancelled
+	c.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsExpired verifica se a cobrança dinâmica passou da validade
+func (c *Charge) IsExpired(reference time.Time) bool {
+	return c.ExpiresAt != nil && reference.After(*c.ExpiresAt)
+}
+
+// Notification representa o registro de um Pix recebido via webhook, usado para garantir a idempotência
+type Notification struct {
+	EndToEndID string             `json:"end_to_end_id"`
+	TxID       string             `json:"txid"`
+	ChargeID   string             `json:"charge_id"`
+	Amount     float64            `json:"amount"`
+	PaidAt     time.Time          `json:"paid_at"`
+	PayerInfo  string             `json:"payer_info"`
+	Status     NotificationStatus `json:"status"`
+	Error      string             `json:"error"`
+	ReceivedAt time.Time          `json:"received_at"`
+}
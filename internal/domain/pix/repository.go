@@ -0,0 +1,51 @@
+package pix
+
+import (
+	"context"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/receivable"
+	pkgpix "github.com/hugohenrick/erp-supermercado/pkg/pix"
+)
+
+// ChargeFilter define os filtros para listagem de cobranças Pix
+type ChargeFilter struct {
+	BranchID     string
+	SaleID       string
+	ReceivableID string
+	Status       ChargeStatus
+}
+
+// SettleFunc liquida a cobrança encontrada para a notificação.
+// Quando a cobrança pertence a um título, devolve o título atualizado e o recebimento a gravar na mesma transação
+type SettleFunc func(c *Charge) (*receivable.Receivable, *receivable.Payment, error)
+
+// Repository define a interface para operações de repositório de Pix
+type Repository interface {
+	// FindSettings busca a configuração de Pix do tenant
+	FindSettings(ctx context.Context) (*Settings, error)
+
+	// SaveSettings grava a configuração de Pix do tenant
+	SaveSettings(ctx context.Context, settings *Settings) error
+
+	// Create grava uma nova cobrança
+	Create(ctx context.Context, charge *Charge) error
+
+	// FindByID busca uma cobrança pelo ID
+	FindByID(ctx context.Context, id string) (*Charge, error)
+
+	// List lista as cobranças com filtros e paginação
+	List(ctx context.Context, filter ChargeFilter, limit, offset int) ([]*Charge, error)
+
+	// Count conta as cobranças que atendem aos filtros
+	Count(ctx context.Context, filter ChargeFilter) (int, error)
+
+	// Cancel grava o cancelamento de uma cobrança ativa
+	Cancel(ctx context.Context, charge *Charge) error
+
+	// Settle registra a notificação e liquida a cobrança do txid em uma única transação.
+	// Notificações repetidas (mesmo endToEndId) retornam ErrNotificationProcessed sem efeito
+	Settle(ctx context.Context, n pkgpix.Notification, settle SettleFunc) (*Charge, error)
+
+	// ListNotifications lista as notificações recebidas, opcionalmente por situação
+	ListNotifications(ctx context.Context, status NotificationStatus, limit, offset int) ([]*Notification, error)
+}
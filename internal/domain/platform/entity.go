@@ -0,0 +1,87 @@
+package platform
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	ErrEmptyName    = errors.New("nome do operador é obrigatório")
+	ErrEmptyEmail   = errors.New("email do operador é obrigatório")
+	ErrWeakPassword = errors.New("senha do operador deve ter no mínimo 12 caracteres")
+)
+
+// MinPasswordLength é o tamanho mínimo da senha de um operador, maior que o dos usuários dos
+// tenants porque o operador administra todos eles
+const MinPasswordLength = 12
+
+// Status define a situação do operador
+type Status string
+
+const (
+	StatusActive   Status = "active"   // Pode se autenticar
+	StatusInactive Status = "inactive" // Acesso revogado
+)
+
+// Operator é um operador da plataforma: administra os tenants, os planos e a medição de consumo.
+// Não pertence a nenhum tenant e não tem acesso aos dados deles pelas rotas dos tenants
+type Operator struct {
+	ID          string     `json:"id"`
+	Name        string     `json:"name"`
+	Email       string     `json:"email"`
+	Password    string     `json:"-"`
+	Status      Status     `json:"status"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// NewOperator cria um operador ativo com a senha informada
+func NewOperator(name, email, password string) (*Operator, error) {
+	now := time.Now()
+	o := &Operator{
+		ID:        uuid.New().String(),
+		Name:      strings.TrimSpace(name),
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		Status:    StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	switch {
+	case o.Name == "":
+		return nil, ErrEmptyName
+	case o.Email == "":
+		return nil, ErrEmptyEmail
+	}
+	if err := o.SetPassword(password); err != nil {
+		return nil, err
+	}
+	return o, nil
+}
+
+// SetPassword configura a senha do operador com hash
+func (o *Operator) SetPassword(password string) error {
+	if len(password) < MinPasswordLength {
+		return ErrWeakPassword
+	}
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	o.Password = string(hashedPassword)
+	return nil
+}
+
+// CheckPassword verifica se a senha fornecida é válida
+func (o *Operator) CheckPassword(password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(o.Password), []byte(password)) == nil
+}
+
+// IsActive verifica se o operador está ativo
+func (o *Operator) IsActive() bool {
+	return o.Status == StatusActive
+}
@@ -0,0 +1,24 @@
+package platform
+
+import "context"
+
+// Repository define a interface para operações de repositório de operadores da plataforma
+type Repository interface {
+	// Create grava um novo operador
+	Create(ctx context.Context, o *Operator) error
+
+	// FindByID busca um operador pelo ID
+	FindByID(ctx context.Context, id string) (*Operator, error)
+
+	// FindByEmail busca um operador pelo email, sem diferenciar maiúsculas
+	FindByEmail(ctx context.Context, email string) (*Operator, error)
+
+	// List lista todos os operadores
+	List(ctx context.Context) ([]*Operator, error)
+
+	// UpdateStatus ativa ou desativa um operador
+	UpdateStatus(ctx context.Context, id string, status Status) error
+
+	// UpdateLastLogin registra o último login do operador
+	UpdateLastLogin(ctx context.Context, id string) error
+}
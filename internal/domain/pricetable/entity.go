@@ -0,0 +1,183 @@
+package pricetable
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+)
+
+var (
+	ErrEmptyTenantID        = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName            = errors.New("nome da tabela de preços é obrigatório")
+	ErrInvalidPercentage    = errors.New("percentual da tabela deve ser maior que -100%")
+	ErrInvalidPeriod        = errors.New("fim da vigência deve ser posterior ao início")
+	ErrInvalidCustomerType  = errors.New("tipo de cliente inválido, use final, reseller ou wholesale")
+	ErrEmptyItemProduct     = errors.New("item da tabela sem produto")
+	ErrInvalidItemPrice     = errors.New("preço fixo do produto deve ser maior que zero")
+	ErrDuplicatedItem       = errors.New("produto informado mais de uma vez na tabela")
+	ErrInvalidQuoteQuantity = errors.New("quantidade do item deve ser maior que zero")
+)
+
+// Item representa o preço fixo de um produto na tabela
+type Item struct {
+	ProductID string  `json:"product_id"`
+	Price     float64 `json:"price"`
+}
+
+// PriceTable representa uma tabela de preços diferenciada para clientes.
+// Produtos com preço fixo usam esse preço; os demais recebem o percentual sobre o preço de venda base
+type PriceTable struct {
+	ID            string                  `json:"id"`
+	TenantID      string                  `json:"tenant_id"`
+	Name          string                  `json:"name"`
+	Percentage    float64                 `json:"percentage"`     // Acréscimo (positivo) ou desconto (negativo) sobre o preço base
+	BranchIDs     []string                `json:"branch_ids"`     // Filiais onde a tabela vale; vazio para todas
+	CustomerTypes []customer.CustomerType `json:"customer_types"` // Tabela padrão para clientes desses tipos sem tabela própria
+	ValidFrom     time.Time               `json:"valid_from"`
+	ValidUntil    *time.Time              `json:"valid_until"` // Sem fim de vigência quando nulo
+	Active        bool                    `json:"active"`
+	Items         []Item                  `json:"items"`
+	CreatedAt     time.Time               `json:"created_at"`
+	UpdatedAt     time.Time               `json:"updated_at"`
+}
+
+// NewPriceTable cria uma tabela de preços ativa a partir de validFrom
+func NewPriceTable(tenantID, name string, percentage float64, validFrom time.Time) *PriceTable {
+	now := time.Now()
+	return &PriceTable{
+		ID:            uuid.New().String(),
+		TenantID:      tenantID,
+		Name:          name,
+		Percentage:    percentage,
+		BranchIDs:     []string{},
+		CustomerTypes: []customer.CustomerType{},
+		ValidFrom:     validFrom,
+		Active:        true,
+		Items:         []Item{},
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Validate verifica os dados da tabela e dos preços fixos
+func (t *PriceTable) Validate() error {
+	if t.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if t.Name == "" {
+		return ErrEmptyName
+	}
+	if t.Percentage <= -100 {
+		return ErrInvalidPercentage
+	}
+	if t.ValidUntil != nil && !t.ValidUntil.After(t.ValidFrom) {
+		return ErrInvalidPeriod
+	}
+
+	for _, customerType := range t.CustomerTypes {
+		switch customerType {
+		case customer.TypeFinal, customer.TypeReseller, customer.TypeWholesale:
+		default:
+			return ErrInvalidCustomerType
+		}
+	}
+
+	products := make(map[string]bool, len(t.Items))
+	for _, item := range t.Items {
+		if item.ProductID == "" {
+			return ErrEmptyItemProduct
+		}
+		if item.Price <= 0 {
+			return ErrInvalidItemPrice
+		}
+		if products[item.ProductID] {
+			return ErrDuplicatedItem
+		}
+		products[item.ProductID] = true
+	}
+
+	return nil
+}
+
+// AppliesTo indica se a tabela está ativa e vigente na filial no momento informado
+func (t *PriceTable) AppliesTo(branchID string, at time.Time) bool {
+	if !t.Active || at.Before(t.ValidFrom) {
+		return false
+	}
+	if t.ValidUntil != nil && !at.Before(*t.ValidUntil) {
+		return false
+	}
+	if len(t.BranchIDs) == 0 {
+		return true
+	}
+	for _, id := range t.BranchIDs {
+		if id == branchID {
+			return true
+		}
+	}
+	return false
+}
+
+// PriceFor retorna o preço do produto na tabela: o preço fixo, se houver, ou o preço base com o percentual
+func (t *PriceTable) PriceFor(productID string, basePrice float64) float64 {
+	for _, item := range t.Items {
+		if item.ProductID == productID {
+			return item.Price
+		}
+	}
+	return round(basePrice * (1 + t.Percentage/100))
+}
+
+// Quote representa um produto a ser precificado
+type Quote struct {
+	ProductID string  `json:"product_id"`
+	Quantity  float64 `json:"quantity"`
+}
+
+// QuotedItem representa o preço resolvido de um produto
+type QuotedItem struct {
+	ProductID string  `json:"product_id"`
+	Quantity  float64 `json:"quantity"`
+	BasePrice float64 `json:"base_price"`
+	UnitPrice float64 `json:"unit_price"`
+	Total     float64 `json:"total"`
+}
+
+// Resolve precifica os produtos a partir dos preços base, aplicando a tabela quando informada
+func Resolve(table *PriceTable, quotes []Quote, basePrices map[string]float64) ([]QuotedItem, float64, error) {
+	items := make([]QuotedItem, 0, len(quotes))
+	total := 0.0
+	for _, quote := range quotes {
+		if quote.ProductID == "" {
+			return nil, 0, ErrEmptyItemProduct
+		}
+		if quote.Quantity <= 0 {
+			return nil, 0, ErrInvalidQuoteQuantity
+		}
+
+		base := basePrices[quote.ProductID]
+		unitPrice := base
+		if table != nil {
+			unitPrice = table.PriceFor(quote.ProductID, base)
+		}
+
+		item := QuotedItem{
+			ProductID: quote.ProductID,
+			Quantity:  quote.Quantity,
+			BasePrice: base,
+			UnitPrice: unitPrice,
+			Total:     round(unitPrice * quote.Quantity),
+		}
+		total += item.Total
+		items = append(items, item)
+	}
+	return items, round(total), nil
+}
+
+// round arredonda valores monetários para centavos
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,42 @@
+package pricetable
+
+import (
+	"context"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+)
+
+// Filter define os filtros para listagem de tabelas de preços
+type Filter struct {
+	BranchID string
+	Active   *bool
+}
+
+// Repository define a interface para operações de repositório de tabelas de preços
+type Repository interface {
+	// Create grava uma nova tabela com seus preços fixos
+	Create(ctx context.Context, t *PriceTable) error
+
+	// Update atualiza a tabela e substitui seus preços fixos
+	Update(ctx context.Context, t *PriceTable) error
+
+	// Delete remove uma tabela que não esteja vinculada a clientes
+	Delete(ctx context.Context, id string) error
+
+	// FindByID busca uma tabela pelo ID com seus preços fixos
+	FindByID(ctx context.Context, id string) (*PriceTable, error)
+
+	// List lista as tabelas com filtros e paginação, sem os preços fixos
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*PriceTable, error)
+
+	// Count conta as tabelas que atendem aos filtros
+	Count(ctx context.Context, filter Filter) (int, error)
+
+	// FindForCustomer busca a tabela que vale para o cliente na filial: a tabela própria do cliente,
+	// se vigente, ou a tabela padrão do seu tipo. Retorna nil quando nenhuma se aplica
+	FindForCustomer(ctx context.Context, c *customer.Customer, branchID string, at time.Time) (*PriceTable, error)
+
+	// BasePrices retorna o preço de venda cadastrado dos produtos ativos
+	BasePrices(ctx context.Context, productIDs []string) (map[string]float64, error)
+}
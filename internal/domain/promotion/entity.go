@@ -0,0 +1,577 @@
+package promotion
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID     = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName         = errors.New("nome da promoção é obrigatório")
+	ErrInvalidType       = errors.New("tipo de promoção inválido, use take_pay, tiered, combo ou price")
+	ErrInvalidPeriod     = errors.New("data final da promoção deve ser posterior à inicial")
+	ErrEmptyProducts     = errors.New("promoção deve ter ao menos um produto")
+	ErrInvalidTakePay    = errors.New("leve N pague M exige N maior que M e M de ao menos uma unidade")
+	ErrEmptyTiers        = errors.New("desconto progressivo deve ter ao menos uma faixa")
+	ErrInvalidTier       = errors.New("faixa de desconto inválida: quantidade mínima maior que zero e desconto entre 0 e 100%")
+	ErrDuplicatedTier    = errors.New("faixas de desconto com a mesma quantidade mínima")
+	ErrEmptyComboItems   = errors.New("combo deve ter ao menos um componente")
+	ErrInvalidComboItem  = errors.New("componente do combo deve ter produtos e quantidade de ao menos uma unidade")
+	ErrInvalidPrice      = errors.New("preço promocional deve ser maior que zero")
+	ErrInvalidQuantity   = errors.New("quantidade do item deve ser maior que zero")
+	ErrQuantityTooLarge  = errors.New("quantidade do produto na venda excede o máximo de 99999")
+	ErrInvalidUnitPrice  = errors.New("preço unitário do item não pode ser negativo")
+	ErrEmptyCartProducts = errors.New("item sem produto")
+
+	ErrInvalidManualDiscount = errors.New("desconto manual deve estar entre 0 e 100%")
+)
+
+// ManualDiscountLimit é o desconto manual máximo (%) que o operador concede sem a permissão
+// pdv.discount.above_10
+const ManualDiscountLimit = 10.0
+
+// MaxItemQuantity é a quantidade máxima de um produto na venda, somadas as linhas repetidas
+const MaxItemQuantity = 99999.0
+
+// Type define a mecânica da promoção
+type Type string
+
+const (
+	TypeTakePay Type = "take_pay" // Leve N pague M; as unidades mais baratas de cada grupo saem de graça
+	TypeTiered  Type = "tiered"   // Desconto progressivo pela quantidade levada
+	TypeCombo   Type = "combo"    // Combinação de produtos por um preço fechado
+	TypePrice   Type = "price"    // Preço promocional, normalmente exclusivo do clube (members_only)
+)
+
+// Tier representa uma faixa do desconto progressivo
+type Tier struct {
+	MinQuantity     float64 `json:"min_quantity"`
+	DiscountPercent float64 `json:"discount_percent"`
+}
+
+// ComboItem representa um componente do combo, que pode ser preenchido por qualquer um dos produtos listados
+type ComboItem struct {
+	ProductIDs []string `json:"product_ids"`
+	Quantity   int      `json:"quantity"`
+}
+
+// Promotion representa uma promoção de preço aplicada no PDV
+type Promotion struct {
+	ID           string      `json:"id"`
+	TenantID     string      `json:"tenant_id"`
+	Name         string      `json:"name"`
+	Type         Type        `json:"type"`
+	BranchIDs    []string    `json:"branch_ids"`  // Filiais participantes; vazio para todas
+	ProductIDs   []string    `json:"product_ids"` // Produtos de take_pay, tiered e price, que se somam entre si
+	TakeQuantity int         `json:"take_quantity,omitempty"`
+	PayQuantity  int         `json:"pay_quantity,omitempty"`
+	Tiers        []Tier      `json:"tiers,omitempty"`
+	ComboItems   []ComboItem `json:"combo_items,omitempty"`
+	ComboPrice   float64     `json:"combo_price,omitempty"`
+	Price        float64     `json:"price,omitempty"`
+	MembersOnly  bool        `json:"members_only"` // Exige cliente identificado no caixa
+	Priority     int         `json:"priority"`     // Desempate entre promoções com o mesmo desconto
+	StartsAt     time.Time   `json:"starts_at"`
+	EndsAt       time.Time   `json:"ends_at"`
+	Active       bool        `json:"active"`
+	CreatedAt    time.Time   `json:"created_at"`
+	UpdatedAt    time.Time   `json:"updated_at"`
+}
+
+// NewPromotion cria uma promoção; os parâmetros de cada mecânica são preenchidos antes de Validate
+func NewPromotion(tenantID, name string, promotionType Type, startsAt, endsAt time.Time) *Promotion {
+	now := time.Now()
+	return &Promotion{
+		ID:         uuid.New().String(),
+		TenantID:   tenantID,
+		Name:       strings.TrimSpace(name),
+		Type:       promotionType,
+		BranchIDs:  []string{},
+		ProductIDs: []string{},
+		StartsAt:   startsAt,
+		EndsAt:     endsAt,
+		Active:     true,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+}
+
+// Validate verifica os dados da promoção conforme a mecânica
+func (p *Promotion) Validate() error {
+	if p.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if p.Name == "" {
+		return ErrEmptyName
+	}
+	if p.StartsAt.IsZero() || !p.EndsAt.After(p.StartsAt) {
+		return ErrInvalidPeriod
+	}
+
+	switch p.Type {
+	case TypeTakePay:
+		if len(p.ProductIDs) == 0 {
+			return ErrEmptyProducts
+		}
+		if p.PayQuantity < 1 || p.TakeQuantity <= p.PayQuantity {
+			return ErrInvalidTakePay
+		}
+	case TypeTiered:
+		if len(p.ProductIDs) == 0 {
+			return ErrEmptyProducts
+		}
+		if len(p.Tiers) == 0 {
+			return ErrEmptyTiers
+		}
+		seen := make(map[float64]bool)
+		for _, t := range p.Tiers {
+			if t.MinQuantity <= 0 || t.DiscountPercent <= 0 || t.DiscountPercent >= 100 {
+				return ErrInvalidTier
+			}
+			if seen[t.MinQuantity] {
+				return ErrDuplicatedTier
+			}
+			seen[t.MinQuantity] = true
+		}
+		sort.Slice(p.Tiers, func(i, j int) bool { return p.Tiers[i].MinQuantity < p.Tiers[j].MinQuantity })
+	case TypeCombo:
+		if len(p.ComboItems) == 0 {
+			return ErrEmptyComboItems
+		}
+		for _, item := range p.ComboItems {
+			if len(item.ProductIDs) == 0 || item.Quantity < 1 {
+				return ErrInvalidComboItem
+			}
+		}
+		if p.ComboPrice <= 0 {
+			return ErrInvalidPrice
+		}
+	case TypePrice:
+		if len(p.ProductIDs) == 0 {
+			return ErrEmptyProducts
+		}
+		if p.Price <= 0 {
+			return ErrInvalidPrice
+		}
+	default:
+		return ErrInvalidType
+	}
+
+	return nil
+}
+
+// AppliesTo verifica se a promoção vale para a filial, o cliente e o momento da venda
+func (p *Promotion) AppliesTo(branchID string, member bool, at time.Time) bool {
+	if !p.Active || at.Before(p.StartsAt) || !at.Before(p.EndsAt) {
+		return false
+	}
+	if p.MembersOnly && !member {
+		return false
+	}
+	if len(p.BranchIDs) == 0 {
+		return true
+	}
+	for _, id := range p.BranchIDs {
+		if id == branchID {
+			return true
+		}
+	}
+	return false
+}
+
+// CartItem representa um item registrado no PDV
+type CartItem struct {
+	ProductID string  `json:"product_id"`
+	Quantity  float64 `json:"quantity"`   // Unidades ou peso
+	UnitPrice float64 `json:"unit_price"` // Preço de venda sem promoção
+}
+
+// Cart representa a venda em andamento no PDV
+type Cart struct {
+	BranchID       string
+	Member         bool    // Cliente identificado no caixa
+	ManualDiscount float64 // Desconto manual do operador sobre o total com promoções (%)
+	Items          []CartItem
+}
+
+// ItemResult representa o item com o desconto das promoções aplicadas
+type ItemResult struct {
+	ProductID    string   `json:"product_id"`
+	Quantity     float64  `json:"quantity"`
+	UnitPrice    float64  `json:"unit_price"`
+	Gross        float64  `json:"gross"`
+	Discount     float64  `json:"discount"`
+	Total        float64  `json:"total"`
+	PromotionIDs []string `json:"promotion_ids,omitempty"`
+}
+
+// Applied representa uma promoção aplicada na venda
+type Applied struct {
+	PromotionID string  `json:"promotion_id"`
+	Name        string  `json:"name"`
+	Type        Type    `json:"type"`
+	Times       int     `json:"times"` // Quantas vezes a mecânica foi aplicada (grupos, combos ou produtos)
+	Discount    float64 `json:"discount"`
+}
+
+// Result representa o cálculo das promoções da venda
+type Result struct {
+	Items          []ItemResult `json:"items"`
+	Applied        []Applied    `json:"applied"`
+	Gross          float64      `json:"gross"`
+	Discount       float64      `json:"discount"`        // Promoções e desconto manual
+	ManualDiscount float64      `json:"manual_discount"` // Parte do desconto concedida pelo operador
+	Total          float64      `json:"total"`
+}
+
+// application representa o efeito de uma promoção sobre as quantidades ainda livres da venda
+type application struct {
+	promotion *Promotion
+	consumed  map[string]float64
+	discount  float64
+	times     int
+}
+
+// Evaluate calcula as promoções da venda. Cada unidade recebe no máximo uma promoção: a cada rodada aplica-se
+// a promoção de maior desconto sobre as unidades livres, com desempate pela prioridade e pelo ID, de modo que
+// a mesma venda sempre produz o mesmo resultado. O desconto manual do operador incide por último, sobre o
+// total com promoções, e é rateado entre os itens
+func Evaluate(cart Cart, promotions []*Promotion, at time.Time) (*Result, error) {
+	if cart.ManualDiscount < 0 || cart.ManualDiscount >= 100 {
+		return nil, ErrInvalidManualDiscount
+	}
+
+	order := make([]string, 0)
+	quantities := make(map[string]float64)
+	prices := make(map[string]float64)
+	for _, item := range cart.Items {
+		if item.ProductID == "" {
+			return nil, ErrEmptyCartProducts
+		}
+		if item.Quantity <= 0 {
+			return nil, ErrInvalidQuantity
+		}
+		if item.UnitPrice < 0 {
+			return nil, ErrInvalidUnitPrice
+		}
+		// Linhas repetidas do mesmo produto são somadas, valendo o preço da primeira
+		if _, ok := quantities[item.ProductID]; !ok {
+			order = append(order, item.ProductID)
+			prices[item.ProductID] = item.UnitPrice
+		}
+		quantities[item.ProductID] += item.Quantity
+		if quantities[item.ProductID] > MaxItemQuantity {
+			return nil, ErrQuantityTooLarge
+		}
+	}
+
+	remaining := make(map[string]float64, len(quantities))
+	for id, qty := range quantities {
+		remaining[id] = qty
+	}
+
+	candidates := make([]*Promotion, 0, len(promotions))
+	for _, p := range promotions {
+		if p.AppliesTo(cart.BranchID, cart.Member, at) {
+			candidates = append(candidates, p)
+		}
+	}
+
+	discounts := make(map[string]float64)
+	applied := make(map[string][]string)
+	result := &Result{Items: make([]ItemResult, 0, len(order)), Applied: make([]Applied, 0)}
+
+	for len(candidates) > 0 {
+		var best *application
+		bestIndex := -1
+		for i, p := range candidates {
+			app := p.apply(remaining, prices)
+			if app.discount <= 0 {
+				continue
+			}
+			if best == nil || better(app, best) {
+				best = app
+				bestIndex = i
+			}
+		}
+		if best == nil {
+			break
+		}
+
+		for id, qty := range best.consumed {
+			remaining[id] -= qty
+		}
+		for id, value := range allocate(best.consumed, prices, best.discount) {
+			discounts[id] += value
+			applied[id] = append(applied[id], best.promotion.ID)
+		}
+		result.Applied = append(result.Applied, Applied{
+			PromotionID: best.promotion.ID,
+			Name:        best.promotion.Name,
+			Type:        best.promotion.Type,
+			Times:       best.times,
+			Discount:    best.discount,
+		})
+		candidates = append(candidates[:bestIndex], candidates[bestIndex+1:]...)
+	}
+
+	if cart.ManualDiscount > 0 {
+		ones := make(map[string]float64, len(order))
+		totals := make(map[string]float64, len(order))
+		var base float64
+		for _, id := range order {
+			ones[id] = 1
+			totals[id] = roundMoney(quantities[id]*prices[id]) - roundMoney(discounts[id])
+			base += totals[id]
+		}
+		result.ManualDiscount = roundMoney(base * cart.ManualDiscount / 100)
+		for id, value := range allocate(ones, totals, result.ManualDiscount) {
+			discounts[id] += value
+		}
+	}
+
+	for _, id := range order {
+		gross := roundMoney(quantities[id] * prices[id])
+		discount := roundMoney(discounts[id])
+		result.Items = append(result.Items, ItemResult{
+			ProductID:    id,
+			Quantity:     quantities[id],
+			UnitPrice:    prices[id],
+			Gross:        gross,
+			Discount:     discount,
+			Total:        roundMoney(gross - discount),
+			PromotionIDs: applied[id],
+		})
+		result.Gross += gross
+		result.Discount += discount
+	}
+	result.Gross = roundMoney(result.Gross)
+	result.Discount = roundMoney(result.Discount)
+	result.Total = roundMoney(result.Gross - result.Discount)
+
+	return result, nil
+}
+
+// better define a ordem de escolha entre duas aplicações: maior desconto, maior prioridade e menor ID
+func better(a, b *application) bool {
+	if a.discount != b.discount {
+		return a.discount > b.discount
+	}
+	if a.promotion.Priority != b.promotion.Priority {
+		return a.promotion.Priority > b.promotion.Priority
+	}
+	return a.promotion.ID < b.promotion.ID
+}
+
+// apply calcula o desconto da promoção sobre as quantidades livres, sem alterá-las
+func (p *Promotion) apply(remaining, prices map[string]float64) *application {
+	app := &application{promotion: p, consumed: make(map[string]float64)}
+
+	switch p.Type {
+	case TypeTakePay:
+		// Unidades ordenadas do maior para o menor preço; em cada grupo, as mais baratas saem de graça.
+		// As posições gratuitas de cada produto são contadas pela faixa que ele ocupa na fila
+		runs := unitRuns(p.ProductIDs, remaining, prices)
+		var units int
+		for _, run := range runs {
+			units += run.units
+		}
+		groups := units / p.TakeQuantity
+		limit := groups * p.TakeQuantity
+		start := 0
+		for _, run := range runs {
+			if start >= limit {
+				break
+			}
+			end := start + run.units
+			if end > limit {
+				end = limit
+			}
+			app.consumed[run.id] = float64(end - start)
+			app.discount += float64(p.freeBefore(end)-p.freeBefore(start)) * prices[run.id]
+			start = end
+		}
+		app.times = groups
+
+	case TypeTiered:
+		var quantity float64
+		for _, id := range uniqueIDs(p.ProductIDs) {
+			quantity += remaining[id]
+		}
+		var percent float64
+		for _, t := range p.Tiers {
+			if quantity+1e-9 >= t.MinQuantity {
+				percent = t.DiscountPercent
+			}
+		}
+		if percent > 0 {
+			for _, id := range uniqueIDs(p.ProductIDs) {
+				if remaining[id] > 0 {
+					app.consumed[id] = remaining[id]
+					app.discount += remaining[id] * prices[id] * percent / 100
+				}
+			}
+			app.times = 1
+		}
+
+	case TypeCombo:
+		free := make(map[string]float64, len(remaining))
+		for id, qty := range remaining {
+			free[id] = qty
+		}
+		for {
+			used, gross, ok := p.fillCombo(free, prices)
+			if !ok || gross <= p.ComboPrice {
+				break
+			}
+			// O mesmo combo se repete enquanto sobrarem unidades de todos os produtos usados; a cada rodada ao
+			// menos um deles se esgota, então o laço não depende da quantidade vendida
+			times := math.MaxInt
+			for id, qty := range used {
+				if n := int(math.Floor((free[id] + 1e-9) / qty)); n < times {
+					times = n
+				}
+			}
+			for id, qty := range used {
+				free[id] -= qty * float64(times)
+				app.consumed[id] += qty * float64(times)
+			}
+			app.discount += (gross - p.ComboPrice) * float64(times)
+			app.times += times
+		}
+
+	case TypePrice:
+		for _, id := range uniqueIDs(p.ProductIDs) {
+			if remaining[id] > 0 && prices[id] > p.Price {
+				app.consumed[id] = remaining[id]
+				app.discount += remaining[id] * (prices[id] - p.Price)
+				app.times++
+			}
+		}
+	}
+
+	app.discount = roundMoney(app.discount)
+	return app
+}
+
+// fillCombo monta um combo com as unidades livres, preenchendo cada componente com os produtos mais caros
+func (p *Promotion) fillCombo(free, prices map[string]float64) (map[string]float64, float64, bool) {
+	available := make(map[string]float64, len(free))
+	for id, qty := range free {
+		available[id] = qty
+	}
+
+	used := make(map[string]float64)
+	var gross float64
+	for _, item := range p.ComboItems {
+		need := item.Quantity
+		for _, run := range unitRuns(item.ProductIDs, available, prices) {
+			if need == 0 {
+				break
+			}
+			n := run.units
+			if n > need {
+				n = need
+			}
+			available[run.id] -= float64(n)
+			used[run.id] += float64(n)
+			gross += float64(n) * prices[run.id]
+			need -= n
+		}
+		if need > 0 {
+			return nil, 0, false
+		}
+	}
+	return used, gross, true
+}
+
+// freeBefore conta as posições gratuitas do leve N pague M entre as primeiras x unidades da fila
+func (p *Promotion) freeBefore(x int) int {
+	free := x / p.TakeQuantity * (p.TakeQuantity - p.PayQuantity)
+	if rest := x%p.TakeQuantity - p.PayQuantity; rest > 0 {
+		free += rest
+	}
+	return free
+}
+
+// unitRun representa as unidades inteiras livres de um produto na fila de preços
+type unitRun struct {
+	id    string
+	units int
+}
+
+// unitRuns lista os produtos com unidades inteiras livres, do mais caro para o mais barato
+func unitRuns(productIDs []string, remaining, prices map[string]float64) []unitRun {
+	ids := uniqueIDs(productIDs)
+	sort.SliceStable(ids, func(i, j int) bool {
+		if prices[ids[i]] != prices[ids[j]] {
+			return prices[ids[i]] > prices[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	runs := make([]unitRun, 0, len(ids))
+	for _, id := range ids {
+		if units := int(math.Floor(remaining[id] + 1e-9)); units > 0 {
+			runs = append(runs, unitRun{id: id, units: units})
+		}
+	}
+	return runs
+}
+
+// allocate rateia o desconto entre os produtos consumidos proporcionalmente ao valor de cada um;
+// o resíduo de arredondamento fica no produto de maior valor
+func allocate(consumed, prices map[string]float64, discount float64) map[string]float64 {
+	ids := make([]string, 0, len(consumed))
+	var total float64
+	for id, qty := range consumed {
+		if qty > 0 {
+			ids = append(ids, id)
+			total += qty * prices[id]
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		gi, gj := consumed[ids[i]]*prices[ids[i]], consumed[ids[j]]*prices[ids[j]]
+		if gi != gj {
+			return gi > gj
+		}
+		return ids[i] < ids[j]
+	})
+
+	shares := make(map[string]float64, len(ids))
+	if total <= 0 || len(ids) == 0 {
+		return shares
+	}
+
+	rest := discount
+	for _, id := range ids[1:] {
+		share := roundMoney(discount * consumed[id] * prices[id] / total)
+		shares[id] = share
+		rest -= share
+	}
+	shares[ids[0]] = roundMoney(rest)
+	return shares
+}
+
+// uniqueIDs remove IDs repetidos preservando a ordem
+func uniqueIDs(ids []string) []string {
+	seen := make(map[string]bool, len(ids))
+	unique := make([]string, 0, len(ids))
+	for _, id := range ids {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
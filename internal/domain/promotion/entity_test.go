@@ -0,0 +1,291 @@
+package promotion
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+var (
+	evaluateAt = time.Date(2025, 3, 10, 12, 0, 0, 0, time.UTC)
+	startsAt   = time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	endsAt     = time.Date(2025, 4, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func takePay(id string, take, pay int, products ...string) *Promotion {
+	return &Promotion{ID: id, Name: id, Type: TypeTakePay, ProductIDs: products, TakeQuantity: take, PayQuantity: pay, StartsAt: startsAt, EndsAt: endsAt, Active: true}
+}
+
+func tiered(id string, tiers []Tier, products ...string) *Promotion {
+	return &Promotion{ID: id, Name: id, Type: TypeTiered, ProductIDs: products, Tiers: tiers, StartsAt: startsAt, EndsAt: endsAt, Active: true}
+}
+
+func combo(id string, price float64, items ...ComboItem) *Promotion {
+	return &Promotion{ID: id, Name: id, Type: TypeCombo, ComboItems: items, ComboPrice: price, StartsAt: startsAt, EndsAt: endsAt, Active: true}
+}
+
+func price(id string, value float64, membersOnly bool, products ...string) *Promotion {
+	return &Promotion{ID: id, Name: id, Type: TypePrice, ProductIDs: products, Price: value, MembersOnly: membersOnly, StartsAt: startsAt, EndsAt: endsAt, Active: true}
+}
+
+func withPriority(p *Promotion, priority int) *Promotion {
+	p.Priority = priority
+	return p
+}
+
+func TestEvaluate(t *testing.T) {
+	tests := []struct {
+		name       string
+		cart       Cart
+		promotions []*Promotion
+		discount   float64
+		applied    []string // IDs na ordem de aplicação
+		times      []int
+	}{
+		{
+			name:       "leve 3 pague 2 de um produto",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 3, UnitPrice: 10}}},
+			promotions: []*Promotion{takePay("tp", 3, 2, "a")},
+			discount:   10,
+			applied:    []string{"tp"},
+			times:      []int{1},
+		},
+		{
+			name: "leve 3 pague 2 libera a unidade mais barata do grupo",
+			cart: Cart{Items: []CartItem{
+				{ProductID: "a", Quantity: 2, UnitPrice: 10},
+				{ProductID: "b", Quantity: 2, UnitPrice: 5},
+			}},
+			promotions: []*Promotion{takePay("tp", 3, 2, "a", "b")},
+			discount:   5,
+			applied:    []string{"tp"},
+			times:      []int{1},
+		},
+		{
+			name: "leve 4 pague 2 com grupos atravessando produtos",
+			cart: Cart{Items: []CartItem{
+				{ProductID: "a", Quantity: 3, UnitPrice: 9},
+				{ProductID: "b", Quantity: 3, UnitPrice: 6},
+				{ProductID: "c", Quantity: 3, UnitPrice: 2},
+			}},
+			// Fila a a a b | b b c c; gratuitas: a b (grupo 1) e c c (grupo 2)
+			promotions: []*Promotion{takePay("tp", 4, 2, "a", "b", "c")},
+			discount:   9 + 6 + 2 + 2,
+			applied:    []string{"tp"},
+			times:      []int{2},
+		},
+		{
+			name:       "leve 3 pague 2 com quantidade máxima sem expandir unidades",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: MaxItemQuantity, UnitPrice: 1}}},
+			promotions: []*Promotion{takePay("tp", 3, 2, "a")},
+			discount:   33333,
+			applied:    []string{"tp"},
+			times:      []int{33333},
+		},
+		{
+			name:       "leve 3 pague 2 ignora frações de peso",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 2.9, UnitPrice: 10}}},
+			promotions: []*Promotion{takePay("tp", 3, 2, "a")},
+			discount:   0,
+			applied:    []string{},
+		},
+		{
+			name: "desconto progressivo na faixa mais alta atingida",
+			cart: Cart{Items: []CartItem{
+				{ProductID: "a", Quantity: 4, UnitPrice: 10},
+				{ProductID: "b", Quantity: 2, UnitPrice: 5},
+			}},
+			promotions: []*Promotion{tiered("tier", []Tier{{MinQuantity: 3, DiscountPercent: 5}, {MinQuantity: 6, DiscountPercent: 10}}, "a", "b")},
+			discount:   5,
+			applied:    []string{"tier"},
+			times:      []int{1},
+		},
+		{
+			name:       "desconto progressivo abaixo da primeira faixa",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 2, UnitPrice: 10}}},
+			promotions: []*Promotion{tiered("tier", []Tier{{MinQuantity: 3, DiscountPercent: 5}}, "a")},
+			discount:   0,
+			applied:    []string{},
+		},
+		{
+			name: "combo preenchido pelos produtos mais caros e parado quando deixa de compensar",
+			cart: Cart{Items: []CartItem{
+				{ProductID: "a", Quantity: 2, UnitPrice: 5},
+				{ProductID: "b", Quantity: 1, UnitPrice: 4},
+				{ProductID: "c", Quantity: 1, UnitPrice: 3},
+			}},
+			// a+b = 9 vale o combo de 8; a+c = 8 não tem desconto
+			promotions: []*Promotion{combo("cb", 8, ComboItem{ProductIDs: []string{"a"}, Quantity: 1}, ComboItem{ProductIDs: []string{"b", "c"}, Quantity: 1})},
+			discount:   1,
+			applied:    []string{"cb"},
+			times:      []int{1},
+		},
+		{
+			name: "combo repetido até esgotar um componente",
+			cart: Cart{Items: []CartItem{
+				{ProductID: "a", Quantity: 7, UnitPrice: 5},
+				{ProductID: "b", Quantity: 10, UnitPrice: 2},
+			}},
+			// As 10 unidades de b fecham 5 combos; sobram 2 unidades de a
+			promotions: []*Promotion{combo("cb", 8, ComboItem{ProductIDs: []string{"a"}, Quantity: 1}, ComboItem{ProductIDs: []string{"b"}, Quantity: 2})},
+			discount:   5,
+			applied:    []string{"cb"},
+			times:      []int{5},
+		},
+		{
+			name: "combo com produto compartilhado entre componentes",
+			cart: Cart{Items: []CartItem{
+				{ProductID: "a", Quantity: 3, UnitPrice: 6},
+				{ProductID: "b", Quantity: 3, UnitPrice: 4},
+			}},
+			// Combos: a+a (12) e a+b (10); sem unidades de a não há novo combo
+			promotions: []*Promotion{combo("cb", 9, ComboItem{ProductIDs: []string{"a"}, Quantity: 1}, ComboItem{ProductIDs: []string{"a", "b"}, Quantity: 1})},
+			discount:   3 + 1,
+			applied:    []string{"cb"},
+			times:      []int{2},
+		},
+		{
+			name: "combo com quantidade máxima sem repetir a montagem",
+			cart: Cart{Items: []CartItem{
+				{ProductID: "a", Quantity: MaxItemQuantity, UnitPrice: 5},
+				{ProductID: "b", Quantity: MaxItemQuantity, UnitPrice: 4},
+			}},
+			promotions: []*Promotion{combo("cb", 8, ComboItem{ProductIDs: []string{"a"}, Quantity: 1}, ComboItem{ProductIDs: []string{"b"}, Quantity: 1})},
+			discount:   MaxItemQuantity,
+			applied:    []string{"cb"},
+			times:      []int{int(MaxItemQuantity)},
+		},
+		{
+			name:       "sobreposição escolhe a promoção de maior desconto",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 3, UnitPrice: 10}}},
+			promotions: []*Promotion{price("preco", 8, false, "a"), takePay("tp", 3, 2, "a")},
+			discount:   10,
+			applied:    []string{"tp"},
+			times:      []int{1},
+		},
+		{
+			name:       "sobreposição aplica a segunda promoção nas unidades que sobraram",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 4, UnitPrice: 10}}},
+			promotions: []*Promotion{price("preco", 8, false, "a"), takePay("tp", 3, 2, "a")},
+			discount:   10 + 2,
+			applied:    []string{"tp", "preco"},
+			times:      []int{1, 1},
+		},
+		{
+			name: "empate no desconto decidido pela prioridade",
+			cart: Cart{Items: []CartItem{{ProductID: "a", Quantity: 2, UnitPrice: 10}}},
+			promotions: []*Promotion{
+				withPriority(price("p1", 9, false, "a"), 1),
+				withPriority(tiered("p2", []Tier{{MinQuantity: 2, DiscountPercent: 10}}, "a"), 5),
+			},
+			discount: 2,
+			applied:  []string{"p2"},
+			times:    []int{1},
+		},
+		{
+			name:       "empate no desconto e na prioridade decidido pelo ID",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 2, UnitPrice: 10}}},
+			promotions: []*Promotion{price("z", 9, false, "a"), tiered("b", []Tier{{MinQuantity: 2, DiscountPercent: 10}}, "a")},
+			discount:   2,
+			applied:    []string{"b"},
+			times:      []int{1},
+		},
+		{
+			name:       "preço do clube sem cliente identificado",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 2, UnitPrice: 10}}},
+			promotions: []*Promotion{price("clube", 7, true, "a")},
+			discount:   0,
+			applied:    []string{},
+		},
+		{
+			name:       "preço do clube com cliente identificado",
+			cart:       Cart{Member: true, Items: []CartItem{{ProductID: "a", Quantity: 2, UnitPrice: 10}}},
+			promotions: []*Promotion{price("clube", 7, true, "a")},
+			discount:   6,
+			applied:    []string{"clube"},
+			times:      []int{1},
+		},
+		{
+			name:       "preço promocional acima do preço de venda",
+			cart:       Cart{Items: []CartItem{{ProductID: "a", Quantity: 2, UnitPrice: 10}}},
+			promotions: []*Promotion{price("preco", 12, false, "a")},
+			discount:   0,
+			applied:    []string{},
+		},
+		{
+			name:       "promoção de outra filial",
+			cart:       Cart{BranchID: "f1", Items: []CartItem{{ProductID: "a", Quantity: 2, UnitPrice: 10}}},
+			promotions: []*Promotion{func() *Promotion { p := price("preco", 8, false, "a"); p.BranchIDs = []string{"f2"}; return p }()},
+			discount:   0,
+			applied:    []string{},
+		},
+		{
+			name:       "desconto manual sobre o total com promoções",
+			cart:       Cart{ManualDiscount: 10, Items: []CartItem{{ProductID: "a", Quantity: 3, UnitPrice: 10}}},
+			promotions: []*Promotion{takePay("tp", 3, 2, "a")},
+			discount:   10 + 2,
+			applied:    []string{"tp"},
+			times:      []int{1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Evaluate(tt.cart, tt.promotions, evaluateAt)
+			if err != nil {
+				t.Fatalf("Evaluate() erro = %v", err)
+			}
+			if result.Discount != tt.discount {
+				t.Errorf("Evaluate() desconto = %.2f, esperado %.2f", result.Discount, tt.discount)
+			}
+			if math.Abs(result.Total-(result.Gross-result.Discount)) > 1e-9 {
+				t.Errorf("Evaluate() total = %.2f, esperado bruto %.2f menos desconto %.2f", result.Total, result.Gross, result.Discount)
+			}
+
+			var itemDiscount float64
+			for _, item := range result.Items {
+				itemDiscount += item.Discount
+			}
+			if math.Abs(itemDiscount-result.Discount) > 1e-6 {
+				t.Errorf("Evaluate() rateio nos itens = %.2f, esperado %.2f", itemDiscount, result.Discount)
+			}
+
+			if len(result.Applied) != len(tt.applied) {
+				t.Fatalf("Evaluate() aplicou %+v, esperado %v", result.Applied, tt.applied)
+			}
+			for i, applied := range result.Applied {
+				if applied.PromotionID != tt.applied[i] || applied.Times != tt.times[i] {
+					t.Errorf("Evaluate() aplicação %d = %s x%d, esperado %s x%d", i, applied.PromotionID, applied.Times, tt.applied[i], tt.times[i])
+				}
+			}
+		})
+	}
+}
+
+func TestEvaluateInvalidCart(t *testing.T) {
+	tests := []struct {
+		name    string
+		cart    Cart
+		wantErr error
+	}{
+		{"item sem produto", Cart{Items: []CartItem{{Quantity: 1, UnitPrice: 1}}}, ErrEmptyCartProducts},
+		{"quantidade zero", Cart{Items: []CartItem{{ProductID: "a", UnitPrice: 1}}}, ErrInvalidQuantity},
+		{"preço negativo", Cart{Items: []CartItem{{ProductID: "a", Quantity: 1, UnitPrice: -1}}}, ErrInvalidUnitPrice},
+		{"quantidade acima do máximo", Cart{Items: []CartItem{{ProductID: "a", Quantity: 1e9, UnitPrice: 1}}}, ErrQuantityTooLarge},
+		{"linhas repetidas acima do máximo", Cart{Items: []CartItem{
+			{ProductID: "a", Quantity: MaxItemQuantity, UnitPrice: 1},
+			{ProductID: "a", Quantity: 1, UnitPrice: 1},
+		}}, ErrQuantityTooLarge},
+		{"desconto manual de 100%", Cart{ManualDiscount: 100, Items: []CartItem{{ProductID: "a", Quantity: 1, UnitPrice: 1}}}, ErrInvalidManualDiscount},
+		{"desconto manual negativo", Cart{ManualDiscount: -1, Items: []CartItem{{ProductID: "a", Quantity: 1, UnitPrice: 1}}}, ErrInvalidManualDiscount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Evaluate(tt.cart, nil, evaluateAt); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Evaluate() erro = %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+}
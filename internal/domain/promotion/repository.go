@@ -0,0 +1,34 @@
+package promotion
+
+import (
+	"context"
+	"time"
+)
+
+// Filter define os filtros para listagem de promoções
+type Filter struct {
+	BranchID string
+	Type     Type
+	Active   *bool
+}
+
+// Repository define a interface para operações de repositório de promoções
+type Repository interface {
+	// Create grava uma nova promoção
+	Create(ctx context.Context, p *Promotion) error
+
+	// Update atualiza uma promoção
+	Update(ctx context.Context, p *Promotion) error
+
+	// FindByID busca uma promoção pelo ID
+	FindByID(ctx context.Context, id string) (*Promotion, error)
+
+	// List lista as promoções com filtros e paginação
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*Promotion, error)
+
+	// Count conta as promoções que atendem aos filtros
+	Count(ctx context.Context, filter Filter) (int, error)
+
+	// ListApplicable lista as promoções ativas e vigentes no momento para a filial
+	ListApplicable(ctx context.Context, branchID string, at time.Time) ([]*Promotion, error)
+}
@@ -0,0 +1,166 @@
+package rbac
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
+)
+
+var (
+	ErrEmptyTenantID      = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName          = errors.New("nome do perfil é obrigatório")
+	ErrNameTooLong        = errors.New("nome do perfil deve ter no máximo 60 caracteres")
+	ErrReservedName       = errors.New("nome reservado a um perfil padrão")
+	ErrEmptyPermissions   = errors.New("o perfil deve ter ao menos uma permissão")
+	ErrUnknownPermission  = errors.New("permissão desconhecida")
+	ErrEmptyAssignmentRef = errors.New("usuário e perfil da atribuição são obrigatórios")
+)
+
+// managerPermissions são as permissões do perfil padrão de gerente: toda a operação da loja,
+// sem a gestão de filiais, usuários e perfis
+var managerPermissions = []Permission{
+	PermCustomerView, PermCustomerCreate, PermCustomerUpdate, PermCustomerDelete,
+	PermProductView, PermProductCreate, PermProductUpdate, PermProductDelete,
+	PermUserView,
+	PermSupplierDelete, PermPaymentMethodManage, PermTerminalManage, PermLossReasonManage, PermLossApprove,
+	PermCertificateManage, PermFiscalManage, PermFiscalCancel, PermPDVDiscountAbove10,
+	PermPayablePay, PermPayableCancel, PermReceivableCancel, PermReceivableBlockOverdue,
+	PermCollectionManage, PermPixManage, PermBankingAccountManage, PermBankingReconcile, PermCardManage,
+	PermLoyaltyManage, PermPromotionManage, PermPriceTableManage, PermSalesmanManage,
+}
+
+// staffPermissions são as permissões do perfil padrão de funcionário: atendimento ao cliente e
+// consulta de produtos
+var staffPermissions = []Permission{
+	PermCustomerView, PermCustomerCreate, PermCustomerUpdate,
+	PermProductView,
+}
+
+// SystemPermissions retorna as permissões do perfil padrão (o campo role do usuário). Elas valem
+// em todas as filiais e são somadas às dos perfis atribuídos. O administrador tem todas
+func SystemPermissions(role user.Role) []Permission {
+	switch role {
+	case user.RoleAdmin:
+		all := make([]Permission, len(catalog))
+		for i, info := range catalog {
+			all[i] = info.Code
+		}
+		return all
+	case user.RoleManager:
+		return append([]Permission(nil), managerPermissions...)
+	case user.RoleStaff:
+		return append([]Permission(nil), staffPermissions...)
+	}
+	return nil
+}
+
+// Role é um perfil de acesso definido pelo tenant
+type Role struct {
+	ID          string       `json:"id"`
+	TenantID    string       `json:"tenant_id"`
+	Name        string       `json:"name"`
+	Description string       `json:"description"`
+	Permissions []Permission `json:"permissions"`
+	CreatedAt   time.Time    `json:"created_at"`
+	UpdatedAt   time.Time    `json:"updated_at"`
+}
+
+// NewRole cria um perfil de acesso com as permissões informadas
+func NewRole(tenantID, name, description string, permissions []Permission) (*Role, error) {
+	now := time.Now()
+	r := &Role{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Name:        strings.TrimSpace(name),
+		Description: strings.TrimSpace(description),
+		Permissions: uniquePermissions(permissions),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Update altera o nome, a descrição e as permissões do perfil
+func (r *Role) Update(name, description string, permissions []Permission) error {
+	r.Name = strings.TrimSpace(name)
+	r.Description = strings.TrimSpace(description)
+	r.Permissions = uniquePermissions(permissions)
+	r.UpdatedAt = time.Now()
+	return r.Validate()
+}
+
+// Validate valida os dados do perfil
+func (r *Role) Validate() error {
+	switch {
+	case r.TenantID == "":
+		return ErrEmptyTenantID
+	case r.Name == "":
+		return ErrEmptyName
+	case len(r.Name) > 60:
+		return ErrNameTooLong
+	case isSystemRoleName(r.Name):
+		return ErrReservedName
+	case len(r.Permissions) == 0:
+		return ErrEmptyPermissions
+	}
+	return ValidatePermissions(r.Permissions)
+}
+
+// Assignment atribui um perfil a um usuário em uma filial. Sem filial, vale em todas
+type Assignment struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	UserID    string    `json:"user_id"`
+	RoleID    string    `json:"role_id"`
+	RoleName  string    `json:"role_name,omitempty"`
+	BranchID  string    `json:"branch_id,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// NewAssignment cria a atribuição do perfil ao usuário na filial
+func NewAssignment(tenantID, userID, roleID, branchID string) (*Assignment, error) {
+	if userID == "" || roleID == "" {
+		return nil, ErrEmptyAssignmentRef
+	}
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	return &Assignment{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		UserID:    userID,
+		RoleID:    roleID,
+		BranchID:  branchID,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// isSystemRoleName verifica se o nome é de um perfil padrão
+func isSystemRoleName(name string) bool {
+	switch user.Role(strings.ToLower(name)) {
+	case user.RoleAdmin, user.RoleManager, user.RoleStaff:
+		return true
+	}
+	return false
+}
+
+// uniquePermissions remove espaços e permissões repetidas, mantendo a ordem
+func uniquePermissions(permissions []Permission) []Permission {
+	seen := make(PermissionSet, len(permissions))
+	unique := make([]Permission, 0, len(permissions))
+	for _, p := range permissions {
+		p = Permission(strings.TrimSpace(string(p)))
+		if p == "" || seen.Has(p) {
+			continue
+		}
+		seen.Add(p)
+		unique = append(unique, p)
+	}
+	return unique
+}
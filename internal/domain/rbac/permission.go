@@ -0,0 +1,165 @@
+package rbac
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Permission identifica uma ação que pode ser concedida a um perfil, no formato
+// <recurso>.<ação>
+type Permission string
+
+const (
+	PermCustomerView   Permission = "customer.view"
+	PermCustomerCreate Permission = "customer.create"
+	PermCustomerUpdate Permission = "customer.update"
+	PermCustomerDelete Permission = "customer.delete"
+
+	PermProductView   Permission = "product.view"
+	PermProductCreate Permission = "product.create"
+	PermProductUpdate Permission = "product.update"
+	PermProductDelete Permission = "product.delete"
+
+	PermUserView   Permission = "user.view"
+	PermUserManage Permission = "user.manage"
+	PermRoleManage Permission = "role.manage"
+
+	PermSupplierDelete      Permission = "supplier.delete"
+	PermPaymentMethodManage Permission = "payment_method.manage"
+	PermTerminalManage      Permission = "terminal.manage"
+	PermLossReasonManage    Permission = "loss.reason.manage"
+	PermLossApprove         Permission = "loss.approve"
+
+	PermBranchManage      Permission = "branch.manage"
+	PermCertificateManage Permission = "certificate.manage"
+	PermFiscalManage      Permission = "fiscal.manage"
+	PermFiscalCancel      Permission = "fiscal.cancel"
+
+	PermPDVDiscountAbove10 Permission = "pdv.discount.above_10"
+
+	PermPayablePay             Permission = "payable.pay"
+	PermPayableCancel          Permission = "payable.cancel"
+	PermReceivableCancel       Permission = "receivable.cancel"
+	PermReceivableBlockOverdue Permission = "receivable.block_overdue"
+	PermCollectionManage       Permission = "collection.manage"
+	PermPixManage              Permission = "pix.manage"
+	PermBankingAccountManage   Permission = "banking.account.manage"
+	PermBankingReconcile       Permission = "banking.reconcile"
+	PermCardManage             Permission = "card.manage"
+	PermLoyaltyManage          Permission = "loyalty.manage"
+	PermPromotionManage        Permission = "promotion.manage"
+	PermPriceTableManage       Permission = "price_table.manage"
+	PermSalesmanManage         Permission = "salesman.manage"
+)
+
+// PermissionInfo descreve uma permissão do catálogo
+type PermissionInfo struct {
+	Code        Permission `json:"code"`
+	Description string     `json:"description"`
+}
+
+// catalog é o catálogo de permissões, na ordem em que são apresentadas
+var catalog = []PermissionInfo{
+	{PermCustomerView, "Consultar clientes"},
+	{PermCustomerCreate, "Cadastrar clientes"},
+	{PermCustomerUpdate, "Alterar clientes"},
+	{PermCustomerDelete, "Excluir clientes"},
+	{PermProductView, "Consultar produtos"},
+	{PermProductCreate, "Cadastrar produtos"},
+	{PermProductUpdate, "Alterar produtos e preços"},
+	{PermProductDelete, "Excluir produtos"},
+	{PermUserView, "Consultar usuários"},
+	{PermUserManage, "Cadastrar, alterar e excluir usuários"},
+	{PermRoleManage, "Gerenciar perfis de acesso e atribuí-los aos usuários"},
+	{PermSupplierDelete, "Excluir fornecedores"},
+	{PermPaymentMethodManage, "Gerenciar formas de pagamento"},
+	{PermTerminalManage, "Gerenciar terminais de PDV"},
+	{PermLossReasonManage, "Gerenciar motivos de perda"},
+	{PermLossApprove, "Aprovar e rejeitar perdas"},
+	{PermBranchManage, "Cadastrar, alterar e excluir filiais"},
+	{PermCertificateManage, "Gerenciar certificados digitais A1"},
+	{PermFiscalManage, "Alterar configurações fiscais, numeração e contingência"},
+	{PermFiscalCancel, "Cancelar documentos fiscais e inutilizar numeração"},
+	{PermPDVDiscountAbove10, "Conceder desconto acima de 10% no PDV"},
+	{PermPayablePay, "Baixar contas a pagar"},
+	{PermPayableCancel, "Cancelar contas a pagar"},
+	{PermReceivableCancel, "Cancelar contas a receber"},
+	{PermReceivableBlockOverdue, "Bloquear clientes inadimplentes"},
+	{PermCollectionManage, "Gerenciar cobrança bancária, remessas e retornos"},
+	{PermPixManage, "Gerenciar configurações e notificações do Pix"},
+	{PermBankingAccountManage, "Gerenciar contas bancárias"},
+	{PermBankingReconcile, "Importar extratos e conciliar transações"},
+	{PermCardManage, "Gerenciar taxas e arquivos de liquidação de cartões"},
+	{PermLoyaltyManage, "Gerenciar o programa de fidelidade"},
+	{PermPromotionManage, "Gerenciar promoções"},
+	{PermPriceTableManage, "Gerenciar tabelas de preço"},
+	{PermSalesmanManage, "Gerenciar vendedores e regras de comissão"},
+}
+
+// Permissions lista o catálogo de permissões
+func Permissions() []PermissionInfo {
+	return append([]PermissionInfo(nil), catalog...)
+}
+
+// ValidatePermissions verifica se todas as permissões estão no catálogo
+func ValidatePermissions(permissions []Permission) error {
+	for _, p := range permissions {
+		if !isKnown(p) {
+			return fmt.Errorf("%w: %s", ErrUnknownPermission, p)
+		}
+	}
+	return nil
+}
+
+// isKnown verifica se a permissão está no catálogo
+func isKnown(p Permission) bool {
+	for _, info := range catalog {
+		if info.Code == p {
+			return true
+		}
+	}
+	return false
+}
+
+// PermissionSet é um conjunto de permissões efetivas de um usuário
+type PermissionSet map[Permission]struct{}
+
+// NewPermissionSet cria um conjunto com as permissões informadas
+func NewPermissionSet(permissions ...Permission) PermissionSet {
+	set := make(PermissionSet, len(permissions))
+	set.Add(permissions...)
+	return set
+}
+
+// Add inclui as permissões no conjunto
+func (s PermissionSet) Add(permissions ...Permission) {
+	for _, p := range permissions {
+		s[p] = struct{}{}
+	}
+}
+
+// Has verifica se a permissão está no conjunto
+func (s PermissionSet) Has(p Permission) bool {
+	_, ok := s[p]
+	return ok
+}
+
+// HasAny verifica se alguma das permissões está no conjunto
+func (s PermissionSet) HasAny(permissions ...Permission) bool {
+	for _, p := range permissions {
+		if s.Has(p) {
+			return true
+		}
+	}
+	return false
+}
+
+// List retorna as permissões do conjunto em ordem alfabética
+func (s PermissionSet) List() []Permission {
+	list := make([]Permission, 0, len(s))
+	for p := range s {
+		list = append(list, p)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
@@ -0,0 +1,31 @@
+package rbac
+
+import "context"
+
+// Repository define a interface para operações de repositório de perfis de acesso
+type Repository interface {
+	// CreateRole grava um novo perfil
+	CreateRole(ctx context.Context, r *Role) error
+
+	// FindRole busca um perfil pelo ID
+	FindRole(ctx context.Context, id string) (*Role, error)
+
+	// ListRoles lista os perfis do tenant
+	ListRoles(ctx context.Context) ([]*Role, error)
+
+	// UpdateRole atualiza um perfil; vale imediatamente para os usuários que o têm
+	UpdateRole(ctx context.Context, r *Role) error
+
+	// DeleteRole exclui um perfil e as atribuições dele
+	DeleteRole(ctx context.Context, id string) error
+
+	// ListAssignments lista as atribuições de perfis do usuário
+	ListAssignments(ctx context.Context, userID string) ([]*Assignment, error)
+
+	// ReplaceAssignments substitui todas as atribuições do usuário
+	ReplaceAssignments(ctx context.Context, userID string, assignments []*Assignment) error
+
+	// AssignedPermissions retorna as permissões dos perfis atribuídos ao usuário na filial,
+	// incluindo os atribuídos a todas as filiais. Sem filial, apenas estes
+	AssignedPermissions(ctx context.Context, userID, branchID string) ([]Permission, error)
+}
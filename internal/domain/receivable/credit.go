@@ -0,0 +1,63 @@
+package receivable
+
+import (
+	"strings"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+)
+
+// DefaultBlockAfterDays é a tolerância padrão, em dias de atraso, antes do bloqueio automático
+const DefaultBlockAfterDays = 30
+
+// CustomerBalance resume a situação de crédito de um cliente
+type CustomerBalance struct {
+	CustomerID      string  `json:"customer_id"`
+	CreditLimit     float64 `json:"credit_limit"`
+	OpenBalance     float64 `json:"open_balance"`     // Saldo em aberto de todos os títulos
+	OverdueBalance  float64 `json:"overdue_balance"`  // Parte do saldo já vencida
+	AvailableCredit float64 `json:"available_credit"` // Limite menos saldo em aberto
+	OpenTitles      int     `json:"open_titles"`
+	MaxDaysOverdue  int     `json:"max_days_overdue"` // Atraso do título vencido mais antigo
+}
+
+// Calculate preenche o crédito disponível a partir do limite e do saldo
+func (b *CustomerBalance) Calculate() {
+	b.AvailableCredit = roundMoney(b.CreditLimit - b.OpenBalance)
+	if b.AvailableCredit < 0 {
+		b.AvailableCredit = 0
+	}
+}
+
+// CheckCredit verifica se o cliente pode realizar uma nova venda a prazo no valor informado.
+// O limite vem do saldo; blockAfterDays define o atraso máximo tolerado, acima dele a venda é recusada
+func CheckCredit(status customer.Status, balance *CustomerBalance, amount float64, blockAfterDays int) error {
+	// O status é gravado em maiúsculas no banco, por isso a comparação ignora a caixa
+	switch {
+	case strings.EqualFold(string(status), string(customer.StatusBlocked)):
+		return ErrCustomerBlocked
+	case strings.EqualFold(string(status), string(customer.StatusInactive)):
+		return ErrCustomerInactive
+	}
+
+	if balance.CreditLimit <= 0 {
+		return ErrNoCreditLimit
+	}
+	if balance.MaxDaysOverdue > blockAfterDays {
+		return ErrCustomerHasOverdue
+	}
+	if roundMoney(balance.OpenBalance+amount) > roundMoney(balance.CreditLimit) {
+		return ErrCreditLimitExceeded
+	}
+
+	return nil
+}
+
+// DaysOverdue calcula quantos dias um vencimento está em atraso na data de referência
+func DaysOverdue(dueDate, reference time.Time) int {
+	days := int(truncateDate(reference).Sub(truncateDate(dueDate)).Hours() / 24)
+	if days < 0 {
+		return 0
+	}
+	return days
+}
@@ -0,0 +1,260 @@
+package receivable
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID       = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyBranchID       = errors.New("ID da filial não pode ser vazio")
+	ErrEmptyCustomerID     = errors.New("cliente é obrigatório")
+	ErrInvalidAmount       = errors.New("valor deve ser maior que zero")
+	ErrInvalidDueDate      = errors.New("vencimento não pode ser anterior à emissão")
+	ErrInvalidInstallments = errors.New("número de parcelas inválido")
+	ErrInvalidPayment      = errors.New("valor do recebimento inválido")
+	ErrPaymentExceeds      = errors.New("valor do recebimento excede o saldo do título")
+	ErrDiscountExceeds     = errors.New("desconto não pode ser maior que o valor recebido")
+	ErrNotOpen             = errors.New("título não está em aberto")
+	ErrHasPayments         = errors.New("título com recebimentos não pode ser cancelado")
+	ErrEmptyCancelReason   = errors.New("informe o motivo do cancelamento")
+	ErrCustomerBlocked     = errors.New("cliente bloqueado para vendas a prazo")
+	ErrCustomerInactive    = errors.New("cliente inativo")
+	ErrNoCreditLimit       = errors.New("cliente não possui limite de crédito")
+	ErrCreditLimitExceeded = errors.New("valor da venda excede o limite de crédito disponível")
+	ErrCustomerHasOverdue  = errors.New("cliente possui títulos vencidos além do prazo de tolerância")
+)
+
+// DefaultPaymentTerm é o prazo, em dias, usado quando o cliente não tem prazo cadastrado
+const DefaultPaymentTerm = 30
+
+// Status representa a situação de um título a receber
+type Status string
+
+const (
+	StatusOpen      Status = "open"      // Em aberto
+	StatusPartial   Status = "partial"   // Recebido parcialmente
+	StatusPaid      Status = "paid"      // Quitado
+	StatusCancelled Status = "cancelled" // Cancelado
+)
+
+// Payment representa uma baixa (recebimento) de um título
+type Payment struct {
+	ID           string    `json:"id"`
+	ReceivableID string    `json:"receivable_id"`
+	PaidAt       time.Time `json:"paid_at"`
+	Amount       float64   `json:"amount"`   // Principal abatido do saldo
+	Interest     float64   `json:"interest"` // Juros
+	Fine         float64   `json:"fine"`     // Multa
+	Discount     float64   `json:"discount"` // Desconto
+	Total        float64   `json:"total"`    // Valor efetivamente recebido
+	Method       string    `json:"method"`
+	Notes        string    `json:"notes"`
+	CreatedBy    string    `json:"created_by"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// Receivable representa um título a receber de uma venda a prazo (fiado/crediário)
+type Receivable struct {
+	ID             string    `json:"id"`
+	TenantID       string    `json:"tenant_id"`
+	BranchID       string    `json:"branch_id"`
+	CustomerID     string    `json:"customer_id"`
+	SaleID         string    `json:"sale_id"`
+	DocumentNumber string    `json:"document_number"`
+	Description    string    `json:"description"`
+	Installment    int       `json:"installment"`
+	Installments   int       `json:"installments"`
+	IssueDate      time.Time `json:"issue_date"`
+	DueDate        time.Time `json:"due_date"`
+	Amount         float64   `json:"amount"`
+	ReceivedAmount float64   `json:"received_amount"` // Principal já recebido
+	Interest       float64   `json:"interest"`        // Juros acumulados nos recebimentos
+	Fine           float64   `json:"fine"`            // Multas acumuladas nos recebimentos
+	Discount       float64   `json:"discount"`        // Descontos acumulados nos recebimentos
+	Status         Status    `json:"status"`
+	CancelReason   string    `json:"cancel_reason"`
+	CreatedBy      string    `json:"created_by"`
+	Payments       []Payment `json:"payments"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// NewReceivable cria um título a receber em parcela única
+func NewReceivable(tenantID, branchID, customerID, documentNumber, description string, amount float64, issueDate, dueDate time.Time) (*Receivable, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	if branchID == "" {
+		return nil, ErrEmptyBranchID
+	}
+	if customerID == "" {
+		return nil, ErrEmptyCustomerID
+	}
+	if amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	issueDate = truncateDate(issueDate)
+	dueDate = truncateDate(dueDate)
+	if dueDate.Before(issueDate) {
+		return nil, ErrInvalidDueDate
+	}
+
+	now := time.Now()
+	return &Receivable{
+		ID:             uuid.New().String(),
+		TenantID:       tenantID,
+		BranchID:       branchID,
+		CustomerID:     customerID,
+		DocumentNumber: documentNumber,
+		Description:    description,
+		Installment:    1,
+		Installments:   1,
+		IssueDate:      issueDate,
+		DueDate:        dueDate,
+		Amount:         roundMoney(amount),
+		Status:         StatusOpen,
+		Payments:       []Payment{},
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}, nil
+}
+
+// GenerateInstallments gera os títulos de uma venda a prazo.
+// Cada parcela vence paymentTerm dias após a anterior e a diferença de arredondamento vai para a primeira
+func GenerateInstallments(tenantID, branchID, customerID, saleID, documentNumber string, total float64, issueDate time.Time, installments, paymentTerm int) ([]*Receivable, error) {
+	if installments < 1 {
+		return nil, ErrInvalidInstallments
+	}
+	if total <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if paymentTerm <= 0 {
+		paymentTerm = DefaultPaymentTerm
+	}
+
+	value := math.Floor(total/float64(installments)*100) / 100
+	remainder := roundMoney(total - value*float64(installments))
+
+	receivables := make([]*Receivable, 0, installments)
+	for i := 0; i < installments; i++ {
+		amount := value
+		if i == 0 {
+			amount = roundMoney(value + remainder)
+		}
+
+		r, err := NewReceivable(tenantID, branchID, customerID, documentNumber, "", amount, issueDate, issueDate.AddDate(0, 0, paymentTerm*(i+1)))
+		if err != nil {
+			return nil, err
+		}
+		r.SaleID = saleID
+		r.Installment = i + 1
+		r.Installments = installments
+		r.Description = "Venda a prazo - parcela " + strconv.Itoa(i+1) + "/" + strconv.Itoa(installments)
+		receivables = append(receivables, r)
+	}
+
+	return receivables, nil
+}
+
+// Balance retorna o saldo em aberto do título
+func (r *Receivable) Balance() float64 {
+	if r.Status == StatusCancelled {
+		return 0
+	}
+	return roundMoney(r.Amount - r.ReceivedAmount)
+}
+
+// IsOpen verifica se o título ainda aceita recebimentos
+func (r *Receivable) IsOpen() bool {
+	return r.Status == StatusOpen || r.Status == StatusPartial
+}
+
+// IsOverdue verifica se o título está vencido na data informada
+func (r *Receivable) IsOverdue(reference time.Time) bool {
+	return r.IsOpen() && r.DueDate.Before(truncateDate(reference))
+}
+
+// Receive registra um recebimento parcial ou total do título.
+// amount é o principal abatido; juros e multa acrescem e o desconto reduz o valor recebido
+func (r *Receivable) Receive(amount, interest, fine, discount float64, paidAt time.Time, method, notes, userID string) (*Payment, error) {
+	if !r.IsOpen() {
+		return nil, ErrNotOpen
+	}
+	if amount <= 0 || interest < 0 || fine < 0 || discount < 0 {
+		return nil, ErrInvalidPayment
+	}
+	if roundMoney(amount) > r.Balance() {
+		return nil, ErrPaymentExceeds
+	}
+	if discount > amount+interest+fine {
+		return nil, ErrDiscountExceeds
+	}
+	if paidAt.IsZero() {
+		paidAt = time.Now()
+	}
+
+	now := time.Now()
+	payment := Payment{
+		ID:           uuid.New().String(),
+		ReceivableID: r.ID,
+		PaidAt:       truncateDate(paidAt),
+		Amount:       roundMoney(amount),
+		Interest:     roundMoney(interest),
+		Fine:         roundMoney(fine),
+		Discount:     roundMoney(discount),
+		Total:        roundMoney(amount + interest + fine - discount),
+		Method:       method,
+		Notes:        notes,
+		CreatedBy:    userID,
+		CreatedAt:    now,
+	}
+
+	r.ReceivedAmount = roundMoney(r.ReceivedAmount + payment.Amount)
+	r.Interest = roundMoney(r.Interest + payment.Interest)
+	r.Fine = roundMoney(r.Fine + payment.Fine)
+	r.Discount = roundMoney(r.Discount + payment.Discount)
+	if r.Balance() <= 0 {
+		r.Status = StatusPaid
+	} else {
+		r.Status = StatusPartial
+	}
+	r.Payments = append(r.Payments, payment)
+	r.UpdatedAt = now
+
+	return &payment, nil
+}
+
+// Cancel cancela um título que ainda não recebeu pagamentos
+func (r *Receivable) Cancel(reason string) error {
+	if !r.IsOpen() {
+		return ErrNotOpen
+	}
+	if r.ReceivedAmount > 0 {
+		return ErrHasPayments
+	}
+	if strings.TrimSpace(reason) == "" {
+		return ErrEmptyCancelReason
+	}
+
+	r.Status = StatusCancelled
+	r.CancelReason = strings.TrimSpace(reason)
+	r.UpdatedAt = time.Now()
+	return nil
+}
+
+// truncateDate remove o horário de uma data
+func truncateDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,65 @@
+package receivable
+
+import (
+	"context"
+	"time"
+)
+
+// ListFilter define os filtros para listagem de títulos a receber
+type ListFilter struct {
+	BranchID   string
+	CustomerID string
+	SaleID     string
+	Status     Status
+	DueFrom    time.Time
+	DueTo      time.Time
+	Overdue    bool // Somente títulos em aberto com vencimento anterior a hoje
+}
+
+// Repository define a interface para operações de repositório de contas a receber
+type Repository interface {
+	// Create grava um ou mais títulos em uma única transação
+	Create(ctx context.Context, receivables ...*Receivable) error
+
+	// CreateForSale grava os títulos de uma venda a prazo bloqueando o cliente durante a operação.
+	// check recebe a situação, o saldo e a configuração lidos com o cliente bloqueado e pode recusar a
+	// venda antes da gravação
+	CreateForSale(ctx context.Context, customerID string, check CreditCheck, receivables ...*Receivable) error
+
+	// FindByID busca um título pelo ID, incluindo os recebimentos
+	FindByID(ctx context.Context, id string) (*Receivable, error)
+
+	// List lista os títulos com filtros e paginação, ordenados por vencimento
+	List(ctx context.Context, filter ListFilter, limit, offset int) ([]*Receivable, error)
+
+	// Count conta os títulos que atendem aos filtros
+	Count(ctx context.Context, filter ListFilter) (int, error)
+
+	// RegisterPayment grava um recebimento e atualiza o saldo do título
+	RegisterPayment(ctx context.Context, r *Receivable, payment *Payment) error
+
+	// Cancel grava o cancelamento de um título
+	Cancel(ctx context.Context, r *Receivable) error
+
+	// CustomerBalance calcula o saldo em aberto e o atraso de um cliente
+	CustomerBalance(ctx context.Context, customerID string) (*CustomerBalance, error)
+
+	// BlockOverdueCustomers bloqueia os clientes com títulos vencidos há mais de days dias
+	// e retorna os IDs dos clientes bloqueados
+	BlockOverdueCustomers(ctx context.Context, days int) ([]string, error)
+
+	// BlockOverdueCustomer bloqueia apenas o cliente informado, se ele tiver títulos vencidos há
+	// mais de days dias, e indica se o bloqueio foi aplicado
+	BlockOverdueCustomer(ctx context.Context, customerID string, days int) (bool, error)
+
+	// BlockOverdueAll executa BlockOverdueCustomers em todos os tenants ativos, com a tolerância
+	// configurada em cada um, e retorna o total de clientes bloqueados. Um tenant com falha não
+	// interrompe os demais
+	BlockOverdueAll(ctx context.Context) (int, error)
+
+	// FindSettings busca a configuração de contas a receber do tenant, ou a padrão se não houver
+	FindSettings(ctx context.Context) (*Settings, error)
+
+	// SaveSettings grava a configuração de contas a receber do tenant
+	SaveSettings(ctx context.Context, settings *Settings) error
+}
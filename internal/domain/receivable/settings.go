@@ -0,0 +1,35 @@
+package receivable
+
+import (
+	"errors"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/internal/domain/customer"
+)
+
+// ErrInvalidBlockAfterDays indica uma tolerância de atraso negativa
+var ErrInvalidBlockAfterDays = errors.New("dias de atraso tolerados não podem ser negativos")
+
+// Settings representa a configuração de contas a receber do tenant
+type Settings struct {
+	TenantID       string    `json:"tenant_id"`
+	BlockAfterDays int       `json:"block_after_days"` // Atraso tolerado antes de recusar vendas a prazo e bloquear o cliente
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// DefaultSettings retorna a configuração usada pelos tenants que ainda não gravaram a sua
+func DefaultSettings(tenantID string) *Settings {
+	return &Settings{TenantID: tenantID, BlockAfterDays: DefaultBlockAfterDays}
+}
+
+// Validate verifica os dados da configuração
+func (s *Settings) Validate() error {
+	if s.BlockAfterDays < 0 {
+		return ErrInvalidBlockAfterDays
+	}
+	return nil
+}
+
+// CreditCheck decide se uma venda a prazo pode ser gravada. Recebe a situação do cliente, o saldo e a
+// configuração lidos na mesma transação em que o cliente está bloqueado
+type CreditCheck func(status customer.Status, balance *CustomerBalance, settings *Settings) error
@@ -0,0 +1,326 @@
+package salesman
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID        = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName            = errors.New("nome do vendedor é obrigatório")
+	ErrInvalidPercentage    = errors.New("percentual de comissão deve estar entre 0 e 100%")
+	ErrEmptyRuleScope       = errors.New("regra de comissão deve ter categoria ou forma de pagamento")
+	ErrInactive             = errors.New("vendedor inativo")
+	ErrEmptySaleID          = errors.New("ID da venda é obrigatório")
+	ErrEmptySaleItems       = errors.New("venda sem itens")
+	ErrInvalidItemAmount    = errors.New("valor do item não pode ser negativo")
+	ErrInvalidPaymentAmount = errors.New("valor do pagamento deve ser maior que zero")
+	ErrNoCommission         = errors.New("venda não gera comissão para o vendedor")
+)
+
+// Salesman representa um vendedor, opcionalmente vinculado a um usuário do sistema
+type Salesman struct {
+	ID                string    `json:"id"`
+	TenantID          string    `json:"tenant_id"`
+	UserID            string    `json:"user_id,omitempty"` // Usuário que acessa o sistema como vendedor
+	Name              string    `json:"name"`
+	Document          string    `json:"document,omitempty"` // CPF
+	Email             string    `json:"email,omitempty"`
+	Phone             string    `json:"phone,omitempty"`
+	CommissionPercent float64   `json:"commission_percent"` // Percentual padrão quando nenhuma regra se aplica
+	Active            bool      `json:"active"`
+	CreatedAt         time.Time `json:"created_at"`
+	UpdatedAt         time.Time `json:"updated_at"`
+}
+
+// NewSalesman cria um novo vendedor ativo
+func NewSalesman(tenantID, name string, commissionPercent float64) (*Salesman, error) {
+	s := &Salesman{
+		ID:                uuid.New().String(),
+		TenantID:          tenantID,
+		Name:              name,
+		CommissionPercent: commissionPercent,
+		Active:            true,
+		CreatedAt:         time.Now(),
+		UpdatedAt:         time.Now(),
+	}
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Validate verifica os dados do vendedor
+func (s *Salesman) Validate() error {
+	if s.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if s.Name == "" {
+		return ErrEmptyName
+	}
+	if s.CommissionPercent < 0 || s.CommissionPercent > 100 {
+		return ErrInvalidPercentage
+	}
+	return nil
+}
+
+// Rule representa uma regra de comissão por categoria de produto e/ou forma de pagamento.
+// Sem vendedor, a regra vale para todos; a regra mais específica que combina com o item prevalece
+type Rule struct {
+	ID              string    `json:"id"`
+	TenantID        string    `json:"tenant_id"`
+	SalesmanID      string    `json:"salesman_id,omitempty"`
+	CategoryID      string    `json:"category_id,omitempty"`
+	PaymentMethodID string    `json:"payment_method_id,omitempty"`
+	Percentage      float64   `json:"percentage"`
+	CreatedAt       time.Time `json:"created_at"`
+	UpdatedAt       time.Time `json:"updated_at"`
+}
+
+// NewRule cria uma regra de comissão
+func NewRule(tenantID, salesmanID, categoryID, paymentMethodID string, percentage float64) (*Rule, error) {
+	r := &Rule{
+		ID:              uuid.New().String(),
+		TenantID:        tenantID,
+		SalesmanID:      salesmanID,
+		CategoryID:      categoryID,
+		PaymentMethodID: paymentMethodID,
+		Percentage:      percentage,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}
+	if err := r.Validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Validate verifica os dados da regra
+func (r *Rule) Validate() error {
+	if r.TenantID == "" {
+		return ErrEmptyTenantID
+	}
+	if r.CategoryID == "" && r.PaymentMethodID == "" {
+		return ErrEmptyRuleScope
+	}
+	if r.Percentage < 0 || r.Percentage > 100 {
+		return ErrInvalidPercentage
+	}
+	return nil
+}
+
+// specificity pontua a regra para o item; -1 quando não combina. O vendedor pesa mais que a categoria,
+// que pesa mais que a forma de pagamento
+func (r *Rule) specificity(salesmanID, categoryID, paymentMethodID string) int {
+	score := 0
+	if r.SalesmanID != "" {
+		if r.SalesmanID != salesmanID {
+			return -1
+		}
+		score += 4
+	}
+	if r.CategoryID != "" {
+		if r.CategoryID != categoryID {
+			return -1
+		}
+		score += 2
+	}
+	if r.PaymentMethodID != "" {
+		if r.PaymentMethodID != paymentMethodID {
+			return -1
+		}
+		score++
+	}
+	return score
+}
+
+// SaleItem representa um item da venda finalizada
+type SaleItem struct {
+	ProductID  string  `json:"product_id"`
+	CategoryID string  `json:"category_id"`
+	Amount     float64 `json:"amount"` // Valor pago no item, já com descontos
+}
+
+// SalePayment representa um pagamento da venda finalizada
+type SalePayment struct {
+	PaymentMethodID string  `json:"payment_method_id"`
+	Amount          float64 `json:"amount"`
+}
+
+// Sale representa a venda finalizada que gera comissão
+type Sale struct {
+	SaleID     string        `json:"sale_id"`
+	BranchID   string        `json:"branch_id"`
+	CustomerID string        `json:"customer_id"`
+	SaleDate   time.Time     `json:"sale_date"`
+	Items      []SaleItem    `json:"items"`
+	Payments   []SalePayment `json:"payments"`
+}
+
+// Status define a situação da comissão
+type Status string
+
+const (
+	StatusAccrued  Status = "accrued"  // Comissão lançada pela venda finalizada
+	StatusReversed Status = "reversed" // Comissão estornada pelo cancelamento da venda
+)
+
+// Line detalha a comissão de um item em uma forma de pagamento
+type Line struct {
+	ProductID       string  `json:"product_id"`
+	CategoryID      string  `json:"category_id"`
+	PaymentMethodID string  `json:"payment_method_id,omitempty"`
+	Base            float64 `json:"base"`
+	Percentage      float64 `json:"percentage"`
+	Amount          float64 `json:"amount"`
+	RuleID          string  `json:"rule_id,omitempty"` // Vazio quando vale o percentual padrão do vendedor
+}
+
+// Commission representa a comissão de um vendedor sobre uma venda
+type Commission struct {
+	ID            string     `json:"id"`
+	TenantID      string     `json:"tenant_id"`
+	SalesmanID    string     `json:"salesman_id"`
+	SaleID        string     `json:"sale_id"`
+	BranchID      string     `json:"branch_id,omitempty"`
+	CustomerID    string     `json:"customer_id,omitempty"`
+	SaleDate      time.Time  `json:"sale_date"`
+	BaseAmount    float64    `json:"base_amount"`
+	Amount        float64    `json:"amount"`
+	Status        Status     `json:"status"`
+	Lines         []Line     `json:"lines"`
+	ReversedAt    *time.Time `json:"reversed_at,omitempty"`
+	ReverseReason string     `json:"reverse_reason,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
+
+// Accrue calcula a comissão do vendedor sobre a venda. Cada item é rateado entre as formas de pagamento
+// na proporção dos valores pagos e recebe o percentual da regra mais específica, ou o padrão do vendedor
+func Accrue(s *Salesman, rules []*Rule, sale Sale) (*Commission, error) {
+	if !s.Active {
+		return nil, ErrInactive
+	}
+	if sale.SaleID == "" {
+		return nil, ErrEmptySaleID
+	}
+	if len(sale.Items) == 0 {
+		return nil, ErrEmptySaleItems
+	}
+
+	paymentsTotal := 0.0
+	for _, p := range sale.Payments {
+		if p.Amount <= 0 {
+			return nil, ErrInvalidPaymentAmount
+		}
+		paymentsTotal += p.Amount
+	}
+	payments := sale.Payments
+	if len(payments) == 0 {
+		payments = []SalePayment{{Amount: 1}}
+		paymentsTotal = 1
+	}
+
+	saleDate := sale.SaleDate
+	if saleDate.IsZero() {
+		saleDate = time.Now()
+	}
+
+	c := &Commission{
+		ID:         uuid.New().String(),
+		TenantID:   s.TenantID,
+		SalesmanID: s.ID,
+		SaleID:     sale.SaleID,
+		BranchID:   sale.BranchID,
+		CustomerID: sale.CustomerID,
+		SaleDate:   saleDate,
+		Status:     StatusAccrued,
+		Lines:      make([]Line, 0, len(sale.Items)*len(payments)),
+		CreatedAt:  time.Now(),
+	}
+
+	for _, item := range sale.Items {
+		if item.Amount < 0 {
+			return nil, ErrInvalidItemAmount
+		}
+		for _, p := range payments {
+			base := round(item.Amount * p.Amount / paymentsTotal)
+			percentage, ruleID := s.CommissionPercent, ""
+			best := -1
+			for _, r := range rules {
+				if score := r.specificity(s.ID, item.CategoryID, p.PaymentMethodID); score > best {
+					best, percentage, ruleID = score, r.Percentage, r.ID
+				}
+			}
+
+			line := Line{
+				ProductID:       item.ProductID,
+				CategoryID:      item.CategoryID,
+				PaymentMethodID: p.PaymentMethodID,
+				Base:            base,
+				Percentage:      percentage,
+				Amount:          round(base * percentage / 100),
+				RuleID:          ruleID,
+			}
+			c.BaseAmount += line.Base
+			c.Amount += line.Amount
+			c.Lines = append(c.Lines, line)
+		}
+	}
+
+	c.BaseAmount = round(c.BaseAmount)
+	c.Amount = round(c.Amount)
+	if c.Amount <= 0 {
+		return nil, ErrNoCommission
+	}
+
+	return c, nil
+}
+
+// Statement representa o extrato de comissões do vendedor no período. Estornos entram no período
+// em que a venda foi cancelada, para não alterar extratos já pagos
+type Statement struct {
+	SalesmanID   string        `json:"salesman_id"`
+	SalesmanName string        `json:"salesman_name"`
+	StartDate    time.Time     `json:"start_date"`
+	EndDate      time.Time     `json:"end_date"`
+	Sales        int           `json:"sales"`
+	BaseAmount   float64       `json:"base_amount"`
+	Accrued      float64       `json:"accrued"`
+	Reversed     float64       `json:"reversed"`
+	Net          float64       `json:"net"`
+	Commissions  []*Commission `json:"commissions"`
+}
+
+// NewStatement monta o extrato a partir das comissões vendidas ou estornadas no período
+func NewStatement(s *Salesman, startDate, endDate time.Time, commissions []*Commission) *Statement {
+	st := &Statement{
+		SalesmanID:   s.ID,
+		SalesmanName: s.Name,
+		StartDate:    startDate,
+		EndDate:      endDate,
+		Commissions:  commissions,
+	}
+	for _, c := range commissions {
+		if !c.SaleDate.Before(startDate) && !c.SaleDate.After(endDate) {
+			st.Sales++
+			st.BaseAmount += c.BaseAmount
+			st.Accrued += c.Amount
+		}
+		if c.ReversedAt != nil && !c.ReversedAt.Before(startDate) && !c.ReversedAt.After(endDate) {
+			st.Reversed += c.Amount
+		}
+	}
+	st.BaseAmount = round(st.BaseAmount)
+	st.Accrued = round(st.Accrued)
+	st.Reversed = round(st.Reversed)
+	st.Net = round(st.Accrued - st.Reversed)
+	return st
+}
+
+// round arredonda valores monetários para centavos
+func round(value float64) float64 {
+	return math.Round(value*100) / 100
+}
@@ -0,0 +1,48 @@
+package salesman
+
+import (
+	"context"
+	"time"
+)
+
+// Filter define os filtros para listagem de vendedores
+type Filter struct {
+	Name   string
+	Active *bool
+}
+
+// Repository define a interface para operações de repositório de vendedores e comissões
+type Repository interface {
+	// Create grava um novo vendedor
+	Create(ctx context.Context, s *Salesman) error
+
+	// Update atualiza um vendedor
+	Update(ctx context.Context, s *Salesman) error
+
+	// FindByID busca um vendedor pelo ID
+	FindByID(ctx context.Context, id string) (*Salesman, error)
+
+	// List lista os vendedores com filtros e paginação
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*Salesman, error)
+
+	// Count conta os vendedores que atendem aos filtros
+	Count(ctx context.Context, filter Filter) (int, error)
+
+	// CreateRule grava uma regra de comissão
+	CreateRule(ctx context.Context, r *Rule) error
+
+	// DeleteRule remove uma regra de comissão
+	DeleteRule(ctx context.Context, id string) error
+
+	// ListRules lista as regras gerais e as do vendedor; salesmanID vazio lista todas
+	ListRules(ctx context.Context, salesmanID string) ([]*Rule, error)
+
+	// Accrue grava a comissão de uma venda finalizada
+	Accrue(ctx context.Context, c *Commission) error
+
+	// Reverse estorna a comissão da venda cancelada
+	Reverse(ctx context.Context, saleID, reason string) (*Commission, error)
+
+	// ListCommissions lista as comissões do vendedor vendidas ou estornadas no período
+	ListCommissions(ctx context.Context, salesmanID string, startDate, endDate time.Time) ([]*Commission, error)
+}
@@ -0,0 +1,148 @@
+package supplier
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
+)
+
+var (
+	ErrEmptyTenantID      = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyName          = errors.New("nome não pode ser vazio")
+	ErrEmptyDocument      = errors.New("documento não pode ser vazio")
+	ErrInvalidDocument    = pkgdocument.ErrInvalid
+	ErrInvalidPaymentTerm = errors.New("prazo de pagamento inválido, use dias separados por \"/\" (ex.: 30/60/90)")
+)
+
+// Address representa o endereço do fornecedor
+type Address struct {
+	Street     string `json:"street"`     // Logradouro
+	Number     string `json:"number"`     // Número
+	Complement string `json:"complement"` // Complemento
+	District   string `json:"district"`   // Bairro
+	City       string `json:"city"`       // Cidade
+	State      string `json:"state"`      // Estado
+	ZipCode    string `json:"zip_code"`   // CEP
+	CityCode   string `json:"city_code"`  // Código IBGE da cidade
+	StateCode  string `json:"state_code"` // Código IBGE do estado
+}
+
+// Supplier representa um fornecedor do tenant
+type Supplier struct {
+	ID            string    `json:"id"`
+	TenantID      string    `json:"tenant_id"`
+	Name          string    `json:"name"`           // Razão Social
+	TradeName     string    `json:"trade_name"`     // Nome Fantasia
+	Document      string    `json:"document"`       // CNPJ/CPF
+	StateDocument string    `json:"state_document"` // Inscrição Estadual
+	Email         string    `json:"email"`
+	Phone         string    `json:"phone"`
+	PaymentTerm   string    `json:"payment_term"` // Prazos em dias, ex.: 30/60/90
+	Address       Address   `json:"address"`
+	Notes         string    `json:"notes"`
+	Active        bool      `json:"active"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// NewSupplier cria um novo fornecedor
+func NewSupplier(tenantID, name, tradeName, document, paymentTerm string) (*Supplier, error) {
+	if tenantID == "" {
+		return nil, ErrEmptyTenantID
+	}
+	if strings.TrimSpace(name) == "" {
+		return nil, ErrEmptyName
+	}
+	if strings.TrimSpace(document) == "" {
+		return nil, ErrEmptyDocument
+	}
+	normalized, _, err := pkgdocument.Validate(document)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := ParsePaymentTerm(paymentTerm); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	return &Supplier{
+		ID:          uuid.New().String(),
+		TenantID:    tenantID,
+		Name:        strings.TrimSpace(name),
+		TradeName:   strings.TrimSpace(tradeName),
+		Document:    normalized,
+		PaymentTerm: strings.TrimSpace(paymentTerm),
+		Active:      true,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// Update atualiza os dados cadastrais do fornecedor
+func (s *Supplier) Update(name, tradeName, stateDocument, email, phone, paymentTerm, notes string, address Address) error {
+	if strings.TrimSpace(name) == "" {
+		return ErrEmptyName
+	}
+	if _, err := ParsePaymentTerm(paymentTerm); err != nil {
+		return err
+	}
+
+	s.Name = strings.TrimSpace(name)
+	s.TradeName = strings.TrimSpace(tradeName)
+	s.StateDocument = stateDocument
+	s.Email = email
+	s.Phone = phone
+	s.PaymentTerm = strings.TrimSpace(paymentTerm)
+	s.Notes = notes
+	s.Address = address
+	s.UpdatedAt = time.Now()
+	return nil
+}
+
+// Activate ativa o fornecedor
+func (s *Supplier) Activate() {
+	s.Active = true
+	s.UpdatedAt = time.Now()
+}
+
+// Deactivate desativa o fornecedor
+func (s *Supplier) Deactivate() {
+	s.Active = false
+	s.UpdatedAt = time.Now()
+}
+
+// InstallmentDays retorna os prazos das parcelas do fornecedor em dias
+func (s *Supplier) InstallmentDays() []int {
+	days, err := ParsePaymentTerm(s.PaymentTerm)
+	if err != nil {
+		return []int{0}
+	}
+	return days
+}
+
+// ParsePaymentTerm converte um prazo de pagamento ("30/60/90") em dias por parcela.
+// Um prazo vazio significa pagamento à vista, com uma única parcela
+func ParsePaymentTerm(term string) ([]int, error) {
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return []int{0}, nil
+	}
+
+	parts := strings.Split(term, "/")
+	days := make([]int, 0, len(parts))
+	last := -1
+	for _, part := range parts {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || value < 0 || value <= last {
+			return nil, ErrInvalidPaymentTerm
+		}
+		days = append(days, value)
+		last = value
+	}
+
+	return days, nil
+}
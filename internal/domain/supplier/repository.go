@@ -0,0 +1,29 @@
+package supplier
+
+import (
+	"context"
+)
+
+// Repository define a interface para operações de repositório de fornecedores
+type Repository interface {
+	// Create cria um novo fornecedor
+	Create(ctx context.Context, s *Supplier) error
+
+	// FindByID busca um fornecedor pelo ID
+	FindByID(ctx context.Context, id string) (*Supplier, error)
+
+	// FindByDocument busca um fornecedor pelo documento
+	FindByDocument(ctx context.Context, document string) (*Supplier, error)
+
+	// List lista os fornecedores com filtro por nome/documento e paginação
+	List(ctx context.Context, search string, limit, offset int) ([]*Supplier, error)
+
+	// Count conta os fornecedores que atendem ao filtro
+	Count(ctx context.Context, search string) (int, error)
+
+	// Update atualiza um fornecedor existente
+	Update(ctx context.Context, s *Supplier) error
+
+	// Delete remove um fornecedor
+	Delete(ctx context.Context, id string) error
+}
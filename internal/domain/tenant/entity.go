@@ -5,13 +5,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
 )
 
 var (
 	ErrEmptyID         = errors.New("id não pode ser vazio")
 	ErrEmptyName       = errors.New("nome não pode ser vazio")
 	ErrEmptyDocument   = errors.New("documento não pode ser vazio")
-	ErrInvalidDocument = errors.New("documento inválido")
+	ErrInvalidDocument = pkgdocument.ErrInvalid
 	ErrInvalidTenantID = errors.New("ID de tenant inválido")
 	ErrTenantNotActive = errors.New("tenant não está ativo")
 )
@@ -23,21 +24,25 @@ const (
 	StatusActive   Status = "active"
 	StatusInactive Status = "inactive"
 	StatusBlocked  Status = "blocked"
+	StatusDeleted  Status = "deleted" // Excluído, aguardando o expurgo do schema
 )
 
 // Tenant representa uma empresa no sistema multi-tenant
 type Tenant struct {
-	ID          string    `json:"id"`
-	Name        string    `json:"name"`
-	Document    string    `json:"document"` // CNPJ da empresa
-	Email       string    `json:"email"`
-	Phone       string    `json:"phone"`
-	Status      Status    `json:"status"`
-	Schema      string    `json:"schema"`       // Nome do schema no banco de dados
-	PlanType    string    `json:"plan_type"`    // Tipo de plano contratado
-	MaxBranches int       `json:"max_branches"` // Número máximo de filiais permitidas
-	CreatedAt   time.Time `json:"created_at"`
-	UpdatedAt   time.Time `json:"updated_at"`
+	ID            string     `json:"id"`
+	Name          string     `json:"name"`
+	Document      string     `json:"document"` // CNPJ da empresa
+	Email         string     `json:"email"`
+	Phone         string     `json:"phone"`
+	Status        Status     `json:"status"`
+	Schema        string     `json:"schema"`                    // Nome do schema no banco de dados
+	PlanType      string     `json:"plan_type"`                 // Tipo de plano contratado
+	MaxBranches   int        `json:"max_branches"`              // Número máximo de filiais permitidas; zero é ilimitado
+	PlanExpiresAt *time.Time `json:"plan_expires_at,omitempty"` // Fim da vigência do plano; nulo não expira
+	DeletedAt     *time.Time `json:"deleted_at,omitempty"`
+	PurgeAfter    *time.Time `json:"purge_after,omitempty"` // A partir de quando o schema pode ser expurgado
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
 }
 
 // NewTenant cria um novo tenant
@@ -50,7 +55,19 @@ func NewTenant(name, document, email, phone, planType string, maxBranches int) (
 		return nil, ErrEmptyDocument
 	}
 
-	// Aqui poderia haver validação de CNPJ
+	normalized, err := pkgdocument.ValidateCNPJ(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem limite de filiais informado, vale o do plano
+	plan, err := FindPlan(planType)
+	if err != nil {
+		return nil, err
+	}
+	if maxBranches <= 0 {
+		maxBranches = plan.MaxBranches
+	}
 
 	id := uuid.New().String()
 	schema := "tenant_" + id[:8] // Criamos um schema baseado no ID
@@ -58,7 +75,7 @@ func NewTenant(name, document, email, phone, planType string, maxBranches int) (
 	return &Tenant{
 		ID:          id,
 		Name:        name,
-		Document:    document,
+		Document:    normalized,
 		Email:       email,
 		Phone:       phone,
 		Status:      StatusActive,
@@ -93,11 +110,25 @@ func (t *Tenant) Block() {
 	t.UpdatedAt = time.Now()
 }
 
-// ChangePlan altera o plano do tenant
-func (t *Tenant) ChangePlan(planType string, maxBranches int) {
+// ChangePlan altera o plano do tenant e a sua vigência. Sem limite de filiais informado, vale o
+// do novo plano
+func (t *Tenant) ChangePlan(planType string, maxBranches int, expiresAt *time.Time) error {
+	plan, err := FindPlan(planType)
+	if err != nil {
+		return err
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return ErrInvalidPlanExpires
+	}
+	if maxBranches <= 0 {
+		maxBranches = plan.MaxBranches
+	}
+
 	t.PlanType = planType
 	t.MaxBranches = maxBranches
+	t.PlanExpiresAt = expiresAt
 	t.UpdatedAt = time.Now()
+	return nil
 }
 
 // Update atualiza os dados do tenant
@@ -112,3 +143,41 @@ func (t *Tenant) Update(name, email, phone string) error {
 	t.UpdatedAt = time.Now()
 	return nil
 }
+
+// IsDeleted verifica se o tenant foi excluído e aguarda o expurgo
+func (t *Tenant) IsDeleted() bool {
+	return t.DeletedAt != nil
+}
+
+// SoftDelete marca o tenant como excluído. Os dados continuam no schema até o fim do prazo de
+// carência, quando podem ser expurgados
+func (t *Tenant) SoftDelete(grace time.Duration) error {
+	if t.IsDeleted() {
+		return ErrTenantDeleted
+	}
+	now := time.Now()
+	purgeAfter := now.Add(grace)
+	t.Status = StatusDeleted
+	t.DeletedAt = &now
+	t.PurgeAfter = &purgeAfter
+	t.UpdatedAt = now
+	return nil
+}
+
+// Restore desfaz a exclusão enquanto o schema ainda não foi expurgado. O tenant volta inativo,
+// para ser reativado explicitamente
+func (t *Tenant) Restore() error {
+	if !t.IsDeleted() {
+		return ErrTenantNotDeleted
+	}
+	t.Status = StatusInactive
+	t.DeletedAt = nil
+	t.PurgeAfter = nil
+	t.UpdatedAt = time.Now()
+	return nil
+}
+
+// CanPurge verifica se o prazo de carência do tenant excluído já terminou
+func (t *Tenant) CanPurge(now time.Time) bool {
+	return t.IsDeleted() && t.PurgeAfter != nil && !now.Before(*t.PurgeAfter)
+}
@@ -0,0 +1,65 @@
+package tenant
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrTenantDeleted    = errors.New("tenant excluído")
+	ErrTenantNotDeleted = errors.New("tenant não está excluído")
+	ErrGracePeriod      = errors.New("prazo de carência do tenant ainda não terminou")
+)
+
+const (
+	// DefaultGracePeriod é o prazo entre a exclusão do tenant e o expurgo do schema
+	DefaultGracePeriod = 30 * 24 * time.Hour
+
+	// FiscalRetention é o prazo legal de guarda dos documentos fiscais (5 anos)
+	FiscalRetention = 5 * 365 * 24 * time.Hour
+)
+
+// AuditAction identifica um evento do ciclo de desligamento do tenant
+type AuditAction string
+
+const (
+	AuditDeleted  AuditAction = "deleted"  // Exclusão lógica solicitada
+	AuditRestored AuditAction = "restored" // Exclusão desfeita dentro do prazo de carência
+	AuditExported AuditAction = "exported" // Dados exportados
+	AuditArchived AuditAction = "archived" // Exportação guardada para a retenção fiscal
+	AuditPurged   AuditAction = "purged"   // Schema e cadastro removidos
+)
+
+// AuditEntry é um evento da trilha de auditoria do tenant. Guarda os dados de identificação do
+// tenant porque continua existindo depois do expurgo
+type AuditEntry struct {
+	ID             string         `json:"id"`
+	TenantID       string         `json:"tenant_id"`
+	TenantName     string         `json:"tenant_name"`
+	TenantDocument string         `json:"tenant_document"`
+	Schema         string         `json:"schema"`
+	Action         AuditAction    `json:"action"`
+	Actor          string         `json:"actor"` // Usuário ou processo que executou a ação
+	Details        map[string]any `json:"details,omitempty"`
+	CreatedAt      time.Time      `json:"created_at"`
+}
+
+// NewAuditEntry cria um evento de auditoria para o tenant
+func NewAuditEntry(t *Tenant, action AuditAction, actor string, details map[string]any) *AuditEntry {
+	if details == nil {
+		details = map[string]any{}
+	}
+	return &AuditEntry{
+		ID:             uuid.New().String(),
+		TenantID:       t.ID,
+		TenantName:     t.Name,
+		TenantDocument: t.Document,
+		Schema:         t.Schema,
+		Action:         action,
+		Actor:          actor,
+		Details:        details,
+		CreatedAt:      time.Now(),
+	}
+}
@@ -0,0 +1,163 @@
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrUnknownPlan        = errors.New("plano desconhecido")
+	ErrPlanLimitExceeded  = errors.New("limite do plano excedido")
+	ErrModuleNotInPlan    = errors.New("módulo não incluído no plano")
+	ErrPlanReadOnly       = errors.New("plano expirado: tenant em modo somente leitura")
+	ErrInvalidPlanExpires = errors.New("data de expiração do plano deve ser futura")
+)
+
+// PlanGracePeriod é o prazo após a expiração do plano em que o tenant mantém acesso completo.
+// Depois dele, o tenant passa a somente leitura até a renovação
+const PlanGracePeriod = 7 * 24 * time.Hour
+
+// Module identifica um conjunto de rotas que depende do plano contratado. Cadastros básicos
+// (filiais, usuários, clientes, fornecedores, perdas e formas de pagamento) fazem parte de todos
+// os planos e não têm módulo
+type Module string
+
+const (
+	ModuleFiscal    Module = "fiscal"    // Certificados e configurações de NF-e/NFC-e
+	ModuleFinance   Module = "finance"   // Contas a pagar e a receber, cobrança, Pix, conciliação e cartões
+	ModuleMarketing Module = "marketing" // Fidelidade, promoções e tabelas de preço
+	ModuleSalesmen  Module = "salesmen"  // Vendedores e comissões
+	ModuleAssistant Module = "assistant" // Assistente MCP
+)
+
+// Plan é um plano do catálogo. Limites com valor zero são ilimitados
+type Plan struct {
+	Code               string   `json:"code"`
+	Name               string   `json:"name"`
+	MaxBranches        int      `json:"max_branches"`
+	MaxUsers           int      `json:"max_users"`
+	MaxTerminals       int      `json:"max_terminals"`        // Terminais de PDV ativos
+	MaxFiscalDocuments int      `json:"max_fiscal_documents"` // NF-e e NFC-e emitidas por mês
+	Modules            []Module `json:"modules"`
+}
+
+// DefaultPlan é o plano aplicado a tenants com um plan_type fora do catálogo, cadastrados antes
+// de ele existir
+const DefaultPlan = "basic"
+
+// catalog é o catálogo de planos, do menor para o maior
+var catalog = []Plan{
+	{
+		Code:               "basic",
+		Name:               "Básico",
+		MaxBranches:        1,
+		MaxUsers:           5,
+		MaxTerminals:       2,
+		MaxFiscalDocuments: 3000,
+		Modules:            []Module{ModuleFiscal},
+	},
+	{
+		Code:               "professional",
+		Name:               "Profissional",
+		MaxBranches:        3,
+		MaxUsers:           20,
+		MaxTerminals:       10,
+		MaxFiscalDocuments: 20000,
+		Modules:            []Module{ModuleFiscal, ModuleFinance, ModuleMarketing},
+	},
+	{
+		Code:    "enterprise",
+		Name:    "Enterprise",
+		Modules: []Module{ModuleFiscal, ModuleFinance, ModuleMarketing, ModuleSalesmen, ModuleAssistant},
+	},
+}
+
+// FindPlan busca um plano do catálogo pelo código
+func FindPlan(code string) (Plan, error) {
+	for _, plan := range catalog {
+		if plan.Code == code {
+			return plan, nil
+		}
+	}
+	return Plan{}, fmt.Errorf("%w: %s", ErrUnknownPlan, code)
+}
+
+// PlanFor retorna o plano do código informado ou o DefaultPlan, se ele não estiver no catálogo
+func PlanFor(code string) Plan {
+	if plan, err := FindPlan(code); err == nil {
+		return plan
+	}
+	plan, _ := FindPlan(DefaultPlan)
+	return plan
+}
+
+// Plans lista o catálogo de planos, do menor para o maior
+func Plans() []Plan {
+	return append([]Plan(nil), catalog...)
+}
+
+// HasModule verifica se o módulo está incluído no plano
+func (p Plan) HasModule(module Module) bool {
+	for _, m := range p.Modules {
+		if m == module {
+			return true
+		}
+	}
+	return false
+}
+
+// LimitError informa qual limite do plano foi atingido. errors.Is(err, ErrPlanLimitExceeded)
+// reconhece qualquer LimitError
+type LimitError struct {
+	Resource string // Recurso limitado, como "filiais" ou "usuários"
+	Limit    int
+}
+
+// NewLimitError cria o erro de limite atingido para o recurso
+func NewLimitError(resource string, limit int) *LimitError {
+	return &LimitError{Resource: resource, Limit: limit}
+}
+
+func (e *LimitError) Error() string {
+	return fmt.Sprintf("%s: máximo de %d %s", ErrPlanLimitExceeded, e.Limit, e.Resource)
+}
+
+func (e *LimitError) Unwrap() error {
+	return ErrPlanLimitExceeded
+}
+
+// PlanState é a situação da assinatura do tenant
+type PlanState string
+
+const (
+	PlanStateActive   PlanState = "active"    // Plano vigente
+	PlanStateGrace    PlanState = "grace"     // Expirado, dentro do prazo de carência
+	PlanStateReadOnly PlanState = "read_only" // Expirado após a carência: apenas consultas
+)
+
+// Subscription é o plano do tenant e a situação da assinatura em um momento
+type Subscription struct {
+	TenantID  string     `json:"tenant_id"`
+	Plan      Plan       `json:"plan"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	State     PlanState  `json:"state"`
+}
+
+// Subscription calcula a situação da assinatura do tenant no momento informado. Sem data de
+// expiração, o plano é vigente por tempo indeterminado
+func (t *Tenant) Subscription(now time.Time) *Subscription {
+	s := &Subscription{
+		TenantID:  t.ID,
+		Plan:      PlanFor(t.PlanType),
+		ExpiresAt: t.PlanExpiresAt,
+		State:     PlanStateActive,
+	}
+	if t.PlanExpiresAt != nil && !now.Before(*t.PlanExpiresAt) {
+		s.State = PlanStateGrace
+		if !now.Before(t.PlanExpiresAt.Add(PlanGracePeriod)) {
+			s.State = PlanStateReadOnly
+		}
+	}
+	return s
+}
@@ -2,6 +2,7 @@ package tenant
 
 import (
 	"context"
+	"time"
 )
 
 // Repository define a interface para operações de repositório de tenants
@@ -38,6 +39,24 @@ type Repository interface {
 
 	// ExistsByDocument verifica se um tenant existe pelo documento
 	ExistsByDocument(ctx context.Context, document string) (bool, error)
+
+	// SoftDelete grava a exclusão lógica do tenant e o evento de auditoria
+	SoftDelete(ctx context.Context, t *Tenant, audit *AuditEntry) error
+
+	// Restore desfaz a exclusão lógica do tenant e grava o evento de auditoria
+	Restore(ctx context.Context, t *Tenant, audit *AuditEntry) error
+
+	// ListPurgeable lista os tenants excluídos cujo prazo de carência terminou
+	ListPurgeable(ctx context.Context, now time.Time) ([]*Tenant, error)
+
+	// Purge remove o schema e o cadastro do tenant e grava o evento de auditoria
+	Purge(ctx context.Context, t *Tenant, audit *AuditEntry) error
+
+	// RecordAudit grava um evento na trilha de auditoria
+	RecordAudit(ctx context.Context, audit *AuditEntry) error
+
+	// ListAudit lista a trilha de auditoria de um tenant, do evento mais antigo ao mais recente
+	ListAudit(ctx context.Context, tenantID string) ([]*AuditEntry, error)
 }
 
 // BranchRepository define a interface para operações de repositório de filiais
@@ -0,0 +1,81 @@
+package terminal
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	ErrEmptyTenantID = errors.New("ID do tenant não pode ser vazio")
+	ErrEmptyBranchID = errors.New("filial do terminal é obrigatória")
+	ErrEmptyCode     = errors.New("código do terminal é obrigatório")
+	ErrCodeTooLong   = errors.New("código do terminal deve ter no máximo 10 caracteres")
+	ErrEmptyName     = errors.New("nome do terminal é obrigatório")
+	ErrInvalidStatus = errors.New("status inválido, use active ou inactive")
+)
+
+// Status define a situação do terminal
+type Status string
+
+const (
+	StatusActive   Status = "active"   // Em uso; conta no limite de terminais do plano
+	StatusInactive Status = "inactive" // Desativado
+)
+
+// Terminal representa um terminal de PDV (caixa) de uma filial
+type Terminal struct {
+	ID        string    `json:"id"`
+	TenantID  string    `json:"tenant_id"`
+	BranchID  string    `json:"branch_id"`
+	Code      string    `json:"code"` // Número do caixa na filial
+	Name      string    `json:"name"`
+	Status    Status    `json:"status"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// NewTerminal cria um terminal ativo
+func NewTerminal(tenantID, branchID, code, name string) (*Terminal, error) {
+	now := time.Now()
+	t := &Terminal{
+		ID:        uuid.New().String(),
+		TenantID:  tenantID,
+		BranchID:  branchID,
+		Code:      strings.TrimSpace(code),
+		Name:      strings.TrimSpace(name),
+		Status:    StatusActive,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Validate valida os dados do terminal
+func (t *Terminal) Validate() error {
+	switch {
+	case t.TenantID == "":
+		return ErrEmptyTenantID
+	case t.BranchID == "":
+		return ErrEmptyBranchID
+	case t.Code == "":
+		return ErrEmptyCode
+	case len(t.Code) > 10:
+		return ErrCodeTooLong
+	case t.Name == "":
+		return ErrEmptyName
+	case t.Status != StatusActive && t.Status != StatusInactive:
+		return ErrInvalidStatus
+	}
+	return nil
+}
+
+// IsActive verifica se o terminal está ativo
+func (t *Terminal) IsActive() bool {
+	return t.Status == StatusActive
+}
@@ -0,0 +1,27 @@
+package terminal
+
+import "context"
+
+// Filter define os filtros para listagem de terminais
+type Filter struct {
+	BranchID string
+	Status   Status
+}
+
+// Repository define a interface para operações de repositório de terminais de PDV
+type Repository interface {
+	// Create grava um novo terminal, respeitando o limite de terminais ativos do plano
+	Create(ctx context.Context, t *Terminal) error
+
+	// FindByID busca um terminal pelo ID
+	FindByID(ctx context.Context, id string) (*Terminal, error)
+
+	// List lista os terminais com filtros e paginação
+	List(ctx context.Context, filter Filter, limit, offset int) ([]*Terminal, error)
+
+	// Count conta os terminais que atendem aos filtros
+	Count(ctx context.Context, filter Filter) (int, error)
+
+	// UpdateStatus ativa ou desativa um terminal; a ativação respeita o limite do plano
+	UpdateStatus(ctx context.Context, id string, status Status) error
+}
@@ -0,0 +1,91 @@
+package usage
+
+import (
+	"errors"
+	"strconv"
+	"time"
+)
+
+var (
+	ErrInvalidPeriod = errors.New("período inválido: use o formato AAAA-MM")
+)
+
+// PeriodLayout é o formato de um período de medição: ano e mês
+const PeriodLayout = "2006-01"
+
+// Usage é o consumo de um tenant em um mês, base da cobrança. Documentos fiscais e tokens do
+// assistente são acumulados no mês; usuários ativos, filiais, terminais e armazenamento guardam o
+// maior valor medido no mês
+type Usage struct {
+	TenantID              string    `json:"tenant_id"`
+	TenantName            string    `json:"tenant_name"`
+	TenantDocument        string    `json:"tenant_document"`
+	PlanType              string    `json:"plan_type"`
+	Period                time.Time `json:"period"`           // Primeiro dia do mês
+	FiscalDocuments       int64     `json:"fiscal_documents"` // NF-e e NFC-e numeradas no mês
+	ActiveUsers           int       `json:"active_users"`
+	Branches              int       `json:"branches"`
+	Terminals             int       `json:"terminals"` // Terminais de PDV ativos
+	AssistantInputTokens  int64     `json:"assistant_input_tokens"`
+	AssistantOutputTokens int64     `json:"assistant_output_tokens"`
+	StorageBytes          int64     `json:"storage_bytes"` // Tamanho das tabelas do schema, com índices
+	UpdatedAt             time.Time `json:"updated_at"`
+}
+
+// Columns são as colunas da exportação mensal, na ordem de Row
+var Columns = []string{
+	"periodo",
+	"tenant_id",
+	"tenant",
+	"documento",
+	"plano",
+	"documentos_fiscais",
+	"usuarios_ativos",
+	"filiais",
+	"terminais_pdv",
+	"tokens_entrada_assistente",
+	"tokens_saida_assistente",
+	"armazenamento_bytes",
+	"atualizado_em",
+}
+
+// Row converte o consumo em uma linha da exportação mensal
+func (u *Usage) Row() []string {
+	return []string{
+		u.Period.Format(PeriodLayout),
+		u.TenantID,
+		u.TenantName,
+		u.TenantDocument,
+		u.PlanType,
+		strconv.FormatInt(u.FiscalDocuments, 10),
+		strconv.Itoa(u.ActiveUsers),
+		strconv.Itoa(u.Branches),
+		strconv.Itoa(u.Terminals),
+		strconv.FormatInt(u.AssistantInputTokens, 10),
+		strconv.FormatInt(u.AssistantOutputTokens, 10),
+		strconv.FormatInt(u.StorageBytes, 10),
+		u.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// PeriodOf retorna o período (primeiro dia do mês) de um instante
+func PeriodOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+// ParsePeriod interpreta um período no formato AAAA-MM. Vazio é o mês corrente
+func ParsePeriod(value string) (time.Time, error) {
+	if value == "" {
+		return PeriodOf(time.Now()), nil
+	}
+	period, err := time.Parse(PeriodLayout, value)
+	if err != nil {
+		return time.Time{}, ErrInvalidPeriod
+	}
+	return period, nil
+}
+
+// IsCurrent verifica se o período é o mês corrente, ainda em medição
+func IsCurrent(period time.Time) bool {
+	return PeriodOf(time.Now()).Equal(PeriodOf(period))
+}
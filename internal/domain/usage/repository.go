@@ -0,0 +1,26 @@
+package usage
+
+import (
+	"context"
+	"time"
+)
+
+// Repository define a interface para a medição de consumo dos tenants
+type Repository interface {
+	// RecordTokens acumula no mês corrente os tokens consumidos pelo assistente do tenant
+	RecordTokens(ctx context.Context, tenantID string, inputTokens, outputTokens int) error
+
+	// Collect mede no schema do tenant os documentos fiscais, usuários ativos, filiais,
+	// terminais e armazenamento do mês corrente e grava a medição
+	Collect(ctx context.Context, tenantID string) (*Usage, error)
+
+	// CollectAll executa Collect para todos os tenants ativos. Um tenant com falha não
+	// interrompe os demais; os erros são retornados juntos ao final
+	CollectAll(ctx context.Context) error
+
+	// Find busca o consumo gravado de um tenant no período
+	Find(ctx context.Context, tenantID string, period time.Time) (*Usage, error)
+
+	// List lista o consumo gravado de todos os tenants no período
+	List(ctx context.Context, period time.Time) ([]*Usage, error)
+}
@@ -39,6 +39,18 @@ type User struct {
 	LastLoginAt time.Time `json:"last_login_at"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
+
+	// Branches são os vínculos com outras filiais além da principal, carregados sob demanda
+	Branches []Membership `json:"branches,omitempty"`
+}
+
+// Login é uma entrada do índice global de logins, que aponta o tenant de cada email sem que
+// seja preciso consultar os schemas dos tenants
+type Login struct {
+	Email      string `json:"email"`
+	TenantID   string `json:"tenant_id"`
+	TenantName string `json:"tenant_name"`
+	UserID     string `json:"user_id"`
 }
 
 // SetPassword configura a senha do usuário com hash
@@ -80,10 +92,7 @@ func (u *User) HasAccessToTenant(tenantID string) bool {
 // HasAccessToBranch verifica se o usuário tem acesso à filial especificada
 // Administradores têm acesso a todas as filiais do seu tenant
 func (u *User) HasAccessToBranch(branchID string) bool {
-	// Administradores têm acesso a todas as filiais
-	if u.IsAdmin() {
-		return true
-	}
-	// Outros usuários só têm acesso à sua própria filial
-	return u.BranchID == branchID
+	// Os demais usuários têm acesso à filial principal e às filiais em que têm vínculo
+	_, ok := u.RoleInBranch(branchID)
+	return ok
 }
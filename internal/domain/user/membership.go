@@ -0,0 +1,78 @@
+package user
+
+import (
+	"errors"
+	"time"
+)
+
+// Erros de vínculo do usuário com filiais
+var (
+	ErrEmptyMembershipBranch = errors.New("filial do vínculo é obrigatória")
+	ErrInvalidRole           = errors.New("papel inválido: use admin, manager ou staff")
+	ErrDuplicatedMembership  = errors.New("filial informada mais de uma vez")
+	ErrNoBranchAccess        = errors.New("usuário sem acesso à filial")
+)
+
+// Membership é o vínculo do usuário com uma filial em que trabalha, com o papel que exerce nela
+type Membership struct {
+	UserID     string    `json:"user_id"`
+	BranchID   string    `json:"branch_id"`
+	BranchName string    `json:"branch_name,omitempty"`
+	Role       Role      `json:"role"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// IsValid verifica se o papel é um dos papéis padrão do sistema
+func (r Role) IsValid() bool {
+	switch r {
+	case RoleAdmin, RoleManager, RoleStaff:
+		return true
+	}
+	return false
+}
+
+// NewMembership cria o vínculo do usuário com uma filial
+func NewMembership(userID, branchID string, role Role) (*Membership, error) {
+	if branchID == "" {
+		return nil, ErrEmptyMembershipBranch
+	}
+	if !role.IsValid() {
+		return nil, ErrInvalidRole
+	}
+	return &Membership{
+		UserID:    userID,
+		BranchID:  branchID,
+		Role:      role,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// ValidateMemberships garante que cada filial aparece uma única vez
+func ValidateMemberships(memberships []Membership) error {
+	seen := make(map[string]bool, len(memberships))
+	for _, m := range memberships {
+		if seen[m.BranchID] {
+			return ErrDuplicatedMembership
+		}
+		seen[m.BranchID] = true
+	}
+	return nil
+}
+
+// RoleInBranch retorna o papel do usuário na filial e se ele tem acesso a ela. Administradores
+// têm acesso a todas as filiais; na filial principal vale o papel do usuário e, nas demais, o
+// papel do vínculo carregado em Branches
+func (u *User) RoleInBranch(branchID string) (Role, bool) {
+	if u.IsAdmin() {
+		return RoleAdmin, true
+	}
+	if branchID != "" && u.BranchID == branchID {
+		return u.Role, true
+	}
+	for _, m := range u.Branches {
+		if m.BranchID == branchID {
+			return m.Role, true
+		}
+	}
+	return "", false
+}
@@ -15,8 +15,12 @@ type Repository interface {
 	// FindByEmail busca um usuário pelo email dentro de um tenant
 	FindByEmail(ctx context.Context, tenantID, email string) (*User, error)
 
-	// FindByEmailAcrossTenants busca um usuário pelo email em todos os tenants
-	FindByEmailAcrossTenants(ctx context.Context, email string) (*User, error)
+	// FindLogins busca no índice global de logins os tenants em que o email está cadastrado
+	FindLogins(ctx context.Context, email string) ([]Login, error)
+
+	// RebuildLogins reconstrói o índice global de logins a partir dos usuários de todos os
+	// tenants e retorna quantos logins foram indexados
+	RebuildLogins(ctx context.Context) (int, error)
 
 	// FindByBranch lista os usuários de uma determinada filial
 	FindByBranch(ctx context.Context, branchID string, limit, offset int) ([]*User, error)
@@ -48,6 +52,17 @@ type Repository interface {
 	// Exists verifica se um usuário existe
 	Exists(ctx context.Context, id string) (bool, error)
 
+	// ListMemberships lista os vínculos do usuário com filiais, incluindo a filial principal
+	ListMemberships(ctx context.Context, userID string) ([]Membership, error)
+
+	// ReplaceMemberships substitui os vínculos do usuário com filiais. O vínculo da filial
+	// principal acompanha o cadastro do usuário e é mantido
+	ReplaceMemberships(ctx context.Context, userID string, memberships []Membership) error
+
+	// FindBranchRole retorna o papel do usuário na filial, ou ErrNoBranchAccess quando ele não
+	// tem acesso a ela
+	FindBranchRole(ctx context.Context, userID, branchID string) (Role, error)
+
 	// TenantExists verifica se um tenant existe
 	TenantExists(ctx context.Context, tenantID string) (bool, error)
 }
@@ -0,0 +1,244 @@
+package database
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ExportManifest descreve o conteúdo do arquivo gerado por ExportSchema e é gravado nele como
+// manifest.json
+type ExportManifest struct {
+	Schema     string          `json:"schema"`
+	ExportedAt time.Time       `json:"exported_at"`
+	Tables     []ExportedTable `json:"tables"`
+	XMLFiles   int             `json:"xml_files"`
+}
+
+// ExportedTable é uma tabela exportada e a quantidade de linhas
+type ExportedTable struct {
+	Name string `json:"name"`
+	Rows int    `json:"rows"`
+}
+
+// xmlColumn é uma coluna de texto que guarda XML, como o XML autorizado de uma nota fiscal
+type xmlColumn struct {
+	table  string
+	column string
+}
+
+// ExportSchema grava em w um ZIP com todas as tabelas do schema, cada uma como <tabela>.json e
+// <tabela>.csv, e os XMLs fiscais encontrados em colunas *xml* como xml/<tabela>/<id>.xml. A
+// leitura é feita em uma única transação somente leitura, de forma que o arquivo é um retrato
+// consistente do schema
+func ExportSchema(ctx context.Context, db *pgxpool.Pool, schema string, w io.Writer) (*ExportManifest, error) {
+	if err := ValidateSchema(schema); err != nil {
+		return nil, err
+	}
+
+	tx, err := db.BeginTx(ctx, pgx.TxOptions{IsoLevel: pgx.RepeatableRead, AccessMode: pgx.ReadOnly})
+	if err != nil {
+		return nil, fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	tables, err := schemaTables(ctx, tx, schema)
+	if err != nil {
+		return nil, err
+	}
+	xmlColumns, err := schemaXMLColumns(ctx, tx, schema)
+	if err != nil {
+		return nil, err
+	}
+
+	manifest := &ExportManifest{Schema: schema, ExportedAt: time.Now()}
+	archive := zip.NewWriter(w)
+
+	for _, table := range tables {
+		rows, err := exportTableJSON(ctx, tx, archive, schema, table)
+		if err != nil {
+			return nil, err
+		}
+		if err := exportTableCSV(ctx, tx, archive, schema, table); err != nil {
+			return nil, err
+		}
+		manifest.Tables = append(manifest.Tables, ExportedTable{Name: table, Rows: rows})
+	}
+
+	for _, col := range xmlColumns {
+		count, err := exportXMLColumn(ctx, tx, archive, schema, col)
+		if err != nil {
+			return nil, err
+		}
+		manifest.XMLFiles += count
+	}
+
+	file, err := archive.Create("manifest.json")
+	if err != nil {
+		return nil, fmt.Errorf("falha ao criar manifest.json: %w", err)
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(manifest); err != nil {
+		return nil, fmt.Errorf("falha ao gravar manifest.json: %w", err)
+	}
+
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("falha ao finalizar arquivo: %w", err)
+	}
+	return manifest, nil
+}
+
+// schemaTables lista as tabelas do schema em ordem alfabética
+func schemaTables(ctx context.Context, tx pgx.Tx, schema string) ([]string, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT table_name
+		FROM information_schema.tables
+		WHERE table_schema = $1 AND table_type = 'BASE TABLE'
+		ORDER BY table_name`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar tabelas do schema: %w", err)
+	}
+	tables, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar tabelas do schema: %w", err)
+	}
+	return tables, nil
+}
+
+// schemaXMLColumns lista as colunas de texto ou xml com "xml" no nome, em tabelas com coluna id
+func schemaXMLColumns(ctx context.Context, tx pgx.Tx, schema string) ([]xmlColumn, error) {
+	rows, err := tx.Query(ctx, `
+		SELECT c.table_name, c.column_name
+		FROM information_schema.columns c
+		JOIN information_schema.tables t ON t.table_schema = c.table_schema AND t.table_name = c.table_name
+		WHERE c.table_schema = $1
+			AND t.table_type = 'BASE TABLE'
+			AND c.column_name ILIKE '%xml%'
+			AND c.data_type IN ('text', 'xml', 'character varying')
+			AND EXISTS (
+				SELECT 1 FROM information_schema.columns id
+				WHERE id.table_schema = c.table_schema AND id.table_name = c.table_name AND id.column_name = 'id'
+			)
+		ORDER BY c.table_name, c.column_name`, schema)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao listar colunas de XML: %w", err)
+	}
+	defer rows.Close()
+
+	var columns []xmlColumn
+	for rows.Next() {
+		var col xmlColumn
+		if err := rows.Scan(&col.table, &col.column); err != nil {
+			return nil, fmt.Errorf("falha ao ler coluna de XML: %w", err)
+		}
+		columns = append(columns, col)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao listar colunas de XML: %w", err)
+	}
+	return columns, nil
+}
+
+// exportTableJSON grava a tabela como um array JSON, uma linha por objeto, e retorna a
+// quantidade de linhas
+func exportTableJSON(ctx context.Context, tx pgx.Tx, archive *zip.Writer, schema, table string) (int, error) {
+	file, err := archive.Create(table + ".json")
+	if err != nil {
+		return 0, fmt.Errorf("falha ao criar %s.json: %w", table, err)
+	}
+
+	// row_to_json converte cada tipo do PostgreSQL (uuid, numeric, timestamp, jsonb) na sua
+	// representação JSON, sem depender dos tipos do driver
+	rows, err := tx.Query(ctx, fmt.Sprintf("SELECT row_to_json(t)::text FROM %s t", pgx.Identifier{schema, table}.Sanitize()))
+	if err != nil {
+		return 0, fmt.Errorf("falha ao exportar %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	count := 0
+	if _, err := io.WriteString(file, "["); err != nil {
+		return 0, err
+	}
+	for rows.Next() {
+		var row string
+		if err := rows.Scan(&row); err != nil {
+			return 0, fmt.Errorf("falha ao ler %s: %w", table, err)
+		}
+		sep := ",\n"
+		if count == 0 {
+			sep = "\n"
+		}
+		if _, err := io.WriteString(file, sep+row); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("falha ao exportar %s: %w", table, err)
+	}
+	if _, err := io.WriteString(file, "\n]\n"); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// exportTableCSV grava a tabela como CSV com cabeçalho, usando o COPY do PostgreSQL
+func exportTableCSV(ctx context.Context, tx pgx.Tx, archive *zip.Writer, schema, table string) error {
+	file, err := archive.Create(table + ".csv")
+	if err != nil {
+		return fmt.Errorf("falha ao criar %s.csv: %w", table, err)
+	}
+
+	sql := fmt.Sprintf("COPY %s TO STDOUT WITH (FORMAT csv, HEADER true)", pgx.Identifier{schema, table}.Sanitize())
+	if _, err := tx.Conn().PgConn().CopyTo(ctx, file, sql); err != nil {
+		return fmt.Errorf("falha ao exportar %s: %w", table, err)
+	}
+	return nil
+}
+
+// exportXMLColumn grava cada XML preenchido da coluna como um arquivo e retorna a quantidade
+func exportXMLColumn(ctx context.Context, tx pgx.Tx, archive *zip.Writer, schema string, col xmlColumn) (int, error) {
+	query := fmt.Sprintf("SELECT id::text, %s::text FROM %s WHERE %s IS NOT NULL",
+		pgx.Identifier{col.column}.Sanitize(), pgx.Identifier{schema, col.table}.Sanitize(), pgx.Identifier{col.column}.Sanitize())
+	rows, err := tx.Query(ctx, query)
+	if err != nil {
+		return 0, fmt.Errorf("falha ao exportar XMLs de %s: %w", col.table, err)
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var id, content string
+		if err := rows.Scan(&id, &content); err != nil {
+			return 0, fmt.Errorf("falha ao ler XML de %s: %w", col.table, err)
+		}
+		if strings.TrimSpace(content) == "" {
+			continue
+		}
+
+		name := fmt.Sprintf("xml/%s/%s.xml", col.table, id)
+		if col.column != "xml" {
+			name = fmt.Sprintf("xml/%s/%s_%s.xml", col.table, id, col.column)
+		}
+		file, err := archive.Create(name)
+		if err != nil {
+			return 0, fmt.Errorf("falha ao criar %s: %w", name, err)
+		}
+		if _, err := io.WriteString(file, content); err != nil {
+			return 0, fmt.Errorf("falha ao gravar %s: %w", name, err)
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("falha ao exportar XMLs de %s: %w", col.table, err)
+	}
+	return count, nil
+}
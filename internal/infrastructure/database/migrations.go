@@ -2,74 +2,408 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
 	"os"
 	"path/filepath"
+	"sort"
 
-	"github.com/golang-migrate/migrate/v4"
-	_ "github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-// RunTenantMigrations aplica as migrações em um schema específico de tenant
-func RunTenantMigrations(schema string) error {
-	// Primeiro, criar o schema se não existir
-	dbURL := os.Getenv("DATABASE_URL")
-	if dbURL == "" {
-		// Construir a URL do banco a partir das variáveis individuais
-		dbURL = fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-			os.Getenv("DB_USER"),
-			os.Getenv("DB_PASSWORD"),
-			os.Getenv("DB_HOST"),
-			os.Getenv("DB_PORT"),
-			os.Getenv("DB_NAME"),
-			os.Getenv("DB_SSL_MODE"),
-		)
-	}
-
-	// Conectar ao banco para criar o schema
-	db, err := pgxpool.New(context.Background(), dbURL)
+var (
+	ErrMissingDownMigration = errors.New("migração sem script de reversão")
+	ErrDirtySchema          = errors.New("schema marcado como dirty pelo golang-migrate, corrija a versão manualmente")
+)
+
+// Migration é uma migração do diretório, com os scripts de subida e de reversão
+type Migration struct {
+	Version uint
+	Name    string // Nome do arquivo .up.sql, registrado em schema_migrations
+	Up      string
+	Down    string
+}
+
+// MigrationsDir retorna o diretório das migrações do schema public; as dos tenants ficam no
+// subdiretório tenant
+func MigrationsDir() string {
+	return getEnv("MIGRATIONS_DIR", "migrations")
+}
+
+// TenantMigrationsDir retorna o diretório das migrações dos schemas de tenant
+func TenantMigrationsDir() string {
+	return filepath.Join(MigrationsDir(), "tenant")
+}
+
+// LoadMigrations lê os arquivos NNNNNN_nome.up.sql e NNNNNN_nome.down.sql do diretório, em ordem de versão
+func LoadMigrations(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler diretório de migrações: %w", err)
+	}
+
+	byVersion := make(map[uint]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+		parsed, err := source.Parse(entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("nome de migração inválido %s: %w", entry.Name(), err)
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("erro ao ler migração %s: %w", entry.Name(), err)
+		}
+
+		m, ok := byVersion[parsed.Version]
+		if !ok {
+			m = &Migration{Version: parsed.Version}
+			byVersion[parsed.Version] = m
+		}
+		if parsed.Direction == source.Up {
+			m.Name, m.Up = entry.Name(), string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for version, m := range byVersion {
+		if m.Name == "" {
+			return nil, fmt.Errorf("migração %d sem script .up.sql", version)
+		}
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// SchemaStatus é a situação das migrações em um schema
+type SchemaStatus struct {
+	Schema  string
+	Version uint        // Maior versão aplicada; zero quando nenhuma foi aplicada
+	Applied []Migration // Em ordem de versão
+	Pending []Migration // Em ordem de versão
+}
+
+// Migrator aplica as migrações de um diretório em schemas do banco. Cada schema registra as
+// migrações aplicadas na própria tabela schema_migrations, de forma que tenants criados em
+// momentos diferentes evoluem de forma independente até a última versão
+type Migrator struct {
+	db         *pgxpool.Pool
+	migrations []Migration
+}
+
+// NewMigrator cria um Migrator com as migrações do diretório
+func NewMigrator(db *pgxpool.Pool, dir string) (*Migrator, error) {
+	migrations, err := LoadMigrations(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Status retorna as migrações aplicadas e pendentes no schema, sem alterá-lo
+func (m *Migrator) Status(ctx context.Context, schema string) (*SchemaStatus, error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao adquirir conexão do pool: %w", err)
+	}
+	defer conn.Release()
+
+	applied, _, err := m.appliedVersions(ctx, conn, schema)
+	if err != nil {
+		return nil, err
+	}
+	return m.status(schema, applied), nil
+}
+
+// Up aplica até steps migrações pendentes no schema, ou todas quando steps é zero. Com dryRun,
+// apenas retorna as migrações que seriam aplicadas
+func (m *Migrator) Up(ctx context.Context, schema string, steps int, dryRun bool) ([]Migration, error) {
+	return m.run(ctx, schema, dryRun, func(status *SchemaStatus) []Migration {
+		return limit(status.Pending, steps)
+	}, true)
+}
+
+// Down reverte as últimas steps migrações aplicadas no schema, da mais recente para a mais antiga.
+// Com dryRun, apenas retorna as migrações que seriam revertidas
+func (m *Migrator) Down(ctx context.Context, schema string, steps int, dryRun bool) ([]Migration, error) {
+	return m.run(ctx, schema, dryRun, func(status *SchemaStatus) []Migration {
+		reverse := make([]Migration, len(status.Applied))
+		for i, migration := range status.Applied {
+			reverse[len(reverse)-1-i] = migration
+		}
+		return limit(reverse, steps)
+	}, false)
+}
+
+// run seleciona e executa as migrações sob um advisory lock do schema, para que a API e a
+// linha de comando nunca migrem o mesmo schema ao mesmo tempo
+func (m *Migrator) run(ctx context.Context, schema string, dryRun bool, selectMigrations func(*SchemaStatus) []Migration, up bool) ([]Migration, error) {
+	conn, err := m.db.Acquire(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao adquirir conexão do pool: %w", err)
+	}
+	defer conn.Release()
+
+	if !dryRun {
+		if _, err := conn.Exec(ctx, "SELECT pg_advisory_lock(hashtext($1))", "schema_migrations:"+schema); err != nil {
+			return nil, fmt.Errorf("erro ao bloquear schema %s: %w", schema, err)
+		}
+		defer conn.Exec(context.Background(), "SELECT pg_advisory_unlock(hashtext($1))", "schema_migrations:"+schema)
+
+		if err := m.prepare(ctx, conn, schema); err != nil {
+			return nil, err
+		}
+	}
+
+	applied, names, err := m.appliedVersions(ctx, conn, schema)
+	if err != nil {
+		return nil, err
+	}
+	selected := selectMigrations(m.status(schema, applied))
+	if dryRun {
+		return selected, nil
+	}
+
+	done := make([]Migration, 0, len(selected))
+	for _, migration := range selected {
+		if up {
+			err = m.apply(ctx, conn, schema, migration.Up, "INSERT INTO %s.schema_migrations (version) VALUES ($1)", migration.Name)
+		} else if migration.Down == "" {
+			err = fmt.Errorf("%w: %s", ErrMissingDownMigration, migration.Name)
+		} else {
+			err = m.apply(ctx, conn, schema, migration.Down, "DELETE FROM %s.schema_migrations WHERE version = $1", names[migration.Version])
+		}
+		if err != nil {
+			return done, fmt.Errorf("migração %s: %w", migration.Name, err)
+		}
+		done = append(done, migration)
+	}
+	return done, nil
+}
+
+// apply executa o script e atualiza o registro de versões na mesma transação
+func (m *Migrator) apply(ctx context.Context, conn *pgxpool.Conn, schema, script, record, version string) error {
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL search_path TO %s", schema)); err != nil {
+		return fmt.Errorf("erro ao configurar search_path: %w", err)
+	}
+	if _, err := tx.Exec(ctx, script); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf(record, schema), version); err != nil {
+		return fmt.Errorf("erro ao registrar versão: %w", err)
+	}
+	return tx.Commit(ctx)
+}
+
+// prepare cria o schema e a tabela de controle. Schemas migrados pelo golang-migrate (versão
+// única e flag dirty) são convertidos para o registro por arquivo, preservando a tabela antiga
+// como schema_migrations_legacy
+func (m *Migrator) prepare(ctx context.Context, conn *pgxpool.Conn, schema string) error {
+	if _, err := conn.Exec(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema)); err != nil {
+		return fmt.Errorf("erro ao criar schema: %w", err)
+	}
+
+	legacy, err := hasLegacyTable(ctx, conn, schema)
+	if err != nil {
+		return err
+	}
+	var legacyVersions map[uint]bool
+	if legacy {
+		if legacyVersions, err = m.legacyVersions(ctx, conn, schema); err != nil {
+			return err
+		}
+	}
+
+	tx, err := conn.Begin(ctx)
 	if err != nil {
-		return fmt.Errorf("erro ao conectar ao banco: %v", err)
+		return fmt.Errorf("erro ao iniciar transação: %w", err)
 	}
-	defer db.Close()
+	defer tx.Rollback(ctx)
 
-	// Criar o schema e configurar permissões
-	if _, err := db.Exec(context.Background(), fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema)); err != nil {
-		return fmt.Errorf("erro ao criar schema: %v", err)
+	if legacy {
+		if _, err := tx.Exec(ctx, fmt.Sprintf("ALTER TABLE %s.schema_migrations RENAME TO schema_migrations_legacy", schema)); err != nil {
+			return fmt.Errorf("erro ao converter tabela do golang-migrate: %w", err)
+		}
 	}
 
-	// Configurar o search_path para incluir o schema
-	if _, err := db.Exec(context.Background(), fmt.Sprintf("SET search_path TO %s, public", schema)); err != nil {
-		return fmt.Errorf("erro ao configurar search_path: %v", err)
+	_, err = tx.Exec(ctx, fmt.Sprintf(`
+		CREATE TABLE IF NOT EXISTS %s.schema_migrations (
+			version VARCHAR(255) PRIMARY KEY,
+			applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`, schema))
+	if err != nil {
+		return fmt.Errorf("erro ao criar tabela de controle de migrações: %w", err)
 	}
 
-	// Configurar URL para as migrações incluindo o schema
-	dbURL = fmt.Sprintf("%s&search_path=%s,public", dbURL, schema)
+	for _, migration := range m.migrations {
+		if legacyVersions[migration.Version] {
+			if _, err := tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s.schema_migrations (version) VALUES ($1) ON CONFLICT DO NOTHING", schema), migration.Name); err != nil {
+				return fmt.Errorf("erro ao converter tabela do golang-migrate: %w", err)
+			}
+		}
+	}
+	return tx.Commit(ctx)
+}
 
-	// Caminho para as migrações do tenant
-	migrationsPath := filepath.Join("migrations", "tenant")
-	sourceURL := fmt.Sprintf("file://%s", migrationsPath)
+// appliedVersions retorna as versões aplicadas no schema e o nome registrado de cada uma
+func (m *Migrator) appliedVersions(ctx context.Context, conn *pgxpool.Conn, schema string) (map[uint]bool, map[uint]string, error) {
+	applied := make(map[uint]bool)
+	names := make(map[uint]string)
 
-	// Criar instância do migrate
-	m, err := migrate.New(sourceURL, dbURL)
+	legacy, err := hasLegacyTable(ctx, conn, schema)
 	if err != nil {
-		return fmt.Errorf("erro ao criar migrate: %v", err)
+		return nil, nil, err
+	}
+	if legacy {
+		// Só acontece no dry-run, antes da conversão
+		applied, err = m.legacyVersions(ctx, conn, schema)
+		return applied, names, err
 	}
-	defer m.Close()
 
-	// Forçar a versão para 0 para garantir que todas as migrações sejam aplicadas
-	if err := m.Force(0); err != nil {
-		return fmt.Errorf("erro ao forçar versão inicial: %v", err)
+	var exists bool
+	err = conn.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = 'schema_migrations'
+		)`, schema).Scan(&exists)
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao verificar tabela de controle de migrações: %w", err)
+	}
+	if !exists {
+		return applied, names, nil
 	}
 
-	// Aplicar migrações
-	if err := m.Up(); err != nil && err != migrate.ErrNoChange {
-		return fmt.Errorf("erro ao aplicar migrações: %v", err)
+	rows, err := conn.Query(ctx, fmt.Sprintf("SELECT version FROM %s.schema_migrations", schema))
+	if err != nil {
+		return nil, nil, fmt.Errorf("erro ao ler migrações aplicadas: %w", err)
 	}
+	defer rows.Close()
 
-	log.Printf("Migrações aplicadas com sucesso no schema %s", schema)
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, nil, fmt.Errorf("erro ao ler migrações aplicadas: %w", err)
+		}
+		// Registros fora do padrão de nome não correspondem a nenhum arquivo e são ignorados
+		if parsed, err := source.Parse(name); err == nil {
+			applied[parsed.Version] = true
+			names[parsed.Version] = name
+		}
+	}
+	return applied, names, rows.Err()
+}
+
+// legacyVersions converte a versão única do golang-migrate nas versões do diretório até ela
+func (m *Migrator) legacyVersions(ctx context.Context, conn *pgxpool.Conn, schema string) (map[uint]bool, error) {
+	var (
+		version int64
+		dirty   bool
+	)
+	err := conn.QueryRow(ctx, fmt.Sprintf("SELECT version, dirty FROM %s.schema_migrations LIMIT 1", schema)).Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, fmt.Errorf("erro ao ler versão do golang-migrate: %w", err)
+	}
+	if dirty {
+		return nil, fmt.Errorf("%w (versão %d)", ErrDirtySchema, version)
+	}
+
+	applied := make(map[uint]bool)
+	for _, migration := range m.migrations {
+		if int64(migration.Version) <= version {
+			applied[migration.Version] = true
+		}
+	}
+	return applied, nil
+}
+
+// status separa as migrações do diretório em aplicadas e pendentes
+func (m *Migrator) status(schema string, applied map[uint]bool) *SchemaStatus {
+	status := &SchemaStatus{Schema: schema}
+	for _, migration := range m.migrations {
+		if applied[migration.Version] {
+			status.Applied = append(status.Applied, migration)
+			status.Version = migration.Version
+		} else {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status
+}
+
+// hasLegacyTable indica se schema_migrations está no formato do golang-migrate
+func hasLegacyTable(ctx context.Context, conn *pgxpool.Conn, schema string) (bool, error) {
+	var legacy bool
+	err := conn.QueryRow(ctx, `
+		SELECT EXISTS(
+			SELECT 1 FROM information_schema.columns
+			WHERE table_schema = $1 AND table_name = 'schema_migrations' AND column_name = 'dirty'
+		)`, schema).Scan(&legacy)
+	if err != nil {
+		return false, fmt.Errorf("erro ao verificar tabela de controle de migrações: %w", err)
+	}
+	return legacy, nil
+}
+
+// limit retorna os primeiros n itens, ou todos quando n é zero
+func limit(migrations []Migration, n int) []Migration {
+	if n > 0 && n < len(migrations) {
+		return migrations[:n]
+	}
+	return migrations
+}
+
+// RunTenantMigrations leva o schema do tenant até a última migração de migrations/tenant,
+// aplicando apenas as que ainda não constam no schema
+func RunTenantMigrations(ctx context.Context, db *pgxpool.Pool, schema string) error {
+	migrator, err := NewMigrator(db, TenantMigrationsDir())
+	if err != nil {
+		return err
+	}
+	if _, err := migrator.Up(ctx, schema, 0, false); err != nil {
+		return fmt.Errorf("erro ao aplicar migrações no schema %s: %w", schema, err)
+	}
+	return nil
+}
+
+// CreateTenantSchema cria o schema de um novo tenant e aplica todas as migrações de
+// migrations/tenant. As migrações são confirmadas uma a uma; se alguma falhar, o schema fica
+// parcial e deve ser removido com DropTenantSchema
+func CreateTenantSchema(ctx context.Context, db *pgxpool.Pool, schema string) error {
+	if err := ValidateSchema(schema); err != nil {
+		return err
+	}
+
+	if _, err := db.Exec(ctx, fmt.Sprintf("CREATE SCHEMA IF NOT EXISTS %s", schema)); err != nil {
+		return fmt.Errorf("erro ao criar schema: %w", err)
+	}
+	if _, err := db.Exec(ctx, fmt.Sprintf("GRANT ALL ON SCHEMA %s TO CURRENT_USER", schema)); err != nil {
+		return fmt.Errorf("erro ao configurar permissões do schema: %w", err)
+	}
+
+	if err := RunTenantMigrations(ctx, db, schema); err != nil {
+		return fmt.Errorf("erro ao aplicar migrações no schema do tenant: %w", err)
+	}
+	return nil
+}
+
+// DropTenantSchema remove o schema de um tenant com todos os dados
+func DropTenantSchema(ctx context.Context, db *pgxpool.Pool, schema string) error {
+	if err := ValidateSchema(schema); err != nil {
+		return err
+	}
+	if _, err := db.Exec(ctx, fmt.Sprintf("DROP SCHEMA IF EXISTS %s CASCADE", schema)); err != nil {
+		return fmt.Errorf("erro ao remover schema: %w", err)
+	}
 	return nil
 }
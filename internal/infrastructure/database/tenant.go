@@ -0,0 +1,142 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/hugohenrick/erp-supermercado/pkg/tenant"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+var (
+	ErrTenantNotInContext = errors.New("tenant ID não encontrado no contexto")
+	ErrTenantNotFound     = errors.New("tenant não encontrado")
+	ErrInvalidSchema      = errors.New("nome de schema inválido")
+)
+
+// schemaPattern aceita apenas identificadores simples do PostgreSQL, que podem ser interpolados
+// nas queries sem aspas
+var schemaPattern = regexp.MustCompile(`^[a-z_][a-z0-9_]{0,62}$`)
+
+// schemaCacheTTL é o tempo em que o schema de um tenant fica em cache
+const schemaCacheTTL = 5 * time.Minute
+
+// cachedSchema é um schema resolvido e o momento em que deixa de valer
+type cachedSchema struct {
+	schema    string
+	expiresAt time.Time
+}
+
+// schemaCache guarda o schema de cada tenant, evitando consultar public.tenants a cada operação
+var schemaCache sync.Map
+
+// RowQuerier é implementado pelo pool, por conexões e por transações
+type RowQuerier interface {
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// TenantScope identifica o tenant e o schema de uma operação
+type TenantScope struct {
+	TenantID string
+	Schema   string
+}
+
+// Table retorna o nome da tabela qualificado pelo schema do tenant
+func (s TenantScope) Table(name string) string {
+	return s.Schema + "." + name
+}
+
+// ValidateSchema garante que o schema é um identificador seguro para ser usado nas queries
+func ValidateSchema(schema string) error {
+	if !schemaPattern.MatchString(schema) {
+		return fmt.Errorf("%w: %q", ErrInvalidSchema, schema)
+	}
+	return nil
+}
+
+// TenantIDFromContext obtém o tenant ID do contexto do Gin ou do context.Context padrão
+func TenantIDFromContext(ctx context.Context) string {
+	if tenantID := tenant.GetTenantID(ctx); tenantID != "" {
+		return tenantID
+	}
+	if tenantID := tenant.GetTenantIDFromContext(ctx); tenantID != "" {
+		return tenantID
+	}
+	if tenantID, ok := ctx.Value("tenant_id").(string); ok {
+		return tenantID
+	}
+	return ""
+}
+
+// ResolveTenantSchema retorna o schema validado do tenant, consultando public.tenants apenas
+// quando ele não está em cache
+func ResolveTenantSchema(ctx context.Context, db RowQuerier, tenantID string) (string, error) {
+	if tenantID == "" {
+		return "", ErrTenantNotInContext
+	}
+	if cached, ok := schemaCache.Load(tenantID); ok {
+		entry := cached.(cachedSchema)
+		if time.Now().Before(entry.expiresAt) {
+			return entry.schema, nil
+		}
+	}
+
+	var schema string
+	err := db.QueryRow(ctx, "SELECT schema FROM public.tenants WHERE id = $1", tenantID).Scan(&schema)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", ErrTenantNotFound
+		}
+		return "", fmt.Errorf("falha ao obter schema do tenant: %w", err)
+	}
+	if err := ValidateSchema(schema); err != nil {
+		return "", err
+	}
+
+	schemaCache.Store(tenantID, cachedSchema{schema: schema, expiresAt: time.Now().Add(schemaCacheTTL)})
+	return schema, nil
+}
+
+// InvalidateTenantSchema remove o schema do tenant do cache, como quando o tenant é excluído
+func InvalidateTenantSchema(tenantID string) {
+	schemaCache.Delete(tenantID)
+}
+
+// TenantTx executa fn em uma transação no schema do tenant do contexto
+func TenantTx(ctx context.Context, db *pgxpool.Pool, fn func(tx pgx.Tx, scope TenantScope) error) error {
+	return TenantTxFor(ctx, db, TenantIDFromContext(ctx), fn)
+}
+
+// TenantTxFor executa fn em uma transação no schema do tenant informado. O search_path é
+// definido com SET LOCAL e vale apenas até o fim da transação, de forma que a conexão volta ao
+// pool sem o schema do tenant
+func TenantTxFor(ctx context.Context, db *pgxpool.Pool, tenantID string, fn func(tx pgx.Tx, scope TenantScope) error) error {
+	schema, err := ResolveTenantSchema(ctx, db, tenantID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("falha ao iniciar transação: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, fmt.Sprintf("SET LOCAL search_path TO %s, public", schema)); err != nil {
+		return fmt.Errorf("falha ao configurar search_path: %w", err)
+	}
+
+	if err := fn(tx, TenantScope{TenantID: tenantID, Schema: schema}); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("falha ao confirmar transação: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,3 @@
+-- Remover índice global de logins
+DROP INDEX IF EXISTS idx_user_logins_email;
+DROP TABLE IF EXISTS user_logins;
@@ -0,0 +1,12 @@
+-- Índice global de logins: em quais tenants cada email está cadastrado, para que o login sem
+-- tenant_id não precise consultar o schema de cada tenant
+CREATE TABLE IF NOT EXISTS user_logins (
+    tenant_id UUID NOT NULL REFERENCES tenants(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_logins_email ON user_logins(lower(email));
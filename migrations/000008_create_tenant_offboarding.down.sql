@@ -0,0 +1,6 @@
+-- Remover auditoria e exclusão lógica de tenants
+DROP INDEX IF EXISTS idx_tenant_audit_log_tenant;
+DROP TABLE IF EXISTS tenant_audit_log;
+DROP INDEX IF EXISTS idx_tenants_purge_after;
+ALTER TABLE tenants DROP COLUMN IF EXISTS purge_after;
+ALTER TABLE tenants DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,22 @@
+-- Ciclo de desligamento de tenants: exclusão lógica com prazo de carência, antes de o schema
+-- ser removido pelo comando purge-tenants
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP;
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS purge_after TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_tenants_purge_after ON tenants(purge_after) WHERE deleted_at IS NOT NULL;
+
+-- Trilha de auditoria do desligamento. Não referencia tenants para continuar existindo depois
+-- que o tenant é expurgado; nome, documento e schema ficam registrados em cada evento
+CREATE TABLE IF NOT EXISTS tenant_audit_log (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    tenant_name VARCHAR(255) NOT NULL,
+    tenant_document VARCHAR(20) NOT NULL,
+    schema VARCHAR(63) NOT NULL,
+    action VARCHAR(30) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    details JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_audit_log_tenant ON tenant_audit_log(tenant_id, created_at);
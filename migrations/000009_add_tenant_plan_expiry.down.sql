@@ -0,0 +1,2 @@
+-- Remover vigência do plano
+ALTER TABLE tenants DROP COLUMN IF EXISTS plan_expires_at;
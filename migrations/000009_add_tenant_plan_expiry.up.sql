@@ -0,0 +1,3 @@
+-- Vigência do plano contratado: após a expiração e o prazo de carência o tenant fica somente
+-- leitura. Nulo indica plano sem data de expiração
+ALTER TABLE tenants ADD COLUMN IF NOT EXISTS plan_expires_at TIMESTAMP;
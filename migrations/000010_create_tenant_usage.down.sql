@@ -0,0 +1,3 @@
+-- Remover a medição de consumo dos tenants
+DROP INDEX IF EXISTS idx_tenant_usage_period;
+DROP TABLE IF EXISTS tenant_usage;
@@ -0,0 +1,18 @@
+-- Consumo mensal dos tenants para a cobrança. Documentos fiscais e tokens do assistente são
+-- acumulados no mês; usuários ativos, filiais, terminais e armazenamento guardam o maior valor
+-- medido. Não referencia tenants para preservar o histórico de cobrança após o expurgo
+CREATE TABLE IF NOT EXISTS tenant_usage (
+    tenant_id UUID NOT NULL,
+    period DATE NOT NULL,                               -- Primeiro dia do mês
+    fiscal_documents BIGINT NOT NULL DEFAULT 0,
+    active_users INTEGER NOT NULL DEFAULT 0,
+    branches INTEGER NOT NULL DEFAULT 0,
+    pdv_terminals INTEGER NOT NULL DEFAULT 0,
+    assistant_input_tokens BIGINT NOT NULL DEFAULT 0,
+    assistant_output_tokens BIGINT NOT NULL DEFAULT 0,
+    storage_bytes BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (tenant_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_tenant_usage_period ON tenant_usage(period);
@@ -0,0 +1,3 @@
+-- Remover os operadores da plataforma
+DROP INDEX IF EXISTS idx_platform_operators_email;
+DROP TABLE IF EXISTS platform_operators;
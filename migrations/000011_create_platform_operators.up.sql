@@ -0,0 +1,14 @@
+-- Operadores da plataforma: a equipe que administra os tenants, os planos e a cobrança. Ficam no
+-- schema public e não pertencem a nenhum tenant, ao contrário dos administradores dos tenants
+CREATE TABLE IF NOT EXISTS platform_operators (
+    id UUID PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    last_login_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_platform_operators_email ON platform_operators(lower(email));
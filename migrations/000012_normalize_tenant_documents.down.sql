@@ -0,0 +1 @@
+-- A máscara original dos documentos não é guardada; os documentos normalizados continuam válidos
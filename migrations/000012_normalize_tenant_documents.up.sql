@@ -0,0 +1,27 @@
+-- O CNPJ dos tenants passa a ser gravado sem máscara e em maiúsculas, como já fazem os cadastros
+-- novos; a busca por documento compara a coluna diretamente e usa o índice único. Tenants que ficariam
+-- com o mesmo documento (um com máscara e outro sem) violariam o índice; a migração falha listando os
+-- conflitos para que sejam resolvidos antes, já que cada um tem o próprio schema
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(format('%s: %s', normalized, records), '; ' ORDER BY normalized)
+    INTO conflicts
+    FROM (
+        SELECT regexp_replace(upper(document), '[^0-9A-Z]', '', 'g') AS normalized,
+               string_agg(format('%s %s (%s)', id, name, document), ', ' ORDER BY created_at, id) AS records
+        FROM tenants
+        GROUP BY 1
+        HAVING count(*) > 1
+    ) duplicated;
+
+    IF conflicts IS NOT NULL THEN
+        RAISE EXCEPTION 'tenants com o mesmo CNPJ sem a máscara; resolva os cadastros e execute a migração novamente: %', conflicts;
+    END IF;
+END
+$$;
+
+UPDATE tenants
+SET document = regexp_replace(upper(document), '[^0-9A-Z]', '', 'g')
+WHERE document ~ '[^0-9A-Z]';
@@ -0,0 +1,21 @@
+-- Remover colunas de custo das movimentações
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS total_cost;
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS unit_cost;
+
+-- Remover itens de perda
+DROP INDEX IF EXISTS idx_loss_items_product_id;
+DROP INDEX IF EXISTS idx_loss_items_loss_id;
+DROP TABLE IF EXISTS loss_items;
+
+-- Remover registros de perda
+DROP INDEX IF EXISTS idx_losses_occurred_at;
+DROP INDEX IF EXISTS idx_losses_status;
+DROP INDEX IF EXISTS idx_losses_reason_id;
+DROP INDEX IF EXISTS idx_losses_branch_id;
+DROP INDEX IF EXISTS idx_losses_tenant_id;
+DROP TABLE IF EXISTS losses;
+
+-- Remover motivos de perda
+DROP INDEX IF EXISTS idx_loss_reasons_category;
+DROP INDEX IF EXISTS idx_loss_reasons_tenant_id;
+DROP TABLE IF EXISTS loss_reasons;
@@ -0,0 +1,59 @@
+-- Motivos de perda/quebra configuráveis por tenant
+CREATE TABLE IF NOT EXISTS loss_reasons (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    code VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NOT NULL,
+    category VARCHAR(30) NOT NULL,                -- damage, spoilage, expiry, theft, internal_consumption, other
+    requires_photo BOOLEAN NOT NULL DEFAULT false,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_loss_reasons_tenant_id ON loss_reasons(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_loss_reasons_category ON loss_reasons(category);
+
+-- Registros de perda por filial
+CREATE TABLE IF NOT EXISTS losses (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID NOT NULL REFERENCES branches(id),
+    reason_id UUID NOT NULL REFERENCES loss_reasons(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, approved, rejected
+    notes TEXT,
+    photos JSONB NOT NULL DEFAULT '[]',
+    total_cost DECIMAL(15,2) NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMP NOT NULL,
+    created_by UUID REFERENCES users(id),
+    reviewed_by UUID REFERENCES users(id),
+    reviewed_at TIMESTAMP,
+    rejection_reason TEXT,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_losses_tenant_id ON losses(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_losses_branch_id ON losses(branch_id);
+CREATE INDEX IF NOT EXISTS idx_losses_reason_id ON losses(reason_id);
+CREATE INDEX IF NOT EXISTS idx_losses_status ON losses(status);
+CREATE INDEX IF NOT EXISTS idx_losses_occurred_at ON losses(occurred_at);
+
+-- Itens de cada registro de perda
+CREATE TABLE IF NOT EXISTS loss_items (
+    id UUID PRIMARY KEY,
+    loss_id UUID NOT NULL REFERENCES losses(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    quantity DECIMAL(15,3) NOT NULL,
+    unit_cost DECIMAL(15,4) NOT NULL DEFAULT 0,
+    total_cost DECIMAL(15,2) NOT NULL DEFAULT 0,
+    notes TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_loss_items_loss_id ON loss_items(loss_id);
+CREATE INDEX IF NOT EXISTS idx_loss_items_product_id ON loss_items(product_id);
+
+-- Custo das movimentações de estoque (usado na valorização das perdas)
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS unit_cost DECIMAL(15,4);
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS total_cost DECIMAL(15,2);
@@ -0,0 +1,17 @@
+-- Remover baixas dos títulos a pagar
+DROP INDEX IF EXISTS idx_payable_payments_paid_at;
+DROP INDEX IF EXISTS idx_payable_payments_payable_id;
+DROP TABLE IF EXISTS payable_payments;
+
+-- Remover títulos a pagar
+DROP INDEX IF EXISTS idx_payables_status;
+DROP INDEX IF EXISTS idx_payables_due_date;
+DROP INDEX IF EXISTS idx_payables_supplier_id;
+DROP INDEX IF EXISTS idx_payables_branch_id;
+DROP INDEX IF EXISTS idx_payables_tenant_id;
+DROP TABLE IF EXISTS payables;
+
+-- Remover fornecedores
+DROP INDEX IF EXISTS idx_suppliers_name;
+DROP INDEX IF EXISTS idx_suppliers_tenant_id;
+DROP TABLE IF EXISTS suppliers;
@@ -0,0 +1,80 @@
+-- Fornecedores
+CREATE TABLE IF NOT EXISTS suppliers (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    trade_name VARCHAR(100),
+    document VARCHAR(20) NOT NULL,
+    state_document VARCHAR(20),
+    email VARCHAR(100),
+    phone VARCHAR(20),
+    payment_term VARCHAR(50) NOT NULL DEFAULT '',   -- Prazos em dias separados por "/", ex.: 30/60/90
+    street VARCHAR(150),
+    number VARCHAR(20),
+    complement VARCHAR(100),
+    district VARCHAR(100),
+    city VARCHAR(100),
+    state VARCHAR(2),
+    zip_code VARCHAR(10),
+    city_code VARCHAR(10),
+    state_code VARCHAR(2),
+    notes TEXT,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, document)
+);
+
+CREATE INDEX IF NOT EXISTS idx_suppliers_tenant_id ON suppliers(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_suppliers_name ON suppliers(name);
+
+-- Títulos a pagar
+CREATE TABLE IF NOT EXISTS payables (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID NOT NULL REFERENCES branches(id),
+    supplier_id UUID NOT NULL REFERENCES suppliers(id),
+    purchase_id UUID,                               -- Compra recebida que originou o título
+    document_number VARCHAR(50),
+    description VARCHAR(255),
+    installment INTEGER NOT NULL DEFAULT 1,
+    installments INTEGER NOT NULL DEFAULT 1,
+    issue_date DATE NOT NULL,
+    due_date DATE NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    interest DECIMAL(15,2) NOT NULL DEFAULT 0,
+    fine DECIMAL(15,2) NOT NULL DEFAULT 0,
+    discount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',     -- open, partial, paid, cancelled
+    cancel_reason TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(purchase_id, installment)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payables_tenant_id ON payables(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_payables_branch_id ON payables(branch_id);
+CREATE INDEX IF NOT EXISTS idx_payables_supplier_id ON payables(supplier_id);
+CREATE INDEX IF NOT EXISTS idx_payables_due_date ON payables(due_date);
+CREATE INDEX IF NOT EXISTS idx_payables_status ON payables(status);
+
+-- Baixas (pagamentos) dos títulos a pagar
+CREATE TABLE IF NOT EXISTS payable_payments (
+    id UUID PRIMARY KEY,
+    payable_id UUID NOT NULL REFERENCES payables(id) ON DELETE CASCADE,
+    paid_at DATE NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,                  -- Valor do principal abatido
+    interest DECIMAL(15,2) NOT NULL DEFAULT 0,
+    fine DECIMAL(15,2) NOT NULL DEFAULT 0,
+    discount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    total DECIMAL(15,2) NOT NULL,                   -- amount + interest + fine - discount
+    method VARCHAR(30),
+    notes TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_payable_payments_payable_id ON payable_payments(payable_id);
+CREATE INDEX IF NOT EXISTS idx_payable_payments_paid_at ON payable_payments(paid_at);
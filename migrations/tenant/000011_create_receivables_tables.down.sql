@@ -0,0 +1,12 @@
+-- Remover baixas dos títulos a receber
+DROP INDEX IF EXISTS idx_receivable_payments_paid_at;
+DROP INDEX IF EXISTS idx_receivable_payments_receivable_id;
+DROP TABLE IF EXISTS receivable_payments;
+
+-- Remover títulos a receber
+DROP INDEX IF EXISTS idx_receivables_status;
+DROP INDEX IF EXISTS idx_receivables_due_date;
+DROP INDEX IF EXISTS idx_receivables_customer_id;
+DROP INDEX IF EXISTS idx_receivables_branch_id;
+DROP INDEX IF EXISTS idx_receivables_tenant_id;
+DROP TABLE IF EXISTS receivables;
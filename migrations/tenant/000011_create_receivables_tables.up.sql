@@ -0,0 +1,50 @@
+-- Títulos a receber (vendas a prazo: fiado/crediário)
+CREATE TABLE IF NOT EXISTS receivables (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID NOT NULL REFERENCES branches(id),
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    sale_id UUID,                                   -- Venda que originou o título
+    document_number VARCHAR(50),
+    description VARCHAR(255),
+    installment INTEGER NOT NULL DEFAULT 1,
+    installments INTEGER NOT NULL DEFAULT 1,
+    issue_date DATE NOT NULL,
+    due_date DATE NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    received_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    interest DECIMAL(15,2) NOT NULL DEFAULT 0,
+    fine DECIMAL(15,2) NOT NULL DEFAULT 0,
+    discount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'open',     -- open, partial, paid, cancelled
+    cancel_reason TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(sale_id, installment)
+);
+
+CREATE INDEX IF NOT EXISTS idx_receivables_tenant_id ON receivables(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_receivables_branch_id ON receivables(branch_id);
+CREATE INDEX IF NOT EXISTS idx_receivables_customer_id ON receivables(customer_id);
+CREATE INDEX IF NOT EXISTS idx_receivables_due_date ON receivables(due_date);
+CREATE INDEX IF NOT EXISTS idx_receivables_status ON receivables(status);
+
+-- Baixas (recebimentos) dos títulos a receber
+CREATE TABLE IF NOT EXISTS receivable_payments (
+    id UUID PRIMARY KEY,
+    receivable_id UUID NOT NULL REFERENCES receivables(id) ON DELETE CASCADE,
+    paid_at DATE NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,                  -- Valor do principal abatido
+    interest DECIMAL(15,2) NOT NULL DEFAULT 0,
+    fine DECIMAL(15,2) NOT NULL DEFAULT 0,
+    discount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    total DECIMAL(15,2) NOT NULL,                   -- amount + interest + fine - discount
+    method VARCHAR(30),
+    notes TEXT,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_receivable_payments_receivable_id ON receivable_payments(receivable_id);
+CREATE INDEX IF NOT EXISTS idx_receivable_payments_paid_at ON receivable_payments(paid_at);
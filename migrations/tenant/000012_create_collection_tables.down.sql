@@ -0,0 +1,14 @@
+-- Remover boletos
+DROP INDEX IF EXISTS idx_boletos_active_receivable;
+DROP INDEX IF EXISTS idx_boletos_status;
+DROP INDEX IF EXISTS idx_boletos_receivable_id;
+DROP INDEX IF EXISTS idx_boletos_tenant_id;
+DROP TABLE IF EXISTS boletos;
+
+-- Remover arquivos de remessa
+DROP INDEX IF EXISTS idx_collection_remittances_account_id;
+DROP TABLE IF EXISTS collection_remittances;
+
+-- Remover contas de cobrança
+DROP INDEX IF EXISTS idx_collection_accounts_tenant_id;
+DROP TABLE IF EXISTS collection_accounts;
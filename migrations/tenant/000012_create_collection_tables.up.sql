@@ -0,0 +1,78 @@
+-- Contas de cobrança registrada (convênios bancários)
+CREATE TABLE IF NOT EXISTS collection_accounts (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    bank_code VARCHAR(3) NOT NULL,
+    agency VARCHAR(10) NOT NULL,
+    agency_digit VARCHAR(2),
+    account VARCHAR(20) NOT NULL,
+    account_digit VARCHAR(2),
+    wallet VARCHAR(5) NOT NULL,                      -- Carteira
+    agreement VARCHAR(20),                           -- Convênio / código do beneficiário
+    beneficiary_name VARCHAR(100) NOT NULL,
+    beneficiary_document VARCHAR(20) NOT NULL,
+    layout VARCHAR(3) NOT NULL DEFAULT '240',        -- 240 ou 400
+    interest_rate DECIMAL(7,4) NOT NULL DEFAULT 0,   -- Juros de mora ao mês (%)
+    fine_percent DECIMAL(7,4) NOT NULL DEFAULT 0,    -- Multa por atraso (%)
+    instructions TEXT,
+    next_our_number BIGINT NOT NULL DEFAULT 1,
+    next_remittance INTEGER NOT NULL DEFAULT 1,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_accounts_tenant_id ON collection_accounts(tenant_id);
+
+-- Arquivos de remessa enviados ao banco
+CREATE TABLE IF NOT EXISTS collection_remittances (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    account_id UUID NOT NULL REFERENCES collection_accounts(id),
+    layout VARCHAR(3) NOT NULL,
+    sequence INTEGER NOT NULL,
+    file_name VARCHAR(100) NOT NULL,
+    content BYTEA NOT NULL,
+    titles INTEGER NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE(account_id, sequence)
+);
+
+CREATE INDEX IF NOT EXISTS idx_collection_remittances_account_id ON collection_remittances(account_id);
+
+-- Boletos emitidos para títulos a receber
+CREATE TABLE IF NOT EXISTS boletos (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    account_id UUID NOT NULL REFERENCES collection_accounts(id),
+    receivable_id UUID NOT NULL REFERENCES receivables(id),
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    our_number VARCHAR(20) NOT NULL,                 -- Sequencial sem DV
+    our_number_digit VARCHAR(2),
+    printed_number VARCHAR(30) NOT NULL,
+    document_number VARCHAR(50),
+    barcode VARCHAR(44) NOT NULL,
+    digitable_line VARCHAR(60) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    issue_date DATE NOT NULL,
+    due_date DATE NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'issued',    -- issued, remitted, registered, rejected, paid, cancelled
+    remittance_id UUID REFERENCES collection_remittances(id),
+    occurrence VARCHAR(2),
+    paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    paid_at DATE,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(account_id, our_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_boletos_tenant_id ON boletos(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_boletos_receivable_id ON boletos(receivable_id);
+CREATE INDEX IF NOT EXISTS idx_boletos_status ON boletos(status);
+
+-- Um título só pode ter um boleto ativo
+CREATE UNIQUE INDEX IF NOT EXISTS idx_boletos_active_receivable
+    ON boletos(receivable_id) WHERE status NOT IN ('rejected', 'cancelled');
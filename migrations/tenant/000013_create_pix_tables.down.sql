@@ -0,0 +1,15 @@
+-- Remover notificações Pix
+DROP INDEX IF EXISTS idx_pix_notifications_status;
+DROP INDEX IF EXISTS idx_pix_notifications_tenant_id;
+DROP TABLE IF EXISTS pix_notifications;
+
+-- Remover cobranças Pix
+DROP INDEX IF EXISTS idx_pix_charges_status;
+DROP INDEX IF EXISTS idx_pix_charges_receivable_id;
+DROP INDEX IF EXISTS idx_pix_charges_sale_id;
+DROP INDEX IF EXISTS idx_pix_charges_branch_id;
+DROP INDEX IF EXISTS idx_pix_charges_tenant_id;
+DROP TABLE IF EXISTS pix_charges;
+
+-- Remover configuração de Pix
+DROP TABLE IF EXISTS pix_settings;
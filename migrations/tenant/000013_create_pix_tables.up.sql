@@ -0,0 +1,63 @@
+-- Configuração de recebimento via Pix do tenant
+CREATE TABLE IF NOT EXISTS pix_settings (
+    tenant_id UUID PRIMARY KEY,
+    pix_key VARCHAR(77) NOT NULL,                    -- Chave Pix do recebedor
+    merchant_name VARCHAR(25) NOT NULL,
+    merchant_city VARCHAR(15) NOT NULL,
+    postal_code VARCHAR(8),
+    provider VARCHAR(20),                            -- fake, api ou vazio (somente QR Code estático)
+    base_url VARCHAR(255),
+    client_id VARCHAR(255),
+    client_secret VARCHAR(255),
+    webhook_secret VARCHAR(255),
+    expires_in INTEGER NOT NULL DEFAULT 3600,        -- Validade das cobranças dinâmicas, em segundos
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Cobranças Pix (QR Code estático ou dinâmico)
+CREATE TABLE IF NOT EXISTS pix_charges (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID NOT NULL REFERENCES branches(id),
+    kind VARCHAR(10) NOT NULL,                       -- static, dynamic
+    txid VARCHAR(35) NOT NULL UNIQUE,
+    sale_id UUID,
+    receivable_id UUID REFERENCES receivables(id),
+    customer_id UUID REFERENCES customers(id),
+    amount DECIMAL(15,2) NOT NULL,
+    description VARCHAR(140),
+    br_code TEXT NOT NULL,                           -- Pix Copia e Cola
+    location VARCHAR(255),
+    provider VARCHAR(20),
+    status VARCHAR(20) NOT NULL DEFAULT 'active',    -- active, paid, cancelled
+    end_to_end_id VARCHAR(32),
+    paid_amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    paid_at TIMESTAMP,
+    expires_at TIMESTAMP,
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pix_charges_tenant_id ON pix_charges(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_pix_charges_branch_id ON pix_charges(branch_id);
+CREATE INDEX IF NOT EXISTS idx_pix_charges_sale_id ON pix_charges(sale_id);
+CREATE INDEX IF NOT EXISTS idx_pix_charges_receivable_id ON pix_charges(receivable_id);
+CREATE INDEX IF NOT EXISTS idx_pix_charges_status ON pix_charges(status);
+
+-- Notificações recebidas do PSP; o endToEndId garante o processamento único de cada Pix
+CREATE TABLE IF NOT EXISTS pix_notifications (
+    end_to_end_id VARCHAR(32) PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    txid VARCHAR(35),
+    charge_id UUID REFERENCES pix_charges(id),
+    amount DECIMAL(15,2) NOT NULL,
+    paid_at TIMESTAMP NOT NULL,
+    payer_info TEXT,
+    status VARCHAR(20) NOT NULL,                     -- settled, unmatched, rejected
+    error TEXT,
+    received_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_pix_notifications_tenant_id ON pix_notifications(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_pix_notifications_status ON pix_notifications(status);
@@ -0,0 +1,15 @@
+-- Remover lançamentos dos extratos
+DROP INDEX IF EXISTS idx_bank_transactions_payment_id;
+DROP INDEX IF EXISTS idx_bank_transactions_status;
+DROP INDEX IF EXISTS idx_bank_transactions_posted_at;
+DROP INDEX IF EXISTS idx_bank_transactions_import_id;
+DROP INDEX IF EXISTS idx_bank_transactions_tenant_id;
+DROP TABLE IF EXISTS bank_transactions;
+
+-- Remover importações de extratos
+DROP INDEX IF EXISTS idx_bank_statement_imports_account_id;
+DROP TABLE IF EXISTS bank_statement_imports;
+
+-- Remover contas bancárias
+DROP INDEX IF EXISTS idx_bank_accounts_tenant_id;
+DROP TABLE IF EXISTS bank_accounts;
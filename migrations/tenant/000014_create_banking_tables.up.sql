@@ -0,0 +1,68 @@
+-- Contas bancárias do tenant
+CREATE TABLE IF NOT EXISTS bank_accounts (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID REFERENCES branches(id),          -- Vazio para conta da empresa
+    name VARCHAR(100) NOT NULL,
+    bank_code VARCHAR(3),
+    agency VARCHAR(10),
+    account_number VARCHAR(20),
+    type VARCHAR(20) NOT NULL DEFAULT 'checking',    -- checking, savings, investment
+    opening_balance DECIMAL(15,2) NOT NULL DEFAULT 0,
+    opening_date DATE NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_accounts_tenant_id ON bank_accounts(tenant_id);
+
+-- Importações de extratos OFX
+CREATE TABLE IF NOT EXISTS bank_statement_imports (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    account_id UUID NOT NULL REFERENCES bank_accounts(id),
+    file_name VARCHAR(255),
+    start_date DATE,
+    end_date DATE,
+    balance DECIMAL(15,2) NOT NULL DEFAULT 0,        -- Saldo final informado pelo banco
+    transactions INTEGER NOT NULL DEFAULT 0,
+    duplicates INTEGER NOT NULL DEFAULT 0,
+    matched INTEGER NOT NULL DEFAULT 0,
+    imported_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_statement_imports_account_id ON bank_statement_imports(account_id);
+
+-- Lançamentos dos extratos e sua conciliação com os títulos
+CREATE TABLE IF NOT EXISTS bank_transactions (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    account_id UUID NOT NULL REFERENCES bank_accounts(id),
+    import_id UUID NOT NULL REFERENCES bank_statement_imports(id),
+    fitid VARCHAR(255) NOT NULL,                     -- Identificador do lançamento no banco
+    type VARCHAR(20),
+    posted_at DATE NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,                   -- Positivo para créditos e negativo para débitos
+    document VARCHAR(50),
+    memo VARCHAR(255),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',   -- pending, matched, ignored
+    match_kind VARCHAR(20),                          -- receivable, payable
+    title_id UUID,
+    payment_id UUID,                                 -- Baixa em receivable_payments ou payable_payments
+    matched_auto BOOLEAN NOT NULL DEFAULT false,
+    matched_by UUID REFERENCES users(id),
+    matched_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(account_id, fitid)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_tenant_id ON bank_transactions(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_import_id ON bank_transactions(import_id);
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_posted_at ON bank_transactions(posted_at);
+CREATE INDEX IF NOT EXISTS idx_bank_transactions_status ON bank_transactions(status);
+
+-- Cada baixa só pode ser conciliada com um lançamento do extrato
+CREATE UNIQUE INDEX IF NOT EXISTS idx_bank_transactions_payment_id ON bank_transactions(payment_id) WHERE payment_id IS NOT NULL;
@@ -0,0 +1,25 @@
+-- Remover registros dos arquivos das adquirentes
+DROP INDEX IF EXISTS idx_card_settlement_records_status;
+DROP INDEX IF EXISTS idx_card_settlement_records_file_id;
+DROP TABLE IF EXISTS card_settlement_records;
+
+-- Remover arquivos de liquidação
+DROP INDEX IF EXISTS idx_card_settlement_files_tenant_id;
+DROP TABLE IF EXISTS card_settlement_files;
+
+-- Remover agenda de recebimentos
+DROP INDEX IF EXISTS idx_card_settlements_status;
+DROP INDEX IF EXISTS idx_card_settlements_expected_date;
+DROP INDEX IF EXISTS idx_card_settlements_tenant_id;
+DROP TABLE IF EXISTS card_settlements;
+
+-- Remover vendas no cartão
+DROP INDEX IF EXISTS idx_card_sales_status;
+DROP INDEX IF EXISTS idx_card_sales_sold_at;
+DROP INDEX IF EXISTS idx_card_sales_branch_id;
+DROP INDEX IF EXISTS idx_card_sales_tenant_id;
+DROP TABLE IF EXISTS card_sales;
+
+-- Remover taxas das adquirentes
+DROP INDEX IF EXISTS idx_card_fee_tables_tenant_id;
+DROP TABLE IF EXISTS card_fee_tables;
@@ -0,0 +1,120 @@
+-- Taxas contratadas com as adquirentes por bandeira, produto e faixa de parcelas
+CREATE TABLE IF NOT EXISTS card_fee_tables (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    acquirer VARCHAR(30) NOT NULL,
+    brand VARCHAR(30) NOT NULL,
+    product VARCHAR(10) NOT NULL,                    -- credit, debit
+    installments_from INTEGER NOT NULL DEFAULT 1,
+    installments_to INTEGER NOT NULL DEFAULT 1,
+    fee_percent DECIMAL(7,4) NOT NULL,
+    settlement_days INTEGER NOT NULL,                -- Prazo de pagamento; no crédito, por parcela
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, acquirer, brand, product, installments_from)
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_fee_tables_tenant_id ON card_fee_tables(tenant_id);
+
+-- Vendas pagas com cartão (TEF/POS)
+CREATE TABLE IF NOT EXISTS card_sales (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID NOT NULL REFERENCES branches(id),
+    sale_id UUID,
+    payment_id UUID UNIQUE,                          -- Pagamento da venda no PDV
+    acquirer VARCHAR(30) NOT NULL,
+    brand VARCHAR(30) NOT NULL,
+    product VARCHAR(10) NOT NULL,
+    installments INTEGER NOT NULL DEFAULT 1,
+    amount DECIMAL(15,2) NOT NULL,
+    fee_percent DECIMAL(7,4) NOT NULL,
+    fee_amount DECIMAL(15,2) NOT NULL,
+    net_amount DECIMAL(15,2) NOT NULL,
+    nsu VARCHAR(20) NOT NULL,
+    authorization_code VARCHAR(20),
+    card_last_digits VARCHAR(4),
+    sold_at TIMESTAMP NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',   -- pending, settled, divergent
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, acquirer, nsu)
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_sales_tenant_id ON card_sales(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_card_sales_branch_id ON card_sales(branch_id);
+CREATE INDEX IF NOT EXISTS idx_card_sales_sold_at ON card_sales(sold_at);
+CREATE INDEX IF NOT EXISTS idx_card_sales_status ON card_sales(status);
+
+-- Agenda de recebimentos prevista para cada parcela
+CREATE TABLE IF NOT EXISTS card_settlements (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    card_sale_id UUID NOT NULL REFERENCES card_sales(id) ON DELETE CASCADE,
+    installment INTEGER NOT NULL,
+    expected_date DATE NOT NULL,
+    gross_amount DECIMAL(15,2) NOT NULL,
+    fee_amount DECIMAL(15,2) NOT NULL,
+    net_amount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'expected',  -- expected, settled, divergent
+    paid_date DATE,
+    paid_gross DECIMAL(15,2) NOT NULL DEFAULT 0,
+    paid_fee DECIMAL(15,2) NOT NULL DEFAULT 0,
+    paid_net DECIMAL(15,2) NOT NULL DEFAULT 0,
+    difference DECIMAL(15,2) NOT NULL DEFAULT 0,     -- Líquido previsto menos o pago
+    record_id UUID,
+    message TEXT,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(card_sale_id, installment)
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_settlements_tenant_id ON card_settlements(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_card_settlements_expected_date ON card_settlements(expected_date);
+CREATE INDEX IF NOT EXISTS idx_card_settlements_status ON card_settlements(status);
+
+-- Arquivos de liquidação importados das adquirentes
+CREATE TABLE IF NOT EXISTS card_settlement_files (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    acquirer VARCHAR(30) NOT NULL,
+    format VARCHAR(10) NOT NULL,                     -- csv, edi
+    file_name VARCHAR(255),
+    records INTEGER NOT NULL DEFAULT 0,
+    matched INTEGER NOT NULL DEFAULT 0,
+    divergent INTEGER NOT NULL DEFAULT 0,
+    unmatched INTEGER NOT NULL DEFAULT 0,
+    duplicates INTEGER NOT NULL DEFAULT 0,
+    imported_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_settlement_files_tenant_id ON card_settlement_files(tenant_id);
+
+-- Pagamentos informados pelas adquirentes; cada parcela de uma transação é conciliada uma única vez
+CREATE TABLE IF NOT EXISTS card_settlement_records (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    file_id UUID NOT NULL REFERENCES card_settlement_files(id),
+    acquirer VARCHAR(30) NOT NULL,
+    line INTEGER NOT NULL,
+    sale_date DATE,
+    payment_date DATE,
+    nsu VARCHAR(20) NOT NULL,
+    authorization_code VARCHAR(20),
+    brand VARCHAR(30),
+    installment INTEGER NOT NULL DEFAULT 1,
+    installments INTEGER NOT NULL DEFAULT 1,
+    gross_amount DECIMAL(15,2) NOT NULL,
+    fee_amount DECIMAL(15,2) NOT NULL,
+    net_amount DECIMAL(15,2) NOT NULL,
+    settlement_id UUID REFERENCES card_settlements(id),
+    status VARCHAR(20) NOT NULL,                     -- matched, divergent, unmatched
+    message TEXT,
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, acquirer, nsu, installment)
+);
+
+CREATE INDEX IF NOT EXISTS idx_card_settlement_records_file_id ON card_settlement_records(file_id);
+CREATE INDEX IF NOT EXISTS idx_card_settlement_records_status ON card_settlement_records(status);
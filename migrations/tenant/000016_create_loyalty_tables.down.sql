@@ -0,0 +1,17 @@
+-- Remover extrato de pontos
+DROP INDEX IF EXISTS idx_loyalty_entries_earn_sale;
+DROP INDEX IF EXISTS idx_loyalty_entries_available;
+DROP INDEX IF EXISTS idx_loyalty_entries_customer;
+DROP TABLE IF EXISTS loyalty_entries;
+
+-- Remover catálogo de prêmios
+DROP INDEX IF EXISTS idx_loyalty_rewards_tenant_id;
+DROP TABLE IF EXISTS loyalty_rewards;
+
+-- Remover campanhas e regras de pontuação
+DROP INDEX IF EXISTS idx_loyalty_campaigns_period;
+DROP TABLE IF EXISTS loyalty_campaigns;
+DROP TABLE IF EXISTS loyalty_rules;
+
+-- Remover configuração do programa
+DROP TABLE IF EXISTS loyalty_programs;
@@ -0,0 +1,79 @@
+-- Configuração do programa de fidelidade (uma por tenant)
+CREATE TABLE IF NOT EXISTS loyalty_programs (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL UNIQUE,
+    name VARCHAR(100) NOT NULL,
+    points_per_real DECIMAL(10,4) NOT NULL,          -- Pontos por R$ 1,00 nas categorias sem regra própria
+    point_value DECIMAL(10,4) NOT NULL,              -- Valor em R$ de cada ponto no resgate como pagamento
+    expiration_days INTEGER NOT NULL,
+    min_redeem_points INTEGER NOT NULL DEFAULT 0,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+-- Pontuação por categoria de produtos
+CREATE TABLE IF NOT EXISTS loyalty_rules (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    category_id UUID NOT NULL REFERENCES product_categories(id),
+    points_per_real DECIMAL(10,4) NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, category_id)
+);
+
+-- Campanhas que multiplicam a pontuação em um período
+CREATE TABLE IF NOT EXISTS loyalty_campaigns (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    category_id UUID REFERENCES product_categories(id), -- NULL para todas as categorias
+    multiplier DECIMAL(6,2) NOT NULL,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_campaigns_period ON loyalty_campaigns(tenant_id, starts_at, ends_at);
+
+-- Produtos que podem ser trocados por pontos
+CREATE TABLE IF NOT EXISTS loyalty_rewards (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    product_id UUID NOT NULL REFERENCES products(id),
+    name VARCHAR(100) NOT NULL,
+    points INTEGER NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_rewards_tenant_id ON loyalty_rewards(tenant_id);
+
+-- Extrato de pontos dos clientes
+CREATE TABLE IF NOT EXISTS loyalty_entries (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    branch_id UUID REFERENCES branches(id),
+    type VARCHAR(10) NOT NULL,                       -- earn, redeem, expire
+    points INTEGER NOT NULL,                         -- Negativo nos resgates e vencimentos
+    remaining INTEGER NOT NULL DEFAULT 0,            -- Saldo ainda disponível dos ganhos
+    amount DECIMAL(15,2) NOT NULL DEFAULT 0,
+    sale_id UUID,
+    reward_id UUID REFERENCES loyalty_rewards(id),
+    expires_at TIMESTAMP,
+    description VARCHAR(255),
+    created_by UUID REFERENCES users(id),
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_customer ON loyalty_entries(customer_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_loyalty_entries_available ON loyalty_entries(customer_id, expires_at) WHERE remaining > 0;
+
+-- Cada venda pontua uma única vez
+CREATE UNIQUE INDEX IF NOT EXISTS idx_loyalty_entries_earn_sale ON loyalty_entries(tenant_id, sale_id) WHERE type = 'earn';
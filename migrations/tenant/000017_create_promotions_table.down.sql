@@ -0,0 +1,4 @@
+-- Remover promoções
+DROP INDEX IF EXISTS idx_promotions_period;
+DROP INDEX IF EXISTS idx_promotions_tenant_id;
+DROP TABLE IF EXISTS promotions;
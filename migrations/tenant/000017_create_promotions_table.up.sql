@@ -0,0 +1,25 @@
+-- Promoções aplicadas no PDV: leve N pague M, desconto progressivo, combos e preço de clube
+CREATE TABLE IF NOT EXISTS promotions (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    type VARCHAR(20) NOT NULL,                       -- take_pay, tiered, combo, price
+    branch_ids JSONB NOT NULL DEFAULT '[]',          -- Filiais participantes; vazio para todas
+    product_ids JSONB NOT NULL DEFAULT '[]',
+    take_quantity INTEGER NOT NULL DEFAULT 0,
+    pay_quantity INTEGER NOT NULL DEFAULT 0,
+    tiers JSONB NOT NULL DEFAULT '[]',
+    combo_items JSONB NOT NULL DEFAULT '[]',
+    combo_price DECIMAL(15,2) NOT NULL DEFAULT 0,
+    price DECIMAL(15,2) NOT NULL DEFAULT 0,
+    members_only BOOLEAN NOT NULL DEFAULT false,     -- Exige cliente identificado no caixa
+    priority INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP NOT NULL,
+    ends_at TIMESTAMP NOT NULL,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_promotions_tenant_id ON promotions(tenant_id);
+CREATE INDEX IF NOT EXISTS idx_promotions_period ON promotions(tenant_id, starts_at, ends_at) WHERE active = true;
@@ -0,0 +1,6 @@
+-- Remover tabelas de preços
+DROP INDEX IF EXISTS idx_price_table_items_product_id;
+DROP TABLE IF EXISTS price_table_items;
+
+DROP INDEX IF EXISTS idx_price_tables_tenant_id;
+DROP TABLE IF EXISTS price_tables;
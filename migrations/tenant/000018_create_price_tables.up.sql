@@ -0,0 +1,26 @@
+-- Tabelas de preços diferenciadas por cliente (atacado, revenda)
+CREATE TABLE IF NOT EXISTS price_tables (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    percentage DECIMAL(7,2) NOT NULL DEFAULT 0,      -- Acréscimo ou desconto sobre o preço de venda
+    branch_ids JSONB NOT NULL DEFAULT '[]',          -- Filiais onde vale; vazio para todas
+    customer_types JSONB NOT NULL DEFAULT '[]',      -- Tabela padrão para esses tipos de cliente
+    valid_from TIMESTAMP NOT NULL,
+    valid_until TIMESTAMP,
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_tables_tenant_id ON price_tables(tenant_id);
+
+-- Preços fixos por produto, que prevalecem sobre o percentual da tabela
+CREATE TABLE IF NOT EXISTS price_table_items (
+    price_table_id UUID NOT NULL REFERENCES price_tables(id) ON DELETE CASCADE,
+    product_id UUID NOT NULL REFERENCES products(id),
+    price DECIMAL(15,2) NOT NULL,
+    PRIMARY KEY (price_table_id, product_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_price_table_items_product_id ON price_table_items(product_id);
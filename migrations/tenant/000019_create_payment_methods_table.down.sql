@@ -0,0 +1,3 @@
+-- Remover formas de pagamento
+DROP INDEX IF EXISTS idx_payment_methods_tenant_id;
+DROP TABLE IF EXISTS payment_methods;
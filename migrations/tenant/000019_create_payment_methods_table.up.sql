@@ -0,0 +1,17 @@
+-- Formas de pagamento aceitas no PDV, com o código tPag usado na NFC-e
+CREATE TABLE IF NOT EXISTS payment_methods (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(60) NOT NULL,
+    kind VARCHAR(20) NOT NULL,                       -- cash, debit, credit, pix, voucher, on_account, meal_voucher
+    tpag VARCHAR(2) NOT NULL,                        -- Meio de pagamento da NFC-e
+    max_installments INTEGER NOT NULL DEFAULT 1,
+    change_allowed BOOLEAN NOT NULL DEFAULT false,   -- Permite troco
+    requires_tef BOOLEAN NOT NULL DEFAULT false,     -- Exige transação TEF/POS
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_payment_methods_tenant_id ON payment_methods(tenant_id);
@@ -0,0 +1,17 @@
+-- Remover comissões
+DROP INDEX IF EXISTS idx_commissions_salesman_reversed_at;
+DROP INDEX IF EXISTS idx_commissions_salesman_sale_date;
+DROP TABLE IF EXISTS commissions;
+
+DROP INDEX IF EXISTS idx_commission_rules_tenant_id;
+DROP TABLE IF EXISTS commission_rules;
+
+-- Clientes voltam a referenciar usuários
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_salesman_id_fkey;
+UPDATE customers SET salesman_id = NULL WHERE salesman_id NOT IN (SELECT id FROM users);
+ALTER TABLE customers ADD CONSTRAINT customers_salesman_id_fkey FOREIGN KEY (salesman_id) REFERENCES users(id);
+
+-- Remover vendedores
+DROP INDEX IF EXISTS idx_salesmen_user_id;
+DROP INDEX IF EXISTS idx_salesmen_tenant_id;
+DROP TABLE IF EXISTS salesmen;
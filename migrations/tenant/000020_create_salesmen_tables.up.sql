@@ -0,0 +1,63 @@
+-- Vendedores, opcionalmente vinculados a um usuário do sistema
+CREATE TABLE IF NOT EXISTS salesmen (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    user_id UUID REFERENCES users(id),
+    name VARCHAR(255) NOT NULL,
+    document VARCHAR(20),
+    email VARCHAR(255),
+    phone VARCHAR(20),
+    commission_percent DECIMAL(5,2) NOT NULL DEFAULT 0, -- Percentual padrão sem regra específica
+    active BOOLEAN NOT NULL DEFAULT true,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_salesmen_tenant_id ON salesmen(tenant_id);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_salesmen_user_id ON salesmen(tenant_id, user_id) WHERE user_id IS NOT NULL;
+
+-- Clientes já vinculados a usuários passam a apontar para vendedores com o mesmo ID
+INSERT INTO salesmen (id, tenant_id, user_id, name, email, commission_percent, active, created_at, updated_at)
+SELECT DISTINCT u.id, u.tenant_id, u.id, u.name, u.email, 0, true, NOW(), NOW()
+FROM users u
+JOIN customers c ON c.salesman_id = u.id
+ON CONFLICT (id) DO NOTHING;
+
+ALTER TABLE customers DROP CONSTRAINT IF EXISTS customers_salesman_id_fkey;
+ALTER TABLE customers ADD CONSTRAINT customers_salesman_id_fkey FOREIGN KEY (salesman_id) REFERENCES salesmen(id);
+
+-- Regras de comissão por categoria de produto e/ou forma de pagamento
+CREATE TABLE IF NOT EXISTS commission_rules (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    salesman_id UUID REFERENCES salesmen(id) ON DELETE CASCADE, -- Nulo para todos os vendedores
+    category_id UUID REFERENCES product_categories(id),
+    payment_method_id UUID REFERENCES payment_methods(id),
+    percentage DECIMAL(5,2) NOT NULL,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_commission_rules_tenant_id ON commission_rules(tenant_id);
+
+-- Comissões lançadas nas vendas finalizadas e estornadas no cancelamento
+CREATE TABLE IF NOT EXISTS commissions (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    salesman_id UUID NOT NULL REFERENCES salesmen(id),
+    sale_id UUID NOT NULL,
+    branch_id UUID,
+    customer_id UUID,
+    sale_date TIMESTAMP NOT NULL,
+    base_amount DECIMAL(15,2) NOT NULL,
+    amount DECIMAL(15,2) NOT NULL,
+    status VARCHAR(20) NOT NULL,                     -- accrued, reversed
+    lines JSONB NOT NULL DEFAULT '[]',               -- Detalhe por item e forma de pagamento
+    reversed_at TIMESTAMP,
+    reverse_reason VARCHAR(255),
+    created_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, sale_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_commissions_salesman_sale_date ON commissions(salesman_id, sale_date);
+CREATE INDEX IF NOT EXISTS idx_commissions_salesman_reversed_at ON commissions(salesman_id, reversed_at) WHERE reversed_at IS NOT NULL;
@@ -0,0 +1,3 @@
+-- Remover códigos IBGE do endereço da filial
+ALTER TABLE branches DROP COLUMN IF EXISTS state_code;
+ALTER TABLE branches DROP COLUMN IF EXISTS city_code;
@@ -0,0 +1,3 @@
+-- Códigos IBGE do endereço da filial, exigidos no emitente da NF-e
+ALTER TABLE branches ADD COLUMN IF NOT EXISTS city_code VARCHAR(10);
+ALTER TABLE branches ADD COLUMN IF NOT EXISTS state_code VARCHAR(2);
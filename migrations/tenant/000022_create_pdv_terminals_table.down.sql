@@ -0,0 +1,3 @@
+-- Remover terminais de PDV
+DROP INDEX IF EXISTS idx_pdv_terminals_tenant_id;
+DROP TABLE IF EXISTS pdv_terminals;
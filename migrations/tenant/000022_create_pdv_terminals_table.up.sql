@@ -0,0 +1,14 @@
+-- Terminais de PDV (caixas) de cada filial. A quantidade de terminais ativos é limitada pelo plano
+CREATE TABLE IF NOT EXISTS pdv_terminals (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID NOT NULL REFERENCES branches(id) ON DELETE CASCADE,
+    code VARCHAR(10) NOT NULL,                       -- Número do caixa na filial
+    name VARCHAR(60) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',    -- active, inactive
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(branch_id, code)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pdv_terminals_tenant_id ON pdv_terminals(tenant_id);
@@ -0,0 +1,2 @@
+-- Remover contadores de documentos fiscais
+DROP TABLE IF EXISTS fiscal_document_counters;
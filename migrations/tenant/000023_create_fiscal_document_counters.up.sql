@@ -0,0 +1,9 @@
+-- Documentos fiscais numerados por mês, filial e modelo, usados no limite mensal do plano
+CREATE TABLE IF NOT EXISTS fiscal_document_counters (
+    period DATE NOT NULL,                            -- Primeiro dia do mês
+    branch_id UUID NOT NULL,
+    model VARCHAR(5) NOT NULL,                       -- nfe, nfce
+    quantity INTEGER NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (period, branch_id, model)
+);
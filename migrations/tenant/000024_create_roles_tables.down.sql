@@ -0,0 +1,5 @@
+-- Remover perfis de acesso e atribuições
+DROP INDEX IF EXISTS idx_user_role_assignments_role_id;
+DROP INDEX IF EXISTS idx_user_role_assignments_unique;
+DROP TABLE IF EXISTS user_role_assignments;
+DROP TABLE IF EXISTS roles;
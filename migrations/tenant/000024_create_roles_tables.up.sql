@@ -0,0 +1,26 @@
+-- Perfis de acesso definidos pelo tenant, compostos de permissões. Os perfis padrão (admin,
+-- manager e staff) são fixos no código e não ficam nesta tabela
+CREATE TABLE IF NOT EXISTS roles (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    name VARCHAR(60) NOT NULL,
+    description VARCHAR(255),
+    permissions TEXT[] NOT NULL DEFAULT '{}',          -- Ex.: customer.create, fiscal.cancel
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    UNIQUE(tenant_id, name)
+);
+
+-- Perfis atribuídos a cada usuário, em uma filial ou, com branch_id nulo, em todas as filiais
+CREATE TABLE IF NOT EXISTS user_role_assignments (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role_id UUID NOT NULL REFERENCES roles(id) ON DELETE CASCADE,
+    branch_id UUID REFERENCES branches(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_role_assignments_unique
+    ON user_role_assignments(user_id, role_id, COALESCE(branch_id, '00000000-0000-0000-0000-000000000000'::uuid));
+CREATE INDEX IF NOT EXISTS idx_user_role_assignments_role_id ON user_role_assignments(role_id);
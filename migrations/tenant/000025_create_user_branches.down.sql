@@ -0,0 +1,3 @@
+-- Remover os vínculos de usuários com filiais
+DROP INDEX IF EXISTS idx_user_branches_branch_id;
+DROP TABLE IF EXISTS user_branches;
@@ -0,0 +1,19 @@
+-- Filiais em que cada usuário trabalha, com o papel que exerce em cada uma. Um gerente regional
+-- pode ser gerente em várias lojas e funcionário em outras
+CREATE TABLE IF NOT EXISTS user_branches (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    branch_id UUID NOT NULL REFERENCES branches(id) ON DELETE CASCADE,
+    tenant_id UUID NOT NULL,
+    role VARCHAR(20) NOT NULL,                         -- admin, manager ou staff nesta filial
+    created_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, branch_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_branches_branch_id ON user_branches(branch_id);
+
+-- A filial principal de cada usuário passa a ser também um vínculo, com o papel do usuário
+INSERT INTO user_branches (user_id, branch_id, tenant_id, role, created_at)
+SELECT id, branch_id, tenant_id, role, NOW()
+FROM users
+WHERE branch_id IS NOT NULL
+ON CONFLICT DO NOTHING;
@@ -0,0 +1,2 @@
+-- Remover o índice de busca de usuários por email
+DROP INDEX IF EXISTS idx_users_tenant_lower_email;
@@ -0,0 +1,2 @@
+-- O login compara o email sem diferenciar maiúsculas; o índice acompanha a expressão usada na busca
+CREATE INDEX IF NOT EXISTS idx_users_tenant_lower_email ON users(tenant_id, lower(email));
@@ -0,0 +1,44 @@
+-- CPF/CNPJ de clientes e fornecedores passam a ser gravados sem máscara e em maiúsculas, como já
+-- fazem os cadastros novos; a busca por documento compara a coluna diretamente e usa os índices.
+-- Cadastros que ficariam com o mesmo documento (um com máscara e outro sem) violariam
+-- UNIQUE(tenant_id, document); como vendas, títulos e compras apontam para eles, a migração não os
+-- unifica sozinha e falha listando os conflitos para que sejam corrigidos antes
+DO $$
+DECLARE
+    conflicts TEXT;
+BEGIN
+    SELECT string_agg(format('clientes %s %s: %s', tenant_id, normalized, records), '; ' ORDER BY tenant_id, normalized)
+    INTO conflicts
+    FROM (
+        SELECT tenant_id,
+               regexp_replace(upper(document), '[^0-9A-Z]', '', 'g') AS normalized,
+               string_agg(format('%s (%s)', id, document), ', ' ORDER BY created_at, id) AS records
+        FROM customers
+        GROUP BY 1, 2
+        HAVING count(*) > 1
+    ) duplicated;
+
+    SELECT concat_ws('; ', conflicts, string_agg(format('fornecedores %s %s: %s', tenant_id, normalized, records), '; ' ORDER BY tenant_id, normalized))
+    INTO conflicts
+    FROM (
+        SELECT tenant_id,
+               regexp_replace(upper(document), '[^0-9A-Z]', '', 'g') AS normalized,
+               string_agg(format('%s (%s)', id, document), ', ' ORDER BY created_at, id) AS records
+        FROM suppliers
+        GROUP BY 1, 2
+        HAVING count(*) > 1
+    ) duplicated;
+
+    IF conflicts <> '' THEN
+        RAISE EXCEPTION 'cadastros com o mesmo CPF/CNPJ sem a máscara; unifique ou corrija os documentos e execute a migração novamente: %', conflicts;
+    END IF;
+END
+$$;
+
+UPDATE customers
+SET document = regexp_replace(upper(document), '[^0-9A-Z]', '', 'g')
+WHERE document ~ '[^0-9A-Z]';
+
+UPDATE suppliers
+SET document = regexp_replace(upper(document), '[^0-9A-Z]', '', 'g')
+WHERE document ~ '[^0-9A-Z]';
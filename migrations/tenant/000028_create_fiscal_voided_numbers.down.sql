@@ -0,0 +1,3 @@
+-- Remover faixas de numeração inutilizadas
+DROP INDEX IF EXISTS idx_fiscal_voided_numbers_branch;
+DROP TABLE IF EXISTS fiscal_voided_numbers;
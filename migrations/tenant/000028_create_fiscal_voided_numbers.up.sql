@@ -0,0 +1,16 @@
+-- Faixas de numeração de NF-e e NFC-e inutilizadas por filial, série e modelo
+CREATE TABLE IF NOT EXISTS fiscal_voided_numbers (
+    id UUID PRIMARY KEY,
+    tenant_id UUID NOT NULL,
+    branch_id UUID NOT NULL REFERENCES branches(id) ON DELETE CASCADE,
+    model VARCHAR(5) NOT NULL,                       -- nfe, nfce
+    series VARCHAR(3) NOT NULL,
+    start_number INTEGER NOT NULL,
+    end_number INTEGER NOT NULL,
+    justification VARCHAR(255) NOT NULL,
+    created_by UUID,
+    created_at TIMESTAMP NOT NULL,
+    CHECK (start_number >= 1 AND end_number >= start_number)
+);
+
+CREATE INDEX IF NOT EXISTS idx_fiscal_voided_numbers_branch ON fiscal_voided_numbers(branch_id, model, series);
@@ -0,0 +1,3 @@
+-- Remover o valor vendido das movimentações de estoque
+DROP INDEX IF EXISTS idx_inventory_movements_branch_type_created_at;
+ALTER TABLE inventory_movements DROP COLUMN IF EXISTS total_value;
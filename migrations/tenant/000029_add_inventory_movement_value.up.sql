@@ -0,0 +1,5 @@
+-- Valor vendido das movimentações de estoque, gravado pelo PDV nas saídas por venda (type = 'sale').
+-- O relatório de perdas soma esse valor para o percentual sobre vendas da filial no período
+ALTER TABLE inventory_movements ADD COLUMN IF NOT EXISTS total_value DECIMAL(15,2);
+
+CREATE INDEX IF NOT EXISTS idx_inventory_movements_branch_type_created_at ON inventory_movements(branch_id, type, created_at);
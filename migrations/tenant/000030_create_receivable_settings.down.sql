@@ -0,0 +1,2 @@
+-- Remover a configuração de contas a receber
+DROP TABLE IF EXISTS receivable_settings;
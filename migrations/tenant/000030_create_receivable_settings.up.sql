@@ -0,0 +1,7 @@
+-- Configuração de contas a receber do tenant. block_after_days é a tolerância, em dias de atraso, lida
+-- tanto pela venda a prazo quanto pelo comando agendado block-overdue
+CREATE TABLE IF NOT EXISTS receivable_settings (
+    tenant_id UUID PRIMARY KEY,
+    block_after_days INTEGER NOT NULL DEFAULT 30 CHECK (block_after_days >= 0),
+    updated_at TIMESTAMP NOT NULL
+);
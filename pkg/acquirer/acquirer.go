@@ -0,0 +1,170 @@
+package acquirer
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidFile    = errors.New("arquivo de liquidação inválido")
+	ErrEmptyFile      = errors.New("arquivo de liquidação sem registros")
+	ErrUnknownFormat  = errors.New("formato de arquivo não suportado, use csv ou edi")
+	ErrMissingColumn  = errors.New("coluna obrigatória ausente no arquivo")
+	ErrInvalidAmount  = errors.New("valor inválido no arquivo de liquidação")
+	ErrInvalidDate    = errors.New("data inválida no arquivo de liquidação")
+	ErrInvalidInteger = errors.New("número inválido no arquivo de liquidação")
+)
+
+// Format identifica o formato do arquivo de liquidação
+type Format string
+
+const (
+	FormatCSV Format = "csv" // Planilha exportada do portal da adquirente
+	FormatEDI Format = "edi" // Arquivo posicional de extrato eletrônico
+)
+
+// Record representa uma parcela paga (ou a pagar) pela adquirente
+type Record struct {
+	Line              int       `json:"line"`
+	SaleDate          time.Time `json:"sale_date"`
+	PaymentDate       time.Time `json:"payment_date"`
+	NSU               string    `json:"nsu"`
+	AuthorizationCode string    `json:"authorization_code"`
+	Brand             string    `json:"brand"`
+	Installment       int       `json:"installment"`
+	Installments      int       `json:"installments"`
+	Gross             float64   `json:"gross"` // Valor bruto da parcela
+	Fee               float64   `json:"fee"`   // Taxa descontada pela adquirente
+	Net               float64   `json:"net"`   // Valor líquido creditado
+}
+
+// Parse interpreta o arquivo de liquidação no formato informado
+func Parse(format Format, data []byte) ([]Record, error) {
+	var records []Record
+	var err error
+	switch format {
+	case FormatCSV:
+		records, err = parseCSV(data)
+	case FormatEDI:
+		records, err = parseEDI(data)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownFormat, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, ErrEmptyFile
+	}
+
+	for i := range records {
+		complete(&records[i])
+	}
+	return records, nil
+}
+
+// complete deduz o valor ausente entre bruto, taxa e líquido e normaliza a parcela
+func complete(r *Record) {
+	r.Gross = roundMoney(math.Abs(r.Gross))
+	r.Fee = roundMoney(math.Abs(r.Fee))
+	r.Net = roundMoney(math.Abs(r.Net))
+	switch {
+	case r.Net == 0 && r.Gross > 0:
+		r.Net = roundMoney(r.Gross - r.Fee)
+	case r.Fee == 0 && r.Gross > r.Net && r.Net > 0:
+		r.Fee = roundMoney(r.Gross - r.Net)
+	case r.Gross == 0:
+		r.Gross = roundMoney(r.Net + r.Fee)
+	}
+	if r.Installment <= 0 {
+		r.Installment = 1
+	}
+	if r.Installments < r.Installment {
+		r.Installments = r.Installment
+	}
+	r.NSU = strings.TrimLeft(strings.TrimSpace(r.NSU), "0")
+	r.AuthorizationCode = strings.ToUpper(strings.TrimSpace(r.AuthorizationCode))
+	r.Brand = NormalizeBrand(r.Brand)
+}
+
+// NormalizeBrand padroniza o nome da bandeira: minúsculo, sem acentos e espaços
+func NormalizeBrand(brand string) string {
+	brand = strings.ToLower(removeAccents(strings.TrimSpace(brand)))
+	brand = strings.NewReplacer(" ", "", "-", "", "_", "").Replace(brand)
+	switch brand {
+	case "master", "mastercard", "maestro":
+		return "mastercard"
+	case "visa", "visaelectron", "electron":
+		return "visa"
+	case "americanexpress", "amex":
+		return "amex"
+	}
+	return brand
+}
+
+// parseDate aceita DD/MM/AAAA, AAAA-MM-DD e DDMMAAAA
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if idx := strings.IndexAny(value, " T"); idx > 0 {
+		value = value[:idx]
+	}
+	for _, layout := range []string{"02/01/2006", "2006-01-02", "02012006", "02-01-2006"} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, value)
+}
+
+// parseAmount aceita valores no formato brasileiro (1.234,56) ou com ponto decimal
+func parseAmount(value string) (float64, error) {
+	value = strings.TrimSpace(strings.NewReplacer("R$", "", " ", "").Replace(value))
+	if value == "" {
+		return 0, nil
+	}
+	if strings.Contains(value, ",") {
+		value = strings.ReplaceAll(value, ".", "")
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, value)
+	}
+	return amount, nil
+}
+
+// parseInt lê um número inteiro, aceitando campo vazio como zero
+func parseInt(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidInteger, value)
+	}
+	return n, nil
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "é", "e", "ê", "e", "í", "i", "ó", "o", "ô", "o", "õ", "o",
+	"ú", "u", "ü", "u", "ç", "c", "Á", "A", "À", "A", "Â", "A", "Ã", "A", "É", "E", "Ê", "E", "Í", "I",
+	"Ó", "O", "Ô", "O", "Õ", "O", "Ú", "U", "Ü", "U", "Ç", "C",
+)
+
+// removeAccents remove os acentos usados nos cabeçalhos e bandeiras
+func removeAccents(value string) string {
+	return accents.Replace(value)
+}
+
+// roundMoney arredonda um valor monetário para duas casas decimais
+func roundMoney(value float64) float64 {
+	return math.Round(value*100) / 100
+}
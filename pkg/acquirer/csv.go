@@ -0,0 +1,140 @@
+package acquirer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Nomes aceitos para cada coluna, já sem acentos e com espaços trocados por "_"
+var csvColumns = map[string][]string{
+	"sale_date":          {"data_venda", "data_da_venda", "sale_date", "data_transacao"},
+	"payment_date":       {"data_pagamento", "data_de_pagamento", "data_credito", "payment_date", "previsao_pagamento"},
+	"nsu":                {"nsu", "nsu_doc", "doc", "nsu/doc"},
+	"authorization_code": {"autorizacao", "codigo_autorizacao", "cod_autorizacao", "authorization", "authorization_code"},
+	"brand":              {"bandeira", "brand"},
+	"installment":        {"parcela", "installment"},
+	"installments":       {"total_parcelas", "qtd_parcelas", "parcelas", "installments"},
+	"gross":              {"valor_bruto", "bruto", "gross", "valor_da_parcela"},
+	"fee":                {"taxa", "valor_taxa", "desconto", "fee", "tarifa"},
+	"net":                {"valor_liquido", "liquido", "net"},
+}
+
+// parseCSV interpreta planilhas com cabeçalho, separadas por ponto e vírgula ou vírgula
+func parseCSV(data []byte) ([]Record, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	firstLine := data
+	if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+		firstLine = data[:idx]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+	}
+
+	index := make(map[string]int)
+	for i, name := range header {
+		name = strings.ToLower(removeAccents(strings.TrimSpace(name)))
+		name = strings.NewReplacer(" ", "_", ".", "", "-", "_").Replace(name)
+		for column, aliases := range csvColumns {
+			for _, alias := range aliases {
+				if name == alias {
+					if _, ok := index[column]; !ok {
+						index[column] = i
+					}
+				}
+			}
+		}
+	}
+	for _, column := range []string{"nsu", "gross"} {
+		if _, ok := index[column]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrMissingColumn, column)
+		}
+	}
+
+	records := make([]Record, 0)
+	for line := 2; ; line++ {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: linha %d: %v", ErrInvalidFile, line, err)
+		}
+
+		get := func(column string) string {
+			i, ok := index[column]
+			if !ok || i >= len(fields) {
+				return ""
+			}
+			return strings.TrimSpace(fields[i])
+		}
+		if get("nsu") == "" && get("gross") == "" {
+			continue
+		}
+
+		r, err := csvRecord(line, get)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", line, err)
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// csvRecord converte os campos de uma linha da planilha
+func csvRecord(line int, get func(string) string) (Record, error) {
+	r := Record{
+		Line:              line,
+		NSU:               get("nsu"),
+		AuthorizationCode: get("authorization_code"),
+		Brand:             get("brand"),
+	}
+
+	var err error
+	if r.SaleDate, err = parseDate(get("sale_date")); err != nil {
+		return r, err
+	}
+	if r.PaymentDate, err = parseDate(get("payment_date")); err != nil {
+		return r, err
+	}
+	if r.Gross, err = parseAmount(get("gross")); err != nil {
+		return r, err
+	}
+	if r.Fee, err = parseAmount(get("fee")); err != nil {
+		return r, err
+	}
+	if r.Net, err = parseAmount(get("net")); err != nil {
+		return r, err
+	}
+
+	// A parcela pode vir como "2/3", dispensando a coluna de total de parcelas
+	installment := get("installment")
+	if parts := strings.SplitN(installment, "/", 2); len(parts) == 2 {
+		installment = parts[0]
+		if r.Installments, err = parseInt(parts[1]); err != nil {
+			return r, err
+		}
+	}
+	if r.Installment, err = parseInt(installment); err != nil {
+		return r, err
+	}
+	if total := get("installments"); total != "" {
+		if r.Installments, err = parseInt(total); err != nil {
+			return r, err
+		}
+	}
+
+	return r, nil
+}
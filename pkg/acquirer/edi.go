@@ -0,0 +1,93 @@
+package acquirer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ediDetail é o tipo do registro de detalhe, o único lido do arquivo EDI
+const ediDetail = '1'
+
+// ediDetailSize é o tamanho mínimo do registro de detalhe
+const ediDetailSize = 94
+
+// parseEDI interpreta o extrato eletrônico posicional de pagamentos.
+// Detalhe (posições base 1): 1 tipo "1"; 2-9 data de pagamento (DDMMAAAA); 10-17 data da venda;
+// 18-29 NSU; 30-41 autorização; 42-51 bandeira; 52-53 parcela; 54-55 total de parcelas;
+// 56-68 valor bruto; 69-81 taxa; 82-94 valor líquido (valores em centavos).
+// Registros de cabeçalho ("0"), trailer ("9") e ajustes de outros tipos são ignorados
+func parseEDI(data []byte) ([]Record, error) {
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+
+	records := make([]Record, 0)
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		if line[0] != ediDetail {
+			continue
+		}
+
+		if len(line) < ediDetailSize {
+			return nil, fmt.Errorf("%w: linha %d com %d posições", ErrInvalidFile, i+1, len(line))
+		}
+
+		r, err := ediRecord(i+1, line)
+		if err != nil {
+			return nil, fmt.Errorf("linha %d: %w", i+1, err)
+		}
+		records = append(records, r)
+	}
+
+	return records, nil
+}
+
+// ediRecord converte um registro de detalhe
+func ediRecord(line int, value string) (Record, error) {
+	field := func(start, end int) string {
+		return strings.TrimSpace(value[start-1 : end])
+	}
+
+	r := Record{
+		Line:              line,
+		NSU:               field(18, 29),
+		AuthorizationCode: field(30, 41),
+		Brand:             field(42, 51),
+	}
+
+	var err error
+	if r.PaymentDate, err = parseDate(field(2, 9)); err != nil {
+		return r, err
+	}
+	if r.SaleDate, err = parseDate(field(10, 17)); err != nil {
+		return r, err
+	}
+	if r.Installment, err = parseInt(field(52, 53)); err != nil {
+		return r, err
+	}
+	if r.Installments, err = parseInt(field(54, 55)); err != nil {
+		return r, err
+	}
+	if r.Gross, err = parseCents(field(56, 68)); err != nil {
+		return r, err
+	}
+	if r.Fee, err = parseCents(field(69, 81)); err != nil {
+		return r, err
+	}
+	if r.Net, err = parseCents(field(82, 94)); err != nil {
+		return r, err
+	}
+
+	return r, nil
+}
+
+// parseCents lê um valor numérico em centavos
+func parseCents(value string) (float64, error) {
+	cents, err := parseInt(value)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, value)
+	}
+	return float64(cents) / 100, nil
+}
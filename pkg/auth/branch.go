@@ -0,0 +1,122 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
+	"github.com/hugohenrick/erp-supermercado/pkg/branch"
+)
+
+// Chaves do contexto do Gin usadas na validação da filial
+const (
+	branchAccessKey = "branch_access_resolver"
+	activeBranchKey = "active_branch_id"
+	allBranchesKey  = "all_branches"
+)
+
+// BranchAccessResolver busca o papel do usuário em uma filial, retornando user.ErrNoBranchAccess
+// quando ele não tem acesso a ela
+type BranchAccessResolver interface {
+	FindBranchRole(ctx context.Context, userID, branchID string) (user.Role, error)
+}
+
+// BranchAccessMiddleware disponibiliza o resolver para que JWTAuthMiddleware valide o cabeçalho
+// branch-id contra as filiais do usuário autenticado
+func BranchAccessMiddleware(resolver BranchAccessResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(branchAccessKey, resolver)
+		c.Next()
+	}
+}
+
+// applyActiveBranch define a filial ativa da requisição. Sem cabeçalho branch-id, ou com a mesma
+// filial do token, vale a filial do token. Outra filial só é aceita se o usuário tiver acesso a
+// ela, e nesse caso o papel passa a ser o que ele exerce na filial. Retorna false depois de
+// abortar a requisição
+func applyActiveBranch(c *gin.Context, claims *JWTClaims) bool {
+	c.Set(allBranchesKey, claims.AllBranches)
+	requested := c.GetHeader("branch-id")
+	if requested == "" || requested == claims.BranchID {
+		setActiveBranch(c, claims.BranchID)
+		return true
+	}
+
+	value, _ := c.Get(branchAccessKey)
+	resolver, ok := value.(BranchAccessResolver)
+	if !ok {
+		abortBranchDenied(c)
+		return false
+	}
+
+	role, err := resolver.FindBranchRole(c.Request.Context(), claims.UserID, requested)
+	if errors.Is(err, user.ErrNoBranchAccess) {
+		abortBranchDenied(c)
+		return false
+	}
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewErrorResponse(
+			http.StatusInternalServerError,
+			"Erro ao verificar acesso à filial",
+			err.Error(),
+		))
+		return false
+	}
+
+	c.Set("user_role", string(role))
+	setActiveBranch(c, requested)
+	return true
+}
+
+// setActiveBranch grava a filial ativa no contexto do Gin e no contexto da requisição
+func setActiveBranch(c *gin.Context, branchID string) {
+	c.Set(activeBranchKey, branchID)
+	c.Set("branch_id", branchID)
+	if branchID != "" {
+		c.Request = c.Request.WithContext(context.WithValue(c.Request.Context(), branch.BranchIDKeyType(), branchID))
+	}
+}
+
+// abortBranchDenied responde 403 para uma filial a que o usuário não tem acesso
+func abortBranchDenied(c *gin.Context) {
+	c.AbortWithStatusJSON(http.StatusForbidden, dto.NewErrorResponse(
+		http.StatusForbidden,
+		"Acesso negado à filial",
+		"O usuário não tem acesso à filial informada no cabeçalho branch-id",
+	))
+}
+
+// AuthorizeBranch retorna a filial em que a operação deve ocorrer. Sem filial informada, vale a
+// filial ativa validada na autenticação; outra filial só é aceita se o usuário tiver acesso a ela,
+// caso contrário retorna user.ErrNoBranchAccess
+func AuthorizeBranch(c *gin.Context, requested string) (string, error) {
+	// O branch_id gravado por BranchMiddleware vem direto do cabeçalho; só a filial validada
+	// na autenticação é considerada
+	value, authenticated := c.Get(activeBranchKey)
+	if !authenticated {
+		return "", user.ErrNoBranchAccess
+	}
+	active, _ := value.(string)
+	if requested == "" || requested == active {
+		return active, nil
+	}
+
+	value, _ = c.Get(branchAccessKey)
+	resolver, ok := value.(BranchAccessResolver)
+	if !ok {
+		return "", user.ErrNoBranchAccess
+	}
+	if _, err := resolver.FindBranchRole(c.Request.Context(), c.GetString("user_id"), requested); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
+
+// HasAllBranches informa se o usuário autenticado acessa todas as filiais do tenant, o que vale
+// apenas para o administrador do tenant
+func HasAllBranches(c *gin.Context) bool {
+	return c.GetBool(allBranchesKey)
+}
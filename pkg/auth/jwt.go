@@ -25,6 +25,8 @@ type JWTClaims struct {
 	Name     string `json:"name"`
 	Role     string `json:"role"`
 	BranchID string `json:"branch_id,omitempty"`
+	// AllBranches indica o administrador do tenant, que acessa todas as filiais
+	AllBranches bool `json:"all_branches,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -57,25 +59,34 @@ func NewJWTService() (*JWTService, error) {
 	}, nil
 }
 
-// GenerateToken gera um token JWT para o usuário
+// GenerateToken gera um token JWT para o usuário, na filial e com o papel do cadastro
 func (s *JWTService) GenerateToken(u *user.User) (string, error) {
+	return s.GenerateBranchToken(u, u.BranchID, u.Role)
+}
+
+// GenerateBranchToken gera um token JWT para o usuário com a filial ativa e o papel que ele exerce
+// nela. Só o papel admin do cadastro dá acesso a todas as filiais; o papel de um vínculo vale
+// apenas na filial do vínculo
+func (s *JWTService) GenerateBranchToken(u *user.User, branchID string, role user.Role) (string, error) {
 	// Definir o tempo de expiração
 	expirationTime := time.Now().Add(s.expiration)
 
 	// Criar as claims
 	claims := JWTClaims{
-		UserID:   u.ID,
-		TenantID: u.TenantID,
-		Email:    u.Email,
-		Name:     u.Name,
-		Role:     string(u.Role),
-		BranchID: u.BranchID,
+		UserID:      u.ID,
+		TenantID:    u.TenantID,
+		Email:       u.Email,
+		Name:        u.Name,
+		Role:        string(role),
+		BranchID:    branchID,
+		AllBranches: u.IsAdmin(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			NotBefore: jwt.NewNumericDate(time.Now()),
 			Issuer:    "erp-supermercado-api",
 			Subject:   u.ID,
+			Audience:  jwt.ClaimStrings{TenantAudience},
 		},
 	}
 
@@ -116,6 +127,12 @@ func (s *JWTService) ValidateToken(tokenString string) (*JWTClaims, error) {
 		return nil, ErrInvalidClaims
 	}
 
+	// Tokens de operadores da plataforma não valem nas rotas dos tenants. Tokens sem audiência,
+	// emitidos antes dela existir, continuam aceitos até expirarem
+	if hasAudience(claims.Audience, PlatformAudience) {
+		return nil, ErrInvalidToken
+	}
+
 	return claims, nil
 }
 
@@ -133,6 +150,7 @@ func (s *JWTService) RefreshToken(tokenString string) (string, error) {
 	claims.ExpiresAt = jwt.NewNumericDate(expirationTime)
 	claims.IssuedAt = jwt.NewNumericDate(time.Now())
 	claims.NotBefore = jwt.NewNumericDate(time.Now())
+	claims.Audience = jwt.ClaimStrings{TenantAudience}
 
 	// Criar novo token com as claims atualizadas
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
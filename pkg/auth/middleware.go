@@ -74,11 +74,15 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 		c.Set("user_email", claims.Email)
 		c.Set("user_name", claims.Name)
 		c.Set("user_role", claims.Role)
-		c.Set("branch_id", claims.BranchID)
 
 		// Definir o tenant ID para o middleware de tenant
 		c.Request = c.Request.WithContext(tenant.SetTenantIDContext(c.Request.Context(), claims.TenantID))
 
+		// Validar a filial pedida no cabeçalho branch-id contra as filiais do usuário
+		if !applyActiveBranch(c, claims) {
+			return
+		}
+
 		c.Next()
 	}
 }
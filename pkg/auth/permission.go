@@ -0,0 +1,99 @@
+package auth
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/user"
+)
+
+// Chaves do contexto do Gin usadas na verificação de permissões
+const (
+	permissionResolverKey = "permission_resolver"
+	permissionsKey        = "permissions"
+)
+
+// PermissionResolver busca as permissões dos perfis atribuídos ao usuário na filial
+type PermissionResolver interface {
+	AssignedPermissions(ctx context.Context, userID, branchID string) ([]rbac.Permission, error)
+}
+
+// PermissionMiddleware disponibiliza o resolver para RequirePermission. As permissões só são
+// calculadas nas rotas que as exigem, depois da autenticação
+func PermissionMiddleware(resolver PermissionResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Set(permissionResolverKey, resolver)
+		c.Next()
+	}
+}
+
+// RequirePermission cria um middleware que exige a permissão do usuário autenticado na filial
+// ativa (403). Deve vir depois de JWTAuthMiddleware
+func RequirePermission(permission rbac.Permission) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetString("user_id") == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.NewErrorResponse(
+				http.StatusUnauthorized,
+				"Autenticação requerida",
+				"",
+			))
+			return
+		}
+
+		permissions, err := Permissions(c)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Erro ao verificar permissões",
+				err.Error(),
+			))
+			return
+		}
+
+		if !permissions.Has(permission) {
+			c.AbortWithStatusJSON(http.StatusForbidden, dto.NewErrorResponse(
+				http.StatusForbidden,
+				"Acesso negado",
+				"Permissão necessária: "+string(permission),
+			))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// Permissions calcula as permissões efetivas do usuário autenticado na filial ativa: as do perfil
+// padrão somadas às dos perfis atribuídos. O resultado fica guardado no contexto da requisição
+func Permissions(c *gin.Context) (rbac.PermissionSet, error) {
+	if value, ok := c.Get(permissionsKey); ok {
+		if permissions, ok := value.(rbac.PermissionSet); ok {
+			return permissions, nil
+		}
+	}
+
+	permissions := rbac.NewPermissionSet(rbac.SystemPermissions(user.Role(c.GetString("user_role")))...)
+
+	if value, ok := c.Get(permissionResolverKey); ok {
+		if resolver, ok := value.(PermissionResolver); ok {
+			assigned, err := resolver.AssignedPermissions(c.Request.Context(), c.GetString("user_id"), c.GetString("branch_id"))
+			if err != nil {
+				return nil, err
+			}
+			permissions.Add(assigned...)
+		}
+	}
+
+	c.Set(permissionsKey, permissions)
+	return permissions, nil
+}
+
+// HasPermission verifica uma permissão do usuário autenticado dentro de um handler. Em caso de
+// erro ao buscar as permissões, nega o acesso
+func HasPermission(c *gin.Context, permission rbac.Permission) bool {
+	permissions, err := Permissions(c)
+	return err == nil && permissions.Has(permission)
+}
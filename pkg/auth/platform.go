@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/platform"
+)
+
+// Audiências dos tokens: um token emitido para uma delas é rejeitado pela outra
+const (
+	TenantAudience   = "erp-supermercado-tenant"   // Usuários dos tenants
+	PlatformAudience = "erp-supermercado-platform" // Operadores da plataforma
+)
+
+// platformTokenExpiration limita a validade dos tokens de operadores, que administram todos os
+// tenants, mesmo com um JWT_EXPIRATION maior
+const platformTokenExpiration = 8 * time.Hour
+
+// PlatformClaims representa as claims do token de um operador da plataforma. Não tem tenant
+type PlatformClaims struct {
+	OperatorID string `json:"operator_id"`
+	Email      string `json:"email"`
+	Name       string `json:"name"`
+	jwt.RegisteredClaims
+}
+
+// GeneratePlatformToken gera um token JWT para o operador da plataforma e retorna a expiração
+func (s *JWTService) GeneratePlatformToken(o *platform.Operator) (string, time.Time, error) {
+	now := time.Now()
+	expirationTime := now.Add(min(s.expiration, platformTokenExpiration))
+
+	claims := PlatformClaims{
+		OperatorID: o.ID,
+		Email:      o.Email,
+		Name:       o.Name,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			Issuer:    "erp-supermercado-api",
+			Subject:   o.ID,
+			Audience:  jwt.ClaimStrings{PlatformAudience},
+		},
+	}
+
+	tokenString, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(s.secretKey)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return tokenString, expirationTime, nil
+}
+
+// ValidatePlatformToken valida um token de operador da plataforma. Tokens de usuários dos tenants
+// são rejeitados pela audiência
+func (s *JWTService) ValidatePlatformToken(tokenString string) (*PlatformClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &PlatformClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, ErrInvalidToken
+		}
+		return s.secretKey, nil
+	}, jwt.WithAudience(PlatformAudience))
+
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return nil, ErrExpiredToken
+		}
+		return nil, ErrInvalidToken
+	}
+
+	claims, ok := token.Claims.(*PlatformClaims)
+	if !ok || !token.Valid || claims.OperatorID == "" {
+		return nil, ErrInvalidClaims
+	}
+	return claims, nil
+}
+
+// OperatorFinder busca o operador do token, para que a desativação revogue o acesso sem esperar a
+// expiração
+type OperatorFinder interface {
+	FindByID(ctx context.Context, id string) (*platform.Operator, error)
+}
+
+// PlatformAuthMiddleware cria um middleware que exige o token de um operador ativo da plataforma
+func PlatformAuthMiddleware(operators OperatorFinder) gin.HandlerFunc {
+	jwtService, err := NewJWTService()
+	if err != nil {
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Erro ao configurar autenticação",
+				"O serviço JWT não foi inicializado corretamente",
+			))
+		}
+	}
+
+	return func(c *gin.Context) {
+		tokenParts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(tokenParts) != 2 || tokenParts[0] != "Bearer" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.NewErrorResponse(
+				http.StatusUnauthorized,
+				"Autenticação requerida",
+				"Informe o token do operador da plataforma no formato 'Bearer <token>'",
+			))
+			return
+		}
+
+		claims, err := jwtService.ValidatePlatformToken(tokenParts[1])
+		if err != nil {
+			message := "Token inválido"
+			if errors.Is(err, ErrExpiredToken) {
+				message = "Token expirado"
+			}
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.NewErrorResponse(
+				http.StatusUnauthorized,
+				message,
+				err.Error(),
+			))
+			return
+		}
+
+		operator, err := operators.FindByID(c.Request.Context(), claims.OperatorID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Erro ao verificar operador",
+				err.Error(),
+			))
+			return
+		}
+		if !operator.IsActive() {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, dto.NewErrorResponse(
+				http.StatusUnauthorized,
+				"Token inválido",
+				"Operador inativo",
+			))
+			return
+		}
+
+		c.Set("operator_id", claims.OperatorID)
+		c.Set("operator_email", claims.Email)
+		c.Set("operator_name", claims.Name)
+
+		c.Next()
+	}
+}
+
+// GetCurrentOperator obtém o ID, o email e o nome do operador autenticado
+func GetCurrentOperator(c *gin.Context) (string, string, string) {
+	return c.GetString("operator_id"), c.GetString("operator_email"), c.GetString("operator_name")
+}
+
+// hasAudience verifica se a audiência está entre as do token
+func hasAudience(audiences jwt.ClaimStrings, audience string) bool {
+	for _, a := range audiences {
+		if a == audience {
+			return true
+		}
+	}
+	return false
+}
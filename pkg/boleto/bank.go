@@ -0,0 +1,355 @@
+package boleto
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// Bank define as regras específicas de cada banco para a montagem do boleto
+type Bank interface {
+	// Code retorna o código de compensação do banco (3 dígitos)
+	Code() string
+
+	// Name retorna o nome do banco como impresso nos boletos e arquivos CNAB
+	Name() string
+
+	// FreeField monta o campo livre (25 posições) do código de barras
+	FreeField(b *Boleto) (string, error)
+
+	// OurNumberDigit calcula o dígito verificador do nosso número
+	OurNumberDigit(b *Boleto) string
+
+	// PrintedOurNumber formata o nosso número como impresso na ficha de compensação
+	PrintedOurNumber(b *Boleto) string
+
+	// BeneficiaryCode formata o campo "Agência/Código do Beneficiário"
+	BeneficiaryCode(b *Boleto) string
+}
+
+var banks = map[string]Bank{
+	"001": bancoDoBrasil{},
+	"033": santander{},
+	"104": caixa{},
+	"237": bradesco{},
+	"341": itau{},
+}
+
+// BankByCode retorna as regras do banco pelo código de compensação
+func BankByCode(code string) (Bank, error) {
+	bank, ok := banks[code]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedBank, code)
+	}
+	return bank, nil
+}
+
+// SupportedBanks retorna os códigos dos bancos suportados
+func SupportedBanks() []string {
+	return []string{"001", "033", "104", "237", "341"}
+}
+
+// bancoDoBrasil implementa as regras do Banco do Brasil (001) para convênios de 4, 6 e 7 dígitos
+type bancoDoBrasil struct{}
+
+func (bancoDoBrasil) Code() string { return "001" }
+func (bancoDoBrasil) Name() string { return "BANCO DO BRASIL S.A." }
+
+func (bb bancoDoBrasil) FreeField(b *Boleto) (string, error) {
+	wallet, err := padDigits(b.Wallet, 2, "carteira")
+	if err != nil {
+		return "", err
+	}
+
+	ourNumber, err := bb.ourNumber(b)
+	if err != nil {
+		return "", err
+	}
+
+	// Convênio de 7 dígitos: zeros(6) + nosso número(17) + carteira(2)
+	if len(onlyDigits(b.Agreement)) == 7 {
+		return "000000" + ourNumber + wallet, nil
+	}
+
+	// Convênios de 4 e 6 dígitos: nosso número(11) + agência(4) + conta(8) + carteira(2)
+	agency, err := padDigits(b.Agency, 4, "agência")
+	if err != nil {
+		return "", err
+	}
+	account, err := padDigits(b.Account, 8, "conta")
+	if err != nil {
+		return "", err
+	}
+	return ourNumber + agency + account + wallet, nil
+}
+
+func (bb bancoDoBrasil) OurNumberDigit(b *Boleto) string {
+	// Convênios de 7 dígitos não usam DV no nosso número
+	if len(onlyDigits(b.Agreement)) == 7 {
+		return ""
+	}
+	ourNumber, err := bb.ourNumber(b)
+	if err != nil {
+		return ""
+	}
+	rest := weightedSum(ourNumber, 9) % 11
+	switch {
+	case rest == 0:
+		return "0"
+	case rest == 1:
+		return "X"
+	default:
+		return strconv.Itoa(11 - rest)
+	}
+}
+
+func (bb bancoDoBrasil) PrintedOurNumber(b *Boleto) string {
+	ourNumber, _ := bb.ourNumber(b)
+	if dv := bb.OurNumberDigit(b); dv != "" {
+		return ourNumber + "-" + dv
+	}
+	return ourNumber
+}
+
+func (bancoDoBrasil) BeneficiaryCode(b *Boleto) string {
+	return formatAgencyAccount(b)
+}
+
+// ourNumber monta o nosso número com o prefixo do convênio
+func (bancoDoBrasil) ourNumber(b *Boleto) (string, error) {
+	agreement := onlyDigits(b.Agreement)
+	switch len(agreement) {
+	case 7:
+		sequence, err := padDigits(b.OurNumber, 10, "nosso número")
+		return agreement + sequence, err
+	case 6:
+		sequence, err := padDigits(b.OurNumber, 5, "nosso número")
+		return agreement + sequence, err
+	case 4:
+		sequence, err := padDigits(b.OurNumber, 7, "nosso número")
+		return agreement + sequence, err
+	default:
+		return "", fmt.Errorf("%w: convênio do Banco do Brasil deve ter 4, 6 ou 7 dígitos", ErrFieldTooLong)
+	}
+}
+
+// santander implementa as regras do Santander (033)
+type santander struct{}
+
+func (santander) Code() string { return "033" }
+func (santander) Name() string { return "BANCO SANTANDER S.A." }
+
+func (s santander) FreeField(b *Boleto) (string, error) {
+	agreement, err := padDigits(b.Agreement, 7, "código do beneficiário")
+	if err != nil {
+		return "", err
+	}
+	ourNumber, err := padDigits(b.OurNumber, 12, "nosso número")
+	if err != nil {
+		return "", err
+	}
+	wallet, err := padDigits(b.Wallet, 3, "carteira")
+	if err != nil {
+		return "", err
+	}
+
+	// "9" + código do beneficiário(7) + nosso número(12) + DV(1) + IOF(1) + carteira(3)
+	return "9" + agreement + ourNumber + s.OurNumberDigit(b) + "0" + wallet, nil
+}
+
+func (santander) OurNumberDigit(b *Boleto) string {
+	ourNumber, err := padDigits(b.OurNumber, 12, "nosso número")
+	if err != nil {
+		return ""
+	}
+	rest := weightedSum(ourNumber, 9) % 11
+	switch rest {
+	case 0, 1:
+		return "0"
+	case 10:
+		return "1"
+	default:
+		return strconv.Itoa(11 - rest)
+	}
+}
+
+func (s santander) PrintedOurNumber(b *Boleto) string {
+	ourNumber, _ := padDigits(b.OurNumber, 12, "nosso número")
+	return ourNumber + "-" + s.OurNumberDigit(b)
+}
+
+func (santander) BeneficiaryCode(b *Boleto) string {
+	return onlyDigits(b.Agency) + " / " + onlyDigits(b.Agreement)
+}
+
+// caixa implementa as regras da Caixa Econômica Federal (104) na carteira SIGCB
+type caixa struct{}
+
+func (caixa) Code() string { return "104" }
+func (caixa) Name() string { return "CAIXA ECONOMICA FEDERAL" }
+
+func (c caixa) FreeField(b *Boleto) (string, error) {
+	agreement, err := padDigits(b.Agreement, 6, "código do beneficiário")
+	if err != nil {
+		return "", err
+	}
+	sequence, err := padDigits(b.OurNumber, 15, "nosso número")
+	if err != nil {
+		return "", err
+	}
+
+	// Beneficiário(6) + DV(1) + seq(3) + "1" registrada + seq(3) + "4" emissão pelo beneficiário + seq(9)
+	field := agreement + caixaDigit(agreement) + sequence[0:3] + "1" + sequence[3:6] + "4" + sequence[6:15]
+	return field + caixaDigit(field), nil
+}
+
+func (c caixa) OurNumberDigit(b *Boleto) string {
+	return caixaDigit(c.ourNumber(b))
+}
+
+func (c caixa) PrintedOurNumber(b *Boleto) string {
+	return c.ourNumber(b) + "-" + c.OurNumberDigit(b)
+}
+
+func (caixa) BeneficiaryCode(b *Boleto) string {
+	agreement, _ := padDigits(b.Agreement, 6, "código do beneficiário")
+	return onlyDigits(b.Agency) + " / " + agreement + "-" + caixaDigit(agreement)
+}
+
+// ourNumber monta o nosso número de 17 posições com o prefixo "14" (registrada, emissão pelo beneficiário)
+func (caixa) ourNumber(b *Boleto) string {
+	sequence, _ := padDigits(b.OurNumber, 15, "nosso número")
+	return "14" + sequence
+}
+
+// caixaDigit calcula o DV módulo 11 usado pela Caixa, onde restos acima de 9 resultam em zero
+func caixaDigit(value string) string {
+	dv := 11 - weightedSum(value, 9)%11
+	if dv > 9 {
+		dv = 0
+	}
+	return strconv.Itoa(dv)
+}
+
+// bradesco implementa as regras do Bradesco (237)
+type bradesco struct{}
+
+func (bradesco) Code() string { return "237" }
+func (bradesco) Name() string { return "BRADESCO" }
+
+func (bradesco) FreeField(b *Boleto) (string, error) {
+	agency, err := padDigits(b.Agency, 4, "agência")
+	if err != nil {
+		return "", err
+	}
+	wallet, err := padDigits(b.Wallet, 2, "carteira")
+	if err != nil {
+		return "", err
+	}
+	ourNumber, err := padDigits(b.OurNumber, 11, "nosso número")
+	if err != nil {
+		return "", err
+	}
+	account, err := padDigits(b.Account, 7, "conta")
+	if err != nil {
+		return "", err
+	}
+
+	// Agência(4) + carteira(2) + nosso número(11) + conta(7) + zero
+	return agency + wallet + ourNumber + account + "0", nil
+}
+
+func (bradesco) OurNumberDigit(b *Boleto) string {
+	wallet, _ := padDigits(b.Wallet, 2, "carteira")
+	ourNumber, err := padDigits(b.OurNumber, 11, "nosso número")
+	if err != nil {
+		return ""
+	}
+	rest := weightedSum(wallet+ourNumber, 7) % 11
+	switch rest {
+	case 0:
+		return "0"
+	case 1:
+		return "P"
+	default:
+		return strconv.Itoa(11 - rest)
+	}
+}
+
+func (br bradesco) PrintedOurNumber(b *Boleto) string {
+	wallet, _ := padDigits(b.Wallet, 2, "carteira")
+	ourNumber, _ := padDigits(b.OurNumber, 11, "nosso número")
+	return wallet + "/" + ourNumber + "-" + br.OurNumberDigit(b)
+}
+
+func (bradesco) BeneficiaryCode(b *Boleto) string {
+	return formatAgencyAccount(b)
+}
+
+// itau implementa as regras do Itaú (341)
+type itau struct{}
+
+func (itau) Code() string { return "341" }
+func (itau) Name() string { return "BANCO ITAU SA" }
+
+func (i itau) FreeField(b *Boleto) (string, error) {
+	wallet, err := padDigits(b.Wallet, 3, "carteira")
+	if err != nil {
+		return "", err
+	}
+	ourNumber, err := padDigits(b.OurNumber, 8, "nosso número")
+	if err != nil {
+		return "", err
+	}
+	agency, err := padDigits(b.Agency, 4, "agência")
+	if err != nil {
+		return "", err
+	}
+	account, err := padDigits(b.Account, 5, "conta")
+	if err != nil {
+		return "", err
+	}
+
+	// Carteira(3) + nosso número(8) + DAC(1) + agência(4) + conta(5) + DAC agência/conta(1) + zeros(3)
+	return wallet + ourNumber + i.OurNumberDigit(b) + agency + account + Mod10(agency+account) + "000", nil
+}
+
+func (itau) OurNumberDigit(b *Boleto) string {
+	wallet, _ := padDigits(b.Wallet, 3, "carteira")
+	ourNumber, err := padDigits(b.OurNumber, 8, "nosso número")
+	if err != nil {
+		return ""
+	}
+
+	// Nas carteiras escriturais o DAC considera apenas carteira e nosso número
+	switch wallet {
+	case "126", "131", "146", "150", "168":
+		return Mod10(wallet + ourNumber)
+	}
+
+	agency, _ := padDigits(b.Agency, 4, "agência")
+	account, _ := padDigits(b.Account, 5, "conta")
+	return Mod10(agency + account + wallet + ourNumber)
+}
+
+func (i itau) PrintedOurNumber(b *Boleto) string {
+	wallet, _ := padDigits(b.Wallet, 3, "carteira")
+	ourNumber, _ := padDigits(b.OurNumber, 8, "nosso número")
+	return wallet + "/" + ourNumber + "-" + i.OurNumberDigit(b)
+}
+
+func (itau) BeneficiaryCode(b *Boleto) string {
+	return formatAgencyAccount(b)
+}
+
+// formatAgencyAccount formata agência e conta no padrão "0000-0 / 00000-0"
+func formatAgencyAccount(b *Boleto) string {
+	agency := onlyDigits(b.Agency)
+	if b.AgencyDigit != "" {
+		agency += "-" + b.AgencyDigit
+	}
+	account := onlyDigits(b.Account)
+	if b.AccountDigit != "" {
+		account += "-" + b.AccountDigit
+	}
+	return agency + " / " + account
+}
@@ -0,0 +1,207 @@
+package boleto
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedBank = errors.New("banco não suportado para emissão de boletos")
+	ErrInvalidAmount   = errors.New("valor do boleto deve ser maior que zero")
+	ErrInvalidDueDate  = errors.New("vencimento do boleto fora da faixa aceita pela FEBRABAN")
+	ErrFieldTooLong    = errors.New("campo excede o tamanho permitido pelo banco")
+	ErrInvalidBarcode  = errors.New("código de barras inválido")
+)
+
+// baseDate é a data base do fator de vencimento definida pela FEBRABAN
+var baseDate = time.Date(1997, 10, 7, 0, 0, 0, 0, time.UTC)
+
+// Party representa o beneficiário ou o pagador de um boleto
+type Party struct {
+	Name     string
+	Document string // CPF/CNPJ
+	Address  string
+	District string
+	City     string
+	State    string
+	ZipCode  string
+}
+
+// Boleto reúne os dados necessários para montar o código de barras e a ficha de compensação
+type Boleto struct {
+	BankCode       string
+	Agency         string
+	AgencyDigit    string
+	Account        string
+	AccountDigit   string
+	Wallet         string // Carteira
+	Agreement      string // Convênio / código do beneficiário
+	OurNumber      string // Nosso número sem dígito verificador
+	DocumentNumber string
+	Amount         float64
+	IssueDate      time.Time
+	DueDate        time.Time
+	Beneficiary    Party
+	Payer          Party
+	Instructions   []string
+}
+
+// Result contém a representação numérica gerada para o boleto
+type Result struct {
+	Barcode        string `json:"barcode"`          // 44 posições
+	DigitableLine  string `json:"digitable_line"`   // Linha digitável formatada
+	OurNumber      string `json:"our_number"`       // Nosso número como impresso no boleto
+	OurNumberDigit string `json:"our_number_digit"` // Dígito do nosso número (vazio quando o banco não usa)
+}
+
+// Generate monta o código de barras e a linha digitável de um boleto
+func Generate(b *Boleto) (*Result, error) {
+	bank, err := BankByCode(b.BankCode)
+	if err != nil {
+		return nil, err
+	}
+	if b.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	factor, err := DueDateFactor(b.DueDate)
+	if err != nil {
+		return nil, err
+	}
+
+	cents := int64(math.Round(b.Amount * 100))
+	if cents > 99999999 {
+		return nil, fmt.Errorf("%w: valor", ErrFieldTooLong)
+	}
+
+	freeField, err := bank.FreeField(b)
+	if err != nil {
+		return nil, err
+	}
+	if len(freeField) != 25 {
+		return nil, fmt.Errorf("%w: campo livre com %d posições", ErrInvalidBarcode, len(freeField))
+	}
+
+	// Código de barras sem o DV: banco(3) + moeda(1) + fator(4) + valor(10) + campo livre(25)
+	partial := bank.Code() + "9" + fmt.Sprintf("%04d%010d", factor, cents) + freeField
+	dv := BarcodeDigit(partial)
+	barcode := partial[:4] + dv + partial[4:]
+
+	return &Result{
+		Barcode:        barcode,
+		DigitableLine:  DigitableLine(barcode),
+		OurNumber:      bank.PrintedOurNumber(b),
+		OurNumberDigit: bank.OurNumberDigit(b),
+	}, nil
+}
+
+// DueDateFactor calcula o fator de vencimento, considerando o reinício em 1000 após 9999 (22/02/2025)
+func DueDateFactor(dueDate time.Time) (int, error) {
+	if dueDate.IsZero() {
+		return 0, ErrInvalidDueDate
+	}
+
+	due := time.Date(dueDate.Year(), dueDate.Month(), dueDate.Day(), 0, 0, 0, 0, time.UTC)
+	days := int(due.Sub(baseDate).Hours() / 24)
+	if days < 1000 {
+		return 0, ErrInvalidDueDate
+	}
+
+	return (days-1000)%9000 + 1000, nil
+}
+
+// DigitableLine converte um código de barras de 44 posições na linha digitável formatada
+func DigitableLine(barcode string) string {
+	if len(barcode) != 44 {
+		return ""
+	}
+
+	free := barcode[19:]
+	field1 := barcode[0:4] + free[0:5]
+	field2 := free[5:15]
+	field3 := free[15:25]
+
+	field1 += Mod10(field1)
+	field2 += Mod10(field2)
+	field3 += Mod10(field3)
+
+	return fmt.Sprintf("%s.%s %s.%s %s.%s %s %s",
+		field1[:5], field1[5:], field2[:5], field2[5:], field3[:5], field3[5:], barcode[4:5], barcode[5:19])
+}
+
+// ValidateBarcode confere o dígito verificador geral de um código de barras
+func ValidateBarcode(barcode string) error {
+	if len(barcode) != 44 || onlyDigits(barcode) != barcode {
+		return ErrInvalidBarcode
+	}
+	if BarcodeDigit(barcode[:4]+barcode[5:]) != barcode[4:5] {
+		return ErrInvalidBarcode
+	}
+	return nil
+}
+
+// BarcodeDigit calcula o DV geral do código de barras (módulo 11, pesos 2 a 9)
+func BarcodeDigit(value string) string {
+	sum := weightedSum(value, 9)
+	dv := 11 - sum%11
+	if dv == 0 || dv == 10 || dv == 11 {
+		dv = 1
+	}
+	return fmt.Sprintf("%d", dv)
+}
+
+// Mod10 calcula o dígito verificador módulo 10 usado nos campos da linha digitável
+func Mod10(value string) string {
+	sum := 0
+	weight := 2
+	for i := len(value) - 1; i >= 0; i-- {
+		product := int(value[i]-'0') * weight
+		if product > 9 {
+			product = product/10 + product%10
+		}
+		sum += product
+		if weight == 2 {
+			weight = 1
+		} else {
+			weight = 2
+		}
+	}
+	return fmt.Sprintf("%d", (10-sum%10)%10)
+}
+
+// weightedSum soma os dígitos multiplicados pelos pesos 2..maxWeight, da direita para a esquerda
+func weightedSum(value string, maxWeight int) int {
+	sum := 0
+	weight := 2
+	for i := len(value) - 1; i >= 0; i-- {
+		sum += int(value[i]-'0') * weight
+		weight++
+		if weight > maxWeight {
+			weight = 2
+		}
+	}
+	return sum
+}
+
+// padDigits mantém apenas os dígitos e completa com zeros à esquerda
+func padDigits(value string, size int, field string) (string, error) {
+	digits := onlyDigits(value)
+	if len(digits) > size {
+		return "", fmt.Errorf("%w: %s", ErrFieldTooLong, field)
+	}
+	return strings.Repeat("0", size-len(digits)) + digits, nil
+}
+
+// onlyDigits remove todos os caracteres não numéricos
+func onlyDigits(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,176 @@
+package boleto
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestDueDateFactor(t *testing.T) {
+	tests := []struct {
+		name    string
+		due     time.Time
+		want    int
+		wantErr error
+	}{
+		{"primeiro fator válido", date(2000, 7, 3), 1000, nil},
+		{"último dia do primeiro ciclo", date(2025, 2, 21), 9999, nil},
+		{"reinício do fator em 22/02/2025", date(2025, 2, 22), 1000, nil},
+		{"dia seguinte ao reinício", date(2025, 2, 23), 1001, nil},
+		{"horário e fuso são ignorados", time.Date(2025, 2, 22, 23, 59, 0, 0, time.FixedZone("BRT", -3*3600)), 1000, nil},
+		{"anterior à faixa da FEBRABAN", date(2000, 7, 2), 0, ErrInvalidDueDate},
+		{"sem vencimento", time.Time{}, 0, ErrInvalidDueDate},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DueDateFactor(tt.due)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DueDateFactor() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("DueDateFactor() = %d, esperado %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name          string
+		boleto        Boleto
+		barcode       string
+		digitableLine string
+		ourNumber     string
+	}{
+		{
+			name:          "Banco do Brasil, convênio de 7 dígitos",
+			boleto:        Boleto{BankCode: "001", Agreement: "1234567", OurNumber: "42", Wallet: "17", Amount: 100.50, DueDate: date(2025, 2, 21)},
+			barcode:       "00197999900000100500000001234567000000004217",
+			digitableLine: "00190.00009 01234.567004 00000.042176 7 99990000010050",
+			ourNumber:     "12345670000000042",
+		},
+		{
+			name:          "Itaú, vencimento no reinício do fator",
+			boleto:        Boleto{BankCode: "341", Agency: "1234", Account: "12345", Wallet: "109", OurNumber: "42", Amount: 1234.56, DueDate: date(2025, 2, 22)},
+			barcode:       "34193100000001234561090000004241234123451000",
+			digitableLine: "34191.09008 00004.241238 41234.510000 3 10000000123456",
+			ourNumber:     "109/00000042-4",
+		},
+		{
+			name:          "Bradesco",
+			boleto:        Boleto{BankCode: "237", Agency: "1234", Account: "12345", Wallet: "09", OurNumber: "42", Amount: 0.99, DueDate: date(2025, 3, 1)},
+			barcode:       "23792100700000000991234090000000004200123450",
+			digitableLine: "23791.23405 90000.000001 42001.234501 2 10070000000099",
+			ourNumber:     "09/00000000042-9",
+		},
+		{
+			name:          "Santander",
+			boleto:        Boleto{BankCode: "033", Agreement: "1234567", Wallet: "101", OurNumber: "42", Amount: 50, DueDate: date(2030, 1, 15)},
+			barcode:       "03393278800000050009123456700000000004260101",
+			digitableLine: "03399.12347 56700.000005 00042.601013 3 27880000005000",
+			ourNumber:     "000000000042-6",
+		},
+		{
+			name:          "Caixa SIGCB",
+			boleto:        Boleto{BankCode: "104", Agreement: "123456", OurNumber: "42", Amount: 0.01, DueDate: date(2000, 7, 3)},
+			barcode:       "10494100000000000011234560000100040000000420",
+			digitableLine: "10491.23456 60000.100044 00000.004200 4 10000000000001",
+			ourNumber:     "14000000000000042-1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Generate(&tt.boleto)
+			if err != nil {
+				t.Fatalf("Generate() erro inesperado: %v", err)
+			}
+			if got.Barcode != tt.barcode {
+				t.Errorf("Barcode = %s, esperado %s", got.Barcode, tt.barcode)
+			}
+			if got.DigitableLine != tt.digitableLine {
+				t.Errorf("DigitableLine = %s, esperado %s", got.DigitableLine, tt.digitableLine)
+			}
+			if got.OurNumber != tt.ourNumber {
+				t.Errorf("OurNumber = %s, esperado %s", got.OurNumber, tt.ourNumber)
+			}
+			if err := ValidateBarcode(got.Barcode); err != nil {
+				t.Errorf("ValidateBarcode() do código gerado: %v", err)
+			}
+		})
+	}
+}
+
+func TestGenerateErrors(t *testing.T) {
+	due := date(2025, 6, 10)
+	tests := []struct {
+		name    string
+		boleto  Boleto
+		wantErr error
+	}{
+		{"banco não suportado", Boleto{BankCode: "999", Amount: 10, DueDate: due}, ErrUnsupportedBank},
+		{"valor zero", Boleto{BankCode: "341", Amount: 0, DueDate: due}, ErrInvalidAmount},
+		{"valor acima de 10 posições", Boleto{BankCode: "341", Wallet: "109", Amount: 1000000, DueDate: due}, ErrFieldTooLong},
+		{"vencimento fora da faixa", Boleto{BankCode: "341", Amount: 10, DueDate: date(1999, 1, 1)}, ErrInvalidDueDate},
+		{"convênio BB de tamanho inválido", Boleto{BankCode: "001", Agreement: "12345", Wallet: "17", Amount: 10, DueDate: due}, ErrFieldTooLong},
+		{"nosso número Itaú longo demais", Boleto{BankCode: "341", Wallet: "109", OurNumber: "123456789", Amount: 10, DueDate: due}, ErrFieldTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Generate(&tt.boleto); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Generate() erro = %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateBarcode(t *testing.T) {
+	tests := []struct {
+		name    string
+		barcode string
+		wantErr error
+	}{
+		{"válido", "34193100000001234561090000004241234123451000", nil},
+		{"DV geral alterado", "34194100000001234561090000004241234123451000", ErrInvalidBarcode},
+		{"valor alterado", "34193100000001234571090000004241234123451000", ErrInvalidBarcode},
+		{"tamanho incorreto", "3419310000000123456109000000424123412345100", ErrInvalidBarcode},
+		{"caractere não numérico", "3419310000000123456109000000424123412345100X", ErrInvalidBarcode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := ValidateBarcode(tt.barcode); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateBarcode() erro = %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckDigits(t *testing.T) {
+	tests := []struct {
+		name  string
+		fn    func(string) string
+		value string
+		want  string
+	}{
+		{"módulo 10", Mod10, "001900000", "9"},
+		{"módulo 10 com soma múltipla de 10", Mod10, "0000000000", "0"},
+		{"módulo 11 geral", BarcodeDigit, "0019999900000100500000001234567000000004217", "7"},
+		{"módulo 11 com resto 8", BarcodeDigit, "3419100000001234561090000004241234123451000", "3"},
+		{"módulo 11 com resto zero usa 1", BarcodeDigit, "0000000000000000000000000000000000000000000", "1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.fn(tt.value); got != tt.want {
+				t.Errorf("dígito de %s = %s, esperado %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
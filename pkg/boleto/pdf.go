@@ -0,0 +1,293 @@
+package boleto
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Dígitos verificadores dos códigos de compensação impressos no cabeçalho da ficha
+var bankDigits = map[string]string{
+	"001": "9",
+	"033": "7",
+	"104": "0",
+	"237": "2",
+	"341": "7",
+}
+
+// Padrões do código Interleaved 2 of 5 (n = barra/espaço estreito, w = largo)
+var i25Patterns = [10]string{
+	"nnwwn", "wnnnw", "nwnnw", "wwnnn", "nnwnw",
+	"wnwnn", "nwwnn", "nnnww", "wnnwn", "nwnwn",
+}
+
+const (
+	pageWidth    = 595.0
+	pageHeight   = 842.0
+	marginLeft   = 30.0
+	contentWidth = 535.0
+	rightColumn  = 140.0
+	narrowBar    = 0.72
+	wideBar      = narrowBar * 3
+	barcodeH     = 36.0
+)
+
+// RenderPDF gera o boleto em PDF (A4) com o recibo do pagador e a ficha de compensação
+func RenderPDF(b *Boleto, r *Result) ([]byte, error) {
+	bank, err := BankByCode(b.BankCode)
+	if err != nil {
+		return nil, err
+	}
+	if err := ValidateBarcode(r.Barcode); err != nil {
+		return nil, err
+	}
+
+	p := &pdfPage{}
+	p.write("0.5 w\n")
+
+	// Recibo do pagador
+	y := pageHeight - 50
+	p.header(y, bank, r.DigitableLine)
+	y -= 8
+	y = p.row(y, []cell{
+		{"Beneficiário", b.Beneficiary.Name + " - " + b.Beneficiary.Document, contentWidth - rightColumn},
+		{"Vencimento", formatDate(b.DueDate), rightColumn},
+	})
+	y = p.row(y, []cell{
+		{"Pagador", b.Payer.Name + " - " + b.Payer.Document, contentWidth - rightColumn},
+		{"Nosso número", r.OurNumber, rightColumn},
+	})
+	y = p.row(y, []cell{
+		{"Nº do documento", b.DocumentNumber, (contentWidth - rightColumn) / 2},
+		{"Agência/Código do Beneficiário", bank.BeneficiaryCode(b), (contentWidth - rightColumn) / 2},
+		{"(=) Valor do documento", formatMoney(b.Amount), rightColumn},
+	})
+	p.text(pageWidth-marginLeft-90, y-10, 6, false, "Autenticação mecânica")
+	p.text(marginLeft, y-10, 6, true, "RECIBO DO PAGADOR")
+
+	// Linha de corte
+	y -= 40
+	for x := marginLeft; x < marginLeft+contentWidth; x += 6 {
+		p.line(x, y, x+3, y)
+	}
+
+	// Ficha de compensação
+	y -= 30
+	p.header(y, bank, r.DigitableLine)
+	y -= 8
+	y = p.row(y, []cell{
+		{"Local de pagamento", "PAGÁVEL EM QUALQUER BANCO ATÉ O VENCIMENTO", contentWidth - rightColumn},
+		{"Vencimento", formatDate(b.DueDate), rightColumn},
+	})
+	y = p.row(y, []cell{
+		{"Beneficiário", b.Beneficiary.Name + " - " + b.Beneficiary.Document, contentWidth - rightColumn},
+		{"Agência/Código do Beneficiário", bank.BeneficiaryCode(b), rightColumn},
+	})
+	small := (contentWidth - rightColumn) / 5
+	y = p.row(y, []cell{
+		{"Data do documento", formatDate(b.IssueDate), small},
+		{"Nº do documento", b.DocumentNumber, small},
+		{"Espécie doc.", "DM", small},
+		{"Aceite", "N", small},
+		{"Data processamento", formatDate(b.IssueDate), small},
+		{"Nosso número", r.OurNumber, rightColumn},
+	})
+	y = p.row(y, []cell{
+		{"Uso do banco", "", small},
+		{"Carteira", b.Wallet, small},
+		{"Espécie", "R$", small},
+		{"Quantidade", "", small},
+		{"Valor", "", small},
+		{"(=) Valor do documento", formatMoney(b.Amount), rightColumn},
+	})
+
+	// Instruções à esquerda e campos de valores à direita
+	instructionsTop := y
+	p.text(marginLeft+2, y-7, 6, false, "Instruções (texto de responsabilidade do beneficiário)")
+	for i, instruction := range b.Instructions {
+		if i >= 6 {
+			break
+		}
+		p.text(marginLeft+2, y-20-float64(i)*11, 8, false, instruction)
+	}
+	for _, label := range []string{"(-) Desconto/Abatimento", "(-) Outras deduções", "(+) Mora/Multa", "(+) Outros acréscimos", "(=) Valor cobrado"} {
+		y = p.row(y, []cell{{"", "", contentWidth - rightColumn}, {label, "", rightColumn}})
+	}
+	p.line(marginLeft, instructionsTop, marginLeft, y)
+	p.line(marginLeft+contentWidth-rightColumn, instructionsTop, marginLeft+contentWidth-rightColumn, y)
+
+	// Pagador
+	p.text(marginLeft+2, y-7, 6, false, "Pagador")
+	p.text(marginLeft+2, y-17, 8, false, b.Payer.Name+" - "+b.Payer.Document)
+	p.text(marginLeft+2, y-27, 8, false, strings.TrimSpace(b.Payer.Address+" "+b.Payer.District))
+	p.text(marginLeft+2, y-37, 8, false, strings.TrimSpace(b.Payer.ZipCode+" "+b.Payer.City+" "+b.Payer.State))
+	y -= 42
+	p.line(marginLeft, y, marginLeft+contentWidth, y)
+	p.text(pageWidth-marginLeft-150, y-8, 6, false, "Autenticação mecânica - Ficha de Compensação")
+
+	p.barcode(marginLeft, y-12-barcodeH, r.Barcode)
+
+	return p.document(), nil
+}
+
+// cell representa um campo rotulado da ficha
+type cell struct {
+	label string
+	value string
+	width float64
+}
+
+// pdfPage acumula os comandos de desenho de uma página
+type pdfPage struct {
+	content bytes.Buffer
+}
+
+func (p *pdfPage) write(format string, args ...interface{}) {
+	fmt.Fprintf(&p.content, format, args...)
+}
+
+func (p *pdfPage) text(x, y, size float64, bold bool, value string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	p.write("BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, y, escapePDF(value))
+}
+
+func (p *pdfPage) line(x1, y1, x2, y2 float64) {
+	p.write("%.2f %.2f m %.2f %.2f l S\n", x1, y1, x2, y2)
+}
+
+func (p *pdfPage) rect(x, y, w, h float64) {
+	p.write("%.3f %.2f %.3f %.2f re f\n", x, y, w, h)
+}
+
+// header desenha o nome do banco, o código com DV e a linha digitável
+func (p *pdfPage) header(y float64, bank Bank, digitableLine string) {
+	p.text(marginLeft, y, 10, true, bank.Name())
+	p.text(marginLeft+150, y, 14, true, bank.Code()+"-"+bankDigits[bank.Code()])
+	p.text(marginLeft+215, y, 11, true, digitableLine)
+	p.line(marginLeft, y-4, marginLeft+contentWidth, y-4)
+}
+
+// row desenha uma linha de campos e retorna a coordenada da próxima linha
+func (p *pdfPage) row(y float64, cells []cell) float64 {
+	const height = 22.0
+	x := marginLeft
+	for _, c := range cells {
+		if c.label != "" {
+			p.text(x+2, y-7, 6, false, c.label)
+			p.text(x+2, y-18, 9, false, c.value)
+		}
+		x += c.width
+		if x < marginLeft+contentWidth {
+			p.line(x, y, x, y-height)
+		}
+	}
+	if cells[0].label != "" {
+		p.line(marginLeft, y-height, marginLeft+contentWidth, y-height)
+	} else {
+		p.line(marginLeft+contentWidth-cells[len(cells)-1].width, y-height, marginLeft+contentWidth, y-height)
+	}
+	return y - height
+}
+
+// barcode desenha o código de barras Interleaved 2 of 5 do boleto
+func (p *pdfPage) barcode(x, y float64, digits string) {
+	// Início: barra e espaço estreitos, duas vezes
+	for i := 0; i < 2; i++ {
+		p.rect(x, y, narrowBar, barcodeH)
+		x += narrowBar * 2
+	}
+
+	for i := 0; i+1 < len(digits); i += 2 {
+		bars := i25Patterns[digits[i]-'0']
+		spaces := i25Patterns[digits[i+1]-'0']
+		for j := 0; j < 5; j++ {
+			width := barWidth(bars[j])
+			p.rect(x, y, width, barcodeH)
+			x += width + barWidth(spaces[j])
+		}
+	}
+
+	// Fim: barra larga, espaço estreito e barra estreita
+	p.rect(x, y, wideBar, barcodeH)
+	x += wideBar + narrowBar
+	p.rect(x, y, narrowBar, barcodeH)
+}
+
+// document monta o arquivo PDF com uma página e as fontes Helvetica
+func (p *pdfPage) document() []byte {
+	var out bytes.Buffer
+	offsets := make([]int, 0, 6)
+
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 4 0 R /F2 5 0 R >> >> /Contents 6 0 R >>", pageWidth, pageHeight))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", p.content.Len(), p.content.String()))
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+
+	return out.Bytes()
+}
+
+func barWidth(kind byte) float64 {
+	if kind == 'w' {
+		return wideBar
+	}
+	return narrowBar
+}
+
+// escapePDF converte o texto para WinAnsi (Latin-1) e escapa os caracteres reservados
+func escapePDF(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 256:
+			sb.WriteByte(byte(r))
+		default:
+			sb.WriteByte('?')
+		}
+	}
+	return sb.String()
+}
+
+// formatDate formata uma data no padrão DD/MM/AAAA
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format("02/01/2006")
+}
+
+// formatMoney formata um valor no padrão brasileiro (1.234,56)
+func formatMoney(value float64) string {
+	raw := fmt.Sprintf("%.2f", value)
+	integer, decimals := raw[:len(raw)-3], raw[len(raw)-2:]
+
+	var sb strings.Builder
+	for i, digit := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			sb.WriteByte('.')
+		}
+		sb.WriteRune(digit)
+	}
+	return sb.String() + "," + decimals
+}
@@ -0,0 +1,112 @@
+package cep
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/hugohenrick/erp-supermercado/pkg/ibge"
+)
+
+var (
+	ErrInvalidZipCode  = errors.New("CEP inválido, informe 8 dígitos")
+	ErrZipCodeNotFound = errors.New("CEP não encontrado")
+	ErrUnavailable     = errors.New("serviço de consulta de CEP indisponível")
+)
+
+// Address representa o endereço devolvido pela consulta de CEP
+type Address struct {
+	ZipCode    string `json:"zip_code"`
+	Street     string `json:"street"`
+	Complement string `json:"complement"`
+	District   string `json:"district"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	CityCode   string `json:"city_code"`  // Código IBGE do município
+	StateCode  string `json:"state_code"` // Código IBGE da UF
+}
+
+// Provider define um serviço de consulta de endereço pelo CEP
+type Provider interface {
+	// Name retorna o identificador do provedor
+	Name() string
+
+	// Lookup busca o endereço do CEP informado, com ou sem máscara
+	Lookup(ctx context.Context, zipCode string) (*Address, error)
+}
+
+// Normalize remove a máscara do CEP e confere se restaram 8 dígitos
+func Normalize(zipCode string) (string, error) {
+	var sb strings.Builder
+	for _, r := range zipCode {
+		switch {
+		case r >= '0' && r <= '9':
+			sb.WriteRune(r)
+		case r == '-' || r == '.' || r == ' ':
+		default:
+			return "", ErrInvalidZipCode
+		}
+	}
+	if sb.Len() != 8 {
+		return "", ErrInvalidZipCode
+	}
+	return sb.String(), nil
+}
+
+// Format aplica a máscara 00000-000 ao CEP
+func Format(zipCode string) string {
+	normalized, err := Normalize(zipCode)
+	if err != nil {
+		return zipCode
+	}
+	return normalized[:5] + "-" + normalized[5:]
+}
+
+// fillCodes completa os códigos IBGE que o provedor não devolveu a partir da tabela embarcada
+func fillCodes(a *Address) {
+	if a.StateCode == "" {
+		if s, ok := ibge.StateByUF(a.State); ok {
+			a.StateCode = s.Code
+		}
+	}
+	if a.CityCode == "" {
+		if m, ok := ibge.FindMunicipality(a.City, a.State); ok {
+			a.CityCode = m.Code
+		}
+	}
+}
+
+// Complete preenche os campos vazios do endereço: os códigos IBGE vêm da tabela embarcada pela cidade
+// e UF e, quando faltam a cidade ou o código do município, o provedor é consultado pelo CEP. Campos
+// já informados nunca são sobrescritos
+func Complete(ctx context.Context, provider Provider, a *Address) error {
+	fillCodes(a)
+	if (a.City != "" && a.CityCode != "") || provider == nil || a.ZipCode == "" {
+		return nil
+	}
+
+	found, err := provider.Lookup(ctx, a.ZipCode)
+	if err != nil {
+		return err
+	}
+
+	// O código do município só é aproveitado quando a cidade informada é a mesma do CEP
+	sameCity := a.City == "" || ibge.SameName(a.City, found.City)
+	setIfEmpty(&a.Street, found.Street)
+	setIfEmpty(&a.District, found.District)
+	setIfEmpty(&a.City, found.City)
+	setIfEmpty(&a.State, found.State)
+	if sameCity {
+		setIfEmpty(&a.CityCode, found.CityCode)
+	}
+	setIfEmpty(&a.StateCode, found.StateCode)
+	fillCodes(a)
+	return nil
+}
+
+// setIfEmpty atribui o valor apenas a campos vazios
+func setIfEmpty(field *string, value string) {
+	if *field == "" {
+		*field = value
+	}
+}
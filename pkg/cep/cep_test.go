@@ -0,0 +1,148 @@
+package cep
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+// countingProvider registra as consultas feitas ao provedor local
+type countingProvider struct {
+	*FixtureProvider
+	lookups int
+}
+
+func (p *countingProvider) Lookup(ctx context.Context, zipCode string) (*Address, error) {
+	p.lookups++
+	return p.FixtureProvider.Lookup(ctx, zipCode)
+}
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    string
+		wantErr error
+	}{
+		{"01001-000", "01001000", nil},
+		{"01.001-000", "01001000", nil},
+		{" 01001000 ", "01001000", nil},
+		{"0100100", "", ErrInvalidZipCode},
+		{"010010000", "", ErrInvalidZipCode},
+		{"01001-00A", "", ErrInvalidZipCode},
+		{"", "", ErrInvalidZipCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := Normalize(tt.value)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Normalize(%q) erro = %v, esperado %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Normalize(%q) = %s, esperado %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestComplete(t *testing.T) {
+	fixtures := append([]Address{
+		{ZipCode: "74000-000", Street: "Avenida Goiás", District: "Setor Central", City: "Goiânia", State: "GO"},
+		{ZipCode: "13010-000", Street: "Rua Barão de Jaguara", District: "Centro", City: "Campinas", State: "SP", CityCode: "3509502"},
+	}, FixtureAddresses...)
+
+	tests := []struct {
+		name        string
+		address     Address
+		want        Address
+		wantLookups int
+		wantErr     error
+	}{
+		{
+			name:        "somente o CEP completa endereço e códigos IBGE",
+			address:     Address{ZipCode: "01001-000"},
+			want:        Address{ZipCode: "01001-000", Street: "Praça da Sé", District: "Sé", City: "São Paulo", State: "SP", CityCode: "3550308", StateCode: "35"},
+			wantLookups: 1,
+		},
+		{
+			name:        "cidade e UF informadas dispensam a consulta",
+			address:     Address{ZipCode: "74000-000", City: "goiania", State: "go"},
+			want:        Address{ZipCode: "74000-000", City: "goiania", State: "go", CityCode: "5208707", StateCode: "52"},
+			wantLookups: 0,
+		},
+		{
+			name:        "campos informados não são sobrescritos",
+			address:     Address{ZipCode: "74000000", Street: "Rua 1", City: "Goiânia"},
+			want:        Address{ZipCode: "74000000", Street: "Rua 1", District: "Setor Central", City: "Goiânia", State: "GO", CityCode: "5208707", StateCode: "52"},
+			wantLookups: 1,
+		},
+		{
+			name:        "cidade diferente da do CEP não recebe o código do município",
+			address:     Address{ZipCode: "13010-000", City: "Cidade Inexistente"},
+			want:        Address{ZipCode: "13010-000", Street: "Rua Barão de Jaguara", District: "Centro", City: "Cidade Inexistente", State: "SP", StateCode: "35"},
+			wantLookups: 1,
+		},
+		{
+			name:        "sem CEP não há consulta",
+			address:     Address{City: "Cidade Inexistente", State: "SP"},
+			want:        Address{City: "Cidade Inexistente", State: "SP", StateCode: "35"},
+			wantLookups: 0,
+		},
+		{
+			name:        "CEP não cadastrado",
+			address:     Address{ZipCode: "99999-999"},
+			wantLookups: 1,
+			wantErr:     ErrZipCodeNotFound,
+		},
+		{
+			name:        "CEP inválido",
+			address:     Address{ZipCode: "123"},
+			wantLookups: 1,
+			wantErr:     ErrInvalidZipCode,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			provider := &countingProvider{FixtureProvider: NewFixtureProvider(fixtures...)}
+			address := tt.address
+
+			err := Complete(context.Background(), provider, &address)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Complete() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if provider.lookups != tt.wantLookups {
+				t.Errorf("Complete() fez %d consultas, esperado %d", provider.lookups, tt.wantLookups)
+			}
+			if tt.wantErr == nil && address != tt.want {
+				t.Errorf("Complete() =\n%+v\nesperado\n%+v", address, tt.want)
+			}
+		})
+	}
+}
+
+func TestFixtureProviderLookup(t *testing.T) {
+	provider := NewFixtureProvider(FixtureAddresses...)
+
+	tests := []struct {
+		zipCode string
+		wantErr error
+	}{
+		{"01001-000", nil},
+		{"01001000", nil},
+		{"01001-001", ErrZipCodeNotFound},
+		{"abc", ErrInvalidZipCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.zipCode, func(t *testing.T) {
+			got, err := provider.Lookup(context.Background(), tt.zipCode)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Lookup(%q) erro = %v, esperado %v", tt.zipCode, err, tt.wantErr)
+			}
+			if err == nil && (got.ZipCode != "01001-000" || got.CityCode != "3550308" || got.StateCode != "35") {
+				t.Errorf("Lookup(%q) = %+v", tt.zipCode, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package cep
+
+import (
+	"context"
+	"sync"
+)
+
+// FixtureAddresses são os CEPs carregados pelo provedor local em ambientes de desenvolvimento
+var FixtureAddresses = []Address{
+	{ZipCode: "01001-000", Street: "Praça da Sé", Complement: "lado ímpar", District: "Sé", City: "São Paulo", State: "SP"},
+}
+
+// FixtureProvider atende consultas de CEP a partir de endereços em memória, para testes e
+// ambientes sem acesso ao serviço externo
+type FixtureProvider struct {
+	mu        sync.RWMutex
+	addresses map[string]Address
+}
+
+// NewFixtureProvider cria um provedor local com os endereços informados
+func NewFixtureProvider(addresses ...Address) *FixtureProvider {
+	p := &FixtureProvider{addresses: make(map[string]Address)}
+	for _, a := range addresses {
+		p.Add(a)
+	}
+	return p
+}
+
+// Name implementa Provider.Name
+func (p *FixtureProvider) Name() string {
+	return "fixture"
+}
+
+// Add inclui ou substitui o endereço de um CEP; endereços com CEP inválido são ignorados
+func (p *FixtureProvider) Add(address Address) {
+	normalized, err := Normalize(address.ZipCode)
+	if err != nil {
+		return
+	}
+	address.ZipCode = Format(normalized)
+	fillCodes(&address)
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.addresses[normalized] = address
+}
+
+// Lookup implementa Provider.Lookup
+func (p *FixtureProvider) Lookup(ctx context.Context, zipCode string) (*Address, error) {
+	normalized, err := Normalize(zipCode)
+	if err != nil {
+		return nil, err
+	}
+
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	address, ok := p.addresses[normalized]
+	if !ok {
+		return nil, ErrZipCodeNotFound
+	}
+	return &address, nil
+}
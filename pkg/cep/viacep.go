@@ -0,0 +1,102 @@
+package cep
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// DefaultViaCEPURL é o endereço público do ViaCEP
+const DefaultViaCEPURL = "https://viacep.com.br/ws"
+
+// ViaCEPProvider consulta serviços no formato do ViaCEP: GET {base}/{cep}/json/
+type ViaCEPProvider struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewViaCEPProvider cria um provedor ViaCEP; sem URL usa o serviço público e sem cliente usa timeout de 5 segundos
+func NewViaCEPProvider(baseURL string, client *http.Client) *ViaCEPProvider {
+	if baseURL == "" {
+		baseURL = DefaultViaCEPURL
+	}
+	if client == nil {
+		client = &http.Client{Timeout: 5 * time.Second}
+	}
+	return &ViaCEPProvider{baseURL: strings.TrimRight(baseURL, "/"), client: client}
+}
+
+// Name implementa Provider.Name
+func (p *ViaCEPProvider) Name() string {
+	return "viacep"
+}
+
+// viaCEPResponse é o corpo devolvido pelo ViaCEP; CEP inexistente vem com "erro" verdadeiro
+// (booleano nas versões antigas e texto "true" nas atuais)
+type viaCEPResponse struct {
+	CEP         string          `json:"cep"`
+	Logradouro  string          `json:"logradouro"`
+	Complemento string          `json:"complemento"`
+	Bairro      string          `json:"bairro"`
+	Localidade  string          `json:"localidade"`
+	UF          string          `json:"uf"`
+	IBGE        string          `json:"ibge"`
+	Erro        json.RawMessage `json:"erro"`
+}
+
+// Lookup implementa Provider.Lookup
+func (p *ViaCEPProvider) Lookup(ctx context.Context, zipCode string) (*Address, error) {
+	normalized, err := Normalize(zipCode)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/%s/json/", p.baseURL, normalized), nil)
+	if err != nil {
+		return nil, fmt.Errorf("falha ao montar consulta de CEP: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<16))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		return nil, ErrInvalidZipCode
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrZipCodeNotFound
+	case resp.StatusCode != http.StatusOK:
+		return nil, fmt.Errorf("%w: status %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	var payload viaCEPResponse
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: resposta inválida: %v", ErrUnavailable, err)
+	}
+	if erro := strings.Trim(string(payload.Erro), `"`); erro != "" && erro != "false" {
+		return nil, ErrZipCodeNotFound
+	}
+
+	address := &Address{
+		ZipCode:    Format(normalized),
+		Street:     payload.Logradouro,
+		Complement: payload.Complemento,
+		District:   payload.Bairro,
+		City:       payload.Localidade,
+		State:      strings.ToUpper(payload.UF),
+		CityCode:   payload.IBGE,
+	}
+	fillCodes(address)
+	return address, nil
+}
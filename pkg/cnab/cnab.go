@@ -0,0 +1,301 @@
+package cnab
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnsupportedLayout = errors.New("layout CNAB não suportado para o banco")
+	ErrEmptyRemittance   = errors.New("remessa sem títulos")
+	ErrInvalidFile       = errors.New("arquivo CNAB inválido")
+	ErrFieldTooLong      = errors.New("campo numérico excede o tamanho do layout")
+)
+
+// Layout identifica o padrão do arquivo CNAB
+type Layout string
+
+const (
+	Layout240 Layout = "240"
+	Layout400 Layout = "400"
+)
+
+// Códigos de ocorrência de retorno comuns aos bancos suportados
+const (
+	OccurrenceRegistered      = "02" // Entrada confirmada
+	OccurrenceRejected        = "03" // Entrada rejeitada
+	OccurrenceSettled         = "06" // Liquidação
+	OccurrenceWrittenOff      = "09" // Baixa
+	OccurrenceSettledAfterOff = "17" // Liquidação após baixa
+)
+
+// Company representa o beneficiário (cedente) da cobrança
+type Company struct {
+	Name         string
+	Document     string // CNPJ/CPF
+	Agency       string
+	AgencyDigit  string
+	Account      string
+	AccountDigit string
+	Wallet       string // Carteira
+	Agreement    string // Convênio / código do beneficiário no banco
+}
+
+// Payer representa o pagador (sacado) de um título
+type Payer struct {
+	Name     string
+	Document string
+	Address  string
+	District string
+	City     string
+	State    string
+	ZipCode  string
+}
+
+// Title representa um título enviado na remessa
+type Title struct {
+	OurNumber      string // Nosso número sem DV
+	OurNumberDigit string
+	DocumentNumber string // Seu número
+	CompanyUse     string // Identificação do título na empresa, devolvida no retorno
+	Amount         float64
+	InterestPerDay float64 // Juros de mora por dia de atraso, em valor
+	FinePercent    float64 // Multa por atraso, em percentual
+	IssueDate      time.Time
+	DueDate        time.Time
+	Payer          Payer
+}
+
+// Remittance representa um arquivo de remessa de cobrança
+type Remittance struct {
+	Layout      Layout
+	BankCode    string
+	BankName    string
+	Sequence    int // Número sequencial do arquivo (NSA)
+	GeneratedAt time.Time
+	Company     Company
+	Titles      []Title
+}
+
+// ReturnEntry representa um título informado no arquivo de retorno
+type ReturnEntry struct {
+	Line           int       `json:"line"`
+	OurNumber      string    `json:"our_number"`
+	CompanyUse     string    `json:"company_use"`
+	DocumentNumber string    `json:"document_number"`
+	Occurrence     string    `json:"occurrence"`
+	Amount         float64   `json:"amount"`      // Valor nominal do título
+	PaidAmount     float64   `json:"paid_amount"` // Valor pago pelo pagador
+	Interest       float64   `json:"interest"`    // Juros e multa recebidos
+	Discount       float64   `json:"discount"`    // Desconto e abatimento concedidos
+	Fees           float64   `json:"fees"`        // Tarifas cobradas pelo banco
+	OccurredAt     time.Time `json:"occurred_at"`
+	CreditedAt     time.Time `json:"credited_at"`
+}
+
+// IsSettlement verifica se a ocorrência representa a liquidação do título
+func (e ReturnEntry) IsSettlement() bool {
+	return e.Occurrence == OccurrenceSettled || e.Occurrence == OccurrenceSettledAfterOff
+}
+
+// Return representa um arquivo de retorno já interpretado
+type Return struct {
+	Layout   Layout        `json:"layout"`
+	BankCode string        `json:"bank_code"`
+	Sequence int           `json:"sequence"`
+	Entries  []ReturnEntry `json:"entries"`
+}
+
+// WriteRemittance gera o conteúdo do arquivo de remessa no layout solicitado
+func WriteRemittance(r *Remittance) ([]byte, error) {
+	if len(r.Titles) == 0 {
+		return nil, ErrEmptyRemittance
+	}
+	if r.GeneratedAt.IsZero() {
+		r.GeneratedAt = time.Now()
+	}
+
+	var lines []string
+	var err error
+	switch r.Layout {
+	case Layout240:
+		lines, err = write240(r)
+	case Layout400:
+		lines, err = write400(r)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedLayout, r.Layout)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Os bancos exigem registros separados por CR+LF
+	return []byte(strings.Join(lines, "\r\n") + "\r\n"), nil
+}
+
+// ParseReturn interpreta um arquivo de retorno CNAB 240 ou 400, identificando o layout pelo tamanho das linhas
+func ParseReturn(data []byte) (*Return, error) {
+	content := strings.ReplaceAll(string(data), "\r\n", "\n")
+	var lines []string
+	for _, line := range strings.Split(content, "\n") {
+		if strings.TrimSpace(line) != "" {
+			lines = append(lines, strings.TrimRight(line, "\r"))
+		}
+	}
+	if len(lines) == 0 {
+		return nil, ErrInvalidFile
+	}
+
+	switch len(lines[0]) {
+	case 240:
+		return parse240(lines)
+	case 400:
+		return parse400(lines)
+	default:
+		return nil, fmt.Errorf("%w: linha com %d posições", ErrInvalidFile, len(lines[0]))
+	}
+}
+
+// record monta um registro de tamanho fixo preenchendo as posições informadas (base 1)
+type record []byte
+
+func newRecord(size int) record {
+	r := make(record, size)
+	for i := range r {
+		r[i] = ' '
+	}
+	return r
+}
+
+// set grava o valor nas posições start..end, inclusive
+func (r record) set(start, end int, value string) {
+	copy(r[start-1:end], value)
+}
+
+// alpha formata um campo alfanumérico: maiúsculo, sem acentos, alinhado à esquerda
+func alpha(value string, size int) string {
+	value = strings.ToUpper(removeAccents(value))
+	if len(value) > size {
+		return value[:size]
+	}
+	return value + strings.Repeat(" ", size-len(value))
+}
+
+// num formata um campo numérico alinhado à direita com zeros
+func num(value string, size int) (string, error) {
+	digits := onlyDigits(value)
+	if len(digits) > size {
+		return "", fmt.Errorf("%w: %s", ErrFieldTooLong, value)
+	}
+	return strings.Repeat("0", size-len(digits)) + digits, nil
+}
+
+// mustNum formata números gerados internamente, que sempre cabem no campo
+func mustNum(value int, size int) string {
+	return fmt.Sprintf("%0*d", size, value)
+}
+
+// money formata um valor monetário em centavos, alinhado à direita
+func money(value float64, size int) string {
+	return fmt.Sprintf("%0*d", size, int64(math.Round(value*100)))
+}
+
+// date8 formata datas no padrão DDMMAAAA, com zeros quando vazia
+func date8(t time.Time) string {
+	if t.IsZero() {
+		return "00000000"
+	}
+	return t.Format("02012006")
+}
+
+// date6 formata datas no padrão DDMMAA, com zeros quando vazia
+func date6(t time.Time) string {
+	if t.IsZero() {
+		return "000000"
+	}
+	return t.Format("020106")
+}
+
+// documentType retorna 1 para CPF e 2 para CNPJ
+func documentType(document string) string {
+	if len(onlyDigits(document)) == 11 {
+		return "1"
+	}
+	return "2"
+}
+
+// field lê as posições start..end (base 1) de uma linha
+func field(line string, start, end int) string {
+	if len(line) < end {
+		return ""
+	}
+	return line[start-1 : end]
+}
+
+// parseMoney lê um valor em centavos
+func parseMoney(value string) float64 {
+	var cents int64
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			cents = cents*10 + int64(r-'0')
+		}
+	}
+	return float64(cents) / 100
+}
+
+// parseDate lê datas nos formatos DDMMAAAA ou DDMMAA
+func parseDate(value string) time.Time {
+	layout := "02012006"
+	if len(value) == 6 {
+		layout = "020106"
+	}
+	t, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		return time.Time{}
+	}
+	return t
+}
+
+// onlyDigits remove todos os caracteres não numéricos
+func onlyDigits(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+	"º", "o", "ª", "a",
+)
+
+// removeAccents substitui caracteres acentuados, que não são aceitos nos arquivos CNAB
+func removeAccents(value string) string {
+	value = accents.Replace(value)
+	var sb strings.Builder
+	for _, r := range value {
+		if r < 128 {
+			sb.WriteRune(r)
+		} else {
+			sb.WriteByte(' ')
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,363 @@
+package cnab
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// profile240 reúne as particularidades de cada banco sobre o layout FEBRABAN 240
+type profile240 struct {
+	fileVersion  string
+	batchVersion string
+	agreement    func(c Company) (string, error)          // Campo "código do convênio" (20 posições)
+	ourNumber    func(c Company, t Title) (string, error) // Campo "nosso número" do segmento P (20 posições)
+}
+
+var profiles240 = map[string]profile240{
+	"001": {
+		fileVersion:  "083",
+		batchVersion: "042",
+		agreement: func(c Company) (string, error) {
+			// Convênio(9) + "0014" cobrança + carteira(2) + variação(3) + brancos(2)
+			agreement, err := num(c.Agreement, 9)
+			if err != nil {
+				return "", err
+			}
+			wallet, err := num(c.Wallet, 2)
+			if err != nil {
+				return "", err
+			}
+			return agreement + "0014" + wallet + "019" + "  ", nil
+		},
+		ourNumber: func(c Company, t Title) (string, error) {
+			agreement := onlyDigits(c.Agreement)
+			size := 17 - len(agreement)
+			if len(agreement) != 7 {
+				size = 11 - len(agreement)
+			}
+			if size <= 0 {
+				return "", fmt.Errorf("%w: convênio", ErrFieldTooLong)
+			}
+			sequence, err := num(t.OurNumber, size)
+			if err != nil {
+				return "", err
+			}
+			return alpha(agreement+sequence+t.OurNumberDigit, 20), nil
+		},
+	},
+	"033": {
+		fileVersion:  "040",
+		batchVersion: "030",
+		agreement:    agreementOnly,
+		ourNumber: func(c Company, t Title) (string, error) {
+			ourNumber, err := num(t.OurNumber, 12)
+			if err != nil {
+				return "", err
+			}
+			return alpha(ourNumber+t.OurNumberDigit, 20), nil
+		},
+	},
+	"104": {
+		fileVersion:  "101",
+		batchVersion: "060",
+		agreement:    agreementOnly,
+		ourNumber: func(c Company, t Title) (string, error) {
+			// Modalidade "14" (registrada, emissão pelo beneficiário) + nosso número(15)
+			ourNumber, err := num(t.OurNumber, 15)
+			if err != nil {
+				return "", err
+			}
+			return alpha("14"+ourNumber, 20), nil
+		},
+	},
+	"237": {
+		fileVersion:  "084",
+		batchVersion: "042",
+		agreement:    agreementOnly,
+		ourNumber: func(c Company, t Title) (string, error) {
+			// Carteira(3) + zeros(5) + nosso número(11) + DV(1)
+			wallet, err := num(c.Wallet, 3)
+			if err != nil {
+				return "", err
+			}
+			ourNumber, err := num(t.OurNumber, 11)
+			if err != nil {
+				return "", err
+			}
+			return wallet + "00000" + ourNumber + alpha(t.OurNumberDigit, 1), nil
+		},
+	},
+	"341": {
+		fileVersion:  "040",
+		batchVersion: "030",
+		agreement:    agreementOnly,
+		ourNumber: func(c Company, t Title) (string, error) {
+			// Carteira(3) + nosso número(8) + DAC(1) + brancos(8)
+			wallet, err := num(c.Wallet, 3)
+			if err != nil {
+				return "", err
+			}
+			ourNumber, err := num(t.OurNumber, 8)
+			if err != nil {
+				return "", err
+			}
+			return alpha(wallet+ourNumber+t.OurNumberDigit, 20), nil
+		},
+	},
+}
+
+// agreementOnly usa o convênio alinhado à esquerda como código do beneficiário
+func agreementOnly(c Company) (string, error) {
+	return alpha(onlyDigits(c.Agreement), 20), nil
+}
+
+// write240 monta as linhas do arquivo de remessa no layout FEBRABAN 240 (um lote de cobrança)
+func write240(r *Remittance) ([]string, error) {
+	profile, ok := profiles240[r.BankCode]
+	if !ok {
+		return nil, fmt.Errorf("%w: CNAB 240 banco %s", ErrUnsupportedLayout, r.BankCode)
+	}
+
+	c := r.Company
+	document, err := num(c.Document, 14)
+	if err != nil {
+		return nil, err
+	}
+	agreement, err := profile.agreement(c)
+	if err != nil {
+		return nil, err
+	}
+	agency, err := num(c.Agency, 5)
+	if err != nil {
+		return nil, err
+	}
+	account, err := num(c.Account, 12)
+	if err != nil {
+		return nil, err
+	}
+
+	// Conta do beneficiário usada em todos os registros: agência(5) + DV + conta(12) + DV + DV ag/conta
+	bankAccount := agency + alpha(c.AgencyDigit, 1) + account + alpha(c.AccountDigit, 1) + " "
+
+	lines := make([]string, 0, len(r.Titles)*3+4)
+
+	// Header de arquivo
+	h := newRecord(240)
+	h.set(1, 3, r.BankCode)
+	h.set(4, 7, "0000")
+	h.set(8, 8, "0")
+	h.set(18, 18, documentType(c.Document))
+	h.set(19, 32, document)
+	h.set(33, 52, agreement)
+	h.set(53, 72, bankAccount)
+	h.set(73, 102, alpha(c.Name, 30))
+	h.set(103, 132, alpha(r.BankName, 30))
+	h.set(143, 143, "1")
+	h.set(144, 151, date8(r.GeneratedAt))
+	h.set(152, 157, r.GeneratedAt.Format("150405"))
+	h.set(158, 163, mustNum(r.Sequence, 6))
+	h.set(164, 166, profile.fileVersion)
+	h.set(167, 171, "00000")
+	lines = append(lines, string(h))
+
+	// Header de lote
+	l := newRecord(240)
+	l.set(1, 3, r.BankCode)
+	l.set(4, 7, "0001")
+	l.set(8, 8, "1")
+	l.set(9, 9, "R")
+	l.set(10, 11, "01")
+	l.set(14, 16, profile.batchVersion)
+	l.set(18, 18, documentType(c.Document))
+	l.set(19, 33, "0"+document)
+	l.set(34, 53, agreement)
+	l.set(54, 73, bankAccount)
+	l.set(74, 103, alpha(c.Name, 30))
+	l.set(184, 191, mustNum(r.Sequence, 8))
+	l.set(192, 199, date8(r.GeneratedAt))
+	l.set(200, 207, "00000000")
+	lines = append(lines, string(l))
+
+	sequence := 0
+	var total float64
+	for _, t := range r.Titles {
+		segments, err := segments240(r.BankCode, profile, c, bankAccount, t, &sequence)
+		if err != nil {
+			return nil, fmt.Errorf("título %s: %w", t.DocumentNumber, err)
+		}
+		lines = append(lines, segments...)
+		total += t.Amount
+	}
+
+	// Trailer de lote: header + detalhes + trailer
+	tl := newRecord(240)
+	tl.set(1, 3, r.BankCode)
+	tl.set(4, 7, "0001")
+	tl.set(8, 8, "5")
+	tl.set(18, 23, mustNum(sequence+2, 6))
+	tl.set(24, 29, mustNum(len(r.Titles), 6))
+	tl.set(30, 46, money(total, 17))
+	tl.set(47, 115, strings.Repeat("0", 69))
+	lines = append(lines, string(tl))
+
+	// Trailer de arquivo
+	ta := newRecord(240)
+	ta.set(1, 3, r.BankCode)
+	ta.set(4, 7, "9999")
+	ta.set(8, 8, "9")
+	ta.set(18, 23, "000001")
+	ta.set(24, 29, mustNum(len(lines)+1, 6))
+	ta.set(30, 35, "000000")
+	lines = append(lines, string(ta))
+
+	return lines, nil
+}
+
+// segments240 monta os segmentos P, Q e, quando há multa, R de um título
+func segments240(bankCode string, profile profile240, c Company, bankAccount string, t Title, sequence *int) ([]string, error) {
+	ourNumber, err := profile.ourNumber(c, t)
+	if err != nil {
+		return nil, err
+	}
+	payerDocument, err := num(t.Payer.Document, 15)
+	if err != nil {
+		return nil, err
+	}
+	zipCode, err := num(t.Payer.ZipCode, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := func(segment string) record {
+		*sequence++
+		d := newRecord(240)
+		d.set(1, 3, bankCode)
+		d.set(4, 7, "0001")
+		d.set(8, 8, "3")
+		d.set(9, 13, mustNum(*sequence, 5))
+		d.set(14, 14, segment)
+		d.set(16, 17, "01") // Entrada de títulos
+		return d
+	}
+
+	// Segmento P: dados do título
+	p := detail("P")
+	p.set(18, 37, bankAccount)
+	p.set(38, 57, ourNumber)
+	p.set(58, 58, "1") // Cobrança simples
+	p.set(59, 59, "1") // Título registrado
+	p.set(60, 60, "1") // Documento tradicional
+	p.set(61, 61, "2") // Boleto emitido pelo beneficiário
+	p.set(62, 62, "2") // Distribuição pelo beneficiário
+	p.set(63, 77, alpha(t.DocumentNumber, 15))
+	p.set(78, 85, date8(t.DueDate))
+	p.set(86, 100, money(t.Amount, 15))
+	p.set(101, 106, "000000")
+	p.set(107, 108, "02") // Duplicata mercantil
+	p.set(109, 109, "N")
+	p.set(110, 117, date8(t.IssueDate))
+	if t.InterestPerDay > 0 {
+		p.set(118, 118, "1") // Valor por dia
+		p.set(119, 126, date8(t.DueDate.AddDate(0, 0, 1)))
+	} else {
+		p.set(118, 118, "3") // Isento
+		p.set(119, 126, "00000000")
+	}
+	p.set(127, 141, money(t.InterestPerDay, 15))
+	p.set(142, 142, "0")
+	p.set(143, 150, "00000000")
+	p.set(151, 195, strings.Repeat("0", 45)) // Desconto, IOF e abatimento
+	p.set(196, 220, alpha(t.CompanyUse, 25))
+	p.set(221, 221, "3") // Não protestar
+	p.set(222, 223, "00")
+	p.set(224, 224, "0")
+	p.set(225, 227, "000")
+	p.set(228, 229, "09") // Real
+	p.set(230, 239, "0000000000")
+
+	// Segmento Q: dados do pagador
+	q := detail("Q")
+	q.set(18, 18, documentType(t.Payer.Document))
+	q.set(19, 33, payerDocument)
+	q.set(34, 73, alpha(t.Payer.Name, 40))
+	q.set(74, 113, alpha(t.Payer.Address, 40))
+	q.set(114, 128, alpha(t.Payer.District, 15))
+	q.set(129, 136, zipCode)
+	q.set(137, 151, alpha(t.Payer.City, 15))
+	q.set(152, 153, alpha(t.Payer.State, 2))
+	q.set(154, 154, "0")
+	q.set(155, 169, strings.Repeat("0", 15))
+	q.set(210, 212, "000")
+
+	segments := []string{string(p), string(q)}
+	if t.FinePercent <= 0 {
+		return segments, nil
+	}
+
+	// Segmento R: multa percentual a partir do dia seguinte ao vencimento
+	rs := detail("R")
+	rs.set(18, 18, "0")
+	rs.set(19, 41, strings.Repeat("0", 23))
+	rs.set(42, 42, "0")
+	rs.set(43, 65, strings.Repeat("0", 23))
+	rs.set(66, 66, "2")
+	rs.set(67, 74, date8(t.DueDate.AddDate(0, 0, 1)))
+	rs.set(75, 89, money(t.FinePercent, 15))
+	rs.set(200, 215, strings.Repeat("0", 16))
+	rs.set(217, 228, strings.Repeat("0", 12))
+	rs.set(231, 231, "0")
+
+	return append(segments, string(rs)), nil
+}
+
+// parse240 interpreta os segmentos T e U de um arquivo de retorno CNAB 240
+func parse240(lines []string) (*Return, error) {
+	header := lines[0]
+	if field(header, 8, 8) != "0" || field(header, 143, 143) != "2" {
+		return nil, fmt.Errorf("%w: header de retorno CNAB 240 não encontrado", ErrInvalidFile)
+	}
+
+	ret := &Return{
+		Layout:   Layout240,
+		BankCode: field(header, 1, 3),
+		Entries:  []ReturnEntry{},
+	}
+	ret.Sequence, _ = strconv.Atoi(field(header, 158, 163))
+
+	var current *ReturnEntry
+	for i, line := range lines {
+		if len(line) != 240 {
+			return nil, fmt.Errorf("%w: linha %d com %d posições", ErrInvalidFile, i+1, len(line))
+		}
+		if field(line, 8, 8) != "3" {
+			continue
+		}
+
+		switch field(line, 14, 14) {
+		case "T":
+			ret.Entries = append(ret.Entries, ReturnEntry{
+				Line:           i + 1,
+				Occurrence:     field(line, 16, 17),
+				OurNumber:      strings.TrimSpace(field(line, 38, 57)),
+				DocumentNumber: strings.TrimSpace(field(line, 59, 73)),
+				Amount:         parseMoney(field(line, 82, 96)),
+				CompanyUse:     strings.TrimSpace(field(line, 106, 130)),
+				Fees:           parseMoney(field(line, 199, 213)),
+			})
+			current = &ret.Entries[len(ret.Entries)-1]
+		case "U":
+			if current == nil {
+				continue
+			}
+			current.Interest = parseMoney(field(line, 18, 32))
+			current.Discount = parseMoney(field(line, 33, 47)) + parseMoney(field(line, 48, 62))
+			current.PaidAmount = parseMoney(field(line, 78, 92))
+			current.OccurredAt = parseDate(field(line, 138, 145))
+			current.CreditedAt = parseDate(field(line, 146, 153))
+			current = nil
+		}
+	}
+
+	return ret, nil
+}
@@ -0,0 +1,279 @@
+package cnab
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// profile400 reúne as particularidades de cada banco sobre o layout de 400 posições
+type profile400 struct {
+	companyCode    func(c Company) (string, error) // Posições 027-046 do header
+	detail         func(c Company, t Title) (record, error)
+	ourNumberStart int // Posição do nosso número no registro de retorno
+	ourNumberEnd   int
+}
+
+var profiles400 = map[string]profile400{
+	"237": {
+		companyCode: func(c Company) (string, error) {
+			return num(c.Agreement, 20)
+		},
+		detail:         detailBradesco400,
+		ourNumberStart: 71,
+		ourNumberEnd:   82,
+	},
+	"341": {
+		companyCode: func(c Company) (string, error) {
+			// Agência(4) + zeros(2) + conta(5) + DAC(1) + brancos(8)
+			agency, err := num(c.Agency, 4)
+			if err != nil {
+				return "", err
+			}
+			account, err := num(c.Account, 5)
+			if err != nil {
+				return "", err
+			}
+			return agency + "00" + account + alpha(c.AccountDigit, 1) + strings.Repeat(" ", 8), nil
+		},
+		detail:         detailItau400,
+		ourNumberStart: 63,
+		ourNumberEnd:   70,
+	},
+}
+
+// write400 monta as linhas do arquivo de remessa no layout de 400 posições
+func write400(r *Remittance) ([]string, error) {
+	profile, ok := profiles400[r.BankCode]
+	if !ok {
+		return nil, fmt.Errorf("%w: CNAB 400 banco %s", ErrUnsupportedLayout, r.BankCode)
+	}
+
+	companyCode, err := profile.companyCode(r.Company)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]string, 0, len(r.Titles)+2)
+
+	h := newRecord(400)
+	h.set(1, 1, "0")
+	h.set(2, 2, "1")
+	h.set(3, 9, "REMESSA")
+	h.set(10, 11, "01")
+	h.set(12, 26, alpha("COBRANCA", 15))
+	h.set(27, 46, companyCode)
+	h.set(47, 76, alpha(r.Company.Name, 30))
+	h.set(77, 79, r.BankCode)
+	h.set(80, 94, alpha(r.BankName, 15))
+	h.set(95, 100, date6(r.GeneratedAt))
+	if r.BankCode == "237" {
+		h.set(109, 110, "MX")
+		h.set(111, 117, mustNum(r.Sequence, 7))
+	}
+	h.set(395, 400, "000001")
+	lines = append(lines, string(h))
+
+	for _, t := range r.Titles {
+		d, err := profile.detail(r.Company, t)
+		if err != nil {
+			return nil, fmt.Errorf("título %s: %w", t.DocumentNumber, err)
+		}
+		d.set(395, 400, mustNum(len(lines)+1, 6))
+		lines = append(lines, string(d))
+	}
+
+	tr := newRecord(400)
+	tr.set(1, 1, "9")
+	tr.set(395, 400, mustNum(len(lines)+1, 6))
+	lines = append(lines, string(tr))
+
+	return lines, nil
+}
+
+// detailBradesco400 monta o registro de transação tipo 1 do Bradesco
+func detailBradesco400(c Company, t Title) (record, error) {
+	wallet, err := num(c.Wallet, 3)
+	if err != nil {
+		return nil, err
+	}
+	agency, err := num(c.Agency, 5)
+	if err != nil {
+		return nil, err
+	}
+	account, err := num(c.Account, 7)
+	if err != nil {
+		return nil, err
+	}
+	ourNumber, err := num(t.OurNumber, 11)
+	if err != nil {
+		return nil, err
+	}
+	payer, err := payerFields(t.Payer)
+	if err != nil {
+		return nil, err
+	}
+
+	d := newRecord(400)
+	d.set(1, 1, "1")
+	d.set(2, 20, strings.Repeat("0", 19)) // Débito automático não utilizado
+	d.set(21, 37, "0"+wallet+agency+account+alpha(c.AccountDigit, 1))
+	d.set(38, 62, alpha(t.CompanyUse, 25))
+	d.set(63, 65, "000")
+	if t.FinePercent > 0 {
+		d.set(66, 66, "2")
+		d.set(67, 70, money(t.FinePercent, 4))
+	} else {
+		d.set(66, 70, "00000")
+	}
+	d.set(71, 81, ourNumber)
+	d.set(82, 82, alpha(t.OurNumberDigit, 1))
+	d.set(83, 92, strings.Repeat("0", 10))
+	d.set(93, 93, "2") // Boleto emitido pelo beneficiário
+	d.set(94, 94, "N")
+	d.set(106, 106, "2")
+	d.set(109, 110, "01") // Remessa
+	d.set(111, 120, alpha(t.DocumentNumber, 10))
+	d.set(121, 126, date6(t.DueDate))
+	d.set(127, 139, money(t.Amount, 13))
+	d.set(140, 147, "00000000")
+	d.set(148, 149, "01") // Duplicata
+	d.set(150, 150, "N")
+	d.set(151, 156, date6(t.IssueDate))
+	d.set(157, 160, "0000")
+	d.set(161, 173, money(t.InterestPerDay, 13))
+	d.set(174, 218, strings.Repeat("0", 45)) // Desconto, IOF e abatimento
+	d.set(219, 220, "0"+documentType(t.Payer.Document))
+	d.set(221, 234, payer.document)
+	d.set(235, 274, alpha(t.Payer.Name, 40))
+	d.set(275, 314, alpha(strings.TrimSpace(t.Payer.Address+" "+t.Payer.District), 40))
+	d.set(327, 334, payer.zipCode)
+	return d, nil
+}
+
+// detailItau400 monta o registro de transação tipo 1 do Itaú
+func detailItau400(c Company, t Title) (record, error) {
+	document, err := num(c.Document, 14)
+	if err != nil {
+		return nil, err
+	}
+	agency, err := num(c.Agency, 4)
+	if err != nil {
+		return nil, err
+	}
+	account, err := num(c.Account, 5)
+	if err != nil {
+		return nil, err
+	}
+	wallet, err := num(c.Wallet, 3)
+	if err != nil {
+		return nil, err
+	}
+	ourNumber, err := num(t.OurNumber, 8)
+	if err != nil {
+		return nil, err
+	}
+	payer, err := payerFields(t.Payer)
+	if err != nil {
+		return nil, err
+	}
+
+	d := newRecord(400)
+	d.set(1, 1, "1")
+	d.set(2, 3, "0"+documentType(c.Document))
+	d.set(4, 17, document)
+	d.set(18, 21, agency)
+	d.set(22, 23, "00")
+	d.set(24, 28, account)
+	d.set(29, 29, alpha(c.AccountDigit, 1))
+	d.set(34, 37, "0000")
+	d.set(38, 62, alpha(t.CompanyUse, 25))
+	d.set(63, 70, ourNumber)
+	d.set(71, 83, strings.Repeat("0", 13))
+	d.set(84, 86, wallet)
+	d.set(108, 108, "I")
+	d.set(109, 110, "01") // Remessa
+	d.set(111, 120, alpha(t.DocumentNumber, 10))
+	d.set(121, 126, date6(t.DueDate))
+	d.set(127, 139, money(t.Amount, 13))
+	d.set(140, 142, "341")
+	d.set(143, 147, "00000")
+	d.set(148, 149, "01") // Duplicata mercantil
+	d.set(150, 150, "N")
+	d.set(151, 156, date6(t.IssueDate))
+	d.set(157, 160, "0000")
+	d.set(161, 173, money(t.InterestPerDay, 13))
+	d.set(174, 218, strings.Repeat("0", 45)) // Desconto, IOF e abatimento
+	d.set(219, 220, "0"+documentType(t.Payer.Document))
+	d.set(221, 234, payer.document)
+	d.set(235, 264, alpha(t.Payer.Name, 30))
+	d.set(275, 314, alpha(t.Payer.Address, 40))
+	d.set(315, 326, alpha(t.Payer.District, 12))
+	d.set(327, 334, payer.zipCode)
+	d.set(335, 349, alpha(t.Payer.City, 15))
+	d.set(350, 351, alpha(t.Payer.State, 2))
+	d.set(386, 391, "000000")
+	d.set(392, 393, "00")
+	return d, nil
+}
+
+// payerFields formata os campos numéricos do pagador usados no layout de 400 posições
+func payerFields(p Payer) (struct{ document, zipCode string }, error) {
+	var out struct{ document, zipCode string }
+	var err error
+	if out.document, err = num(p.Document, 14); err != nil {
+		return out, err
+	}
+	if out.zipCode, err = num(p.ZipCode, 8); err != nil {
+		return out, err
+	}
+	return out, nil
+}
+
+// parse400 interpreta os registros de transação de um arquivo de retorno de 400 posições
+func parse400(lines []string) (*Return, error) {
+	header := lines[0]
+	if field(header, 1, 2) != "02" {
+		return nil, fmt.Errorf("%w: header de retorno CNAB 400 não encontrado", ErrInvalidFile)
+	}
+
+	ret := &Return{
+		Layout:   Layout400,
+		BankCode: field(header, 77, 79),
+		Entries:  []ReturnEntry{},
+	}
+
+	profile, ok := profiles400[ret.BankCode]
+	if !ok {
+		return nil, fmt.Errorf("%w: CNAB 400 banco %s", ErrUnsupportedLayout, ret.BankCode)
+	}
+	if ret.BankCode == "237" {
+		ret.Sequence, _ = strconv.Atoi(field(header, 109, 113))
+	}
+
+	for i, line := range lines {
+		if len(line) != 400 {
+			return nil, fmt.Errorf("%w: linha %d com %d posições", ErrInvalidFile, i+1, len(line))
+		}
+		if field(line, 1, 1) != "1" {
+			continue
+		}
+
+		ret.Entries = append(ret.Entries, ReturnEntry{
+			Line:           i + 1,
+			CompanyUse:     strings.TrimSpace(field(line, 38, 62)),
+			OurNumber:      strings.TrimSpace(field(line, profile.ourNumberStart, profile.ourNumberEnd)),
+			Occurrence:     field(line, 109, 110),
+			OccurredAt:     parseDate(field(line, 111, 116)),
+			DocumentNumber: strings.TrimSpace(field(line, 117, 126)),
+			Amount:         parseMoney(field(line, 153, 165)),
+			Fees:           parseMoney(field(line, 176, 188)),
+			Discount:       parseMoney(field(line, 228, 240)) + parseMoney(field(line, 241, 253)),
+			PaidAmount:     parseMoney(field(line, 254, 266)),
+			Interest:       parseMoney(field(line, 267, 279)),
+			CreditedAt:     parseDate(field(line, 296, 301)),
+		})
+	}
+
+	return ret, nil
+}
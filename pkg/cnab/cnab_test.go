@@ -0,0 +1,209 @@
+package cnab
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// line monta um registro de tamanho fixo a partir das posições iniciais (base 1) de cada campo
+func line(size int, fields map[int]string) string {
+	r := newRecord(size)
+	for start, value := range fields {
+		r.set(start, start+len(value)-1, value)
+	}
+	return string(r)
+}
+
+func local(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.Local)
+}
+
+func TestParseReturn240(t *testing.T) {
+	header := line(240, map[int]string{1: "341", 8: "0", 143: "2", 158: "000123"})
+	batchHeader := line(240, map[int]string{1: "341", 8: "1"})
+	settledT := line(240, map[int]string{
+		1: "341", 8: "3", 14: "T", 16: "06",
+		38: "10900000042", 59: "NF-42", 82: "000000000010050", 106: "REC-1", 199: "000000000000250",
+	})
+	settledU := line(240, map[int]string{
+		1: "341", 8: "3", 14: "U",
+		18: "000000000000120", 33: "000000000000030", 48: "000000000000020",
+		78: "000000000010120", 138: "10032025", 146: "11032025",
+	})
+	rejectedT := line(240, map[int]string{1: "341", 8: "3", 14: "T", 16: "03", 38: "10900000043", 82: "000000000005000"})
+	trailer := line(240, map[int]string{1: "341", 8: "9"})
+
+	settled := ReturnEntry{
+		Line:           3,
+		Occurrence:     OccurrenceSettled,
+		OurNumber:      "10900000042",
+		DocumentNumber: "NF-42",
+		Amount:         100.50,
+		CompanyUse:     "REC-1",
+		Fees:           2.50,
+		Interest:       1.20,
+		Discount:       0.50,
+		PaidAmount:     101.20,
+		OccurredAt:     local(2025, 3, 10),
+		CreditedAt:     local(2025, 3, 11),
+	}
+	rejected := ReturnEntry{Line: 5, Occurrence: OccurrenceRejected, OurNumber: "10900000043", Amount: 50}
+
+	tests := []struct {
+		name    string
+		content string
+		want    []ReturnEntry
+		wantErr error
+	}{
+		{
+			name:    "liquidação com segmentos T e U, separados por CR+LF",
+			content: strings.Join([]string{header, batchHeader, settledT, settledU, rejectedT, trailer}, "\r\n") + "\r\n",
+			want:    []ReturnEntry{settled, rejected},
+		},
+		{
+			name:    "segmento U sem T é ignorado",
+			content: strings.Join([]string{header, settledU, trailer}, "\n"),
+			want:    []ReturnEntry{},
+		},
+		{
+			name:    "header de remessa em vez de retorno",
+			content: line(240, map[int]string{1: "341", 8: "0", 143: "1"}),
+			wantErr: ErrInvalidFile,
+		},
+		{
+			name:    "linha com tamanho diferente",
+			content: header + "\n" + settledT[:239],
+			wantErr: ErrInvalidFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReturn([]byte(tt.content))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseReturn() erro = %v, esperado %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReturn() erro inesperado: %v", err)
+			}
+			if got.Layout != Layout240 || got.BankCode != "341" || got.Sequence != 123 {
+				t.Errorf("ParseReturn() = layout %s, banco %s, sequência %d", got.Layout, got.BankCode, got.Sequence)
+			}
+			if !reflect.DeepEqual(got.Entries, tt.want) {
+				t.Errorf("ParseReturn() entradas =\n%+v\nesperado\n%+v", got.Entries, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseReturn400(t *testing.T) {
+	detail := func(ourNumberStart int, ourNumber string) string {
+		return line(400, map[int]string{
+			1: "1", 38: "REC-7", ourNumberStart: ourNumber, 109: "17", 111: "050325",
+			117: "DUP-7", 153: "0000000020000", 176: "0000000000190",
+			228: "0000000000100", 241: "0000000000050", 254: "0000000020300",
+			267: "0000000000450", 296: "060325",
+		})
+	}
+	entry := func(ourNumber string) ReturnEntry {
+		return ReturnEntry{
+			Line:           2,
+			CompanyUse:     "REC-7",
+			OurNumber:      ourNumber,
+			Occurrence:     OccurrenceSettledAfterOff,
+			OccurredAt:     local(2025, 3, 5),
+			DocumentNumber: "DUP-7",
+			Amount:         200,
+			Fees:           1.90,
+			Discount:       1.50,
+			PaidAmount:     203,
+			Interest:       4.50,
+			CreditedAt:     local(2025, 3, 6),
+		}
+	}
+	trailer := line(400, map[int]string{1: "9"})
+
+	tests := []struct {
+		name     string
+		content  string
+		bankCode string
+		sequence int
+		want     []ReturnEntry
+		wantErr  error
+	}{
+		{
+			name:     "Itaú",
+			content:  strings.Join([]string{line(400, map[int]string{1: "02", 77: "341"}), detail(63, "00000042"), trailer}, "\r\n"),
+			bankCode: "341",
+			want:     []ReturnEntry{entry("00000042")},
+		},
+		{
+			name:     "Bradesco com número sequencial do arquivo",
+			content:  strings.Join([]string{line(400, map[int]string{1: "02", 77: "237", 109: "00045"}), detail(71, "000000000427"), trailer}, "\n"),
+			bankCode: "237",
+			sequence: 45,
+			want:     []ReturnEntry{entry("000000000427")},
+		},
+		{
+			name:    "banco sem layout de 400 posições",
+			content: line(400, map[int]string{1: "02", 77: "001"}),
+			wantErr: ErrUnsupportedLayout,
+		},
+		{
+			name:    "header de remessa em vez de retorno",
+			content: line(400, map[int]string{1: "01", 77: "341"}),
+			wantErr: ErrInvalidFile,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseReturn([]byte(tt.content))
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseReturn() erro = %v, esperado %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseReturn() erro inesperado: %v", err)
+			}
+			if got.Layout != Layout400 || got.BankCode != tt.bankCode || got.Sequence != tt.sequence {
+				t.Errorf("ParseReturn() = layout %s, banco %s, sequência %d", got.Layout, got.BankCode, got.Sequence)
+			}
+			if !reflect.DeepEqual(got.Entries, tt.want) {
+				t.Errorf("ParseReturn() entradas =\n%+v\nesperado\n%+v", got.Entries, tt.want)
+			}
+			for _, e := range got.Entries {
+				if !e.IsSettlement() {
+					t.Errorf("ocorrência %s deveria ser liquidação", e.Occurrence)
+				}
+			}
+		})
+	}
+}
+
+func TestParseReturnInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"arquivo vazio", ""},
+		{"somente linhas em branco", "\r\n  \r\n"},
+		{"tamanho de linha desconhecido", strings.Repeat("0", 300)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseReturn([]byte(tt.content)); !errors.Is(err, ErrInvalidFile) {
+				t.Errorf("ParseReturn() erro = %v, esperado %v", err, ErrInvalidFile)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package document
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid é a causa comum de todo documento rejeitado; use errors.Is para identificá-lo
+var ErrInvalid = errors.New("documento inválido")
+
+// Type define o tipo do documento
+type Type string
+
+const (
+	TypeCPF  Type = "CPF"
+	TypeCNPJ Type = "CNPJ"
+)
+
+// Error descreve o documento rejeitado e o motivo
+type Error struct {
+	Document string
+	Expected Type // Tipo exigido pelo cadastro; vazio quando CPF e CNPJ são aceitos
+	Reason   string
+}
+
+func (e *Error) Error() string {
+	if e.Expected != "" {
+		return fmt.Sprintf("%s inválido (%s): %s", e.Expected, e.Document, e.Reason)
+	}
+	return fmt.Sprintf("documento inválido (%s): %s", e.Document, e.Reason)
+}
+
+// Unwrap permite errors.Is(err, ErrInvalid)
+func (e *Error) Unwrap() error {
+	return ErrInvalid
+}
+
+// Normalize remove a máscara (pontos, traços, barras e espaços) e converte letras para maiúsculas,
+// forma em que CPF e CNPJ são gravados
+func Normalize(value string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToUpper(value) {
+		if (r >= '0' && r <= '9') || (r >= 'A' && r <= 'Z') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// Validate normaliza e valida um CPF ou CNPJ, identificando o tipo pelo tamanho
+func Validate(value string) (string, Type, error) {
+	normalized := Normalize(value)
+	switch len(normalized) {
+	case 11:
+		if err := validateCPF(normalized); err != nil {
+			return "", "", &Error{Document: value, Reason: err.Error()}
+		}
+		return normalized, TypeCPF, nil
+	case 14:
+		if err := validateCNPJ(normalized); err != nil {
+			return "", "", &Error{Document: value, Reason: err.Error()}
+		}
+		return normalized, TypeCNPJ, nil
+	}
+	return "", "", &Error{Document: value, Reason: "deve ter 11 (CPF) ou 14 (CNPJ) caracteres"}
+}
+
+// ValidateCPF normaliza e valida um CPF
+func ValidateCPF(value string) (string, error) {
+	normalized := Normalize(value)
+	if len(normalized) != 11 {
+		return "", &Error{Document: value, Expected: TypeCPF, Reason: "deve ter 11 dígitos"}
+	}
+	if err := validateCPF(normalized); err != nil {
+		return "", &Error{Document: value, Expected: TypeCPF, Reason: err.Error()}
+	}
+	return normalized, nil
+}
+
+// ValidateCNPJ normaliza e valida um CNPJ numérico ou alfanumérico
+func ValidateCNPJ(value string) (string, error) {
+	normalized := Normalize(value)
+	if len(normalized) != 14 {
+		return "", &Error{Document: value, Expected: TypeCNPJ, Reason: "deve ter 14 caracteres"}
+	}
+	if err := validateCNPJ(normalized); err != nil {
+		return "", &Error{Document: value, Expected: TypeCNPJ, Reason: err.Error()}
+	}
+	return normalized, nil
+}
+
+// Format aplica a máscara de CPF (000.000.000-00) ou CNPJ (00.000.000/0000-00) ao documento normalizado
+func Format(value string) string {
+	normalized := Normalize(value)
+	switch len(normalized) {
+	case 11:
+		return normalized[0:3] + "." + normalized[3:6] + "." + normalized[6:9] + "-" + normalized[9:]
+	case 14:
+		return normalized[0:2] + "." + normalized[2:5] + "." + normalized[5:8] + "/" + normalized[8:12] + "-" + normalized[12:]
+	}
+	return value
+}
+
+// validateCPF confere os dígitos verificadores de um CPF normalizado
+func validateCPF(cpf string) error {
+	for _, r := range cpf {
+		if r < '0' || r > '9' {
+			return errors.New("CPF deve conter apenas dígitos")
+		}
+	}
+	if repeated(cpf) {
+		return errors.New("dígitos todos iguais")
+	}
+
+	for size := 9; size <= 10; size++ {
+		sum := 0
+		for i := 0; i < size; i++ {
+			sum += int(cpf[i]-'0') * (size + 1 - i)
+		}
+		digit := sum * 10 % 11
+		if digit == 10 {
+			digit = 0
+		}
+		if digit != int(cpf[size]-'0') {
+			return errors.New("dígito verificador não confere")
+		}
+	}
+	return nil
+}
+
+// validateCNPJ confere os dígitos verificadores de um CNPJ normalizado. Desde 2026 a raiz e a ordem
+// (12 primeiras posições) podem conter letras; cada caractere vale seu código ASCII menos 48, de forma
+// que os dígitos mantêm o valor e o cálculo do módulo 11 continua o mesmo
+func validateCNPJ(cnpj string) error {
+	for i, r := range cnpj {
+		isDigit := r >= '0' && r <= '9'
+		if i >= 12 && !isDigit {
+			return errors.New("dígitos verificadores do CNPJ devem ser numéricos")
+		}
+		if !isDigit && (r < 'A' || r > 'Z') {
+			return errors.New("CNPJ contém caracteres inválidos")
+		}
+	}
+	if repeated(cnpj) {
+		return errors.New("caracteres todos iguais")
+	}
+
+	for size := 12; size <= 13; size++ {
+		sum, weight := 0, 2
+		for i := size - 1; i >= 0; i-- {
+			sum += int(cnpj[i]-'0') * weight
+			weight++
+			if weight > 9 {
+				weight = 2
+			}
+		}
+		digit := 11 - sum%11
+		if digit >= 10 {
+			digit = 0
+		}
+		if digit != int(cnpj[size]-'0') {
+			return errors.New("dígito verificador não confere")
+		}
+	}
+	return nil
+}
+
+// repeated indica documentos formados por um único caractere repetido, que passam no módulo 11
+func repeated(value string) bool {
+	return strings.Count(value, value[:1]) == len(value)
+}
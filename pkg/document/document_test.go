@@ -0,0 +1,107 @@
+package document
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		want     string
+		wantType Type
+		wantErr  bool
+	}{
+		{"CPF com máscara", "529.982.247-25", "52998224725", TypeCPF, false},
+		{"CPF sem máscara", "52998224725", "52998224725", TypeCPF, false},
+		{"CPF com dígito verificador errado", "529.982.247-24", "", "", true},
+		{"CPF com dígitos repetidos", "111.111.111-11", "", "", true},
+		{"CPF com letra", "5299822472A", "", "", true},
+		{"CNPJ numérico com máscara", "11.222.333/0001-81", "11222333000181", TypeCNPJ, false},
+		{"CNPJ numérico sem máscara", "11444777000161", "11444777000161", TypeCNPJ, false},
+		{"CNPJ numérico com dígito verificador errado", "11.222.333/0001-82", "", "", true},
+		{"CNPJ com zeros repetidos", "00.000.000/0000-00", "", "", true},
+		{"CNPJ alfanumérico (exemplo da Receita Federal)", "12.ABC.345/01DE-35", "12ABC34501DE35", TypeCNPJ, false},
+		{"CNPJ alfanumérico em minúsculas", "12.abc.345/01de-35", "12ABC34501DE35", TypeCNPJ, false},
+		{"CNPJ alfanumérico com letras na raiz e na ordem", "AB.1CD.2EF/0001-70", "AB1CD2EF000170", TypeCNPJ, false},
+		{"CNPJ alfanumérico com dígito verificador errado", "12.ABC.345/01DE-36", "", "", true},
+		{"CNPJ com letra no dígito verificador", "12.ABC.345/01DE-3A", "", "", true},
+		{"CNPJ com caractere acentuado", "12.ÁBC.345/01DE-35", "", "", true},
+		{"tamanho inválido", "123.456.789", "", "", true},
+		{"vazio", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, gotType, err := Validate(tt.value)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalid) {
+					t.Fatalf("Validate(%q) erro = %v, esperado ErrInvalid", tt.value, err)
+				}
+				var docErr *Error
+				if !errors.As(err, &docErr) || docErr.Document != tt.value {
+					t.Errorf("Validate(%q) erro sem o documento rejeitado: %v", tt.value, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Validate(%q) erro inesperado: %v", tt.value, err)
+			}
+			if got != tt.want || gotType != tt.wantType {
+				t.Errorf("Validate(%q) = %s %s, esperado %s %s", tt.value, got, gotType, tt.want, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestValidateByType(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func(string) (string, error)
+		value    string
+		want     string
+		wantErr  bool
+	}{
+		{"CPF válido", ValidateCPF, "529.982.247-25", "52998224725", false},
+		{"CNPJ informado como CPF", ValidateCPF, "11.222.333/0001-81", "", true},
+		{"CNPJ válido", ValidateCNPJ, "11.222.333/0001-81", "11222333000181", false},
+		{"CNPJ alfanumérico válido", ValidateCNPJ, "12ABC34501DE35", "12ABC34501DE35", false},
+		{"CPF informado como CNPJ", ValidateCNPJ, "529.982.247-25", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.validate(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("erro = %v, esperado erro %v", err, tt.wantErr)
+			}
+			if tt.wantErr && !errors.Is(err, ErrInvalid) {
+				t.Errorf("erro %v não identifica ErrInvalid", err)
+			}
+			if got != tt.want {
+				t.Errorf("resultado = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"52998224725", "529.982.247-25"},
+		{"11222333000181", "11.222.333/0001-81"},
+		{"12abc34501de35", "12.ABC.345/01DE-35"},
+		{"12345", "12345"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := Format(tt.value); got != tt.want {
+				t.Errorf("Format(%q) = %s, esperado %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,101 @@
+//go:build ignore
+
+// gen_municipios gera a tabela embarcada municipios.csv a partir da lista de municípios da API de
+// localidades do IBGE, com todos os municípios da DTB vigente. Uso: go generate ./pkg/ibge
+// (make ibge-municipios); -in lê a lista de um arquivo JSON já baixado em vez da API
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	apiURL = "https://servicodados.ibge.gov.br/api/v1/localidades/municipios"
+
+	// minMunicipalities protege a tabela embarcada de respostas truncadas da API
+	minMunicipalities = 5570
+)
+
+// ufs mapeia o código IBGE da UF para a sigla
+var ufs = map[string]string{
+	"11": "RO", "12": "AC", "13": "AM", "14": "RR", "15": "PA", "16": "AP", "17": "TO",
+	"21": "MA", "22": "PI", "23": "CE", "24": "RN", "25": "PB", "26": "PE", "27": "AL", "28": "SE", "29": "BA",
+	"31": "MG", "32": "ES", "33": "RJ", "35": "SP",
+	"41": "PR", "42": "SC", "43": "RS",
+	"50": "MS", "51": "MT", "52": "GO", "53": "DF",
+}
+
+func main() {
+	in := flag.String("in", "", "arquivo JSON da API de localidades (padrão: baixa da API)")
+	out := flag.String("out", "municipios.csv", "arquivo CSV gerado")
+	flag.Parse()
+
+	data, err := read(*in)
+	if err != nil {
+		log.Fatalf("Erro ao obter municípios do IBGE: %v", err)
+	}
+
+	var items []struct {
+		ID   json.Number `json:"id"`
+		Nome string      `json:"nome"`
+	}
+	if err := json.Unmarshal(data, &items); err != nil {
+		log.Fatalf("Erro ao ler municípios do IBGE: %v", err)
+	}
+
+	lines := make([]string, 0, len(items))
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		code := item.ID.String()
+		uf, ok := ufs[code[:min(2, len(code))]]
+		if len(code) != 7 || !ok {
+			log.Fatalf("Código de município inválido: %q", code)
+		}
+		name := strings.TrimSpace(item.Nome)
+		if name == "" || strings.Contains(name, ";") {
+			log.Fatalf("Nome de município inválido no código %s: %q", code, name)
+		}
+		if seen[code] {
+			log.Fatalf("Código de município repetido: %s", code)
+		}
+		seen[code] = true
+		lines = append(lines, code+";"+name+";"+uf)
+	}
+	if len(lines) < minMunicipalities {
+		log.Fatalf("A lista do IBGE tem %d municípios, esperado ao menos %d", len(lines), minMunicipalities)
+	}
+	sort.Strings(lines)
+
+	content := "codigo;municipio;uf\n" + strings.Join(lines, "\n") + "\n"
+	if err := os.WriteFile(*out, []byte(content), 0o644); err != nil {
+		log.Fatalf("Erro ao gravar %s: %v", *out, err)
+	}
+	fmt.Printf("%d municípios gravados em %s\n", len(lines), *out)
+}
+
+// read lê a lista de municípios do arquivo informado ou da API de localidades
+func read(path string) ([]byte, error) {
+	if path != "" {
+		return os.ReadFile(path)
+	}
+
+	client := &http.Client{Timeout: 2 * time.Minute}
+	resp, err := client.Get(apiURL)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("API de localidades respondeu %s", resp.Status)
+	}
+	return io.ReadAll(resp.Body)
+}
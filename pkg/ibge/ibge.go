@@ -0,0 +1,281 @@
+package ibge
+
+import (
+	"bufio"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// ErrInvalidTable ocorre quando o arquivo de municípios não segue o layout codigo;municipio;uf
+var ErrInvalidTable = errors.New("tabela de municípios IBGE inválida")
+
+// dtbMunicipalities é a quantidade de municípios da DTB (Divisão Territorial Brasileira) vigente
+const dtbMunicipalities = 5570
+
+// municipiosCSV é a tabela embarcada com todos os municípios da DTB, gerada a partir da API de
+// localidades do IBGE por go generate (make ibge-municipios). LoadFile a substitui em tempo de
+// execução, por exemplo por uma DTB mais recente que a do build
+//
+//go:generate go run gen_municipios.go -out municipios.csv
+//go:embed municipios.csv
+var municipiosCSV string
+
+// State representa uma unidade da federação
+type State struct {
+	Code string `json:"code"` // Código IBGE da UF (cUF)
+	UF   string `json:"uf"`
+	Name string `json:"name"`
+}
+
+// Municipality representa um município da tabela do IBGE
+type Municipality struct {
+	Code string `json:"code"` // Código IBGE do município com 7 dígitos (cMun)
+	Name string `json:"name"`
+	UF   string `json:"uf"`
+}
+
+var states = []State{
+	{"11", "RO", "Rondônia"},
+	{"12", "AC", "Acre"},
+	{"13", "AM", "Amazonas"},
+	{"14", "RR", "Roraima"},
+	{"15", "PA", "Pará"},
+	{"16", "AP", "Amapá"},
+	{"17", "TO", "Tocantins"},
+	{"21", "MA", "Maranhão"},
+	{"22", "PI", "Piauí"},
+	{"23", "CE", "Ceará"},
+	{"24", "RN", "Rio Grande do Norte"},
+	{"25", "PB", "Paraíba"},
+	{"26", "PE", "Pernambuco"},
+	{"27", "AL", "Alagoas"},
+	{"28", "SE", "Sergipe"},
+	{"29", "BA", "Bahia"},
+	{"31", "MG", "Minas Gerais"},
+	{"32", "ES", "Espírito Santo"},
+	{"33", "RJ", "Rio de Janeiro"},
+	{"35", "SP", "São Paulo"},
+	{"41", "PR", "Paraná"},
+	{"42", "SC", "Santa Catarina"},
+	{"43", "RS", "Rio Grande do Sul"},
+	{"50", "MS", "Mato Grosso do Sul"},
+	{"51", "MT", "Mato Grosso"},
+	{"52", "GO", "Goiás"},
+	{"53", "DF", "Distrito Federal"},
+}
+
+// table indexa os municípios por código e por nome+UF
+type table struct {
+	byCode map[string]Municipality
+	byName map[string]Municipality
+}
+
+var (
+	loadOnce sync.Once
+	mu       sync.RWMutex
+	current  *table
+	complete bool
+)
+
+// States retorna as unidades da federação em ordem de código
+func States() []State {
+	result := make([]State, len(states))
+	copy(result, states)
+	return result
+}
+
+// StateByUF busca uma UF pela sigla
+func StateByUF(uf string) (State, bool) {
+	uf = strings.ToUpper(strings.TrimSpace(uf))
+	for _, s := range states {
+		if s.UF == uf {
+			return s, true
+		}
+	}
+	return State{}, false
+}
+
+// StateByCode busca uma UF pelo código IBGE
+func StateByCode(code string) (State, bool) {
+	code = strings.TrimSpace(code)
+	for _, s := range states {
+		if s.Code == code {
+			return s, true
+		}
+	}
+	return State{}, false
+}
+
+// FindMunicipality busca o município pelo nome e pela UF, ignorando acentos, caixa e hífens
+func FindMunicipality(city, uf string) (Municipality, bool) {
+	m, ok := municipalities().byName[nameKey(city, uf)]
+	return m, ok
+}
+
+// MunicipalityByCode busca o município pelo código IBGE
+func MunicipalityByCode(code string) (Municipality, bool) {
+	m, ok := municipalities().byCode[strings.TrimSpace(code)]
+	return m, ok
+}
+
+// LoadFile substitui a tabela embarcada pela tabela do arquivo. Aceita o layout
+// codigo;municipio;uf ou, com extensão .json, a lista de municípios da API de localidades do IBGE
+// (servicodados.ibge.gov.br/api/v1/localidades/municipios)
+func LoadFile(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("falha ao abrir tabela de municípios: %w", err)
+	}
+	defer f.Close()
+
+	var t *table
+	if strings.HasSuffix(strings.ToLower(path), ".json") {
+		t, err = parseJSON(f)
+	} else {
+		t, err = parse(f)
+	}
+	if err != nil {
+		return err
+	}
+
+	loadOnce.Do(func() {})
+	mu.Lock()
+	current = t
+	complete = true
+	mu.Unlock()
+	return nil
+}
+
+// Complete indica se a tabela em uso tem todos os municípios da DTB, seja a embarcada ou a carregada
+// com LoadFile. Com uma tabela parcial, um município não encontrado pode existir
+func Complete() bool {
+	municipalities()
+	mu.RLock()
+	defer mu.RUnlock()
+	return complete
+}
+
+// municipalities retorna a tabela em uso, carregando a embarcada no primeiro acesso
+func municipalities() *table {
+	loadOnce.Do(func() {
+		t, err := parse(strings.NewReader(municipiosCSV))
+		if err != nil {
+			panic(err)
+		}
+		mu.Lock()
+		current = t
+		complete = len(t.byCode) >= dtbMunicipalities
+		mu.Unlock()
+	})
+
+	mu.RLock()
+	defer mu.RUnlock()
+	return current
+}
+
+// parse lê a tabela de municípios, ignorando o cabeçalho
+func parse(r io.Reader) (*table, error) {
+	t := &table{
+		byCode: make(map[string]Municipality),
+		byName: make(map[string]Municipality),
+	}
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || (line == 1 && strings.HasPrefix(strings.ToLower(text), "codigo")) {
+			continue
+		}
+
+		fields := strings.Split(text, ";")
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("%w: linha %d", ErrInvalidTable, line)
+		}
+		m := Municipality{
+			Code: strings.TrimSpace(fields[0]),
+			Name: strings.TrimSpace(fields[1]),
+			UF:   strings.ToUpper(strings.TrimSpace(fields[2])),
+		}
+		if len(m.Code) != 7 {
+			return nil, fmt.Errorf("%w: código %q na linha %d", ErrInvalidTable, m.Code, line)
+		}
+		if s, ok := StateByUF(m.UF); !ok || s.Code != m.Code[:2] {
+			return nil, fmt.Errorf("%w: UF %q não confere com o código %s na linha %d", ErrInvalidTable, m.UF, m.Code, line)
+		}
+
+		t.byCode[m.Code] = m
+		t.byName[nameKey(m.Name, m.UF)] = m
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("falha ao ler tabela de municípios: %w", err)
+	}
+	if len(t.byCode) == 0 {
+		return nil, fmt.Errorf("%w: nenhum município", ErrInvalidTable)
+	}
+	return t, nil
+}
+
+// parseJSON lê a lista de municípios da API de localidades do IBGE. A UF vem dos dois primeiros
+// dígitos do código, já que a hierarquia regional da API muda entre as versões da DTB
+func parseJSON(r io.Reader) (*table, error) {
+	var items []struct {
+		ID   json.Number `json:"id"`
+		Nome string      `json:"nome"`
+	}
+	if err := json.NewDecoder(r).Decode(&items); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTable, err)
+	}
+
+	t := &table{
+		byCode: make(map[string]Municipality, len(items)),
+		byName: make(map[string]Municipality, len(items)),
+	}
+	for i, item := range items {
+		code := item.ID.String()
+		if len(code) != 7 {
+			return nil, fmt.Errorf("%w: código %q no item %d", ErrInvalidTable, code, i+1)
+		}
+		s, ok := StateByCode(code[:2])
+		if !ok {
+			return nil, fmt.Errorf("%w: UF do código %s no item %d", ErrInvalidTable, code, i+1)
+		}
+
+		m := Municipality{Code: code, Name: strings.TrimSpace(item.Nome), UF: s.UF}
+		t.byCode[m.Code] = m
+		t.byName[nameKey(m.Name, m.UF)] = m
+	}
+	if len(t.byCode) == 0 {
+		return nil, fmt.Errorf("%w: nenhum município", ErrInvalidTable)
+	}
+	return t, nil
+}
+
+// accents mapeia as letras acentuadas usadas nos nomes de municípios
+var accents = strings.NewReplacer(
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+	"-", " ", "'", " ", "`", " ",
+)
+
+// nameKey monta a chave de busca por nome: sem acentos, em maiúsculas e com espaços simples
+func nameKey(city, uf string) string {
+	name := accents.Replace(strings.ToUpper(strings.TrimSpace(city)))
+	return strings.Join(strings.Fields(name), " ") + "/" + strings.ToUpper(strings.TrimSpace(uf))
+}
+
+// SameName compara nomes de municípios ignorando acentos, caixa e hífens
+func SameName(a, b string) bool {
+	return nameKey(a, "") == nameKey(b, "")
+}
@@ -0,0 +1,114 @@
+package ibge
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFindMunicipality(t *testing.T) {
+	tests := []struct {
+		city   string
+		uf     string
+		want   string
+		wantOK bool
+	}{
+		{"São Paulo", "SP", "3550308", true},
+		{"sao paulo", "sp", "3550308", true},
+		{"  GOIÂNIA ", "GO", "5208707", true},
+		{"Ji Paraná", "RO", "1100122", true},
+		{"São Paulo", "RJ", "", false},
+		{"Cidade Inexistente", "SP", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.city+"/"+tt.uf, func(t *testing.T) {
+			got, ok := FindMunicipality(tt.city, tt.uf)
+			if ok != tt.wantOK || got.Code != tt.want {
+				t.Errorf("FindMunicipality(%q, %q) = %s %v, esperado %s %v", tt.city, tt.uf, got.Code, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr error
+	}{
+		{"com cabeçalho", "codigo;municipio;uf\n3550308;São Paulo;SP\n5208707;Goiânia;GO\n", 2, nil},
+		{"sem cabeçalho", "3550308;São Paulo;SP\n", 1, nil},
+		{"colunas faltando", "3550308;São Paulo\n", 0, ErrInvalidTable},
+		{"código com tamanho errado", "355030;São Paulo;SP\n", 0, ErrInvalidTable},
+		{"UF diferente do código", "3550308;São Paulo;RJ\n", 0, ErrInvalidTable},
+		{"vazio", "codigo;municipio;uf\n", 0, ErrInvalidTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parse(strings.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parse() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if err == nil && len(got.byCode) != tt.want {
+				t.Errorf("parse() = %d municípios, esperado %d", len(got.byCode), tt.want)
+			}
+		})
+	}
+}
+
+func TestParseJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    int
+		wantErr error
+	}{
+		{"lista da API de localidades", `[{"id":3550308,"nome":"São Paulo"},{"id":5300108,"nome":"Brasília"}]`, 2, nil},
+		{"código com tamanho errado", `[{"id":355030,"nome":"São Paulo"}]`, 0, ErrInvalidTable},
+		{"UF inexistente", `[{"id":9900000,"nome":"Teste"}]`, 0, ErrInvalidTable},
+		{"JSON inválido", `{"id":`, 0, ErrInvalidTable},
+		{"lista vazia", `[]`, 0, ErrInvalidTable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseJSON(strings.NewReader(tt.content))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("parseJSON() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if err == nil && len(got.byCode) != tt.want {
+				t.Errorf("parseJSON() = %d municípios, esperado %d", len(got.byCode), tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	if Complete() {
+		t.Fatal("a tabela embarcada não deve ser considerada completa")
+	}
+
+	path := filepath.Join(t.TempDir(), "municipios.json")
+	content := `[{"id":3550308,"nome":"São Paulo"},{"id":2927408,"nome":"Salvador"},{"id":1100015,"nome":"Alta Floresta D'Oeste"}]`
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := LoadFile(path); err != nil {
+		t.Fatalf("LoadFile() erro inesperado: %v", err)
+	}
+
+	if !Complete() {
+		t.Error("Complete() = false após LoadFile")
+	}
+	if m, ok := FindMunicipality("Alta Floresta d Oeste", "RO"); !ok || m.Code != "1100015" {
+		t.Errorf("FindMunicipality() = %+v %v, esperado o município carregado do arquivo", m, ok)
+	}
+	if err := LoadFile(filepath.Join(t.TempDir(), "inexistente.json")); err == nil {
+		t.Error("LoadFile() de arquivo inexistente deveria falhar")
+	}
+}
@@ -34,8 +34,12 @@ func (a *CustomerRepositoryAdapter) Create(tenantID string, c *domain.Customer)
 		"tenant_id", tenantID)
 
 	// Convert our simple domain model to the internal domain model
-	personType := customer.PersonTypePF
-	if c.CustomerType == "PJ" {
+	// An empty person type lets NewCustomer deduce it from the CPF/CNPJ
+	var personType customer.PersonType
+	switch c.CustomerType {
+	case "PF":
+		personType = customer.PersonTypePF
+	case "PJ":
 		personType = customer.PersonTypePJ
 	}
 
@@ -156,13 +160,13 @@ func (a *CustomerRepositoryAdapter) Update(tenantID string, c *domain.Customer)
 
 	// Update document if provided
 	if c.Document != "" {
-		internalCustomer.Document = c.Document
-		// Update person type based on document
-		if len(c.Document) > 11 {
-			internalCustomer.PersonType = customer.PersonTypePJ
-		} else {
-			internalCustomer.PersonType = customer.PersonTypePF
+		// Validate the CPF/CNPJ and update person type based on it
+		document, personType, err := customer.NormalizeDocument(c.Document, "")
+		if err != nil {
+			return err
 		}
+		internalCustomer.Document = document
+		internalCustomer.PersonType = personType
 	}
 
 	// Update status
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	pkgdocument "github.com/hugohenrick/erp-supermercado/pkg/document"
 	"github.com/hugohenrick/erp-supermercado/pkg/domain"
 	"github.com/hugohenrick/erp-supermercado/pkg/logger"
 	"github.com/hugohenrick/erp-supermercado/pkg/repository"
@@ -313,14 +314,9 @@ func (h *CustomerIntentHandler) Extract(message string) (*Intent, error) {
 	return nil, nil
 }
 
-// CheckPermission verifica se o usuário tem permissão para executar a ação
-func (h *CustomerIntentHandler) CheckPermission(ctx ContextData) bool {
-	// Verificar se o usuário tem permissão baseado no papel
-	// Admin e manager podem gerenciar clientes
-	if ctx.Role == "admin" || ctx.Role == "manager" || ctx.Role == "sales" {
-		return true
-	}
-	return false
+// CheckPermission verifica se o usuário tem a permissão exigida pela intenção
+func (h *CustomerIntentHandler) CheckPermission(ctx ContextData, intent *Intent) bool {
+	return hasIntentPermission(ctx, intent)
 }
 
 // Execute executa a ação associada à intenção
@@ -431,11 +427,7 @@ func (h *CustomerIntentHandler) createCustomer(ctxData ContextData, intent *Inte
 	if document, ok := intent.Entities["document"].(string); ok && document != "" {
 		customer.Document = document
 		// Determinar tipo de cliente com base no documento (CPF ou CNPJ)
-		if len(document) > 11 {
-			customer.CustomerType = "PJ"
-		} else {
-			customer.CustomerType = "PF"
-		}
+		customer.CustomerType = customerTypeFromDocument(document)
 	}
 
 	if phone, ok := intent.Entities["phone"].(string); ok && phone != "" {
@@ -716,11 +708,7 @@ func (h *CustomerIntentHandler) updateCustomer(ctxData ContextData, intent *Inte
 	if newDocument, ok := intent.Entities["new_document"].(string); ok && newDocument != "" && newDocument != customer.Document {
 		customer.Document = newDocument
 		// Atualizar tipo de cliente com base no documento
-		if len(newDocument) > 11 {
-			customer.CustomerType = "PJ"
-		} else {
-			customer.CustomerType = "PF"
-		}
+		customer.CustomerType = customerTypeFromDocument(newDocument)
 		updated = true
 	}
 
@@ -1031,3 +1019,16 @@ func customerStatusToString(active bool) string {
 	}
 	return "Inativo"
 }
+
+// customerTypeFromDocument identifica PF ou PJ pelo CPF/CNPJ. Documento inválido fica sem tipo
+// e é rejeitado pelo cadastro de clientes
+func customerTypeFromDocument(document string) string {
+	_, docType, err := pkgdocument.Validate(document)
+	if err != nil {
+		return ""
+	}
+	if docType == pkgdocument.TypeCNPJ {
+		return "PJ"
+	}
+	return "PF"
+}
@@ -83,12 +83,9 @@ func (m *IntentManager) ProcessMessage(ctx context.Context, message string, ctxD
 	m.logger.Info("Intent detected", "intent", intent.Name, "confidence", intent.Confidence)
 
 	// Verificar permissões
-	if !handler.CheckPermission(ctxData) {
+	if !handler.CheckPermission(ctxData, intent) {
 		m.logger.Warn("Permission denied", "intent", intent.Name, "role", ctxData.Role)
-		return &ActionResult{
-			Success: false,
-			Message: "Você não tem permissão para executar esta ação. Por favor, contate um administrador se precisar de acesso.",
-		}, nil
+		return permissionDenied(), nil
 	}
 
 	// Verificar se é uma operação que requer confirmação
@@ -165,6 +162,13 @@ func (m *IntentManager) processContinuation(ctx context.Context, message string,
 				}, nil
 			}
 
+			// As permissões podem ter mudado desde a detecção da intenção
+			if !targetHandler.CheckPermission(ctxData, state.PendingIntent) {
+				delete(m.sessions, sessionID)
+				m.logger.Warn("Permission denied", "intent", state.PendingIntent.Name, "role", ctxData.Role)
+				return permissionDenied(), nil
+			}
+
 			m.logger.Info("Executando ação confirmada",
 				"intent", state.PendingIntent.Name,
 				"message", message,
@@ -258,6 +262,14 @@ func (m *IntentManager) detectIntent(message string) (*Intent, IntentHandler, er
 	return bestIntent, bestHandler, nil
 }
 
+// permissionDenied é a resposta a uma intenção sem a permissão exigida
+func permissionDenied() *ActionResult {
+	return &ActionResult{
+		Success: false,
+		Message: "Você não tem permissão para executar esta ação. Por favor, contate um administrador se precisar de acesso.",
+	}
+}
+
 // getSessionID gera um ID de sessão baseado nos dados do contexto
 func getSessionID(ctxData ContextData) string {
 	return fmt.Sprintf("%s:%s", ctxData.TenantID, ctxData.UserID)
@@ -0,0 +1,48 @@
+package intent
+
+import "github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+
+// intentPermissions associa cada intenção à permissão exigida, a mesma das rotas /customers e
+// /users da API; produtos não têm rotas REST e seguem o mesmo catálogo. As intenções genéricas e
+// a confirmação avulsa apenas respondem com orientação e exigem a consulta
+var intentPermissions = map[string]rbac.Permission{
+	"create_customer":  rbac.PermCustomerCreate,
+	"get_customer":     rbac.PermCustomerView,
+	"update_customer":  rbac.PermCustomerUpdate,
+	"delete_customer":  rbac.PermCustomerDelete,
+	"list_customers":   rbac.PermCustomerView,
+	"confirm_action":   rbac.PermCustomerView,
+	"customer_generic": rbac.PermCustomerView,
+
+	"create_product":  rbac.PermProductCreate,
+	"get_product":     rbac.PermProductView,
+	"update_product":  rbac.PermProductUpdate,
+	"delete_product":  rbac.PermProductDelete,
+	"list_products":   rbac.PermProductView,
+	"update_stock":    rbac.PermProductUpdate,
+	"update_price":    rbac.PermProductUpdate,
+	"product_generic": rbac.PermProductView,
+
+	"create_user":  rbac.PermUserManage,
+	"get_user":     rbac.PermUserView,
+	"update_user":  rbac.PermUserManage,
+	"delete_user":  rbac.PermUserManage,
+	"list_users":   rbac.PermUserView,
+	"user_generic": rbac.PermUserView,
+}
+
+// RequiredPermission retorna a permissão exigida pela intenção
+func RequiredPermission(intentName string) (rbac.Permission, bool) {
+	permission, ok := intentPermissions[intentName]
+	return permission, ok
+}
+
+// hasIntentPermission verifica se o usuário tem a permissão exigida pela intenção. Intenções sem
+// permissão associada são negadas
+func hasIntentPermission(ctx ContextData, intent *Intent) bool {
+	if intent == nil {
+		return false
+	}
+	permission, ok := RequiredPermission(intent.Name)
+	return ok && ctx.Permissions.Has(permission)
+}
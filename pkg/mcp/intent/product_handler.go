@@ -108,40 +108,9 @@ func (h *ProductIntentHandler) Extract(message string) (*Intent, error) {
 	return nil, nil
 }
 
-// CheckPermission verifica se o usuário tem permissão para executar a ação
-func (h *ProductIntentHandler) CheckPermission(ctxData ContextData) bool {
-	// Verificar se o usuário tem permissão baseado no papel/role
-	switch ctxData.Role {
-	case "admin", "administrator", "superadmin", "manager":
-		// Administradores e gerentes podem fazer tudo
-		return true
-
-	case "inventory", "stock":
-		// Pessoal de estoque pode criar e atualizar produtos/estoque
-		return true
-
-	case "sales":
-		// Vendedores podem apenas consultar produtos
-		if intentName := h.getIntentNameFromContext(ctxData); intentName == "get_product" ||
-			intentName == "list_products" || intentName == "product_generic" {
-			return true
-		}
-		return false
-
-	default:
-		// Consultas são permitidas para todos os papéis autenticados
-		if intentName := h.getIntentNameFromContext(ctxData); intentName == "get_product" ||
-			intentName == "list_products" {
-			return true
-		}
-		return false
-	}
-}
-
-// getIntentNameFromContext é um helper para obter o nome da intenção do contexto
-func (h *ProductIntentHandler) getIntentNameFromContext(ctxData ContextData) string {
-	// Implementação básica - em um sistema real, teríamos o intent no contexto
-	return ""
+// CheckPermission verifica se o usuário tem a permissão exigida pela intenção
+func (h *ProductIntentHandler) CheckPermission(ctxData ContextData, intent *Intent) bool {
+	return hasIntentPermission(ctxData, intent)
 }
 
 // Execute executa a ação correspondente à intenção
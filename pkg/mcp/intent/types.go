@@ -1,5 +1,7 @@
 package intent
 
+import "github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
+
 // Intent representa uma intenção detectada em uma mensagem do usuário
 type Intent struct {
 	// Nome da intenção (ex: "create_user", "list_products")
@@ -41,17 +43,18 @@ type IntentHandler interface {
 	// Executa a ação associada à intenção
 	Execute(ctx ContextData, intent *Intent) (*ActionResult, error)
 
-	// Verifica se o usuário tem permissão para executar esta ação
-	CheckPermission(ctx ContextData) bool
+	// Verifica se o usuário tem a permissão exigida pela intenção
+	CheckPermission(ctx ContextData, intent *Intent) bool
 }
 
 // ContextData contém informações do contexto da requisição
 type ContextData struct {
-	UserID   string
-	TenantID string
-	Role     string
-	Locale   string
-	Session  map[string]interface{}
+	UserID      string
+	TenantID    string
+	Role        string
+	Permissions rbac.PermissionSet // Permissões efetivas do usuário na filial ativa
+	Locale      string
+	Session     map[string]interface{}
 }
 
 // FlowState rastreia o estado de uma conversa de confirmação
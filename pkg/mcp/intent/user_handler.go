@@ -105,33 +105,9 @@ func (h *UserIntentHandler) Extract(message string) (*Intent, error) {
 	return nil, nil
 }
 
-// CheckPermission verifica se o usuário tem permissão para executar a ação
-func (h *UserIntentHandler) CheckPermission(ctxData ContextData) bool {
-	// Verificar se o usuário tem permissão baseado no papel/role
-	switch ctxData.Role {
-	case "admin", "administrator", "superadmin":
-		// Administradores podem fazer tudo
-		return true
-
-	case "manager":
-		// Gerentes podem fazer tudo exceto excluir usuários
-		if _, ok := h.regexPatterns["delete_user"]; ok {
-			// Verificar se a intenção é de exclusão
-			return false
-		}
-		return true
-
-	case "hr", "rh":
-		// Recursos humanos podem gerenciar usuários, mas não exclusão
-		if _, ok := h.regexPatterns["delete_user"]; ok {
-			return false
-		}
-		return true
-
-	default:
-		// Outros perfis não podem gerenciar usuários
-		return false
-	}
+// CheckPermission verifica se o usuário tem a permissão exigida pela intenção
+func (h *UserIntentHandler) CheckPermission(ctxData ContextData, intent *Intent) bool {
+	return hasIntentPermission(ctxData, intent)
 }
 
 // Execute executa a ação correspondente à intenção
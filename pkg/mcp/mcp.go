@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/rbac"
 	"github.com/hugohenrick/erp-supermercado/pkg/chat"
 	"github.com/hugohenrick/erp-supermercado/pkg/logger"
 	"github.com/hugohenrick/erp-supermercado/pkg/mcp/intent"
@@ -22,16 +23,22 @@ const (
 	defaultModel         = "claude-3-sonnet-20240229"
 )
 
+// UsageRecorder registra os tokens consumidos pelo assistente para a medição de consumo do tenant
+type UsageRecorder interface {
+	RecordTokens(ctx context.Context, tenantID string, inputTokens, outputTokens int) error
+}
+
 // MCPClient represents the MCP client configuration
 type MCPClient struct {
 	apiKey     string
 	client     *http.Client
 	logger     logger.Logger
 	repository chat.Repository
+	usage      UsageRecorder
 }
 
-// NewMCPClient creates a new MCP client
-func NewMCPClient(logger logger.Logger, repository chat.Repository) (*MCPClient, error) {
+// NewMCPClient creates a new MCP client. usage pode ser nil, sem medição de tokens
+func NewMCPClient(logger logger.Logger, repository chat.Repository, usage UsageRecorder) (*MCPClient, error) {
 	apiKey := os.Getenv("ANTHROPIC_API_KEY")
 	if apiKey == "" {
 		return nil, fmt.Errorf("ANTHROPIC_API_KEY não encontrada nas variáveis de ambiente")
@@ -42,6 +49,7 @@ func NewMCPClient(logger logger.Logger, repository chat.Repository) (*MCPClient,
 		client:     &http.Client{},
 		logger:     logger,
 		repository: repository,
+		usage:      usage,
 	}, nil
 }
 
@@ -252,6 +260,13 @@ func (m *MCPClient) ProcessWithContext(ctx context.Context, message string, cont
 		"output_tokens", apiResp.Usage.OutputTokens,
 		"stop_reason", apiResp.StopReason)
 
+	// Registrar o consumo do tenant; a resposta não depende da medição
+	if m.usage != nil {
+		if err := m.usage.RecordTokens(ctx, contextData.TenantID, apiResp.Usage.InputTokens, apiResp.Usage.OutputTokens); err != nil {
+			m.logger.Error("Erro ao registrar tokens do assistente", "error", err)
+		}
+	}
+
 	// Salvar resposta do assistente
 	assistantMessage := &chat.Message{
 		UserID:  contextData.UserID,
@@ -299,6 +314,8 @@ type MCP struct {
 	IntentManager  *intent.IntentManager
 	// Sessões de intenção - mapeia sessões para dados de estado
 	IntentSessions map[string]*intent.FlowState
+	// Usage registra os tokens consumidos por tenant; opcional
+	Usage UsageRecorder
 }
 
 // NewMCP cria uma nova instância do MCP
@@ -323,8 +340,9 @@ func (m *MCP) RegisterHandler(handler intent.IntentHandler) {
 	m.IntentManager.RegisterHandler(handler)
 }
 
-// ProcessWithContext processa uma mensagem com contexto do usuário
-func (m *MCP) ProcessWithContext(ctx context.Context, userMessage string, userID string, tenantID string, userRole string, locale string) (*Message, error) {
+// ProcessWithContext processa uma mensagem com contexto do usuário. As intenções só são executadas
+// com as permissões exigidas, as mesmas das rotas da API
+func (m *MCP) ProcessWithContext(ctx context.Context, userMessage string, userID string, tenantID string, userRole string, permissions rbac.PermissionSet, locale string) (*Message, error) {
 	// Gerar ID da sessão
 	sessionID := m.getSessionKey(tenantID, userID)
 
@@ -376,10 +394,11 @@ func (m *MCP) ProcessWithContext(ctx context.Context, userMessage string, userID
 
 		// Build context data
 		intentCtx := intent.ContextData{
-			UserID:   userID,
-			TenantID: tenantID,
-			Role:     userRole,
-			Locale:   locale,
+			UserID:      userID,
+			TenantID:    tenantID,
+			Role:        userRole,
+			Permissions: permissions,
+			Locale:      locale,
 		}
 
 		// If session exists, inject it
@@ -467,10 +486,11 @@ func (m *MCP) ProcessWithContext(ctx context.Context, userMessage string, userID
 
 	// Verificar se é uma mensagem que pode ser processada como uma intenção
 	intentCtx := intent.ContextData{
-		UserID:   userID,
-		TenantID: tenantID,
-		Role:     userRole,
-		Locale:   locale,
+		UserID:      userID,
+		TenantID:    tenantID,
+		Role:        userRole,
+		Permissions: permissions,
+		Locale:      locale,
 	}
 
 	// Se existe sessão ativa no MCP, passar para o intent manager
@@ -674,6 +694,13 @@ no contexto da conversa.`, userID, userID, tenantID, userRole)
 		"outputTokens", messageResponse.Usage.OutputTokens,
 		"stopReason", messageResponse.StopReason)
 
+	// Registrar o consumo do tenant; a resposta não depende da medição
+	if m.Usage != nil {
+		if err := m.Usage.RecordTokens(ctx, tenantID, messageResponse.Usage.InputTokens, messageResponse.Usage.OutputTokens); err != nil {
+			m.Logger.Error("Erro ao registrar tokens do assistente", "error", err)
+		}
+	}
+
 	// Criar a mensagem de resposta
 	responseMsg := &Message{
 		Role:    "assistant",
@@ -0,0 +1,317 @@
+package ofx
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidFile   = errors.New("arquivo OFX inválido")
+	ErrNoStatement   = errors.New("arquivo OFX sem extrato bancário")
+	ErrInvalidAmount = errors.New("valor inválido no arquivo OFX")
+	ErrInvalidDate   = errors.New("data inválida no arquivo OFX")
+)
+
+// Transaction representa um lançamento do extrato (STMTTRN)
+type Transaction struct {
+	FITID       string    // Identificador único do lançamento no banco
+	Type        string    // TRNTYPE: CREDIT, DEBIT, CHECK, DEP, XFER, PAYMENT...
+	PostedAt    time.Time // Data do lançamento
+	Amount      float64   // Positivo para créditos e negativo para débitos
+	CheckNumber string
+	RefNumber   string
+	Name        string
+	Memo        string
+}
+
+// Statement representa o extrato de uma conta contido no arquivo
+type Statement struct {
+	BankID       string
+	BranchID     string
+	AccountID    string
+	AccountType  string
+	Currency     string
+	StartDate    time.Time
+	EndDate      time.Time
+	Balance      float64 // LEDGERBAL: saldo ao final do período
+	BalanceDate  time.Time
+	Transactions []Transaction
+}
+
+// token representa uma marcação do arquivo: abertura, fechamento ou elemento com valor
+type token struct {
+	name    string
+	value   string
+	closing bool
+}
+
+// Parse interpreta um arquivo OFX 1.x (SGML) ou 2.x (XML) e devolve o extrato bancário.
+// Arquivos em Windows-1252, comuns nos bancos brasileiros, são convertidos para UTF-8
+func Parse(data []byte) (*Statement, error) {
+	data = toUTF8(data)
+
+	start := bytes.Index(bytes.ToUpper(data), []byte("<OFX>"))
+	if start < 0 {
+		return nil, ErrInvalidFile
+	}
+
+	tokens := tokenize(string(data[start:]))
+
+	var stmt *Statement
+	var current *Transaction
+	var inBalance bool
+	for _, tok := range tokens {
+		switch {
+		case tok.name == "STMTRS" || tok.name == "CCSTMTRS":
+			if !tok.closing && stmt == nil {
+				stmt = &Statement{}
+			}
+		case stmt == nil:
+			continue
+		case tok.name == "STMTTRN":
+			// Em SGML o fechamento pode ser omitido: a abertura do próximo lançamento encerra o anterior
+			if current != nil {
+				stmt.Transactions = append(stmt.Transactions, *current)
+				current = nil
+			}
+			if !tok.closing {
+				current = &Transaction{}
+			}
+		case tok.name == "BANKTRANLIST" || tok.name == "LEDGERBAL":
+			if current != nil {
+				stmt.Transactions = append(stmt.Transactions, *current)
+				current = nil
+			}
+			if tok.name == "LEDGERBAL" {
+				inBalance = !tok.closing
+			}
+		case tok.closing:
+			continue
+		case current != nil:
+			if err := setTransactionField(current, tok); err != nil {
+				return nil, err
+			}
+		case inBalance:
+			if err := setBalanceField(stmt, tok); err != nil {
+				return nil, err
+			}
+		default:
+			if err := setStatementField(stmt, tok); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// SGML permite omitir o fechamento do último lançamento
+	if current != nil {
+		stmt.Transactions = append(stmt.Transactions, *current)
+	}
+	if stmt == nil {
+		return nil, ErrNoStatement
+	}
+
+	// Lançamentos idênticos no mesmo dia são diferenciados pela ordem de ocorrência,
+	// o que mantém o identificador estável entre extratos de períodos sobrepostos
+	occurrences := make(map[string]int)
+	for i := range stmt.Transactions {
+		tx := &stmt.Transactions[i]
+		if tx.FITID == "" {
+			key := fmt.Sprintf("%s|%.2f|%s|%s", tx.PostedAt.Format("20060102"), tx.Amount, tx.Memo, tx.RefNumber)
+			occurrences[key]++
+			tx.FITID = syntheticFITID(key, occurrences[key])
+		}
+	}
+
+	return stmt, nil
+}
+
+// setStatementField preenche os dados da conta e do período
+func setStatementField(stmt *Statement, tok token) error {
+	var err error
+	switch tok.name {
+	case "BANKID":
+		stmt.BankID = tok.value
+	case "BRANCHID":
+		stmt.BranchID = tok.value
+	case "ACCTID":
+		stmt.AccountID = tok.value
+	case "ACCTTYPE":
+		stmt.AccountType = tok.value
+	case "CURDEF":
+		stmt.Currency = tok.value
+	case "DTSTART":
+		stmt.StartDate, err = parseDate(tok.value)
+	case "DTEND":
+		stmt.EndDate, err = parseDate(tok.value)
+	}
+	return err
+}
+
+// setBalanceField preenche o saldo final do extrato
+func setBalanceField(stmt *Statement, tok token) error {
+	var err error
+	switch tok.name {
+	case "BALAMT":
+		stmt.Balance, err = parseAmount(tok.value)
+	case "DTASOF":
+		stmt.BalanceDate, err = parseDate(tok.value)
+	}
+	return err
+}
+
+// setTransactionField preenche um campo do lançamento
+func setTransactionField(tx *Transaction, tok token) error {
+	var err error
+	switch tok.name {
+	case "TRNTYPE":
+		tx.Type = strings.ToUpper(tok.value)
+	case "DTPOSTED":
+		tx.PostedAt, err = parseDate(tok.value)
+	case "TRNAMT":
+		tx.Amount, err = parseAmount(tok.value)
+	case "FITID":
+		tx.FITID = tok.value
+	case "CHECKNUM":
+		tx.CheckNumber = tok.value
+	case "REFNUM":
+		tx.RefNumber = tok.value
+	case "NAME":
+		tx.Name = tok.value
+	case "MEMO":
+		tx.Memo = tok.value
+	}
+	return err
+}
+
+// tokenize separa as marcações; em SGML o valor vai até a próxima marcação, sem fechamento
+func tokenize(content string) []token {
+	tokens := make([]token, 0)
+	for i := 0; i < len(content); {
+		open := strings.IndexByte(content[i:], '<')
+		if open < 0 {
+			break
+		}
+		open += i
+		end := strings.IndexByte(content[open:], '>')
+		if end < 0 {
+			break
+		}
+		end += open
+
+		tag := strings.ToUpper(strings.TrimSpace(content[open+1 : end]))
+		i = end + 1
+
+		if strings.HasPrefix(tag, "?") || strings.HasPrefix(tag, "!") {
+			continue
+		}
+		if strings.HasPrefix(tag, "/") {
+			tokens = append(tokens, token{name: tag[1:], closing: true})
+			continue
+		}
+
+		next := strings.IndexByte(content[i:], '<')
+		if next < 0 {
+			next = len(content) - i
+		}
+		value := strings.TrimSpace(content[i : i+next])
+		tokens = append(tokens, token{name: tag, value: unescape(value)})
+	}
+	return tokens
+}
+
+// parseDate lê datas no formato AAAAMMDD[HHMMSS[.XXX]][[-3:BRT]]
+func parseDate(value string) (time.Time, error) {
+	value = strings.TrimSpace(value)
+	if idx := strings.IndexByte(value, '['); idx >= 0 {
+		value = value[:idx]
+	}
+	if idx := strings.IndexByte(value, '.'); idx >= 0 {
+		value = value[:idx]
+	}
+	if len(value) < 8 {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, value)
+	}
+
+	layout := "20060102"
+	if len(value) >= 14 {
+		value = value[:14]
+		layout = "20060102150405"
+	} else {
+		value = value[:8]
+	}
+
+	// A data do lançamento é a data local do banco, independentemente do fuso informado
+	t, err := time.ParseInLocation(layout, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%w: %s", ErrInvalidDate, value)
+	}
+	return t, nil
+}
+
+// parseAmount aceita ponto ou vírgula como separador decimal
+func parseAmount(value string) (float64, error) {
+	value = strings.TrimSpace(strings.ReplaceAll(value, " ", ""))
+	if strings.Contains(value, ",") {
+		if strings.Contains(value, ".") {
+			value = strings.ReplaceAll(value, ".", "")
+		}
+		value = strings.ReplaceAll(value, ",", ".")
+	}
+	amount, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAmount, value)
+	}
+	return amount, nil
+}
+
+// syntheticFITID gera um identificador estável para bancos que não informam o FITID
+func syntheticFITID(key string, occurrence int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("%s|%d", key, occurrence)))
+	return "SYN" + hex.EncodeToString(sum[:])[:29]
+}
+
+var entities = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&quot;", "\"", "&apos;", "'", "&amp;", "&")
+
+// unescape converte as entidades XML usadas nos valores
+func unescape(value string) string {
+	return entities.Replace(value)
+}
+
+// cp1252 mapeia os caracteres de 0x80 a 0x9F do Windows-1252 que diferem do ISO-8859-1
+var cp1252 = map[byte]rune{
+	0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†', 0x87: '‡', 0x88: 'ˆ', 0x89: '‰',
+	0x8A: 'Š', 0x8B: '‹', 0x8C: 'Œ', 0x8E: 'Ž', 0x91: '\'', 0x92: '\'', 0x93: '"', 0x94: '"', 0x95: '•',
+	0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9A: 'š', 0x9B: '›', 0x9C: 'œ', 0x9E: 'ž', 0x9F: 'Ÿ',
+}
+
+// toUTF8 converte arquivos Windows-1252/ISO-8859-1 para UTF-8, mantendo os que já estão em UTF-8
+func toUTF8(data []byte) []byte {
+	if utf8.Valid(data) {
+		return data
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(data) + len(data)/10)
+	for _, b := range data {
+		switch {
+		case b < 0x80:
+			buf.WriteByte(b)
+		case b < 0xA0:
+			if r, ok := cp1252[b]; ok {
+				buf.WriteRune(r)
+			} else {
+				buf.WriteByte(' ')
+			}
+		default:
+			buf.WriteRune(rune(b))
+		}
+	}
+	return buf.Bytes()
+}
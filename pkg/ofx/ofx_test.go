@@ -0,0 +1,212 @@
+package ofx
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sgmlStatement = `OFXHEADER:100
+DATA:OFXSGML
+VERSION:102
+CHARSET:1252
+
+<OFX>
+<SIGNONMSGSRSV1><SONRS><STATUS><CODE>0<SEVERITY>INFO</STATUS><DTSERVER>20250310120000[-3:BRT]<LANGUAGE>POR</SONRS></SIGNONMSGSRSV1>
+<BANKMSGSRSV1><STMTTRNRS><TRNUID>1<STMTRS>
+<CURDEF>BRL
+<BANKACCTFROM><BANKID>0341<BRANCHID>1234<ACCTID>12345-6<ACCTTYPE>CHECKING</BANKACCTFROM>
+<BANKTRANLIST>
+<DTSTART>20250301
+<DTEND>20250310235959[-3:BRT]
+<STMTTRN>
+<TRNTYPE>credit
+<DTPOSTED>20250303120000.000[-3:BRT]
+<TRNAMT>1.250,75
+<FITID>202503030001
+<MEMO>PIX RECEBIDO JOSE
+</STMTTRN>
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250305
+<TRNAMT>-89.90
+<FITID>202503050001
+<CHECKNUM>000123
+<NAME>FORNECEDOR &amp; CIA
+<MEMO>PAGTO BOLETO
+<STMTTRN>
+<TRNTYPE>DEBIT
+<DTPOSTED>20250310
+<TRNAMT>-10,00
+<FITID>202503100001
+<MEMO>TARIFA
+</BANKTRANLIST>
+<LEDGERBAL><BALAMT>3.400,85<DTASOF>20250310</LEDGERBAL>
+</STMTRS></STMTTRNRS></BANKMSGSRSV1>
+</OFX>`
+
+const xmlStatement = `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="211"?>
+<OFX>
+  <CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+    <CURDEF>BRL</CURDEF>
+    <CCACCTFROM><ACCTID>4111********1111</ACCTID></CCACCTFROM>
+    <BANKTRANLIST>
+      <DTSTART>20250201</DTSTART><DTEND>20250228</DTEND>
+      <STMTTRN>
+        <TRNTYPE>PAYMENT</TRNTYPE>
+        <DTPOSTED>20250215</DTPOSTED>
+        <TRNAMT>-45.30</TRNAMT>
+        <MEMO>Padaria São João</MEMO>
+      </STMTTRN>
+      <STMTTRN>
+        <TRNTYPE>PAYMENT</TRNTYPE>
+        <DTPOSTED>20250215</DTPOSTED>
+        <TRNAMT>-45.30</TRNAMT>
+        <MEMO>Padaria São João</MEMO>
+      </STMTTRN>
+    </BANKTRANLIST>
+    <LEDGERBAL><BALAMT>-90.60</BALAMT><DTASOF>20250228</DTASOF></LEDGERBAL>
+  </CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>
+</OFX>`
+
+func day(year int, month time.Month, d int) time.Time {
+	return time.Date(year, month, d, 0, 0, 0, 0, time.Local)
+}
+
+func TestParse(t *testing.T) {
+	// O mesmo extrato salvo em Windows-1252, como exportam os bancos brasileiros
+	cp1252 := strings.Replace(sgmlStatement, "PIX RECEBIDO JOSE", "PIX RECEBIDO JOS\xc9 \x96 LOJA", 1)
+
+	tests := []struct {
+		name         string
+		data         string
+		account      string
+		balance      float64
+		start        time.Time
+		end          time.Time
+		wantTx       []Transaction
+		syntheticIDs bool
+	}{
+		{
+			name:    "OFX 1.x em SGML sem fechamento das marcações",
+			data:    sgmlStatement,
+			account: "12345-6",
+			balance: 3400.85,
+			start:   day(2025, 3, 1),
+			end:     time.Date(2025, 3, 10, 23, 59, 59, 0, time.Local),
+			wantTx: []Transaction{
+				{FITID: "202503030001", Type: "CREDIT", PostedAt: time.Date(2025, 3, 3, 12, 0, 0, 0, time.Local), Amount: 1250.75, Memo: "PIX RECEBIDO JOSE"},
+				{FITID: "202503050001", Type: "DEBIT", PostedAt: day(2025, 3, 5), Amount: -89.90, CheckNumber: "000123", Name: "FORNECEDOR & CIA", Memo: "PAGTO BOLETO"},
+				{FITID: "202503100001", Type: "DEBIT", PostedAt: day(2025, 3, 10), Amount: -10, Memo: "TARIFA"},
+			},
+		},
+		{
+			name:    "OFX 1.x em Windows-1252",
+			data:    cp1252,
+			account: "12345-6",
+			balance: 3400.85,
+			start:   day(2025, 3, 1),
+			end:     time.Date(2025, 3, 10, 23, 59, 59, 0, time.Local),
+			wantTx: []Transaction{
+				{FITID: "202503030001", Type: "CREDIT", PostedAt: time.Date(2025, 3, 3, 12, 0, 0, 0, time.Local), Amount: 1250.75, Memo: "PIX RECEBIDO JOSÉ – LOJA"},
+				{FITID: "202503050001", Type: "DEBIT", PostedAt: day(2025, 3, 5), Amount: -89.90, CheckNumber: "000123", Name: "FORNECEDOR & CIA", Memo: "PAGTO BOLETO"},
+				{FITID: "202503100001", Type: "DEBIT", PostedAt: day(2025, 3, 10), Amount: -10, Memo: "TARIFA"},
+			},
+		},
+		{
+			name:    "OFX 2.x em XML de cartão de crédito sem FITID",
+			data:    xmlStatement,
+			account: "4111********1111",
+			balance: -90.60,
+			start:   day(2025, 2, 1),
+			end:     day(2025, 2, 28),
+			wantTx: []Transaction{
+				{Type: "PAYMENT", PostedAt: day(2025, 2, 15), Amount: -45.30, Memo: "Padaria São João"},
+				{Type: "PAYMENT", PostedAt: day(2025, 2, 15), Amount: -45.30, Memo: "Padaria São João"},
+			},
+			syntheticIDs: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stmt, err := Parse([]byte(tt.data))
+			if err != nil {
+				t.Fatalf("Parse() erro inesperado: %v", err)
+			}
+			if stmt.AccountID != tt.account || stmt.Currency != "BRL" || stmt.Balance != tt.balance {
+				t.Errorf("Parse() = conta %s, moeda %s, saldo %.2f", stmt.AccountID, stmt.Currency, stmt.Balance)
+			}
+			if !stmt.StartDate.Equal(tt.start) || !stmt.EndDate.Equal(tt.end) {
+				t.Errorf("Parse() período = %s a %s, esperado %s a %s", stmt.StartDate, stmt.EndDate, tt.start, tt.end)
+			}
+			if len(stmt.Transactions) != len(tt.wantTx) {
+				t.Fatalf("Parse() = %d lançamentos, esperado %d", len(stmt.Transactions), len(tt.wantTx))
+			}
+
+			for i, want := range tt.wantTx {
+				got := stmt.Transactions[i]
+				if tt.syntheticIDs {
+					if !strings.HasPrefix(got.FITID, "SYN") || len(got.FITID) != 32 {
+						t.Errorf("lançamento %d: FITID sintético inválido %q", i, got.FITID)
+					}
+					want.FITID = got.FITID
+				}
+				if !got.PostedAt.Equal(want.PostedAt) {
+					t.Errorf("lançamento %d: data %s, esperado %s", i, got.PostedAt, want.PostedAt)
+				}
+				got.PostedAt, want.PostedAt = time.Time{}, time.Time{}
+				if got != want {
+					t.Errorf("lançamento %d =\n%+v\nesperado\n%+v", i, got, want)
+				}
+			}
+
+			if tt.syntheticIDs && stmt.Transactions[0].FITID == stmt.Transactions[1].FITID {
+				t.Errorf("lançamentos idênticos receberam o mesmo FITID sintético")
+			}
+		})
+	}
+}
+
+func TestParseSyntheticFITIDIsStable(t *testing.T) {
+	first, err := Parse([]byte(xmlStatement))
+	if err != nil {
+		t.Fatalf("Parse() erro inesperado: %v", err)
+	}
+	again, err := Parse([]byte(xmlStatement))
+	if err != nil {
+		t.Fatalf("Parse() erro inesperado: %v", err)
+	}
+
+	// Reimportar o mesmo extrato deve gerar os mesmos identificadores, evitando lançamentos duplicados
+	for i := range first.Transactions {
+		if first.Transactions[i].FITID != again.Transactions[i].FITID {
+			t.Errorf("lançamento %d: FITID %s diferente de %s", i, first.Transactions[i].FITID, again.Transactions[i].FITID)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		wantErr error
+	}{
+		{"sem marcação OFX", "OFXHEADER:100\nDATA:OFXSGML", ErrInvalidFile},
+		{"sem extrato bancário", "<OFX><SIGNONMSGSRSV1><SONRS><STATUS><CODE>0</STATUS></SONRS></SIGNONMSGSRSV1></OFX>", ErrNoStatement},
+		{"valor inválido", "<OFX><STMTRS><STMTTRN><TRNAMT>dez reais</STMTTRN></STMTRS></OFX>", ErrInvalidAmount},
+		{"data inválida", "<OFX><STMTRS><STMTTRN><DTPOSTED>2025</STMTTRN></STMTRS></OFX>", ErrInvalidDate},
+		{"mês inexistente", "<OFX><STMTRS><DTSTART>20251301</STMTRS></OFX>", ErrInvalidDate},
+		{"saldo inválido", "<OFX><STMTRS><LEDGERBAL><BALAMT>abc</LEDGERBAL></STMTRS></OFX>", ErrInvalidAmount},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse([]byte(tt.data)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() erro = %v, esperado %v", err, tt.wantErr)
+			}
+		})
+	}
+}
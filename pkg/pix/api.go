@@ -0,0 +1,241 @@
+package pix
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebhookSecretHeader é o cabeçalho com o segredo compartilhado configurado na URL de notificação do PSP
+const WebhookSecretHeader = "X-Webhook-Secret"
+
+// APIConfig reúne as credenciais de acesso à API Pix de um PSP
+type APIConfig struct {
+	BaseURL       string // Ex.: https://pix.example.com/api
+	ClientID      string
+	ClientSecret  string
+	WebhookSecret string       // Segredo exigido nas notificações; vazio rejeita todas
+	HTTPClient    *http.Client // Cliente com o certificado mTLS exigido pelo PSP
+}
+
+// APIProvider integra com PSPs que seguem a especificação da API Pix do Banco Central
+type APIProvider struct {
+	config APIConfig
+	client *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+// NewAPIProvider cria um provedor para a API Pix padronizada
+func NewAPIProvider(config APIConfig) *APIProvider {
+	client := config.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+	config.BaseURL = strings.TrimRight(config.BaseURL, "/")
+	return &APIProvider{config: config, client: client}
+}
+
+// Name implementa Provider.Name
+func (p *APIProvider) Name() string {
+	return "api"
+}
+
+// cobPayload é o corpo da cobrança imediata na API Pix
+type cobPayload struct {
+	Calendario struct {
+		Criacao   string `json:"criacao,omitempty"`
+		Expiracao int    `json:"expiracao"`
+	} `json:"calendario"`
+	Devedor *cobDebtor `json:"devedor,omitempty"`
+	Valor   struct {
+		Original string `json:"original"`
+	} `json:"valor"`
+	Chave              string `json:"chave,omitempty"`
+	SolicitacaoPagador string `json:"solicitacaoPagador,omitempty"`
+	TxID               string `json:"txid,omitempty"`
+	Location           string `json:"location,omitempty"`
+	Status             string `json:"status,omitempty"`
+	PixCopiaECola      string `json:"pixCopiaECola,omitempty"`
+}
+
+// cobDebtor identifica o devedor da cobrança
+type cobDebtor struct {
+	CPF  string `json:"cpf,omitempty"`
+	CNPJ string `json:"cnpj,omitempty"`
+	Nome string `json:"nome"`
+}
+
+// CreateCharge implementa Provider.CreateCharge
+func (p *APIProvider) CreateCharge(ctx context.Context, req *ChargeRequest) (*ChargeResponse, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+
+	var body cobPayload
+	body.Calendario.Expiracao = req.ExpiresIn
+	if body.Calendario.Expiracao <= 0 {
+		body.Calendario.Expiracao = defaultChargeExpiresIn
+	}
+	body.Valor.Original = strconv.FormatFloat(req.Amount, 'f', 2, 64)
+	body.Chave = req.Key
+	body.SolicitacaoPagador = truncate(req.Description, 140)
+
+	// O devedor só é aceito com nome e documento
+	if document := onlyDigits(req.PayerDocument); document != "" && req.PayerName != "" {
+		body.Devedor = &cobDebtor{Nome: truncate(req.PayerName, 200)}
+		if len(document) == 11 {
+			body.Devedor.CPF = document
+		} else {
+			body.Devedor.CNPJ = document
+		}
+	}
+
+	var result cobPayload
+	if err := p.do(ctx, http.MethodPut, "/v2/cob/"+url.PathEscape(req.TxID), body, &result); err != nil {
+		return nil, err
+	}
+
+	return result.response(), nil
+}
+
+// GetCharge implementa Provider.GetCharge
+func (p *APIProvider) GetCharge(ctx context.Context, txID string) (*ChargeResponse, error) {
+	var result cobPayload
+	if err := p.do(ctx, http.MethodGet, "/v2/cob/"+url.PathEscape(txID), nil, &result); err != nil {
+		return nil, err
+	}
+	return result.response(), nil
+}
+
+// CancelCharge implementa Provider.CancelCharge
+func (p *APIProvider) CancelCharge(ctx context.Context, txID string) error {
+	body := map[string]string{"status": ChargeRemovedByUser}
+	return p.do(ctx, http.MethodPatch, "/v2/cob/"+url.PathEscape(txID), body, nil)
+}
+
+// ParseWebhook implementa Provider.ParseWebhook. Sem segredo configurado, nenhuma notificação é
+// aceita
+func (p *APIProvider) ParseWebhook(header http.Header, body []byte) ([]Notification, error) {
+	if p.config.WebhookSecret == "" {
+		return nil, ErrWebhookNoSecret
+	}
+	secret := header.Get(WebhookSecretHeader)
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(p.config.WebhookSecret)) != 1 {
+		return nil, ErrInvalidSignature
+	}
+	return DecodeWebhook(body)
+}
+
+// response converte o retorno da API para o formato do provedor
+func (c *cobPayload) response() *ChargeResponse {
+	createdAt, _ := time.Parse(time.RFC3339, c.Calendario.Criacao)
+	return &ChargeResponse{
+		TxID:      c.TxID,
+		Location:  strings.TrimPrefix(strings.TrimPrefix(c.Location, "https://"), "http://"),
+		Status:    c.Status,
+		BRCode:    c.PixCopiaECola,
+		CreatedAt: createdAt,
+		ExpiresIn: c.Calendario.Expiracao,
+	}
+}
+
+// do executa uma chamada autenticada à API do PSP
+func (p *APIProvider) do(ctx context.Context, method, path string, in, out interface{}) error {
+	token, err := p.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	var reader io.Reader
+	if in != nil {
+		payload, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, p.config.BaseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderRequest, err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrProviderRequest, err)
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrChargeNotFound
+	}
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: status %d: %s", ErrProviderRequest, resp.StatusCode, truncate(string(data), 500))
+	}
+
+	if out != nil && len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil {
+			return fmt.Errorf("%w: resposta inválida: %v", ErrProviderRequest, err)
+		}
+	}
+	return nil
+}
+
+// accessToken obtém um token OAuth2 (client credentials), reaproveitando-o até perto da expiração
+func (p *APIProvider) accessToken(ctx context.Context) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Now().Before(p.expiresAt) {
+		return p.token, nil
+	}
+
+	form := url.Values{"grant_type": {"client_credentials"}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.config.BaseURL+"/oauth/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.SetBasicAuth(p.config.ClientID, p.config.ClientSecret)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("%w: %v", ErrProviderRequest, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("%w: autenticação recusada (status %d)", ErrProviderRequest, resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("%w: token inválido: %v", ErrProviderRequest, err)
+	}
+
+	p.token = token.AccessToken
+	p.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return p.token, nil
+}
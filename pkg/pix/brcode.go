@@ -0,0 +1,284 @@
+package pix
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrEmptyKey          = errors.New("chave Pix é obrigatória")
+	ErrEmptyMerchant     = errors.New("nome e cidade do recebedor são obrigatórios")
+	ErrInvalidAmount     = errors.New("valor da cobrança Pix inválido")
+	ErrInvalidTxID       = errors.New("identificador da transação (txid) inválido")
+	ErrInvalidBRCode     = errors.New("BR Code inválido")
+	ErrInvalidCRC        = errors.New("CRC16 do BR Code não confere")
+	ErrFieldTooLong      = errors.New("campo excede o tamanho permitido no BR Code")
+	ErrProviderRequest   = errors.New("falha na comunicação com o PSP")
+	ErrChargeNotFound    = errors.New("cobrança não encontrada no PSP")
+	ErrInvalidSignature  = errors.New("assinatura da notificação Pix inválida")
+	ErrInvalidWebhookMsg = errors.New("notificação Pix em formato inválido")
+	ErrWebhookNoSecret   = errors.New("segredo do webhook Pix não configurado")
+)
+
+// Identificadores dos campos EMV usados no BR Code (Manual do BR Code, Banco Central)
+const (
+	idPayloadFormat       = "00"
+	idPointOfInitiation   = "01"
+	idMerchantAccount     = "26"
+	idMerchantCategory    = "52"
+	idTransactionCurrency = "53"
+	idTransactionAmount   = "54"
+	idCountryCode         = "58"
+	idMerchantName        = "59"
+	idMerchantCity        = "60"
+	idPostalCode          = "61"
+	idAdditionalData      = "62"
+	idCRC16               = "63"
+
+	idGUI         = "00"
+	idKey         = "01"
+	idDescription = "02"
+	idURL         = "25"
+	idTxID        = "05"
+
+	gui = "br.gov.bcb.pix"
+)
+
+// Payload representa os dados de um BR Code Pix estático ou dinâmico
+type Payload struct {
+	Key          string  // Chave Pix do recebedor (somente no estático)
+	URL          string  // Location da cobrança no PSP, sem https:// (somente no dinâmico)
+	MerchantName string  // Nome do recebedor, até 25 caracteres
+	MerchantCity string  // Cidade do recebedor, até 15 caracteres
+	PostalCode   string  // CEP do recebedor (opcional)
+	Amount       float64 // Valor; zero permite que o pagador informe o valor
+	TxID         string  // Identificador da transação, até 25 caracteres no estático
+	Description  string  // Informação adicional exibida ao pagador (somente no estático)
+	SingleUse    bool    // Indica que o QR Code não pode ser pago mais de uma vez
+}
+
+// IsDynamic indica se o payload aponta para uma cobrança hospedada no PSP
+func (p *Payload) IsDynamic() bool {
+	return p.URL != ""
+}
+
+// Encode monta o BR Code (Pix Copia e Cola) com o CRC16 ao final
+func (p *Payload) Encode() (string, error) {
+	if p.Key == "" && p.URL == "" {
+		return "", ErrEmptyKey
+	}
+	if p.Amount < 0 {
+		return "", ErrInvalidAmount
+	}
+
+	name := normalize(p.MerchantName)
+	city := normalize(p.MerchantCity)
+	if name == "" || city == "" {
+		return "", ErrEmptyMerchant
+	}
+
+	txID := p.TxID
+	if txID == "" || p.IsDynamic() {
+		txID = "***"
+	}
+	if len(txID) > 25 || (txID != "***" && !isAlphanumeric(txID)) {
+		return "", ErrInvalidTxID
+	}
+
+	var account strings.Builder
+	account.WriteString(tlv(idGUI, gui))
+	if p.IsDynamic() {
+		account.WriteString(tlv(idURL, p.URL))
+	} else {
+		account.WriteString(tlv(idKey, p.Key))
+		if description := normalize(p.Description); description != "" {
+			account.WriteString(tlv(idDescription, description))
+		}
+	}
+	if account.Len() > 99 {
+		return "", fmt.Errorf("%w: informações da conta", ErrFieldTooLong)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(tlv(idPayloadFormat, "01"))
+	if p.SingleUse || p.IsDynamic() {
+		sb.WriteString(tlv(idPointOfInitiation, "12"))
+	}
+	sb.WriteString(tlv(idMerchantAccount, account.String()))
+	sb.WriteString(tlv(idMerchantCategory, "0000"))
+	sb.WriteString(tlv(idTransactionCurrency, "986"))
+	if p.Amount > 0 {
+		sb.WriteString(tlv(idTransactionAmount, strconv.FormatFloat(p.Amount, 'f', 2, 64)))
+	}
+	sb.WriteString(tlv(idCountryCode, "BR"))
+	sb.WriteString(tlv(idMerchantName, truncate(name, 25)))
+	sb.WriteString(tlv(idMerchantCity, truncate(city, 15)))
+	if zip := onlyDigits(p.PostalCode); zip != "" {
+		sb.WriteString(tlv(idPostalCode, zip))
+	}
+	sb.WriteString(tlv(idAdditionalData, tlv(idTxID, txID)))
+
+	// O CRC é calculado sobre todo o conteúdo, incluindo o identificador e o tamanho do próprio campo
+	sb.WriteString(idCRC16 + "04")
+	code := sb.String()
+	return code + CRC16(code), nil
+}
+
+// Parse interpreta um BR Code, validando a estrutura e o CRC16
+func Parse(code string) (*Payload, error) {
+	if err := Validate(code); err != nil {
+		return nil, err
+	}
+
+	fields, err := decodeTLV(code[:len(code)-8])
+	if err != nil {
+		return nil, err
+	}
+	if fields[idPayloadFormat] != "01" {
+		return nil, ErrInvalidBRCode
+	}
+
+	account, err := decodeTLV(fields[idMerchantAccount])
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(account[idGUI], gui) {
+		return nil, ErrInvalidBRCode
+	}
+
+	p := &Payload{
+		Key:          account[idKey],
+		URL:          account[idURL],
+		Description:  account[idDescription],
+		MerchantName: fields[idMerchantName],
+		MerchantCity: fields[idMerchantCity],
+		PostalCode:   fields[idPostalCode],
+		SingleUse:    fields[idPointOfInitiation] == "12",
+	}
+
+	if value := fields[idTransactionAmount]; value != "" {
+		p.Amount, err = strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, ErrInvalidAmount
+		}
+	}
+
+	if additional := fields[idAdditionalData]; additional != "" {
+		data, err := decodeTLV(additional)
+		if err != nil {
+			return nil, err
+		}
+		if txID := data[idTxID]; txID != "***" {
+			p.TxID = txID
+		}
+	}
+
+	return p, nil
+}
+
+// Validate confere o CRC16 de um BR Code
+func Validate(code string) error {
+	if len(code) < 8 || code[len(code)-8:len(code)-4] != idCRC16+"04" {
+		return ErrInvalidBRCode
+	}
+	if !strings.EqualFold(CRC16(code[:len(code)-4]), code[len(code)-4:]) {
+		return ErrInvalidCRC
+	}
+	return nil
+}
+
+// CRC16 calcula o CRC16-CCITT (polinômio 0x1021, valor inicial 0xFFFF) em hexadecimal maiúsculo
+func CRC16(data string) string {
+	crc := uint16(0xFFFF)
+	for i := 0; i < len(data); i++ {
+		crc ^= uint16(data[i]) << 8
+		for bit := 0; bit < 8; bit++ {
+			if crc&0x8000 != 0 {
+				crc = crc<<1 ^ 0x1021
+			} else {
+				crc <<= 1
+			}
+		}
+	}
+	return fmt.Sprintf("%04X", crc)
+}
+
+// tlv formata um campo EMV no padrão identificador + tamanho + valor
+func tlv(id, value string) string {
+	return fmt.Sprintf("%s%02d%s", id, len(value), value)
+}
+
+// decodeTLV separa os campos EMV de um trecho do BR Code
+func decodeTLV(data string) (map[string]string, error) {
+	fields := make(map[string]string)
+	for i := 0; i < len(data); {
+		if i+4 > len(data) {
+			return nil, ErrInvalidBRCode
+		}
+		size, err := strconv.Atoi(data[i+2 : i+4])
+		if err != nil || i+4+size > len(data) {
+			return nil, ErrInvalidBRCode
+		}
+		fields[data[i:i+2]] = data[i+4 : i+4+size]
+		i += 4 + size
+	}
+	return fields, nil
+}
+
+var accents = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	"Á", "A", "À", "A", "Â", "A", "Ã", "A", "Ä", "A",
+	"É", "E", "È", "E", "Ê", "E", "Ë", "E",
+	"Í", "I", "Ì", "I", "Î", "I", "Ï", "I",
+	"Ó", "O", "Ò", "O", "Ô", "O", "Õ", "O", "Ö", "O",
+	"Ú", "U", "Ù", "U", "Û", "U", "Ü", "U",
+	"Ç", "C", "Ñ", "N",
+)
+
+// normalize remove acentos e caracteres fora do ASCII, que alguns aplicativos não leem corretamente
+func normalize(value string) string {
+	value = accents.Replace(strings.TrimSpace(value))
+	var sb strings.Builder
+	for _, r := range value {
+		if r >= 32 && r < 127 {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// truncate limita o texto ao tamanho máximo do campo
+func truncate(value string, size int) string {
+	if len(value) > size {
+		return strings.TrimSpace(value[:size])
+	}
+	return value
+}
+
+// onlyDigits remove todos os caracteres não numéricos
+func onlyDigits(value string) string {
+	var sb strings.Builder
+	for _, r := range value {
+		if r >= '0' && r <= '9' {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// isAlphanumeric verifica se o texto contém apenas letras e números sem acento
+func isAlphanumeric(value string) bool {
+	for _, r := range value {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z') {
+			return false
+		}
+	}
+	return value != ""
+}
@@ -0,0 +1,143 @@
+package pix
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCRC16(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want string
+	}{
+		{"vazio", "", "FFFF"},
+		{"vetor de verificação CCITT", "123456789", "29B1"},
+		{"exemplo do manual do BR Code", "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***6304", "1D3D"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := CRC16(tt.data); got != tt.want {
+				t.Errorf("CRC16() = %s, esperado %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadEncode(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload Payload
+		want    string
+		wantErr error
+	}{
+		{
+			name: "estático sem valor (exemplo do manual do BR Code)",
+			payload: Payload{
+				Key:          "123e4567-e12b-12d1-a456-426655440000",
+				MerchantName: "Fulano de Tal",
+				MerchantCity: "BRASILIA",
+			},
+			want: "00020126580014br.gov.bcb.pix0136123e4567-e12b-12d1-a456-4266554400005204000053039865802BR5913Fulano de Tal6008BRASILIA62070503***63041D3D",
+		},
+		{
+			name: "estático com valor, descrição e txid",
+			payload: Payload{
+				Key:          "12345678909",
+				MerchantName: "Supermercado São João",
+				MerchantCity: "Goiânia",
+				PostalCode:   "74000-000",
+				Amount:       10.5,
+				TxID:         "PDV01VENDA42",
+				Description:  "Venda 42",
+			},
+			want: "00020126450014br.gov.bcb.pix0111123456789090208Venda 42520400005303986540510.505802BR5921Supermercado Sao Joao6007Goiania61087400000062160512PDV01VENDA426304BC28",
+		},
+		{
+			name: "dinâmico usa a location e txid ***",
+			payload: Payload{
+				URL:          "pix.fake.local/qr/v2/abc",
+				MerchantName: "Loja",
+				MerchantCity: "Recife",
+				Amount:       1,
+				TxID:         "ignorado",
+			},
+			want: "00020101021226460014br.gov.bcb.pix2524pix.fake.local/qr/v2/abc52040000530398654041.005802BR5904Loja6006Recife62070503***6304E823",
+		},
+		{name: "sem chave nem location", payload: Payload{MerchantName: "Loja", MerchantCity: "Recife"}, wantErr: ErrEmptyKey},
+		{name: "sem recebedor", payload: Payload{Key: "chave", MerchantCity: "Recife"}, wantErr: ErrEmptyMerchant},
+		{name: "valor negativo", payload: Payload{Key: "chave", MerchantName: "Loja", MerchantCity: "Recife", Amount: -1}, wantErr: ErrInvalidAmount},
+		{name: "txid com caractere especial", payload: Payload{Key: "chave", MerchantName: "Loja", MerchantCity: "Recife", TxID: "venda-42"}, wantErr: ErrInvalidTxID},
+		{name: "txid acima de 25 caracteres", payload: Payload{Key: "chave", MerchantName: "Loja", MerchantCity: "Recife", TxID: "ABCDEFGHIJKLMNOPQRSTUVWXYZ"}, wantErr: ErrInvalidTxID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.payload.Encode()
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Encode() erro = %v, esperado %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Encode() erro inesperado: %v", err)
+			}
+
+			if got != tt.want {
+				t.Errorf("Encode() =\n%s\nesperado\n%s", got, tt.want)
+			}
+			if err := Validate(got); err != nil {
+				t.Errorf("Validate() do código gerado: %v", err)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	original := Payload{
+		Key:          "12345678909",
+		MerchantName: "Supermercado",
+		MerchantCity: "Goiania",
+		PostalCode:   "74000000",
+		Amount:       25.9,
+		TxID:         "PDV01VENDA42",
+		Description:  "Venda 42",
+		SingleUse:    true,
+	}
+	code, err := original.Encode()
+	if err != nil {
+		t.Fatalf("Encode() erro inesperado: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		code    string
+		wantErr error
+	}{
+		{"código válido", code, nil},
+		{"CRC alterado", code[:len(code)-4] + "0000", ErrInvalidCRC},
+		{"conteúdo alterado", code[:len(code)-20] + "X" + code[len(code)-19:], ErrInvalidCRC},
+		{"sem campo CRC", code[:len(code)-8], ErrInvalidBRCode},
+		{"curto demais", "6304", ErrInvalidBRCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.code)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Parse() erro = %v, esperado %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() erro inesperado: %v", err)
+			}
+			if *got != original {
+				t.Errorf("Parse() = %+v, esperado %+v", *got, original)
+			}
+		})
+	}
+}
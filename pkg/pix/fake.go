@@ -0,0 +1,158 @@
+package pix
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FakeSignatureHeader é o cabeçalho com a assinatura HMAC-SHA256 das notificações do PSP simulado
+const FakeSignatureHeader = "X-Fake-PSP-Signature"
+
+// FakeProvider simula um PSP em memória, para testes e ambientes de desenvolvimento
+type FakeProvider struct {
+	secret string
+
+	mu       sync.Mutex
+	charges  map[string]*ChargeResponse
+	amounts  map[string]float64
+	sequence int
+}
+
+// NewFakeProvider cria um PSP simulado; o segredo assina as notificações geradas por Pay
+func NewFakeProvider(secret string) *FakeProvider {
+	return &FakeProvider{
+		secret:  secret,
+		charges: make(map[string]*ChargeResponse),
+		amounts: make(map[string]float64),
+	}
+}
+
+// Name implementa Provider.Name
+func (f *FakeProvider) Name() string {
+	return "fake"
+}
+
+// CreateCharge implementa Provider.CreateCharge
+func (f *FakeProvider) CreateCharge(ctx context.Context, req *ChargeRequest) (*ChargeResponse, error) {
+	if req.Amount <= 0 {
+		return nil, ErrInvalidAmount
+	}
+	if len(req.TxID) < 26 || len(req.TxID) > 35 || !isAlphanumeric(req.TxID) {
+		return nil, ErrInvalidTxID
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	expiresIn := req.ExpiresIn
+	if expiresIn <= 0 {
+		expiresIn = defaultChargeExpiresIn
+	}
+
+	charge := &ChargeResponse{
+		TxID:      req.TxID,
+		Location:  "pix.fake.local/qr/v2/" + req.TxID,
+		Status:    ChargeActive,
+		CreatedAt: time.Now(),
+		ExpiresIn: expiresIn,
+	}
+	f.charges[req.TxID] = charge
+	f.amounts[req.TxID] = req.Amount
+
+	result := *charge
+	return &result, nil
+}
+
+// GetCharge implementa Provider.GetCharge
+func (f *FakeProvider) GetCharge(ctx context.Context, txID string) (*ChargeResponse, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[txID]
+	if !ok {
+		return nil, ErrChargeNotFound
+	}
+
+	result := *charge
+	return &result, nil
+}
+
+// CancelCharge implementa Provider.CancelCharge
+func (f *FakeProvider) CancelCharge(ctx context.Context, txID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	charge, ok := f.charges[txID]
+	if !ok {
+		return ErrChargeNotFound
+	}
+	if charge.Status != ChargeActive {
+		return fmt.Errorf("%w: cobrança %s", ErrProviderRequest, strings.ToLower(charge.Status))
+	}
+
+	charge.Status = ChargeRemovedByUser
+	return nil
+}
+
+// ParseWebhook implementa Provider.ParseWebhook. Sem segredo configurado, nenhuma notificação é
+// aceita
+func (f *FakeProvider) ParseWebhook(header http.Header, body []byte) ([]Notification, error) {
+	if f.secret == "" {
+		return nil, ErrWebhookNoSecret
+	}
+	if !hmac.Equal([]byte(header.Get(FakeSignatureHeader)), []byte(f.sign(body))) {
+		return nil, ErrInvalidSignature
+	}
+	return DecodeWebhook(body)
+}
+
+// Pay simula o pagamento de uma cobrança e devolve a notificação que o PSP enviaria ao webhook.
+// Um valor zero paga o valor original da cobrança.
+func (f *FakeProvider) Pay(txID string, amount float64) (http.Header, []byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if charge, ok := f.charges[txID]; ok {
+		if amount == 0 {
+			amount = f.amounts[txID]
+		}
+		charge.Status = ChargeCompleted
+	}
+	if amount <= 0 {
+		return nil, nil, ErrInvalidAmount
+	}
+
+	f.sequence++
+	notification := Notification{
+		EndToEndID: fmt.Sprintf("E00000000%s%011d", time.Now().Format("200601021504"), f.sequence),
+		TxID:       txID,
+		Amount:     amount,
+		PaidAt:     time.Now(),
+	}
+
+	body, err := encodeWebhook([]Notification{notification})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	if f.secret != "" {
+		header.Set(FakeSignatureHeader, f.sign(body))
+	}
+	return header, body, nil
+}
+
+// sign calcula a assinatura HMAC-SHA256 do corpo da notificação
+func (f *FakeProvider) sign(body []byte) string {
+	mac := hmac.New(sha256.New, []byte(f.secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
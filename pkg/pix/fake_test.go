@@ -0,0 +1,111 @@
+package pix
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestFakeProviderParseWebhook(t *testing.T) {
+	provider := NewFakeProvider("segredo")
+	charge, err := provider.CreateCharge(context.Background(), &ChargeRequest{
+		TxID:   "a1b2c3d4e5f6a1b2c3d4e5f6a1b2c3d4",
+		Amount: 42.5,
+	})
+	if err != nil {
+		t.Fatalf("CreateCharge() erro inesperado: %v", err)
+	}
+
+	header, body, err := provider.Pay(charge.TxID, 0)
+	if err != nil {
+		t.Fatalf("Pay() erro inesperado: %v", err)
+	}
+
+	unsigned := header.Clone()
+	unsigned.Del(FakeSignatureHeader)
+
+	tampered := append([]byte(nil), body...)
+	tampered[len(tampered)-2] = ' '
+
+	tests := []struct {
+		name     string
+		provider *FakeProvider
+		header   http.Header
+		body     []byte
+		wantErr  error
+	}{
+		{"assinatura válida", provider, header, body, nil},
+		{"sem assinatura", provider, unsigned, body, ErrInvalidSignature},
+		{"corpo alterado", provider, header, tampered, ErrInvalidSignature},
+		{"segredo diferente", NewFakeProvider("outro"), header, body, ErrInvalidSignature},
+		{"sem segredo configurado", NewFakeProvider(""), header, body, ErrWebhookNoSecret},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			notifications, err := tt.provider.ParseWebhook(tt.header, tt.body)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("ParseWebhook() erro = %v, esperado %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseWebhook() erro inesperado: %v", err)
+			}
+			if len(notifications) != 1 {
+				t.Fatalf("ParseWebhook() = %d notificações, esperado 1", len(notifications))
+			}
+			n := notifications[0]
+			if n.TxID != charge.TxID || n.Amount != 42.5 || n.EndToEndID == "" {
+				t.Errorf("ParseWebhook() = %+v, esperado txid %s e valor 42.50", n, charge.TxID)
+			}
+		})
+	}
+
+	// O reenvio da mesma notificação mantém o endToEndId, que é a chave de idempotência da liquidação
+	first, _ := provider.ParseWebhook(header, body)
+	again, err := provider.ParseWebhook(header, body)
+	if err != nil || len(again) != 1 || again[0].EndToEndID != first[0].EndToEndID {
+		t.Errorf("reenvio gerou endToEndId diferente: %v %v", first, again)
+	}
+
+	// Um novo pagamento recebe um endToEndId próprio
+	_, other, err := provider.Pay(charge.TxID, 1)
+	if err != nil {
+		t.Fatalf("Pay() erro inesperado: %v", err)
+	}
+	decoded, err := DecodeWebhook(other)
+	if err != nil || decoded[0].EndToEndID == first[0].EndToEndID {
+		t.Errorf("pagamentos distintos com o mesmo endToEndId: %v", decoded)
+	}
+}
+
+func TestDecodeWebhook(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    int
+		wantErr error
+	}{
+		{"lista vazia", `{"pix":[]}`, 0, nil},
+		{"dois pagamentos", `{"pix":[{"endToEndId":"E1","txid":"T1","valor":"10.00","horario":"2025-01-02T10:00:00Z"},{"endToEndId":"E2","valor":"5.50","horario":"2025-01-02T10:01:00Z"}]}`, 2, nil},
+		{"JSON inválido", `{"pix":`, 0, ErrInvalidWebhookMsg},
+		{"sem endToEndId", `{"pix":[{"valor":"10.00"}]}`, 0, ErrInvalidWebhookMsg},
+		{"valor zero", `{"pix":[{"endToEndId":"E1","valor":"0.00"}]}`, 0, ErrInvalidWebhookMsg},
+		{"valor inválido", `{"pix":[{"endToEndId":"E1","valor":"dez"}]}`, 0, ErrInvalidWebhookMsg},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DecodeWebhook([]byte(tt.body))
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("DecodeWebhook() erro = %v, esperado %v", err, tt.wantErr)
+			}
+			if len(got) != tt.want {
+				t.Errorf("DecodeWebhook() = %d notificações, esperado %d", len(got), tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package pix
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Situações de uma cobrança no PSP, conforme a API Pix do Banco Central
+const (
+	ChargeActive           = "ATIVA"
+	ChargeCompleted        = "CONCLUIDA"
+	ChargeRemovedByUser    = "REMOVIDA_PELO_USUARIO_RECEBEDOR"
+	ChargeRemovedByPSP     = "REMOVIDA_PELO_PSP"
+	defaultChargeExpiresIn = 3600
+)
+
+// ChargeRequest representa a criação de uma cobrança imediata (cob) no PSP
+type ChargeRequest struct {
+	TxID          string
+	Key           string
+	Amount        float64
+	ExpiresIn     int // Segundos até a expiração da cobrança
+	PayerName     string
+	PayerDocument string // CPF ou CNPJ do devedor (opcional)
+	Description   string // Solicitação ao pagador
+}
+
+// ChargeResponse representa uma cobrança devolvida pelo PSP
+type ChargeResponse struct {
+	TxID      string
+	Location  string // URL do payload, sem o esquema, usada no BR Code dinâmico
+	Status    string
+	BRCode    string // Pix Copia e Cola, quando o PSP já o devolve pronto
+	CreatedAt time.Time
+	ExpiresIn int
+}
+
+// Notification representa um Pix recebido informado pelo PSP via webhook
+type Notification struct {
+	EndToEndID string    `json:"end_to_end_id"`
+	TxID       string    `json:"txid"`
+	Amount     float64   `json:"amount"`
+	PaidAt     time.Time `json:"paid_at"`
+	PayerInfo  string    `json:"payer_info"`
+}
+
+// Provider define a integração com a API de um PSP (Prestador de Serviços de Pagamento)
+type Provider interface {
+	// Name retorna o identificador do provedor
+	Name() string
+
+	// CreateCharge cria uma cobrança imediata com o txid informado
+	CreateCharge(ctx context.Context, req *ChargeRequest) (*ChargeResponse, error)
+
+	// GetCharge consulta uma cobrança pelo txid
+	GetCharge(ctx context.Context, txID string) (*ChargeResponse, error)
+
+	// CancelCharge remove uma cobrança ainda não paga
+	CancelCharge(ctx context.Context, txID string) error
+
+	// ParseWebhook autentica e interpreta uma notificação recebida do PSP
+	ParseWebhook(header http.Header, body []byte) ([]Notification, error)
+}
+
+// NewTxID gera um identificador de transação aceito pelo PSP; cobranças dinâmicas exigem de 26 a 35 caracteres
+func NewTxID(size int) string {
+	buf := make([]byte, (size+1)/2)
+	if _, err := rand.Read(buf); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(buf)[:size]
+}
+
+// webhookBody é o formato de notificação definido pela API Pix do Banco Central
+type webhookBody struct {
+	Pix []webhookItem `json:"pix"`
+}
+
+// webhookItem representa cada Pix recebido na notificação
+type webhookItem struct {
+	EndToEndID  string `json:"endToEndId"`
+	TxID        string `json:"txid,omitempty"`
+	Valor       string `json:"valor"`
+	Horario     string `json:"horario"`
+	InfoPagador string `json:"infoPagador,omitempty"`
+}
+
+// DecodeWebhook interpreta o corpo padrão de notificação {"pix": [...]} da API Pix
+func DecodeWebhook(body []byte) ([]Notification, error) {
+	var payload webhookBody
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, ErrInvalidWebhookMsg
+	}
+
+	notifications := make([]Notification, 0, len(payload.Pix))
+	for _, item := range payload.Pix {
+		if item.EndToEndID == "" {
+			return nil, ErrInvalidWebhookMsg
+		}
+
+		amount, err := strconv.ParseFloat(strings.TrimSpace(item.Valor), 64)
+		if err != nil || amount <= 0 {
+			return nil, ErrInvalidWebhookMsg
+		}
+
+		paidAt, err := time.Parse(time.RFC3339, item.Horario)
+		if err != nil {
+			paidAt = time.Now()
+		}
+
+		notifications = append(notifications, Notification{
+			EndToEndID: item.EndToEndID,
+			TxID:       item.TxID,
+			Amount:     amount,
+			PaidAt:     paidAt,
+			PayerInfo:  item.InfoPagador,
+		})
+	}
+
+	return notifications, nil
+}
+
+// encodeWebhook monta o corpo padrão de notificação a partir dos Pix recebidos
+func encodeWebhook(notifications []Notification) ([]byte, error) {
+	var payload webhookBody
+	for _, n := range notifications {
+		payload.Pix = append(payload.Pix, webhookItem{
+			EndToEndID:  n.EndToEndID,
+			TxID:        n.TxID,
+			Valor:       strconv.FormatFloat(n.Amount, 'f', 2, 64),
+			Horario:     n.PaidAt.Format(time.RFC3339),
+			InfoPagador: n.PayerInfo,
+		})
+	}
+	return json.Marshal(payload)
+}
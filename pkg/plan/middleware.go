@@ -0,0 +1,97 @@
+package plan
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
+	"github.com/hugohenrick/erp-supermercado/internal/domain/tenant"
+)
+
+// subscriptionKey é a chave da assinatura do tenant no contexto do Gin
+const subscriptionKey = "subscription"
+
+// SubscriptionProvider fornece o plano e a situação da assinatura de um tenant
+type SubscriptionProvider interface {
+	Subscription(ctx context.Context, tenantID string) (*tenant.Subscription, error)
+}
+
+// Middleware carrega a assinatura do tenant validado pelo TenantMiddleware e aplica o modo
+// somente leitura: com o plano expirado além da carência, apenas consultas são aceitas (402).
+// Durante a carência, as respostas levam o cabeçalho X-Plan-Expires-At como aviso
+func Middleware(provider SubscriptionProvider) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		tenantID := c.GetString("tenant_id")
+		if tenantID == "" || isExcludedPath(c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		subscription, err := provider.Subscription(c.Request.Context(), tenantID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, dto.NewErrorResponse(
+				http.StatusInternalServerError,
+				"Erro ao verificar plano do tenant",
+				err.Error(),
+			))
+			return
+		}
+		c.Set(subscriptionKey, subscription)
+
+		if subscription.State != tenant.PlanStateActive {
+			c.Header("X-Plan-Expires-At", subscription.ExpiresAt.Format(time.RFC3339))
+		}
+		if subscription.State == tenant.PlanStateReadOnly && !isReadOnlyMethod(c.Request.Method) {
+			c.AbortWithStatusJSON(http.StatusPaymentRequired, dto.NewErrorResponse(
+				http.StatusPaymentRequired,
+				"Plano expirado",
+				tenant.ErrPlanReadOnly.Error(),
+			))
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// RequireModule bloqueia as rotas de um módulo não incluído no plano do tenant (403). Rotas sem
+// tenant, como os webhooks, não são afetadas
+func RequireModule(module tenant.Module) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		subscription := GetSubscription(c)
+		if subscription == nil || subscription.Plan.HasModule(module) {
+			c.Next()
+			return
+		}
+
+		c.AbortWithStatusJSON(http.StatusForbidden, dto.NewErrorResponse(
+			http.StatusForbidden,
+			"Módulo não disponível no plano",
+			tenant.ErrModuleNotInPlan.Error()+": "+string(module),
+		))
+	}
+}
+
+// GetSubscription retorna a assinatura carregada pelo Middleware, se houver
+func GetSubscription(c *gin.Context) *tenant.Subscription {
+	if value, ok := c.Get(subscriptionKey); ok {
+		if subscription, ok := value.(*tenant.Subscription); ok {
+			return subscription
+		}
+	}
+	return nil
+}
+
+// isReadOnlyMethod verifica se o método HTTP apenas consulta dados
+func isReadOnlyMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// isExcludedPath verifica se a rota continua disponível com o plano expirado: a autenticação e a
+// gestão do tenant, por onde o plano é renovado
+func isExcludedPath(path string) bool {
+	return strings.HasPrefix(path, "/api/v1/auth/") || strings.HasPrefix(path, "/api/v1/tenants")
+}
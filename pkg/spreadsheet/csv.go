@@ -0,0 +1,65 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// utf8BOM permite que o Excel reconheça a codificação do CSV
+var utf8BOM = []byte("\xef\xbb\xbf")
+
+// readCSV lê planilhas separadas por ponto e vírgula ou vírgula, conforme o cabeçalho
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+	firstLine := data
+	if idx := bytes.IndexByte(data, '\n'); idx >= 0 {
+		firstLine = data[:idx]
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	if bytes.Count(firstLine, []byte(";")) > bytes.Count(firstLine, []byte(",")) {
+		reader.Comma = ';'
+	}
+
+	rows := make([][]string, 0)
+	for {
+		fields, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidFile, err)
+		}
+		rows = append(rows, fields)
+	}
+	return rows, nil
+}
+
+// csvWriter grava CSV separado por ponto e vírgula, padrão do Excel em português
+type csvWriter struct {
+	w *csv.Writer
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	if _, err := w.Write(utf8BOM); err != nil {
+		return nil, err
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = ';'
+	return &csvWriter{w: writer}, nil
+}
+
+// Write implementa Writer.Write
+func (c *csvWriter) Write(row []string) error {
+	return c.w.Write(row)
+}
+
+// Close implementa Writer.Close
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
@@ -0,0 +1,111 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrInvalidFile       = errors.New("planilha inválida")
+	ErrUnsupportedFormat = errors.New("formato de planilha não suportado, use csv ou xlsx")
+)
+
+// Format define o formato da planilha
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// zipSignature são os primeiros bytes de um arquivo ZIP, como o XLSX
+var zipSignature = []byte("PK\x03\x04")
+
+// ParseFormat valida o formato informado pelo usuário
+func ParseFormat(value string) (Format, error) {
+	switch Format(strings.ToLower(strings.TrimSpace(value))) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// DetectFormat identifica o formato pelo conteúdo do arquivo e, na dúvida, pela extensão
+func DetectFormat(filename string, data []byte) (Format, error) {
+	if bytes.HasPrefix(data, zipSignature) {
+		return FormatXLSX, nil
+	}
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".txt", "":
+		return FormatCSV, nil
+	case ".xlsx":
+		return "", ErrInvalidFile
+	}
+	return "", ErrUnsupportedFormat
+}
+
+// Read lê todas as linhas da primeira planilha do arquivo. A posição de cada linha no resultado
+// corresponde à linha da planilha, de forma que rows[i] é a linha i+1
+func Read(format Format, data []byte) ([][]string, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(data)
+	case FormatXLSX:
+		return readXLSX(data)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// Writer grava uma planilha linha a linha, sem manter o conteúdo em memória
+type Writer interface {
+	// Write grava uma linha
+	Write(row []string) error
+
+	// Close conclui o arquivo; nada é válido antes dele
+	Close() error
+}
+
+// NewWriter cria um gravador no formato informado
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, ErrUnsupportedFormat
+}
+
+// ContentType retorna o tipo MIME do formato
+func ContentType(format Format) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// headerReplacer remove acentos e troca separadores por "_" nos nomes de colunas
+var headerReplacer = strings.NewReplacer(
+	"á", "a", "à", "a", "â", "a", "ã", "a", "ä", "a",
+	"é", "e", "è", "e", "ê", "e", "ë", "e",
+	"í", "i", "ì", "i", "î", "i", "ï", "i",
+	"ó", "o", "ò", "o", "ô", "o", "õ", "o", "ö", "o",
+	"ú", "u", "ù", "u", "û", "u", "ü", "u",
+	"ç", "c", "ñ", "n",
+	" ", "_", "-", "_", "/", "_", ".", "", "\t", "_",
+)
+
+// HeaderKey normaliza um nome de coluna ou valor codificado para comparação: minúsculas, sem
+// acentos e com espaços, hífens e barras trocados por "_"
+func HeaderKey(value string) string {
+	key := headerReplacer.Replace(strings.ToLower(strings.TrimSpace(value)))
+	for strings.Contains(key, "__") {
+		key = strings.ReplaceAll(key, "__", "_")
+	}
+	return strings.Trim(key, "_")
+}
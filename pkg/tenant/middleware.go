@@ -2,6 +2,7 @@ package tenant
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hugohenrick/erp-supermercado/internal/adapter/api/dto"
@@ -68,7 +69,10 @@ func isExcludedPath(path string) bool {
 		"/api/v1/tenants",
 		"/api/v1/tenants/",
 		"/api/v1/health",
-		"/api/v1/setup/admin", // Rota para criar o primeiro usuário administrador
+		"/api/v1/setup/admin",                // Rota para criar o primeiro usuário administrador
+		"/api/v1/onboarding",                 // Cadastro inicial pelos operadores, que cria o próprio tenant
+		"/api/v1/pix/webhook/:tenant_id",     // Notificações do PSP, com o tenant na URL
+		"/api/v1/pix/webhook/:tenant_id/pix", // Variante com o sufixo /pix da API Pix
 	}
 
 	for _, excludedPath := range excludedPaths {
@@ -77,5 +81,13 @@ func isExcludedPath(path string) bool {
 		}
 	}
 
+	// Rotas dos operadores da plataforma, que não pertencem a um tenant e são protegidas pelo
+	// token de operador
+	for _, prefix := range []string{"/api/v1/tenants/", "/api/v1/platform/"} {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+
 	return false
 }